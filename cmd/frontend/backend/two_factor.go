@@ -0,0 +1,145 @@
+package backend
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/randstring"
+	"github.com/sourcegraph/sourcegraph/internal/totp"
+)
+
+// TwoFactor contains backend methods related to two-factor authentication (TOTP) of builtin
+// (username-password) accounts.
+var TwoFactor = &twoFactor{}
+
+type twoFactor struct{}
+
+// ErrInvalidTwoFactorCode occurs when a two-factor authentication code (or recovery code) is
+// incorrect or expired.
+var ErrInvalidTwoFactorCode = errors.New("invalid two-factor authentication code")
+
+const (
+	twoFactorIssuer         = "Sourcegraph"
+	twoFactorRecoveryCodes  = 10
+	recoveryCodeHalfLength  = 5
+	recoveryCodeAllowedChar = "abcdefghijklmnopqrstuvwxyz0123456789"
+)
+
+// BeginEnrollment generates a new secret for the user and stores it as a pending enrollment. It
+// returns the secret and the otpauth:// URI for authenticator apps. The second factor is only
+// required to sign in after the enrollment is confirmed with ConfirmEnrollment.
+//
+// 🚨 SECURITY: The caller must ensure that the actor is the user.
+func (*twoFactor) BeginEnrollment(ctx context.Context, user *types.User) (secret, keyURI string, err error) {
+	secret, err = totp.GenerateSecret()
+	if err != nil {
+		return "", "", err
+	}
+	if err := db.UserTOTP.CreatePending(ctx, user.ID, secret); err != nil {
+		return "", "", err
+	}
+	return secret, TwoFactor.KeyURI(user, secret), nil
+}
+
+// KeyURI returns the otpauth:// URI of the user's secret for authenticator apps.
+func (*twoFactor) KeyURI(user *types.User, secret string) string {
+	return totp.KeyURI(twoFactorIssuer, user.Username, secret)
+}
+
+// ConfirmEnrollment enables two-factor authentication for the user if code was generated from
+// the pending secret. It returns the user's new recovery codes, which must be shown to the user
+// exactly once.
+//
+// 🚨 SECURITY: The caller must ensure that the actor is the user.
+func (*twoFactor) ConfirmEnrollment(ctx context.Context, userID int32, code string) (recoveryCodes []string, err error) {
+	t, err := db.UserTOTP.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if t.EnabledAt != nil {
+		return nil, db.ErrUserTOTPAlreadyEnabled
+	}
+	if err := verify(ctx, t, code, false); err != nil {
+		return nil, err
+	}
+
+	recoveryCodes = makeRecoveryCodes()
+	if err := db.UserTOTP.Enable(ctx, userID, recoveryCodes); err != nil {
+		return nil, err
+	}
+	return recoveryCodes, nil
+}
+
+// Verify checks the second factor of a user who has two-factor authentication enabled. The code
+// may be a TOTP code or one of the user's unused recovery codes (which is then used up). It
+// returns ErrInvalidTwoFactorCode if the code is not valid and db.ErrTwoFactorRateLimit if the
+// user made too many failed attempts recently.
+func (*twoFactor) Verify(ctx context.Context, userID int32, code string) error {
+	t, err := db.UserTOTP.GetByUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if t.EnabledAt == nil {
+		return db.ErrUserTOTPNotFound
+	}
+	return verify(ctx, t, code, true)
+}
+
+// RegenerateRecoveryCodes invalidates the recovery codes of the user and returns new ones. It
+// requires a valid second factor code, so that a stolen session alone is not enough to obtain
+// recovery codes.
+//
+// 🚨 SECURITY: The caller must ensure that the actor is the user.
+func (s *twoFactor) RegenerateRecoveryCodes(ctx context.Context, userID int32, code string) ([]string, error) {
+	if err := s.Verify(ctx, userID, code); err != nil {
+		return nil, err
+	}
+	recoveryCodes := makeRecoveryCodes()
+	if err := db.UserTOTP.ReplaceRecoveryCodes(ctx, userID, recoveryCodes); err != nil {
+		return nil, err
+	}
+	return recoveryCodes, nil
+}
+
+func verify(ctx context.Context, t *db.UserTOTPFactor, code string, allowRecoveryCode bool) error {
+	// 🚨 SECURITY: Register the attempt before checking the code to rate limit guessing.
+	if err := db.UserTOTP.RegisterAttempt(ctx, t.UserID); err != nil {
+		return err
+	}
+
+	code = strings.TrimSpace(code)
+	step, ok := totp.ValidateStep(t.Secret, code, time.Now())
+	if ok {
+		// 🚨 SECURITY: Reject codes of time steps that were already used, so that an observed
+		// code can't be replayed.
+		var err error
+		ok, err = db.UserTOTP.UseStep(ctx, t.UserID, int64(step))
+		if err != nil {
+			return err
+		}
+	} else if allowRecoveryCode {
+		var err error
+		ok, err = db.UserTOTP.UseRecoveryCode(ctx, t.UserID, strings.ToLower(code))
+		if err != nil {
+			return err
+		}
+	}
+	if !ok {
+		return ErrInvalidTwoFactorCode
+	}
+	return db.UserTOTP.ClearFailedAttempts(ctx, t.UserID)
+}
+
+// makeRecoveryCodes returns new random recovery codes of the form "xxxxx-xxxxx".
+func makeRecoveryCodes() []string {
+	codes := make([]string, twoFactorRecoveryCodes)
+	for i := range codes {
+		codes[i] = randstring.NewLenChars(recoveryCodeHalfLength, []byte(recoveryCodeAllowedChar)) + "-" +
+			randstring.NewLenChars(recoveryCodeHalfLength, []byte(recoveryCodeAllowedChar))
+	}
+	return codes
+}
@@ -0,0 +1,98 @@
+package backend
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/internal/totp"
+)
+
+// 🚨 SECURITY: This tests that second factor codes are verified and rate limited.
+func TestTwoFactor_Verify(t *testing.T) {
+	ctx := testContext()
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	code, err := totp.Code(secret, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	enabledAt := time.Now()
+	mock := func(t *testing.T, rateLimited bool, lastUsedStep int64) (cleared *bool) {
+		cleared = new(bool)
+		db.Mocks.UserTOTP.GetByUserID = func(userID int32) (*db.UserTOTPFactor, error) {
+			return &db.UserTOTPFactor{UserID: userID, Secret: secret, EnabledAt: &enabledAt}, nil
+		}
+		db.Mocks.UserTOTP.RegisterAttempt = func(userID int32) error {
+			if rateLimited {
+				return db.ErrTwoFactorRateLimit
+			}
+			return nil
+		}
+		db.Mocks.UserTOTP.UseRecoveryCode = func(userID int32, code string) (bool, error) {
+			return code == "abcde-fghij", nil
+		}
+		db.Mocks.UserTOTP.UseStep = func(userID int32, step int64) (bool, error) {
+			if step <= lastUsedStep {
+				return false, nil
+			}
+			lastUsedStep = step
+			return true, nil
+		}
+		db.Mocks.UserTOTP.ClearFailedAttempts = func(userID int32) error {
+			*cleared = true
+			return nil
+		}
+		return cleared
+	}
+	defer func() { db.Mocks.UserTOTP = db.MockUserTOTP{} }()
+
+	step, _ := totp.ValidateStep(secret, code, time.Now())
+
+	tests := []struct {
+		name         string
+		code         string
+		rateLimited  bool
+		lastUsedStep int64
+		wantErr      error
+	}{
+		{name: "valid code", code: code},
+		{name: "valid recovery code", code: "ABCDE-FGHIJ"},
+		{name: "invalid code", code: "000000", wantErr: ErrInvalidTwoFactorCode},
+		{name: "rate limited", code: code, rateLimited: true, wantErr: db.ErrTwoFactorRateLimit},
+		{name: "reused code", code: code, lastUsedStep: int64(step), wantErr: ErrInvalidTwoFactorCode},
+		{name: "code older than last used", code: code, lastUsedStep: int64(step) + 1, wantErr: ErrInvalidTwoFactorCode},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cleared := mock(t, test.rateLimited, test.lastUsedStep)
+			if err := TwoFactor.Verify(ctx, 1, test.code); err != test.wantErr {
+				t.Fatalf("got err %v, want %v", err, test.wantErr)
+			}
+			if want := test.wantErr == nil; *cleared != want {
+				t.Errorf("got failed attempts cleared %v, want %v", *cleared, want)
+			}
+		})
+	}
+}
+
+func TestMakeRecoveryCodes(t *testing.T) {
+	codes := makeRecoveryCodes()
+	if len(codes) != twoFactorRecoveryCodes {
+		t.Fatalf("got %d codes, want %d", len(codes), twoFactorRecoveryCodes)
+	}
+	seen := map[string]bool{}
+	for _, code := range codes {
+		if len(code) != 2*recoveryCodeHalfLength+1 || code[recoveryCodeHalfLength] != '-' {
+			t.Errorf("malformed recovery code %q", code)
+		}
+		if seen[code] {
+			t.Errorf("duplicate recovery code %q", code)
+		}
+		seen[code] = true
+	}
+}
@@ -43,6 +43,7 @@ var ExternalServiceKinds = map[string]ExternalServiceKind{
 	"GITOLITE":        {CodeHost: true, JSONSchema: schema.GitoliteSchemaJSON},
 	"PHABRICATOR":     {CodeHost: true, JSONSchema: schema.PhabricatorSchemaJSON},
 	"OTHER":           {CodeHost: true, JSONSchema: schema.OtherExternalServiceSchemaJSON},
+	"PERFORCE":        {CodeHost: true, JSONSchema: schema.PerforceSchemaJSON},
 }
 
 // ExternalServiceKind describes a kind of external service.
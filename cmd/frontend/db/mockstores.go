@@ -17,9 +17,14 @@ type MockStores struct {
 	Settings      MockSettings
 	Users         MockUsers
 	UserEmails    MockUserEmails
+	UserSessions  MockUserSessions
+	UserTOTP      MockUserTOTP
 
 	Phabricator MockPhabricator
 
+	RepoAccessLogs MockRepoAccessLogs
+	RepoDiskUsage  MockRepoDiskUsage
+
 	ExternalAccounts MockExternalAccounts
 
 	OrgInvitations MockOrgInvitations
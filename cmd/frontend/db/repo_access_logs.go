@@ -0,0 +1,130 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/db/dbconn"
+)
+
+// RepoAccessLogEntry describes an access to the code of a repository whose accesses are logged
+// (per the repoAccessLog site configuration).
+type RepoAccessLogEntry struct {
+	ID        int64
+	RepoID    api.RepoID
+	RepoName  api.RepoName
+	UserID    int32  // 0 for anonymous users and internal services
+	Kind      string // see the repoaccesslog package for the kinds of accesses
+	Path      string // the file or directory path, if any
+	CommitID  api.CommitID
+	Query     string // the search query, for search result accesses
+	IP        string
+	CreatedAt time.Time
+}
+
+type repoAccessLogs struct{}
+
+// Insert adds an entry to the repository access log.
+func (*repoAccessLogs) Insert(ctx context.Context, e *RepoAccessLogEntry) error {
+	if Mocks.RepoAccessLogs.Insert != nil {
+		return Mocks.RepoAccessLogs.Insert(e)
+	}
+
+	_, err := dbconn.Global.ExecContext(ctx,
+		"INSERT INTO repo_access_logs(repo_id, repo_name, user_id, kind, path, commit_id, query, ip) VALUES($1, $2, $3, $4, $5, $6, $7, $8)",
+		e.RepoID, e.RepoName, e.UserID, e.Kind, e.Path, e.CommitID, e.Query, e.IP,
+	)
+	return err
+}
+
+// RepoAccessLogsListOptions contains options for listing repository access log entries.
+type RepoAccessLogsListOptions struct {
+	RepoID api.RepoID // only list entries for this repository
+	UserID int32      // only list entries of this user
+	Kind   string     // only list entries of this kind
+	Since  time.Time  // only list entries created at or after this time
+	*LimitOffset
+}
+
+func (o RepoAccessLogsListOptions) sqlConditions() []*sqlf.Query {
+	conds := []*sqlf.Query{sqlf.Sprintf("TRUE")}
+	if o.RepoID != 0 {
+		conds = append(conds, sqlf.Sprintf("repo_id=%d", o.RepoID))
+	}
+	if o.UserID != 0 {
+		conds = append(conds, sqlf.Sprintf("user_id=%d", o.UserID))
+	}
+	if o.Kind != "" {
+		conds = append(conds, sqlf.Sprintf("kind=%s", o.Kind))
+	}
+	if !o.Since.IsZero() {
+		conds = append(conds, sqlf.Sprintf("created_at >= %s", o.Since))
+	}
+	return conds
+}
+
+// List lists repository access log entries (most recent first) that satisfy the options.
+//
+// 🚨 SECURITY: The caller must ensure that the actor is a site admin.
+func (*repoAccessLogs) List(ctx context.Context, opt RepoAccessLogsListOptions) ([]*RepoAccessLogEntry, error) {
+	if Mocks.RepoAccessLogs.List != nil {
+		return Mocks.RepoAccessLogs.List(opt)
+	}
+
+	q := sqlf.Sprintf(`
+SELECT id, repo_id, repo_name, user_id, kind, path, commit_id, query, ip, created_at FROM repo_access_logs
+WHERE (%s)
+ORDER BY created_at DESC, id DESC
+%s`,
+		sqlf.Join(opt.sqlConditions(), ") AND ("),
+		opt.LimitOffset.SQL(),
+	)
+	rows, err := dbconn.Global.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []*RepoAccessLogEntry
+	for rows.Next() {
+		var e RepoAccessLogEntry
+		if err := rows.Scan(&e.ID, &e.RepoID, &e.RepoName, &e.UserID, &e.Kind, &e.Path, &e.CommitID, &e.Query, &e.IP, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		entries = append(entries, &e)
+	}
+	return entries, rows.Err()
+}
+
+// Count counts the repository access log entries that satisfy the options (ignoring limit and
+// offset).
+//
+// 🚨 SECURITY: The caller must ensure that the actor is a site admin.
+func (*repoAccessLogs) Count(ctx context.Context, opt RepoAccessLogsListOptions) (int, error) {
+	if Mocks.RepoAccessLogs.Count != nil {
+		return Mocks.RepoAccessLogs.Count(opt)
+	}
+
+	q := sqlf.Sprintf("SELECT COUNT(*) FROM repo_access_logs WHERE (%s)", sqlf.Join(opt.sqlConditions(), ") AND ("))
+	var count int
+	err := dbconn.Global.QueryRowContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...).Scan(&count)
+	return count, err
+}
+
+// DeleteOlderThan deletes the entries that were created before the given time and returns the
+// number of deleted entries.
+func (*repoAccessLogs) DeleteOlderThan(ctx context.Context, t time.Time) (int64, error) {
+	res, err := dbconn.Global.ExecContext(ctx, "DELETE FROM repo_access_logs WHERE created_at < $1", t)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+type MockRepoAccessLogs struct {
+	Insert func(e *RepoAccessLogEntry) error
+	List   func(opt RepoAccessLogsListOptions) ([]*RepoAccessLogEntry, error)
+	Count  func(opt RepoAccessLogsListOptions) (int, error)
+}
@@ -0,0 +1,98 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/db/dbconn"
+)
+
+// RepoDiskUsageRecord is the size of a repository on a gitserver shard, as last recorded by
+// repo-updater.
+type RepoDiskUsageRecord struct {
+	RepoID    api.RepoID
+	RepoName  api.RepoName
+	Shard     string // the address of the gitserver
+	SizeBytes int64
+	UpdatedAt time.Time
+}
+
+type repoDiskUsage struct{}
+
+// RepoDiskUsageListOptions contains options for listing the disk usage of repositories.
+type RepoDiskUsageListOptions struct {
+	RepoID api.RepoID // only list the disk usage of this repository
+	Shard  string     // only list the disk usage on this gitserver shard
+	*LimitOffset
+}
+
+func (o RepoDiskUsageListOptions) sqlConditions() []*sqlf.Query {
+	conds := []*sqlf.Query{sqlf.Sprintf("repo.deleted_at IS NULL")}
+	if o.RepoID != 0 {
+		conds = append(conds, sqlf.Sprintf("repo_disk_usage.repo_id=%d", o.RepoID))
+	}
+	if o.Shard != "" {
+		conds = append(conds, sqlf.Sprintf("repo_disk_usage.shard=%s", o.Shard))
+	}
+	return conds
+}
+
+// List lists the disk usage of repositories (largest first) that satisfy the options.
+//
+// 🚨 SECURITY: The caller must ensure that the actor is a site admin.
+func (*repoDiskUsage) List(ctx context.Context, opt RepoDiskUsageListOptions) ([]*RepoDiskUsageRecord, error) {
+	if Mocks.RepoDiskUsage.List != nil {
+		return Mocks.RepoDiskUsage.List(opt)
+	}
+
+	q := sqlf.Sprintf(`
+SELECT repo_disk_usage.repo_id, repo.name, repo_disk_usage.shard, repo_disk_usage.size_bytes, repo_disk_usage.updated_at
+FROM repo_disk_usage
+JOIN repo ON repo.id = repo_disk_usage.repo_id
+WHERE (%s)
+ORDER BY repo_disk_usage.size_bytes DESC, repo_disk_usage.repo_id ASC, repo_disk_usage.shard ASC
+%s`,
+		sqlf.Join(opt.sqlConditions(), ") AND ("),
+		opt.LimitOffset.SQL(),
+	)
+	rows, err := dbconn.Global.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var usage []*RepoDiskUsageRecord
+	for rows.Next() {
+		var u RepoDiskUsageRecord
+		if err := rows.Scan(&u.RepoID, &u.RepoName, &u.Shard, &u.SizeBytes, &u.UpdatedAt); err != nil {
+			return nil, err
+		}
+		usage = append(usage, &u)
+	}
+	return usage, rows.Err()
+}
+
+// Count counts the disk usage entries of repositories that satisfy the options (ignoring
+// limit and offset).
+//
+// 🚨 SECURITY: The caller must ensure that the actor is a site admin.
+func (*repoDiskUsage) Count(ctx context.Context, opt RepoDiskUsageListOptions) (int, error) {
+	if Mocks.RepoDiskUsage.Count != nil {
+		return Mocks.RepoDiskUsage.Count(opt)
+	}
+
+	q := sqlf.Sprintf(
+		"SELECT COUNT(*) FROM repo_disk_usage JOIN repo ON repo.id = repo_disk_usage.repo_id WHERE (%s)",
+		sqlf.Join(opt.sqlConditions(), ") AND ("),
+	)
+	var count int
+	err := dbconn.Global.QueryRowContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...).Scan(&count)
+	return count, err
+}
+
+type MockRepoDiskUsage struct {
+	List  func(opt RepoDiskUsageListOptions) ([]*RepoDiskUsageRecord, error)
+	Count func(opt RepoDiskUsageListOptions) (int, error)
+}
@@ -20,8 +20,9 @@ import (
 )
 
 type repoNotFoundErr struct {
-	ID   api.RepoID
-	Name api.RepoName
+	ID           api.RepoID
+	Name         api.RepoName
+	ExternalRepo api.ExternalRepoSpec
 }
 
 func (e *repoNotFoundErr) Error() string {
@@ -31,6 +32,9 @@ func (e *repoNotFoundErr) Error() string {
 	if e.ID != 0 {
 		return fmt.Sprintf("repo not found: id=%d", e.ID)
 	}
+	if e.ExternalRepo != (api.ExternalRepoSpec{}) {
+		return fmt.Sprintf("repo not found: external_repo=%s", e.ExternalRepo)
+	}
 	return "repo not found"
 }
 
@@ -117,6 +121,27 @@ func (s *repos) GetByIDs(ctx context.Context, ids ...api.RepoID) ([]*types.Repo,
 	return s.getReposBySQL(ctx, true, q)
 }
 
+// GetByExternalRepo returns the repository identified by the given external repository spec
+// (the repository's ID on the code host that it was synced from).
+func (s *repos) GetByExternalRepo(ctx context.Context, spec api.ExternalRepoSpec) (*types.Repo, error) {
+	if Mocks.Repos.GetByExternalRepo != nil {
+		return Mocks.Repos.GetByExternalRepo(ctx, spec)
+	}
+
+	repos, err := s.getBySQL(ctx, sqlf.Sprintf(
+		"external_id=%s AND external_service_type=%s AND external_service_id=%s LIMIT 1",
+		spec.ID, spec.ServiceType, spec.ServiceID,
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(repos) == 0 {
+		return nil, &repoNotFoundErr{ExternalRepo: spec}
+	}
+	return repos[0], nil
+}
+
 func (s *repos) Count(ctx context.Context, opt ReposListOptions) (int, error) {
 	if Mocks.Repos.Count != nil {
 		return Mocks.Repos.Count(ctx, opt)
@@ -15,6 +15,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/db/dbconn"
 	"github.com/sourcegraph/sourcegraph/internal/db/dbtesting"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
 )
 
 /*
@@ -203,6 +204,39 @@ func TestRepos_Get(t *testing.T) {
 	}
 }
 
+func TestRepos_GetByExternalRepo(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+
+	dbtesting.SetupGlobalTestDB(t)
+	ctx := context.Background()
+
+	spec := api.ExternalRepoSpec{
+		ID:          "a",
+		ServiceType: "b",
+		ServiceID:   "c",
+	}
+	want := mustCreate(ctx, t, &types.Repo{
+		Name:         "r",
+		ExternalRepo: spec,
+		RepoFields:   &types.RepoFields{URI: "u"},
+	})
+
+	repo, err := Repos.GetByExternalRepo(ctx, spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !jsonEqual(t, repo, want[0]) {
+		t.Errorf("got %v, want %v", repo, want[0])
+	}
+
+	spec.ServiceID = "d"
+	if _, err := Repos.GetByExternalRepo(ctx, spec); !errcode.IsNotFound(err) {
+		t.Errorf("got err %v, want not found", err)
+	}
+}
+
 func TestRepos_GetByIDs(t *testing.T) {
 	if testing.Short() {
 		t.Skip()
@@ -16,7 +16,11 @@ var (
 	Settings                  = &settings{}
 	Users                     = &users{}
 	UserEmails                = &userEmails{}
+	UserSessions              = &userSessions{}
+	UserTOTP                  = &userTOTP{}
 	EventLogs                 = &eventLogs{}
+	RepoAccessLogs            = &repoAccessLogs{}
+	RepoDiskUsage             = &repoDiskUsage{}
 
 	SurveyResponses = &surveyResponses{}
 
@@ -0,0 +1,232 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/internal/db/dbconn"
+)
+
+// UserSession describes an active session of a user. The session data itself lives in the
+// session store (Redis); this record is the index that allows listing and revoking sessions
+// by user.
+type UserSession struct {
+	ID           int64
+	UserID       int32
+	IP           string
+	UserAgent    string
+	CreatedAt    time.Time
+	LastActiveAt time.Time
+	ExpiryPeriod time.Duration // the session expires ExpiryPeriod after LastActiveAt
+}
+
+// ErrUserSessionNotFound occurs when a database operation expects a specific user session to
+// exist but it does not exist (or has been revoked).
+var ErrUserSessionNotFound = errors.New("user session not found")
+
+type userSessions struct{}
+
+// Create records a new session for the user that expires expiryPeriod after it was last active,
+// and returns its ID.
+func (s *userSessions) Create(ctx context.Context, userID int32, ip, userAgent string, expiryPeriod time.Duration) (id int64, err error) {
+	if Mocks.UserSessions.Create != nil {
+		return Mocks.UserSessions.Create(userID, ip, userAgent, expiryPeriod)
+	}
+
+	if err := dbconn.Global.QueryRowContext(ctx,
+		// Include users table query (with "FOR UPDATE") to ensure that the user has not been
+		// deleted. If it was deleted, the query will return an error.
+		`
+WITH session_user AS (
+  SELECT id FROM users WHERE id=$1 AND deleted_at IS NULL FOR UPDATE
+)
+INSERT INTO user_sessions(user_id, ip, user_agent, expiry_period) SELECT session_user.id, $2, $3, $4 * interval '1 second' FROM session_user RETURNING id
+`,
+		userID, ip, userAgent, expiryPeriod.Seconds(),
+	).Scan(&id); err != nil {
+		if err == sql.ErrNoRows {
+			return 0, userNotFoundErr{args: []interface{}{userID}}
+		}
+		return 0, err
+	}
+	return id, nil
+}
+
+// Lookup returns the session with the given ID if and only if it belongs to the given user and
+// has not been revoked. Otherwise ErrUserSessionNotFound is returned.
+//
+// 🚨 SECURITY: This is used to enforce session revocation, a session must be rejected if this
+// method returns an error.
+func (s *userSessions) Lookup(ctx context.Context, id int64, userID int32) (*UserSession, error) {
+	if Mocks.UserSessions.Lookup != nil {
+		return Mocks.UserSessions.Lookup(id, userID)
+	}
+
+	results, err := s.list(ctx, []*sqlf.Query{
+		sqlf.Sprintf("id=%d", id),
+		sqlf.Sprintf("user_id=%d", userID),
+		sqlf.Sprintf("revoked_at IS NULL"),
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(results) == 0 {
+		return nil, ErrUserSessionNotFound
+	}
+	return results[0], nil
+}
+
+// Touch updates the last-active-at date of the session.
+func (s *userSessions) Touch(ctx context.Context, id int64) error {
+	if Mocks.UserSessions.Touch != nil {
+		return Mocks.UserSessions.Touch(id)
+	}
+
+	_, err := dbconn.Global.ExecContext(ctx, "UPDATE user_sessions SET last_active_at=now() WHERE id=$1", id)
+	return err
+}
+
+// GetByID retrieves the (non-revoked) session given its ID.
+//
+// 🚨 SECURITY: The caller must ensure that the actor is permitted to view this session.
+func (s *userSessions) GetByID(ctx context.Context, id int64) (*UserSession, error) {
+	if Mocks.UserSessions.GetByID != nil {
+		return Mocks.UserSessions.GetByID(id)
+	}
+
+	results, err := s.list(ctx, []*sqlf.Query{
+		sqlf.Sprintf("id=%d", id),
+		sqlf.Sprintf("revoked_at IS NULL"),
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(results) == 0 {
+		return nil, ErrUserSessionNotFound
+	}
+	return results[0], nil
+}
+
+// UserSessionsListOptions contains options for listing user sessions.
+type UserSessionsListOptions struct {
+	UserID int32 // only list sessions of this user
+	*LimitOffset
+}
+
+func (o UserSessionsListOptions) sqlConditions() []*sqlf.Query {
+	conds := []*sqlf.Query{
+		sqlf.Sprintf("revoked_at IS NULL"),
+		// Expired sessions are only revoked once they are used again, which may never happen.
+		sqlf.Sprintf("last_active_at + expiry_period > now()"),
+	}
+	if o.UserID != 0 {
+		conds = append(conds, sqlf.Sprintf("user_id=%d", o.UserID))
+	}
+	return conds
+}
+
+// List lists all non-revoked, unexpired sessions that satisfy the options.
+//
+// 🚨 SECURITY: The caller must ensure that the actor is permitted to list with the specified
+// options.
+func (s *userSessions) List(ctx context.Context, opt UserSessionsListOptions) ([]*UserSession, error) {
+	if Mocks.UserSessions.List != nil {
+		return Mocks.UserSessions.List(opt)
+	}
+	return s.list(ctx, opt.sqlConditions(), opt.LimitOffset)
+}
+
+func (s *userSessions) list(ctx context.Context, conds []*sqlf.Query, limitOffset *LimitOffset) ([]*UserSession, error) {
+	q := sqlf.Sprintf(`
+SELECT id, user_id, ip, user_agent, created_at, last_active_at, EXTRACT(EPOCH FROM expiry_period) FROM user_sessions
+WHERE (%s)
+ORDER BY last_active_at DESC, id DESC
+%s`,
+		sqlf.Join(conds, ") AND ("),
+		limitOffset.SQL(),
+	)
+
+	rows, err := dbconn.Global.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []*UserSession
+	for rows.Next() {
+		var (
+			us                  UserSession
+			expiryPeriodSeconds float64
+		)
+		if err := rows.Scan(&us.ID, &us.UserID, &us.IP, &us.UserAgent, &us.CreatedAt, &us.LastActiveAt, &expiryPeriodSeconds); err != nil {
+			return nil, err
+		}
+		us.ExpiryPeriod = time.Duration(expiryPeriodSeconds * float64(time.Second))
+		results = append(results, &us)
+	}
+	return results, rows.Err()
+}
+
+// Count counts all non-revoked, unexpired sessions that satisfy the options (ignoring limit and offset).
+//
+// 🚨 SECURITY: The caller must ensure that the actor is permitted to count the sessions.
+func (s *userSessions) Count(ctx context.Context, opt UserSessionsListOptions) (int, error) {
+	q := sqlf.Sprintf("SELECT COUNT(*) FROM user_sessions WHERE (%s)", sqlf.Join(opt.sqlConditions(), ") AND ("))
+	var count int
+	if err := dbconn.Global.QueryRowContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...).Scan(&count); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// RevokeByID revokes a session given its ID and associated user.
+//
+// 🚨 SECURITY: The caller must ensure that the actor is permitted to revoke the session.
+func (s *userSessions) RevokeByID(ctx context.Context, id int64, userID int32) error {
+	if Mocks.UserSessions.RevokeByID != nil {
+		return Mocks.UserSessions.RevokeByID(id, userID)
+	}
+
+	n, err := s.revoke(ctx, sqlf.Sprintf("id=%d AND user_id=%d", id, userID))
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrUserSessionNotFound
+	}
+	return nil
+}
+
+// RevokeByUser revokes all sessions of the user and returns the number of revoked sessions.
+//
+// 🚨 SECURITY: The caller must ensure that the actor is permitted to revoke the sessions.
+func (s *userSessions) RevokeByUser(ctx context.Context, userID int32) (int64, error) {
+	if Mocks.UserSessions.RevokeByUser != nil {
+		return Mocks.UserSessions.RevokeByUser(userID)
+	}
+	return s.revoke(ctx, sqlf.Sprintf("user_id=%d", userID))
+}
+
+func (s *userSessions) revoke(ctx context.Context, cond *sqlf.Query) (int64, error) {
+	conds := []*sqlf.Query{cond, sqlf.Sprintf("revoked_at IS NULL")}
+	q := sqlf.Sprintf("UPDATE user_sessions SET revoked_at=now() WHERE (%s)", sqlf.Join(conds, ") AND ("))
+
+	res, err := dbconn.Global.ExecContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+type MockUserSessions struct {
+	Create       func(userID int32, ip, userAgent string, expiryPeriod time.Duration) (int64, error)
+	Lookup       func(id int64, userID int32) (*UserSession, error)
+	Touch        func(id int64) error
+	GetByID      func(id int64) (*UserSession, error)
+	List         func(opt UserSessionsListOptions) ([]*UserSession, error)
+	RevokeByID   func(id int64, userID int32) error
+	RevokeByUser func(userID int32) (int64, error)
+}
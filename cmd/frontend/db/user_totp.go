@@ -0,0 +1,313 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/internal/db/dbconn"
+	"github.com/sourcegraph/sourcegraph/internal/db/dbutil"
+)
+
+// UserTOTPFactor describes the time-based one-time password (TOTP) second factor of a user with a
+// builtin (username-password) account.
+type UserTOTPFactor struct {
+	UserID int32
+	Secret string // base32-encoded shared secret
+
+	// EnabledAt is the time when the user confirmed the enrollment. A nil value means that the
+	// enrollment is pending and the second factor is not yet required to sign in.
+	EnabledAt *time.Time
+}
+
+var (
+	// ErrUserTOTPNotFound occurs when a user has not enrolled in two-factor authentication.
+	ErrUserTOTPNotFound = errors.New("two-factor authentication is not set up for user")
+
+	// ErrUserTOTPAlreadyEnabled occurs when beginning an enrollment for a user who already has
+	// two-factor authentication enabled.
+	ErrUserTOTPAlreadyEnabled = errors.New("two-factor authentication is already enabled for user")
+
+	// ErrTwoFactorRateLimit occurs when a user has made too many failed verification attempts.
+	ErrTwoFactorRateLimit = errors.New("two-factor authentication rate limit reached")
+)
+
+var (
+	// twoFactorMaxFailedAttempts is the number of failed verification attempts after which
+	// further attempts are rejected until twoFactorLockout has passed since the last failed
+	// attempt.
+	twoFactorMaxFailedAttempts = 5
+	twoFactorLockout           = "5 minutes"
+)
+
+type userTOTP struct{}
+
+// GetByUserID returns the TOTP second factor (enabled or pending) of the user.
+//
+// 🚨 SECURITY: The secret must never be shown to anyone but the user during enrollment.
+func (s *userTOTP) GetByUserID(ctx context.Context, userID int32) (*UserTOTPFactor, error) {
+	if Mocks.UserTOTP.GetByUserID != nil {
+		return Mocks.UserTOTP.GetByUserID(userID)
+	}
+
+	t := UserTOTPFactor{UserID: userID}
+	err := dbconn.Global.QueryRowContext(ctx, "SELECT secret, enabled_at FROM user_totp WHERE user_id=$1", userID).Scan(&t.Secret, &t.EnabledAt)
+	if err == sql.ErrNoRows {
+		return nil, ErrUserTOTPNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// IsEnabled reports whether the user has a confirmed TOTP second factor.
+func (s *userTOTP) IsEnabled(ctx context.Context, userID int32) (bool, error) {
+	t, err := s.GetByUserID(ctx, userID)
+	if err == ErrUserTOTPNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return t.EnabledAt != nil, nil
+}
+
+// CreatePending stores a new, not yet confirmed secret for the user, replacing any previous
+// pending enrollment. It fails with ErrUserTOTPAlreadyEnabled if the user already has two-factor
+// authentication enabled.
+func (s *userTOTP) CreatePending(ctx context.Context, userID int32, secret string) error {
+	if Mocks.UserTOTP.CreatePending != nil {
+		return Mocks.UserTOTP.CreatePending(userID, secret)
+	}
+
+	res, err := dbconn.Global.ExecContext(ctx, `
+INSERT INTO user_totp(user_id, secret) VALUES($1, $2)
+ON CONFLICT (user_id) DO UPDATE SET secret=excluded.secret, failed_attempts=0, last_failed_attempt_at=NULL, created_at=now()
+WHERE user_totp.enabled_at IS NULL
+`, userID, secret)
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrUserTOTPAlreadyEnabled
+	}
+	return nil
+}
+
+// Enable confirms the pending enrollment of the user and replaces the user's recovery codes with
+// the given ones (which are stored hashed).
+//
+// 🚨 SECURITY: The caller must have verified a code generated from the pending secret.
+func (s *userTOTP) Enable(ctx context.Context, userID int32, recoveryCodes []string) error {
+	if Mocks.UserTOTP.Enable != nil {
+		return Mocks.UserTOTP.Enable(userID, recoveryCodes)
+	}
+
+	return dbutil.Transaction(ctx, dbconn.Global, func(tx *sql.Tx) error {
+		res, err := tx.ExecContext(ctx, "UPDATE user_totp SET enabled_at=now() WHERE user_id=$1 AND enabled_at IS NULL", userID)
+		if err != nil {
+			return err
+		}
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+		if affected == 0 {
+			return ErrUserTOTPNotFound
+		}
+		return replaceRecoveryCodes(ctx, tx, userID, recoveryCodes)
+	})
+}
+
+// ReplaceRecoveryCodes invalidates all recovery codes of the user and stores the given ones
+// (hashed) instead.
+func (s *userTOTP) ReplaceRecoveryCodes(ctx context.Context, userID int32, recoveryCodes []string) error {
+	if Mocks.UserTOTP.ReplaceRecoveryCodes != nil {
+		return Mocks.UserTOTP.ReplaceRecoveryCodes(userID, recoveryCodes)
+	}
+
+	return dbutil.Transaction(ctx, dbconn.Global, func(tx *sql.Tx) error {
+		return replaceRecoveryCodes(ctx, tx, userID, recoveryCodes)
+	})
+}
+
+func replaceRecoveryCodes(ctx context.Context, tx *sql.Tx, userID int32, recoveryCodes []string) error {
+	if _, err := tx.ExecContext(ctx, "DELETE FROM user_totp_recovery_codes WHERE user_id=$1", userID); err != nil {
+		return err
+	}
+	for _, code := range recoveryCodes {
+		// 🚨 SECURITY: Recovery codes are equivalent to passwords, so only store their hashes.
+		hash, err := hashPassword(code)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, "INSERT INTO user_totp_recovery_codes(user_id, code_hash) VALUES($1, $2)", userID, hash); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// UseRecoveryCode reports whether code is one of the user's unused recovery codes. If it is, the
+// code is marked as used so it can't be used again.
+func (s *userTOTP) UseRecoveryCode(ctx context.Context, userID int32, code string) (bool, error) {
+	if Mocks.UserTOTP.UseRecoveryCode != nil {
+		return Mocks.UserTOTP.UseRecoveryCode(userID, code)
+	}
+	if code == "" {
+		return false, nil
+	}
+
+	rows, err := dbconn.Global.QueryContext(ctx, "SELECT id, code_hash FROM user_totp_recovery_codes WHERE user_id=$1 AND used_at IS NULL", userID)
+	if err != nil {
+		return false, err
+	}
+	defer rows.Close()
+
+	var match int64
+	for rows.Next() {
+		var (
+			id   int64
+			hash string
+		)
+		if err := rows.Scan(&id, &hash); err != nil {
+			return false, err
+		}
+		if validPassword(hash, code) {
+			match = id
+			break
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return false, err
+	}
+	if match == 0 {
+		return false, nil
+	}
+
+	// 🚨 SECURITY: Only succeed if this request marked the code as used, so that concurrent
+	// requests can't use the same code twice.
+	res, err := dbconn.Global.ExecContext(ctx, "UPDATE user_totp_recovery_codes SET used_at=now() WHERE id=$1 AND used_at IS NULL", match)
+	if err != nil {
+		return false, err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected == 1, nil
+}
+
+// CountRecoveryCodes returns the number of unused recovery codes of the user.
+func (s *userTOTP) CountRecoveryCodes(ctx context.Context, userID int32) (int, error) {
+	if Mocks.UserTOTP.CountRecoveryCodes != nil {
+		return Mocks.UserTOTP.CountRecoveryCodes(userID)
+	}
+
+	var count int
+	err := dbconn.Global.QueryRowContext(ctx, "SELECT COUNT(*) FROM user_totp_recovery_codes WHERE user_id=$1 AND used_at IS NULL", userID).Scan(&count)
+	return count, err
+}
+
+// RegisterAttempt records a verification attempt for the user's second factor. The attempt counts
+// as failed until ClearFailedAttempts is called. It returns ErrTwoFactorRateLimit (without
+// recording the attempt) if the user has made too many failed attempts recently.
+//
+// 🚨 SECURITY: This must be called before checking a code, so that codes can't be guessed by
+// brute force (also not by making many concurrent attempts).
+func (s *userTOTP) RegisterAttempt(ctx context.Context, userID int32) error {
+	if Mocks.UserTOTP.RegisterAttempt != nil {
+		return Mocks.UserTOTP.RegisterAttempt(userID)
+	}
+
+	res, err := dbconn.Global.ExecContext(ctx, `
+UPDATE user_totp SET
+  failed_attempts=CASE WHEN last_failed_attempt_at IS NULL OR last_failed_attempt_at + interval '`+twoFactorLockout+`' < now() THEN 1 ELSE failed_attempts+1 END,
+  last_failed_attempt_at=now()
+WHERE user_id=$1 AND NOT (failed_attempts >= $2 AND last_failed_attempt_at + interval '`+twoFactorLockout+`' >= now())
+`, userID, twoFactorMaxFailedAttempts)
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		if _, err := s.GetByUserID(ctx, userID); err != nil {
+			return err
+		}
+		return ErrTwoFactorRateLimit
+	}
+	return nil
+}
+
+// UseStep records that a code of the given time step was accepted for the user. It reports false if
+// a code of the same or a later time step was accepted before, in which case the code must be
+// rejected.
+//
+// 🚨 SECURITY: This must be called for every accepted code, so that an observed code can't be
+// used again while it is still valid (also not by making concurrent attempts).
+func (s *userTOTP) UseStep(ctx context.Context, userID int32, step int64) (bool, error) {
+	if Mocks.UserTOTP.UseStep != nil {
+		return Mocks.UserTOTP.UseStep(userID, step)
+	}
+
+	res, err := dbconn.Global.ExecContext(ctx, "UPDATE user_totp SET last_used_step=$2 WHERE user_id=$1 AND (last_used_step IS NULL OR last_used_step < $2)", userID, step)
+	if err != nil {
+		return false, err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected == 1, nil
+}
+
+// ClearFailedAttempts resets the failed verification attempts of the user after a successful
+// verification.
+func (s *userTOTP) ClearFailedAttempts(ctx context.Context, userID int32) error {
+	if Mocks.UserTOTP.ClearFailedAttempts != nil {
+		return Mocks.UserTOTP.ClearFailedAttempts(userID)
+	}
+
+	_, err := dbconn.Global.ExecContext(ctx, "UPDATE user_totp SET failed_attempts=0, last_failed_attempt_at=NULL WHERE user_id=$1", userID)
+	return err
+}
+
+// Delete removes the second factor (enabled or pending) and all recovery codes of the user.
+//
+// 🚨 SECURITY: The caller must ensure that the actor is permitted to disable two-factor
+// authentication for the user.
+func (s *userTOTP) Delete(ctx context.Context, userID int32) error {
+	if Mocks.UserTOTP.Delete != nil {
+		return Mocks.UserTOTP.Delete(userID)
+	}
+
+	return dbutil.Transaction(ctx, dbconn.Global, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, "DELETE FROM user_totp_recovery_codes WHERE user_id=$1", userID); err != nil {
+			return err
+		}
+		_, err := tx.ExecContext(ctx, "DELETE FROM user_totp WHERE user_id=$1", userID)
+		return err
+	})
+}
+
+type MockUserTOTP struct {
+	GetByUserID          func(userID int32) (*UserTOTPFactor, error)
+	CreatePending        func(userID int32, secret string) error
+	Enable               func(userID int32, recoveryCodes []string) error
+	ReplaceRecoveryCodes func(userID int32, recoveryCodes []string) error
+	UseRecoveryCode      func(userID int32, code string) (bool, error)
+	CountRecoveryCodes   func(userID int32) (int, error)
+	RegisterAttempt      func(userID int32) error
+	UseStep              func(userID int32, step int64) (bool, error)
+	ClearFailedAttempts  func(userID int32) error
+	Delete               func(userID int32) error
+}
@@ -0,0 +1,159 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/internal/db/dbtesting"
+)
+
+// 🚨 SECURITY: This tests the enrollment and recovery codes of the TOTP second factor.
+func TestUserTOTP_EnableAndRecoveryCodes(t *testing.T) {
+	dbtesting.SetupGlobalTestDB(t)
+	ctx := context.Background()
+
+	user, err := Users.Create(ctx, NewUser{
+		Email:                 "a@example.com",
+		Username:              "u",
+		Password:              "p",
+		EmailVerificationCode: "c",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := UserTOTP.GetByUserID(ctx, user.ID); err != ErrUserTOTPNotFound {
+		t.Fatalf("got err %v, want %v", err, ErrUserTOTPNotFound)
+	}
+
+	if err := UserTOTP.CreatePending(ctx, user.ID, "s1"); err != nil {
+		t.Fatal(err)
+	}
+	// A pending enrollment can be replaced.
+	if err := UserTOTP.CreatePending(ctx, user.ID, "s2"); err != nil {
+		t.Fatal(err)
+	}
+	if enabled, err := UserTOTP.IsEnabled(ctx, user.ID); err != nil {
+		t.Fatal(err)
+	} else if enabled {
+		t.Error("pending enrollment should not be enabled")
+	}
+
+	if err := UserTOTP.Enable(ctx, user.ID, []string{"r1", "r2"}); err != nil {
+		t.Fatal(err)
+	}
+	totp, err := UserTOTP.GetByUserID(ctx, user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if totp.Secret != "s2" || totp.EnabledAt == nil {
+		t.Errorf("got %+v, want enabled with secret s2", totp)
+	}
+	if err := UserTOTP.CreatePending(ctx, user.ID, "s3"); err != ErrUserTOTPAlreadyEnabled {
+		t.Errorf("got err %v, want %v", err, ErrUserTOTPAlreadyEnabled)
+	}
+
+	if ok, err := UserTOTP.UseRecoveryCode(ctx, user.ID, "x"); err != nil {
+		t.Fatal(err)
+	} else if ok {
+		t.Error("invalid recovery code was accepted")
+	}
+	if ok, err := UserTOTP.UseRecoveryCode(ctx, user.ID, "r1"); err != nil {
+		t.Fatal(err)
+	} else if !ok {
+		t.Error("valid recovery code was rejected")
+	}
+	if ok, err := UserTOTP.UseRecoveryCode(ctx, user.ID, "r1"); err != nil {
+		t.Fatal(err)
+	} else if ok {
+		t.Error("used recovery code was accepted again")
+	}
+	if n, err := UserTOTP.CountRecoveryCodes(ctx, user.ID); err != nil {
+		t.Fatal(err)
+	} else if n != 1 {
+		t.Errorf("got %d unused recovery codes, want 1", n)
+	}
+
+	if err := UserTOTP.Delete(ctx, user.ID); err != nil {
+		t.Fatal(err)
+	}
+	if enabled, err := UserTOTP.IsEnabled(ctx, user.ID); err != nil {
+		t.Fatal(err)
+	} else if enabled {
+		t.Error("deleted second factor should not be enabled")
+	}
+}
+
+// 🚨 SECURITY: This tests that verification attempts are rate limited.
+func TestUserTOTP_RegisterAttempt(t *testing.T) {
+	dbtesting.SetupGlobalTestDB(t)
+	ctx := context.Background()
+
+	user, err := Users.Create(ctx, NewUser{
+		Email:                 "a@example.com",
+		Username:              "u",
+		Password:              "p",
+		EmailVerificationCode: "c",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := UserTOTP.RegisterAttempt(ctx, user.ID); err != ErrUserTOTPNotFound {
+		t.Fatalf("got err %v, want %v", err, ErrUserTOTPNotFound)
+	}
+
+	if err := UserTOTP.CreatePending(ctx, user.ID, "s"); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < twoFactorMaxFailedAttempts; i++ {
+		if err := UserTOTP.RegisterAttempt(ctx, user.ID); err != nil {
+			t.Fatalf("attempt %d: %s", i, err)
+		}
+	}
+	if err := UserTOTP.RegisterAttempt(ctx, user.ID); err != ErrTwoFactorRateLimit {
+		t.Fatalf("got err %v, want %v", err, ErrTwoFactorRateLimit)
+	}
+
+	if err := UserTOTP.ClearFailedAttempts(ctx, user.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := UserTOTP.RegisterAttempt(ctx, user.ID); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// 🚨 SECURITY: This tests that codes of a time step can't be used again.
+func TestUserTOTP_UseStep(t *testing.T) {
+	dbtesting.SetupGlobalTestDB(t)
+	ctx := context.Background()
+
+	user, err := Users.Create(ctx, NewUser{
+		Email:                 "a@example.com",
+		Username:              "u",
+		Password:              "p",
+		EmailVerificationCode: "c",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := UserTOTP.CreatePending(ctx, user.ID, "s"); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		step int64
+		want bool
+	}{
+		{step: 100, want: true},
+		{step: 100, want: false},
+		{step: 99, want: false},
+		{step: 101, want: true},
+	} {
+		if ok, err := UserTOTP.UseStep(ctx, user.ID, test.step); err != nil {
+			t.Fatal(err)
+		} else if ok != test.want {
+			t.Errorf("step %d: got %v, want %v", test.step, ok, test.want)
+		}
+	}
+}
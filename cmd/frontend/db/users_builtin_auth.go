@@ -79,6 +79,10 @@ func (u *users) SetPassword(ctx context.Context, id int32, resetCode string, new
 	if _, err := dbconn.Global.ExecContext(ctx, "UPDATE users SET passwd_reset_code=NULL, passwd_reset_time=NULL, passwd=$1 WHERE id=$2", passwd, id); err != nil {
 		return false, err
 	}
+	// 🚨 SECURITY: Revoke all existing sessions, they may have been established with the old password.
+	if _, err := UserSessions.RevokeByUser(ctx, id); err != nil {
+		return false, err
+	}
 	return true, nil
 }
 
@@ -115,6 +119,10 @@ func (u *users) UpdatePassword(ctx context.Context, id int32, oldPassword, newPa
 	if _, err := dbconn.Global.ExecContext(ctx, "UPDATE users SET passwd_reset_code=NULL, passwd_reset_time=NULL, passwd=$1 WHERE id=$2", passwd, id); err != nil {
 		return err
 	}
+	// 🚨 SECURITY: Revoke all existing sessions, they may have been established with the old password.
+	if _, err := UserSessions.RevokeByUser(ctx, id); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -0,0 +1,10 @@
+// Package inventory exports symbols from frontend/internal/inventory. See the
+// parent package godoc for more information.
+package inventory
+
+import "github.com/sourcegraph/sourcegraph/cmd/frontend/internal/inventory"
+
+type (
+	Inventory = inventory.Inventory
+	Lang      = inventory.Lang
+)
@@ -16,6 +16,9 @@ type AuthzResolver interface {
 	UsersWithPendingPermissions(ctx context.Context) ([]string, error)
 	AuthorizedUsers(ctx context.Context, args *RepoAuthorizedUserArgs) (UserConnectionResolver, error)
 	RepositoryPermissionsInfo(ctx context.Context, repoID graphql.ID) (PermissionsInfoResolver, error)
+	AuthorizationExplain(ctx context.Context, args *AuthorizationExplainArgs) (AuthorizationExplanationResolver, error)
+	ScheduleUserPermissionsSync(ctx context.Context, args *UserPermissionsSyncArgs) (*EmptyResponse, error)
+	ScheduleRepositoryPermissionsSync(ctx context.Context, args *RepositoryPermissionsSyncArgs) (*EmptyResponse, error)
 }
 
 var authzInEnterprise = errors.New("authorization mutations and queries are only available in enterprise")
@@ -44,6 +47,18 @@ func (defaultAuthzResolver) RepositoryPermissionsInfo(ctx context.Context, repoI
 	return nil, authzInEnterprise
 }
 
+func (defaultAuthzResolver) AuthorizationExplain(ctx context.Context, args *AuthorizationExplainArgs) (AuthorizationExplanationResolver, error) {
+	return nil, authzInEnterprise
+}
+
+func (defaultAuthzResolver) ScheduleUserPermissionsSync(ctx context.Context, args *UserPermissionsSyncArgs) (*EmptyResponse, error) {
+	return nil, authzInEnterprise
+}
+
+func (defaultAuthzResolver) ScheduleRepositoryPermissionsSync(ctx context.Context, args *RepositoryPermissionsSyncArgs) (*EmptyResponse, error) {
+	return nil, authzInEnterprise
+}
+
 type RepoPermsArgs struct {
 	Repository graphql.ID
 	BindIDs    []string
@@ -63,3 +78,38 @@ type PermissionsInfoResolver interface {
 	SyncedAt() *DateTime
 	UpdatedAt() DateTime
 }
+
+type AuthorizationExplainArgs struct {
+	User       graphql.ID
+	Repository graphql.ID
+}
+
+type UserPermissionsSyncArgs struct {
+	User graphql.ID
+}
+
+type RepositoryPermissionsSyncArgs struct {
+	Repository graphql.ID
+}
+
+type AuthorizationExplanationResolver interface {
+	User() *UserResolver
+	Repository() *RepositoryResolver
+	Public() bool
+	Unrestricted() bool
+	Providers() []AuthorizationProviderExplanationResolver
+	UserPermissions() AuthorizationPermissionsExplanationResolver
+	RepositoryPermissions() AuthorizationPermissionsExplanationResolver
+	PendingBindIDs() []string
+}
+
+type AuthorizationProviderExplanationResolver interface {
+	ServiceType() string
+	ServiceID() string
+	AccountID() *string
+}
+
+type AuthorizationPermissionsExplanationResolver interface {
+	PermissionsInfoResolver
+	GrantsAccess() bool
+}
@@ -7,6 +7,7 @@ import (
 	graphql "github.com/graph-gophers/graphql-go"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/graphqlutil"
 	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/lsif"
 )
 
 // NewCodeIntelResolver will be set by enterprise.
@@ -16,7 +17,16 @@ type CodeIntelResolver interface {
 	LSIFUploadByID(ctx context.Context, id graphql.ID) (LSIFUploadResolver, error)
 	LSIFUploads(ctx context.Context, args *LSIFRepositoryUploadsQueryArgs) (LSIFUploadConnectionResolver, error)
 	DeleteLSIFUpload(ctx context.Context, id graphql.ID) (*EmptyResponse, error)
+	LSIFIndexByID(ctx context.Context, id graphql.ID) (LSIFIndexResolver, error)
+	LSIFIndexes(ctx context.Context, args *LSIFRepositoryIndexesQueryArgs) (LSIFIndexConnectionResolver, error)
+	LSIFRetention(ctx context.Context, repositoryID graphql.ID) ([]LSIFUploadRetentionResolver, error)
+	LSIFCoverage(ctx context.Context, args *LSIFRepositoryCoverageQueryArgs) (LSIFCoverageResolver, error)
+	LSIFRepositoryCoverage(ctx context.Context, args *LSIFRepositoryCoverageArgs) (LSIFRepositoryCoverageConnectionResolver, error)
 	LSIF(ctx context.Context, args *LSIFQueryArgs) (LSIFQueryResolver, error)
+
+	// LSIFSymbols returns the symbols from the LSIF uploads of a repository at exactly the given
+	// commit. It is used by symbol search and is not part of the GraphQL schema.
+	LSIFSymbols(ctx context.Context, args *LSIFSymbolsArgs) ([]lsif.LSIFSymbolLocation, error)
 }
 
 var codeIntelOnlyInEnterprise = errors.New("lsif uploads and queries are only available in enterprise")
@@ -35,10 +45,34 @@ func (defaultCodeIntelResolver) DeleteLSIFUpload(ctx context.Context, id graphql
 	return nil, codeIntelOnlyInEnterprise
 }
 
+func (defaultCodeIntelResolver) LSIFIndexByID(ctx context.Context, id graphql.ID) (LSIFIndexResolver, error) {
+	return nil, codeIntelOnlyInEnterprise
+}
+
+func (defaultCodeIntelResolver) LSIFIndexes(ctx context.Context, args *LSIFRepositoryIndexesQueryArgs) (LSIFIndexConnectionResolver, error) {
+	return nil, codeIntelOnlyInEnterprise
+}
+
+func (defaultCodeIntelResolver) LSIFRetention(ctx context.Context, repositoryID graphql.ID) ([]LSIFUploadRetentionResolver, error) {
+	return nil, codeIntelOnlyInEnterprise
+}
+
+func (defaultCodeIntelResolver) LSIFCoverage(ctx context.Context, args *LSIFRepositoryCoverageQueryArgs) (LSIFCoverageResolver, error) {
+	return nil, codeIntelOnlyInEnterprise
+}
+
+func (defaultCodeIntelResolver) LSIFRepositoryCoverage(ctx context.Context, args *LSIFRepositoryCoverageArgs) (LSIFRepositoryCoverageConnectionResolver, error) {
+	return nil, codeIntelOnlyInEnterprise
+}
+
 func (defaultCodeIntelResolver) LSIF(ctx context.Context, args *LSIFQueryArgs) (LSIFQueryResolver, error) {
 	return nil, codeIntelOnlyInEnterprise
 }
 
+func (defaultCodeIntelResolver) LSIFSymbols(ctx context.Context, args *LSIFSymbolsArgs) ([]lsif.LSIFSymbolLocation, error) {
+	return nil, codeIntelOnlyInEnterprise
+}
+
 func (r *schemaResolver) DeleteLSIFUpload(ctx context.Context, args *struct{ ID graphql.ID }) (*EmptyResponse, error) {
 	// We need to override the embedded method here as it takes slightly different arguments
 	return r.CodeIntelResolver.DeleteLSIFUpload(ctx, args.ID)
@@ -70,6 +104,7 @@ type LSIFUploadResolver interface {
 	Failure() LSIFUploadFailureReasonResolver
 	IsLatestForRepo() bool
 	PlaceInQueue() *int32
+	Diagnostics(ctx context.Context) ([]LSIFUploadDiagnosticResolver, error)
 }
 
 type LSIFUploadFailureReasonResolver interface {
@@ -77,16 +112,117 @@ type LSIFUploadFailureReasonResolver interface {
 	Stacktrace() string
 }
 
+type LSIFUploadDiagnosticResolver interface {
+	Severity() string
+	Code() string
+	Message() string
+	ElementID() string
+	Line() int32
+}
+
 type LSIFUploadConnectionResolver interface {
 	Nodes(ctx context.Context) ([]LSIFUploadResolver, error)
 	TotalCount(ctx context.Context) (*int32, error)
 	PageInfo(ctx context.Context) (*graphqlutil.PageInfo, error)
 }
 
+type LSIFUploadRetentionResolver interface {
+	Upload() LSIFUploadResolver
+	RetainedBecause() []string
+	Expired() bool
+}
+
+type LSIFIndexesQueryArgs struct {
+	graphqlutil.ConnectionArgs
+	State *string
+	After *string
+}
+
+type LSIFRepositoryIndexesQueryArgs struct {
+	*LSIFIndexesQueryArgs
+	RepositoryID graphql.ID
+}
+
+type LSIFIndexResolver interface {
+	ID() graphql.ID
+	InputCommit() string
+	Indexer() string
+	State() string
+	QueuedAt() DateTime
+	StartedAt() *DateTime
+	FinishedAt() *DateTime
+	Failure() *string
+	Log(ctx context.Context) (*string, error)
+	Upload(ctx context.Context) (LSIFUploadResolver, error)
+}
+
+type LSIFIndexConnectionResolver interface {
+	Nodes(ctx context.Context) ([]LSIFIndexResolver, error)
+	TotalCount(ctx context.Context) (*int32, error)
+	PageInfo(ctx context.Context) (*graphqlutil.PageInfo, error)
+}
+
+type LSIFCoverageArgs struct {
+	Path string
+}
+
+type LSIFRepositoryCoverageQueryArgs struct {
+	*LSIFCoverageArgs
+	Repository *RepositoryResolver
+}
+
+type LSIFRepositoryCoverageArgs struct {
+	graphqlutil.ConnectionArgs
+	After *string
+	Query *string
+}
+
+type LSIFCoverageResolver interface {
+	Commit() *GitCommitResolver
+	Uploads() []LSIFUploadCoverageResolver
+	Directories() []LSIFDirectoryCoverageResolver
+}
+
+type LSIFUploadCoverageResolver interface {
+	Upload() LSIFUploadResolver
+	CommitsBehindTip() int32
+}
+
+type LSIFDirectoryCoverageResolver interface {
+	Path() string
+	Languages() []LSIFLanguageCoverageResolver
+}
+
+type LSIFLanguageCoverageResolver interface {
+	Name() string
+	TotalBytes() float64
+	TotalLines() int32
+	Uploads() []LSIFUploadCoverageResolver
+	PartialUploads() []LSIFUploadCoverageResolver
+}
+
+type LSIFRepositoryCoverageConnectionResolver interface {
+	Nodes(ctx context.Context) ([]LSIFRepositoryCoverageResolver, error)
+	TotalCount(ctx context.Context) (int32, error)
+	PageInfo(ctx context.Context) (*graphqlutil.PageInfo, error)
+}
+
+type LSIFRepositoryCoverageResolver interface {
+	Repository() *RepositoryResolver
+	Coverage(ctx context.Context, args *LSIFCoverageArgs) (LSIFCoverageResolver, error)
+}
+
 type LSIFQueryResolver interface {
+	// Precise is false if the results are search-based heuristics rather than LSIF data.
+	Precise() bool
 	Definitions(ctx context.Context, args *LSIFQueryPositionArgs) (LocationConnectionResolver, error)
 	References(ctx context.Context, args *LSIFPagedQueryPositionArgs) (LocationConnectionResolver, error)
 	Hover(ctx context.Context, args *LSIFQueryPositionArgs) (HoverResolver, error)
+	Ranges(ctx context.Context, args *LSIFRangesArgs) ([]CodeIntelligenceRangeResolver, error)
+
+	// DocumentSymbols returns the symbols defined in the path, nested by containment. This
+	// is not exposed in the schema directly; it backs GitBlob.documentSymbols.
+	DocumentSymbols(ctx context.Context) ([]lsif.LSIFSymbol, error)
 }
 
 type LSIFQueryArgs struct {
@@ -96,11 +232,33 @@ type LSIFQueryArgs struct {
 	UploadID   int64
 }
 
+// LSIFSymbolsArgs selects the symbols returned by LSIFSymbols. The query and path patterns are
+// regular expressions.
+type LSIFSymbolsArgs struct {
+	RepoID          api.RepoID
+	Commit          api.CommitID
+	Query           string
+	IncludePatterns []string
+	ExcludePattern  string
+	Limit           int
+}
+
 type LSIFQueryPositionArgs struct {
 	Line      int32
 	Character int32
 }
 
+type LSIFRangesArgs struct {
+	Positions *[]LSIFQueryPositionArgs
+}
+
+type CodeIntelligenceRangeResolver interface {
+	Range() RangeResolver
+	Hover() HoverResolver
+	Definitions() LocationConnectionResolver
+	ReferenceCount() int32
+}
+
 type LSIFPagedQueryPositionArgs struct {
 	LSIFQueryPositionArgs
 	graphqlutil.ConnectionArgs
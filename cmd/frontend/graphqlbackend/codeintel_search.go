@@ -0,0 +1,244 @@
+package graphqlbackend
+
+import (
+	"context"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/sourcegraph/go-langserver/pkg/lsp"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/graphqlutil"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/lsif"
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+)
+
+const (
+	// searchBasedMaxFileBytes is the size of the largest file in which search-based code
+	// intelligence looks up the identifier under a position.
+	searchBasedMaxFileBytes = 1 << 20
+
+	// searchBasedDefinitionsLimit is the maximum number of symbols returned as definitions.
+	searchBasedDefinitionsLimit = 50
+
+	// searchBasedReferencesLimit is the default maximum number of references returned.
+	searchBasedReferencesLimit = 100
+
+	// searchBasedTimeout bounds the symbols and searcher requests of a single query.
+	searchBasedTimeout = 10 * time.Second
+)
+
+// searchBasedQueryResolver answers code intelligence queries without LSIF data. The identifier
+// under the requested position is matched with the symbols of the repository for definitions,
+// and with a word-boundary text search for references. Results are restricted to files with the
+// same extension as the requested file, and are imprecise: a definition may be of an unrelated
+// symbol of the same name, and references include all uses of the name.
+type searchBasedQueryResolver struct {
+	commit *GitCommitResolver
+	path   string
+}
+
+var _ LSIFQueryResolver = &searchBasedQueryResolver{}
+
+func newSearchBasedQueryResolver(commit *GitCommitResolver, path string) *searchBasedQueryResolver {
+	return &searchBasedQueryResolver{commit: commit, path: path}
+}
+
+func (r *searchBasedQueryResolver) Precise() bool { return false }
+
+func (r *searchBasedQueryResolver) Definitions(ctx context.Context, args *LSIFQueryPositionArgs) (LocationConnectionResolver, error) {
+	cachedRepo, commitID, err := r.gitRepo(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	word, err := r.identifierAt(ctx, cachedRepo, commitID, args.Line, args.Character)
+	if err != nil || word == "" {
+		return &searchBasedLocationConnectionResolver{}, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, searchBasedTimeout)
+	defer cancel()
+
+	symbols, err := backend.Symbols.ListTags(ctx, search.SymbolsParameters{
+		Repo:            r.commit.repo.repo.Name,
+		CommitID:        commitID,
+		Query:           "^" + regexp.QuoteMeta(word) + "$",
+		IsRegExp:        true,
+		IsCaseSensitive: true,
+		IncludePatterns: r.includePatterns(),
+		First:           searchBasedDefinitionsLimit,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Prefer definitions in the requested file, then in its directory
+	dir := path.Dir(r.path)
+	var sameFile, sameDir, others []LocationResolver
+	for _, symbol := range symbols {
+		lspRange := symbolRange(symbol)
+		location := NewLocationResolver(r.treeEntry(symbol.Path), &lspRange)
+
+		switch {
+		case symbol.Path == r.path:
+			sameFile = append(sameFile, location)
+		case path.Dir(symbol.Path) == dir:
+			sameDir = append(sameDir, location)
+		default:
+			others = append(others, location)
+		}
+	}
+
+	return &searchBasedLocationConnectionResolver{locations: append(append(sameFile, sameDir...), others...)}, nil
+}
+
+func (r *searchBasedQueryResolver) References(ctx context.Context, args *LSIFPagedQueryPositionArgs) (LocationConnectionResolver, error) {
+	cachedRepo, commitID, err := r.gitRepo(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	word, err := r.identifierAt(ctx, cachedRepo, commitID, args.Line, args.Character)
+	if err != nil || word == "" {
+		return &searchBasedLocationConnectionResolver{}, err
+	}
+
+	limit := searchBasedReferencesLimit
+	if args.First != nil && *args.First > 0 {
+		limit = int(*args.First)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, searchBasedTimeout)
+	defer cancel()
+
+	fileMatches, _, err := textSearch(ctx, search.SearcherURLs(), *cachedRepo, commitID, &search.TextPatternInfo{
+		Pattern:                regexp.QuoteMeta(word),
+		IsRegExp:               true,
+		IsWordMatch:            true,
+		IsCaseSensitive:        true,
+		FileMatchLimit:         int32(limit),
+		IncludePatterns:        r.includePatterns(),
+		PathPatternsAreRegExps: true,
+		PatternMatchesContent:  true,
+	}, searchBasedTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	var locations []LocationResolver
+	for _, fileMatch := range fileMatches {
+		for _, lineMatch := range fileMatch.JLineMatches {
+			for _, offsetAndLength := range lineMatch.JOffsetAndLengths {
+				if len(locations) >= limit {
+					break
+				}
+
+				lspRange := lsp.Range{
+					Start: lsp.Position{Line: int(lineMatch.JLineNumber), Character: int(offsetAndLength[0])},
+					End:   lsp.Position{Line: int(lineMatch.JLineNumber), Character: int(offsetAndLength[0] + offsetAndLength[1])},
+				}
+				locations = append(locations, NewLocationResolver(r.treeEntry(fileMatch.JPath), &lspRange))
+			}
+		}
+	}
+
+	return &searchBasedLocationConnectionResolver{locations: locations}, nil
+}
+
+// Hover returns nil, as search results do not carry documentation.
+func (r *searchBasedQueryResolver) Hover(ctx context.Context, args *LSIFQueryPositionArgs) (HoverResolver, error) {
+	return nil, nil
+}
+
+// Ranges returns no ranges, as resolving each identifier of a file with search would be too slow.
+func (r *searchBasedQueryResolver) Ranges(ctx context.Context, args *LSIFRangesArgs) ([]CodeIntelligenceRangeResolver, error) {
+	return []CodeIntelligenceRangeResolver{}, nil
+}
+
+// DocumentSymbols returns nil, as GitBlob.documentSymbols already falls back to the symbols service.
+func (r *searchBasedQueryResolver) DocumentSymbols(ctx context.Context) ([]lsif.LSIFSymbol, error) {
+	return nil, nil
+}
+
+func (r *searchBasedQueryResolver) gitRepo(ctx context.Context) (*gitserver.Repo, api.CommitID, error) {
+	cachedRepo, err := backend.CachedGitRepo(ctx, r.commit.repo.repo)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return cachedRepo, api.CommitID(r.commit.OID()), nil
+}
+
+// identifierAt returns the identifier under the given position of the requested file, or the
+// empty string if the position is not on an identifier.
+func (r *searchBasedQueryResolver) identifierAt(ctx context.Context, repo *gitserver.Repo, commitID api.CommitID, line, character int32) (string, error) {
+	content, err := git.ReadFile(ctx, *repo, commitID, r.path, searchBasedMaxFileBytes)
+	if err != nil {
+		return "", err
+	}
+
+	lines := strings.Split(string(content), "\n")
+	if line < 0 || int(line) >= len(lines) {
+		return "", nil
+	}
+
+	return identifierAt([]rune(lines[line]), int(character)), nil
+}
+
+// includePatterns restricts results to files with the same extension as the requested file.
+func (r *searchBasedQueryResolver) includePatterns() []string {
+	if ext := path.Ext(r.path); ext != "" {
+		return []string{regexp.QuoteMeta(ext) + "$"}
+	}
+	return nil
+}
+
+func (r *searchBasedQueryResolver) treeEntry(name string) *GitTreeEntryResolver {
+	return NewGitTreeEntryResolver(r.commit, CreateFileInfo(name, false))
+}
+
+// identifierAt returns the identifier of the given line that contains the given character
+// offset, or the empty string if the character is not part of an identifier.
+func identifierAt(line []rune, character int) string {
+	if character < 0 || character >= len(line) || !isIdentifierRune(line[character]) {
+		return ""
+	}
+
+	start := character
+	for start > 0 && isIdentifierRune(line[start-1]) {
+		start--
+	}
+	end := character
+	for end < len(line) && isIdentifierRune(line[end]) {
+		end++
+	}
+
+	// Numeric literals are not identifiers
+	if unicode.IsDigit(line[start]) {
+		return ""
+	}
+
+	return string(line[start:end])
+}
+
+func isIdentifierRune(r rune) bool {
+	return r == '_' || r == '$' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+type searchBasedLocationConnectionResolver struct {
+	locations []LocationResolver
+}
+
+func (r *searchBasedLocationConnectionResolver) Nodes(ctx context.Context) ([]LocationResolver, error) {
+	return r.locations, nil
+}
+
+func (r *searchBasedLocationConnectionResolver) PageInfo(ctx context.Context) (*graphqlutil.PageInfo, error) {
+	return graphqlutil.HasNextPage(false), nil
+}
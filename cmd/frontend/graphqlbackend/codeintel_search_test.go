@@ -0,0 +1,92 @@
+package graphqlbackend
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/graphqlutil"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/search"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+)
+
+func TestIdentifierAt(t *testing.T) {
+	testCases := []struct {
+		line      string
+		character int
+		expected  string
+	}{
+		{line: "func foo(bar int) {", character: 5, expected: "foo"},
+		{line: "func foo(bar int) {", character: 7, expected: "foo"},
+		{line: "func foo(bar int) {", character: 8, expected: ""},
+		{line: "\tx := $el_1 + 42", character: 8, expected: "$el_1"},
+		{line: "\tx := $el_1 + 42", character: 15, expected: ""},
+		{line: "\tπ := 3", character: 1, expected: "π"},
+		{line: "short", character: 10, expected: ""},
+	}
+
+	for _, testCase := range testCases {
+		if identifier := identifierAt([]rune(testCase.line), testCase.character); identifier != testCase.expected {
+			t.Errorf("unexpected identifier at %q:%d. want=%q have=%q", testCase.line, testCase.character, testCase.expected, identifier)
+		}
+	}
+}
+
+func TestSearchBasedReferences(t *testing.T) {
+	git.Mocks.ReadFile = func(commit api.CommitID, name string) ([]byte, error) {
+		return []byte("package foo\n\nfunc bar() {\n\tbaz()\n}\n"), nil
+	}
+	defer git.ResetMocks()
+
+	var patternInfo *search.TextPatternInfo
+	mockTextSearch = func(ctx context.Context, repo gitserver.Repo, commit api.CommitID, p *search.TextPatternInfo, fetchTimeout time.Duration) ([]*FileMatchResolver, bool, error) {
+		patternInfo = p
+		return []*FileMatchResolver{
+			{JPath: "foo.go", JLineMatches: []*lineMatch{{JLineNumber: 3, JOffsetAndLengths: [][2]int32{{1, 3}}}}},
+			{JPath: "sub/baz.go", JLineMatches: []*lineMatch{{JLineNumber: 10, JOffsetAndLengths: [][2]int32{{5, 3}, {20, 3}}}}},
+		}, false, nil
+	}
+	defer func() { mockTextSearch = nil }()
+
+	resolver := newSearchBasedQueryResolver(&GitCommitResolver{
+		repo: &RepositoryResolver{repo: &types.Repo{Name: "github.com/foo/bar"}},
+		oid:  GitObjectID("deadbeef"),
+	}, "foo.go")
+
+	first := int32(2)
+	connection, err := resolver.References(context.Background(), &LSIFPagedQueryPositionArgs{
+		LSIFQueryPositionArgs: LSIFQueryPositionArgs{Line: 3, Character: 2},
+		ConnectionArgs:        graphqlutil.ConnectionArgs{First: &first},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error resolving references: %s", err)
+	}
+
+	if patternInfo.Pattern != "baz" || !patternInfo.IsWordMatch {
+		t.Errorf("unexpected search pattern. want=%q (word match) have=%q", "baz", patternInfo.Pattern)
+	}
+	if diff := cmp.Diff([]string{`\.go$`}, patternInfo.IncludePatterns); diff != "" {
+		t.Errorf("unexpected include patterns (-want +got):\n%s", diff)
+	}
+
+	nodes, err := connection.Nodes(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error resolving nodes: %s", err)
+	}
+
+	var locations []string
+	for _, node := range nodes {
+		r := node.Range()
+		locations = append(locations, fmt.Sprintf("%s:%d:%d-%d", node.Resource().Path(), r.Start().Line(), r.Start().Character(), r.End().Character()))
+	}
+
+	expected := []string{"foo.go:3:1-4", "sub/baz.go:10:5-8"}
+	if diff := cmp.Diff(expected, locations); diff != "" {
+		t.Errorf("unexpected locations (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,100 @@
+package graphqlbackend
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"github.com/inconshreveable/log15"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/graphqlutil"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/inventory"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/gituri"
+	"github.com/sourcegraph/sourcegraph/internal/lsif"
+	"github.com/sourcegraph/sourcegraph/internal/symbols/protocol"
+	"github.com/sourcegraph/sourcegraph/internal/trace"
+)
+
+type documentSymbolsArgs struct {
+	graphqlutil.ConnectionArgs
+}
+
+// DocumentSymbols returns the symbols defined in this blob. Symbols from an LSIF upload are
+// preferred as they have precise ranges and nesting. When no upload can answer for this path,
+// the symbols are read from the symbols service instead.
+func (r *GitTreeEntryResolver) DocumentSymbols(ctx context.Context, args *documentSymbolsArgs) (*symbolConnectionResolver, error) {
+	symbols, err := r.preciseDocumentSymbols(ctx)
+	if err != nil && err != codeIntelOnlyInEnterprise {
+		log15.Warn("Failed to resolve precise document symbols, falling back to the symbols service", "repo", r.Repository().Name(), "path", r.Path(), "error", err)
+	}
+	if len(symbols) > 0 {
+		return &symbolConnectionResolver{symbols: symbols, first: args.First}, nil
+	}
+
+	query := ""
+	includePatterns := []string{"^" + regexp.QuoteMeta(r.Path()) + "$"}
+	symbols, err = computeSymbols(ctx, r.commit, &query, args.First, &includePatterns)
+	if err != nil && len(symbols) == 0 {
+		return nil, err
+	}
+	return &symbolConnectionResolver{symbols: symbols, first: args.First}, nil
+}
+
+// preciseDocumentSymbols returns the symbols of this blob from the LSIF upload that best answers
+// queries for it. This method returns no symbols if there is no such upload.
+func (r *GitTreeEntryResolver) preciseDocumentSymbols(ctx context.Context) ([]*symbolResolver, error) {
+	codeIntelRequests.WithLabelValues(trace.RequestOrigin(ctx)).Inc()
+	lsifResolver, err := EnterpriseResolvers.codeIntelResolver.LSIF(ctx, &LSIFQueryArgs{
+		Repository: r.Repository(),
+		Commit:     api.CommitID(r.Commit().OID()),
+		Path:       r.Path(),
+	})
+	if err != nil || lsifResolver == nil {
+		return nil, err
+	}
+
+	symbols, err := lsifResolver.DocumentSymbols(ctx)
+	if err != nil || len(symbols) == 0 {
+		return nil, err
+	}
+
+	baseURI, err := gituri.Parse("git://" + string(r.commit.repo.repo.Name) + "?" + string(r.commit.oid))
+	if err != nil {
+		return nil, err
+	}
+
+	language, _ := inventory.GetLanguageByFilename(r.Name())
+	return toPreciseSymbolResolvers(symbols, "", r.Path(), strings.ToLower(language), baseURI, r.commit), nil
+}
+
+// toPreciseSymbolResolvers flattens the given symbol tree depth-first. The name of each symbol
+// becomes the container name of its children.
+func toPreciseSymbolResolvers(symbols []lsif.LSIFSymbol, parent, path, lang string, baseURI *gituri.URI, commitResolver *GitCommitResolver) []*symbolResolver {
+	var resolvers []*symbolResolver
+	for _, symbol := range symbols {
+		resolver := &symbolResolver{
+			symbol: protocol.Symbol{
+				Name:   symbol.Name,
+				Parent: parent,
+				Path:   path,
+				Line:   symbol.Range.Start.Line + 1,
+			},
+			lspKind:  symbol.Kind,
+			language: lang,
+			uri:      baseURI.WithFilePath(path),
+		}
+		symbolRange := symbol.Range
+		resolver.location = &locationResolver{
+			resource: &GitTreeEntryResolver{
+				commit: commitResolver,
+				stat:   CreateFileInfo(path, false),
+			},
+			lspRange: &symbolRange,
+		}
+
+		resolvers = append(resolvers, resolver)
+		resolvers = append(resolvers, toPreciseSymbolResolvers(symbol.Children, symbol.Name, path, lang, baseURI, commitResolver)...)
+	}
+
+	return resolvers
+}
@@ -0,0 +1,57 @@
+package graphqlbackend
+
+import (
+	"testing"
+
+	"github.com/sourcegraph/go-lsp"
+	"github.com/sourcegraph/sourcegraph/internal/gituri"
+	"github.com/sourcegraph/sourcegraph/internal/lsif"
+)
+
+func TestToPreciseSymbolResolvers(t *testing.T) {
+	baseURI, err := gituri.Parse("git://github.com/foo/bar?deadbeef")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	symbols := []lsif.LSIFSymbol{
+		{
+			Name:  "Server",
+			Kind:  lsp.SKStruct,
+			Range: lsp.Range{Start: lsp.Position{Line: 3, Character: 5}, End: lsp.Position{Line: 3, Character: 11}},
+			Children: []lsif.LSIFSymbol{
+				{Name: "addr", Kind: lsp.SKField, Range: lsp.Range{Start: lsp.Position{Line: 4, Character: 1}, End: lsp.Position{Line: 4, Character: 5}}},
+			},
+		},
+		{Name: "main", Kind: lsp.SKFunction, Range: lsp.Range{Start: lsp.Position{Line: 7, Character: 5}, End: lsp.Position{Line: 7, Character: 9}}},
+	}
+
+	resolvers := toPreciseSymbolResolvers(symbols, "", "cmd/server/main.go", "go", baseURI, nil)
+
+	type summary struct {
+		name, containerName, kind string
+		line, character           int
+	}
+	var actual []summary
+	for _, r := range resolvers {
+		containerName := ""
+		if c := r.ContainerName(); c != nil {
+			containerName = *c
+		}
+		actual = append(actual, summary{r.Name(), containerName, r.Kind(), r.Location().lspRange.Start.Line, r.Location().lspRange.Start.Character})
+	}
+
+	expected := []summary{
+		{"Server", "", "STRUCT", 3, 5},
+		{"addr", "Server", "FIELD", 4, 1},
+		{"main", "", "FUNCTION", 7, 5},
+	}
+	if len(actual) != len(expected) {
+		t.Fatalf("unexpected number of symbols. want=%d have=%d", len(expected), len(actual))
+	}
+	for i := range expected {
+		if actual[i] != expected[i] {
+			t.Errorf("unexpected symbol at index %d. want=%+v have=%+v", i, expected[i], actual[i])
+		}
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/repoaccesslog"
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/highlight"
 	"github.com/sourcegraph/sourcegraph/internal/markdown"
@@ -27,10 +28,24 @@ func (r *GitTreeEntryResolver) Content(ctx context.Context) (string, error) {
 	if err != nil {
 		return "", err
 	}
+	r.logAccess(ctx)
 
 	return string(contents), nil
 }
 
+// logAccess records that the contents of the file were read, if accesses to the repository are
+// logged. It is recorded at most once per resolver, even if multiple fields read the contents.
+func (r *GitTreeEntryResolver) logAccess(ctx context.Context) {
+	r.logAccessOnce.Do(func() {
+		repoaccesslog.Log(ctx, repoaccesslog.Entry{
+			Repo:     r.commit.repo.repo,
+			Kind:     repoaccesslog.KindBlob,
+			Path:     r.Path(),
+			CommitID: api.CommitID(r.commit.OID()),
+		})
+	})
+}
+
 func (r *GitTreeEntryResolver) RichHTML(ctx context.Context) (string, error) {
 	switch path.Ext(r.Path()) {
 	case ".md", ".mdown", ".markdown", ".markdn":
@@ -110,6 +125,7 @@ func (r *GitTreeEntryResolver) Highlight(ctx context.Context, args *struct {
 	if err != nil {
 		return nil, err
 	}
+	r.logAccess(ctx)
 
 	// Highlight the content.
 	var (
@@ -6,6 +6,7 @@ import (
 	neturl "net/url"
 	"os"
 	"path"
+	"sync"
 	"time"
 
 	"github.com/inconshreveable/log15"
@@ -42,6 +43,8 @@ type GitTreeEntryResolver struct {
 
 	isRecursive   bool  // whether entries is populated recursively (otherwise just current level of hierarchy)
 	isSingleChild *bool // whether this is the single entry in its parent. Only set by the (&GitTreeEntryResolver) entries.
+
+	logAccessOnce sync.Once
 }
 
 func NewGitTreeEntryResolver(commit *GitCommitResolver, stat os.FileInfo) *GitTreeEntryResolver {
@@ -232,13 +235,23 @@ func (r *GitTreeEntryResolver) IsSingleChild(ctx context.Context, args *gitTreeE
 	return len(entries) == 1, nil
 }
 
-func (r *GitTreeEntryResolver) LSIF(ctx context.Context) (LSIFQueryResolver, error) {
+type LSIFArgs struct {
+	SearchBasedFallback bool
+}
+
+func (r *GitTreeEntryResolver) LSIF(ctx context.Context, args *LSIFArgs) (LSIFQueryResolver, error) {
 	codeIntelRequests.WithLabelValues(trace.RequestOrigin(ctx)).Inc()
-	return EnterpriseResolvers.codeIntelResolver.LSIF(ctx, &LSIFQueryArgs{
+	lsifResolver, err := EnterpriseResolvers.codeIntelResolver.LSIF(ctx, &LSIFQueryArgs{
 		Repository: r.Repository(),
 		Commit:     api.CommitID(r.Commit().OID()),
 		Path:       r.Path(),
 	})
+	if err == codeIntelOnlyInEnterprise || (err == nil && lsifResolver == nil) {
+		if args.SearchBasedFallback {
+			return newSearchBasedQueryResolver(r.commit, r.Path()), nil
+		}
+	}
+	return lsifResolver, err
 }
 
 type fileInfo struct {
@@ -0,0 +1,34 @@
+package graphqlbackend
+
+import (
+	"context"
+	"math"
+
+	"github.com/sourcegraph/sourcegraph/internal/lfs"
+)
+
+type lfsResolver struct {
+	pointer *lfs.Pointer
+}
+
+func (r *lfsResolver) ByteSize() int32 {
+	if r.pointer.Size > math.MaxInt32 {
+		return math.MaxInt32
+	}
+	return int32(r.pointer.Size)
+}
+
+func (r *GitTreeEntryResolver) LFS(ctx context.Context) (*lfsResolver, error) {
+	// Pointer files are small, so we only read the contents of small blobs.
+	if r.stat.Mode().IsDir() || r.stat.Size() > lfs.MaxPointerSize {
+		return nil, nil
+	}
+	content, err := r.Content(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if pointer, ok := lfs.ParsePointer([]byte(content)); ok {
+		return &lfsResolver{pointer: pointer}, nil
+	}
+	return nil, nil
+}
@@ -437,6 +437,11 @@ func (r *NodeResolver) ToUser() (*UserResolver, bool) {
 	return n, ok
 }
 
+func (r *NodeResolver) ToUserSession() (*userSessionResolver, bool) {
+	n, ok := r.Node.(*userSessionResolver)
+	return n, ok
+}
+
 func (r *NodeResolver) ToOrg() (*OrgResolver, bool) {
 	n, ok := r.Node.(*OrgResolver)
 	return n, ok
@@ -474,6 +479,11 @@ func (r *NodeResolver) ToLSIFUpload() (LSIFUploadResolver, bool) {
 	return n, ok
 }
 
+func (r *NodeResolver) ToLSIFIndex() (LSIFIndexResolver, bool) {
+	n, ok := r.Node.(LSIFIndexResolver)
+	return n, ok
+}
+
 // schemaResolver handles all GraphQL queries for Sourcegraph. To do this, it
 // uses subresolvers which are globals. Enterprise-only resolvers are assigned
 // to a field of EnterpriseResolvers.
@@ -513,6 +523,8 @@ func (r *schemaResolver) nodeByID(ctx context.Context, id graphql.ID) (Node, err
 	switch relay.UnmarshalKind(id) {
 	case "AccessToken":
 		return accessTokenByID(ctx, id)
+	case "UserSession":
+		return userSessionByID(ctx, id)
 	case "Campaign":
 		return r.CampaignByID(ctx, id)
 	case "PatchSet":
@@ -555,6 +567,8 @@ func (r *schemaResolver) nodeByID(ctx context.Context, id graphql.ID) (Node, err
 		return siteByGQLID(ctx, id)
 	case "LSIFUpload":
 		return r.LSIFUploadByID(ctx, id)
+	case "LSIFIndex":
+		return r.LSIFIndexByID(ctx, id)
 	default:
 		return nil, errors.New("invalid id")
 	}
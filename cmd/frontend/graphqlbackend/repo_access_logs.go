@@ -0,0 +1,148 @@
+package graphqlbackend
+
+import (
+	"context"
+	"sync"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/graphqlutil"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+)
+
+func (r *schemaResolver) RepositoryAccessLogs(ctx context.Context, args *struct {
+	graphqlutil.ConnectionArgs
+	Repository *graphql.ID
+	User       *graphql.ID
+	Kind       *string
+}) (*repoAccessLogConnectionResolver, error) {
+	// 🚨 SECURITY: Only site admins can view the repository access log.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	var opt db.RepoAccessLogsListOptions
+	if args.Repository != nil {
+		repoID, err := UnmarshalRepositoryID(*args.Repository)
+		if err != nil {
+			return nil, err
+		}
+		opt.RepoID = repoID
+	}
+	if args.User != nil {
+		userID, err := UnmarshalUserID(*args.User)
+		if err != nil {
+			return nil, err
+		}
+		opt.UserID = userID
+	}
+	if args.Kind != nil {
+		opt.Kind = *args.Kind
+	}
+	args.ConnectionArgs.Set(&opt.LimitOffset)
+	return &repoAccessLogConnectionResolver{opt: opt}, nil
+}
+
+// repoAccessLogEntryResolver resolves an entry of the repository access log.
+type repoAccessLogEntryResolver struct {
+	entry db.RepoAccessLogEntry
+}
+
+func (r *repoAccessLogEntryResolver) RepositoryName() string { return string(r.entry.RepoName) }
+
+func (r *repoAccessLogEntryResolver) Repository(ctx context.Context) (*RepositoryResolver, error) {
+	repo, err := RepositoryByIDInt32(ctx, r.entry.RepoID)
+	if errcode.IsNotFound(err) {
+		// The repository was deleted after it was accessed.
+		return nil, nil
+	}
+	return repo, err
+}
+
+func (r *repoAccessLogEntryResolver) User(ctx context.Context) (*UserResolver, error) {
+	if r.entry.UserID == 0 {
+		return nil, nil
+	}
+	user, err := UserByIDInt32(ctx, r.entry.UserID)
+	if errcode.IsNotFound(err) {
+		// The user was deleted after accessing the repository.
+		return nil, nil
+	}
+	return user, err
+}
+
+func (r *repoAccessLogEntryResolver) Kind() string { return r.entry.Kind }
+
+func (r *repoAccessLogEntryResolver) Path() *string { return nullString(r.entry.Path) }
+
+func (r *repoAccessLogEntryResolver) Commit() *string { return nullString(string(r.entry.CommitID)) }
+
+func (r *repoAccessLogEntryResolver) Query() *string { return nullString(r.entry.Query) }
+
+func (r *repoAccessLogEntryResolver) IP() *string { return nullString(r.entry.IP) }
+
+func (r *repoAccessLogEntryResolver) CreatedAt() DateTime { return DateTime{Time: r.entry.CreatedAt} }
+
+func nullString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+// repoAccessLogConnectionResolver resolves a list of repository access log entries.
+//
+// 🚨 SECURITY: When instantiating a repoAccessLogConnectionResolver value, the caller MUST check
+// permissions.
+type repoAccessLogConnectionResolver struct {
+	opt db.RepoAccessLogsListOptions
+
+	// cache results because they are used by multiple fields
+	once    sync.Once
+	entries []*db.RepoAccessLogEntry
+	err     error
+}
+
+func (r *repoAccessLogConnectionResolver) compute(ctx context.Context) ([]*db.RepoAccessLogEntry, error) {
+	r.once.Do(func() {
+		opt2 := r.opt
+		if opt2.LimitOffset != nil {
+			tmp := *opt2.LimitOffset
+			opt2.LimitOffset = &tmp
+			opt2.Limit++ // so we can detect if there is a next page
+		}
+
+		r.entries, r.err = db.RepoAccessLogs.List(ctx, opt2)
+	})
+	return r.entries, r.err
+}
+
+func (r *repoAccessLogConnectionResolver) Nodes(ctx context.Context) ([]*repoAccessLogEntryResolver, error) {
+	entries, err := r.compute(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if r.opt.LimitOffset != nil && len(entries) > r.opt.LimitOffset.Limit {
+		entries = entries[:r.opt.LimitOffset.Limit]
+	}
+
+	var l []*repoAccessLogEntryResolver
+	for _, entry := range entries {
+		l = append(l, &repoAccessLogEntryResolver{entry: *entry})
+	}
+	return l, nil
+}
+
+func (r *repoAccessLogConnectionResolver) TotalCount(ctx context.Context) (int32, error) {
+	count, err := db.RepoAccessLogs.Count(ctx, r.opt)
+	return int32(count), err
+}
+
+func (r *repoAccessLogConnectionResolver) PageInfo(ctx context.Context) (*graphqlutil.PageInfo, error) {
+	entries, err := r.compute(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return graphqlutil.HasNextPage(r.opt.LimitOffset != nil && len(entries) > r.opt.Limit), nil
+}
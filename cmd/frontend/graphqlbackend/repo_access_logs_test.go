@@ -0,0 +1,107 @@
+package graphqlbackend
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/gqltesting"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/graphqlutil"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+)
+
+type repoDeletedErr struct{}
+
+func (repoDeletedErr) Error() string  { return "repo not found" }
+func (repoDeletedErr) NotFound() bool { return true }
+
+func TestRepositoryAccessLogs(t *testing.T) {
+	resetMocks()
+	db.Mocks.Users.GetByCurrentAuthUser = func(context.Context) (*types.User, error) {
+		return &types.User{SiteAdmin: true}, nil
+	}
+	db.Mocks.Repos.Get = func(ctx context.Context, id api.RepoID) (*types.Repo, error) {
+		return nil, repoDeletedErr{}
+	}
+	db.Mocks.RepoAccessLogs.List = func(opt db.RepoAccessLogsListOptions) ([]*db.RepoAccessLogEntry, error) {
+		if want := "raw"; opt.Kind != want {
+			t.Errorf("got kind %q, want %q", opt.Kind, want)
+		}
+		return []*db.RepoAccessLogEntry{
+			{RepoID: 1, RepoName: "github.com/a/b", Kind: "raw", Path: "/README", CommitID: "c", IP: "1.2.3.4", CreatedAt: time.Date(2019, 1, 2, 3, 4, 5, 0, time.UTC)},
+		}, nil
+	}
+	db.Mocks.RepoAccessLogs.Count = func(db.RepoAccessLogsListOptions) (int, error) { return 1, nil }
+
+	gqltesting.RunTests(t, []*gqltesting.Test{
+		{
+			Schema: mustParseGraphQLSchema(t),
+			Query: `
+				{
+					repositoryAccessLogs(kind: "raw") {
+						nodes {
+							repositoryName
+							repository { name }
+							user { username }
+							kind
+							path
+							commit
+							query
+							ip
+							createdAt
+						}
+						totalCount
+					}
+				}
+			`,
+			ExpectedResult: `
+				{
+					"repositoryAccessLogs": {
+						"nodes": [
+							{
+								"repositoryName": "github.com/a/b",
+								"repository": null,
+								"user": null,
+								"kind": "raw",
+								"path": "/README",
+								"commit": "c",
+								"query": null,
+								"ip": "1.2.3.4",
+								"createdAt": "2019-01-02T03:04:05Z"
+							}
+						],
+						"totalCount": 1
+					}
+				}
+			`,
+		},
+	})
+}
+
+// 🚨 SECURITY: This tests that non-site-admins can't view the repository access log.
+func TestRepositoryAccessLogs_nonSiteAdmin(t *testing.T) {
+	resetMocks()
+	db.Mocks.Users.GetByCurrentAuthUser = func(context.Context) (*types.User, error) {
+		return &types.User{ID: 1}, nil
+	}
+	db.Mocks.RepoAccessLogs.List = func(db.RepoAccessLogsListOptions) ([]*db.RepoAccessLogEntry, error) {
+		t.Fatal("List should not be called")
+		return nil, nil
+	}
+
+	ctx := actor.WithActor(context.Background(), &actor.Actor{UID: 1})
+	_, err := (&schemaResolver{}).RepositoryAccessLogs(ctx, &struct {
+		graphqlutil.ConnectionArgs
+		Repository *graphql.ID
+		User       *graphql.ID
+		Kind       *string
+	}{})
+	if err != backend.ErrMustBeSiteAdmin {
+		t.Errorf("got error %v, want %v", err, backend.ErrMustBeSiteAdmin)
+	}
+}
@@ -0,0 +1,159 @@
+package graphqlbackend
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/graphqlutil"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
+)
+
+func (r *schemaResolver) GitserverShards(ctx context.Context) ([]*gitserverShardResolver, error) {
+	// 🚨 SECURITY: Only site admins can view the disk usage of gitserver.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	usage, err := gitserver.DefaultClient.DiskUsage(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	shards := make([]*gitserverShardResolver, 0, len(usage))
+	for addr, u := range usage {
+		shards = append(shards, &gitserverShardResolver{addr: addr, usage: u})
+	}
+	sort.Slice(shards, func(i, j int) bool { return shards[i].addr < shards[j].addr })
+	return shards, nil
+}
+
+// gitserverShardResolver resolves the disk usage of a gitserver shard.
+type gitserverShardResolver struct {
+	addr  string
+	usage *protocol.DiskUsageResponse
+}
+
+func (r *gitserverShardResolver) Address() string { return r.addr }
+
+func (r *gitserverShardResolver) TotalBytes() float64 { return float64(r.usage.DiskSizeBytes) }
+
+func (r *gitserverShardResolver) FreeBytes() float64 { return float64(r.usage.FreeBytes) }
+
+func (r *gitserverShardResolver) RepositoriesBytes() float64 {
+	var total int64
+	for _, size := range r.usage.Repos {
+		total += size
+	}
+	return float64(total)
+}
+
+func (r *gitserverShardResolver) RepositoriesCount() int32 { return int32(len(r.usage.Repos)) }
+
+func (r *schemaResolver) RepositoryDiskUsage(ctx context.Context, args *struct {
+	graphqlutil.ConnectionArgs
+	Repository *graphql.ID
+	Shard      *string
+}) (*repoDiskUsageConnectionResolver, error) {
+	// 🚨 SECURITY: Only site admins can view the disk usage of repositories.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	var opt db.RepoDiskUsageListOptions
+	if args.Repository != nil {
+		repoID, err := UnmarshalRepositoryID(*args.Repository)
+		if err != nil {
+			return nil, err
+		}
+		opt.RepoID = repoID
+	}
+	if args.Shard != nil {
+		opt.Shard = *args.Shard
+	}
+	args.ConnectionArgs.Set(&opt.LimitOffset)
+	return &repoDiskUsageConnectionResolver{opt: opt}, nil
+}
+
+// repoDiskUsageResolver resolves the size of a repository on a gitserver shard.
+type repoDiskUsageResolver struct {
+	usage db.RepoDiskUsageRecord
+}
+
+func (r *repoDiskUsageResolver) RepositoryName() string { return string(r.usage.RepoName) }
+
+func (r *repoDiskUsageResolver) Repository(ctx context.Context) (*RepositoryResolver, error) {
+	repo, err := RepositoryByIDInt32(ctx, r.usage.RepoID)
+	if errcode.IsNotFound(err) {
+		// The repository was deleted after its size was recorded.
+		return nil, nil
+	}
+	return repo, err
+}
+
+func (r *repoDiskUsageResolver) Shard() string { return r.usage.Shard }
+
+func (r *repoDiskUsageResolver) SizeBytes() float64 { return float64(r.usage.SizeBytes) }
+
+func (r *repoDiskUsageResolver) UpdatedAt() DateTime { return DateTime{Time: r.usage.UpdatedAt} }
+
+// repoDiskUsageConnectionResolver resolves a list of repository disk usage entries.
+//
+// 🚨 SECURITY: When instantiating a repoDiskUsageConnectionResolver value, the caller MUST check
+// permissions.
+type repoDiskUsageConnectionResolver struct {
+	opt db.RepoDiskUsageListOptions
+
+	// cache results because they are used by multiple fields
+	once  sync.Once
+	usage []*db.RepoDiskUsageRecord
+	err   error
+}
+
+func (r *repoDiskUsageConnectionResolver) compute(ctx context.Context) ([]*db.RepoDiskUsageRecord, error) {
+	r.once.Do(func() {
+		opt2 := r.opt
+		if opt2.LimitOffset != nil {
+			tmp := *opt2.LimitOffset
+			opt2.LimitOffset = &tmp
+			opt2.Limit++ // so we can detect if there is a next page
+		}
+
+		r.usage, r.err = db.RepoDiskUsage.List(ctx, opt2)
+	})
+	return r.usage, r.err
+}
+
+func (r *repoDiskUsageConnectionResolver) Nodes(ctx context.Context) ([]*repoDiskUsageResolver, error) {
+	usage, err := r.compute(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if r.opt.LimitOffset != nil && len(usage) > r.opt.LimitOffset.Limit {
+		usage = usage[:r.opt.LimitOffset.Limit]
+	}
+
+	var l []*repoDiskUsageResolver
+	for _, u := range usage {
+		l = append(l, &repoDiskUsageResolver{usage: *u})
+	}
+	return l, nil
+}
+
+func (r *repoDiskUsageConnectionResolver) TotalCount(ctx context.Context) (int32, error) {
+	count, err := db.RepoDiskUsage.Count(ctx, r.opt)
+	return int32(count), err
+}
+
+func (r *repoDiskUsageConnectionResolver) PageInfo(ctx context.Context) (*graphqlutil.PageInfo, error) {
+	usage, err := r.compute(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return graphqlutil.HasNextPage(r.opt.LimitOffset != nil && len(usage) > r.opt.Limit), nil
+}
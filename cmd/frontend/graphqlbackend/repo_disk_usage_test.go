@@ -0,0 +1,96 @@
+package graphqlbackend
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/gqltesting"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/graphqlutil"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+)
+
+func TestRepositoryDiskUsage(t *testing.T) {
+	resetMocks()
+	db.Mocks.Users.GetByCurrentAuthUser = func(context.Context) (*types.User, error) {
+		return &types.User{SiteAdmin: true}, nil
+	}
+	db.Mocks.Repos.Get = func(ctx context.Context, id api.RepoID) (*types.Repo, error) {
+		return nil, repoDeletedErr{}
+	}
+	db.Mocks.RepoDiskUsage.List = func(opt db.RepoDiskUsageListOptions) ([]*db.RepoDiskUsageRecord, error) {
+		if want := "gitserver-0"; opt.Shard != want {
+			t.Errorf("got shard %q, want %q", opt.Shard, want)
+		}
+		return []*db.RepoDiskUsageRecord{
+			{RepoID: 1, RepoName: "github.com/a/b", Shard: "gitserver-0", SizeBytes: 3 << 20, UpdatedAt: time.Date(2019, 1, 2, 3, 4, 5, 0, time.UTC)},
+		}, nil
+	}
+	db.Mocks.RepoDiskUsage.Count = func(db.RepoDiskUsageListOptions) (int, error) { return 1, nil }
+
+	gqltesting.RunTests(t, []*gqltesting.Test{
+		{
+			Schema: mustParseGraphQLSchema(t),
+			Query: `
+				{
+					repositoryDiskUsage(shard: "gitserver-0") {
+						nodes {
+							repositoryName
+							repository { name }
+							shard
+							sizeBytes
+							updatedAt
+						}
+						totalCount
+					}
+				}
+			`,
+			ExpectedResult: `
+				{
+					"repositoryDiskUsage": {
+						"nodes": [
+							{
+								"repositoryName": "github.com/a/b",
+								"repository": null,
+								"shard": "gitserver-0",
+								"sizeBytes": 3145728,
+								"updatedAt": "2019-01-02T03:04:05Z"
+							}
+						],
+						"totalCount": 1
+					}
+				}
+			`,
+		},
+	})
+}
+
+// 🚨 SECURITY: This tests that non-site-admins can't view the disk usage of repositories.
+func TestRepositoryDiskUsage_nonSiteAdmin(t *testing.T) {
+	resetMocks()
+	db.Mocks.Users.GetByCurrentAuthUser = func(context.Context) (*types.User, error) {
+		return &types.User{ID: 1}, nil
+	}
+	db.Mocks.RepoDiskUsage.List = func(db.RepoDiskUsageListOptions) ([]*db.RepoDiskUsageRecord, error) {
+		t.Fatal("List should not be called")
+		return nil, nil
+	}
+
+	ctx := actor.WithActor(context.Background(), &actor.Actor{UID: 1})
+	_, err := (&schemaResolver{}).RepositoryDiskUsage(ctx, &struct {
+		graphqlutil.ConnectionArgs
+		Repository *graphql.ID
+		Shard      *string
+	}{})
+	if err != backend.ErrMustBeSiteAdmin {
+		t.Errorf("got error %v, want %v", err, backend.ErrMustBeSiteAdmin)
+	}
+	if _, err := (&schemaResolver{}).GitserverShards(ctx); err != backend.ErrMustBeSiteAdmin {
+		t.Errorf("got error %v, want %v", err, backend.ErrMustBeSiteAdmin)
+	}
+}
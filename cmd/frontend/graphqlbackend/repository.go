@@ -296,6 +296,24 @@ func (r *RepositoryResolver) LSIFUploads(ctx context.Context, args *LSIFUploadsQ
 	})
 }
 
+func (r *RepositoryResolver) LSIFRetentionReport(ctx context.Context) ([]LSIFUploadRetentionResolver, error) {
+	return EnterpriseResolvers.codeIntelResolver.LSIFRetention(ctx, r.ID())
+}
+
+func (r *RepositoryResolver) LSIFCoverage(ctx context.Context, args *LSIFCoverageArgs) (LSIFCoverageResolver, error) {
+	return EnterpriseResolvers.codeIntelResolver.LSIFCoverage(ctx, &LSIFRepositoryCoverageQueryArgs{
+		LSIFCoverageArgs: args,
+		Repository:       r,
+	})
+}
+
+func (r *RepositoryResolver) LSIFIndexes(ctx context.Context, args *LSIFIndexesQueryArgs) (LSIFIndexConnectionResolver, error) {
+	return EnterpriseResolvers.codeIntelResolver.LSIFIndexes(ctx, &LSIFRepositoryIndexesQueryArgs{
+		LSIFIndexesQueryArgs: args,
+		RepositoryID:         r.ID(),
+	})
+}
+
 type AuthorizedUserArgs struct {
 	RepositoryID graphql.ID
 	Perm         string
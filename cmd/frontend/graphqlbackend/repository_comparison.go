@@ -6,7 +6,6 @@ import (
 	"encoding/hex"
 	"fmt"
 	"io"
-	"strings"
 	"sync"
 
 	"github.com/sourcegraph/go-diff/diff"
@@ -162,34 +161,25 @@ type fileDiffConnectionResolver struct {
 
 func (r *fileDiffConnectionResolver) compute(ctx context.Context) ([]*diff.FileDiff, error) {
 	do := func() ([]*diff.FileDiff, error) {
-		var rangeSpec string
-		hOid := r.cmp.head.OID()
+		opt := git.DiffOptions{
+			Head:             string(r.cmp.head.OID()),
+			FindRenames:      true,
+			FullIndex:        true,
+			InterHunkContext: 3,
+			NoPrefix:         true,
+		}
 		if r.cmp.base == nil {
 			// Rare case: the base is the empty tree, in which case we need ".." not "..." because the latter only works for commits.
-			rangeSpec = string(r.cmp.baseRevspec) + ".." + string(hOid)
+			opt.Base = string(r.cmp.baseRevspec)
 		} else {
-			rangeSpec = string(r.cmp.base.OID()) + "..." + string(hOid)
-		}
-		if strings.HasPrefix(rangeSpec, "-") || strings.HasPrefix(rangeSpec, ".") {
-			// This should not be possible since r.head is a SHA returned by ResolveRevision, but be
-			// extra careful to avoid letting user input add additional `git diff` command-line
-			// flags or refer to a file.
-			return nil, fmt.Errorf("invalid diff range argument: %q", rangeSpec)
+			opt.Base = string(r.cmp.base.OID())
+			opt.MergeBase = true
 		}
 		cachedRepo, err := backend.CachedGitRepo(ctx, r.cmp.repo.repo)
 		if err != nil {
 			return nil, err
 		}
-		rdr, err := git.ExecReader(ctx, *cachedRepo, []string{
-			"diff",
-			"--find-renames",
-			"--find-copies",
-			"--full-index",
-			"--inter-hunk-context=3",
-			"--no-prefix",
-			rangeSpec,
-			"--",
-		})
+		rdr, err := git.ReadDiff(ctx, *cachedRepo, opt)
 		if err != nil {
 			return nil, err
 		}
@@ -145,6 +145,18 @@ func (r *updateScheduleResolver) Total() int32 {
 	return int32(r.schedule.Total)
 }
 
+func (r *updateScheduleResolver) LastFetched() *DateTime {
+	return DateTimeOrNil(r.schedule.LastFetched)
+}
+
+func (r *updateScheduleResolver) LastError() *string {
+	return nullString(r.schedule.LastError)
+}
+
+func (r *updateScheduleResolver) ConsecutiveFailures() int32 {
+	return int32(r.schedule.ConsecutiveFailures)
+}
+
 func (r *repositoryMirrorInfoResolver) UpdateQueue(ctx context.Context) (*updateQueueResolver, error) {
 	info, err := r.repoUpdateSchedulerInfo(ctx)
 	if err != nil {
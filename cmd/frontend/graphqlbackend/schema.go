@@ -194,6 +194,22 @@ type Mutation {
     deleteUser(user: ID!, hard: Boolean): EmptyResponse
     # Updates the current user's password. The oldPassword arg must match the user's current password.
     updatePassword(oldPassword: String!, newPassword: String!): EmptyResponse
+    # Begins setting up two-factor authentication (TOTP) for the current user, who must sign in with a username and
+    # password. The returned secret must be added to an authenticator app, and the setup must be completed with
+    # Mutation.confirmTwoFactorEnrollment. Calling this again replaces a setup that was not completed.
+    beginTwoFactorEnrollment: TwoFactorEnrollment!
+    # Completes setting up two-factor authentication for the current user. The code must be generated by the
+    # authenticator app from the secret returned by Mutation.beginTwoFactorEnrollment. The result is the list of
+    # recovery codes, which can each be used once in place of a code. They are only returned once.
+    confirmTwoFactorEnrollment(code: String!): [String!]!
+    # Replaces the current user's two-factor authentication recovery codes with new ones and returns them. The code
+    # must be a valid two-factor authentication code (or recovery code).
+    regenerateTwoFactorRecoveryCodes(code: String!): [String!]!
+    # Disables two-factor authentication for the user.
+    #
+    # Only the user and site admins may perform this mutation. The user must provide a valid two-factor
+    # authentication code (or recovery code); site admins disabling it for another user need not.
+    disableTwoFactor(user: ID!, code: String): EmptyResponse!
     # Creates an access token that grants the privileges of the specified user (referred to as the access token's
     # "subject" user after token creation). The result is the access token value, which the caller is responsible
     # for storing (it is not accessible by Sourcegraph after creation).
@@ -211,6 +227,14 @@ type Mutation {
     #
     # Only site admins or the user who owns the token may perform this mutation.
     deleteAccessToken(byID: ID, byToken: String): EmptyResponse!
+    # Revokes the specified session of a user, signing out the browser that holds it.
+    #
+    # Only site admins or the user who owns the session may perform this mutation.
+    revokeUserSession(userSession: ID!): EmptyResponse!
+    # Revokes all sessions of the user, signing the user out everywhere.
+    #
+    # Only site admins or the user may perform this mutation.
+    revokeAllUserSessions(user: ID!): EmptyResponse!
     # Deletes the association between an external account and its Sourcegraph user. It does NOT delete the external
     # account on the external service where it resides.
     #
@@ -407,6 +431,16 @@ type Mutation {
         # The level of repository permission.
         perm: RepositoryPermission = READ
     ): EmptyResponse!
+    # Schedule a permissions sync for the given user in high priority. It does not wait
+    # for the sync to complete.
+    #
+    # Only site admins may perform this mutation.
+    scheduleUserPermissionsSync(user: ID!): EmptyResponse!
+    # Schedule a permissions sync for the given repository in high priority. It does not
+    # wait for the sync to complete.
+    #
+    # Only site admins may perform this mutation.
+    scheduleRepositoryPermissionsSync(repository: ID!): EmptyResponse!
 }
 
 # A patch to apply to a repository (in a new branch) when a campaign is created
@@ -1068,6 +1102,19 @@ type Query {
         # Sort direction.
         descending: Boolean = false
     ): RepositoryConnection!
+    # (experimental) The LSIF API may change substantially in the near future as we
+    # continue to adjust it for our use cases. Changes will not be documented in the
+    # CHANGELOG during this time.
+    # The precise code intelligence coverage of repositories, ordered by name. Only site
+    # admins may perform this query.
+    lsifRepositoryCoverage(
+        # Returns the first n repositories from the list.
+        first: Int
+        # Opaque pagination cursor.
+        after: String
+        # Return repositories whose names match the query.
+        query: String
+    ): LSIFRepositoryCoverageConnection!
     # Looks up a Phabricator repository by name.
     phabricatorRepo(
         # The name, for example "github.com/gorilla/mux".
@@ -1163,6 +1210,36 @@ type Query {
         # Returns the first n survey responses from the list.
         first: Int
     ): SurveyResponseConnection!
+    # Lists accesses to the code of repositories whose accesses are logged (per the repoAccessLog site
+    # configuration property), most recent first.
+    #
+    # Only site admins may perform this query.
+    repositoryAccessLogs(
+        # Returns the first n entries from the list.
+        first: Int
+        # Only list accesses to this repository.
+        repository: ID
+        # Only list accesses by this user.
+        user: ID
+        # Only list accesses of this kind ("blob", "raw", "archive", "search" or "git-clone").
+        kind: String
+    ): RepositoryAccessLogConnection!
+    # The disk usage of the gitserver shards, as currently reported by each shard.
+    #
+    # Only site admins may perform this query.
+    gitserverShards: [GitserverShard!]!
+    # Lists the disk usage of repositories on the gitserver shards, largest first. The sizes are
+    # recorded periodically, so they may be out of date.
+    #
+    # Only site admins may perform this query.
+    repositoryDiskUsage(
+        # Returns the first n entries from the list.
+        first: Int
+        # Only list the disk usage of this repository.
+        repository: ID
+        # Only list the disk usage on the gitserver shard with this address.
+        shard: String
+    ): RepositoryDiskUsageConnection!
     # The extension registry.
     extensionRegistry: ExtensionRegistry!
     # Queries that are only used on Sourcegraph.com.
@@ -1195,6 +1272,17 @@ type Query {
     # Returns a list of usernames or emails that have associated pending permissions.
     # The returned list can be used to query authorizedUserRepositories for pending permissions.
     usersWithPendingPermissions: [String!]!
+
+    # Explains what permissions data is used to decide whether the user can access the
+    # repository. It is meant for debugging repository permissions.
+    #
+    # Only site admins may perform this query.
+    authorizationExplain(
+        # The user.
+        user: ID!
+        # The repository.
+        repository: ID!
+    ): AuthorizationExplanation!
 }
 
 # The version of the search syntax.
@@ -1210,6 +1298,10 @@ enum SearchPatternType {
     literal
     regexp
     structural
+    # Secret detection search. The search pattern is a list of secret detection rule IDs to
+    # apply (or empty to apply all rules), and file contents are searched for secrets such as
+    # credentials. The secrets in the results' previews are masked.
+    secrets
 }
 
 # Configuration details for the browser extension, editor extensions, etc.
@@ -1345,6 +1437,10 @@ type SearchResults {
     # missing OR you may simply continue making further paginated requests and choose to skip
     # the missing repositories.
     missing: [Repository!]!
+    # Repositories whose commits could not be searched because only part of
+    # their history is cloned (see the cloneStrategies setting of code host
+    # connections).
+    historyUnavailable: [Repository!]!
     # Repositories or commits which we did not manage to search in time. Trying
     # again usually will work.
     #
@@ -1542,6 +1638,7 @@ enum ExternalServiceKind {
     GITLAB
     GITOLITE
     PHABRICATOR
+    PERFORCE
     OTHER
 }
 
@@ -1744,6 +1841,48 @@ type Repository implements Node & GenericSearchResultInterface {
         after: String
     ): LSIFUploadConnection!
 
+    # (experimental) The LSIF API may change substantially in the near future as we
+    # continue to adjust it for our use cases. Changes will not be documented in the
+    # CHANGELOG during this time.
+    # The repository's LSIF index jobs, which are run when the repository is configured
+    # for auto-indexing (in the "codeIntelAutoIndexing" site configuration property).
+    lsifIndexes(
+        # The state of returned index jobs.
+        state: LSIFIndexState
+
+        # When specified, indicates that this request should be paginated and
+        # the first N results (relative to the cursor) should be returned. i.e.
+        # how many results to return per page. It must be in the range of 0-5000.
+        first: Int
+
+        # When specified, indicates that this request should be paginated and
+        # to fetch results starting at this cursor.
+        #
+        # A future request can be made for more results by passing in the
+        # 'LSIFIndexConnection.pageInfo.endCursor' that is returned.
+        after: String
+    ): LSIFIndexConnection!
+
+    # (experimental) The LSIF API may change substantially in the near future as we
+    # continue to adjust it for our use cases. Changes will not be documented in the
+    # CHANGELOG during this time.
+    # How the retention policies (in the "codeIntelRetention" site configuration property)
+    # apply to each completed LSIF upload of the repository, from the most to the least
+    # recently uploaded. Nothing is deleted by this query. Only site admins may run it.
+    lsifRetentionReport: [LSIFUploadRetention!]!
+
+    # (experimental) The LSIF API may change substantially in the near future as we
+    # continue to adjust it for our use cases. Changes will not be documented in the
+    # CHANGELOG during this time.
+    # Where precise code intelligence is available at the tip of the repository's default
+    # branch. The languages of the given directory and of each of its subdirectories are
+    # matched with the LSIF uploads visible at the tip whose indexer handles the language.
+    # Null if the repository is empty.
+    lsifCoverage(
+        # The path of the directory, relative to the root of the repository.
+        path: String = ""
+    ): LSIFCoverage
+
     # A list of authorized users to access this repository with the given permission.
     # This API currently only returns permissions from the Sourcegraph provider, i.e.
     # "permissions.userMapping" in site configuration.
@@ -1772,6 +1911,51 @@ type PermissionsInfo {
     updatedAt: DateTime!
 }
 
+# Explanation of the permissions data used to decide whether a user can access a repository.
+type AuthorizationExplanation {
+    # The user.
+    user: User!
+    # The repository.
+    repository: Repository!
+    # Whether the repository is public on the code host.
+    public: Boolean!
+    # Whether the repository is accessible to everyone because no authorization provider
+    # applies to it and "authz.allowByDefault" is enabled.
+    unrestricted: Boolean!
+    # The authorization providers that apply to the repository, along with the external
+    # account of the user that is used for each of them.
+    providers: [AuthorizationProviderExplanation!]!
+    # The user-centric permissions of the user, null if they were never synced.
+    userPermissions: AuthorizationPermissionsExplanation
+    # The repository-centric permissions of the repository, null if they were never synced.
+    repositoryPermissions: AuthorizationPermissionsExplanation
+    # The bind IDs of the user that have pending permissions to the repository.
+    pendingBindIDs: [String!]!
+}
+
+# An authorization provider that applies to a repository.
+type AuthorizationProviderExplanation {
+    # The type of the external service (e.g. "gitlab") of the authorization provider.
+    serviceType: String!
+    # The identifier of the external service of the authorization provider.
+    serviceID: String!
+    # The identifier of the external account of the user on the external service, null
+    # if the user has no such external account.
+    accountID: String
+}
+
+# Permissions information of a user or a repository as part of an authorization explanation.
+type AuthorizationPermissionsExplanation {
+    # The permission levels.
+    permissions: [RepositoryPermission!]!
+    # Whether the stored permissions grant the user access to the repository.
+    grantsAccess: Boolean!
+    # The last complete synced time. It is null when the complete sync never happened.
+    syncedAt: DateTime
+    # The last updated time of permissions.
+    updatedAt: DateTime!
+}
+
 # A reference to another Sourcegraph instance.
 type Redirect {
     # The URL of the other Sourcegraph instance.
@@ -1825,6 +2009,13 @@ type UpdateSchedule {
     index: Int!
     # The total number of repos in the schedule.
     total: Int!
+    # The last time that the repository was fetched from the code host, if known.
+    lastFetched: DateTime
+    # The error of the last update of the repository, if it failed.
+    lastError: String
+    # The number of consecutive updates of the repository that failed. Repositories whose updates keep
+    # failing are updated less frequently.
+    consecutiveFailures: Int!
 }
 
 # The state of a repository in the update queue.
@@ -2426,6 +2617,12 @@ type Submodule {
     path: String!
 }
 
+# Git LFS metadata of a file tracked by Git LFS.
+type LFS {
+    # The size of the file contents in bytes, capped at 2^31-1.
+    byteSize: Int!
+}
+
 # A file, directory, or other tree entry.
 interface TreeEntry {
     # The full path (relative to the repository root) of this tree entry.
@@ -2614,6 +2811,9 @@ type GitBlob implements TreeEntry & File2 {
     highlight(disableTimeout: Boolean!, isLightTheme: Boolean!, highlightLongLines: Boolean = false): HighlightedFile!
     # Submodule metadata if this tree points to a submodule
     submodule: Submodule
+    # Git LFS metadata if this blob is a Git LFS pointer file, which is stored in place of the
+    # contents of a file tracked by Git LFS. The content of such a blob is the pointer file.
+    lfs: LFS
     # Symbols defined in this blob.
     symbols(
         # Returns the first n symbols from the list.
@@ -2621,6 +2821,14 @@ type GitBlob implements TreeEntry & File2 {
         # Return symbols matching the query.
         query: String
     ): SymbolConnection!
+    # The outline of this blob: the symbols it defines, in document order. Each symbol is followed by
+    # the symbols it contains, whose containerName is the name of the enclosing symbol. Symbols come
+    # from precise code intelligence (LSIF) data when it is available for this blob, and from the
+    # symbols service otherwise.
+    documentSymbols(
+        # Returns the first n symbols from the list.
+        first: Int
+    ): SymbolConnection!
     # Always false, since a blob is a file, not directory.
     isSingleChild(
         # Returns the first n files in the tree.
@@ -2635,13 +2843,23 @@ type GitBlob implements TreeEntry & File2 {
     # continue to adjust it for our use cases. Changes will not be documented in the
     # CHANGELOG during this time.
     # A wrapper around LSIF query methods. If no LSIF upload can be used to answer code
-    # intelligence queries for this path-at-revision, this resolves to null.
-    lsif: LSIFQueryResolver
+    # intelligence queries for this path-at-revision, this resolves to null unless the
+    # search-based fallback is requested.
+    lsif(
+        # When no LSIF upload covers this path-at-revision, answer queries with search-based
+        # heuristics instead of resolving to null. Such results have precise set to false.
+        searchBasedFallback: Boolean = false
+    ): LSIFQueryResolver
 }
 
 # A wrapper object around LSIF query methods for a particular path-at-revision. When this node is
 # null, no LSIF data is available for containing git blob.
 type LSIFQueryResolver {
+    # Whether the results come from LSIF uploads. When false, the results are search-based
+    # heuristics: definitions are symbols with the same name as the one under the position,
+    # references are word matches of that name, and hovers are not available.
+    precise: Boolean!
+
     # (experimental) The LSIF API may change substantially in the near future as we
     # continue to adjust it for our use cases. Changes will not be documented in the
     # CHANGELOG during this time.
@@ -2689,6 +2907,43 @@ type LSIFQueryResolver {
         # The character (not byte) of the start line on which the symbol occurs (zero-based, inclusive).
         character: Int!
     ): Hover
+
+    # (experimental) The LSIF API may change substantially in the near future as we
+    # continue to adjust it for our use cases. Changes will not be documented in the
+    # CHANGELOG during this time.
+    # The hover, definitions, and number of references of many ranges of the document at once,
+    # for editors that decorate an entire file. Each result is the innermost range containing one
+    # of the given positions; positions outside of any range are skipped, and positions within the
+    # same range are answered once. Always empty for search-based results.
+    ranges(
+        # The positions to look up. If omitted, every range of the document is returned.
+        positions: [LSIFPositionInput!]
+    ): [CodeIntelligenceRange!]!
+}
+
+# A zero-based position inside a document.
+input LSIFPositionInput {
+    # The line of the position (zero-based).
+    line: Int!
+
+    # The character (not byte) of the position in the line (zero-based).
+    character: Int!
+}
+
+# The code intelligence of a range of a document.
+type CodeIntelligenceRange {
+    # The range.
+    range: Range!
+
+    # The hover result of the symbol in the range.
+    hover: Hover
+
+    # The definitions of the symbol in the range.
+    definitions: LocationConnection!
+
+    # The number of references to the symbol in the range from the LSIF upload of the document.
+    # References from other uploads are not counted.
+    referenceCount: Int!
 }
 
 # A highlighted file.
@@ -2732,6 +2987,9 @@ type LineMatch {
     offsetAndLengths: [[Int!]!]!
     # Whether or not the limit was hit.
     limitHit: Boolean!
+    # For secret detection searches (patternType:secrets), the IDs of the secret detection rules
+    # that matched, in the same order as offsetAndLengths. Null for other searches.
+    secretRules: [String!]
 }
 
 # A hunk.
@@ -2847,6 +3105,17 @@ type User implements Node & SettingsSubject & Namespace {
         # Returns the first n access tokens from the list.
         first: Int
     ): AccessTokenConnection!
+    # The user's active (signed-in) sessions, most recently active first.
+    #
+    # Only the user and site admins can access this field.
+    sessions(
+        # Returns the first n sessions from the list.
+        first: Int
+    ): UserSessionConnection!
+    # Whether the user has set up two-factor authentication for signing in with a username and password.
+    #
+    # Only the user and site admins can access this field.
+    twoFactorEnabled: Boolean!
     # A list of external accounts that are associated with the user.
     externalAccounts(
         # Returns the first n external accounts from the list.
@@ -2914,6 +3183,116 @@ type AccessTokenConnection {
     pageInfo: PageInfo!
 }
 
+# A pending two-factor authentication (TOTP) setup.
+type TwoFactorEnrollment {
+    # The base32-encoded secret, which can be entered into an authenticator app manually.
+    secret: String!
+    # The otpauth:// URI of the secret, which authenticator apps can import (usually by scanning it as a QR code).
+    keyURI: String!
+}
+
+# A signed-in session of a user.
+type UserSession implements Node {
+    # The unique ID for the session.
+    id: ID!
+    # The user who owns the session.
+    user: User!
+    # The IP address from which the session was established.
+    ip: String!
+    # The user agent of the browser that established the session.
+    userAgent: String!
+    # A short description of the device (operating system) derived from the user agent.
+    device: String!
+    # The date when the session was established.
+    createdAt: DateTime!
+    # The date when the session was last used.
+    lastActiveAt: DateTime!
+}
+
+# A list of user sessions.
+type UserSessionConnection {
+    # A list of user sessions.
+    nodes: [UserSession!]!
+    # The total count of user sessions in the connection. This total count may be larger than the number of nodes
+    # in this object when the result is paginated.
+    totalCount: Int!
+    # Pagination information.
+    pageInfo: PageInfo!
+}
+
+# An access to the code of a repository whose accesses are logged.
+type RepositoryAccessLogEntry {
+    # The name of the repository at the time of the access.
+    repositoryName: String!
+    # The repository, or null if it was deleted.
+    repository: Repository
+    # The user who accessed the code, or null for anonymous users, internal services and deleted
+    # users.
+    user: User
+    # The kind of access ("blob", "raw", "archive", "search" or "git-clone").
+    kind: String!
+    # The path of the file or directory that was accessed, if any.
+    path: String
+    # The commit ID that was accessed, if known.
+    commit: String
+    # The search query whose results included the repository, for "search" accesses.
+    query: String
+    # The IP address from which the code was accessed, if known.
+    ip: String
+    # The date and time of the access.
+    createdAt: DateTime!
+}
+
+# A list of repository access log entries.
+type RepositoryAccessLogConnection {
+    # A list of repository access log entries.
+    nodes: [RepositoryAccessLogEntry!]!
+    # The total count of entries in the connection. This total count may be larger than the number of nodes
+    # in this object when the result is paginated.
+    totalCount: Int!
+    # Pagination information.
+    pageInfo: PageInfo!
+}
+
+# A gitserver shard, which stores clones of repositories.
+type GitserverShard {
+    # The address of the shard.
+    address: String!
+    # The size in bytes of the disk the repositories are stored on.
+    totalBytes: Float!
+    # The free space in bytes on the disk the repositories are stored on.
+    freeBytes: Float!
+    # The total size in bytes of the repositories whose size is known.
+    repositoriesBytes: Float!
+    # The number of repositories whose size is known.
+    repositoriesCount: Int!
+}
+
+# The size of a repository on a gitserver shard.
+type RepositoryDiskUsage {
+    # The name of the repository.
+    repositoryName: String!
+    # The repository, or null if it was deleted.
+    repository: Repository
+    # The address of the gitserver shard the repository is stored on.
+    shard: String!
+    # The size in bytes of the repository on disk.
+    sizeBytes: Float!
+    # The date and time the size was recorded.
+    updatedAt: DateTime!
+}
+
+# A list of repository disk usage entries.
+type RepositoryDiskUsageConnection {
+    # A list of repository disk usage entries.
+    nodes: [RepositoryDiskUsage!]!
+    # The total count of entries in the connection. This total count may be larger than the number of nodes
+    # in this object when the result is paginated.
+    totalCount: Int!
+    # Pagination information.
+    pageInfo: PageInfo!
+}
+
 # A list of authentication providers.
 type AuthProviderConnection {
     # A list of authentication providers.
@@ -3901,6 +4280,11 @@ type LSIFUpload implements Node {
 
     # The rank of this upload in the queue. The value of this field is null if the upload has been processed.
     placeInQueue: Int
+
+    # The problems found while validating the upload, in the order in which they occur in the upload. At most
+    # 100 diagnostics are recorded. An upload with errors (or with warnings, if strict validation is enabled)
+    # is marked as errored.
+    diagnostics: [LSIFUploadDiagnostic!]!
 }
 
 # Metadata about a LSIF upload failure.
@@ -3912,6 +4296,47 @@ type LSIFUploadFailureReason {
     stacktrace: String!
 }
 
+# The severity of a problem found in an LSIF upload.
+enum LSIFUploadDiagnosticSeverity {
+    # The upload is invalid and is not processed.
+    ERROR
+
+    # The upload is processed, but some of its data may be missing or incorrect.
+    WARNING
+}
+
+# A problem found while validating an LSIF upload.
+type LSIFUploadDiagnostic {
+    # The severity of the problem.
+    severity: LSIFUploadDiagnosticSeverity!
+
+    # A stable identifier of the kind of problem, such as "dangling-reference" or "range-outside-document".
+    code: String!
+
+    # A description of the problem.
+    message: String!
+
+    # The identifier of the LSIF element with the problem. Empty if the problem does not concern a single element.
+    elementID: String!
+
+    # The one-based line of the element in the upload. Zero if the problem does not concern a single element.
+    line: Int!
+}
+
+# How the retention policies apply to an LSIF upload.
+type LSIFUploadRetention {
+    # The upload.
+    upload: LSIFUpload!
+
+    # The reasons the upload is kept, such as "visible from tip", "tagged: v1.0.0", or
+    # "latest on branch: release/1.0". Empty if no retention policy keeps the upload.
+    retainedBecause: [String!]!
+
+    # Whether the upload is not kept by any retention policy and is older than the maximum
+    # age. Expired uploads are deleted by the next janitor run unless dry-run is enabled.
+    expired: Boolean!
+}
+
 # A list of LSIF uploads.
 type LSIFUploadConnection {
     # A list of LSIF uploads.
@@ -3924,6 +4349,140 @@ type LSIFUploadConnection {
     pageInfo: PageInfo!
 }
 
+# The state an LSIF index job can be in.
+enum LSIFIndexState {
+    # The indexer is running.
+    PROCESSING
+
+    # The indexer failed.
+    ERRORED
+
+    # The indexer succeeded and its output was uploaded.
+    COMPLETED
+
+    # This index job is queued to be run later.
+    QUEUED
+}
+
+# Metadata and status about an LSIF index job, which runs an indexer against a repository
+# to produce an LSIF upload.
+type LSIFIndex implements Node {
+    # The ID.
+    id: ID!
+
+    # The 40-character commit that is indexed.
+    inputCommit: String!
+
+    # The name of the indexer.
+    indexer: String!
+
+    # The index job's current state.
+    state: LSIFIndexState!
+
+    # The time the index job was queued.
+    queuedAt: DateTime!
+
+    # The time the indexer was started.
+    startedAt: DateTime
+
+    # The time the index job completed or errored.
+    finishedAt: DateTime
+
+    # A summary of the index job's failure (not set if state is not ERRORED).
+    failure: String
+
+    # The (possibly truncated) output of the indexer. Only site admins may view the output of indexers.
+    log: String
+
+    # The upload produced by the indexer (not set if state is not COMPLETED).
+    upload: LSIFUpload
+}
+
+# Precise code intelligence coverage of a directory at the tip of a repository's default branch.
+type LSIFCoverage {
+    # The commit at the tip of the default branch.
+    commit: GitCommit!
+
+    # The completed LSIF uploads visible at the tip of the default branch.
+    uploads: [LSIFUploadCoverage!]!
+
+    # The coverage of the directory, followed by the coverage of each of its subdirectories.
+    directories: [LSIFDirectoryCoverage!]!
+}
+
+# A list of repositories and their precise code intelligence coverage.
+type LSIFRepositoryCoverageConnection {
+    # A list of repositories and their precise code intelligence coverage.
+    nodes: [LSIFRepositoryCoverage!]!
+
+    # The total number of repositories in the connection.
+    totalCount: Int!
+
+    # Pagination information.
+    pageInfo: PageInfo!
+}
+
+# The precise code intelligence coverage of a repository.
+type LSIFRepositoryCoverage {
+    # The repository.
+    repository: Repository!
+
+    # Where precise code intelligence is available at the tip of the repository's default
+    # branch, as in Repository.lsifCoverage. Null if the repository is empty.
+    coverage(
+        # The path of the directory, relative to the root of the repository.
+        path: String = ""
+    ): LSIFCoverage
+}
+
+# An LSIF upload visible at the tip of a repository's default branch.
+type LSIFUploadCoverage {
+    # The upload.
+    upload: LSIFUpload!
+
+    # The number of commits on the default branch that are not in the upload's commit.
+    commitsBehindTip: Int!
+}
+
+# The languages of a directory and the LSIF uploads that cover them.
+type LSIFDirectoryCoverage {
+    # The path of the directory, relative to the root of the repository.
+    path: String!
+
+    # The languages of the directory.
+    languages: [LSIFLanguageCoverage!]!
+}
+
+# A language of a directory and the LSIF uploads whose indexer handles the language.
+type LSIFLanguageCoverage {
+    # The name of the language.
+    name: String!
+
+    # The total bytes in the language in the directory.
+    totalBytes: Float!
+
+    # The total number of lines in the language in the directory.
+    totalLines: Int!
+
+    # The uploads whose root encloses the directory, which cover all of it.
+    uploads: [LSIFUploadCoverage!]!
+
+    # The uploads whose root lies within the directory, which cover part of it.
+    partialUploads: [LSIFUploadCoverage!]!
+}
+
+# A list of LSIF index jobs.
+type LSIFIndexConnection {
+    # A list of LSIF index jobs.
+    nodes: [LSIFIndex!]!
+
+    # The total number of index jobs in this result set.
+    totalCount: Int
+
+    # Pagination information.
+    pageInfo: PageInfo!
+}
+
 # Mutations that are only used on Sourcegraph.com.
 #
 # FOR INTERNAL USE ONLY.
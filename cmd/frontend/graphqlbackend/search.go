@@ -88,7 +88,7 @@ func NewSearchImplementer(args *SearchArgs) (SearchImplementer, error) {
 	}
 
 	var queryInfo query.QueryInfo
-	if conf.AndOrQueryEnabled() && searchType != query.SearchTypeLiteral && query.ContainsAndOrKeyword(args.Query) {
+	if conf.AndOrQueryEnabled() && searchType != query.SearchTypeLiteral && searchType != query.SearchTypeSecrets && query.ContainsAndOrKeyword(args.Query) {
 		// To process the input as an and/or query, the flag must be enabled, not be a
 		// literal search, and must contain either an 'and' or 'or' expression.
 		// Else, fallback to the older existing parser.
@@ -198,6 +198,8 @@ func detectSearchType(version string, patternType *string, input string) (query.
 			searchType = query.SearchTypeRegex
 		case "structural":
 			searchType = query.SearchTypeStructural
+		case "secrets":
+			searchType = query.SearchTypeSecrets
 		default:
 			return -1, fmt.Errorf("unrecognized patternType: %v", patternType)
 		}
@@ -226,6 +228,8 @@ func detectSearchType(version string, patternType *string, input string) (query.
 
 		} else if match, _ := regexp.MatchString("structural", extracted); match {
 			searchType = query.SearchTypeStructural
+		} else if match, _ := regexp.MatchString("secrets", extracted); match {
+			searchType = query.SearchTypeSecrets
 		}
 	}
 
@@ -957,7 +961,7 @@ func (r *searchSuggestionResolver) ToSymbol() (*symbolResolver, bool) {
 	if !ok {
 		return nil, false
 	}
-	return toSymbolResolver(s.symbol, s.baseURI, s.lang, s.commit), true
+	return s.toSymbolResolver(), true
 }
 
 func (r *searchSuggestionResolver) ToLanguage() (*languageResolver, bool) {
@@ -1025,6 +1029,8 @@ func handleRepoSearchResult(common *searchResultsCommon, repoRev *search.Reposit
 		} else {
 			return searchErr
 		}
+	} else if gitserver.IsHistoryUnavailable(searchErr) {
+		common.shallow = append(common.shallow, repoRev.Repo)
 	} else if errcode.IsNotFound(searchErr) {
 		common.missing = append(common.missing, repoRev.Repo)
 	} else if errcode.IsTimeout(searchErr) || errcode.IsTemporary(searchErr) || timedOut {
@@ -559,6 +559,7 @@ func sliceSearchResultsCommon(common *searchResultsCommon, firstResultRepo, last
 	final.indexed = doAppend(final.indexed, common.indexed)
 	final.cloning = doAppend(final.cloning, common.cloning)
 	final.missing = doAppend(final.missing, common.missing)
+	final.shallow = doAppend(final.shallow, common.shallow)
 	final.timedout = doAppend(final.timedout, common.timedout)
 	return final
 }
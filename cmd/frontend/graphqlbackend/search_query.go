@@ -17,6 +17,8 @@ func (q searchQueryDescription) Query() string {
 			return q.query + " patternType:literal"
 		case query.SearchTypeStructural:
 			return q.query + " patternType:structural"
+		case query.SearchTypeSecrets:
+			return q.query + " patternType:secrets"
 		default:
 			panic("unreachable")
 		}
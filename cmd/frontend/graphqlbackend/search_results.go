@@ -16,6 +16,7 @@ import (
 
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/authz"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/inventory"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/repoaccesslog"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/usagestats"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
 	"github.com/src-d/enry/v2"
@@ -54,6 +55,7 @@ type searchResultsCommon struct {
 	indexed  []*types.Repo             // repos that were searched using an index
 	cloning  []*types.Repo             // repos that could not be searched because they were still being cloned
 	missing  []*types.Repo             // repos that could not be searched because they do not exist
+	shallow  []*types.Repo             // repos whose history could not be searched because it was cloned shallowly
 	partial  map[api.RepoName]struct{} // repos that were searched, but have results that were not returned due to exceeded limits
 
 	maxResultsCount, resultCount int32
@@ -94,6 +96,10 @@ func (c *searchResultsCommon) Missing() []*RepositoryResolver {
 	return RepositoryResolvers(c.missing)
 }
 
+func (c *searchResultsCommon) HistoryUnavailable() []*RepositoryResolver {
+	return RepositoryResolvers(c.shallow)
+}
+
 func (c *searchResultsCommon) Timedout() []*RepositoryResolver {
 	return RepositoryResolvers(c.timedout)
 }
@@ -122,6 +128,7 @@ func (c *searchResultsCommon) update(other searchResultsCommon) {
 	c.indexed = append(c.indexed, other.indexed...)
 	c.cloning = append(c.cloning, other.cloning...)
 	c.missing = append(c.missing, other.missing...)
+	c.shallow = append(c.shallow, other.shallow...)
 	c.timedout = append(c.timedout, other.timedout...)
 	c.resultCount += other.resultCount
 
@@ -492,6 +499,8 @@ func (r *searchResolver) logSearchLatency(ctx context.Context, durationMs int32)
 			switch {
 			case r.patternType == query.SearchTypeStructural:
 				types = append(types, "structural")
+			case r.patternType == query.SearchTypeSecrets:
+				types = append(types, "secrets")
 			case r.patternType == query.SearchTypeLiteral:
 				types = append(types, "literal")
 			case r.patternType == query.SearchTypeRegex:
@@ -514,12 +523,17 @@ func (r *searchResolver) logSearchLatency(ctx context.Context, durationMs int32)
 	if r.patternType == query.SearchTypeLiteral {
 		options = &getPatternInfoOptions{performLiteralSearch: true}
 	}
+	if r.patternType == query.SearchTypeSecrets {
+		options = &getPatternInfoOptions{performSecretSearch: true}
+	}
 	p, _ := r.getPatternInfo(options)
 
 	// If no type: was explicitly specified, infer the result type.
 	if len(types) == 0 {
 		// If a pattern was specified, a content search happened.
-		if p.Pattern != "" {
+		if p.IsSecretSearch {
+			types = append(types, "secrets")
+		} else if p.Pattern != "" {
 			switch {
 			case r.patternType == query.SearchTypeStructural:
 				types = append(types, "structural")
@@ -878,6 +892,31 @@ func (r *searchResolver) evaluate(ctx context.Context, q []query.Node) (*SearchR
 }
 
 func (r *searchResolver) Results(ctx context.Context) (*SearchResultsResolver, error) {
+	srr, err := r.results(ctx)
+	if srr != nil {
+		logSearchResultsAccess(ctx, r.rawQuery(), srr)
+	}
+	return srr, err
+}
+
+// logSearchResultsAccess records that results from the repositories were returned, for the
+// repositories whose accesses are logged.
+func logSearchResultsAccess(ctx context.Context, query string, srr *SearchResultsResolver) {
+	repos := make([]*types.Repo, 0, len(srr.SearchResults))
+	for _, result := range srr.SearchResults {
+		switch m := result.(type) {
+		case *RepositoryResolver:
+			repos = append(repos, m.repo)
+		case *commitSearchResultResolver:
+			repos = append(repos, m.commit.repo.repo)
+		case *FileMatchResolver:
+			repos = append(repos, m.Repo)
+		}
+	}
+	repoaccesslog.LogSearch(ctx, query, repos)
+}
+
+func (r *searchResolver) results(ctx context.Context) (*SearchResultsResolver, error) {
 	switch q := r.query.(type) {
 	case *query.OrdinaryQuery:
 		return r.evaluateLeaf(ctx)
@@ -1073,6 +1112,7 @@ type getPatternInfoOptions struct {
 	forceFileSearch         bool
 	performStructuralSearch bool
 	performLiteralSearch    bool
+	performSecretSearch     bool
 
 	fileMatchLimit int32
 }
@@ -1194,6 +1234,15 @@ func getPatternInfo(q query.QueryInfo, opts *getPatternInfoOptions) (*search.Tex
 	if len(excludePatterns) > 0 {
 		patternInfo.ExcludePattern = unionRegExps(excludePatterns)
 	}
+	if opts.performSecretSearch {
+		// The terms of the search pattern select the secret detection rules to
+		// apply; they are not matched against file contents.
+		patternInfo.IsSecretSearch = true
+		patternInfo.SecretRules = query.SecretRules(q)
+		patternInfo.Pattern = ""
+		patternInfo.IsRegExp = false
+		patternInfo.IsCaseSensitive = false
+	}
 	return patternInfo, nil
 }
 
@@ -1370,6 +1419,10 @@ func (r *searchResolver) doResults(ctx context.Context, forceOnlyResultType stri
 	if r.patternType == query.SearchTypeLiteral {
 		options = &getPatternInfoOptions{performLiteralSearch: true}
 	}
+	if r.patternType == query.SearchTypeSecrets {
+		options = &getPatternInfoOptions{performSecretSearch: true}
+		forceOnlyResultType = "file"
+	}
 	p, err := r.getPatternInfo(options)
 	if err != nil {
 		return nil, err
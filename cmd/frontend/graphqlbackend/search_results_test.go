@@ -614,6 +614,29 @@ func TestSearchResolver_getPatternInfo(t *testing.T) {
 			}
 		})
 	}
+
+	t.Run("secret detection search", func(t *testing.T) {
+		q, err := query.ParseAndCheck("patterntype:secrets github-token aws-access-key-id file:f")
+		if err != nil {
+			t.Fatal(err)
+		}
+		sr := searchResolver{query: q}
+		p, err := sr.getPatternInfo(&getPatternInfoOptions{performSecretSearch: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := search.TextPatternInfo{
+			IsSecretSearch:         true,
+			SecretRules:            []string{"github-token", "aws-access-key-id"},
+			PathPatternsAreRegExps: true,
+			IncludePatterns:        []string{"f"},
+		}
+		normalize(p)
+		normalize(&want)
+		if !reflect.DeepEqual(*p, want) {
+			t.Errorf("\ngot  %+v\nwant %+v", *p, want)
+		}
+	})
 }
 
 func TestSearchResolver_DynamicFilters(t *testing.T) {
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"net/url"
+	"path"
 	"sort"
 	"strings"
 	"sync"
@@ -18,6 +19,7 @@ import (
 	lsp "github.com/sourcegraph/go-lsp"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/goroutine"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/inventory"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/conf"
@@ -37,12 +39,26 @@ type searchSymbolResult struct {
 	baseURI *gituri.URI
 	lang    string
 	commit  *GitCommitResolver // TODO: change to utility type we create to remove git resolvers from search.
+
+	// lspKind and lspRange are set for symbols from LSIF uploads, whose kind and range are
+	// precise instead of guessed from ctags output.
+	lspKind  lsp.SymbolKind
+	lspRange *lsp.Range
 }
 
 func (s *searchSymbolResult) uri() *gituri.URI {
 	return s.baseURI.WithFilePath(s.symbol.Path)
 }
 
+func (s *searchSymbolResult) toSymbolResolver() *symbolResolver {
+	resolver := toSymbolResolver(s.symbol, s.baseURI, s.lang, s.commit)
+	if s.lspRange != nil {
+		resolver.lspKind = s.lspKind
+		resolver.location.lspRange = s.lspRange
+	}
+	return resolver
+}
+
 var mockSearchSymbols func(ctx context.Context, args *search.TextParameters, limit int) (res []*FileMatchResolver, common *searchResultsCommon, err error)
 
 // searchSymbols searches the given repos in parallel for symbols matching the given search query
@@ -161,6 +177,7 @@ func searchSymbols(ctx context.Context, args *search.TextParameters, limit int)
 	goroutine.Go(func() {
 		defer run.Release()
 		matches, limitHit, reposLimitHit, searchErr := zoektSearchHEAD(ctx, args, zoektRepos, true, time.Since)
+		matches = addPreciseSymbols(ctx, zoektRepos, matches, args.PatternInfo, limit)
 		mu.Lock()
 		defer mu.Unlock()
 		if ctx.Err() == nil {
@@ -278,6 +295,18 @@ func searchSymbolsInRepo(ctx context.Context, repoRevs *search.RepositoryRevisio
 		return nil, err
 	}
 
+	// Symbols from LSIF uploads are searched alongside the symbols service, which remains the
+	// source of symbols for files without LSIF data.
+	var (
+		precise     []*searchSymbolResult
+		preciseErr  error
+		preciseDone = make(chan struct{})
+	)
+	goroutine.Go(func() {
+		defer close(preciseDone)
+		precise, preciseErr = searchPreciseSymbolsInRepo(ctx, repoRevs.Repo, commitID, inputRev, patternInfo, limit)
+	})
+
 	symbols, err := backend.Symbols.ListTags(ctx, search.SymbolsParameters{
 		Repo:            repoRevs.Repo.Name,
 		CommitID:        commitID,
@@ -289,8 +318,7 @@ func searchSymbolsInRepo(ctx context.Context, repoRevs *search.RepositoryRevisio
 		// Ask for limit + 1 so we can detect whether there are more results than the limit.
 		First: limit + 1,
 	})
-	fileMatchesByURI := make(map[string]*FileMatchResolver)
-	fileMatches := make([]*FileMatchResolver, 0)
+	symbolResults := make([]*searchSymbolResult, 0, len(symbols))
 	for _, symbol := range symbols {
 		commit := &GitCommitResolver{
 			repo:     &RepositoryResolver{repo: repoRevs.Repo},
@@ -298,12 +326,27 @@ func searchSymbolsInRepo(ctx context.Context, repoRevs *search.RepositoryRevisio
 			inputRev: &inputRev,
 			// NOTE: Not all fields are set, for performance.
 		}
-		symbolRes := &searchSymbolResult{
+		symbolResults = append(symbolResults, &searchSymbolResult{
 			symbol:  symbol,
 			baseURI: baseURI,
 			lang:    strings.ToLower(symbol.Language),
 			commit:  commit,
-		}
+		})
+	}
+
+	<-preciseDone
+	if preciseErr != nil {
+		logPreciseSymbolsError(repoRevs.Repo, preciseErr)
+	}
+	return mergePreciseFileMatches(groupSymbolFileMatches(symbolResults, inputRev), precise, inputRev), err
+}
+
+// groupSymbolFileMatches groups the given symbols into a file match per file, in the order of
+// their first symbol.
+func groupSymbolFileMatches(symbols []*searchSymbolResult, inputRev string) []*FileMatchResolver {
+	fileMatchesByURI := make(map[string]*FileMatchResolver)
+	fileMatches := make([]*FileMatchResolver, 0)
+	for _, symbolRes := range symbols {
 		uri := makeFileMatchURIFromSymbol(symbolRes, inputRev)
 		if fileMatch, ok := fileMatchesByURI[uri]; ok {
 			fileMatch.symbols = append(fileMatch.symbols, symbolRes)
@@ -321,7 +364,162 @@ func searchSymbolsInRepo(ctx context.Context, repoRevs *search.RepositoryRevisio
 			fileMatches = append(fileMatches, fileMatch)
 		}
 	}
-	return fileMatches, err
+	return fileMatches
+}
+
+// searchPreciseSymbolsInRepo returns the symbols matching the given pattern from the LSIF uploads
+// of the repository at exactly the given commit. Uploads of other commits are not used, as the
+// positions of their symbols may be off.
+func searchPreciseSymbolsInRepo(ctx context.Context, repo *types.Repo, commitID api.CommitID, inputRev string, patternInfo *search.TextPatternInfo, limit int) ([]*searchSymbolResult, error) {
+	// Match like the symbols service does
+	caseSensitive := func(pattern string) string {
+		if pattern == "" || patternInfo.IsCaseSensitive {
+			return pattern
+		}
+		return "(?i:" + pattern + ")"
+	}
+	includePatterns := make([]string, 0, len(patternInfo.IncludePatterns))
+	for _, pattern := range patternInfo.IncludePatterns {
+		includePatterns = append(includePatterns, caseSensitive(pattern))
+	}
+
+	locations, err := EnterpriseResolvers.codeIntelResolver.LSIFSymbols(ctx, &LSIFSymbolsArgs{
+		RepoID:          repo.ID,
+		Commit:          commitID,
+		Query:           caseSensitive(patternInfo.Pattern),
+		IncludePatterns: includePatterns,
+		ExcludePattern:  caseSensitive(patternInfo.ExcludePattern),
+		Limit:           limit + 1,
+	})
+	if err != nil || len(locations) == 0 {
+		return nil, err
+	}
+
+	baseURI, err := gituri.Parse("git://" + string(repo.Name) + "?" + url.QueryEscape(inputRev))
+	if err != nil {
+		return nil, err
+	}
+
+	symbols := make([]*searchSymbolResult, 0, len(locations))
+	for _, location := range locations {
+		lspRange := location.Range
+		language, _ := inventory.GetLanguageByFilename(path.Base(location.Path))
+		symbols = append(symbols, &searchSymbolResult{
+			symbol: protocol.Symbol{
+				Name:   location.Name,
+				Parent: location.ContainerName,
+				Path:   location.Path,
+				Line:   location.Range.Start.Line + 1,
+			},
+			baseURI: baseURI,
+			lang:    strings.ToLower(language),
+			commit: &GitCommitResolver{
+				repo:     &RepositoryResolver{repo: repo},
+				oid:      GitObjectID(commitID),
+				inputRev: &inputRev,
+			},
+			lspKind:  location.Kind,
+			lspRange: &lspRange,
+		})
+	}
+	return symbols, nil
+}
+
+// mergePreciseFileMatches replaces the symbols of the file matches for which there are symbols from
+// LSIF uploads with those, and adds file matches for the files only LSIF uploads have symbols for.
+// The other file matches are kept as they are, which makes the symbols service the fallback for
+// files without LSIF data.
+func mergePreciseFileMatches(fileMatches []*FileMatchResolver, precise []*searchSymbolResult, inputRev string) []*FileMatchResolver {
+	if len(precise) == 0 {
+		return fileMatches
+	}
+
+	preciseByPath := make(map[string][]*searchSymbolResult)
+	for _, symbol := range precise {
+		preciseByPath[symbol.symbol.Path] = append(preciseByPath[symbol.symbol.Path], symbol)
+	}
+
+	merged := make([]*FileMatchResolver, 0, len(fileMatches)+len(preciseByPath))
+	for _, fileMatch := range fileMatches {
+		symbols, ok := preciseByPath[fileMatch.JPath]
+		if !ok {
+			merged = append(merged, fileMatch)
+			continue
+		}
+
+		preciseFileMatch := *fileMatch
+		preciseFileMatch.symbols = symbols
+		merged = append(merged, &preciseFileMatch)
+		delete(preciseByPath, fileMatch.JPath)
+	}
+
+	var remaining []*searchSymbolResult
+	for _, symbol := range precise {
+		if _, ok := preciseByPath[symbol.symbol.Path]; ok {
+			remaining = append(remaining, symbol)
+		}
+	}
+	return append(merged, groupSymbolFileMatches(remaining, inputRev)...)
+}
+
+// addPreciseSymbols merges the symbols from LSIF uploads into the symbol matches zoekt found in the
+// given indexed repositories. Repositories without zoekt matches are not searched for LSIF symbols.
+func addPreciseSymbols(ctx context.Context, repos []*search.RepositoryRevisions, matches []*FileMatchResolver, patternInfo *search.TextPatternInfo, limit int) []*FileMatchResolver {
+	reposByID := make(map[api.RepoID]*search.RepositoryRevisions, len(repos))
+	for _, repoRevs := range repos {
+		reposByID[repoRevs.Repo.ID] = repoRevs
+	}
+
+	var repoIDs []api.RepoID
+	matchesByRepo := make(map[api.RepoID][]*FileMatchResolver)
+	for _, match := range matches {
+		if _, ok := matchesByRepo[match.Repo.ID]; !ok {
+			repoIDs = append(repoIDs, match.Repo.ID)
+		}
+		matchesByRepo[match.Repo.ID] = append(matchesByRepo[match.Repo.ID], match)
+	}
+
+	var (
+		run = parallel.NewRun(conf.SearchSymbolsParallelism())
+		mu  sync.Mutex
+	)
+	for _, repoID := range repoIDs {
+		repoRevs, ok := reposByID[repoID]
+		if !ok {
+			continue
+		}
+
+		run.Acquire()
+		goroutine.Go(func() {
+			defer run.Release()
+			inputRev := repoRevs.RevSpecs()[0]
+			precise, err := searchPreciseSymbolsInRepo(ctx, repoRevs.Repo, repoRevs.IndexedHEADCommit(), inputRev, patternInfo, limit)
+			if err != nil {
+				logPreciseSymbolsError(repoRevs.Repo, err)
+				return
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			matchesByRepo[repoRevs.Repo.ID] = mergePreciseFileMatches(matchesByRepo[repoRevs.Repo.ID], precise, inputRev)
+		})
+	}
+	_ = run.Wait()
+
+	merged := make([]*FileMatchResolver, 0, len(matches))
+	for _, repoID := range repoIDs {
+		merged = append(merged, matchesByRepo[repoID]...)
+	}
+	return merged
+}
+
+// logPreciseSymbolsError logs a failure to search the symbols of LSIF uploads. Symbol search falls
+// back to the symbols service, so the error is not returned.
+func logPreciseSymbolsError(repo *types.Repo, err error) {
+	if err == codeIntelOnlyInEnterprise || err == context.Canceled || err == context.DeadlineExceeded {
+		return
+	}
+	log15.Warn("Failed to search LSIF symbols, falling back to the symbols service", "repo", repo.Name, "error", err)
 }
 
 // makeFileMatchURIFromSymbol makes a git://repo?rev#path URI from a symbol
@@ -1,14 +1,18 @@
 package graphqlbackend
 
 import (
+	"context"
 	"reflect"
 	"testing"
 	"time"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/sourcegraph/go-lsp"
 
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
 	"github.com/sourcegraph/sourcegraph/internal/gituri"
+	"github.com/sourcegraph/sourcegraph/internal/lsif"
+	"github.com/sourcegraph/sourcegraph/internal/search"
 	"github.com/sourcegraph/sourcegraph/internal/symbols/protocol"
 	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
 )
@@ -27,7 +31,7 @@ func TestMakeFileMatchURIFromSymbol(t *testing.T) {
 		oid:    "c1",
 		author: *toSignatureResolver(&gitSignatureWithDate, false),
 	}
-	sr := &searchSymbolResult{symbol, baseURI, "go", commit}
+	sr := &searchSymbolResult{symbol: symbol, baseURI: baseURI, lang: "go", commit: commit}
 
 	tests := []struct {
 		rev  string
@@ -257,3 +261,70 @@ func Test_limitingSymbolResults(t *testing.T) {
 		}
 	})
 }
+
+type fakeLSIFSymbolsResolver struct {
+	defaultCodeIntelResolver
+	args    *LSIFSymbolsArgs
+	symbols []lsif.LSIFSymbolLocation
+}
+
+func (r *fakeLSIFSymbolsResolver) LSIFSymbols(ctx context.Context, args *LSIFSymbolsArgs) ([]lsif.LSIFSymbolLocation, error) {
+	r.args = args
+	return r.symbols, nil
+}
+
+func TestSearchPreciseSymbolsInRepo(t *testing.T) {
+	resolver := &fakeLSIFSymbolsResolver{
+		symbols: []lsif.LSIFSymbolLocation{
+			{Name: "Server", Kind: lsp.SKStruct, Path: "cmd/main.go", Range: lsp.Range{Start: lsp.Position{Line: 3, Character: 5}, End: lsp.Position{Line: 3, Character: 11}}},
+			{Name: "ServeHTTP", Kind: lsp.SKMethod, ContainerName: "Server", Path: "cmd/server.go", Range: lsp.Range{Start: lsp.Position{Line: 9, Character: 17}, End: lsp.Position{Line: 9, Character: 26}}},
+		},
+	}
+	old := EnterpriseResolvers.codeIntelResolver
+	EnterpriseResolvers.codeIntelResolver = resolver
+	defer func() { EnterpriseResolvers.codeIntelResolver = old }()
+
+	repo := &types.Repo{ID: 1, Name: "repo"}
+	patternInfo := &search.TextPatternInfo{Pattern: "^Serve", IncludePatterns: []string{`\.go$`}}
+	precise, err := searchPreciseSymbolsInRepo(context.Background(), repo, "deadbeef", "", patternInfo, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedArgs := &LSIFSymbolsArgs{RepoID: 1, Commit: "deadbeef", Query: "(?i:^Serve)", IncludePatterns: []string{`(?i:\.go$)`}, Limit: 11}
+	if diff := cmp.Diff(expectedArgs, resolver.args); diff != "" {
+		t.Errorf("unexpected LSIFSymbols args (-want +got):\n%s", diff)
+	}
+
+	// The ctags symbols of cmd/main.go are replaced, the ones of README.md are kept, and
+	// cmd/server.go is only known to the LSIF upload.
+	ctags := groupSymbolFileMatches([]*searchSymbolResult{
+		{symbol: protocol.Symbol{Name: "Server", Path: "cmd/main.go", Line: 4, Kind: "type"}, commit: precise[0].commit, baseURI: precise[0].baseURI},
+		{symbol: protocol.Symbol{Name: "Serving", Path: "README.md", Line: 1, Kind: "section"}, commit: precise[0].commit, baseURI: precise[0].baseURI},
+	}, "")
+	merged := mergePreciseFileMatches(ctags, precise, "")
+
+	type summary struct {
+		path, name, containerName, kind string
+		line, character                 int
+	}
+	var actual []summary
+	for _, fileMatch := range merged {
+		for _, symbol := range fileMatch.Symbols() {
+			containerName := ""
+			if c := symbol.ContainerName(); c != nil {
+				containerName = *c
+			}
+			actual = append(actual, summary{fileMatch.JPath, symbol.Name(), containerName, symbol.Kind(), symbol.Location().lspRange.Start.Line, symbol.Location().lspRange.Start.Character})
+		}
+	}
+
+	expected := []summary{
+		{"cmd/main.go", "Server", "", "STRUCT", 3, 5},
+		{"README.md", "Serving", "", "CLASS", 0, 0},
+		{"cmd/server.go", "ServeHTTP", "Server", "METHOD", 9, 17},
+	}
+	if diff := cmp.Diff(expected, actual, cmp.AllowUnexported(summary{})); diff != "" {
+		t.Errorf("unexpected merged symbols (-want +got):\n%s", diff)
+	}
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
 	"github.com/sourcegraph/sourcegraph/internal/search"
 	"github.com/sourcegraph/sourcegraph/internal/search/query"
 	querytypes "github.com/sourcegraph/sourcegraph/internal/search/query/types"
@@ -318,6 +319,7 @@ func Test_defaultRepositories(t *testing.T) {
 func Test_detectSearchType(t *testing.T) {
 	typeRegexp := "regexp"
 	typeLiteral := "literal"
+	typeSecrets := "secrets"
 	testCases := []struct {
 		name        string
 		version     string
@@ -337,6 +339,8 @@ func Test_detectSearchType(t *testing.T) {
 		{"V2, override regex variant pattern type with single quotes", "V2", &typeLiteral, `patterntype:'regex'`, query.SearchTypeRegex},
 		{"V1, override literal pattern type", "V1", &typeRegexp, "patterntype:literal", query.SearchTypeLiteral},
 		{"V1, override literal pattern type, with case-insensitive query", "V1", &typeRegexp, "pAtTErNTypE:literal", query.SearchTypeLiteral},
+		{"V2, secrets pattern type", "V2", &typeSecrets, "", query.SearchTypeSecrets},
+		{"V2, override secrets pattern type", "V2", &typeLiteral, "patterntype:secrets github-token", query.SearchTypeSecrets},
 	}
 
 	for _, test := range testCases {
@@ -478,3 +482,15 @@ func Test_queryForStableResults(t *testing.T) {
 		})
 	}
 }
+
+func TestHandleRepoSearchResult_historyUnavailable(t *testing.T) {
+	repo := &types.Repo{Name: "example.com/foo/bar"}
+	var common searchResultsCommon
+	err := handleRepoSearchResult(&common, &search.RepositoryRevisions{Repo: repo}, false, false, &gitserver.HistoryUnavailableError{Repo: repo.Name})
+	if err != nil {
+		t.Fatalf("got error %v, want it reported as a shallow repository", err)
+	}
+	if want := []*types.Repo{repo}; !reflect.DeepEqual(common.shallow, want) {
+		t.Errorf("got shallow repos %v, want %v", common.shallow, want)
+	}
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/google/zoekt"
 	zoektquery "github.com/google/zoekt/query"
+	"github.com/sourcegraph/go-lsp"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/graphqlutil"
 	"github.com/sourcegraph/sourcegraph/internal/api"
@@ -239,6 +240,7 @@ func (r *symbolConnectionResolver) PageInfo(ctx context.Context) (*graphqlutil.P
 
 type symbolResolver struct {
 	symbol   protocol.Symbol
+	lspKind  lsp.SymbolKind // overrides the kind of symbol when non-zero
 	language string
 	location *locationResolver
 	uri      *gituri.URI
@@ -254,7 +256,10 @@ func (r *symbolResolver) ContainerName() *string {
 }
 
 func (r *symbolResolver) Kind() string /* enum SymbolKind */ {
-	kind := ctagsKindToLSPSymbolKind(r.symbol.Kind)
+	kind := r.lspKind
+	if kind == 0 {
+		kind = ctagsKindToLSPSymbolKind(r.symbol.Kind)
+	}
 	if kind == 0 {
 		return "UNKNOWN"
 	}
@@ -120,7 +120,7 @@ func (fm *FileMatchResolver) Resource() string {
 func (fm *FileMatchResolver) Symbols() []*symbolResolver {
 	symbols := make([]*symbolResolver, len(fm.symbols))
 	for i, s := range fm.symbols {
-		symbols[i] = toSymbolResolver(s.symbol, s.baseURI, s.lang, s.commit)
+		symbols[i] = s.toSymbolResolver()
 	}
 	return symbols
 }
@@ -161,6 +161,7 @@ type lineMatch struct {
 	JOffsetAndLengths [][2]int32 `json:"OffsetAndLengths"`
 	JLineNumber       int32      `json:"LineNumber"`
 	JLimitHit         bool       `json:"LimitHit"`
+	JRuleIDs          []string   `json:"RuleIDs,omitempty"`
 }
 
 func (lm *lineMatch) Preview() string {
@@ -183,6 +184,13 @@ func (lm *lineMatch) LimitHit() bool {
 	return lm.JLimitHit
 }
 
+func (lm *lineMatch) SecretRules() *[]string {
+	if lm.JRuleIDs == nil {
+		return nil
+	}
+	return &lm.JRuleIDs
+}
+
 var mockTextSearch func(ctx context.Context, repo gitserver.Repo, commit api.CommitID, p *search.TextPatternInfo, fetchTimeout time.Duration) (matches []*FileMatchResolver, limitHit bool, err error)
 
 // textSearch searches repo@commit with p.
@@ -223,6 +231,10 @@ func textSearch(ctx context.Context, searcherURLs *endpoint.Map, repo gitserver.
 	if p.IsStructuralPat {
 		q.Set("IsStructuralPat", "true")
 	}
+	if p.IsSecretSearch {
+		q.Set("IsSecretSearch", "true")
+		q["SecretRules"] = p.SecretRules
+	}
 	if p.IsWordMatch {
 		q.Set("IsWordMatch", "true")
 	}
@@ -540,6 +552,14 @@ func searchFilesInRepos(ctx context.Context, args *search.TextParameters) (res [
 		}
 	}
 
+	if args.PatternInfo.IsSecretSearch {
+		// The index can't evaluate secret detection rules, so all repositories are
+		// searched by searcher.
+		tr.LazyPrintf("secret detection search, bypassing zoekt (using searcher) for %d indexed repos", len(zoektRepos))
+		searcherRepos = append(searcherRepos, zoektRepos...)
+		zoektRepos = nil
+	}
+
 	var (
 		// TODO: convert wg to an errgroup
 		wg                sync.WaitGroup
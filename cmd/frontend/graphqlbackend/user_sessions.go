@@ -0,0 +1,190 @@
+package graphqlbackend
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/relay"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/graphqlutil"
+)
+
+// userSessionResolver resolves a session of a user that was established by signing in.
+type userSessionResolver struct {
+	session db.UserSession
+}
+
+func userSessionByID(ctx context.Context, id graphql.ID) (*userSessionResolver, error) {
+	userSessionID, err := unmarshalUserSessionID(id)
+	if err != nil {
+		return nil, err
+	}
+	session, err := db.UserSessions.GetByID(ctx, userSessionID)
+	if err != nil {
+		return nil, err
+	}
+	// 🚨 SECURITY: Only the user (session owner) and site admins may retrieve the session.
+	if err := backend.CheckSiteAdminOrSameUser(ctx, session.UserID); err != nil {
+		return nil, err
+	}
+	return &userSessionResolver{session: *session}, nil
+}
+
+func marshalUserSessionID(id int64) graphql.ID { return relay.MarshalID("UserSession", id) }
+
+func unmarshalUserSessionID(id graphql.ID) (userSessionID int64, err error) {
+	err = relay.UnmarshalSpec(id, &userSessionID)
+	return
+}
+
+func (r *userSessionResolver) ID() graphql.ID { return marshalUserSessionID(r.session.ID) }
+
+func (r *userSessionResolver) User(ctx context.Context) (*UserResolver, error) {
+	return UserByIDInt32(ctx, r.session.UserID)
+}
+
+func (r *userSessionResolver) IP() string { return r.session.IP }
+
+func (r *userSessionResolver) UserAgent() string { return r.session.UserAgent }
+
+func (r *userSessionResolver) Device() string { return deviceFromUserAgent(r.session.UserAgent) }
+
+func (r *userSessionResolver) CreatedAt() DateTime { return DateTime{Time: r.session.CreatedAt} }
+
+func (r *userSessionResolver) LastActiveAt() DateTime { return DateTime{Time: r.session.LastActiveAt} }
+
+// deviceFromUserAgent returns a short, human-readable description of the device (operating
+// system) that sent requests with the given user agent.
+func deviceFromUserAgent(userAgent string) string {
+	ua := strings.ToLower(userAgent)
+	switch {
+	case strings.Contains(ua, "iphone"):
+		return "iPhone"
+	case strings.Contains(ua, "ipad"):
+		return "iPad"
+	case strings.Contains(ua, "android"):
+		return "Android"
+	case strings.Contains(ua, "windows"):
+		return "Windows"
+	case strings.Contains(ua, "mac os x"), strings.Contains(ua, "macintosh"):
+		return "macOS"
+	case strings.Contains(ua, "cros"):
+		return "Chrome OS"
+	case strings.Contains(ua, "linux"):
+		return "Linux"
+	default:
+		return "Unknown"
+	}
+}
+
+func (r *UserResolver) Sessions(ctx context.Context, args *struct {
+	graphqlutil.ConnectionArgs
+}) (*userSessionConnectionResolver, error) {
+	// 🚨 SECURITY: Only site admins and the user can list a user's sessions.
+	if err := backend.CheckSiteAdminOrSameUser(ctx, r.user.ID); err != nil {
+		return nil, err
+	}
+
+	opt := db.UserSessionsListOptions{UserID: r.user.ID}
+	args.ConnectionArgs.Set(&opt.LimitOffset)
+	return &userSessionConnectionResolver{opt: opt}, nil
+}
+
+func (r *schemaResolver) RevokeUserSession(ctx context.Context, args *struct {
+	UserSession graphql.ID
+}) (*EmptyResponse, error) {
+	userSessionID, err := unmarshalUserSessionID(args.UserSession)
+	if err != nil {
+		return nil, err
+	}
+	session, err := db.UserSessions.GetByID(ctx, userSessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 🚨 SECURITY: Only site admins and the user can revoke a user's session.
+	if err := backend.CheckSiteAdminOrSameUser(ctx, session.UserID); err != nil {
+		return nil, err
+	}
+	if err := db.UserSessions.RevokeByID(ctx, session.ID, session.UserID); err != nil {
+		return nil, err
+	}
+	return &EmptyResponse{}, nil
+}
+
+func (r *schemaResolver) RevokeAllUserSessions(ctx context.Context, args *struct {
+	User graphql.ID
+}) (*EmptyResponse, error) {
+	userID, err := UnmarshalUserID(args.User)
+	if err != nil {
+		return nil, err
+	}
+
+	// 🚨 SECURITY: Only site admins and the user can revoke a user's sessions.
+	if err := backend.CheckSiteAdminOrSameUser(ctx, userID); err != nil {
+		return nil, err
+	}
+	if _, err := db.UserSessions.RevokeByUser(ctx, userID); err != nil {
+		return nil, err
+	}
+	return &EmptyResponse{}, nil
+}
+
+// userSessionConnectionResolver resolves a list of user sessions.
+//
+// 🚨 SECURITY: When instantiating a userSessionConnectionResolver value, the caller MUST check
+// permissions.
+type userSessionConnectionResolver struct {
+	opt db.UserSessionsListOptions
+
+	// cache results because they are used by multiple fields
+	once     sync.Once
+	sessions []*db.UserSession
+	err      error
+}
+
+func (r *userSessionConnectionResolver) compute(ctx context.Context) ([]*db.UserSession, error) {
+	r.once.Do(func() {
+		opt2 := r.opt
+		if opt2.LimitOffset != nil {
+			tmp := *opt2.LimitOffset
+			opt2.LimitOffset = &tmp
+			opt2.Limit++ // so we can detect if there is a next page
+		}
+
+		r.sessions, r.err = db.UserSessions.List(ctx, opt2)
+	})
+	return r.sessions, r.err
+}
+
+func (r *userSessionConnectionResolver) Nodes(ctx context.Context) ([]*userSessionResolver, error) {
+	sessions, err := r.compute(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if r.opt.LimitOffset != nil && len(sessions) > r.opt.LimitOffset.Limit {
+		sessions = sessions[:r.opt.LimitOffset.Limit]
+	}
+
+	var l []*userSessionResolver
+	for _, session := range sessions {
+		l = append(l, &userSessionResolver{session: *session})
+	}
+	return l, nil
+}
+
+func (r *userSessionConnectionResolver) TotalCount(ctx context.Context) (int32, error) {
+	count, err := db.UserSessions.Count(ctx, r.opt)
+	return int32(count), err
+}
+
+func (r *userSessionConnectionResolver) PageInfo(ctx context.Context) (*graphqlutil.PageInfo, error) {
+	sessions, err := r.compute(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return graphqlutil.HasNextPage(r.opt.LimitOffset != nil && len(sessions) > r.opt.Limit), nil
+}
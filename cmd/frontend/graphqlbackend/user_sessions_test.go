@@ -0,0 +1,106 @@
+package graphqlbackend
+
+import (
+	"context"
+	"testing"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/gqltesting"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+)
+
+// 🚨 SECURITY: This tests that users can't revoke sessions of other users.
+func TestMutation_RevokeUserSession(t *testing.T) {
+	mockUserSessions := func(t *testing.T) (revoked *bool) {
+		revoked = new(bool)
+		db.Mocks.UserSessions.GetByID = func(id int64) (*db.UserSession, error) {
+			if want := int64(1); id != want {
+				t.Errorf("got %d, want %d", id, want)
+			}
+			return &db.UserSession{ID: 1, UserID: 2}, nil
+		}
+		db.Mocks.UserSessions.RevokeByID = func(id int64, userID int32) error {
+			if want := int64(1); id != want {
+				t.Errorf("got %d, want %d", id, want)
+			}
+			if want := int32(2); userID != want {
+				t.Errorf("got %v, want %v", userID, want)
+			}
+			*revoked = true
+			return nil
+		}
+		return revoked
+	}
+
+	session1GQLID := marshalUserSessionID(1)
+
+	t.Run("authenticated as user", func(t *testing.T) {
+		resetMocks()
+		revoked := mockUserSessions(t)
+		gqltesting.RunTests(t, []*gqltesting.Test{
+			{
+				Context: actor.WithActor(context.Background(), &actor.Actor{UID: 2}),
+				Schema:  mustParseGraphQLSchema(t),
+				Query: `
+				mutation {
+					revokeUserSession(userSession: "` + string(session1GQLID) + `") {
+						alwaysNil
+					}
+				}
+			`,
+				ExpectedResult: `
+				{
+					"revokeUserSession": {
+						"alwaysNil": null
+					}
+				}
+			`,
+			},
+		})
+		if !*revoked {
+			t.Error("session was not revoked")
+		}
+	})
+
+	t.Run("authenticated as different non-site-admin user", func(t *testing.T) {
+		resetMocks()
+		const differentNonSiteAdminUID = 456
+		revoked := mockUserSessions(t)
+		db.Mocks.Users.GetByCurrentAuthUser = func(ctx context.Context) (*types.User, error) { return &types.User{ID: differentNonSiteAdminUID}, nil }
+		defer func() { db.Mocks.Users.GetByCurrentAuthUser = nil }()
+		db.Mocks.Users.GetByID = func(_ context.Context, userID int32) (*types.User, error) {
+			return &types.User{Username: "username"}, nil
+		}
+		defer func() { db.Mocks.Users.GetByID = nil }()
+
+		ctx := actor.WithActor(context.Background(), &actor.Actor{UID: differentNonSiteAdminUID})
+		result, err := (&schemaResolver{}).RevokeUserSession(ctx, &struct{ UserSession graphql.ID }{UserSession: session1GQLID})
+		if err == nil {
+			t.Error("Expected error, but there was none")
+		}
+		if result != nil {
+			t.Errorf("got result %v, want nil", result)
+		}
+		if *revoked {
+			t.Error("session was revoked")
+		}
+	})
+}
+
+func TestDeviceFromUserAgent(t *testing.T) {
+	tests := map[string]string{
+		"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_2) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/79.0.3945.88 Safari/537.36": "macOS",
+		"Mozilla/5.0 (Windows NT 10.0; Win64; x64; rv:71.0) Gecko/20100101 Firefox/71.0":                                           "Windows",
+		"Mozilla/5.0 (iPhone; CPU iPhone OS 13_3 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Mobile/15E148":            "iPhone",
+		"Mozilla/5.0 (Linux; Android 10; Pixel 3) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/79.0.3945.93 Mobile Safari/537.36": "Android",
+		"Mozilla/5.0 (X11; Linux x86_64; rv:71.0) Gecko/20100101 Firefox/71.0":                                                     "Linux",
+		"curl/7.64.1": "Unknown",
+	}
+	for userAgent, want := range tests {
+		if got := deviceFromUserAgent(userAgent); got != want {
+			t.Errorf("%q: got %q, want %q", userAgent, got, want)
+		}
+	}
+}
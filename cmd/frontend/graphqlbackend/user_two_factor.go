@@ -0,0 +1,111 @@
+package graphqlbackend
+
+import (
+	"context"
+	"errors"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+)
+
+func (r *UserResolver) TwoFactorEnabled(ctx context.Context) (bool, error) {
+	// 🚨 SECURITY: Only the user and site admins can see whether the user uses two-factor
+	// authentication.
+	if err := backend.CheckSiteAdminOrSameUser(ctx, r.user.ID); err != nil {
+		return false, err
+	}
+	return db.UserTOTP.IsEnabled(ctx, r.user.ID)
+}
+
+// currentBuiltinUser returns the current user, who must have a builtin (username-password)
+// account, because two-factor authentication only applies to signing in with a password.
+func currentBuiltinUser(ctx context.Context) (*types.User, error) {
+	user, err := db.Users.GetByCurrentAuthUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, errors.New("no authenticated user")
+	}
+	if !user.BuiltinAuth {
+		return nil, errors.New("two-factor authentication is only available for accounts that sign in with a username and password")
+	}
+	return user, nil
+}
+
+type twoFactorEnrollmentResolver struct {
+	secret, keyURI string
+}
+
+func (r *twoFactorEnrollmentResolver) Secret() string { return r.secret }
+func (r *twoFactorEnrollmentResolver) KeyURI() string { return r.keyURI }
+
+func (r *schemaResolver) BeginTwoFactorEnrollment(ctx context.Context) (*twoFactorEnrollmentResolver, error) {
+	// 🚨 SECURITY: A user can only set up two-factor authentication for themselves.
+	user, err := currentBuiltinUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, keyURI, err := backend.TwoFactor.BeginEnrollment(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	return &twoFactorEnrollmentResolver{secret: secret, keyURI: keyURI}, nil
+}
+
+func (r *schemaResolver) ConfirmTwoFactorEnrollment(ctx context.Context, args *struct {
+	Code string
+}) ([]string, error) {
+	// 🚨 SECURITY: A user can only set up two-factor authentication for themselves.
+	user, err := currentBuiltinUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return backend.TwoFactor.ConfirmEnrollment(ctx, user.ID, args.Code)
+}
+
+func (r *schemaResolver) RegenerateTwoFactorRecoveryCodes(ctx context.Context, args *struct {
+	Code string
+}) ([]string, error) {
+	// 🚨 SECURITY: A user can only regenerate their own recovery codes.
+	user, err := currentBuiltinUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return backend.TwoFactor.RegenerateRecoveryCodes(ctx, user.ID, args.Code)
+}
+
+func (r *schemaResolver) DisableTwoFactor(ctx context.Context, args *struct {
+	User graphql.ID
+	Code *string
+}) (*EmptyResponse, error) {
+	userID, err := UnmarshalUserID(args.User)
+	if err != nil {
+		return nil, err
+	}
+
+	// 🚨 SECURITY: Only site admins and the user can disable two-factor authentication. The user
+	// must provide a valid code, so that a stolen session alone is not enough to remove the second
+	// factor. Site admins may disable it for other users without a code (e.g., for users who lost
+	// their authenticator and recovery codes).
+	if err := backend.CheckSiteAdminOrSameUser(ctx, userID); err != nil {
+		return nil, err
+	}
+	if a := actor.FromContext(ctx); a.UID == userID {
+		if args.Code == nil {
+			return nil, errors.New("a two-factor authentication code is required")
+		}
+		if err := backend.TwoFactor.Verify(ctx, userID, *args.Code); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := db.UserTOTP.Delete(ctx, userID); err != nil {
+		return nil, err
+	}
+	return &EmptyResponse{}, nil
+}
@@ -16,6 +16,8 @@ import (
 	"github.com/inconshreveable/log15"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/handlerutil"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/repoaccesslog"
 	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver"
 
@@ -140,6 +142,20 @@ func serveRaw(w http.ResponseWriter, r *http.Request) (err error) {
 			errorS = "error"
 		}
 		metricRawDuration.WithLabelValues(contentType, requestType, errorS).Observe(duration.Seconds())
+
+		if err == nil && requestType != "404" {
+			kind := repoaccesslog.KindRaw
+			if contentType == applicationZip || contentType == applicationXTar {
+				kind = repoaccesslog.KindArchive
+			}
+			repoaccesslog.Log(r.Context(), repoaccesslog.Entry{
+				Repo:     common.Repo,
+				Kind:     kind,
+				Path:     requestedPath,
+				CommitID: common.CommitID,
+				IP:       handlerutil.RemoteIP(r),
+			})
+		}
 	}()
 
 	switch contentType {
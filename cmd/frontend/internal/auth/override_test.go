@@ -77,7 +77,7 @@ func TestOverrideAuthMiddleware(t *testing.T) {
 		}
 		defer func() { auth.MockGetAndSaveUser = nil }()
 		db.Mocks.Users.SetIsSiteAdmin = func(int32, bool) error { return nil }
-		defer func() { db.Mocks = db.MockStores{} }()
+		defer func() { db.Mocks.Users = db.MockUsers{} }()
 		handler.ServeHTTP(rr, req)
 		if got, want := rr.Body.String(), "user 1"; got != want {
 			t.Errorf("got %q, want %q", got, want)
@@ -104,7 +104,7 @@ func TestOverrideAuthMiddleware(t *testing.T) {
 		}
 		defer func() { auth.MockGetAndSaveUser = nil }()
 		db.Mocks.Users.SetIsSiteAdmin = func(int32, bool) error { return nil }
-		defer func() { db.Mocks = db.MockStores{} }()
+		defer func() { db.Mocks.Users = db.MockUsers{} }()
 		handler.ServeHTTP(rr, req)
 		if got, want := rr.Body.String(), "user 1"; got != want {
 			t.Errorf("got %q, want %q", got, want)
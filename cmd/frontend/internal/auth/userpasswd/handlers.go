@@ -25,6 +25,9 @@ type credentials struct {
 	Email    string `json:"email"`
 	Username string `json:"username"`
 	Password string `json:"password"`
+
+	// TwoFactorCode is the TOTP code or a recovery code. It is only used when signing in.
+	TwoFactorCode string `json:"twoFactorCode,omitempty"`
 }
 
 // HandleSignUp handles submission of the user signup form.
@@ -229,6 +232,11 @@ func HandleSignIn(w http.ResponseWriter, r *http.Request) {
 		httpLogAndError(w, "Authentication failed", http.StatusUnauthorized)
 		return
 	}
+	// 🚨 SECURITY: check the second factor (if any) before creating the session
+	recoveryCodes, ok := checkTwoFactor(w, r, usr, creds.TwoFactorCode)
+	if !ok {
+		return
+	}
 	actor := &actor.Actor{UID: usr.ID}
 
 	// Write the session cookie
@@ -236,6 +244,13 @@ func HandleSignIn(w http.ResponseWriter, r *http.Request) {
 		httpLogAndError(w, "Could not create new user session", http.StatusInternalServerError)
 		return
 	}
+
+	if len(recoveryCodes) > 0 {
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		if err := json.NewEncoder(w).Encode(signInResult{RecoveryCodes: recoveryCodes}); err != nil {
+			log15.Error("Error writing sign-in result", "err", err)
+		}
+	}
 }
 
 func httpLogAndError(w http.ResponseWriter, msg string, code int, errArgs ...interface{}) {
@@ -0,0 +1,111 @@
+package userpasswd
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/inconshreveable/log15"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+)
+
+// twoFactorChallenge is the JSON body of a sign-in response when the password was correct but a
+// second factor is needed to complete the sign-in. The client retries the sign-in with the code in
+// the twoFactorCode field.
+type twoFactorChallenge struct {
+	TwoFactorRequired bool   `json:"twoFactorRequired"`
+	Error             string `json:"error,omitempty"`
+
+	// Enrollment is set if the site requires two-factor authentication and the user must set it
+	// up before signing in.
+	Enrollment *twoFactorEnrollment `json:"enrollment,omitempty"`
+}
+
+type twoFactorEnrollment struct {
+	Secret string `json:"secret"`
+	KeyURI string `json:"keyURI"`
+}
+
+// signInResult is the JSON body of a successful sign-in response, if there is anything to show to
+// the user.
+type signInResult struct {
+	// RecoveryCodes are the user's new two-factor authentication recovery codes, if the user set up
+	// two-factor authentication while signing in.
+	RecoveryCodes []string `json:"recoveryCodes,omitempty"`
+}
+
+// checkTwoFactor performs the second step of signing in with a username and password, after the
+// password was verified. If it returns ok == false, the sign-in must be aborted (and the response
+// has already been written). If the user set up two-factor authentication as part of signing in,
+// recoveryCodes contains the new recovery codes, which must be shown to the user.
+//
+// 🚨 SECURITY: Any change to this function could allow bypassing the second factor. Be careful.
+func checkTwoFactor(w http.ResponseWriter, r *http.Request, usr *types.User, code string) (recoveryCodes []string, ok bool) {
+	ctx := r.Context()
+
+	t, err := db.UserTOTP.GetByUserID(ctx, usr.ID)
+	if err != nil && err != db.ErrUserTOTPNotFound {
+		httpLogAndError(w, "Error checking two-factor authentication", http.StatusInternalServerError, "err", err)
+		return nil, false
+	}
+
+	if t == nil || t.EnabledAt == nil {
+		if pc, _ := getProviderConfig(); pc == nil || !pc.RequireTwoFactor {
+			return nil, true
+		}
+
+		// The site requires two-factor authentication, so the user must set it up first.
+		if t == nil || code == "" {
+			secret, keyURI, err := backend.TwoFactor.BeginEnrollment(ctx, usr)
+			if err != nil {
+				httpLogAndError(w, "Error setting up two-factor authentication", http.StatusInternalServerError, "err", err)
+				return nil, false
+			}
+			writeTwoFactorChallenge(w, twoFactorChallenge{
+				TwoFactorRequired: true,
+				Enrollment:        &twoFactorEnrollment{Secret: secret, KeyURI: keyURI},
+			})
+			return nil, false
+		}
+		recoveryCodes, err := backend.TwoFactor.ConfirmEnrollment(ctx, usr.ID, code)
+		if err != nil {
+			handleTwoFactorError(w, err, &twoFactorEnrollment{Secret: t.Secret, KeyURI: backend.TwoFactor.KeyURI(usr, t.Secret)})
+			return nil, false
+		}
+		return recoveryCodes, true
+	}
+
+	if code == "" {
+		writeTwoFactorChallenge(w, twoFactorChallenge{TwoFactorRequired: true})
+		return nil, false
+	}
+	if err := backend.TwoFactor.Verify(ctx, usr.ID, code); err != nil {
+		handleTwoFactorError(w, err, nil)
+		return nil, false
+	}
+	return nil, true
+}
+
+func handleTwoFactorError(w http.ResponseWriter, err error, enrollment *twoFactorEnrollment) {
+	switch err {
+	case backend.ErrInvalidTwoFactorCode:
+		writeTwoFactorChallenge(w, twoFactorChallenge{
+			TwoFactorRequired: true,
+			Error:             "Two-factor authentication code was incorrect",
+			Enrollment:        enrollment,
+		})
+	case db.ErrTwoFactorRateLimit:
+		httpLogAndError(w, "Too many two-factor authentication attempts. Try again in a few minutes.", http.StatusTooManyRequests, "err", err)
+	default:
+		httpLogAndError(w, "Error checking two-factor authentication", http.StatusInternalServerError, "err", err)
+	}
+}
+
+func writeTwoFactorChallenge(w http.ResponseWriter, c twoFactorChallenge) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusUnauthorized)
+	if err := json.NewEncoder(w).Encode(c); err != nil {
+		log15.Error("Error writing two-factor authentication challenge", "err", err)
+	}
+}
@@ -0,0 +1,149 @@
+package userpasswd
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/totp"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// 🚨 SECURITY: This tests that the second factor is required to sign in when it is enabled or
+// required by the site.
+func Test_checkTwoFactor(t *testing.T) {
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	code, err := totp.Code(secret, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	enabledAt := time.Now()
+
+	tests := []struct {
+		name             string
+		requireTwoFactor bool
+		factor           *db.UserTOTPFactor
+		rateLimited      bool
+		code             string
+
+		wantOK         bool
+		wantStatus     int
+		wantEnrollment bool
+		wantRecovery   bool
+	}{
+		{
+			name:   "not enrolled",
+			wantOK: true,
+		},
+		{
+			name:   "pending enrollment",
+			factor: &db.UserTOTPFactor{UserID: 1, Secret: secret},
+			code:   "",
+			wantOK: true,
+		},
+		{
+			name:       "enabled without code",
+			factor:     &db.UserTOTPFactor{UserID: 1, Secret: secret, EnabledAt: &enabledAt},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "enabled with wrong code",
+			factor:     &db.UserTOTPFactor{UserID: 1, Secret: secret, EnabledAt: &enabledAt},
+			code:       "000000",
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:        "enabled and rate limited",
+			factor:      &db.UserTOTPFactor{UserID: 1, Secret: secret, EnabledAt: &enabledAt},
+			rateLimited: true,
+			code:        code,
+			wantStatus:  http.StatusTooManyRequests,
+		},
+		{
+			name:   "enabled with correct code",
+			factor: &db.UserTOTPFactor{UserID: 1, Secret: secret, EnabledAt: &enabledAt},
+			code:   code,
+			wantOK: true,
+		},
+		{
+			name:             "required but not enrolled",
+			requireTwoFactor: true,
+			wantStatus:       http.StatusUnauthorized,
+			wantEnrollment:   true,
+		},
+		{
+			name:             "required and confirming enrollment",
+			requireTwoFactor: true,
+			factor:           &db.UserTOTPFactor{UserID: 1, Secret: secret},
+			code:             code,
+			wantOK:           true,
+			wantRecovery:     true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{
+				AuthProviders: []schema.AuthProviders{{Builtin: &schema.BuiltinAuthProvider{Type: "builtin", RequireTwoFactor: test.requireTwoFactor}}},
+			}})
+			defer conf.Mock(nil)
+
+			db.Mocks.UserTOTP = db.MockUserTOTP{
+				GetByUserID: func(userID int32) (*db.UserTOTPFactor, error) {
+					if test.factor == nil {
+						return nil, db.ErrUserTOTPNotFound
+					}
+					return test.factor, nil
+				},
+				CreatePending: func(userID int32, secret string) error { return nil },
+				Enable:        func(userID int32, recoveryCodes []string) error { return nil },
+				RegisterAttempt: func(userID int32) error {
+					if test.rateLimited {
+						return db.ErrTwoFactorRateLimit
+					}
+					return nil
+				},
+				UseStep:             func(userID int32, step int64) (bool, error) { return true, nil },
+				UseRecoveryCode:     func(userID int32, code string) (bool, error) { return false, nil },
+				ClearFailedAttempts: func(userID int32) error { return nil },
+			}
+			defer func() { db.Mocks.UserTOTP = db.MockUserTOTP{} }()
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest("POST", "/-/sign-in", nil)
+			recoveryCodes, ok := checkTwoFactor(w, r, &types.User{ID: 1, Username: "u"}, test.code)
+			if ok != test.wantOK {
+				t.Fatalf("got ok %v, want %v", ok, test.wantOK)
+			}
+			if ok {
+				if got := len(recoveryCodes) > 0; got != test.wantRecovery {
+					t.Errorf("got recovery codes %v, want %v", got, test.wantRecovery)
+				}
+				return
+			}
+
+			if w.Code != test.wantStatus {
+				t.Fatalf("got status %d, want %d", w.Code, test.wantStatus)
+			}
+			if w.Code == http.StatusUnauthorized {
+				var c twoFactorChallenge
+				if err := json.NewDecoder(w.Body).Decode(&c); err != nil {
+					t.Fatal(err)
+				}
+				if !c.TwoFactorRequired {
+					t.Error("want twoFactorRequired")
+				}
+				if got := c.Enrollment != nil; got != test.wantEnrollment {
+					t.Errorf("got enrollment %v, want %v", got, test.wantEnrollment)
+				}
+			}
+		})
+	}
+}
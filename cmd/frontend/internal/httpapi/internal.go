@@ -16,6 +16,8 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/globals"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/handlerutil"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/repoaccesslog"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/conf"
@@ -591,9 +593,29 @@ func (s *gitServiceHandler) serveInfoRefs(w http.ResponseWriter, r *http.Request
 }
 
 func (s *gitServiceHandler) serveGitUploadPack(w http.ResponseWriter, r *http.Request) {
+	logGitCloneAccess(r)
 	s.redirectToGitServer(w, r, "/git-upload-pack")
 }
 
+// logGitCloneAccess records the clone or fetch in the repository access log, if accesses to the
+// repository are logged.
+func logGitCloneAccess(r *http.Request) {
+	repoName := api.RepoName(mux.Vars(r)["RepoName"])
+	if !repoaccesslog.Enabled(repoName) {
+		return
+	}
+	repo, err := db.Repos.GetByName(r.Context(), repoName)
+	if err != nil {
+		log15.Error("Failed to look up repository to record git clone access.", "repo", repoName, "error", err)
+		return
+	}
+	repoaccesslog.Log(r.Context(), repoaccesslog.Entry{
+		Repo: repo,
+		Kind: repoaccesslog.KindGitClone,
+		IP:   handlerutil.RemoteIP(r),
+	})
+}
+
 func (s *gitServiceHandler) redirectToGitServer(w http.ResponseWriter, r *http.Request, gitPath string) {
 	repo := mux.Vars(r)["RepoName"]
 
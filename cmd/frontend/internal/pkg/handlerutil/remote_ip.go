@@ -0,0 +1,20 @@
+package handlerutil
+
+import (
+	"net"
+	"net/http"
+	"strings"
+)
+
+// RemoteIP returns the IP address of the client of the request. It prefers the first address in
+// the X-Forwarded-For header because Sourcegraph is usually deployed behind a reverse proxy. The
+// value is only informational and must not be used for access control.
+func RemoteIP(r *http.Request) string {
+	if xff := r.Header.Get("X-Forwarded-For"); xff != "" {
+		return strings.TrimSpace(strings.Split(xff, ",")[0])
+	}
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		return host
+	}
+	return r.RemoteAddr
+}
@@ -0,0 +1,172 @@
+// Package repoaccesslog records accesses to the code of repositories that the site configuration
+// marks as sensitive (in repoAccessLog.repositories), so that site admins can later report who
+// accessed the code.
+package repoaccesslog
+
+import (
+	"context"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/inconshreveable/log15"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+)
+
+// The kinds of accesses that are logged.
+const (
+	KindBlob     = "blob"      // a file's contents were viewed
+	KindRaw      = "raw"       // a file or directory was downloaded via the raw endpoint
+	KindArchive  = "archive"   // an archive (zip or tar) of the repository was downloaded
+	KindSearch   = "search"    // search results from the repository were returned
+	KindGitClone = "git-clone" // the repository was cloned or fetched via the git protocol
+)
+
+// defaultRetentionDays is the number of days for which entries are kept if
+// repoAccessLog.retentionDays is not set.
+const defaultRetentionDays = 365
+
+// Entry describes an access to a repository.
+type Entry struct {
+	Repo     *types.Repo
+	Kind     string
+	Path     string
+	CommitID api.CommitID
+	Query    string
+	IP       string
+}
+
+// Enabled reports whether accesses to the repository are logged.
+func Enabled(repo api.RepoName) bool {
+	re := matcher()
+	return re != nil && re.MatchString(string(repo))
+}
+
+// Log records the access in the repository access log if accesses to the repository are logged.
+// The user is taken from the actor in the context.
+//
+// Errors are logged but not returned, so that failing to record an access doesn't break the
+// request.
+func Log(ctx context.Context, e Entry) {
+	if e.Repo == nil || !Enabled(e.Repo.Name) {
+		return
+	}
+
+	err := db.RepoAccessLogs.Insert(ctx, &db.RepoAccessLogEntry{
+		RepoID:   e.Repo.ID,
+		RepoName: e.Repo.Name,
+		UserID:   actor.FromContext(ctx).UID,
+		Kind:     e.Kind,
+		Path:     e.Path,
+		CommitID: e.CommitID,
+		Query:    e.Query,
+		IP:       e.IP,
+	})
+	if err != nil {
+		log15.Error("Failed to record repository access.", "repo", e.Repo.Name, "kind", e.Kind, "error", err)
+	}
+}
+
+// LogSearch records that results of the search query from the given repositories were returned.
+// Repositories that appear more than once are only recorded once.
+func LogSearch(ctx context.Context, query string, repos []*types.Repo) {
+	if matcher() == nil {
+		return
+	}
+
+	seen := make(map[api.RepoID]bool, len(repos))
+	for _, repo := range repos {
+		if repo == nil || seen[repo.ID] {
+			continue
+		}
+		seen[repo.ID] = true
+		Log(ctx, Entry{Repo: repo, Kind: KindSearch, Query: query})
+	}
+}
+
+// RetentionPeriod returns how long entries are kept (per repoAccessLog.retentionDays).
+func RetentionPeriod() time.Duration {
+	days := defaultRetentionDays
+	if c := conf.Get().RepoAccessLog; c != nil && c.RetentionDays > 0 {
+		days = c.RetentionDays
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// DeleteExpired periodically deletes the entries that are older than the retention period. It
+// runs forever.
+func DeleteExpired(ctx context.Context) {
+	for {
+		if n, err := db.RepoAccessLogs.DeleteOlderThan(ctx, time.Now().Add(-RetentionPeriod())); err != nil {
+			log15.Error("deleting expired rows from repo_access_logs table", "error", err)
+		} else if n > 0 {
+			log15.Debug("deleted expired rows from repo_access_logs table", "count", n)
+		}
+		time.Sleep(time.Hour)
+	}
+}
+
+var (
+	mu       sync.Mutex
+	patterns []string
+	compiled *regexp.Regexp
+)
+
+// matcher returns the regexp that matches the names of repositories whose accesses are logged, or
+// nil if no accesses are logged. It is recompiled when the site configuration changes.
+func matcher() *regexp.Regexp {
+	var current []string
+	if c := conf.Get().RepoAccessLog; c != nil {
+		current = c.Repositories
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if reflect.DeepEqual(current, patterns) {
+		return compiled
+	}
+
+	re, err := compile(current)
+	if err != nil {
+		// The site configuration validator reports invalid patterns. Keep logging accesses to
+		// the previously configured repositories rather than none.
+		log15.Error("Invalid repoAccessLog.repositories pattern in site configuration.", "error", err)
+		return compiled
+	}
+	patterns, compiled = current, re
+	return compiled
+}
+
+func compile(patterns []string) (*regexp.Regexp, error) {
+	if len(patterns) == 0 {
+		return nil, nil
+	}
+	parts := make([]string, 0, len(patterns))
+	for _, p := range patterns {
+		if _, err := regexp.Compile(p); err != nil {
+			return nil, err
+		}
+		parts = append(parts, "(?:"+p+")")
+	}
+	return regexp.Compile(strings.Join(parts, "|"))
+}
+
+func init() {
+	conf.ContributeValidator(func(c conf.Unified) (problems conf.Problems) {
+		if c.RepoAccessLog == nil {
+			return nil
+		}
+		for _, p := range c.RepoAccessLog.Repositories {
+			if _, err := regexp.Compile(p); err != nil {
+				problems = append(problems, conf.NewSiteProblem("Invalid repoAccessLog.repositories pattern: "+err.Error()))
+			}
+		}
+		return problems
+	})
+}
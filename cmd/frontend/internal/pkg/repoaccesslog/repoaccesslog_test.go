@@ -0,0 +1,68 @@
+package repoaccesslog
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func mockRepositories(patterns ...string) func() {
+	conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{
+		RepoAccessLog: &schema.RepoAccessLog{Repositories: patterns},
+	}})
+	return func() { conf.Mock(nil) }
+}
+
+func TestEnabled(t *testing.T) {
+	defer mockRepositories(`^github\.com/acme/secret-`, `^gitlab\.com/finance/`)()
+
+	tests := map[api.RepoName]bool{
+		"github.com/acme/secret-sauce":             true,
+		"gitlab.com/finance/ledger":                true,
+		"github.com/acme/public":                   false,
+		"example.com/github.com/acme/secret-sauce": false,
+	}
+	for repo, want := range tests {
+		if got := Enabled(repo); got != want {
+			t.Errorf("%s: got %v, want %v", repo, got, want)
+		}
+	}
+}
+
+func TestEnabled_noConfig(t *testing.T) {
+	conf.Mock(&conf.Unified{})
+	defer conf.Mock(nil)
+
+	if Enabled("github.com/acme/secret-sauce") {
+		t.Error("want accesses not to be logged without configuration")
+	}
+}
+
+func TestLogSearch(t *testing.T) {
+	defer mockRepositories(`secret`)()
+
+	var logged []*db.RepoAccessLogEntry
+	db.Mocks.RepoAccessLogs.Insert = func(e *db.RepoAccessLogEntry) error {
+		logged = append(logged, e)
+		return nil
+	}
+	defer func() { db.Mocks.RepoAccessLogs = db.MockRepoAccessLogs{} }()
+
+	secret := &types.Repo{ID: 1, Name: "github.com/acme/secret"}
+	public := &types.Repo{ID: 2, Name: "github.com/acme/public"}
+	ctx := actor.WithActor(context.Background(), &actor.Actor{UID: 3})
+	LogSearch(ctx, "password", []*types.Repo{secret, public, secret})
+
+	if len(logged) != 1 {
+		t.Fatalf("got %d entries, want 1", len(logged))
+	}
+	if e := logged[0]; e.RepoID != 1 || e.UserID != 3 || e.Kind != KindSearch || e.Query != "password" {
+		t.Errorf("unexpected entry %+v", e)
+	}
+}
@@ -0,0 +1,67 @@
+package pushwebhooks
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketcloud"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// bitbucketCloudPushEvent contains the fields of Bitbucket Cloud repo:push events that we need.
+type bitbucketCloudPushEvent struct {
+	Repository struct {
+		UUID string `json:"uuid"`
+	} `json:"repository"`
+}
+
+// parseBitbucketCloudWebhook parses a Bitbucket Cloud repo:push event, which is also sent for
+// pushes of tags.
+//
+// The webhook must be created manually, with a URL that contains the "webhookSecret" of the
+// Bitbucket Cloud external service config in the "secret" query parameter. Bitbucket Cloud
+// doesn't sign webhook payloads, so there is no other way to authenticate them.
+func parseBitbucketCloudWebhook(r *http.Request, payload []byte) ([]api.ExternalRepoSpec, *httpError) {
+	svcs, err := listExternalServices(r.Context(), "BITBUCKETCLOUD", func() interface{} { return &schema.BitbucketCloudConnection{} })
+	if err != nil {
+		return nil, &httpError{http.StatusInternalServerError, err}
+	}
+
+	// 🚨 SECURITY: Compare the secret in constant time to every stored secret.
+	secret := []byte(r.URL.Query().Get("secret"))
+	var con *schema.BitbucketCloudConnection
+	for _, svc := range svcs {
+		c := svc.config.(*schema.BitbucketCloudConnection)
+		if c.WebhookSecret != "" && subtle.ConstantTimeCompare(secret, []byte(c.WebhookSecret)) == 1 {
+			con = c
+			break
+		}
+	}
+	if con == nil {
+		return nil, &httpError{http.StatusUnauthorized, errors.New("invalid Bitbucket Cloud webhook secret")}
+	}
+
+	if r.Header.Get("X-Event-Key") != "repo:push" {
+		return nil, nil // Nothing to do
+	}
+	var e bitbucketCloudPushEvent
+	if err := json.Unmarshal(payload, &e); err != nil {
+		return nil, &httpError{http.StatusBadRequest, errors.Wrap(err, "parsing webhook")}
+	}
+	if e.Repository.UUID == "" {
+		return nil, &httpError{http.StatusBadRequest, errors.New("push event has no repository UUID")}
+	}
+
+	id, err := serviceID(con.Url)
+	if err != nil {
+		return nil, &httpError{http.StatusInternalServerError, err}
+	}
+	return []api.ExternalRepoSpec{{
+		ID:          e.Repository.UUID,
+		ServiceType: bitbucketcloud.ServiceType,
+		ServiceID:   id,
+	}}, nil
+}
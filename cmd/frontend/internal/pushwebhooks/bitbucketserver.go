@@ -0,0 +1,86 @@
+package pushwebhooks
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	gh "github.com/google/go-github/v28/github"
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	bbs "github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketserver"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+const externalServiceIDParam = "externalServiceID"
+
+// bitbucketServerPushEvent contains the fields of Bitbucket Server repo:refs_changed events that
+// we need.
+type bitbucketServerPushEvent struct {
+	Repository struct {
+		ID int `json:"id"`
+	} `json:"repository"`
+}
+
+// parseBitbucketServerWebhook parses a Bitbucket Server repo:refs_changed event, which is sent
+// for pushes of branches and tags.
+//
+// The webhook is created by repo-updater (see repos.RunPushWebhookSyncWorker) through the Bitbucket
+// Server Sourcegraph plugin, with the webhook secret of the Bitbucket Server external service
+// config.
+func parseBitbucketServerWebhook(r *http.Request, payload []byte) ([]api.ExternalRepoSpec, *httpError) {
+	var externalServiceID int64
+	if rawID := r.FormValue(externalServiceIDParam); rawID != "" {
+		var err error
+		externalServiceID, err = strconv.ParseInt(rawID, 10, 64)
+		if err != nil {
+			return nil, &httpError{http.StatusBadRequest, errors.Wrap(err, "invalid external service id")}
+		}
+	}
+
+	svcs, err := listExternalServices(r.Context(), "BITBUCKETSERVER", func() interface{} { return &schema.BitbucketServerConnection{} })
+	if err != nil {
+		return nil, &httpError{http.StatusInternalServerError, err}
+	}
+
+	// 🚨 SECURITY: Authenticate the request with the secret of the external service that the
+	// webhook was created for, or any stored secret if the webhook URL doesn't name one.
+	sig := r.Header.Get("X-Hub-Signature")
+	var con *schema.BitbucketServerConnection
+	for _, svc := range svcs {
+		if externalServiceID != 0 && svc.id != externalServiceID {
+			continue
+		}
+		c := svc.config.(*schema.BitbucketServerConnection)
+		if secret := c.WebhookSecret(); secret != "" {
+			if err = gh.ValidateSignature(sig, payload, []byte(secret)); err == nil {
+				con = c
+				break
+			}
+		}
+	}
+	if con == nil {
+		return nil, &httpError{http.StatusUnauthorized, err}
+	}
+
+	if bbs.WebhookEventType(r) != "repo:refs_changed" {
+		return nil, nil // Nothing to do
+	}
+	var e bitbucketServerPushEvent
+	if err := json.Unmarshal(payload, &e); err != nil {
+		return nil, &httpError{http.StatusBadRequest, errors.Wrap(err, "parsing webhook")}
+	}
+	if e.Repository.ID == 0 {
+		return nil, &httpError{http.StatusBadRequest, errors.New("push event has no repository ID")}
+	}
+
+	id, err := serviceID(con.Url)
+	if err != nil {
+		return nil, &httpError{http.StatusInternalServerError, err}
+	}
+	return []api.ExternalRepoSpec{{
+		ID:          strconv.Itoa(e.Repository.ID),
+		ServiceType: bbs.ServiceType,
+		ServiceID:   id,
+	}}, nil
+}
@@ -0,0 +1,69 @@
+package pushwebhooks
+
+import (
+	"net/http"
+
+	gh "github.com/google/go-github/v28/github"
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// parseGitHubWebhook parses a GitHub push event. Pushes of tags are push events, too.
+//
+// The organization webhooks are created by repo-updater (see repos.RunPushWebhookSyncWorker), with
+// the secret of the "webhooks" entry of the GitHub external service config for the organization.
+func parseGitHubWebhook(r *http.Request, payload []byte) ([]api.ExternalRepoSpec, *httpError) {
+	svcs, err := listExternalServices(r.Context(), "GITHUB", func() interface{} { return &schema.GitHubConnection{} })
+	if err != nil {
+		return nil, &httpError{http.StatusInternalServerError, err}
+	}
+
+	// 🚨 SECURITY: Try to authenticate the request with any of the stored secrets in GitHub
+	// external services config. If there are no secrets or no secret managed to authenticate the
+	// request, we return a 401 to the client.
+	sig := r.Header.Get("X-Hub-Signature")
+	var con *schema.GitHubConnection
+	for _, svc := range svcs {
+		c := svc.config.(*schema.GitHubConnection)
+		for _, hook := range c.Webhooks {
+			if hook.Secret == "" {
+				continue
+			}
+			if err = gh.ValidateSignature(sig, payload, []byte(hook.Secret)); err == nil {
+				con = c
+				break
+			}
+		}
+		if con != nil {
+			break
+		}
+	}
+	if con == nil {
+		return nil, &httpError{http.StatusUnauthorized, err}
+	}
+
+	e, err := gh.ParseWebHook(gh.WebHookType(r), payload)
+	if err != nil {
+		return nil, &httpError{http.StatusBadRequest, err}
+	}
+	push, ok := e.(*gh.PushEvent)
+	if !ok {
+		// Nothing to do for pings and events that the webhook is subscribed to for campaigns.
+		return nil, nil
+	}
+	if push.GetRepo().GetNodeID() == "" {
+		return nil, &httpError{http.StatusBadRequest, errors.New("push event has no repository node ID")}
+	}
+
+	id, err := serviceID(con.Url)
+	if err != nil {
+		return nil, &httpError{http.StatusInternalServerError, err}
+	}
+	return []api.ExternalRepoSpec{{
+		ID:          push.GetRepo().GetNodeID(),
+		ServiceType: github.ServiceType,
+		ServiceID:   id,
+	}}, nil
+}
@@ -0,0 +1,73 @@
+package pushwebhooks
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitlab"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// gitLabPushEvent contains the fields of GitLab push and tag push events that we need.
+type gitLabPushEvent struct {
+	ObjectKind string `json:"object_kind"`
+	ProjectID  int    `json:"project_id"`
+}
+
+// parseGitLabWebhook parses a GitLab push or tag push event, sent by a project, group or system
+// hook.
+//
+// The project hooks are created by repo-updater (see repos.RunPushWebhookSyncWorker), with the
+// secret token of the first of the "webhooks" in the GitLab external service config. Group and
+// system hooks must be created manually, with the secret token of any of the "webhooks".
+func parseGitLabWebhook(r *http.Request, payload []byte) ([]api.ExternalRepoSpec, *httpError) {
+	svcs, err := listExternalServices(r.Context(), "GITLAB", func() interface{} { return &schema.GitLabConnection{} })
+	if err != nil {
+		return nil, &httpError{http.StatusInternalServerError, err}
+	}
+
+	// 🚨 SECURITY: GitLab sends the secret token as is, so compare it in constant time to
+	// every stored secret.
+	token := []byte(r.Header.Get("X-Gitlab-Token"))
+	var con *schema.GitLabConnection
+	for _, svc := range svcs {
+		c := svc.config.(*schema.GitLabConnection)
+		for _, hook := range c.Webhooks {
+			if hook.Secret != "" && subtle.ConstantTimeCompare(token, []byte(hook.Secret)) == 1 {
+				con = c
+				break
+			}
+		}
+		if con != nil {
+			break
+		}
+	}
+	if con == nil {
+		return nil, &httpError{http.StatusUnauthorized, errors.New("invalid GitLab webhook token")}
+	}
+
+	var e gitLabPushEvent
+	if err := json.Unmarshal(payload, &e); err != nil {
+		return nil, &httpError{http.StatusBadRequest, errors.Wrap(err, "parsing webhook")}
+	}
+	if e.ObjectKind != "push" && e.ObjectKind != "tag_push" {
+		return nil, nil // Nothing to do
+	}
+	if e.ProjectID == 0 {
+		return nil, &httpError{http.StatusBadRequest, errors.New("push event has no project ID")}
+	}
+
+	id, err := serviceID(con.Url)
+	if err != nil {
+		return nil, &httpError{http.StatusInternalServerError, err}
+	}
+	return []api.ExternalRepoSpec{{
+		ID:          strconv.Itoa(e.ProjectID),
+		ServiceType: gitlab.ServiceType,
+		ServiceID:   id,
+	}}, nil
+}
@@ -0,0 +1,173 @@
+// Package pushwebhooks receives push webhooks from code hosts and enqueues an update of the
+// repositories that were pushed to, so that pushes become searchable without waiting for the
+// next scheduled poll of the repository.
+package pushwebhooks
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+
+	"github.com/gorilla/mux"
+	"github.com/hashicorp/go-multierror"
+	"github.com/inconshreveable/log15"
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/errcode"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/jsonc"
+	"github.com/sourcegraph/sourcegraph/internal/repoupdater"
+)
+
+// CodeHostVar is the name of the route variable that identifies the code host that sent the
+// webhook (see codeHosts).
+const CodeHostVar = "codeHost"
+
+// maxPayloadSize is the maximum size of a webhook payload that is read. GitHub caps payloads at
+// 25 MB, but push events without huge commit lists are much smaller.
+const maxPayloadSize = 5 << 20
+
+// A parseFunc authenticates a webhook request and returns the external repository specs of the
+// repositories that were pushed to. It returns no specs for events that don't change any
+// repository (such as pings).
+type parseFunc func(r *http.Request, payload []byte) ([]api.ExternalRepoSpec, *httpError)
+
+// codeHosts maps the value of CodeHostVar to the parser of the code host's webhooks.
+var codeHosts = map[string]parseFunc{
+	"github":          parseGitHubWebhook,
+	"gitlab":          parseGitLabWebhook,
+	"bitbucketserver": parseBitbucketServerWebhook,
+	"bitbucketcloud":  parseBitbucketCloudWebhook,
+}
+
+// ServeHTTP serves a push webhook sent by the code host named by the CodeHostVar route
+// variable.
+//
+// 🚨 SECURITY: Requests to this handler are not authenticated by the caller. Each code host's
+// parseFunc authenticates the request with the webhook secret from the external service config.
+func ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	parse, ok := codeHosts[mux.Vars(r)[CodeHostVar]]
+	if !ok {
+		respond(w, http.StatusNotFound, nil)
+		return
+	}
+
+	// 🚨 SECURITY: Limit the size of the payload, which is read before the request is
+	// authenticated.
+	payload, err := ioutil.ReadAll(http.MaxBytesReader(w, r.Body, maxPayloadSize))
+	if err != nil {
+		if len(payload) >= maxPayloadSize {
+			respond(w, http.StatusRequestEntityTooLarge, err)
+			return
+		}
+		respond(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	specs, hErr := parse(r, payload)
+	if hErr != nil {
+		respond(w, hErr.code, hErr)
+		return
+	}
+
+	// The webhook isn't sent on behalf of a user, and the repositories it refers to may be
+	// private.
+	ctx := actor.WithActor(r.Context(), &actor.Actor{Internal: true})
+
+	m := new(multierror.Error)
+	for _, spec := range specs {
+		if err := enqueueRepoUpdate(ctx, spec); err != nil {
+			m = multierror.Append(m, err)
+		}
+	}
+	if m.ErrorOrNil() != nil {
+		respond(w, http.StatusInternalServerError, m)
+		return
+	}
+	respond(w, http.StatusOK, nil)
+}
+
+// enqueueRepoUpdate asks repo-updater to update the repository identified by spec with high
+// priority. Repositories that aren't mirrored by Sourcegraph are ignored.
+func enqueueRepoUpdate(ctx context.Context, spec api.ExternalRepoSpec) error {
+	repo, err := db.Repos.GetByExternalRepo(ctx, spec)
+	if errcode.IsNotFound(err) {
+		log15.Debug("Push webhook could not be matched to a repository", "externalRepo", spec)
+		return nil
+	}
+	if err != nil {
+		return errors.Wrap(err, "getting repository")
+	}
+
+	// The URL is left empty so that repo-updater uses the repository's clone URL.
+	_, err = repoupdater.DefaultClient.EnqueueRepoUpdate(ctx, gitserver.Repo{Name: repo.Name})
+	return errors.Wrapf(err, "enqueueing update of repository %q", repo.Name)
+}
+
+// externalService is an external service with its decoded config.
+type externalService struct {
+	id     int64
+	config interface{}
+}
+
+// listExternalServices returns the external services of the given kind, with their configs
+// decoded into values of the type returned by newConfig.
+//
+// 🚨 SECURITY: The configs contain secrets. They must only be used to authenticate webhooks.
+func listExternalServices(ctx context.Context, kind string, newConfig func() interface{}) ([]externalService, error) {
+	services, err := db.ExternalServices.List(ctx, db.ExternalServicesListOptions{Kinds: []string{kind}})
+	if err != nil {
+		return nil, err
+	}
+
+	svcs := make([]externalService, 0, len(services))
+	for _, service := range services {
+		config := newConfig()
+		if err := jsonc.Unmarshal(service.Config, config); err != nil {
+			log15.Error("Ignoring external service config that has invalid JSON", "id", service.ID, "err", err)
+			continue
+		}
+		svcs = append(svcs, externalService{id: service.ID, config: config})
+	}
+	return svcs, nil
+}
+
+// serviceID returns the api.ExternalRepoSpec ServiceID of repositories of the code host at
+// baseURL.
+func serviceID(baseURL string) (string, error) {
+	u, err := url.Parse(baseURL)
+	if err != nil {
+		return "", errors.Wrap(err, "parsing code host URL")
+	}
+	return extsvc.NormalizeBaseURL(u).String(), nil
+}
+
+type httpError struct {
+	code int
+	err  error
+}
+
+func (e httpError) Error() string {
+	if e.err != nil {
+		return fmt.Sprintf("HTTP %d: %v", e.code, e.err)
+	}
+	return fmt.Sprintf("HTTP %d: %s", e.code, http.StatusText(e.code))
+}
+
+func respond(w http.ResponseWriter, code int, err error) {
+	if err == nil {
+		w.WriteHeader(code)
+		return
+	}
+	if code >= http.StatusInternalServerError {
+		log15.Error("Serving push webhook failed", "err", err)
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(code)
+	fmt.Fprintf(w, "%v", err)
+}
@@ -0,0 +1,192 @@
+package pushwebhooks
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/gorilla/mux"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/repoupdater"
+	"github.com/sourcegraph/sourcegraph/internal/repoupdater/protocol"
+)
+
+func sign(payload, secret string) string {
+	mac := hmac.New(sha1.New, []byte(secret))
+	mac.Write([]byte(payload))
+	return "sha1=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestServeHTTP(t *testing.T) {
+	services := map[string]string{
+		"GITHUB":          `{"url": "https://github.com", "webhooks": [{"org": "o", "secret": "gh-secret"}]}`,
+		"GITLAB":          `{"url": "https://gitlab.example.com/", "webhooks": [{"secret": "gl-secret"}]}`,
+		"BITBUCKETSERVER": `{"url": "https://bbs.example.com", "plugin": {"webhooks": {"secret": "bbs-secret"}}}`,
+		"BITBUCKETCLOUD":  `{"url": "https://bitbucket.org", "webhookSecret": "bbc-secret"}`,
+	}
+	db.Mocks.ExternalServices.List = func(opt db.ExternalServicesListOptions) ([]*types.ExternalService, error) {
+		return []*types.ExternalService{{ID: 1, Kind: opt.Kinds[0], Config: services[opt.Kinds[0]]}}, nil
+	}
+	db.Mocks.Repos.GetByExternalRepo = func(ctx context.Context, spec api.ExternalRepoSpec) (*types.Repo, error) {
+		return &types.Repo{Name: api.RepoName(spec.ServiceType + "/" + spec.ID)}, nil
+	}
+	var enqueued []api.RepoName
+	repoupdater.MockEnqueueRepoUpdate = func(ctx context.Context, repo gitserver.Repo) (*protocol.RepoUpdateResponse, error) {
+		enqueued = append(enqueued, repo.Name)
+		return &protocol.RepoUpdateResponse{}, nil
+	}
+	defer func() {
+		db.Mocks = db.MockStores{}
+		repoupdater.MockEnqueueRepoUpdate = nil
+	}()
+
+	tests := []struct {
+		name     string
+		codeHost string
+		query    string
+		header   map[string]string
+		payload  string
+		wantCode int
+		want     []api.RepoName
+	}{
+		{
+			name:     "github push",
+			codeHost: "github",
+			header:   map[string]string{"X-GitHub-Event": "push", "X-Hub-Signature": sign(`{"repository": {"node_id": "MDEw"}}`, "gh-secret")},
+			payload:  `{"repository": {"node_id": "MDEw"}}`,
+			wantCode: http.StatusOK,
+			want:     []api.RepoName{"github/MDEw"},
+		},
+		{
+			name:     "github ping",
+			codeHost: "github",
+			header:   map[string]string{"X-GitHub-Event": "ping", "X-Hub-Signature": sign(`{}`, "gh-secret")},
+			payload:  `{}`,
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "github bad signature",
+			codeHost: "github",
+			header:   map[string]string{"X-GitHub-Event": "push", "X-Hub-Signature": sign(`{"repository": {"node_id": "MDEw"}}`, "wrong")},
+			payload:  `{"repository": {"node_id": "MDEw"}}`,
+			wantCode: http.StatusUnauthorized,
+		},
+		{
+			name:     "gitlab tag push",
+			codeHost: "gitlab",
+			header:   map[string]string{"X-Gitlab-Event": "Tag Push Hook", "X-Gitlab-Token": "gl-secret"},
+			payload:  `{"object_kind": "tag_push", "project_id": 15}`,
+			wantCode: http.StatusOK,
+			want:     []api.RepoName{"gitlab/15"},
+		},
+		{
+			name:     "gitlab bad token",
+			codeHost: "gitlab",
+			header:   map[string]string{"X-Gitlab-Event": "Push Hook", "X-Gitlab-Token": "wrong"},
+			payload:  `{"object_kind": "push", "project_id": 15}`,
+			wantCode: http.StatusUnauthorized,
+		},
+		{
+			name:     "bitbucket server push",
+			codeHost: "bitbucketserver",
+			query:    "?externalServiceID=1",
+			header:   map[string]string{"X-Event-Key": "repo:refs_changed", "X-Hub-Signature": sign(`{"repository": {"id": 7}}`, "bbs-secret")},
+			payload:  `{"repository": {"id": 7}}`,
+			wantCode: http.StatusOK,
+			want:     []api.RepoName{"bitbucketServer/7"},
+		},
+		{
+			name:     "bitbucket server other external service",
+			codeHost: "bitbucketserver",
+			query:    "?externalServiceID=2",
+			header:   map[string]string{"X-Event-Key": "repo:refs_changed", "X-Hub-Signature": sign(`{"repository": {"id": 7}}`, "bbs-secret")},
+			payload:  `{"repository": {"id": 7}}`,
+			wantCode: http.StatusUnauthorized,
+		},
+		{
+			name:     "bitbucket cloud push",
+			codeHost: "bitbucketcloud",
+			query:    "?secret=bbc-secret",
+			header:   map[string]string{"X-Event-Key": "repo:push"},
+			payload:  `{"repository": {"uuid": "{a-b}"}}`,
+			wantCode: http.StatusOK,
+			want:     []api.RepoName{"bitbucketCloud/{a-b}"},
+		},
+		{
+			name:     "bitbucket cloud missing secret",
+			codeHost: "bitbucketcloud",
+			header:   map[string]string{"X-Event-Key": "repo:push"},
+			payload:  `{"repository": {"uuid": "{a-b}"}}`,
+			wantCode: http.StatusUnauthorized,
+		},
+		{
+			name:     "unknown code host",
+			codeHost: "phabricator",
+			wantCode: http.StatusNotFound,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			enqueued = nil
+
+			req := httptest.NewRequest("POST", "/.api/repo-update-webhooks/"+test.codeHost+test.query, strings.NewReader(test.payload))
+			for k, v := range test.header {
+				req.Header.Set(k, v)
+			}
+			req = mux.SetURLVars(req, map[string]string{CodeHostVar: test.codeHost})
+			rec := httptest.NewRecorder()
+			ServeHTTP(rec, req)
+
+			if rec.Code != test.wantCode {
+				t.Errorf("got status %d, want %d (body: %s)", rec.Code, test.wantCode, rec.Body.String())
+			}
+			if diff := cmp.Diff(test.want, enqueued); diff != "" {
+				t.Errorf("enqueued repos mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestServeHTTP_PayloadTooLarge(t *testing.T) {
+	db.Mocks.ExternalServices.List = func(opt db.ExternalServicesListOptions) ([]*types.ExternalService, error) {
+		t.Error("the request must be rejected before it is authenticated")
+		return nil, nil
+	}
+	defer func() { db.Mocks = db.MockStores{} }()
+
+	req := httptest.NewRequest("POST", "/.api/repo-update-webhooks/github", strings.NewReader(strings.Repeat(" ", maxPayloadSize+1)))
+	req = mux.SetURLVars(req, map[string]string{CodeHostVar: "github"})
+	rec := httptest.NewRecorder()
+	ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("got status %d, want %d", rec.Code, http.StatusRequestEntityTooLarge)
+	}
+}
+
+func TestServeHTTP_ServiceID(t *testing.T) {
+	db.Mocks.ExternalServices.List = func(opt db.ExternalServicesListOptions) ([]*types.ExternalService, error) {
+		return []*types.ExternalService{{ID: 1, Kind: "GITLAB", Config: `{"url": "https://gitlab.example.com", "webhooks": [{"secret": "s"}]}`}}, nil
+	}
+	defer func() { db.Mocks = db.MockStores{} }()
+
+	req := httptest.NewRequest("POST", "/", strings.NewReader(`{"object_kind": "push", "project_id": 15}`))
+	req.Header.Set("X-Gitlab-Token", "s")
+	specs, hErr := parseGitLabWebhook(req, []byte(`{"object_kind": "push", "project_id": 15}`))
+	if hErr != nil {
+		t.Fatal(hErr)
+	}
+	want := []api.ExternalRepoSpec{{ID: "15", ServiceType: "gitlab", ServiceID: "https://gitlab.example.com/"}}
+	if diff := cmp.Diff(want, specs); diff != "" {
+		t.Errorf("specs mismatch (-want +got):\n%s", diff)
+	}
+}
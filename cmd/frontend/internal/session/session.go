@@ -12,6 +12,7 @@ import (
 
 	"github.com/pkg/errors"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/internal/pkg/handlerutil"
 	"github.com/sourcegraph/sourcegraph/internal/actor"
 	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/env"
@@ -56,6 +57,9 @@ type sessionInfo struct {
 	Actor        *actor.Actor  `json:"actor"`
 	LastActive   time.Time     `json:"lastActive"`
 	ExpiryPeriod time.Duration `json:"expiryPeriod"`
+	// SessionID is the ID of the session record in the database (see db.UserSessions). It is
+	// zero for sessions that were created before sessions were tracked in the database.
+	SessionID int64 `json:"sessionID,omitempty"`
 }
 
 // SetSessionStore sets the backing store used for storing sessions on the server. It should be called exactly once.
@@ -221,6 +225,9 @@ func GetData(r *http.Request, key string, value interface{}) error {
 // new session is created.
 //
 // If expiryPeriod is 0, the default expiry period is used.
+//
+// Sessions with an authenticated actor are recorded in the database so that they can be listed
+// and revoked by user. Removing the actor revokes the record of the current session.
 func SetActor(w http.ResponseWriter, r *http.Request, actor *actor.Actor, expiryPeriod time.Duration) error {
 	var value *sessionInfo
 	if actor != nil {
@@ -232,10 +239,36 @@ func SetActor(w http.ResponseWriter, r *http.Request, actor *actor.Actor, expiry
 			}
 		}
 		value = &sessionInfo{Actor: actor, ExpiryPeriod: expiryPeriod, LastActive: time.Now()}
+
+		if actor.IsAuthenticated() {
+			id, err := db.UserSessions.Create(r.Context(), actor.UID, handlerutil.RemoteIP(r), r.UserAgent(), expiryPeriod)
+			if err != nil {
+				return errors.WithMessage(err, "recording session")
+			}
+			value.SessionID = id
+		}
+	} else {
+		revokeCurrentSession(r)
 	}
 	return SetData(w, r, "actor", value)
 }
 
+// revokeCurrentSession revokes the database record of the session of the request, if any. It is
+// best-effort because the session data is removed from the session store by the caller anyway.
+func revokeCurrentSession(r *http.Request) {
+	if !hasSessionCookie(r) {
+		return
+	}
+
+	var info *sessionInfo
+	if err := GetData(r, "actor", &info); err != nil || info == nil || info.SessionID == 0 || info.Actor == nil {
+		return
+	}
+	if err := db.UserSessions.RevokeByID(r.Context(), info.SessionID, info.Actor.UID); err != nil && err != db.ErrUserSessionNotFound {
+		log15.Error("Error revoking session.", "uid", info.Actor.UID, "sessionID", info.SessionID, "error", err)
+	}
+}
+
 func hasSessionCookie(r *http.Request) bool {
 	c, _ := r.Cookie(cookieName)
 	return c != nil
@@ -339,6 +372,11 @@ func authenticateByCookie(r *http.Request, w http.ResponseWriter) context.Contex
 	if info != nil {
 		// Check expiry
 		if info.LastActive.Add(info.ExpiryPeriod).Before(time.Now()) {
+			if info.SessionID != 0 && info.Actor != nil {
+				if err := db.UserSessions.RevokeByID(r.Context(), info.SessionID, info.Actor.UID); err != nil && err != db.ErrUserSessionNotFound {
+					log15.Error("Error revoking expired session.", "uid", info.Actor.UID, "sessionID", info.SessionID, "error", err)
+				}
+			}
 			_ = deleteSession(w, r) // clear the bad value
 			return actor.WithActor(r.Context(), &actor.Actor{})
 		}
@@ -355,9 +393,37 @@ func authenticateByCookie(r *http.Request, w http.ResponseWriter) context.Contex
 			return r.Context() // not authenticated
 		}
 
+		// Sessions created before sessions were recorded in the database are recorded the first
+		// time they are used, so that they can be listed and revoked like any other session.
+		if info.SessionID == 0 {
+			id, err := db.UserSessions.Create(r.Context(), info.Actor.UID, handlerutil.RemoteIP(r), r.UserAgent(), info.ExpiryPeriod)
+			if err != nil {
+				// Don't delete session, since the error might be an ephemeral DB error.
+				log15.Error("Error recording session.", "uid", info.Actor.UID, "error", err)
+				return r.Context() // not authenticated
+			}
+			info.SessionID = id
+			if err := SetData(w, r, "actor", info); err != nil {
+				log15.Error("Error saving recorded session.", "uid", info.Actor.UID, "sessionID", id, "error", err)
+			}
+		}
+
+		// 🚨 SECURITY: Check that the session has not been revoked.
+		if _, err := db.UserSessions.Lookup(r.Context(), info.SessionID, info.Actor.UID); err != nil {
+			if err == db.ErrUserSessionNotFound {
+				_ = deleteSession(w, r) // clear the revoked value
+			} else {
+				log15.Error("Error looking up session.", "uid", info.Actor.UID, "sessionID", info.SessionID, "error", err)
+			}
+			return r.Context() // not authenticated
+		}
+
 		// Renew session
 		if time.Since(info.LastActive) > 5*time.Minute {
 			info.LastActive = time.Now()
+			if err := db.UserSessions.Touch(r.Context(), info.SessionID); err != nil {
+				log15.Error("error renewing session record", "error", err)
+			}
 			if err := SetData(w, r, "actor", info); err != nil {
 				log15.Error("error renewing session", "error", err)
 				return r.Context()
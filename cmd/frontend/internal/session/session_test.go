@@ -168,6 +168,97 @@ func TestSessionExpiry(t *testing.T) {
 	if gotActor := actor.FromContext(authenticateByCookie(authedReq, httptest.NewRecorder())); !reflect.DeepEqual(gotActor, &actor.Actor{}) {
 		t.Errorf("session didn't expire, found actor %+v", gotActor)
 	}
+
+	// The record of the expired session is revoked, so that it is no longer listed as active.
+	if n, err := db.UserSessions.RevokeByUser(context.Background(), actr.UID); err != nil {
+		t.Fatal(err)
+	} else if n != 0 {
+		t.Errorf("expected expired session record to be revoked, but %d sessions were still active", n)
+	}
+}
+
+func TestSessionRevoked(t *testing.T) {
+	cleanup := ResetMockSessionStore(t)
+	defer cleanup()
+
+	db.Mocks.Users.GetByID = func(ctx context.Context, id int32) (*types.User, error) {
+		return &types.User{ID: id}, nil
+	}
+	defer func() { db.Mocks.Users = db.MockUsers{} }()
+
+	// Start new session
+	w := httptest.NewRecorder()
+	actr := &actor.Actor{UID: 123, FromSessionCookie: true}
+	if err := SetActor(w, httptest.NewRequest("GET", "/", nil), actr, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+
+	// Create authed request with session cookie
+	authedReq := httptest.NewRequest("GET", "/", nil)
+	for _, cookie := range w.Result().Cookies() {
+		if cookie.Expires.After(time.Now()) || cookie.MaxAge > 0 {
+			authedReq.AddCookie(cookie)
+		}
+	}
+
+	if gotActor := actor.FromContext(authenticateByCookie(authedReq, httptest.NewRecorder())); !reflect.DeepEqual(gotActor, actr) {
+		t.Fatalf("didn't find actor %v != %v", gotActor, actr)
+	}
+
+	// Revoke all sessions of the user, as it happens on a password change
+	if n, err := db.UserSessions.RevokeByUser(context.Background(), actr.UID); err != nil {
+		t.Fatal(err)
+	} else if n != 1 {
+		t.Fatalf("got %d revoked sessions, want 1", n)
+	}
+
+	w = httptest.NewRecorder()
+	if gotActor := actor.FromContext(authenticateByCookie(authedReq, w)); !reflect.DeepEqual(gotActor, &actor.Actor{}) {
+		t.Errorf("session wasn't revoked, found actor %+v", gotActor)
+	}
+	checkCookieDeleted(t, w.Result())
+}
+
+func TestSessionWithoutID(t *testing.T) {
+	cleanup := ResetMockSessionStore(t)
+	defer cleanup()
+
+	db.Mocks.Users.GetByID = func(ctx context.Context, id int32) (*types.User, error) {
+		return &types.User{ID: id}, nil
+	}
+	defer func() { db.Mocks.Users = db.MockUsers{} }()
+
+	// Start a session the way it was done before sessions were recorded in the database
+	w := httptest.NewRecorder()
+	info := &sessionInfo{Actor: &actor.Actor{UID: 123}, ExpiryPeriod: time.Hour, LastActive: time.Now().Add(-time.Hour / 2)}
+	if err := SetData(w, httptest.NewRequest("GET", "/", nil), "actor", info); err != nil {
+		t.Fatal(err)
+	}
+
+	authedReq := httptest.NewRequest("GET", "/", nil)
+	for _, cookie := range w.Result().Cookies() {
+		if cookie.Expires.After(time.Now()) || cookie.MaxAge > 0 {
+			authedReq.AddCookie(cookie)
+		}
+	}
+
+	// The session is still valid, and it is recorded the first time it is used.
+	wantActor := &actor.Actor{UID: 123, FromSessionCookie: true}
+	if gotActor := actor.FromContext(authenticateByCookie(authedReq, httptest.NewRecorder())); !reflect.DeepEqual(gotActor, wantActor) {
+		t.Fatalf("didn't find actor %v != %v", gotActor, wantActor)
+	}
+	if n, err := db.UserSessions.RevokeByUser(context.Background(), 123); err != nil {
+		t.Fatal(err)
+	} else if n != 1 {
+		t.Fatalf("got %d revoked sessions, want 1", n)
+	}
+
+	// The recorded session ID was stored in the session, so revoking the record signs it out.
+	w = httptest.NewRecorder()
+	if gotActor := actor.FromContext(authenticateByCookie(authedReq, w)); !reflect.DeepEqual(gotActor, &actor.Actor{}) {
+		t.Errorf("session wasn't revoked, found actor %+v", gotActor)
+	}
+	checkCookieDeleted(t, w.Result())
 }
 
 func TestCookieMiddleware(t *testing.T) {
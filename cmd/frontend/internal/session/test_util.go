@@ -3,10 +3,13 @@ package session
 import (
 	"io/ioutil"
 	"os"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/gorilla/securecookie"
 	"github.com/gorilla/sessions"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
 )
 
 func ResetMockSessionStore(t *testing.T) (cleanup func()) {
@@ -23,7 +26,65 @@ func ResetMockSessionStore(t *testing.T) (cleanup func()) {
 	}()
 
 	SetSessionStore(sessions.NewFilesystemStore(tempdir, securecookie.GenerateRandomKey(2048)))
+	db.Mocks.UserSessions = newMockUserSessions()
 	return func() {
 		os.RemoveAll(tempdir)
+		db.Mocks.UserSessions = db.MockUserSessions{}
+	}
+}
+
+// newMockUserSessions returns mocks for db.UserSessions that keep session records in memory.
+func newMockUserSessions() db.MockUserSessions {
+	var (
+		mu       sync.Mutex
+		nextID   int64
+		sessions = make(map[int64]*db.UserSession)
+	)
+	return db.MockUserSessions{
+		Create: func(userID int32, ip, userAgent string, expiryPeriod time.Duration) (int64, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			nextID++
+			now := time.Now()
+			sessions[nextID] = &db.UserSession{ID: nextID, UserID: userID, IP: ip, UserAgent: userAgent, CreatedAt: now, LastActiveAt: now, ExpiryPeriod: expiryPeriod}
+			return nextID, nil
+		},
+		Lookup: func(id int64, userID int32) (*db.UserSession, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			if s, ok := sessions[id]; ok && s.UserID == userID {
+				return s, nil
+			}
+			return nil, db.ErrUserSessionNotFound
+		},
+		Touch: func(id int64) error {
+			mu.Lock()
+			defer mu.Unlock()
+			if s, ok := sessions[id]; ok {
+				s.LastActiveAt = time.Now()
+			}
+			return nil
+		},
+		RevokeByID: func(id int64, userID int32) error {
+			mu.Lock()
+			defer mu.Unlock()
+			if s, ok := sessions[id]; ok && s.UserID == userID {
+				delete(sessions, id)
+				return nil
+			}
+			return db.ErrUserSessionNotFound
+		},
+		RevokeByUser: func(userID int32) (int64, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			var n int64
+			for id, s := range sessions {
+				if s.UserID == userID {
+					delete(sessions, id)
+					n++
+				}
+			}
+			return n, nil
+		},
 	}
 }
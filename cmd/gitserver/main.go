@@ -28,6 +28,10 @@ var (
 	runRepoCleanup, _ = strconv.ParseBool(env.Get("SRC_RUN_REPO_CLEANUP", "", "Periodically remove inactive repositories."))
 	wantPctFree       = env.Get("SRC_REPOS_DESIRED_PERCENT_FREE", "10", "Target percentage of free space on disk.")
 	janitorInterval   = env.Get("SRC_REPOS_JANITOR_INTERVAL", "1m", "Interval between cleanup runs")
+	maintenanceConc   = env.Get("SRC_REPOS_MAINTENANCE_CONCURRENCY", "1", "Maximum number of repositories git maintenance runs on at the same time.")
+	lfsMaxObjectMB    = env.Get("SRC_REPOS_LFS_MAX_OBJECT_MB", "100", "Size in MB of the largest Git LFS object fetched. 0 means no limit.")
+	lfsMaxRepoMB      = env.Get("SRC_REPOS_LFS_MAX_REPO_MB", "2048", "Maximum size in MB of the Git LFS objects fetched per repository. 0 means no limit.")
+	execCacheMB       = env.Get("SRC_REPOS_EXEC_CACHE_MB", "1024", "Maximum size in MB of the cached output of git commands at absolute commits. 0 disables the cache.")
 )
 
 func main() {
@@ -46,10 +50,35 @@ func main() {
 	if err != nil {
 		log.Fatalf("parsing $SRC_REPOS_DESIRED_PERCENT_FREE: %v", err)
 	}
+	maintenanceConc2, err := strconv.Atoi(maintenanceConc)
+	if err != nil || maintenanceConc2 < 1 {
+		log.Fatalf("parsing $SRC_REPOS_MAINTENANCE_CONCURRENCY: must be a positive integer, got %q", maintenanceConc)
+	}
+	lfsMaxObjectMB2, err := strconv.ParseInt(lfsMaxObjectMB, 10, 64)
+	if err != nil || lfsMaxObjectMB2 < 0 {
+		log.Fatalf("parsing $SRC_REPOS_LFS_MAX_OBJECT_MB: must be a non-negative integer, got %q", lfsMaxObjectMB)
+	}
+	lfsMaxRepoMB2, err := strconv.ParseInt(lfsMaxRepoMB, 10, 64)
+	if err != nil || lfsMaxRepoMB2 < 0 {
+		log.Fatalf("parsing $SRC_REPOS_LFS_MAX_REPO_MB: must be a non-negative integer, got %q", lfsMaxRepoMB)
+	}
+	execCacheMB2, err := strconv.ParseInt(execCacheMB, 10, 64)
+	if err != nil || execCacheMB2 < 0 {
+		log.Fatalf("parsing $SRC_REPOS_EXEC_CACHE_MB: must be a non-negative integer, got %q", execCacheMB)
+	}
+	hostname, err := os.Hostname()
+	if err != nil {
+		log.Fatalf("failed to get hostname: %s", err)
+	}
 	gitserver := server.Server{
 		ReposDir:                reposDir,
+		Hostname:                hostname,
 		DeleteStaleRepositories: runRepoCleanup,
 		DesiredPercentFree:      wantPctFree2,
+		MaintenanceConcurrency:  maintenanceConc2,
+		LFSMaxObjectSize:        lfsMaxObjectMB2 << 20,
+		LFSMaxRepoSize:          lfsMaxRepoMB2 << 20,
+		ExecCacheMaxSize:        execCacheMB2 << 20,
 	}
 	gitserver.RegisterMetrics()
 
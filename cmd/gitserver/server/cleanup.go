@@ -1,12 +1,12 @@
 package server
 
 import (
-	"bytes"
 	"context"
 	"fmt"
 	"hash/fnv"
 	"io"
 	"io/ioutil"
+	"net"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -19,6 +19,9 @@ import (
 	"github.com/hashicorp/go-multierror"
 	"github.com/pkg/errors"
 	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
 	"github.com/sourcegraph/sourcegraph/internal/lazyregexp"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -31,14 +34,6 @@ func init() {
 	prometheus.MustRegister(reposRecloned)
 }
 
-const (
-	// repoTTL is how often we should reclone a repository
-	repoTTL = time.Hour * 24 * 45
-	// repoTTLGC is how often we should reclone a repository once it is
-	// reporting git gc issues.
-	repoTTLGC = time.Hour * 24 * 2
-)
-
 var reposRemoved = prometheus.NewCounter(prometheus.CounterOpts{
 	Name: "src_gitserver_repos_removed",
 	Help: "number of repos removed during cleanup",
@@ -46,7 +41,7 @@ var reposRemoved = prometheus.NewCounter(prometheus.CounterOpts{
 
 var reposRecloned = prometheus.NewCounter(prometheus.CounterOpts{
 	Name: "src_gitserver_repos_recloned",
-	Help: "number of repos removed and recloned due to age",
+	Help: "number of repos removed and recloned due to corruption or failing maintenance",
 })
 
 // cleanupRepos walks the repos directory and performs maintenance tasks:
@@ -54,7 +49,8 @@ var reposRecloned = prometheus.NewCounter(prometheus.CounterOpts{
 // 1. Remove corrupt repos.
 // 2. Remove stale lock files.
 // 3. Remove inactive repos on sourcegraph.com
-// 4. Reclone repos after a while. (simulate git gc)
+// 4. Run incremental git maintenance, or reclone repos which are corrupt or
+//    keep failing maintenance.
 func (s *Server) cleanupRepos() {
 	bCtx, bCancel := s.serverContext()
 	defer bCancel()
@@ -75,33 +71,53 @@ func (s *Server) cleanupRepos() {
 		return true, nil
 	}
 
+	recordSize := func(dir GitDir) (done bool, err error) {
+		repo := s.name(dir)
+		if !s.repoSizeStale(repo) {
+			return false, nil
+		}
+		size, err := dirSize(string(dir))
+		if err != nil {
+			return false, err
+		}
+		s.recordRepoSize(repo, size)
+		return false, nil
+	}
+
 	ensureGitAttributes := func(dir GitDir) (done bool, err error) {
 		return false, setGitAttributes(dir)
 	}
 
-	maybeReclone := func(dir GitDir) (done bool, err error) {
-		recloneTime, err := getRecloneTime(dir)
+	maybeMaintainOrReclone := func(dir GitDir) (done bool, err error) {
+		state, err := getMaintenanceState(dir)
 		if err != nil {
 			return false, err
 		}
 
-		// Add a jitter to spread out recloning of repos cloned at the same
-		// time.
 		var reason string
 		if maybeCorrupt, _ := gitConfigGet(dir, "sourcegraph.maybeCorruptRepo"); maybeCorrupt != "" {
 			reason = "maybeCorrupt"
 			// unset flag to stop constantly recloning if it fails.
 			_ = gitConfigUnset(dir, "sourcegraph.maybeCorruptRepo")
 		}
-		if time.Since(recloneTime) > repoTTL+jitterDuration(string(dir), repoTTL/4) {
-			reason = "old"
-		}
-		if time.Since(recloneTime) > repoTTLGC+jitterDuration(string(dir), repoTTLGC/4) {
-			if gclog, err := ioutil.ReadFile(dir.Path("gc.log")); err == nil && len(gclog) > 0 {
-				reason = fmt.Sprintf("git gc %s", string(bytes.TrimSpace(gclog)))
-			}
+		if state.Failures >= maxMaintenanceFailures {
+			reason = fmt.Sprintf("%d failed maintenance runs", state.Failures)
+			// reset the failures so that we don't constantly reclone if
+			// cloning fails. For example if a repo fails to clone due to
+			// being large, we will constantly be doing a clone which uses up
+			// lots of resources.
+			_ = gitConfigUnset(dir, maintenanceConfigPrefix+"failures")
 		}
 		if reason == "" {
+			// Repositories we don't replicate (anymore) are not worth
+			// maintaining. They are the first to be removed to free up
+			// space.
+			if s.ownsRepo(s.name(dir)) && len(dueMaintenanceTasks(dir, state, time.Now())) > 0 {
+				// Maintenance can take a long time, so it runs in the
+				// background instead of holding up the cleanup of the other
+				// repositories.
+				s.queueMaintenance(dir)
+			}
 			return false, nil
 		}
 
@@ -110,22 +126,19 @@ func (s *Server) cleanupRepos() {
 
 		// name is the relative path to ReposDir, but without the .git suffix.
 		repo := s.name(dir)
-		log15.Info("recloning expired repo", "repo", repo, "cloned", recloneTime, "reason", reason)
-
-		// update the reclone time so that we don't constantly reclone if
-		// cloning fails. For example if a repo fails to clone due to being
-		// large, we will constantly be doing a clone which uses up lots of
-		// resources.
-		if err := setRecloneTime(dir, recloneTime.Add(time.Since(recloneTime)/2)); err != nil {
-			log15.Warn("setting backed off reclone time failed", "repo", repo, "cloned", recloneTime, "reason", reason, "error", err)
-		}
+		recloneTime, _ := getRecloneTime(dir)
+		log15.Info("recloning repo", "repo", repo, "cloned", recloneTime, "reason", reason)
 
 		remoteURL, err := repoRemoteURL(ctx, dir)
 		if err != nil {
 			return false, errors.Wrap(err, "failed to get remote URL")
 		}
 
-		if _, err := s.cloneRepo(ctx, repo, remoteURL, &cloneOptions{Block: true, Overwrite: true}); err != nil {
+		strategy, err := readCloneStrategy(dir)
+		if err != nil {
+			return false, errors.Wrap(err, "failed to read clone strategy")
+		}
+		if _, err := s.cloneRepo(ctx, repo, remoteURL, &cloneOptions{Block: true, Overwrite: true, Strategy: strategy}); err != nil {
 			return true, err
 		}
 		reposRecloned.Inc()
@@ -175,14 +188,18 @@ func (s *Server) cleanupRepos() {
 		// If git is interrupted it can leave lock files lying around. It does
 		// not clean these up, and instead fails commands.
 		{"remove stale locks", removeStaleLocks},
+		// We keep track of the size of every repository, which is reported
+		// to admins and the total of which is exported as a metric.
+		{"record size", recordSize},
 		// We always want to have the same git attributes file at
 		// info/attributes.
 		{"ensure git attributes", ensureGitAttributes},
-		// Old git clones accumulate loose git objects that waste space and
-		// slow down git operations. Periodically do a fresh clone to avoid
-		// these problems. git gc is slow and resource intensive. It is
-		// cheaper and faster to just reclone the repository.
-		{"maybe reclone", maybeReclone},
+		// Old git clones accumulate loose git objects and packs that waste
+		// space and slow down git operations. We periodically run
+		// incremental maintenance tasks to avoid these problems, and only
+		// reclone repositories which are corrupt or keep failing
+		// maintenance.
+		{"maybe maintain or reclone", maybeMaintainOrReclone},
 	}
 
 	err := bestEffortWalk(s.ReposDir, func(dir string, fi os.FileInfo) error {
@@ -216,6 +233,12 @@ func (s *Server) cleanupRepos() {
 		log15.Error("cleanup: error iterating over repositories", "error", err)
 	}
 
+	var totalSize int64
+	for _, size := range s.recordedRepoSizes() {
+		totalSize += size
+	}
+	reposSizeBytes.Set(float64(totalSize))
+
 	if s.DiskSizer == nil {
 		s.DiskSizer = &StatDiskSizer{}
 	}
@@ -297,8 +320,17 @@ func (s *Server) freeUpSpace(howManyBytesToFree int64) error {
 		dirModTimes[d] = mt
 	}
 
-	// Sort the repos from least to most recently used.
+	owned := make(map[GitDir]bool, len(gitDirs))
+	for _, d := range gitDirs {
+		owned[d] = s.ownsRepo(s.name(d))
+	}
+
+	// Sort the repos we don't replicate first, and then from least to most
+	// recently used.
 	sort.Slice(gitDirs, func(i, j int) bool {
+		if owned[gitDirs[i]] != owned[gitDirs[j]] {
+			return !owned[gitDirs[i]]
+		}
 		return dirModTimes[gitDirs[i]].Before(dirModTimes[gitDirs[j]])
 	})
 
@@ -344,6 +376,44 @@ func (s *Server) freeUpSpace(howManyBytesToFree int64) error {
 	return nil
 }
 
+// ownsRepo reports whether this gitserver is one of the gitservers repo is
+// replicated on. If we can't tell, because this gitserver can't be found in
+// the list of gitserver addresses, we assume it is.
+func (s *Server) ownsRepo(repo api.RepoName) bool {
+	c := conf.Get()
+	addrs := c.ServiceConnections.GitServers
+	self := s.selfAddr(addrs)
+	if self == "" {
+		return true
+	}
+	for _, addr := range gitserver.AddrsForKey(addrs, string(protocol.NormalizeRepo(repo)), c.ExperimentalFeatures.GitServerReplicationFactor) {
+		if addr == self {
+			return true
+		}
+	}
+	return false
+}
+
+// selfAddr returns the address of this gitserver in addrs, or "" if it is not
+// found. Addresses match if they equal s.Hostname, or if their host is
+// s.Hostname or a domain name in it (e.g. "gitserver-0.gitserver:3178" for
+// the hostname "gitserver-0").
+func (s *Server) selfAddr(addrs []string) string {
+	if s.Hostname == "" {
+		return ""
+	}
+	for _, addr := range addrs {
+		host := addr
+		if h, _, err := net.SplitHostPort(addr); err == nil {
+			host = h
+		}
+		if addr == s.Hostname || host == s.Hostname || strings.HasPrefix(host, s.Hostname+".") {
+			return addr
+		}
+	}
+	return ""
+}
+
 func gitDirModTime(d GitDir) (time.Time, error) {
 	head, err := os.Stat(d.Path("HEAD"))
 	if err != nil {
@@ -408,6 +478,7 @@ func (s *Server) removeRepoDirectory(gitDir GitDir) error {
 	if err := renameAndSync(dir, filepath.Join(tmp, "repo")); err != nil {
 		return err
 	}
+	s.forgetRepoSize(s.name(gitDir))
 
 	// Everything after this point is just cleanup, so any error that occurs
 	// should not be returned, just logged.
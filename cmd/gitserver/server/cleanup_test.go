@@ -2,6 +2,7 @@ package server
 
 import (
 	"context"
+	"fmt"
 	"io"
 	"io/ioutil"
 	"log"
@@ -10,12 +11,18 @@ import (
 	"path"
 	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
 	"testing"
 	"testing/quick"
 	"time"
 
 	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/conf/conftypes"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/schema"
 )
 
 const (
@@ -52,7 +59,7 @@ func TestCleanupInactive(t *testing.T) {
 	}
 }
 
-func TestCleanupExpired(t *testing.T) {
+func TestCleanupReclone(t *testing.T) {
 	root, err := ioutil.TempDir("", "gitserver-test-")
 	if err != nil {
 		t.Fatal(err)
@@ -61,12 +68,11 @@ func TestCleanupExpired(t *testing.T) {
 
 	repoNew := path.Join(root, "repo-new", ".git")
 	repoOld := path.Join(root, "repo-old", ".git")
-	repoGCNew := path.Join(root, "repo-gc-new", ".git")
-	repoGCOld := path.Join(root, "repo-gc-old", ".git")
+	repoFailing := path.Join(root, "repo-failing", ".git")
 	repoBoom := path.Join(root, "repo-boom", ".git")
 	repoCorrupt := path.Join(root, "repo-corrupt", ".git")
 	remote := path.Join(root, "remote", ".git")
-	for _, path := range []string{repoNew, repoOld, repoGCNew, repoGCOld, repoBoom, repoCorrupt, remote} {
+	for _, path := range []string{repoNew, repoOld, repoFailing, repoBoom, repoCorrupt, remote} {
 		cmd := exec.Command("git", "--bare", "init", path)
 		if err := cmd.Run(); err != nil {
 			t.Fatal(err)
@@ -90,25 +96,9 @@ func TestCleanupExpired(t *testing.T) {
 		}
 		return fi.ModTime()
 	}
-	recloneTime := func(path string) time.Time {
-		t.Helper()
-		ts, err := getRecloneTime(GitDir(path))
-		if err != nil {
-			t.Fatal(err)
-		}
-		return ts
-	}
-
-	writeFile(t, filepath.Join(repoGCNew, "gc.log"), []byte("warning: There are too many unreachable loose objects; run 'git prune' to remove them."))
-	writeFile(t, filepath.Join(repoGCOld, "gc.log"), []byte("warning: There are too many unreachable loose objects; run 'git prune' to remove them."))
 
-	for path, delta := range map[string]time.Duration{
-		repoOld:     2 * repoTTL,
-		repoGCOld:   2 * repoTTLGC,
-		repoBoom:    2 * repoTTL,
-		repoCorrupt: repoTTLGC / 2, // should only trigger corrupt, not old
-	} {
-		ts := time.Now().Add(-delta)
+	for _, path := range []string{repoOld, repoFailing, repoBoom, repoCorrupt} {
+		ts := time.Now().Add(-90 * 24 * time.Hour)
 		if err := setRecloneTime(GitDir(path), ts); err != nil {
 			t.Fatal(err)
 		}
@@ -116,51 +106,49 @@ func TestCleanupExpired(t *testing.T) {
 			t.Fatal(err)
 		}
 	}
+	for _, path := range []string{repoFailing, repoBoom} {
+		if err := gitConfigSet(GitDir(path), maintenanceConfigPrefix+"failures", strconv.Itoa(maxMaintenanceFailures)); err != nil {
+			t.Fatal(err)
+		}
+	}
 	if err := gitConfigSet(GitDir(repoCorrupt), "sourcegraph.maybeCorruptRepo", "1"); err != nil {
 		t.Fatal(err)
 	}
 
-	now := time.Now()
 	repoNewTime := modTime(repoNew)
 	repoOldTime := modTime(repoOld)
-	repoGCNewTime := modTime(repoGCNew)
-	repoGCOldTime := modTime(repoGCOld)
-	repoCorruptTime := modTime(repoBoom)
+	repoFailingTime := modTime(repoFailing)
+	repoCorruptTime := modTime(repoCorrupt)
 	repoBoomTime := modTime(repoBoom)
-	repoBoomRecloneTime := recloneTime(repoBoom)
 
 	s := &Server{ReposDir: root}
 	s.Handler() // Handler as a side-effect sets up Server
 	s.cleanupRepos()
 
-	// repos that shouldn't be recloned
+	// repos that shouldn't be recloned. Old repos are maintained instead.
 	if repoNewTime.Before(modTime(repoNew)) {
 		t.Error("expected repoNew to not be modified")
 	}
-	if repoGCNewTime.Before(modTime(repoGCNew)) {
-		t.Error("expected repoGCNew to not be modified")
+	if repoOldTime.Before(modTime(repoOld)) {
+		t.Error("expected repoOld to not be modified")
 	}
 
 	// repos that should be recloned
-	if !repoOldTime.Before(modTime(repoOld)) {
-		t.Error("expected repoOld to be recloned during clean up")
-	}
-	if !repoGCOldTime.Before(modTime(repoGCOld)) {
-		t.Error("expected repoGCOld to be recloned during clean up")
+	if !repoFailingTime.Before(modTime(repoFailing)) {
+		t.Error("expected repoFailing to be recloned during clean up")
 	}
 	if !repoCorruptTime.Before(modTime(repoCorrupt)) {
 		t.Error("expected repoCorrupt to be recloned during clean up")
 	}
 
-	// repos that fail to clone need to have recloneTime updated
+	// repos that fail to clone need to have their failures reset
 	if repoBoomTime.Before(modTime(repoBoom)) {
 		t.Fatal("expected repoBoom to fail to reclone due to hardcoding getRemoteURL failure")
 	}
-	if !repoBoomRecloneTime.Before(recloneTime(repoBoom)) {
-		t.Error("expected repoBoom reclone time to be updated")
-	}
-	if !now.After(recloneTime(repoBoom)) {
-		t.Error("expected repoBoom reclone time to be updated to not now")
+	if state, err := getMaintenanceState(GitDir(repoBoom)); err != nil {
+		t.Fatal(err)
+	} else if state.Failures != 0 {
+		t.Errorf("expected repoBoom maintenance failures to be reset, got %d", state.Failures)
 	}
 }
 
@@ -613,10 +601,41 @@ error: Could not read d24d09b8bc5d1ea2c3aa24455f4578db6aa3afda`,
 
 func TestJitterDuration(t *testing.T) {
 	f := func(key string) bool {
-		d := jitterDuration(key, repoTTLGC/4)
-		return 0 <= d && d < repoTTLGC/4
+		d := jitterDuration(key, 12*time.Hour)
+		return 0 <= d && d < 12*time.Hour
 	}
 	if err := quick.Check(f, nil); err != nil {
 		t.Error(err)
 	}
 }
+
+func TestOwnsRepo(t *testing.T) {
+	addrs := []string{"gitserver-0.gitserver:3178", "gitserver-1.gitserver:3178", "gitserver-2.gitserver:3178"}
+	conf.Mock(&conf.Unified{
+		SiteConfiguration: schema.SiteConfiguration{
+			ExperimentalFeatures: &schema.ExperimentalFeatures{GitServerReplicationFactor: 2},
+		},
+		ServiceConnections: conftypes.ServiceConnections{GitServers: addrs},
+	})
+	defer conf.Mock(nil)
+
+	repos := []api.RepoName{"a", "b", "c", "github.com/foo/bar"}
+	for _, repo := range repos {
+		replicas := gitserver.AddrsForKey(addrs, string(repo), 2)
+		for i, addr := range addrs {
+			s := &Server{Hostname: fmt.Sprintf("gitserver-%d", i)}
+			want := addr == replicas[0] || addr == replicas[1]
+			if have := s.ownsRepo(repo); have != want {
+				t.Errorf("%s owns %s: have %v, want %v", addr, repo, have, want)
+			}
+		}
+	}
+
+	// If we can't find ourselves, we assume we own every repo.
+	s := &Server{Hostname: "unknown"}
+	for _, repo := range repos {
+		if !s.ownsRepo(repo) {
+			t.Errorf("expected unknown gitserver to own %s", repo)
+		}
+	}
+}
@@ -0,0 +1,228 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
+)
+
+// The git config keys under which the clone strategy of a repository is
+// stored, so that fetches which don't specify it (such as the fetches of
+// ensureRevision) keep the repository cloned the same way.
+const (
+	cloneModeConfigKey  = "sourcegraph.cloneMode"
+	cloneDepthConfigKey = "sourcegraph.cloneDepth"
+	cloneSinceConfigKey = "sourcegraph.cloneSince"
+)
+
+// cloneMode returns the mode of cs, which is "full" if cs is nil.
+func cloneMode(cs *protocol.CloneStrategy) string {
+	if cs == nil || cs.Mode == "" {
+		return "full"
+	}
+	return cs.Mode
+}
+
+// fullClone normalizes cs to nil if it clones the whole history.
+func fullClone(cs *protocol.CloneStrategy) *protocol.CloneStrategy {
+	if cs == nil || (cs.Mode != protocol.CloneModeShallow && cs.Mode != protocol.CloneModeBlobless) {
+		return nil
+	}
+	return cs
+}
+
+// cloneStrategyFetchArgs returns the arguments of git fetch which fetch
+// according to cs.
+func cloneStrategyFetchArgs(cs *protocol.CloneStrategy) []string {
+	switch cloneMode(cs) {
+	case protocol.CloneModeShallow:
+		if cs.Since != "" {
+			return []string{"--shallow-since=" + cs.Since}
+		}
+		depth := cs.Depth
+		if depth <= 0 {
+			depth = 1
+		}
+		return []string{"--depth=" + strconv.Itoa(depth)}
+	case protocol.CloneModeBlobless:
+		return []string{"--filter=blob:none"}
+	}
+	return nil
+}
+
+// cloneStrategyCloneArgs returns the arguments of git clone which clone
+// according to cs.
+func cloneStrategyCloneArgs(cs *protocol.CloneStrategy) []string {
+	args := cloneStrategyFetchArgs(cs)
+	if cloneMode(cs) == protocol.CloneModeShallow {
+		// Shallow clones only clone the default branch otherwise, even
+		// with --mirror.
+		args = append(args, "--no-single-branch")
+	}
+	return args
+}
+
+// readCloneStrategy returns the clone strategy of the repository at dir, or
+// nil if its whole history is cloned.
+func readCloneStrategy(dir GitDir) (*protocol.CloneStrategy, error) {
+	mode, err := gitConfigGet(dir, cloneModeConfigKey)
+	if err != nil {
+		return nil, err
+	}
+	cs := &protocol.CloneStrategy{Mode: strings.TrimSpace(mode)}
+	if cs.Mode == protocol.CloneModeShallow {
+		depth, err := gitConfigGet(dir, cloneDepthConfigKey)
+		if err != nil {
+			return nil, err
+		}
+		if depth = strings.TrimSpace(depth); depth != "" {
+			if cs.Depth, err = strconv.Atoi(depth); err != nil {
+				return nil, errors.Wrapf(err, "invalid %s", cloneDepthConfigKey)
+			}
+		}
+		since, err := gitConfigGet(dir, cloneSinceConfigKey)
+		if err != nil {
+			return nil, err
+		}
+		cs.Since = strings.TrimSpace(since)
+	}
+	return fullClone(cs), nil
+}
+
+// writeCloneStrategy stores cs as the clone strategy of the repository at
+// dir.
+func writeCloneStrategy(dir GitDir, cs *protocol.CloneStrategy) error {
+	cs = fullClone(cs)
+	if cs == nil {
+		for _, key := range []string{cloneModeConfigKey, cloneDepthConfigKey, cloneSinceConfigKey} {
+			if err := gitConfigUnset(dir, key); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if err := gitConfigSet(dir, cloneModeConfigKey, cs.Mode); err != nil {
+		return err
+	}
+	if cs.Depth > 0 {
+		if err := gitConfigSet(dir, cloneDepthConfigKey, strconv.Itoa(cs.Depth)); err != nil {
+			return err
+		}
+	} else if err := gitConfigUnset(dir, cloneDepthConfigKey); err != nil {
+		return err
+	}
+	if cs.Since != "" {
+		return gitConfigSet(dir, cloneSinceConfigKey, cs.Since)
+	}
+	return gitConfigUnset(dir, cloneSinceConfigKey)
+}
+
+// updateCloneStrategy changes the clone strategy of the repository at dir to
+// cs. It returns true if the repository has to be recloned for that, which is
+// the case if the mode changes. A new depth or date of a shallow clone is
+// used by the next fetch.
+func updateCloneStrategy(dir GitDir, cs *protocol.CloneStrategy) (reclone bool, err error) {
+	current, err := readCloneStrategy(dir)
+	if err != nil {
+		return false, err
+	}
+	cs = fullClone(cs)
+	if cloneMode(current) != cloneMode(cs) {
+		return true, nil
+	}
+	if reflect.DeepEqual(current, cs) {
+		return false, nil
+	}
+	return false, writeCloneStrategy(dir, cs)
+}
+
+// isShallowClone reports whether the repository at dir lacks some of its
+// history.
+func isShallowClone(dir GitDir) bool {
+	_, err := os.Stat(dir.Path("shallow"))
+	return err == nil
+}
+
+// needsFullHistory reports whether the output of running git with args is
+// wrong if the history of the repository is incomplete. That is the case for
+// blame, which attributes all older lines to the oldest commit it has, and
+// for searches of the commit log (see RawLogDiffSearch), which miss the
+// commits that weren't fetched.
+func needsFullHistory(args []string) bool {
+	if len(args) == 0 {
+		return false
+	}
+	switch args[0] {
+	case "blame":
+		return true
+	case "log":
+		for _, arg := range args[1:] {
+			if arg == "--" {
+				break
+			}
+			if strings.HasPrefix(arg, "-S") || strings.HasPrefix(arg, "-G") {
+				return true
+			}
+			for _, flag := range []string{"--grep", "--author", "--committer"} {
+				if arg == flag || strings.HasPrefix(arg, flag+"=") {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// historyUnavailableReason explains why git args, for which needsFullHistory
+// is true, isn't run in a shallow clone.
+func historyUnavailableReason(args []string) string {
+	return fmt.Sprintf("git %s needs the full history, but the repository is cloned shallowly (see the cloneStrategies setting of its code host connection)", args[0])
+}
+
+// fetchMissingBlobsAtHead fetches the blobs of the files at HEAD that a
+// blobless clone at dir doesn't have yet. Git fetches missing blobs on demand,
+// but one request at a time, which is too slow for archiving a whole tree for
+// search.
+func fetchMissingBlobsAtHead(ctx context.Context, dir GitDir) error {
+	if _, err := quickRevParseHead(dir); err != nil {
+		// Empty repository.
+		return nil
+	}
+
+	cmd := exec.CommandContext(ctx, "git", "rev-list", "--objects", "--no-walk", "--missing=print", "HEAD")
+	cmd.Dir = string(dir)
+	out, err := cmd.Output()
+	if err != nil {
+		return wrapCmdError(cmd, err)
+	}
+
+	var missing bytes.Buffer
+	for _, line := range bytes.Split(out, []byte("\n")) {
+		if bytes.HasPrefix(line, []byte("?")) {
+			missing.Write(line[1:])
+			missing.WriteByte('\n')
+		}
+	}
+	if missing.Len() == 0 {
+		return nil
+	}
+
+	// This is what git runs itself to fetch missing objects from the remote
+	// of a partial clone.
+	cmd = exec.CommandContext(ctx, "git", "-c", "fetch.negotiationAlgorithm=noop", "fetch", "--no-tags", "--recurse-submodules=no", "--filter=blob:none", "--stdin", "origin")
+	cmd.Dir = string(dir)
+	cmd.Stdin = &missing
+	if out, err := runWithRemoteOpts(ctx, cmd, nil); err != nil {
+		return errors.Wrapf(err, "failed to fetch missing blobs. Output: %s", out)
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
+	"github.com/sourcegraph/sourcegraph/internal/mutablelimiter"
+)
+
+func TestNeedsFullHistory(t *testing.T) {
+	tests := []struct {
+		args []string
+		want bool
+	}{
+		{args: nil, want: false},
+		{args: []string{"blame", "--porcelain", "HEAD", "--", "a.go"}, want: true},
+		{args: []string{"log", "--format=%H", "-n", "1", "HEAD"}, want: false},
+		{args: []string{"log", "--source", "-Gfoo", "--regexp-ignore-case", "HEAD"}, want: true},
+		{args: []string{"log", "--source", "-Sfoo", "HEAD"}, want: true},
+		{args: []string{"log", "--all-match", "--grep=fix", "HEAD"}, want: true},
+		{args: []string{"log", "--author=alice", "HEAD"}, want: true},
+		{args: []string{"log", "HEAD", "--", "--grep"}, want: false},
+		{args: []string{"show", "HEAD:a.go"}, want: false},
+	}
+	for _, test := range tests {
+		if got := needsFullHistory(test.args); got != test.want {
+			t.Errorf("needsFullHistory(%q) = %v, want %v", test.args, got, test.want)
+		}
+	}
+}
+
+func TestCloneStrategy(t *testing.T) {
+	remote := tmpDir(t)
+	cmd := func(dir, name string, arg ...string) string {
+		t.Helper()
+		return strings.TrimSpace(runCmd(t, dir, name, arg...))
+	}
+
+	cmd(remote, "git", "init", ".")
+	cmd(remote, "git", "config", "uploadpack.allowFilter", "true")
+	for _, content := range []string{"a", "b", "c"} {
+		cmd(remote, "sh", "-c", "echo "+content+" > file.txt")
+		cmd(remote, "git", "add", "file.txt")
+		cmd(remote, "git", "commit", "-m", content)
+	}
+	cmd(remote, "git", "branch", "other", "HEAD~1")
+
+	s := &Server{
+		ReposDir:         tmpDir(t),
+		ctx:              context.Background(),
+		locker:           &RepositoryLocker{},
+		cloneLimiter:     mutablelimiter.New(1),
+		cloneableLimiter: mutablelimiter.New(1),
+		repoUpdateLocks:  make(map[api.RepoName]*locks),
+	}
+	ctx := context.Background()
+	repo := api.RepoName("example.com/foo/bar")
+	url := "file://" + remote // local clones ignore --depth and --filter
+	dir := s.dir(repo)
+
+	update := func(cs *protocol.CloneStrategy) {
+		t.Helper()
+		if err := s.updateRepoForRequest(ctx, dir, &protocol.RepoUpdateRequest{Repo: repo, URL: url, Clone: cs}); err != nil {
+			t.Fatal(err)
+		}
+		got, err := readCloneStrategy(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := fullClone(cs); !reflect.DeepEqual(got, want) {
+			t.Fatalf("got clone strategy %+v, want %+v", got, want)
+		}
+	}
+
+	// A shallow clone has the tip of every branch, but no other history.
+	shallow := &protocol.CloneStrategy{Mode: protocol.CloneModeShallow, Depth: 1}
+	if _, err := s.cloneRepo(ctx, repo, url, &cloneOptions{Block: true, Strategy: shallow}); err != nil {
+		t.Fatal(err)
+	}
+	if !isShallowClone(dir) {
+		t.Fatal("expected a shallow clone")
+	}
+	if got := cmd(string(dir), "git", "rev-list", "--all", "--count"); got != "2" {
+		t.Fatalf("got %s commits, want 2", got)
+	}
+
+	// Blame is refused rather than attributing every line to the oldest
+	// commit we have.
+	w := httptest.NewRecorder()
+	s.exec(w, httptest.NewRequest("POST", "/exec", nil), &protocol.ExecRequest{Repo: repo, Args: []string{"blame", "file.txt"}})
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("got status %d for blame, want %d", w.Code, http.StatusUnprocessableEntity)
+	}
+	head := api.CommitID(cmd(string(dir), "git", "rev-parse", "HEAD"))
+	if _, err := blameFile(ctx, dir, &protocol.BlameRequest{NewestCommit: head, Path: "file.txt"}); !isGitErrorCode(err, protocol.GitErrorHistoryUnavailable) {
+		t.Fatalf("got error %v for the blame endpoint, want %s", err, protocol.GitErrorHistoryUnavailable)
+	}
+	if _, err := commitLog(ctx, dir, &protocol.LogRequest{Range: string(head), MessageQuery: "a"}); !isGitErrorCode(err, protocol.GitErrorHistoryUnavailable) {
+		t.Fatalf("got error %v for the log endpoint, want %s", err, protocol.GitErrorHistoryUnavailable)
+	}
+	if _, err := commitLog(ctx, dir, &protocol.LogRequest{Range: string(head), N: 1}); err != nil {
+		t.Fatalf("got error %v for a plain log, want none", err)
+	}
+
+	// A new depth is used by the next fetch.
+	update(&protocol.CloneStrategy{Mode: protocol.CloneModeShallow, Depth: 2})
+	if got := cmd(string(dir), "git", "rev-list", "--all", "--count"); got != "3" {
+		t.Fatalf("got %s commits, want 3", got)
+	}
+
+	// Changing the mode reclones the repository.
+	update(nil)
+	if isShallowClone(dir) {
+		t.Fatal("expected a full clone")
+	}
+
+	update(&protocol.CloneStrategy{Mode: protocol.CloneModeBlobless})
+	if got := cmd(string(dir), "git", "config", "remote.origin.promisor"); got != "true" {
+		t.Fatalf("got remote.origin.promisor %q, want true", got)
+	}
+
+	// The blobs at HEAD are fetched after every fetch, the others only when
+	// they are needed.
+	cmd(remote, "sh", "-c", "echo d > file.txt")
+	cmd(remote, "git", "commit", "-am", "d")
+	update(&protocol.CloneStrategy{Mode: protocol.CloneModeBlobless})
+	if got, want := cmd(string(dir), "git", "rev-parse", "HEAD"), cmd(remote, "git", "rev-parse", "HEAD"); got != want {
+		t.Fatalf("got HEAD %s, want %s", got, want)
+	}
+	if got := cmd(string(dir), "git", "rev-list", "--objects", "--no-walk", "--missing=print", "HEAD"); strings.Contains(got, "?") {
+		t.Fatalf("blobs at HEAD are missing:\n%s", got)
+	}
+	if got := cmd(string(dir), "git", "rev-list", "--objects", "--all", "--missing=print"); !strings.Contains(got, "?") {
+		t.Fatal("expected older blobs to be missing")
+	}
+}
+
+func isGitErrorCode(err error, code protocol.GitErrorCode) bool {
+	e, ok := err.(*protocol.GitError)
+	return ok && e.Code == code
+}
@@ -0,0 +1,207 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/inconshreveable/log15"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
+)
+
+var (
+	reposSizeBytes = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "src_gitserver_repos_size_bytes",
+		Help: "The total size of the repositories on disk, as last recorded by the janitor.",
+	})
+	reposTooLarge = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "src_gitserver_repos_too_large_total",
+		Help: "The number of clones aborted and updates failed because a repository exceeded its size limit.",
+	})
+)
+
+func init() {
+	prometheus.MustRegister(reposSizeBytes)
+	prometheus.MustRegister(reposTooLarge)
+}
+
+// repoSizeMaxAge is how long the janitor uses the recorded size of a
+// repository before it computes it again. Updates record the size of a
+// repository right away.
+const repoSizeMaxAge = time.Hour
+
+// cloneSizeCheckInterval is how often the size of a running clone is checked
+// against the size limit of the repository.
+var cloneSizeCheckInterval = 5 * time.Second
+
+// repoSize is the recorded size of a repository.
+type repoSize struct {
+	bytes int64
+	at    time.Time
+}
+
+// recordRepoSize records that repo takes up bytes on disk.
+func (s *Server) recordRepoSize(repo api.RepoName, bytes int64) {
+	s.repoSizesMu.Lock()
+	defer s.repoSizesMu.Unlock()
+	if s.repoSizes == nil {
+		s.repoSizes = make(map[api.RepoName]repoSize)
+	}
+	s.repoSizes[repo] = repoSize{bytes: bytes, at: time.Now()}
+}
+
+// forgetRepoSize removes the recorded size of repo, e.g. because it was
+// removed.
+func (s *Server) forgetRepoSize(repo api.RepoName) {
+	s.repoSizesMu.Lock()
+	delete(s.repoSizes, repo)
+	s.repoSizesMu.Unlock()
+}
+
+// repoSizeStale reports whether the size of repo has to be computed again.
+func (s *Server) repoSizeStale(repo api.RepoName) bool {
+	s.repoSizesMu.Lock()
+	defer s.repoSizesMu.Unlock()
+	size, ok := s.repoSizes[repo]
+	return !ok || time.Since(size.at) > repoSizeMaxAge
+}
+
+// recordedRepoSizes returns the recorded sizes of all repositories.
+func (s *Server) recordedRepoSizes() map[api.RepoName]int64 {
+	s.repoSizesMu.Lock()
+	defer s.repoSizesMu.Unlock()
+	sizes := make(map[api.RepoName]int64, len(s.repoSizes))
+	for repo, size := range s.repoSizes {
+		sizes[repo] = size.bytes
+	}
+	return sizes
+}
+
+// handleDiskUsage returns the size of the disk and of the repositories on
+// it.
+func (s *Server) handleDiskUsage(w http.ResponseWriter, r *http.Request) {
+	resp := protocol.DiskUsageResponse{Repos: s.recordedRepoSizes()}
+
+	var err error
+	if resp.DiskSizeBytes, err = s.DiskSizer.DiskSizeBytes(s.ReposDir); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if resp.FreeBytes, err = s.DiskSizer.BytesFreeOnDisk(s.ReposDir); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if err := json.NewEncoder(w).Encode(&resp); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+// repoSizeLimit is a size limit of the gitRepoSizeLimits site configuration.
+type repoSizeLimit struct {
+	pattern *regexp.Regexp
+	bytes   int64
+}
+
+var repoSizeLimits = conf.Cached(func() interface{} {
+	var limits []repoSizeLimit
+	for _, l := range conf.Get().GitRepoSizeLimits {
+		re, err := regexp.Compile(l.Pattern)
+		if err != nil {
+			log15.Error("ignoring invalid pattern in gitRepoSizeLimits", "pattern", l.Pattern, "error", err)
+			continue
+		}
+		limits = append(limits, repoSizeLimit{pattern: re, bytes: int64(l.MaxSizeMB) << 20})
+	}
+	return limits
+})
+
+// maxRepoSize returns the size limit in bytes of repo, or 0 if it has no
+// limit. The first limit of the gitRepoSizeLimits site configuration whose
+// pattern matches the repository name applies.
+func maxRepoSize(repo api.RepoName) int64 {
+	for _, l := range repoSizeLimits().([]repoSizeLimit) {
+		if l.pattern.MatchString(string(repo)) {
+			return l.bytes
+		}
+	}
+	return 0
+}
+
+// repoTooLargeError is the error of clones and updates of repositories which
+// exceed their size limit.
+type repoTooLargeError struct {
+	Repo  api.RepoName
+	Size  int64 // the size of the repository, or the size when its clone was aborted
+	Limit int64
+}
+
+func (e *repoTooLargeError) Error() string {
+	return fmt.Sprintf("repository %s exceeds its size limit of %d MB (%d MB on disk), see the gitRepoSizeLimits site configuration", e.Repo, e.Limit>>20, e.Size>>20)
+}
+
+// checkRepoSize records the size of repo, which is cloned at dir. It returns
+// a *repoTooLargeError if the repository exceeds its size limit.
+func (s *Server) checkRepoSize(repo api.RepoName, dir GitDir) error {
+	size, err := dirSize(string(dir))
+	if err != nil {
+		return err
+	}
+	s.recordRepoSize(repo, size)
+	if limit := maxRepoSize(repo); limit > 0 && size > limit {
+		reposTooLarge.Inc()
+		return &repoTooLargeError{Repo: repo, Size: size, Limit: limit}
+	}
+	return nil
+}
+
+// cloneSizeWatcher aborts a clone once the directory it clones into exceeds
+// the size limit of the repository.
+type cloneSizeWatcher struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+	err    error // set before done is closed
+}
+
+// watchCloneSize checks the size of dir every cloneSizeCheckInterval while
+// repo is cloned into it, and calls abort if it exceeds limit.
+func watchCloneSize(ctx context.Context, repo api.RepoName, dir string, limit int64, abort context.CancelFunc) *cloneSizeWatcher {
+	ctx, cancel := context.WithCancel(ctx)
+	w := &cloneSizeWatcher{cancel: cancel, done: make(chan struct{})}
+	go func() {
+		defer close(w.done)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(cloneSizeCheckInterval):
+			}
+			size, err := dirSize(dir)
+			if err != nil {
+				continue
+			}
+			if size > limit {
+				reposTooLarge.Inc()
+				w.err = &repoTooLargeError{Repo: repo, Size: size, Limit: limit}
+				abort()
+				return
+			}
+		}
+	}()
+	return w
+}
+
+// stop stops watching the clone. It returns a *repoTooLargeError if the
+// clone was aborted.
+func (w *cloneSizeWatcher) stop() error {
+	w.cancel()
+	<-w.done
+	return w.err
+}
@@ -0,0 +1,317 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/inconshreveable/log15"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/diskcache"
+)
+
+// execCacheDirName is the name of the directory under ReposDir which stores
+// the cached output of git commands.
+const execCacheDirName = ".exec-cache"
+
+var (
+	execCacheRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "src_gitserver_exec_cache_requests_total",
+		Help: "Git commands which could be served from the exec cache, by result (hit, miss or failed).",
+	}, []string{"result"})
+	execCacheSizeBytes = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "src_gitserver_exec_cache_size_bytes",
+		Help: "The total size of the cached git command output on disk.",
+	})
+	execCacheEvictions = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "src_gitserver_exec_cache_evictions_total",
+		Help: "The total number of git command outputs evicted from the exec cache.",
+	})
+)
+
+func init() {
+	prometheus.MustRegister(execCacheRequests)
+	prometheus.MustRegister(execCacheSizeBytes)
+	prometheus.MustRegister(execCacheEvictions)
+}
+
+// execCacheCommands are the git commands whose output only depends on their
+// arguments if all revisions in the arguments are absolute commit IDs. blame
+// is missing, as it always maps author names through the .mailmap file of the
+// repository, which in a bare repository is read from HEAD (see
+// dependsOnMailmap).
+var execCacheCommands = map[string]bool{
+	"cat-file":   true,
+	"diff":       true,
+	"log":        true,
+	"ls-tree":    true,
+	"merge-base": true,
+	"rev-list":   true,
+	"show":       true,
+}
+
+// execCacheUncacheableFlags are flags which make the output of a command
+// depend on the refs of the repository or on the current time.
+var execCacheUncacheableFlags = []string{
+	"--all", "--branches", "--tags", "--remotes", "--glob", "--exclude",
+	"--decorate", "--source", "--reflog", "--walk-reflogs", "-g", "--stdin", "--batch",
+	"--since", "--after", "--until", "--before", "--date=relative", "--relative-date",
+	"--contents",
+}
+
+// execCacheValueFlags are flags whose value is the next argument.
+var execCacheValueFlags = map[string]bool{"-n": true, "-L": true}
+
+// mailmapPlaceholderRe matches the placeholders of a --format which print the
+// author or committer name or email after mapping it through .mailmap.
+var mailmapPlaceholderRe = regexp.MustCompile(`%[ac][NEL]`)
+
+// absoluteRevisionSuffixRe matches the suffixes of a revision which select a
+// commit or tree relative to it, such as "^0", "~2" or "^{tree}".
+var absoluteRevisionSuffixRe = regexp.MustCompile(`^(\^[0-9]*|~[0-9]*|\^\{(commit|tree)?\})*$`)
+
+// execCacheKey returns the exec cache key of running git with args in repo,
+// and whether the output of the command can be cached. That is the case if
+// the command is deterministic, does not depend on the .mailmap file of the
+// repository and all of its revisions are absolute commit IDs (see
+// isAbsoluteRevision).
+//
+// 🚨 SECURITY: The key includes the repository name, so that cached output is
+// never shared between repositories. Otherwise the contents of a commit in a
+// private repository could be read through any other repository.
+func execCacheKey(repo api.RepoName, args []string) (string, bool) {
+	if len(args) == 0 || !execCacheCommands[args[0]] || dependsOnMailmap(args) {
+		return "", false
+	}
+
+	hasRevision := false
+	for i := 1; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			// The remaining arguments are paths.
+			break
+		}
+		if strings.HasPrefix(arg, "-") {
+			if !isCacheableFlag(arg) {
+				return "", false
+			}
+			if execCacheValueFlags[arg] {
+				i++
+			}
+			continue
+		}
+		if !isAbsoluteRevisionArg(arg) {
+			// A symbolic revision (or a path which we can't tell apart from
+			// one).
+			return "", false
+		}
+		hasRevision = true
+	}
+	if !hasRevision {
+		return "", false
+	}
+	return string(repo) + "\x00" + strings.Join(args, "\x00"), true
+}
+
+func isCacheableFlag(flag string) bool {
+	for _, f := range execCacheUncacheableFlags {
+		if flag == f || strings.HasPrefix(flag, f+"=") {
+			return false
+		}
+	}
+	if strings.HasPrefix(flag, "--format=") || strings.HasPrefix(flag, "--pretty=") {
+		// %d and %D are the ref names of commits.
+		format := strings.ToLower(flag)
+		if strings.Contains(format, "%d") {
+			return false
+		}
+	}
+	return true
+}
+
+// dependsOnMailmap reports whether the output of running git with args
+// depends on the .mailmap file of the repository. The file can change without
+// any change to the commits in the arguments, so such output can't be cached.
+// log and show map the names in their default formats through .mailmap (see
+// log.mailmap), unless show only prints blobs or trees.
+func dependsOnMailmap(args []string) bool {
+	if args[0] == "blame" {
+		return true
+	}
+
+	hasFormat, onlyObjects := false, true
+	for i := 1; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			break
+		}
+		switch {
+		case arg == "--use-mailmap" || arg == "--mailmap":
+			return true
+		case arg == "--oneline":
+			hasFormat = true
+		case strings.HasPrefix(arg, "--format=") || strings.HasPrefix(arg, "--pretty="):
+			hasFormat = true
+			format := arg[strings.IndexByte(arg, '=')+1:]
+			if strings.Contains(format, "%") {
+				if mailmapPlaceholderRe.MatchString(format) {
+					return true
+				}
+			} else if format != "oneline" && format != "raw" {
+				// The built-in formats other than these print names.
+				return true
+			}
+		case execCacheValueFlags[arg]:
+			i++
+		case !strings.HasPrefix(arg, "-") && !strings.Contains(arg, ":"):
+			onlyObjects = false
+		}
+	}
+
+	switch args[0] {
+	case "log":
+		return !hasFormat
+	case "show":
+		return !hasFormat && !onlyObjects
+	}
+	return false
+}
+
+// isAbsoluteRevisionArg reports whether arg only refers to absolute commit
+// IDs, such as "c0ffee...^{tree}", "c0ffee...:path" or "c0ffee...~1..beef...".
+func isAbsoluteRevisionArg(arg string) bool {
+	if i := strings.IndexByte(arg, ':'); i >= 0 {
+		// <rev>:<path>
+		arg = arg[:i]
+	}
+	for _, rev := range strings.Split(strings.Replace(arg, "...", "..", -1), "..") {
+		if len(rev) < 40 || !isAbsoluteRevision(rev[:40]) || !absoluteRevisionSuffixRe.MatchString(rev[40:]) {
+			return false
+		}
+	}
+	return true
+}
+
+// cachedExec is the result of a git command run through the exec cache.
+type cachedExec struct {
+	Stdout     io.ReadCloser
+	ExitStatus int
+	Stderr     string
+	Err        error // the error of running the command, if any
+}
+
+// errExecFailed is returned to the exec cache by commands which failed, so
+// that their output isn't cached.
+var errExecFailed = errors.New("command failed")
+
+// openCachedExec returns the result of running git with args in the
+// repository at dir. If the output is cached, it is returned without running
+// git. Otherwise git is run once, and its output is cached if it succeeds.
+// The returned bool is false if the command can't be cached, the cache is
+// disabled or the cache failed before the command ran. Callers should then
+// run the command themselves.
+func (s *Server) openCachedExec(ctx context.Context, repo api.RepoName, dir GitDir, args []string) (*cachedExec, bool) {
+	if s.execCache == nil || isShallowClone(dir) {
+		// The output of commands at a commit of a shallow clone depends on
+		// where its history was cut off, which moves with every fetch.
+		return nil, false
+	}
+	key, ok := execCacheKey(repo, args)
+	if !ok {
+		return nil, false
+	}
+
+	var (
+		ran    bool
+		failed *cachedExec
+	)
+	f, err := s.execCache.OpenWithPath(ctx, key, func(ctx context.Context, path string) error {
+		ran = true
+		out, err := os.OpenFile(path, os.O_WRONLY, 0600)
+		if err != nil {
+			return err
+		}
+		defer out.Close()
+
+		var stderr bytes.Buffer
+		cmd := exec.CommandContext(ctx, "git", args...)
+		cmd.Dir = string(dir)
+		cmd.Stdout = out
+		cmd.Stderr = &limitWriter{W: &stderr, N: 1024}
+		exitStatus, err := runCommand(ctx, cmd)
+		if err == nil && exitStatus == 0 {
+			return nil
+		}
+
+		// The output of failed commands isn't cached, but we keep it open
+		// for the caller, since the cache removes the file.
+		stdout, openErr := os.Open(path)
+		if openErr != nil {
+			return openErr
+		}
+		failed = &cachedExec{Stdout: stdout, ExitStatus: exitStatus, Stderr: stderr.String(), Err: err}
+		return errExecFailed
+	})
+	if err != nil {
+		// failed is only set if the command ran to completion, in which
+		// case the cache returned its error.
+		if errors.Cause(err) == errExecFailed {
+			execCacheRequests.WithLabelValues("failed").Inc()
+			return failed, true
+		}
+		if ctx.Err() == nil {
+			execCacheRequests.WithLabelValues("failed").Inc()
+		}
+		return nil, false
+	}
+	if ran {
+		execCacheRequests.WithLabelValues("miss").Inc()
+	} else {
+		execCacheRequests.WithLabelValues("hit").Inc()
+	}
+	return &cachedExec{Stdout: f}, true
+}
+
+// startExecCache sets up the exec cache if ExecCacheMaxSize is set, and
+// evicts the least recently used entries when the cache is too large.
+func (s *Server) startExecCache() {
+	if s.ExecCacheMaxSize <= 0 {
+		return
+	}
+	dir := filepath.Join(s.ReposDir, execCacheDirName)
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		log15.Error("failed to create exec cache directory, not caching git command output", "error", err)
+		return
+	}
+	s.execCache = &diskcache.Store{
+		Dir:       dir,
+		Component: "gitserver-exec-cache",
+	}
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		for {
+			select {
+			case <-s.ctx.Done():
+				return
+			case <-time.After(10 * time.Second):
+			}
+			stats, err := s.execCache.Evict(s.ExecCacheMaxSize)
+			if err != nil {
+				log15.Error("failed to evict exec cache entries", "error", err)
+				continue
+			}
+			execCacheSizeBytes.Set(float64(stats.CacheSize))
+			execCacheEvictions.Add(float64(stats.Evicted))
+		}
+	}()
+}
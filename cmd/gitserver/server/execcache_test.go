@@ -0,0 +1,147 @@
+package server
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+)
+
+func TestExecCacheKey(t *testing.T) {
+	sha := strings.Repeat("a", 40)
+	tests := []struct {
+		args []string
+		want bool
+	}{
+		{[]string{"show", sha + ":file"}, true},
+		{[]string{"log", "--format=%H %s", "-n", "10", sha, "--", "dir"}, true},
+		{[]string{"diff", sha + "~1..." + sha}, true},
+		{[]string{"ls-tree", sha + "^{tree}", "--", "HEAD"}, true},
+		{[]string{"rev-list", "--format=%an <%ae>", sha}, true},
+		{[]string{"show", "--format=%H", sha}, true},
+		{[]string{"log", "--pretty=oneline", sha}, true},
+
+		{[]string{"show", "HEAD:file"}, false},
+		{[]string{"blame", "--porcelain", sha, "--", "file"}, false},
+		{[]string{"log", "--format=%H %aN %aE", sha}, false},
+		{[]string{"log", "--format=format:%cN", sha}, false},
+		{[]string{"log", "--use-mailmap", "--format=%H", sha}, false},
+		{[]string{"log", "--pretty=medium", sha}, false},
+		{[]string{"log", "-n", "1", sha}, false},
+		{[]string{"show", sha}, false},
+		{[]string{"log", sha + "..master"}, false},
+		{[]string{"log", "--all"}, false},
+		{[]string{"log", "--decorate=full", sha}, false},
+		{[]string{"log", "--format=%H %D", sha}, false},
+		{[]string{"log", "--since=1 week ago", sha}, false},
+		{[]string{"log", "--", sha}, false},
+		{[]string{"rev-parse", sha}, false},
+		{[]string{"show", sha[:7]}, false},
+		{nil, false},
+	}
+	for _, test := range tests {
+		key, ok := execCacheKey("repo", test.args)
+		if ok != test.want {
+			t.Errorf("execCacheKey(%q): got cacheable %v, want %v", test.args, ok, test.want)
+		}
+		if ok && !strings.HasPrefix(key, "repo\x00") {
+			t.Errorf("execCacheKey(%q): got key %q, want it to include the repository", test.args, key)
+		}
+	}
+
+	a, _ := execCacheKey("a", []string{"show", sha + ":file"})
+	b, _ := execCacheKey("b", []string{"show", sha + ":file"})
+	if a == b {
+		t.Error("got the same key for different repositories")
+	}
+}
+
+func TestExecCache(t *testing.T) {
+	root := tmpDir(t)
+	repo := filepath.Join(root, "repo")
+	runCmd(t, root, "git", "init", repo)
+	runCmd(t, repo, "sh", "-c", "echo hello > file")
+	runCmd(t, repo, "git", "add", ".")
+	runCmd(t, repo, "git", "commit", "-m", "first")
+	head := strings.TrimSpace(runCmd(t, repo, "git", "rev-parse", "HEAD"))
+
+	s := &Server{ReposDir: root, ExecCacheMaxSize: 1 << 20}
+	h := s.Handler()
+	defer s.Stop()
+
+	exec := func() string {
+		t.Helper()
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest("POST", "/exec", strings.NewReader(`{"repo": "repo", "args": ["show", "`+head+`:file"]}`)))
+		res := w.Result()
+		body, err := ioutil.ReadAll(res.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if status := res.Trailer.Get("X-Exec-Exit-Status"); status != "0" {
+			t.Fatalf("got exit status %q, want 0 (stderr: %q)", status, res.Trailer.Get("X-Exec-Stderr"))
+		}
+		return string(body)
+	}
+
+	if got, want := exec(), "hello\n"; got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+
+	// Without the objects of the repository, only the cache can answer.
+	if err := os.RemoveAll(filepath.Join(repo, ".git", "objects")); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := exec(), "hello\n"; got != want {
+		t.Fatalf("got %q from the cache, want %q", got, want)
+	}
+}
+
+func TestExecCacheFailedCommand(t *testing.T) {
+	root := tmpDir(t)
+	repo := filepath.Join(root, "repo")
+	runCmd(t, root, "git", "init", repo)
+	runCmd(t, repo, "sh", "-c", "echo hello > file")
+	runCmd(t, repo, "git", "add", ".")
+	runCmd(t, repo, "git", "commit", "-m", "first")
+	head := strings.TrimSpace(runCmd(t, repo, "git", "rev-parse", "HEAD"))
+
+	runs := 0
+	runCommandMock = func(ctx context.Context, cmd *exec.Cmd) (int, error) {
+		if len(cmd.Args) > 1 && cmd.Args[1] == "show" {
+			runs++
+		}
+		err := cmd.Run()
+		return cmd.ProcessState.Sys().(syscall.WaitStatus).ExitStatus(), err
+	}
+	defer func() { runCommandMock = nil }()
+
+	s := &Server{ReposDir: root, ExecCacheMaxSize: 1 << 20}
+	h := s.Handler()
+	defer s.Stop()
+
+	for i := 1; i <= 2; i++ {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest("POST", "/exec", strings.NewReader(`{"repo": "repo", "args": ["show", "`+head+`:missing"]}`)))
+		res := w.Result()
+		if _, err := ioutil.ReadAll(res.Body); err != nil {
+			t.Fatal(err)
+		}
+		if status := res.Trailer.Get("X-Exec-Exit-Status"); status == "0" || status == "" {
+			t.Fatalf("got exit status %q, want a failure", status)
+		}
+		if stderr := res.Trailer.Get("X-Exec-Stderr"); !strings.Contains(stderr, "missing") {
+			t.Fatalf("got stderr %q, want it to mention the missing path", stderr)
+		}
+
+		// The command runs once per request, and its failure isn't cached.
+		if runs != i {
+			t.Fatalf("got %d runs of the command after %d requests", runs, i)
+		}
+	}
+}
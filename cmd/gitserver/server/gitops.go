@@ -0,0 +1,809 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/inconshreveable/log15"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
+	"github.com/sourcegraph/sourcegraph/internal/trace"
+	"gopkg.in/src-d/go-git.v4/plumbing/format/config"
+)
+
+// This file implements the typed git endpoints. Unlike /exec, they take a
+// description of the operation, build the git command themselves and return
+// structured results and errors (see protocol.GitRequest).
+
+var gitOpDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "src_gitserver_git_op_duration_seconds",
+	Help:    "Latencies of the typed git operations in seconds, by operation and error code.",
+	Buckets: trace.UserLatencyBuckets,
+}, []string{"op", "code"})
+
+func init() {
+	prometheus.MustRegister(gitOpDuration)
+}
+
+// gitOpTimeout is the timeout of the typed git operations.
+const gitOpTimeout = time.Minute
+
+func (s *Server) registerGitOps(mux *http.ServeMux) {
+	mux.HandleFunc("/resolve-revision", s.handleResolveRevision)
+	mux.HandleFunc("/blob", s.handleReadBlob)
+	mux.HandleFunc("/tree", s.handleListTree)
+	mux.HandleFunc("/log", s.handleLog)
+	mux.HandleFunc("/diff", s.handleDiff)
+	mux.HandleFunc("/blame", s.handleBlame)
+	mux.HandleFunc("/merge-base", s.handleMergeBase)
+}
+
+func (s *Server) handleResolveRevision(w http.ResponseWriter, r *http.Request) {
+	var req protocol.ResolveRevisionRequest
+	s.serveGitOp(w, r, "resolve-revision", &req, func(ctx context.Context, dir GitDir, w http.ResponseWriter) error {
+		commit, err := resolveRevision(ctx, dir, req.Spec)
+		if err != nil {
+			return err
+		}
+		return writeGitOpResponse(w, &protocol.ResolveRevisionResponse{CommitID: commit})
+	})
+}
+
+func (s *Server) handleReadBlob(w http.ResponseWriter, r *http.Request) {
+	var req protocol.ReadBlobRequest
+	s.serveGitOp(w, r, "blob", &req, func(ctx context.Context, dir GitDir, w http.ResponseWriter) error {
+		if err := checkGitOpSpecs(string(req.Commit)); err != nil {
+			return err
+		}
+		args := []string{"show", string(req.Commit) + ":" + req.Path}
+		var err error
+		if res, ok := s.openCachedExec(ctx, protocol.NormalizeRepo(req.Repo), dir, args); ok {
+			defer res.Stdout.Close()
+			err = writeCachedExec(w, res, args, req.MaxBytes)
+		} else {
+			cmd := exec.CommandContext(ctx, "git", args...)
+			cmd.Dir = string(dir)
+			err = streamGitCommand(w, cmd, req.MaxBytes)
+		}
+		e, ok := err.(*protocol.GitError)
+		if !ok {
+			return err
+		}
+		switch {
+		case e.Code == protocol.GitErrorPathNotFound && !commitExists(ctx, dir, req.Commit):
+			// git show reports missing paths for missing commits too.
+			e.Code = protocol.GitErrorRevisionNotFound
+		case e.Code == protocol.GitErrorRevisionNotFound && isSubmodule(ctx, dir, req.Commit, req.Path):
+			// git show fails for submodules, since their commit is not in
+			// the repository. We treat them as empty files.
+			w.WriteHeader(http.StatusOK)
+			return nil
+		}
+		return e
+	})
+}
+
+func (s *Server) handleListTree(w http.ResponseWriter, r *http.Request) {
+	var req protocol.ListTreeRequest
+	s.serveGitOp(w, r, "tree", &req, func(ctx context.Context, dir GitDir, w http.ResponseWriter) error {
+		entries, err := listTree(ctx, dir, &req)
+		if err != nil {
+			return err
+		}
+		return writeGitOpResponse(w, &protocol.ListTreeResponse{Entries: entries})
+	})
+}
+
+func (s *Server) handleLog(w http.ResponseWriter, r *http.Request) {
+	var req protocol.LogRequest
+	s.serveGitOp(w, r, "log", &req, func(ctx context.Context, dir GitDir, w http.ResponseWriter) error {
+		commits, err := commitLog(ctx, dir, &req)
+		if err != nil {
+			return err
+		}
+		return writeGitOpResponse(w, &protocol.LogResponse{Commits: commits})
+	})
+}
+
+func (s *Server) handleDiff(w http.ResponseWriter, r *http.Request) {
+	var req protocol.DiffRequest
+	s.serveGitOp(w, r, "diff", &req, func(ctx context.Context, dir GitDir, w http.ResponseWriter) error {
+		args, err := diffArgs(&req)
+		if err != nil {
+			return err
+		}
+		if res, ok := s.openCachedExec(ctx, protocol.NormalizeRepo(req.Repo), dir, args); ok {
+			defer res.Stdout.Close()
+			return writeCachedExec(w, res, args, 0)
+		}
+		cmd := exec.CommandContext(ctx, "git", args...)
+		cmd.Dir = string(dir)
+		return streamGitCommand(w, cmd, 0)
+	})
+}
+
+func (s *Server) handleBlame(w http.ResponseWriter, r *http.Request) {
+	var req protocol.BlameRequest
+	s.serveGitOp(w, r, "blame", &req, func(ctx context.Context, dir GitDir, w http.ResponseWriter) error {
+		hunks, err := blameFile(ctx, dir, &req)
+		if err != nil {
+			return err
+		}
+		return writeGitOpResponse(w, &protocol.BlameResponse{Hunks: hunks})
+	})
+}
+
+func (s *Server) handleMergeBase(w http.ResponseWriter, r *http.Request) {
+	var req protocol.MergeBaseRequest
+	s.serveGitOp(w, r, "merge-base", &req, func(ctx context.Context, dir GitDir, w http.ResponseWriter) error {
+		if err := checkGitOpSpecs(string(req.A), string(req.B)); err != nil {
+			return err
+		}
+		out, err := runGitOp(ctx, dir, "merge-base", "--", string(req.A), string(req.B))
+		if err != nil {
+			return err
+		}
+		return writeGitOpResponse(w, &protocol.MergeBaseResponse{CommitID: api.CommitID(bytes.TrimSpace(out))})
+	})
+}
+
+// serveGitOp serves a request of the typed git endpoint op. It decodes the
+// request body into req and calls run for the repository of the request.
+//
+// Errors returned by run are written as a protocol.GitError, or in the
+// X-Git-Error trailer if run already started writing the response. If run
+// fails with a GitErrorRevisionNotFound error and the request has a remote
+// URL, the repository is fetched and run is called again.
+func (s *Server) serveGitOp(w http.ResponseWriter, r *http.Request, op string, req protocol.GitRequest, run func(ctx context.Context, dir GitDir, w http.ResponseWriter) error) {
+	if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+		writeGitError(w, &protocol.GitError{Code: protocol.GitErrorBadRequest, Message: err.Error()})
+		return
+	}
+	repo := req.Repository()
+	repo.Repo = protocol.NormalizeRepo(repo.Repo)
+
+	ctx, cancel := context.WithTimeout(r.Context(), gitOpTimeout)
+	defer cancel()
+
+	start := time.Now()
+	tr, ctx := trace.New(ctx, "git."+op, string(repo.Repo))
+	var err error
+	defer func() {
+		code := "OK"
+		if err != nil {
+			code = string(toGitError(ctx, err).Code)
+		}
+		tr.SetError(err)
+		tr.Finish()
+		gitOpDuration.WithLabelValues(op, code).Observe(time.Since(start).Seconds())
+	}()
+
+	dir := s.dir(repo.Repo)
+	if err = s.checkRepoCloned(ctx, repo, dir); err != nil {
+		writeGitError(w, toGitError(ctx, err))
+		return
+	}
+
+	gw := &gitOpResponseWriter{ResponseWriter: w}
+	err = run(ctx, dir, gw)
+	if e, ok := err.(*protocol.GitError); ok && e.Code == protocol.GitErrorRevisionNotFound && !gw.wroteHeader && repo.URL != "" {
+		if s.ensureRevision(ctx, repo.Repo, repo.URL, req.EnsureRevision(), dir) {
+			err = run(ctx, dir, gw)
+		}
+	}
+	if err == nil {
+		return
+	}
+
+	gitErr := toGitError(ctx, err)
+	if gw.wroteHeader {
+		// This only works for responses which declared the trailer.
+		w.Header().Set("X-Git-Error", gitErr.Error())
+		return
+	}
+	if gitErr.Code == protocol.GitErrorInternal {
+		log15.Warn("git operation failed", "op", op, "repo", repo.Repo, "error", gitErr.Message)
+	}
+	writeGitError(w, gitErr)
+}
+
+// checkRepoCloned returns a GitErrorRepoNotFound error if the repository at
+// dir is not cloned. If it is not cloned yet and repo has a remote URL, the
+// clone is started.
+func (s *Server) checkRepoCloned(ctx context.Context, repo protocol.GitRepo, dir GitDir) error {
+	if progress, cloning := s.locker.Status(dir); cloning {
+		return &protocol.GitError{Code: protocol.GitErrorRepoNotFound, Message: "repository is being cloned", CloneInProgress: true, CloneProgress: progress}
+	}
+	if repoCloned(dir) {
+		return nil
+	}
+	if repo.URL == "" {
+		return &protocol.GitError{Code: protocol.GitErrorRepoNotFound, Message: "repository not found"}
+	}
+	progress, err := s.cloneRepo(ctx, repo.Repo, repo.URL, nil)
+	if err != nil {
+		log15.Debug("error cloning repo", "repo", repo.Repo, "err", err)
+		return &protocol.GitError{Code: protocol.GitErrorRepoNotFound, Message: "repository not found"}
+	}
+	return &protocol.GitError{Code: protocol.GitErrorRepoNotFound, Message: "repository is being cloned", CloneInProgress: true, CloneProgress: progress}
+}
+
+// gitOpResponseWriter records whether the response was started.
+type gitOpResponseWriter struct {
+	http.ResponseWriter
+	wroteHeader bool
+}
+
+func (w *gitOpResponseWriter) WriteHeader(status int) {
+	w.wroteHeader = true
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *gitOpResponseWriter) Write(p []byte) (int, error) {
+	w.wroteHeader = true
+	return w.ResponseWriter.Write(p)
+}
+
+func writeGitOpResponse(w http.ResponseWriter, v interface{}) error {
+	w.Header().Set("Content-Type", "application/json")
+	return json.NewEncoder(w).Encode(v)
+}
+
+func writeGitError(w http.ResponseWriter, err *protocol.GitError) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(err.Code.HTTPStatus())
+	_ = json.NewEncoder(w).Encode(err)
+}
+
+// toGitError converts err to a GitError.
+func toGitError(ctx context.Context, err error) *protocol.GitError {
+	if e, ok := err.(*protocol.GitError); ok {
+		return e
+	}
+	if ctx.Err() != nil {
+		err = ctx.Err()
+	}
+	return &protocol.GitError{Code: protocol.GitErrorInternal, Message: err.Error()}
+}
+
+var (
+	// pathNotFoundStderr are substrings of git errors for missing paths.
+	pathNotFoundStderr = []string{"does not exist in", "exists on disk, but not in", "no such path"}
+
+	// revisionNotFoundStderr are substrings of git errors for missing
+	// revisions.
+	revisionNotFoundStderr = []string{"unknown revision", "bad object", "bad revision", "invalid object name", "Invalid revision range", "Not a valid commit name", "not a tree object"}
+)
+
+// gitOpError converts the error of cmd, which was run with Output, to a
+// GitError.
+func gitOpError(cmd *exec.Cmd, err error) *protocol.GitError {
+	var stderr string
+	if ee, ok := err.(*exec.ExitError); ok {
+		stderr = string(ee.Stderr)
+	}
+	return classifyGitError(stderr, wrapCmdError(cmd, err).Error())
+}
+
+func classifyGitError(stderr, message string) *protocol.GitError {
+	code := protocol.GitErrorInternal
+	for _, s := range pathNotFoundStderr {
+		if strings.Contains(stderr, s) {
+			code = protocol.GitErrorPathNotFound
+		}
+	}
+	if code == protocol.GitErrorInternal {
+		for _, s := range revisionNotFoundStderr {
+			if strings.Contains(stderr, s) {
+				code = protocol.GitErrorRevisionNotFound
+			}
+		}
+	}
+	return &protocol.GitError{Code: code, Message: message}
+}
+
+// runGitOp runs git with args in dir and returns its stdout. Like /exec, it
+// refuses to run commands whose output would be wrong in a shallow clone.
+func runGitOp(ctx context.Context, dir GitDir, args ...string) ([]byte, error) {
+	if needsFullHistory(args) && isShallowClone(dir) {
+		return nil, &protocol.GitError{Code: protocol.GitErrorHistoryUnavailable, Message: historyUnavailableReason(args)}
+	}
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = string(dir)
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, gitOpError(cmd, err)
+	}
+	return out, nil
+}
+
+// streamGitCommand runs cmd and writes its stdout as the response body. If
+// limit is positive, at most limit bytes are written.
+//
+// Errors which happen before the command writes any output are returned as
+// is, so that they are reported as a GitError. Later errors are reported in
+// the X-Git-Error trailer.
+func streamGitCommand(w http.ResponseWriter, cmd *exec.Cmd, limit int64) error {
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	br := bufio.NewReader(stdout)
+	if _, err := br.Peek(1); err != nil {
+		// No output, so the command failed or the output is empty.
+		if err := cmd.Wait(); err != nil {
+			return classifyGitError(stderr.String(), wrapCmdError(cmd, err).Error()+": "+stderr.String())
+		}
+		w.WriteHeader(http.StatusOK)
+		return nil
+	}
+
+	w.Header().Set("Trailer", "X-Git-Error")
+	w.WriteHeader(http.StatusOK)
+	if limit > 0 {
+		_, err = io.CopyN(w, br, limit)
+		if err == nil {
+			// We have all we need, so stop the command.
+			_ = cmd.Process.Kill()
+			_ = cmd.Wait()
+			return nil
+		} else if err == io.EOF {
+			err = nil
+		}
+	} else {
+		_, err = io.Copy(w, br)
+	}
+	if err != nil {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+		return err
+	}
+	if err := cmd.Wait(); err != nil {
+		return classifyGitError(stderr.String(), wrapCmdError(cmd, err).Error()+": "+stderr.String())
+	}
+	return nil
+}
+
+// writeCachedExec writes the output of git args run through the exec cache
+// as the response body, and reports a failed command like streamGitCommand.
+func writeCachedExec(w http.ResponseWriter, res *cachedExec, args []string, limit int64) error {
+	failed := res.Err != nil || res.ExitStatus != 0
+	gitErr := func() error {
+		message := fmt.Sprintf("git %s failed with exit status %d", strings.Join(args, " "), res.ExitStatus)
+		if res.Err != nil {
+			message += ": " + res.Err.Error()
+		}
+		return classifyGitError(res.Stderr, message+": "+res.Stderr)
+	}
+
+	br := bufio.NewReader(res.Stdout)
+	if _, err := br.Peek(1); err != nil {
+		if failed {
+			return gitErr()
+		}
+		w.WriteHeader(http.StatusOK)
+		return nil
+	}
+
+	w.Header().Set("Trailer", "X-Git-Error")
+	w.WriteHeader(http.StatusOK)
+	if limit > 0 {
+		_, err := io.CopyN(w, br, limit)
+		if err == nil {
+			// Like streamGitCommand, we don't report a failure after the
+			// output we need.
+			return nil
+		} else if err != io.EOF {
+			return err
+		}
+	} else if _, err := io.Copy(w, br); err != nil {
+		return err
+	}
+	if failed {
+		return gitErr()
+	}
+	return nil
+}
+
+// checkGitOpSpecs returns a GitErrorBadRequest error if any of specs could
+// be interpreted as a git command line option.
+func checkGitOpSpecs(specs ...string) error {
+	for _, spec := range specs {
+		if err := checkSpecArgSafety(spec); err != nil {
+			return &protocol.GitError{Code: protocol.GitErrorBadRequest, Message: err.Error()}
+		}
+	}
+	return nil
+}
+
+// resolveRevision returns the commit ID spec resolves to in the repository at
+// dir. An empty spec resolves HEAD.
+func resolveRevision(ctx context.Context, dir GitDir, spec string) (api.CommitID, error) {
+	if err := checkGitOpSpecs(spec); err != nil {
+		return "", err
+	}
+	if spec == "" || spec == "HEAD" {
+		// Resolving HEAD is done for every repository in the scope of a
+		// search, so we avoid running git if we can.
+		if commit, err := quickRevParseHead(dir); err == nil && isAbsoluteRevision(commit) {
+			return api.CommitID(commit), nil
+		}
+		spec = "HEAD"
+	} else {
+		// "git rev-parse HEAD^0" is slower than "git rev-parse HEAD" since
+		// it checks that the resolved git object exists. We can assume it
+		// exists for HEAD, but for other commits we should check.
+		spec = spec + "^0"
+	}
+
+	out, err := runGitOp(ctx, dir, "rev-parse", spec)
+	if err != nil {
+		return "", err
+	}
+	commit := string(bytes.TrimSpace(out))
+	if !isAbsoluteRevision(commit) {
+		if commit == "HEAD" {
+			// If HEAD doesn't point to anything, for example in an empty
+			// repository, git just returns HEAD.
+			return "", &protocol.GitError{Code: protocol.GitErrorRevisionNotFound, Message: "HEAD not found"}
+		}
+		return "", fmt.Errorf("got bad commit %q for revision %q", commit, spec)
+	}
+	return api.CommitID(commit), nil
+}
+
+// commitExists reports whether commit exists in the repository at dir.
+func commitExists(ctx context.Context, dir GitDir, commit api.CommitID) bool {
+	_, err := runGitOp(ctx, dir, "cat-file", "-e", string(commit)+"^{commit}")
+	return err == nil
+}
+
+// isSubmodule reports whether path is a submodule at commit.
+func isSubmodule(ctx context.Context, dir GitDir, commit api.CommitID, path string) bool {
+	out, err := runGitOp(ctx, dir, "ls-tree", string(commit), "--", path)
+	return err == nil && bytes.HasPrefix(out, []byte("160000 commit "))
+}
+
+// listTree returns the tree entries for req in the repository at dir.
+func listTree(ctx context.Context, dir GitDir, req *protocol.ListTreeRequest) ([]*protocol.TreeEntry, error) {
+	if err := checkGitOpSpecs(string(req.Commit), req.Path); err != nil {
+		return nil, err
+	}
+
+	args := []string{
+		"ls-tree",
+		"--long", // show size
+		"--full-name",
+		"-z",
+		string(req.Commit),
+	}
+	if req.Recursive {
+		args = append(args, "-r", "-t")
+	}
+	if req.Path != "" {
+		args = append(args, "--", req.Path)
+	}
+	out, err := runGitOp(ctx, dir, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		entries    []*protocol.TreeEntry
+		submodules *config.Config
+	)
+	for _, line := range strings.Split(string(out), "\x00") {
+		if line == "" {
+			// The last entry is empty.
+			continue
+		}
+
+		// <mode> SP <type> SP <object> SP+ <size> TAB <path>
+		tabPos := strings.IndexByte(line, '\t')
+		if tabPos == -1 {
+			return nil, fmt.Errorf("invalid `git ls-tree` output: %q", out)
+		}
+		info := strings.SplitN(line[:tabPos], " ", 4)
+		if len(info) != 4 {
+			return nil, fmt.Errorf("invalid `git ls-tree` output: %q", out)
+		}
+		mode, err := strconv.ParseUint(info[0], 8, 32)
+		if err != nil {
+			return nil, err
+		}
+		entry := &protocol.TreeEntry{
+			Path: line[tabPos+1:],
+			Mode: uint32(mode),
+			Type: info[1],
+			OID:  info[2],
+		}
+		if size := strings.TrimSpace(info[3]); size != "-" {
+			// Size of "-" indicates a dir or submodule.
+			entry.Size, err = strconv.ParseInt(size, 10, 64)
+			if err != nil || entry.Size < 0 {
+				return nil, fmt.Errorf("invalid `git ls-tree` size output: %q (error: %s)", size, err)
+			}
+		}
+
+		if entry.Type == "commit" {
+			if submodules == nil {
+				submodules, err = readSubmodules(ctx, dir, req.Commit)
+				if err != nil {
+					return nil, err
+				}
+			}
+			entry.Submodule = &protocol.Submodule{
+				Path: submodules.Section("submodule").Subsection(entry.Path).Option("path"),
+				URL:  submodules.Section("submodule").Subsection(entry.Path).Option("url"),
+			}
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// readSubmodules reads the .gitmodules file at commit. If it doesn't exist,
+// an empty config is returned.
+func readSubmodules(ctx context.Context, dir GitDir, commit api.CommitID) (*config.Config, error) {
+	var cfg config.Config
+	out, err := runGitOp(ctx, dir, "show", string(commit)+":.gitmodules")
+	if err != nil {
+		return &cfg, nil
+	}
+	if err := config.NewDecoder(bytes.NewBuffer(out)).Decode(&cfg); err != nil {
+		return nil, fmt.Errorf("error parsing .gitmodules: %s", err)
+	}
+	return &cfg, nil
+}
+
+const (
+	partsPerCommit = 9 // number of \x00-separated fields per commit
+	logFormat      = "--format=format:%H%x00%aN%x00%aE%x00%at%x00%cN%x00%cE%x00%ct%x00%B%x00%P%x00"
+)
+
+// commitLog returns the commits for req in the repository at dir.
+func commitLog(ctx context.Context, dir GitDir, req *protocol.LogRequest) ([]*protocol.Commit, error) {
+	if err := checkGitOpSpecs(req.Range); err != nil {
+		return nil, err
+	}
+
+	args := []string{"log", logFormat}
+	if req.N != 0 {
+		args = append(args, "-n", strconv.FormatUint(uint64(req.N), 10))
+	}
+	if req.Skip != 0 {
+		args = append(args, "--skip="+strconv.FormatUint(uint64(req.Skip), 10))
+	}
+	if req.Author != "" {
+		args = append(args, "--fixed-strings", "--author="+req.Author)
+	}
+	if req.After != "" {
+		args = append(args, "--after="+req.After)
+	}
+	if req.MessageQuery != "" {
+		args = append(args, "--fixed-strings", "--regexp-ignore-case", "--grep="+req.MessageQuery)
+	}
+	if req.Range != "" {
+		args = append(args, req.Range)
+	}
+	args = append(args, "--")
+	if req.Path != "" {
+		args = append(args, req.Path)
+	}
+
+	out, err := runGitOp(ctx, dir, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	commits := []*protocol.Commit{}
+	for len(out) > 0 {
+		var commit *protocol.Commit
+		commit, out, err = parseCommitFromLog(out)
+		if err != nil {
+			return nil, err
+		}
+		commits = append(commits, commit)
+	}
+	return commits, nil
+}
+
+// parseCommitFromLog parses the next commit from data and returns the commit
+// and the remaining data. The data arg is a byte array that contains
+// NUL-separated log fields as formatted by logFormat.
+func parseCommitFromLog(data []byte) (commit *protocol.Commit, rest []byte, err error) {
+	parts := bytes.SplitN(data, []byte{'\x00'}, partsPerCommit+1)
+	if len(parts) < partsPerCommit {
+		return nil, nil, fmt.Errorf("invalid commit log entry: %q", parts)
+	}
+
+	// log outputs are newline separated, so all but the 1st commit ID part
+	// has an erroneous leading newline.
+	parts[0] = bytes.TrimPrefix(parts[0], []byte{'\n'})
+
+	authorTime, err := strconv.ParseInt(string(parts[3]), 10, 64)
+	if err != nil {
+		return nil, nil, fmt.Errorf("parsing git commit author time: %s", err)
+	}
+	committerTime, err := strconv.ParseInt(string(parts[6]), 10, 64)
+	if err != nil {
+		return nil, nil, fmt.Errorf("parsing git commit committer time: %s", err)
+	}
+
+	var parents []api.CommitID
+	if parentPart := parts[8]; len(parentPart) > 0 {
+		for _, id := range bytes.Split(parentPart, []byte{' '}) {
+			parents = append(parents, api.CommitID(id))
+		}
+	}
+
+	commit = &protocol.Commit{
+		ID:        api.CommitID(parts[0]),
+		Author:    protocol.Signature{Name: string(parts[1]), Email: string(parts[2]), Date: time.Unix(authorTime, 0).UTC()},
+		Committer: &protocol.Signature{Name: string(parts[4]), Email: string(parts[5]), Date: time.Unix(committerTime, 0).UTC()},
+		Message:   string(bytes.TrimSuffix(parts[7], []byte{'\n'})),
+		Parents:   parents,
+	}
+	if len(parts) == partsPerCommit+1 {
+		rest = parts[partsPerCommit]
+	}
+	return commit, rest, nil
+}
+
+// diffArgs returns the git diff arguments for req.
+func diffArgs(req *protocol.DiffRequest) ([]string, error) {
+	if req.Base == "" || req.Head == "" {
+		return nil, &protocol.GitError{Code: protocol.GitErrorBadRequest, Message: "base and head are required"}
+	}
+	if err := checkGitOpSpecs(req.Base, req.Head); err != nil {
+		return nil, err
+	}
+
+	args := []string{"diff"}
+	if req.FindRenames {
+		args = append(args, "--find-renames", "--find-copies")
+	}
+	if req.FullIndex {
+		args = append(args, "--full-index")
+	}
+	if req.InterHunkContext > 0 {
+		args = append(args, "--inter-hunk-context="+strconv.Itoa(req.InterHunkContext))
+	}
+	if req.NoPrefix {
+		args = append(args, "--no-prefix")
+	}
+	if req.MergeBase {
+		args = append(args, req.Base+"..."+req.Head)
+	} else {
+		args = append(args, req.Base, req.Head)
+	}
+	args = append(args, "--")
+	return append(args, req.Paths...), nil
+}
+
+// blameFile returns the blame hunks for req in the repository at dir.
+func blameFile(ctx context.Context, dir GitDir, req *protocol.BlameRequest) ([]*protocol.Hunk, error) {
+	if err := checkGitOpSpecs(string(req.NewestCommit)); err != nil {
+		return nil, err
+	}
+
+	args := []string{"blame", "-w", "--porcelain"}
+	if req.StartLine != 0 || req.EndLine != 0 {
+		args = append(args, fmt.Sprintf("-L%d,%d", req.StartLine, req.EndLine))
+	}
+	args = append(args, string(req.NewestCommit), "--", req.Path)
+
+	out, err := runGitOp(ctx, dir, args...)
+	if err != nil {
+		return nil, err
+	}
+	if len(out) == 0 {
+		return nil, nil
+	}
+	return parseBlame(out)
+}
+
+// parseBlame parses the output of git blame --porcelain.
+func parseBlame(out []byte) ([]*protocol.Hunk, error) {
+	commits := make(map[string]protocol.Commit)
+	hunks := make([]*protocol.Hunk, 0)
+	remainingLines := strings.Split(string(out[:len(out)-1]), "\n")
+	byteOffset := 0
+	for len(remainingLines) > 0 {
+		// Consume hunk
+		hunkHeader := strings.Split(remainingLines[0], " ")
+		if len(hunkHeader) != 4 {
+			return nil, fmt.Errorf("Expected at least 4 parts to hunkHeader, but got: '%s'", hunkHeader)
+		}
+		commitID := hunkHeader[0]
+		lineNoCur, _ := strconv.Atoi(hunkHeader[2])
+		nLines, _ := strconv.Atoi(hunkHeader[3])
+		hunk := &protocol.Hunk{
+			CommitID:  api.CommitID(commitID),
+			StartLine: lineNoCur,
+			EndLine:   lineNoCur + nLines,
+			StartByte: byteOffset,
+		}
+
+		if _, in := commits[commitID]; in {
+			// Already seen commit
+			byteOffset += len(remainingLines[1])
+			remainingLines = remainingLines[2:]
+		} else {
+			// New commit
+			author := strings.Join(strings.Split(remainingLines[1], " ")[1:], " ")
+			email := strings.Join(strings.Split(remainingLines[2], " ")[1:], " ")
+			if len(email) >= 2 && email[0] == '<' && email[len(email)-1] == '>' {
+				email = email[1 : len(email)-1]
+			}
+			authorTime, err := strconv.ParseInt(strings.Join(strings.Split(remainingLines[3], " ")[1:], " "), 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("Failed to parse author-time %q", remainingLines[3])
+			}
+			summary := strings.Join(strings.Split(remainingLines[9], " ")[1:], " ")
+			commit := protocol.Commit{
+				ID:      api.CommitID(commitID),
+				Message: summary,
+				Author: protocol.Signature{
+					Name:  author,
+					Email: email,
+					Date:  time.Unix(authorTime, 0).UTC(),
+				},
+			}
+
+			if len(remainingLines) >= 13 && strings.HasPrefix(remainingLines[10], "previous ") {
+				byteOffset += len(remainingLines[12])
+				remainingLines = remainingLines[13:]
+			} else if len(remainingLines) >= 13 && remainingLines[10] == "boundary" {
+				byteOffset += len(remainingLines[12])
+				remainingLines = remainingLines[13:]
+			} else if len(remainingLines) >= 12 {
+				byteOffset += len(remainingLines[11])
+				remainingLines = remainingLines[12:]
+			} else if len(remainingLines) == 11 {
+				// Empty file
+				remainingLines = remainingLines[11:]
+			} else {
+				return nil, fmt.Errorf("Unexpected number of remaining lines (%d):\n%s", len(remainingLines), "  "+strings.Join(remainingLines, "\n  "))
+			}
+
+			commits[commitID] = commit
+		}
+
+		if commit, present := commits[commitID]; present {
+			// Should always be present, but check just to avoid
+			// panicking in case of a (somewhat likely) bug in our
+			// git-blame parser above.
+			hunk.CommitID = commit.ID
+			hunk.Author = commit.Author
+			hunk.Message = commit.Message
+		}
+
+		// Consume remaining lines in hunk
+		for i := 1; i < nLines; i++ {
+			byteOffset += len(remainingLines[1])
+			remainingLines = remainingLines[2:]
+		}
+
+		hunk.EndByte = byteOffset
+		hunks = append(hunks, hunk)
+	}
+
+	return hunks, nil
+}
@@ -0,0 +1,197 @@
+package server
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
+)
+
+func TestGitOps(t *testing.T) {
+	root := tmpDir(t)
+	repo := filepath.Join(root, "repo")
+	runCmd(t, root, "git", "init", repo)
+	runCmd(t, repo, "sh", "-c", "echo hello > file && mkdir dir && echo world > dir/file")
+	runCmd(t, repo, "git", "add", ".")
+	runCmd(t, repo, "git", "commit", "-m", "first")
+	runCmd(t, repo, "sh", "-c", "echo hello world > file")
+	runCmd(t, repo, "git", "commit", "-am", "second")
+	head := strings.TrimSpace(runCmd(t, repo, "git", "rev-parse", "HEAD"))
+	first := strings.TrimSpace(runCmd(t, repo, "git", "rev-parse", "HEAD~"))
+
+	s := &Server{ReposDir: root}
+	h := s.Handler()
+
+	tests := []struct {
+		name     string
+		op       string
+		req      string
+		wantCode int
+		wantBody string                // checked if non-empty
+		wantErr  protocol.GitErrorCode // checked if non-empty
+	}{
+		{
+			name:     "resolve HEAD",
+			op:       "/resolve-revision",
+			req:      `{"repo": "repo"}`,
+			wantCode: http.StatusOK,
+			wantBody: `{"commitID":"` + head + `"}`,
+		},
+		{
+			name:     "resolve branch",
+			op:       "/resolve-revision",
+			req:      `{"repo": "repo", "spec": "HEAD~"}`,
+			wantCode: http.StatusOK,
+			wantBody: `{"commitID":"` + first + `"}`,
+		},
+		{
+			name:     "resolve missing revision",
+			op:       "/resolve-revision",
+			req:      `{"repo": "repo", "spec": "doesnotexist"}`,
+			wantCode: http.StatusNotFound,
+			wantErr:  protocol.GitErrorRevisionNotFound,
+		},
+		{
+			name:     "resolve option",
+			op:       "/resolve-revision",
+			req:      `{"repo": "repo", "spec": "--all"}`,
+			wantCode: http.StatusBadRequest,
+			wantErr:  protocol.GitErrorBadRequest,
+		},
+		{
+			name:     "missing repo",
+			op:       "/resolve-revision",
+			req:      `{"repo": "doesnotexist"}`,
+			wantCode: http.StatusNotFound,
+			wantErr:  protocol.GitErrorRepoNotFound,
+		},
+		{
+			name:     "invalid request",
+			op:       "/resolve-revision",
+			req:      `{`,
+			wantCode: http.StatusBadRequest,
+			wantErr:  protocol.GitErrorBadRequest,
+		},
+		{
+			name:     "blob",
+			op:       "/blob",
+			req:      `{"repo": "repo", "commit": "` + head + `", "path": "dir/file"}`,
+			wantCode: http.StatusOK,
+			wantBody: "world",
+		},
+		{
+			name:     "blob max bytes",
+			op:       "/blob",
+			req:      `{"repo": "repo", "commit": "` + head + `", "path": "file", "maxBytes": 3}`,
+			wantCode: http.StatusOK,
+			wantBody: "hel",
+		},
+		{
+			name:     "missing blob",
+			op:       "/blob",
+			req:      `{"repo": "repo", "commit": "` + head + `", "path": "doesnotexist"}`,
+			wantCode: http.StatusNotFound,
+			wantErr:  protocol.GitErrorPathNotFound,
+		},
+		{
+			name:     "blob at missing commit",
+			op:       "/blob",
+			req:      `{"repo": "repo", "commit": "` + strings.Repeat("a", 40) + `", "path": "file"}`,
+			wantCode: http.StatusNotFound,
+			wantErr:  protocol.GitErrorRevisionNotFound,
+		},
+		{
+			name:     "merge base",
+			op:       "/merge-base",
+			req:      `{"repo": "repo", "a": "` + head + `", "b": "` + first + `"}`,
+			wantCode: http.StatusOK,
+			wantBody: `{"commitID":"` + first + `"}`,
+		},
+		{
+			name:     "diff",
+			op:       "/diff",
+			req:      `{"repo": "repo", "base": "` + first + `", "head": "` + head + `", "noPrefix": true}`,
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "diff without head",
+			op:       "/diff",
+			req:      `{"repo": "repo", "base": "` + first + `"}`,
+			wantCode: http.StatusBadRequest,
+			wantErr:  protocol.GitErrorBadRequest,
+		},
+		{
+			name:     "log with missing revision",
+			op:       "/log",
+			req:      `{"repo": "repo", "range": "doesnotexist"}`,
+			wantCode: http.StatusNotFound,
+			wantErr:  protocol.GitErrorRevisionNotFound,
+		},
+		{
+			name:     "blame missing path",
+			op:       "/blame",
+			req:      `{"repo": "repo", "newestCommit": "` + head + `", "path": "doesnotexist"}`,
+			wantCode: http.StatusNotFound,
+			wantErr:  protocol.GitErrorPathNotFound,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest("POST", test.op, strings.NewReader(test.req)))
+			res := w.Result()
+			body, err := ioutil.ReadAll(res.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if res.StatusCode != test.wantCode {
+				t.Errorf("got status %d, want %d (body: %q)", res.StatusCode, test.wantCode, body)
+			}
+			if test.wantBody != "" && strings.TrimSpace(string(body)) != test.wantBody {
+				t.Errorf("got body %q, want %q", body, test.wantBody)
+			}
+			if test.wantErr != "" {
+				var gitErr protocol.GitError
+				if err := json.Unmarshal(body, &gitErr); err != nil {
+					t.Fatal(err)
+				}
+				if gitErr.Code != test.wantErr {
+					t.Errorf("got error code %q, want %q (message: %q)", gitErr.Code, test.wantErr, gitErr.Message)
+				}
+			}
+		})
+	}
+
+	t.Run("log", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest("POST", "/log", strings.NewReader(`{"repo": "repo", "path": "dir"}`)))
+		var resp protocol.LogResponse
+		if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+			t.Fatal(err)
+		}
+		if len(resp.Commits) != 1 || string(resp.Commits[0].ID) != first || resp.Commits[0].Message != "first" {
+			t.Errorf("got commits %+v, want only %s", resp.Commits, first)
+		}
+	})
+
+	t.Run("tree", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest("POST", "/tree", strings.NewReader(`{"repo": "repo", "commit": "`+head+`", "recursive": true}`)))
+		var resp protocol.ListTreeResponse
+		if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for _, e := range resp.Entries {
+			got = append(got, e.Type+" "+e.Path)
+		}
+		if want := "tree dir,blob dir/file,blob file"; strings.Join(got, ",") != want {
+			t.Errorf("got entries %q, want %q", strings.Join(got, ","), want)
+		}
+	})
+}
@@ -0,0 +1,737 @@
+package server
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/inconshreveable/log15"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/internal/lfs"
+)
+
+func init() {
+	prometheus.MustRegister(lfsFetchedBytes)
+	prometheus.MustRegister(lfsFetchErrors)
+}
+
+var lfsFetchedBytes = prometheus.NewCounter(prometheus.CounterOpts{
+	Name: "src_gitserver_lfs_fetched_bytes_total",
+	Help: "Number of bytes of Git LFS objects fetched from code hosts.",
+})
+
+var lfsFetchErrors = prometheus.NewCounter(prometheus.CounterOpts{
+	Name: "src_gitserver_lfs_fetch_errors_total",
+	Help: "Number of failed Git LFS object fetches.",
+})
+
+// lfsBatchSize is the number of objects we request per Git LFS batch API
+// call.
+const lfsBatchSize = 100
+
+// lfsDir returns the path of the Git LFS object store of the repository at
+// dir. It uses the same layout as git-lfs, so that the store of a repository
+// cloned with git-lfs installed can be read too.
+//
+// The store only exists for repositories with Git LFS enabled.
+func lfsDir(dir GitDir) string {
+	return dir.Path("lfs", "objects")
+}
+
+// lfsObjectPath returns the path of the Git LFS object oid in the store of
+// the repository at dir.
+func lfsObjectPath(dir GitDir, oid string) string {
+	return filepath.Join(lfsDir(dir), oid[0:2], oid[2:4], oid)
+}
+
+// lfsEnabled reports whether Git LFS is enabled for the repository at dir.
+func lfsEnabled(dir GitDir) bool {
+	_, err := os.Stat(lfsDir(dir))
+	return err == nil
+}
+
+// listLFSPointers returns the Git LFS pointers of the files at the given refs
+// (or HEAD if there are none) in the repository at dir, keyed by OID. Refs
+// which don't exist in the repository are ignored.
+func listLFSPointers(ctx context.Context, dir GitDir, refs []string) (map[string]*lfs.Pointer, error) {
+	if len(refs) == 0 {
+		refs = []string{"HEAD"}
+	}
+
+	// Every pointer is a small blob, so we only read the contents of those.
+	candidates := map[string]bool{}
+	for _, ref := range refs {
+		// Refs starting with "-" would be interpreted as options.
+		if strings.HasPrefix(ref, "-") {
+			continue
+		}
+		tree := ref + "^{tree}"
+		cmd := exec.CommandContext(ctx, "git", "rev-parse", "--verify", "--quiet", tree)
+		cmd.Dir = string(dir)
+		if err := cmd.Run(); err != nil {
+			// The ref doesn't exist, for example HEAD of an empty repository.
+			continue
+		}
+
+		cmd = exec.CommandContext(ctx, "git", "ls-tree", "-r", "-z", "-l", tree)
+		cmd.Dir = string(dir)
+		out, err := cmd.Output()
+		if err != nil {
+			return nil, wrapCmdError(cmd, err)
+		}
+		for _, entry := range bytes.Split(out, []byte{0}) {
+			// <mode> SP <type> SP <object> SP <size> TAB <path>
+			i := bytes.IndexByte(entry, '\t')
+			if i < 0 {
+				continue
+			}
+			fields := strings.Fields(string(entry[:i]))
+			if len(fields) != 4 || fields[0] == "120000" || fields[1] != "blob" {
+				continue
+			}
+			if size, err := strconv.Atoi(fields[3]); err != nil || size > lfs.MaxPointerSize {
+				continue
+			}
+			candidates[fields[2]] = true
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+
+	var stdin bytes.Buffer
+	for oid := range candidates {
+		stdin.WriteString(oid + "\n")
+	}
+	cmd := exec.CommandContext(ctx, "git", "cat-file", "--batch")
+	cmd.Dir = string(dir)
+	cmd.Stdin = &stdin
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, wrapCmdError(cmd, err)
+	}
+
+	pointers := map[string]*lfs.Pointer{}
+	r := bufio.NewReader(bytes.NewReader(out))
+	for {
+		// <oid> SP <type> SP <size> LF <contents> LF
+		header, err := r.ReadString('\n')
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		fields := strings.Fields(header)
+		if len(fields) != 3 {
+			return nil, errors.Errorf("unexpected git cat-file output: %q", header)
+		}
+		size, err := strconv.Atoi(fields[2])
+		if err != nil {
+			return nil, errors.Errorf("unexpected git cat-file output: %q", header)
+		}
+		data := make([]byte, size+1)
+		if _, err := io.ReadFull(r, data); err != nil {
+			return nil, err
+		}
+		if p, ok := lfs.ParsePointer(data[:size]); ok {
+			pointers[p.OID] = p
+		}
+	}
+	return pointers, nil
+}
+
+// fetchLFSObjects fetches the Git LFS objects of the files at refs (or HEAD)
+// of the repository at dir from the Git LFS server of remoteURL into the
+// store of the repository, and removes all other objects from the store.
+//
+// Objects larger than s.LFSMaxObjectSize are skipped, as are objects which
+// would make the store grow beyond s.LFSMaxRepoSize. Objects are considered
+// in order of their OID, so that the same objects are kept on every run.
+// Files whose objects aren't in the store are served as pointer files.
+func (s *Server) fetchLFSObjects(ctx context.Context, dir GitDir, remoteURL string, refs []string) error {
+	if err := os.MkdirAll(lfsDir(dir), os.ModePerm); err != nil {
+		return err
+	}
+
+	pointers, err := listLFSPointers(ctx, dir, refs)
+	if err != nil {
+		return errors.Wrap(err, "listing Git LFS pointers")
+	}
+
+	oids := make([]string, 0, len(pointers))
+	for oid := range pointers {
+		oids = append(oids, oid)
+	}
+	sort.Strings(oids)
+
+	var (
+		keep    = map[string]*lfs.Pointer{}
+		missing []*lfs.Pointer
+		total   int64
+	)
+	for _, oid := range oids {
+		p := pointers[oid]
+		if s.LFSMaxObjectSize > 0 && p.Size > s.LFSMaxObjectSize {
+			continue
+		}
+		if s.LFSMaxRepoSize > 0 && total+p.Size > s.LFSMaxRepoSize {
+			continue
+		}
+		total += p.Size
+		keep[oid] = p
+		if _, err := os.Stat(lfsObjectPath(dir, p.OID)); os.IsNotExist(err) {
+			missing = append(missing, p)
+		}
+	}
+
+	// Objects which are no longer referenced, or which no longer fit in the
+	// limits, are removed before fetching so that the store never grows
+	// beyond s.LFSMaxRepoSize.
+	if err := removeLFSObjectsExcept(dir, keep); err != nil {
+		return err
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+
+	c, err := newLFSClient(remoteURL)
+	if err != nil {
+		return err
+	}
+
+	var failed int
+	for len(missing) > 0 {
+		n := lfsBatchSize
+		if n > len(missing) {
+			n = len(missing)
+		}
+		batch := missing[:n]
+		missing = missing[n:]
+
+		objects, err := c.batch(ctx, batch)
+		if err != nil {
+			lfsFetchErrors.Add(float64(len(batch)))
+			return err
+		}
+		for _, o := range objects {
+			if err := c.download(ctx, dir, o); err != nil {
+				lfsFetchErrors.Inc()
+				log15.Warn("failed to fetch Git LFS object", "repo", dir, "oid", o.OID, "error", err)
+				failed++
+			}
+		}
+	}
+	if failed > 0 {
+		return errors.Errorf("failed to fetch %d Git LFS objects", failed)
+	}
+	return nil
+}
+
+// removeLFSObjectsExcept removes the objects from the store of the
+// repository at dir which aren't in keep.
+func removeLFSObjectsExcept(dir GitDir, keep map[string]*lfs.Pointer) error {
+	return filepath.Walk(lfsDir(dir), func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		if _, ok := keep[info.Name()]; ok {
+			return nil
+		}
+		return os.Remove(path)
+	})
+}
+
+// removeLFSObjects removes the Git LFS object store of the repository at dir,
+// which disables Git LFS for it.
+func removeLFSObjects(dir GitDir) error {
+	return os.RemoveAll(dir.Path("lfs"))
+}
+
+// lfsClient is a client for the Git LFS batch API.
+//
+// See https://github.com/git-lfs/git-lfs/blob/master/docs/api/batch.md.
+type lfsClient struct {
+	doer     httpcli.Doer
+	endpoint *url.URL
+	username string
+	password string
+}
+
+func newLFSClient(remoteURL string) (*lfsClient, error) {
+	u, err := url.Parse(remoteURL)
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing remote URL")
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, errors.Errorf("Git LFS is only supported for HTTP(S) remotes, got %q", u.Scheme)
+	}
+
+	// We don't use httpcli.NewExternalHTTPClientFactory, since its response
+	// cache is not meant for large objects.
+	doer, err := httpcli.NewFactory(
+		httpcli.NewMiddleware(httpcli.ContextErrorMiddleware),
+		httpcli.ExternalTransportOpt,
+		httpcli.TracedTransportOpt,
+	).Doer()
+	if err != nil {
+		return nil, err
+	}
+
+	c := &lfsClient{doer: doer}
+	if u.User != nil {
+		c.username = u.User.Username()
+		c.password, _ = u.User.Password()
+	}
+
+	// The Git LFS server of https://host/foo/bar is at
+	// https://host/foo/bar.git/info/lfs.
+	endpoint := *u
+	endpoint.User = nil
+	endpoint.RawQuery = ""
+	endpoint.Fragment = ""
+	endpoint.Path = strings.TrimSuffix(endpoint.Path, "/")
+	if !strings.HasSuffix(endpoint.Path, ".git") {
+		endpoint.Path += ".git"
+	}
+	endpoint.Path += "/info/lfs"
+	endpoint.RawPath = ""
+	c.endpoint = &endpoint
+
+	return c, nil
+}
+
+type lfsBatchRequest struct {
+	Operation string          `json:"operation"`
+	Transfers []string        `json:"transfers"`
+	Objects   []*lfsBatchSpec `json:"objects"`
+}
+
+type lfsBatchSpec struct {
+	OID  string `json:"oid"`
+	Size int64  `json:"size"`
+}
+
+type lfsBatchResponse struct {
+	Objects []*lfsBatchObject `json:"objects"`
+}
+
+type lfsBatchObject struct {
+	OID     string `json:"oid"`
+	Size    int64  `json:"size"`
+	Actions struct {
+		Download *struct {
+			Href   string            `json:"href"`
+			Header map[string]string `json:"header"`
+		} `json:"download"`
+	} `json:"actions"`
+	Error *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+const lfsMediaType = "application/vnd.git-lfs+json"
+
+// batch requests the download actions for pointers.
+func (c *lfsClient) batch(ctx context.Context, pointers []*lfs.Pointer) ([]*lfsBatchObject, error) {
+	body := lfsBatchRequest{Operation: "download", Transfers: []string{"basic"}}
+	for _, p := range pointers {
+		body.Objects = append(body.Objects, &lfsBatchSpec{OID: p.OID, Size: p.Size})
+	}
+	b, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", c.endpoint.String()+"/objects/batch", bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", lfsMediaType)
+	req.Header.Set("Content-Type", lfsMediaType)
+	c.setAuth(req)
+
+	resp, err := c.doer.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, errors.Wrap(err, "Git LFS batch request")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("Git LFS batch request: unexpected status %s", resp.Status)
+	}
+
+	var batchResp lfsBatchResponse
+	if err := json.NewDecoder(resp.Body).Decode(&batchResp); err != nil {
+		return nil, errors.Wrap(err, "decoding Git LFS batch response")
+	}
+	return batchResp.Objects, nil
+}
+
+// download downloads the object o into the store of the repository at dir.
+// The object is verified against its OID before it is added to the store.
+func (c *lfsClient) download(ctx context.Context, dir GitDir, o *lfsBatchObject) error {
+	if o.Error != nil {
+		return errors.Errorf("%d %s", o.Error.Code, o.Error.Message)
+	}
+	if o.Actions.Download == nil {
+		return errors.New("no download action")
+	}
+	if !lfs.ValidOID(o.OID) {
+		// 🚨 SECURITY: the OID is used as a file name, so we must make sure
+		// it is a hash and not a path.
+		return errors.Errorf("invalid oid %q", o.OID)
+	}
+
+	req, err := http.NewRequest("GET", o.Actions.Download.Href, nil)
+	if err != nil {
+		return err
+	}
+	for k, v := range o.Actions.Download.Header {
+		req.Header.Set(k, v)
+	}
+	if req.Header.Get("Authorization") == "" && req.URL.Host == c.endpoint.Host {
+		c.setAuth(req)
+	}
+
+	resp, err := c.doer.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("unexpected status %s", resp.Status)
+	}
+
+	path := lfsObjectPath(dir, o.OID)
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return err
+	}
+	f, err := ioutil.TempFile(filepath.Dir(path), "tmp-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	h := sha256.New()
+	n, err := io.Copy(io.MultiWriter(f, h), io.LimitReader(resp.Body, o.Size+1))
+	if err != nil {
+		return err
+	}
+	if n != o.Size {
+		return errors.Errorf("got %d bytes, expected %d", n, o.Size)
+	}
+	if got := hex.EncodeToString(h.Sum(nil)); got != o.OID {
+		return errors.Errorf("got contents with oid %s", got)
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	lfsFetchedBytes.Add(float64(n))
+	return os.Rename(f.Name(), path)
+}
+
+func (c *lfsClient) setAuth(req *http.Request) {
+	if c.username != "" || c.password != "" {
+		req.SetBasicAuth(c.username, c.password)
+	}
+}
+
+// lfsArchiveWriter is an http.ResponseWriter which replaces the Git LFS
+// pointer files in a successful git archive response with the objects in the
+// store of the repository. Other responses, such as errors, are passed
+// through as is.
+//
+// Close must be called once the archive is written.
+type lfsArchiveWriter struct {
+	http.ResponseWriter
+	dir    GitDir
+	format string
+
+	status int
+	pw     *io.PipeWriter
+	done   chan error
+}
+
+func newLFSArchiveWriter(w http.ResponseWriter, dir GitDir, format string) *lfsArchiveWriter {
+	return &lfsArchiveWriter{ResponseWriter: w, dir: dir, format: format}
+}
+
+func (w *lfsArchiveWriter) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *lfsArchiveWriter) Write(p []byte) (int, error) {
+	if w.status == 0 {
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.status != http.StatusOK {
+		return w.ResponseWriter.Write(p)
+	}
+
+	if w.pw == nil {
+		// We only start rewriting once we see the archive, so that empty
+		// responses stay empty.
+		pr, pw := io.Pipe()
+		w.pw = pw
+		w.done = make(chan error, 1)
+		go func() {
+			var err error
+			if w.format == "zip" {
+				err = smudgeZip(w.ResponseWriter, pr, w.dir)
+			} else {
+				err = smudgeTar(w.ResponseWriter, pr, w.dir)
+			}
+			if err == nil {
+				// Consume the padding after the end of the archive.
+				_, err = io.Copy(ioutil.Discard, pr)
+			}
+			pr.CloseWithError(err)
+			w.done <- err
+		}()
+	}
+	return w.pw.Write(p)
+}
+
+// Close waits for the rewritten archive to be written. Rewrite errors are
+// reported in the X-Exec-Error trailer, like errors of the git command.
+func (w *lfsArchiveWriter) Close() {
+	if w.pw == nil {
+		return
+	}
+	_ = w.pw.Close()
+	if err := <-w.done; err != nil {
+		log15.Error("failed to replace Git LFS pointers in archive", "repo", w.dir, "error", err)
+		if w.Header().Get("X-Exec-Error") == "" {
+			w.Header().Set("X-Exec-Error", err.Error())
+		}
+	}
+}
+
+// openLFSObject opens the object for the pointer file data from the store of
+// the repository at dir. It returns false if data is not a pointer file or
+// the object is not in the store.
+func openLFSObject(dir GitDir, data []byte) (*os.File, int64, bool) {
+	p, ok := lfs.ParsePointer(data)
+	if !ok {
+		return nil, 0, false
+	}
+	f, err := os.Open(lfsObjectPath(dir, p.OID))
+	if err != nil {
+		return nil, 0, false
+	}
+	fi, err := f.Stat()
+	if err != nil || fi.Size() != p.Size {
+		f.Close()
+		return nil, 0, false
+	}
+	return f, p.Size, true
+}
+
+// smudgeTar copies the tar archive src to dst, replacing Git LFS pointer
+// files with their objects from the store of the repository at dir.
+func smudgeTar(dst io.Writer, src io.Reader, dir GitDir) error {
+	tr := tar.NewReader(src)
+	tw := tar.NewWriter(dst)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+
+		if hdr.Typeflag != tar.TypeReg || hdr.Size > lfs.MaxPointerSize {
+			if err := tw.WriteHeader(hdr); err != nil {
+				return err
+			}
+			if _, err := io.Copy(tw, tr); err != nil {
+				return err
+			}
+			continue
+		}
+
+		data, err := ioutil.ReadAll(tr)
+		if err != nil {
+			return err
+		}
+		if err := writeTarFile(tw, hdr, data, dir); err != nil {
+			return err
+		}
+	}
+	return tw.Close()
+}
+
+func writeTarFile(tw *tar.Writer, hdr *tar.Header, data []byte, dir GitDir) error {
+	f, size, ok := openLFSObject(dir, data)
+	if !ok {
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		_, err := tw.Write(data)
+		return err
+	}
+	defer f.Close()
+
+	hdr.Size = size
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	_, err := io.Copy(tw, f)
+	return err
+}
+
+// smudgeZip copies the zip archive src to dst, replacing Git LFS pointer
+// files with their objects from the store of the repository at dir. Zip
+// archives can only be read with random access, so src is buffered in a
+// temporary file.
+func smudgeZip(dst io.Writer, src io.Reader, dir GitDir) error {
+	tmp, err := ioutil.TempFile("", "lfs-archive-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	size, err := io.Copy(tmp, src)
+	if err != nil {
+		return err
+	}
+	zr, err := zip.NewReader(tmp, size)
+	if err != nil {
+		return err
+	}
+
+	zw := zip.NewWriter(dst)
+	if err := zw.SetComment(zr.Comment); err != nil {
+		return err
+	}
+	for _, file := range zr.File {
+		if err := copyZipFile(zw, file, dir); err != nil {
+			return errors.Wrap(err, file.Name)
+		}
+	}
+	return zw.Close()
+}
+
+func copyZipFile(zw *zip.Writer, file *zip.File, dir GitDir) error {
+	rc, err := file.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	hdr := file.FileHeader
+	var r io.Reader = rc
+	if !file.Mode().IsDir() && file.UncompressedSize64 <= lfs.MaxPointerSize {
+		data, err := ioutil.ReadAll(rc)
+		if err != nil {
+			return err
+		}
+		r = bytes.NewReader(data)
+		if f, _, ok := openLFSObject(dir, data); ok {
+			defer f.Close()
+			r = f
+		}
+	}
+
+	w, err := zw.CreateHeader(&hdr)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(w, r)
+	return err
+}
+
+// updateLFS fetches the Git LFS objects of the files at refs (or HEAD) of the
+// repository at dir if enabled is true, or removes its Git LFS object store
+// otherwise. remoteURL defaults to the URL of the origin remote.
+func (s *Server) updateLFS(ctx context.Context, dir GitDir, enabled bool, remoteURL string, refs []string) error {
+	if !enabled {
+		return removeLFSObjects(dir)
+	}
+	if remoteURL == "" {
+		var err error
+		if remoteURL, err = repoRemoteURL(ctx, dir); err != nil {
+			return err
+		}
+	}
+	return s.fetchLFSObjects(ctx, dir, remoteURL, refs)
+}
+
+// updateLFSForRequest updates the Git LFS objects of the repository at dir
+// after it was cloned or updated for req. It returns once the objects are
+// fetched, so that archives of the updated repository include their contents.
+// If an update of the objects of the repository is already running, it waits
+// for that update instead. Failures are only logged, since the repository
+// itself is up to date.
+func (s *Server) updateLFSForRequest(ctx context.Context, dir GitDir, req *protocol.RepoUpdateRequest) {
+	if !req.LFS && !lfsEnabled(dir) {
+		return
+	}
+
+	s.lfsUpdatesMu.Lock()
+	if done, ok := s.lfsUpdates[dir]; ok {
+		s.lfsUpdatesMu.Unlock()
+		select {
+		case <-done:
+		case <-ctx.Done():
+		}
+		return
+	}
+	if s.lfsUpdates == nil {
+		s.lfsUpdates = map[GitDir]chan struct{}{}
+	}
+	done := make(chan struct{})
+	s.lfsUpdates[dir] = done
+	s.lfsUpdatesMu.Unlock()
+
+	defer func() {
+		s.lfsUpdatesMu.Lock()
+		delete(s.lfsUpdates, dir)
+		s.lfsUpdatesMu.Unlock()
+		close(done)
+	}()
+
+	if err := s.updateLFS(ctx, dir, req.LFS, req.URL, req.LFSRefs); err != nil {
+		log15.Warn("failed to update Git LFS objects", "repo", req.Repo, "error", err)
+	}
+}
+
+// waitForLFSUpdate blocks until no update of the Git LFS objects of the
+// repository at dir is running. Archives that replace pointer files wait for
+// running updates, as callers cache them by commit.
+func (s *Server) waitForLFSUpdate(ctx context.Context, dir GitDir) error {
+	s.lfsUpdatesMu.Lock()
+	done, ok := s.lfsUpdates[dir]
+	s.lfsUpdatesMu.Unlock()
+	if !ok {
+		return nil
+	}
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,391 @@
+package server
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/lfs"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestLFS(t *testing.T) {
+	conf.Mock(&conf.Unified{SiteConfiguration: schema.SiteConfiguration{
+		ExperimentalFeatures: &schema.ExperimentalFeatures{},
+	}})
+	defer conf.Mock(nil)
+
+	objects := map[string]string{}
+	pointer := func(content string) string {
+		h := sha256.Sum256([]byte(content))
+		oid := hex.EncodeToString(h[:])
+		objects[oid] = content
+		return (&lfs.Pointer{OID: oid, Size: int64(len(content))}).String()
+	}
+
+	const (
+		bigContent   = "big file contents\n"
+		largeContent = "this object is larger than the limit\n"
+	)
+	bigPointer := pointer(bigContent)
+	largePointer := pointer(largeContent)
+	missingPointer := (&lfs.Pointer{OID: strings.Repeat("a", 64), Size: 1}).String()
+
+	root := tmpDir(t)
+	repo := filepath.Join(root, "repo")
+	dir := GitDir(filepath.Join(repo, ".git"))
+	runCmd(t, root, "git", "init", repo)
+	for name, content := range map[string]string{
+		"big.bin":     bigPointer,
+		"large.bin":   largePointer,
+		"missing.bin": missingPointer,
+		"README":      "hello\n",
+	} {
+		if err := ioutil.WriteFile(filepath.Join(repo, name), []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	runCmd(t, repo, "git", "add", ".")
+	runCmd(t, repo, "git", "commit", "-m", "lfs")
+
+	// A Git LFS server which serves objects, except for the missing one.
+	ts := newLFSTestServer(t, objects)
+	defer ts.Close()
+
+	s := &Server{ReposDir: root, LFSMaxObjectSize: int64(len(bigContent))}
+	remoteURL := strings.Replace(ts.URL, "http://", "http://u:p@", 1) + "/foo/bar"
+	if err := s.updateLFS(context.Background(), dir, true, remoteURL, nil); err == nil {
+		t.Error("expected error for missing Git LFS object")
+	}
+	if !lfsEnabled(dir) {
+		t.Fatal("expected Git LFS to be enabled")
+	}
+
+	want := map[string]string{
+		"README":      "hello\n",
+		"big.bin":     bigContent,
+		"large.bin":   largePointer,
+		"missing.bin": missingPointer,
+	}
+
+	t.Run("tar", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := smudgeTar(&buf, bytes.NewReader(gitArchive(t, dir, "tar")), dir); err != nil {
+			t.Fatal(err)
+		}
+		got := map[string]string{}
+		tr := tar.NewReader(&buf)
+		for {
+			hdr, err := tr.Next()
+			if err == io.EOF {
+				break
+			} else if err != nil {
+				t.Fatal(err)
+			}
+			if hdr.Typeflag != tar.TypeReg {
+				continue
+			}
+			b, err := ioutil.ReadAll(tr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got[hdr.Name] = string(b)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	})
+
+	t.Run("zip", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := smudgeZip(&buf, bytes.NewReader(gitArchive(t, dir, "zip")), dir); err != nil {
+			t.Fatal(err)
+		}
+		zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := map[string]string{}
+		for _, f := range zr.File {
+			rc, err := f.Open()
+			if err != nil {
+				t.Fatal(err)
+			}
+			b, err := ioutil.ReadAll(rc)
+			rc.Close()
+			if err != nil {
+				t.Fatal(err)
+			}
+			got[f.Name] = string(b)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	})
+
+	t.Run("archive writer", func(t *testing.T) {
+		var want bytes.Buffer
+		archive := gitArchive(t, dir, "tar")
+		if err := smudgeTar(&want, bytes.NewReader(archive), dir); err != nil {
+			t.Fatal(err)
+		}
+
+		rec := httptest.NewRecorder()
+		w := newLFSArchiveWriter(rec, dir, "tar")
+		for len(archive) > 0 {
+			n := 100
+			if n > len(archive) {
+				n = len(archive)
+			}
+			if _, err := w.Write(archive[:n]); err != nil {
+				t.Fatal(err)
+			}
+			archive = archive[n:]
+		}
+		w.Close()
+		if !bytes.Equal(rec.Body.Bytes(), want.Bytes()) {
+			t.Error("archive writer output differs from smudgeTar output")
+		}
+
+		// Errors are passed through unchanged.
+		rec = httptest.NewRecorder()
+		w = newLFSArchiveWriter(rec, dir, "tar")
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte("not found"))
+		w.Close()
+		if got := rec.Body.String(); got != "not found" {
+			t.Errorf("got body %q, want %q", got, "not found")
+		}
+	})
+
+	// Disabling Git LFS removes the store.
+	if err := s.updateLFS(context.Background(), dir, false, "", nil); err != nil {
+		t.Fatal(err)
+	}
+	if lfsEnabled(dir) {
+		t.Error("expected Git LFS to be disabled")
+	}
+	if _, err := os.Stat(dir.Path("lfs")); !os.IsNotExist(err) {
+		t.Errorf("expected Git LFS store to be removed: %v", err)
+	}
+}
+
+func TestLFSMaxRepoSize(t *testing.T) {
+	objects := map[string]string{}
+	var oids []string
+	for _, content := range []string{"one\n", "two\n", "six\n"} {
+		h := sha256.Sum256([]byte(content))
+		oid := hex.EncodeToString(h[:])
+		objects[oid] = content
+		oids = append(oids, oid)
+	}
+	sort.Strings(oids)
+
+	root := tmpDir(t)
+	repo := filepath.Join(root, "repo")
+	dir := GitDir(filepath.Join(repo, ".git"))
+	runCmd(t, root, "git", "init", repo)
+	for i, oid := range oids {
+		p := &lfs.Pointer{OID: oid, Size: int64(len(objects[oid]))}
+		if err := ioutil.WriteFile(filepath.Join(repo, strconv.Itoa(i)+".bin"), []byte(p.String()), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	runCmd(t, repo, "git", "add", ".")
+	runCmd(t, repo, "git", "commit", "-m", "lfs")
+
+	// The last object was stored by an earlier run, for example before the
+	// limit was lowered.
+	last := lfsObjectPath(dir, oids[2])
+	if err := os.MkdirAll(filepath.Dir(last), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(last, []byte(objects[oids[2]]), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	ts := newLFSTestServer(t, objects)
+	defer ts.Close()
+
+	// The limit leaves room for two of the three objects.
+	s := &Server{ReposDir: root, LFSMaxRepoSize: 8}
+	remoteURL := strings.Replace(ts.URL, "http://", "http://u:p@", 1) + "/foo/bar"
+	for i := 0; i < 2; i++ {
+		if err := s.updateLFS(context.Background(), dir, true, remoteURL, nil); err != nil {
+			t.Fatal(err)
+		}
+
+		var stored []string
+		err := filepath.Walk(lfsDir(dir), func(path string, info os.FileInfo, err error) error {
+			if err == nil && !info.IsDir() {
+				stored = append(stored, info.Name())
+			}
+			return err
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		sort.Strings(stored)
+		if want := oids[:2]; !reflect.DeepEqual(stored, want) {
+			t.Errorf("run %d: got stored objects %v, want %v", i, stored, want)
+		}
+	}
+}
+
+// newLFSTestServer returns a Git LFS server for the repository at /foo/bar
+// which serves the given objects, keyed by OID. It requires the basic auth
+// credentials u:p.
+func newLFSTestServer(t *testing.T, objects map[string]string) *httptest.Server {
+	var ts *httptest.Server
+	ts = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, _ := r.BasicAuth(); user != "u" || pass != "p" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		switch {
+		case r.URL.Path == "/foo/bar.git/info/lfs/objects/batch":
+			var req lfsBatchRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				t.Error(err)
+			}
+			var resp lfsBatchResponse
+			for _, spec := range req.Objects {
+				o := &lfsBatchObject{OID: spec.OID, Size: spec.Size}
+				if _, ok := objects[spec.OID]; ok {
+					o.Actions.Download = &struct {
+						Href   string            `json:"href"`
+						Header map[string]string `json:"header"`
+					}{Href: ts.URL + "/objects/" + spec.OID}
+				} else {
+					o.Error = &struct {
+						Code    int    `json:"code"`
+						Message string `json:"message"`
+					}{Code: 404, Message: "not found"}
+				}
+				resp.Objects = append(resp.Objects, o)
+			}
+			w.Header().Set("Content-Type", lfsMediaType)
+			_ = json.NewEncoder(w).Encode(resp)
+		case strings.HasPrefix(r.URL.Path, "/objects/"):
+			_, _ = io.WriteString(w, objects[strings.TrimPrefix(r.URL.Path, "/objects/")])
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	return ts
+}
+
+func TestListLFSPointers(t *testing.T) {
+	pointer := func(content string) *lfs.Pointer {
+		h := sha256.Sum256([]byte(content))
+		return &lfs.Pointer{OID: hex.EncodeToString(h[:]), Size: int64(len(content))}
+	}
+	mainPointer := pointer("main")
+	releasePointer := pointer("release")
+
+	root := tmpDir(t)
+	repo := filepath.Join(root, "repo")
+	dir := GitDir(filepath.Join(repo, ".git"))
+	runCmd(t, root, "git", "init", repo)
+
+	// An empty repository has no pointers.
+	if pointers, err := listLFSPointers(context.Background(), dir, nil); err != nil {
+		t.Fatal(err)
+	} else if len(pointers) != 0 {
+		t.Errorf("got pointers %v in empty repository", pointers)
+	}
+
+	commit := func(p *lfs.Pointer) {
+		if err := ioutil.WriteFile(filepath.Join(repo, "file.bin"), []byte(p.String()), 0600); err != nil {
+			t.Fatal(err)
+		}
+		runCmd(t, repo, "git", "add", ".")
+		runCmd(t, repo, "git", "commit", "-m", p.OID)
+	}
+	commit(mainPointer)
+	runCmd(t, repo, "git", "checkout", "-b", "release")
+	commit(releasePointer)
+	runCmd(t, repo, "git", "checkout", "-")
+
+	tests := []struct {
+		refs []string
+		want []string
+	}{
+		{refs: nil, want: []string{mainPointer.OID}},
+		{refs: []string{"refs/heads/release"}, want: []string{releasePointer.OID}},
+		{refs: []string{"HEAD", "release", "refs/heads/missing", "--all"}, want: []string{mainPointer.OID, releasePointer.OID}},
+	}
+	for _, test := range tests {
+		pointers, err := listLFSPointers(context.Background(), dir, test.refs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for oid := range pointers {
+			got = append(got, oid)
+		}
+		sort.Strings(got)
+		sort.Strings(test.want)
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("refs %v: got pointers %v, want %v", test.refs, got, test.want)
+		}
+	}
+}
+
+func gitArchive(t *testing.T, dir GitDir, format string) []byte {
+	t.Helper()
+	cmd := exec.Command("git", "archive", "--format="+format, "HEAD")
+	cmd.Dir = string(dir)
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatal(wrapCmdError(cmd, err))
+	}
+	return out
+}
+
+func TestWaitForLFSUpdate(t *testing.T) {
+	dir := GitDir("/repos/foo/.git")
+	done := make(chan struct{})
+	s := &Server{lfsUpdates: map[GitDir]chan struct{}{dir: done}}
+
+	if err := s.waitForLFSUpdate(context.Background(), GitDir("/repos/bar/.git")); err != nil {
+		t.Errorf("unexpected error waiting without a running update: %s", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := s.waitForLFSUpdate(ctx, dir); err != context.Canceled {
+		t.Errorf("got error %v, want %v", err, context.Canceled)
+	}
+
+	errs := make(chan error, 1)
+	go func() { errs <- s.waitForLFSUpdate(context.Background(), dir) }()
+	select {
+	case err := <-errs:
+		t.Fatalf("returned before the update finished: %v", err)
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	close(done)
+	if err := <-errs; err != nil {
+		t.Errorf("unexpected error waiting for update: %s", err)
+	}
+}
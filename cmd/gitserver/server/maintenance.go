@@ -0,0 +1,311 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/inconshreveable/log15"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func init() {
+	prometheus.MustRegister(maintenanceDuration)
+	prometheus.MustRegister(maintenanceRepos)
+}
+
+// maxMaintenanceFailures is the number of consecutive failed maintenance runs
+// after which we give up on maintaining a repository and reclone it instead.
+const maxMaintenanceFailures = 3
+
+var maintenanceDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "src_gitserver_maintenance_task_duration_seconds",
+	Help:    "Time spent running a git maintenance task on a repository.",
+	Buckets: []float64{1, 5, 10, 30, 60, 300, 900, 1800, 3600},
+}, []string{"task", "success"})
+
+var maintenanceRepos = prometheus.NewCounterVec(prometheus.CounterOpts{
+	Name: "src_gitserver_maintenance_repos_total",
+	Help: "number of repos maintenance was run on, by result",
+}, []string{"success"})
+
+// maintenanceTask is a git maintenance task which is run periodically on
+// every repository. The tasks mirror the incremental strategy of "git
+// maintenance", which never rewrites all packs at once and so is cheap even
+// for very large repositories.
+type maintenanceTask struct {
+	// Name identifies the task in metrics and in the per-repository
+	// maintenance state. It must be a valid git config variable name.
+	Name string
+
+	// Interval is how often the task should run. A jitter of up to a quarter
+	// of the interval is added to spread out the load.
+	Interval time.Duration
+
+	// Commands are the git commands (without the leading "git") the task
+	// runs, in order.
+	Commands [][]string
+
+	// Skip optionally reports whether the task has nothing to do for the
+	// repository at dir. Skipped tasks count as successful.
+	Skip func(dir GitDir) bool
+}
+
+var maintenanceTasks = []maintenanceTask{
+	// Write a commit-graph incrementally, which speeds up commit walks such
+	// as git log and merge base calculations.
+	{
+		Name:     "commit-graph",
+		Interval: 24 * time.Hour,
+		Commands: [][]string{{"commit-graph", "write", "--reachable", "--split", "--changed-paths"}},
+	},
+	// Pack loose objects created by fetches. Without -a, repack only packs
+	// the loose objects and leaves existing packs alone.
+	{
+		Name:     "loose-objects",
+		Interval: 24 * time.Hour,
+		Commands: [][]string{{"prune-packed"}, {"repack", "-d", "-q", "--no-write-bitmap-index"}},
+	},
+	// Index all packs with a multi-pack-index, and merge small packs into
+	// bigger ones a batch at a time.
+	{
+		Name:     "incremental-repack",
+		Interval: 24 * time.Hour,
+		Commands: [][]string{
+			{"multi-pack-index", "write"},
+			{"multi-pack-index", "expire"},
+			{"multi-pack-index", "repack", "--batch-size=2g"},
+		},
+		// git refuses to write a multi-pack-index without packs, for
+		// example for empty repositories.
+		Skip: hasNoPacks,
+	},
+	// Pack loose refs, which fetches of repositories with many refs create a
+	// lot of.
+	{
+		Name:     "pack-refs",
+		Interval: 24 * time.Hour,
+		Commands: [][]string{{"pack-refs", "--all", "--prune"}},
+	},
+	// Remove unreachable objects, for example of force pushed branches. This
+	// walks all reachable objects, so we do it less often.
+	{
+		Name:     "prune",
+		Interval: 7 * 24 * time.Hour,
+		Commands: [][]string{{"prune", "--expire=2.weeks.ago"}},
+	},
+}
+
+// maintenanceState is the maintenance state of a repository. It is stored in
+// the git config of the repository under the sourcegraph.maintenance section.
+type maintenanceState struct {
+	// LastRun is the last time each task was run, keyed by task name.
+	LastRun map[string]time.Time
+
+	// Failures is the number of consecutive failed maintenance runs.
+	Failures int
+}
+
+const maintenanceConfigPrefix = "sourcegraph.maintenance."
+
+// getMaintenanceState reads the maintenance state of the repository at dir.
+func getMaintenanceState(dir GitDir) (*maintenanceState, error) {
+	cmd := exec.Command("git", "config", "--get-regexp", `^sourcegraph\.maintenance\.`)
+	cmd.Dir = string(dir)
+	out, err := cmd.Output()
+	if err != nil {
+		// Exit code 1 means no key matched.
+		if ee, ok := err.(*exec.ExitError); !ok || ee.Sys().(syscall.WaitStatus).ExitStatus() != 1 {
+			return nil, errors.Wrap(wrapCmdError(cmd, err), "failed to get maintenance state")
+		}
+	}
+
+	state := &maintenanceState{LastRun: map[string]time.Time{}}
+	sc := bufio.NewScanner(bytes.NewReader(out))
+	for sc.Scan() {
+		fields := strings.Fields(sc.Text())
+		if len(fields) != 2 {
+			continue
+		}
+		key := strings.TrimPrefix(fields[0], maintenanceConfigPrefix)
+		value, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			// Ignore bad values, they are overwritten by the next run.
+			continue
+		}
+		if key == "failures" {
+			state.Failures = int(value)
+		} else {
+			state.LastRun[key] = time.Unix(value, 0)
+		}
+	}
+	return state, nil
+}
+
+// dueMaintenanceTasks returns the tasks which should run on the repository
+// at dir given its maintenance state. Repositories which have never been
+// maintained have all tasks due.
+func dueMaintenanceTasks(dir GitDir, state *maintenanceState, now time.Time) []maintenanceTask {
+	var due []maintenanceTask
+	for _, task := range maintenanceTasks {
+		interval := task.Interval + jitterDuration(string(dir)+task.Name, task.Interval/4)
+		if now.Sub(state.LastRun[task.Name]) > interval {
+			due = append(due, task)
+		}
+	}
+	return due
+}
+
+// maintenanceQueueSize is the number of repositories which can wait for
+// maintenance. Repositories which don't fit are queued again by the next
+// janitor run.
+const maintenanceQueueSize = 1000
+
+// startMaintenance starts s.MaintenanceConcurrency workers which run the due
+// maintenance tasks on the repositories queued by queueMaintenance.
+func (s *Server) startMaintenance() {
+	concurrency := s.MaintenanceConcurrency
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+
+	s.maintenanceQueue = make(chan GitDir, maintenanceQueueSize)
+	s.maintenancePending = map[GitDir]bool{}
+	for i := 0; i < concurrency; i++ {
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			for {
+				select {
+				case <-s.ctx.Done():
+					return
+				case dir := <-s.maintenanceQueue:
+					if err := s.maintainRepo(s.ctx, dir); err != nil {
+						log15.Error("cleanup: error maintaining repository", "repo", dir, "error", err)
+					}
+
+					s.maintenanceMu.Lock()
+					delete(s.maintenancePending, dir)
+					s.maintenanceMu.Unlock()
+				}
+			}
+		}()
+	}
+}
+
+// queueMaintenance queues the repository at dir for the maintenance workers,
+// unless it is already queued or being maintained. It does not block, so
+// that long running maintenance doesn't hold up the janitor.
+func (s *Server) queueMaintenance(dir GitDir) {
+	s.maintenanceMu.Lock()
+	defer s.maintenanceMu.Unlock()
+	if s.maintenanceQueue == nil || s.maintenancePending[dir] {
+		return
+	}
+
+	select {
+	case s.maintenanceQueue <- dir:
+		s.maintenancePending[dir] = true
+	default:
+		log15.Debug("cleanup: maintenance queue is full", "repo", dir)
+	}
+}
+
+// maintainRepo runs the due maintenance tasks on the repository at dir and
+// records the result in its maintenance state. Each failed run increases the
+// failure count, which makes the repository be recloned once it reaches
+// maxMaintenanceFailures.
+func (s *Server) maintainRepo(ctx context.Context, dir GitDir) error {
+	// Maintenance rewrites packs and refs, so it must not run at the same
+	// time as a fetch or while the clone is replaced.
+	mu := s.repoUpdateMutex(s.name(dir))
+	mu.Lock()
+	defer mu.Unlock()
+
+	// The repository may have been removed since we decided to maintain it,
+	// or is being recloned, which makes maintaining it pointless.
+	if _, cloning := s.locker.Status(dir); cloning {
+		return nil
+	}
+	if _, err := os.Stat(dir.Path("HEAD")); os.IsNotExist(err) {
+		return nil
+	}
+
+	state, err := getMaintenanceState(dir)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	due := dueMaintenanceTasks(dir, state, now)
+	if len(due) == 0 {
+		return nil
+	}
+	for i, task := range due {
+		start := time.Now()
+		err := runMaintenanceTask(ctx, dir, task)
+		maintenanceDuration.WithLabelValues(task.Name, strconv.FormatBool(err == nil)).Observe(time.Since(start).Seconds())
+		if err != nil {
+			maintenanceRepos.WithLabelValues("false").Inc()
+
+			// We record the run time of the failed and the remaining tasks
+			// too, so that we retry them after their interval instead of on
+			// every janitor run.
+			for _, task := range due[i:] {
+				if err := setMaintenanceTime(dir, task, now); err != nil {
+					return err
+				}
+			}
+			if err := gitConfigSet(dir, maintenanceConfigPrefix+"failures", strconv.Itoa(state.Failures+1)); err != nil {
+				return err
+			}
+			return errors.Wrapf(err, "maintenance task %s", task.Name)
+		}
+
+		if err := setMaintenanceTime(dir, task, now); err != nil {
+			return err
+		}
+	}
+
+	maintenanceRepos.WithLabelValues("true").Inc()
+	if state.Failures > 0 {
+		return gitConfigUnset(dir, maintenanceConfigPrefix+"failures")
+	}
+	return nil
+}
+
+func setMaintenanceTime(dir GitDir, task maintenanceTask, now time.Time) error {
+	return gitConfigSet(dir, maintenanceConfigPrefix+task.Name, strconv.FormatInt(now.Unix(), 10))
+}
+
+func runMaintenanceTask(ctx context.Context, dir GitDir, task maintenanceTask) error {
+	if task.Skip != nil && task.Skip(dir) {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, longGitCommandTimeout)
+	defer cancel()
+
+	for _, args := range task.Commands {
+		cmd := exec.CommandContext(ctx, "git", args...)
+		cmd.Dir = string(dir)
+		if _, err := cmd.Output(); err != nil {
+			return wrapCmdError(cmd, err)
+		}
+	}
+	return nil
+}
+
+// hasNoPacks reports whether the repository at dir has no pack files.
+func hasNoPacks(dir GitDir) bool {
+	packs, _ := filepath.Glob(dir.Path("objects", "pack", "*.pack"))
+	return len(packs) == 0
+}
@@ -0,0 +1,123 @@
+package server
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestMaintainRepo(t *testing.T) {
+	root := tmpDir(t)
+	dir := GitDir(filepath.Join(root, "repo", ".git"))
+	runCmd(t, root, "git", "init", filepath.Join(root, "repo"))
+	runCmd(t, filepath.Join(root, "repo"), "git", "-c", "user.name=a", "-c", "user.email=a@a", "commit", "--allow-empty", "-m", "foo")
+
+	s := &Server{ReposDir: root, locker: &RepositoryLocker{}}
+	if err := s.maintainRepo(context.Background(), dir); err != nil {
+		t.Fatal(err)
+	}
+
+	state, err := getMaintenanceState(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.Failures != 0 {
+		t.Errorf("got %d failures, want 0", state.Failures)
+	}
+	for _, task := range maintenanceTasks {
+		if state.LastRun[task.Name].IsZero() {
+			t.Errorf("expected task %s to have run", task.Name)
+		}
+	}
+	if due := dueMaintenanceTasks(dir, state, time.Now()); len(due) != 0 {
+		t.Errorf("expected no due tasks after maintenance, got %v", due)
+	}
+
+	// A failed task is retried after its interval, and counts as a failed
+	// run.
+	orig := maintenanceTasks
+	maintenanceTasks = append(maintenanceTasks, maintenanceTask{
+		Name:     "broken",
+		Interval: time.Hour,
+		Commands: [][]string{{"no-such-command"}},
+	})
+	defer func() { maintenanceTasks = orig }()
+	if err := s.maintainRepo(context.Background(), dir); err == nil {
+		t.Fatal("expected broken maintenance task to fail")
+	}
+	state, err = getMaintenanceState(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.Failures != 1 {
+		t.Errorf("got %d failures, want 1", state.Failures)
+	}
+	if due := dueMaintenanceTasks(dir, state, time.Now()); len(due) != 0 {
+		t.Errorf("expected no due tasks after failed maintenance, got %v", due)
+	}
+}
+
+func TestDueMaintenanceTasks(t *testing.T) {
+	now := time.Now()
+	state := &maintenanceState{LastRun: map[string]time.Time{}}
+	for _, task := range maintenanceTasks {
+		state.LastRun[task.Name] = now.Add(-2 * 24 * time.Hour)
+	}
+
+	var got []string
+	for _, task := range dueMaintenanceTasks("repo", state, now) {
+		got = append(got, task.Name)
+	}
+	want := []string{"commit-graph", "loose-objects", "incremental-repack", "pack-refs"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got due tasks %v, want %v", got, want)
+	}
+}
+
+func TestMaintainRepoWaitsForUpdate(t *testing.T) {
+	root := tmpDir(t)
+	dir := GitDir(filepath.Join(root, "repo", ".git"))
+	runCmd(t, root, "git", "init", filepath.Join(root, "repo"))
+
+	s := &Server{ReposDir: root, locker: &RepositoryLocker{}}
+	mu := s.repoUpdateMutex(s.name(dir))
+	mu.Lock()
+
+	done := make(chan error, 1)
+	go func() { done <- s.maintainRepo(context.Background(), dir) }()
+
+	select {
+	case <-done:
+		t.Fatal("expected maintenance to wait for the running update")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	mu.Unlock()
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestQueueMaintenance(t *testing.T) {
+	s := &Server{
+		maintenanceQueue:   make(chan GitDir, 2),
+		maintenancePending: map[GitDir]bool{},
+	}
+
+	// Repositories which are already queued are not queued again, and
+	// repositories which don't fit are dropped.
+	for _, dir := range []GitDir{"a", "a", "b", "c"} {
+		s.queueMaintenance(dir)
+	}
+	close(s.maintenanceQueue)
+
+	var got []GitDir
+	for dir := range s.maintenanceQueue {
+		got = append(got, dir)
+	}
+	if want := []GitDir{"a", "b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got queued %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,115 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/perforce"
+)
+
+// gitP4 is the command which converts Perforce depots into Git repositories,
+// with the interface of git-p4. Tests replace it with a fake converter.
+var gitP4 = []string{"git", "p4"}
+
+// p4 is the Perforce CLI.
+var p4 = "p4"
+
+// perforceBranch is the ref git p4 imports the changelists of a depot into.
+// We point the default branch at it after every import.
+const perforceBranch = "refs/remotes/p4/master"
+
+// perforceEnv returns the environment of the Perforce CLI for u.
+func perforceEnv(u *perforce.CloneURL) []string {
+	return append(os.Environ(),
+		"P4PORT="+u.Port,
+		"P4USER="+u.User,
+		"P4PASSWD="+u.Password,
+	)
+}
+
+// perforceCommand returns a command which runs the Perforce converter with
+// args against the depot of u.
+func perforceCommand(ctx context.Context, u *perforce.CloneURL, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, gitP4[0], append(append([]string{}, gitP4[1:]...), args...)...)
+	cmd.Env = perforceEnv(u)
+	return cmd
+}
+
+// perforceCloneCmd returns the command which imports the depot at rawurl into
+// a new bare repository at tmpPath.
+func perforceCloneCmd(ctx context.Context, rawurl, tmpPath string) (*exec.Cmd, error) {
+	u, err := perforce.ParseCloneURL(rawurl)
+	if err != nil {
+		return nil, err
+	}
+	args := []string{"clone", "--bare"}
+	if u.MaxChanges > 0 {
+		args = append(args, "--max-changes", strconv.Itoa(u.MaxChanges))
+	}
+	args = append(args, strings.TrimSuffix(u.Depot, "/")+"@all", tmpPath)
+	return perforceCommand(ctx, u, args...), nil
+}
+
+// isPerforceCloneable checks that the depot at rawurl exists and can be read
+// with its credentials.
+func isPerforceCloneable(ctx context.Context, rawurl string) error {
+	u, err := perforce.ParseCloneURL(rawurl)
+	if err != nil {
+		return err
+	}
+	// p4 files doesn't fail for a path without files, but prints an error.
+	cmd := exec.CommandContext(ctx, p4, "-s", "files", "-m", "1", strings.TrimSuffix(u.Depot, "/")+"/...")
+	cmd.Env = perforceEnv(u)
+	out, err := runWith(ctx, cmd, false, nil)
+	if err == nil && strings.Contains(string(out), "error: ") {
+		err = errors.New("no files in depot")
+	}
+	if err != nil {
+		if ctxerr := ctx.Err(); ctxerr != nil {
+			err = ctxerr
+		}
+		if len(out) > 0 {
+			err = fmt.Errorf("%s (output follows)\n\n%s", err, out)
+		}
+		return err
+	}
+	return nil
+}
+
+// syncPerforceDepot imports the changelists of the depot at rawurl which were
+// submitted since the last import into the repository at dir.
+func syncPerforceDepot(ctx context.Context, dir GitDir, rawurl string) ([]byte, error) {
+	u, err := perforce.ParseCloneURL(rawurl)
+	if err != nil {
+		return nil, err
+	}
+	// Without a depot path, git p4 continues from the changelist recorded in
+	// the message of the last commit it imported.
+	cmd := perforceCommand(ctx, u, "sync")
+	cmd.Dir = string(dir)
+	if out, err := runWith(ctx, cmd, false, nil); err != nil {
+		return out, err
+	}
+	return nil, setPerforceHead(ctx, dir)
+}
+
+// setPerforceHead points the default branch of the repository at dir, which
+// was imported from Perforce, at the last imported changelist.
+func setPerforceHead(ctx context.Context, dir GitDir) error {
+	for _, args := range [][]string{
+		{"update-ref", "refs/heads/master", perforceBranch},
+		{"symbolic-ref", "HEAD", "refs/heads/master"},
+	} {
+		cmd := exec.CommandContext(ctx, "git", args...)
+		cmd.Dir = string(dir)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			return errors.Wrapf(wrapCmdError(cmd, err), "failed to set HEAD. Output: %s", out)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+package server
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/perforce"
+	"github.com/sourcegraph/sourcegraph/internal/mutablelimiter"
+)
+
+// fakeGitP4 imports the changelists of a depot like git p4 does, without a
+// Perforce Server. The changelists of the depot //NAME/ are read from the
+// fixture directory FIXTURE/NAME/CHANGE, which contains the description of
+// the changelist in the file desc and the files of the depot after it in the
+// directory files.
+const fakeGitP4 = `
+set -e
+test "$P4USER" = admin && test "$P4PASSWD" = s3cr3t || { echo "invalid credentials" >&2; exit 1; }
+export GIT_AUTHOR_NAME=admin GIT_AUTHOR_EMAIL=admin@perforce GIT_AUTHOR_DATE=2006-01-02T15:04:05Z
+export GIT_COMMITTER_NAME=admin GIT_COMMITTER_EMAIL=admin@perforce GIT_COMMITTER_DATE=2006-01-02T15:04:05Z
+
+import() { # depot, changes
+	parent=$(git rev-parse -q --verify refs/remotes/p4/master || true)
+	for change in $2; do
+		export GIT_INDEX_FILE=$(mktemp -u)
+		git --work-tree="FIXTURE/$1/$change/files" add -A
+		tree=$(git write-tree)
+		rm -f "$GIT_INDEX_FILE"
+		msg="$(cat "FIXTURE/$1/$change/desc")
+
+[git-p4: depot-paths = \"//$1/\": change = $change]"
+		parent=$(git commit-tree $tree ${parent:+-p $parent} -m "$msg")
+		git update-ref refs/remotes/p4/master $parent
+	done
+}
+
+case "$1" in
+clone)
+	shift 2 # --bare
+	max=0
+	if [ "$1" = --max-changes ]; then max=$2; shift 2; fi
+	depot=${1#//}; depot=${depot%@all}
+	git init -q --bare "$2"
+	cd "$2"
+	changes=$(ls "FIXTURE/$depot" | sort -n)
+	if [ $max -gt 0 ]; then changes=$(echo "$changes" | tail -n $max); fi
+	import $depot "$changes"
+	;;
+sync)
+	msg=$(git log -1 --format=%B refs/remotes/p4/master)
+	depot=$(echo "$msg" | sed -n 's|.*depot-paths = "//\(.*\)/": change = \([0-9]*\).*|\1|p')
+	last=$(echo "$msg" | sed -n 's|.*depot-paths = "//\(.*\)/": change = \([0-9]*\).*|\2|p')
+	import $depot "$(ls "FIXTURE/$depot" | sort -n | awk -v last=$last '$1 > last')"
+	;;
+esac
+`
+
+func TestPerforceDepot(t *testing.T) {
+	fixture := tmpDir(t)
+	submit := func(change, desc, content string) {
+		t.Helper()
+		dir := filepath.Join(fixture, "Sourcegraph", change)
+		if err := os.MkdirAll(filepath.Join(dir, "files"), 0700); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(filepath.Join(dir, "desc"), []byte(desc), 0600); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(filepath.Join(dir, "files", "README"), []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	submit("1", "first", "a")
+	submit("2", "second", "b")
+
+	script := filepath.Join(tmpDir(t), "git-p4")
+	if err := ioutil.WriteFile(script, []byte(strings.Replace(fakeGitP4, "FIXTURE", fixture, -1)), 0700); err != nil {
+		t.Fatal(err)
+	}
+	origGitP4 := gitP4
+	gitP4 = []string{"sh", script}
+	testRepoExists = func(ctx context.Context, url string) error { return nil }
+	defer func() {
+		gitP4 = origGitP4
+		testRepoExists = nil
+	}()
+
+	s := &Server{
+		ReposDir:         tmpDir(t),
+		ctx:              context.Background(),
+		locker:           &RepositoryLocker{},
+		cloneLimiter:     mutablelimiter.New(1),
+		cloneableLimiter: mutablelimiter.New(1),
+		repoUpdateLocks:  make(map[api.RepoName]*locks),
+	}
+	ctx := context.Background()
+	cloneURL := &perforce.CloneURL{Port: "ssl:perforce.example.com:1666", User: "admin", Password: "s3cr3t", Depot: "//Sourcegraph/"}
+
+	log := func(repo api.RepoName) string {
+		t.Helper()
+		return strings.TrimSpace(runCmd(t, string(s.dir(repo)), "git", "log", "--format=%s", "HEAD"))
+	}
+
+	repo := api.RepoName("perforce.example.com/Sourcegraph")
+	if _, err := s.cloneRepo(ctx, repo, cloneURL.String(), &cloneOptions{Block: true}); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := log(repo), "second\nfirst"; got != want {
+		t.Fatalf("got log %q after clone, want %q", got, want)
+	}
+
+	// Updates import the new changelists.
+	submit("3", "third", "c")
+	if err := s.doRepoUpdate(ctx, repo, cloneURL.String()); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := log(repo), "third\nsecond\nfirst"; got != want {
+		t.Fatalf("got log %q after update, want %q", got, want)
+	}
+	if got := runCmd(t, string(s.dir(repo)), "git", "show", "HEAD:README"); got != "c" {
+		t.Fatalf("got README %q, want %q", got, "c")
+	}
+
+	// maxChanges limits the history imported by the clone.
+	cloneURL.MaxChanges = 1
+	repo = "perforce.example.com/Sourcegraph-recent"
+	if _, err := s.cloneRepo(ctx, repo, cloneURL.String(), &cloneOptions{Block: true}); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := log(repo), "third"; got != want {
+		t.Fatalf("got log %q after clone with maxChanges, want %q", got, want)
+	}
+
+	// The credentials are passed to the converter.
+	cloneURL.Password = "hunter2"
+	repo = "perforce.example.com/Sourcegraph-invalid"
+	if _, err := s.cloneRepo(ctx, repo, cloneURL.String(), &cloneOptions{Block: true}); err == nil || !strings.Contains(err.Error(), "invalid credentials") {
+		t.Fatalf("got error %v for invalid credentials", err)
+	} else if strings.Contains(err.Error(), cloneURL.Password) {
+		t.Fatalf("error %q contains the password", err)
+	}
+}
@@ -32,7 +32,9 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/diskcache"
 	"github.com/sourcegraph/sourcegraph/internal/env"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/perforce"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
 	"github.com/sourcegraph/sourcegraph/internal/honey"
 	"github.com/sourcegraph/sourcegraph/internal/lazyregexp"
@@ -116,6 +118,29 @@ type Server struct {
 	// DiskSizer tells how much disk is free and how large the disk is.
 	DiskSizer DiskSizer
 
+	// Hostname is the hostname of this gitserver. It is used to find this
+	// gitserver in the list of gitserver addresses, to determine which
+	// repositories it replicates.
+	Hostname string
+
+	// MaintenanceConcurrency is the maximum number of repositories the
+	// Janitor job runs git maintenance tasks on at the same time. It defaults
+	// to 1.
+	MaintenanceConcurrency int
+
+	// LFSMaxObjectSize is the size in bytes of the largest Git LFS object we
+	// fetch. Zero means no limit.
+	LFSMaxObjectSize int64
+
+	// LFSMaxRepoSize is the maximum size in bytes of the Git LFS objects we
+	// fetch per repository. Zero means no limit.
+	LFSMaxRepoSize int64
+
+	// ExecCacheMaxSize is the maximum size in bytes of the on disk cache of
+	// the output of git commands at absolute commits. Zero disables the
+	// cache.
+	ExecCacheMaxSize int64
+
 	// skipCloneForTests is set by tests to avoid clones.
 	skipCloneForTests bool
 
@@ -130,6 +155,10 @@ type Server struct {
 
 	locker *RepositoryLocker
 
+	// execCache caches the output of deterministic git commands. It is nil
+	// if the cache is disabled.
+	execCache *diskcache.Store
+
 	// cloneLimiter and cloneableLimiter limits the number of concurrent
 	// clones and ls-remotes respectively. Use s.acquireCloneLimiter() and
 	// s.acquireClonableLimiter() instead of using these directly.
@@ -138,11 +167,48 @@ type Server struct {
 
 	repoUpdateLocksMu sync.Mutex // protects the map below and also updates to locks.once
 	repoUpdateLocks   map[api.RepoName]*locks
+
+	// maintenanceQueue holds the repositories waiting for the maintenance
+	// workers, see queueMaintenance.
+	maintenanceQueue   chan GitDir
+	maintenanceMu      sync.Mutex      // protects maintenancePending
+	maintenancePending map[GitDir]bool // queued or running maintenance
+
+	repoSizesMu sync.Mutex
+	repoSizes   map[api.RepoName]repoSize // the recorded sizes of repositories, see recordRepoSize
+
+	lfsUpdatesMu sync.Mutex
+	lfsUpdates   map[GitDir]chan struct{} // closed once the running update of the Git LFS objects of a repository finishes
 }
 
 type locks struct {
 	once *sync.Once  // consolidates multiple waiting updates
-	mu   *sync.Mutex // prevents updates running in parallel
+	mu   *sync.Mutex // prevents updates, maintenance and overwriting clones running in parallel
+}
+
+// repoUpdateLocksLocked returns the update locks of repo, creating them if
+// needed. The caller must hold s.repoUpdateLocksMu.
+func (s *Server) repoUpdateLocksLocked(repo api.RepoName) *locks {
+	if s.repoUpdateLocks == nil {
+		s.repoUpdateLocks = make(map[api.RepoName]*locks)
+	}
+	l, ok := s.repoUpdateLocks[repo]
+	if !ok {
+		l = &locks{
+			once: new(sync.Once),
+			mu:   new(sync.Mutex),
+		}
+		s.repoUpdateLocks[repo] = l
+	}
+	return l
+}
+
+// repoUpdateMutex returns the mutex which is held while repo is fetched,
+// maintained or its clone is replaced.
+func (s *Server) repoUpdateMutex(repo api.RepoName) *sync.Mutex {
+	s.repoUpdateLocksMu.Lock()
+	defer s.repoUpdateLocksMu.Unlock()
+	return s.repoUpdateLocksLocked(repo).mu
 }
 
 // shortGitCommandTimeout returns the timeout for git commands that should not
@@ -199,6 +265,11 @@ func (s *Server) Handler() http.Handler {
 	s.ctx, s.cancel = context.WithCancel(context.Background())
 	s.locker = &RepositoryLocker{}
 	s.repoUpdateLocks = make(map[api.RepoName]*locks)
+	if s.DiskSizer == nil {
+		s.DiskSizer = &StatDiskSizer{}
+	}
+	s.startExecCache()
+	s.startMaintenance()
 
 	// GitMaxConcurrentClones controls the maximum number of clones that
 	// can happen at once on a single gitserver.
@@ -233,8 +304,10 @@ func (s *Server) Handler() http.Handler {
 	mux.HandleFunc("/repo-clone-progress", s.handleRepoCloneProgress)
 	mux.HandleFunc("/delete", s.handleRepoDelete)
 	mux.HandleFunc("/repo-update", s.handleRepoUpdate)
+	mux.HandleFunc("/disk-usage", s.handleDiskUsage)
 	mux.HandleFunc("/getGitolitePhabricatorMetadata", s.handleGetGitolitePhabricatorMetadata)
 	mux.HandleFunc("/create-commit-from-patch", s.handleCreateCommitFromPatch)
+	s.registerGitOps(mux)
 	mux.HandleFunc("/ping", func(w http.ResponseWriter, _ *http.Request) {
 		w.WriteHeader(http.StatusOK)
 	})
@@ -325,11 +398,12 @@ func (s *Server) tempDir(prefix string) (name string, err error) {
 }
 
 func (s *Server) ignorePath(path string) bool {
-	// We ignore any path which starts with .tmp in ReposDir
+	// We ignore any path which starts with .tmp in ReposDir, and the exec
+	// cache.
 	if filepath.Dir(path) != s.ReposDir {
 		return false
 	}
-	return strings.HasPrefix(filepath.Base(path), tempDirName)
+	return strings.HasPrefix(filepath.Base(path), tempDirName) || filepath.Base(path) == execCacheDirName
 }
 
 func (s *Server) handleIsRepoCloneable(w http.ResponseWriter, r *http.Request) {
@@ -409,17 +483,28 @@ func (s *Server) handleRepoUpdate(w http.ResponseWriter, r *http.Request) {
 		// optimistically, we assume that our cloning attempt might
 		// succeed.
 		resp.CloneInProgress = true
-		_, err := s.cloneRepo(ctx, req.Repo, req.URL, &cloneOptions{Block: true})
+		_, err := s.cloneRepo(ctx, req.Repo, req.URL, &cloneOptions{Block: true, Strategy: req.Clone})
 		if err != nil {
 			log15.Warn("error cloning repo", "repo", req.Repo, "err", err)
 			resp.Error = err.Error()
+		} else {
+			s.updateLFSForRequest(ctx, dir, &req)
+			if err := s.checkRepoSize(req.Repo, dir); err != nil {
+				resp.Error = err.Error()
+			}
 		}
 	} else {
 		resp.Cloned = true
 		var statusErr, updateErr error
 
 		if debounce(req.Repo, req.Since) {
-			updateErr = s.doRepoUpdate(ctx, req.Repo, req.URL)
+			updateErr = s.updateRepoForRequest(ctx, dir, &req)
+			if updateErr == nil {
+				s.updateLFSForRequest(ctx, dir, &req)
+				// The repository stays, but we report that it outgrew its
+				// size limit.
+				updateErr = s.checkRepoSize(req.Repo, dir)
+			}
 		}
 
 		// attempts to acquire these values are not contingent on the success of
@@ -455,6 +540,22 @@ func (s *Server) handleRepoUpdate(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// updateRepoForRequest fetches the repository of req, or reclones it if it
+// was cloned with another mode than req.Clone.
+func (s *Server) updateRepoForRequest(ctx context.Context, dir GitDir, req *protocol.RepoUpdateRequest) error {
+	reclone, err := updateCloneStrategy(dir, req.Clone)
+	if err != nil {
+		return err
+	}
+	if !reclone || useRefspecOverrides() {
+		return s.doRepoUpdate(ctx, req.Repo, req.URL)
+	}
+
+	log15.Info("recloning repo because its clone mode changed", "repo", req.Repo, "mode", cloneMode(req.Clone))
+	_, err = s.cloneRepo(ctx, req.Repo, req.URL, &cloneOptions{Block: true, Overwrite: true, Strategy: req.Clone})
+	return err
+}
+
 func (s *Server) handleArchive(w http.ResponseWriter, r *http.Request) {
 	var (
 		q       = r.URL.Query()
@@ -502,6 +603,21 @@ func (s *Server) handleArchive(w http.ResponseWriter, r *http.Request) {
 	req.Args = append(req.Args, treeish, "--")
 	req.Args = append(req.Args, paths...)
 
+	if q.Get("lfs") == "true" {
+		if dir := s.dir(protocol.NormalizeRepo(req.Repo)); lfsEnabled(dir) {
+			if err := s.waitForLFSUpdate(r.Context(), dir); err != nil {
+				log15.Warn("gitserver.archive", "error", err)
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+
+			aw := newLFSArchiveWriter(w, dir, format)
+			s.exec(aw, r, req)
+			aw.Close()
+			return
+		}
+	}
+
 	s.exec(w, r, req)
 }
 
@@ -655,6 +771,15 @@ func (s *Server) exec(w http.ResponseWriter, r *http.Request, req *protocol.Exec
 		ensureRevisionStatus = "noop"
 	}
 
+	if needsFullHistory(req.Args) && isShallowClone(dir) {
+		status = "history-unavailable"
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		_ = json.NewEncoder(w).Encode(&protocol.HistoryUnavailablePayload{
+			Reason: historyUnavailableReason(req.Args),
+		})
+		return
+	}
+
 	w.Header().Set("Trailer", "X-Exec-Error")
 	w.Header().Add("Trailer", "X-Exec-Exit-Status")
 	w.Header().Add("Trailer", "X-Exec-Stderr")
@@ -678,6 +803,24 @@ func (s *Server) exec(w http.ResponseWriter, r *http.Request, req *protocol.Exec
 	stderrW := &writeCounter{w: &limitWriter{W: &stderrBuf, N: 1024}}
 
 	cmdStart = time.Now()
+	if res, ok := s.openCachedExec(ctx, req.Repo, dir, req.Args); ok {
+		defer res.Stdout.Close()
+		var copyErr error
+		stdoutN, copyErr = io.Copy(w, res.Stdout)
+		execErr = res.Err
+		if execErr == nil {
+			execErr = copyErr
+		}
+		status = strconv.Itoa(res.ExitStatus)
+		stderrN = int64(len(res.Stderr))
+		checkMaybeCorruptRepo(req.Repo, dir, res.Stderr)
+
+		w.Header().Set("X-Exec-Error", errorString(execErr))
+		w.Header().Set("X-Exec-Exit-Status", status)
+		w.Header().Set("X-Exec-Stderr", res.Stderr)
+		return
+	}
+
 	cmd := exec.CommandContext(ctx, "git", req.Args...)
 	cmd.Dir = string(dir)
 	cmd.Stdout = stdoutW
@@ -730,6 +873,10 @@ type cloneOptions struct {
 
 	// Overwrite will overwrite the existing clone.
 	Overwrite bool
+
+	// Strategy is how to clone the repository, or nil to clone its whole
+	// history.
+	Strategy *protocol.CloneStrategy
 }
 
 // cloneRepo issues a git clone command for the given repo. It is
@@ -790,6 +937,8 @@ func (s *Server) cloneRepo(ctx context.Context, repo api.RepoName, url string, o
 		defer cancel1()
 		ctx, cancel2 := context.WithTimeout(ctx, longGitCommandTimeout)
 		defer cancel2()
+		ctx, abort := context.WithCancel(ctx)
+		defer abort()
 
 		dstPath := string(dir)
 		overwrite := opts != nil && opts.Overwrite
@@ -813,26 +962,85 @@ func (s *Server) cloneRepo(ctx context.Context, repo api.RepoName, url string, o
 		tmpPath = filepath.Join(tmpPath, ".git")
 		tmp := GitDir(tmpPath)
 
+		var strategy *protocol.CloneStrategy
+		if opts != nil {
+			strategy = fullClone(opts.Strategy)
+		}
+
 		var cmd *exec.Cmd
-		if useRefspecOverrides() {
+		configRemoteOpts := true
+		isPerforce := perforce.IsCloneURL(url)
+		if isPerforce {
+			// Depots are imported by git p4, so clone strategies don't
+			// apply to them.
+			strategy = nil
+			configRemoteOpts = false
+			cmd, err = perforceCloneCmd(ctx, url, tmpPath)
+			if err != nil {
+				return err
+			}
+		} else if useRefspecOverrides() {
+			// Clone strategies are not supported together with refspec
+			// overrides.
+			strategy = nil
 			cmd, err = refspecOverridesCloneCmd(ctx, url, tmpPath)
 			if err != nil {
 				return err
 			}
 		} else {
-			cmd = exec.CommandContext(ctx, "git", "clone", "--mirror", "--progress", url, tmpPath)
+			args := append([]string{"clone", "--mirror", "--progress"}, cloneStrategyCloneArgs(strategy)...)
+			cmd = exec.CommandContext(ctx, "git", append(args, url, tmpPath)...)
+		}
+		// see issue #7322: skip LFS content in repositories with Git LFS
+		// configured. If Git LFS is enabled for the repository, we fetch the
+		// objects ourselves after the clone (see fetchLFSObjects).
+		if cmd.Env == nil {
+			cmd.Env = os.Environ()
 		}
-		// see issue #7322: skip LFS content in repositories with Git LFS configured
-		cmd.Env = append(os.Environ(), "GIT_LFS_SKIP_SMUDGE=1")
+		cmd.Env = append(cmd.Env, "GIT_LFS_SKIP_SMUDGE=1")
 		log15.Info("cloning repo", "repo", repo, "tmp", tmpPath, "dst", dstPath)
 
 		pr, pw := io.Pipe()
 		defer pw.Close()
 		go readCloneProgress(redactor, lock, pr)
 
-		if output, err := runWithRemoteOpts(ctx, cmd, pw); err != nil {
+		// Abort clones of repositories which exceed their size limit, before
+		// they fill up the disk.
+		limit := maxRepoSize(repo)
+		var sizeWatcher *cloneSizeWatcher
+		if limit > 0 {
+			sizeWatcher = watchCloneSize(ctx, repo, tmpPath, limit, abort)
+		}
+		output, err := runWith(ctx, cmd, configRemoteOpts, pw)
+		if sizeWatcher != nil {
+			if err := sizeWatcher.stop(); err != nil {
+				return err
+			}
+		}
+		if err != nil {
 			return errors.Wrapf(err, "clone failed. Output: %s", string(output))
 		}
+		if isPerforce {
+			if err := setPerforceHead(ctx, tmp); err != nil {
+				return err
+			}
+		}
+		if err := writeCloneStrategy(tmp, strategy); err != nil {
+			return err
+		}
+		if cloneMode(strategy) == protocol.CloneModeBlobless {
+			if err := fetchMissingBlobsAtHead(ctx, tmp); err != nil {
+				return err
+			}
+		}
+		size, err := dirSize(tmpPath)
+		if err != nil {
+			return err
+		}
+		if limit > 0 && size > limit {
+			reposTooLarge.Inc()
+			return &repoTooLargeError{Repo: repo, Size: size, Limit: limit}
+		}
 
 		removeBadRefs(ctx, tmp)
 
@@ -847,6 +1055,12 @@ func (s *Server) cloneRepo(ctx context.Context, repo api.RepoName, url string, o
 		}
 
 		if overwrite {
+			// Wait for running fetches and maintenance of the current clone
+			// before replacing it.
+			mu := s.repoUpdateMutex(repo)
+			mu.Lock()
+			defer mu.Unlock()
+
 			// remove the current repo by putting it into our temporary directory
 			err := renameAndSync(dstPath, filepath.Join(filepath.Dir(tmpPath), "old"))
 			if err != nil && !os.IsNotExist(err) {
@@ -863,6 +1077,7 @@ func (s *Server) cloneRepo(ctx context.Context, repo api.RepoName, url string, o
 
 		log15.Info("repo cloned", "repo", repo)
 		repoClonedCounter.Inc()
+		s.recordRepoSize(repo, size)
 
 		return nil
 	}
@@ -994,6 +1209,9 @@ func (s *Server) isCloneable(ctx context.Context, url string) error {
 	if testRepoExists != nil {
 		return testRepoExists(ctx, url)
 	}
+	if perforce.IsCloneURL(url) {
+		return isPerforceCloneable(ctx, url)
+	}
 
 	cmd := exec.CommandContext(ctx, "git", args...)
 	out, err := runWithRemoteOpts(ctx, cmd, nil)
@@ -1050,14 +1268,7 @@ func (s *Server) doRepoUpdate(ctx context.Context, repo api.RepoName, url string
 	defer span.Finish()
 
 	s.repoUpdateLocksMu.Lock()
-	l, ok := s.repoUpdateLocks[repo]
-	if !ok {
-		l = &locks{
-			once: new(sync.Once),
-			mu:   new(sync.Mutex),
-		}
-		s.repoUpdateLocks[repo] = l
-	}
+	l := s.repoUpdateLocksLocked(repo)
 	once := l.once
 	mu := l.mu
 	s.repoUpdateLocksMu.Unlock()
@@ -1298,6 +1509,15 @@ func (s *Server) doRepoUpdate2(repo api.RepoName, url string) error {
 		}
 	}
 
+	if perforce.IsCloneURL(url) {
+		return s.doPerforceUpdate(ctx, repo, dir, url)
+	}
+
+	strategy, err := readCloneStrategy(dir)
+	if err != nil {
+		return errors.Wrap(err, "failed to read clone strategy")
+	}
+
 	configRemoteOpts := true
 	var cmd *exec.Cmd
 	if customCmd := customFetchCmd(ctx, url); customCmd != nil {
@@ -1306,7 +1526,16 @@ func (s *Server) doRepoUpdate2(repo api.RepoName, url string) error {
 	} else if useRefspecOverrides() {
 		cmd = refspecOverridesFetchCmd(ctx, url)
 	} else {
-		cmd = exec.CommandContext(ctx, "git", "fetch", "--prune", url, "+refs/heads/*:refs/heads/*", "+refs/tags/*:refs/tags/*", "+refs/pull/*:refs/pull/*", "+refs/sourcegraph/*:refs/sourcegraph/*")
+		remote := url
+		if cloneMode(strategy) == protocol.CloneModeBlobless {
+			// Fetch from the remote we cloned from (which has the URL set
+			// above), so that git records the objects it omits as
+			// available from it.
+			remote = "origin"
+		}
+		args := append([]string{"fetch", "--prune"}, cloneStrategyFetchArgs(strategy)...)
+		args = append(args, remote, "+refs/heads/*:refs/heads/*", "+refs/tags/*:refs/tags/*", "+refs/pull/*:refs/pull/*", "+refs/sourcegraph/*:refs/sourcegraph/*")
+		cmd = exec.CommandContext(ctx, "git", args...)
 	}
 	cmd.Dir = string(dir)
 
@@ -1372,6 +1601,28 @@ func (s *Server) doRepoUpdate2(repo api.RepoName, url string) error {
 		log15.Error("Failed to set HEAD", "repo", repo, "error", err, "output", string(output))
 		return errors.Wrap(err, "Failed to set HEAD")
 	}
+
+	if cloneMode(strategy) == protocol.CloneModeBlobless {
+		if err := fetchMissingBlobsAtHead(ctx, dir); err != nil {
+			log15.Warn("Failed to fetch missing blobs", "repo", repo, "error", err)
+		}
+	}
+	return nil
+}
+
+// doPerforceUpdate imports the changelists submitted to the depot at url
+// since the last update of repo.
+func (s *Server) doPerforceUpdate(ctx context.Context, repo api.RepoName, dir GitDir, url string) error {
+	defer s.cleanTmpFiles(dir)
+
+	if output, err := syncPerforceDepot(ctx, dir, url); err != nil {
+		log15.Error("Failed to update", "repo", repo, "error", err, "output", newURLRedactor(url).redact(string(output)))
+		return errors.Wrap(err, "failed to update")
+	}
+
+	if err := setLastChanged(dir); err != nil {
+		log15.Warn("Failed to update last changed time", "repo", repo, "error", err)
+	}
 	return nil
 }
 
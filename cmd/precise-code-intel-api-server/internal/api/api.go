@@ -25,6 +25,20 @@ type CodeIntelAPI interface {
 
 	// Hover returns the hover text and range for the symbol at the given position.
 	Hover(ctx context.Context, file string, line, character, uploadID int) (string, bundles.Range, bool, error)
+
+	// DocumentSymbols returns the symbols defined in the given file, nested by containment.
+	DocumentSymbols(ctx context.Context, file string, uploadID int) ([]bundles.Symbol, error)
+
+	// SearchSymbols returns up to limit symbols matching the given query from the dumps of the given
+	// repository at exactly the given commit. Paths are relative to the repository, and the query's
+	// Root is ignored. Symbols of dumps at other commits are not returned, as their positions may not
+	// match the requested commit.
+	SearchSymbols(ctx context.Context, repositoryID int, commit string, query bundles.SymbolQuery, limit int) ([]ResolvedSymbol, error)
+
+	// Ranges returns the hover text, definitions, and number of references of the ranges containing the
+	// given positions of the given file. If no positions are given, every range of the file is returned.
+	// Definitions and hover text missing from the dump are resolved as in Definitions and Hover.
+	Ranges(ctx context.Context, file string, positions []bundles.Position, uploadID int) ([]ResolvedCodeIntelligenceRange, error)
 }
 
 type codeIntelAPI struct {
@@ -0,0 +1,23 @@
+package api
+
+import (
+	"context"
+	"strings"
+
+	bundles "github.com/sourcegraph/sourcegraph/internal/codeintel/bundles/client"
+)
+
+// DocumentSymbols returns the symbols defined in the given file, nested by containment.
+func (api *codeIntelAPI) DocumentSymbols(ctx context.Context, file string, uploadID int) ([]bundles.Symbol, error) {
+	dump, exists, err := api.db.GetDumpByID(ctx, uploadID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrMissingDump
+	}
+
+	pathInBundle := strings.TrimPrefix(file, dump.Root)
+	bundleClient := api.bundleManagerClient.BundleClient(dump.ID)
+	return bundleClient.DocumentSymbols(ctx, pathInBundle)
+}
@@ -0,0 +1,48 @@
+package api
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	bundles "github.com/sourcegraph/sourcegraph/internal/codeintel/bundles/client"
+	bundlemocks "github.com/sourcegraph/sourcegraph/internal/codeintel/bundles/mocks"
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/db"
+	dbmocks "github.com/sourcegraph/sourcegraph/internal/codeintel/db/mocks"
+)
+
+func TestDocumentSymbols(t *testing.T) {
+	mockDB := dbmocks.NewMockDB()
+	mockBundleManagerClient := bundlemocks.NewMockBundleManagerClient()
+	mockBundleClient := bundlemocks.NewMockBundleClient()
+
+	symbols := []bundles.Symbol{
+		{Name: "Server", Kind: 23, Range: testRange1, Children: []bundles.Symbol{{Name: "addr", Kind: 8, Range: testRange2}}},
+		{Name: "main", Kind: 12, Range: testRange3},
+	}
+
+	setMockDBGetDumpByID(t, mockDB, map[int]db.Dump{42: testDump1})
+	setMockBundleManagerClientBundleClient(t, mockBundleManagerClient, map[int]bundles.BundleClient{42: mockBundleClient})
+	setMockBundleClientDocumentSymbols(t, mockBundleClient, "main.go", symbols)
+
+	api := New(mockDB, mockBundleManagerClient)
+	actual, err := api.DocumentSymbols(context.Background(), "sub1/main.go", 42)
+	if err != nil {
+		t.Fatalf("unexpected error getting document symbols: %s", err)
+	}
+
+	if diff := cmp.Diff(symbols, actual); diff != "" {
+		t.Errorf("unexpected symbols (-want +got):\n%s", diff)
+	}
+}
+
+func TestDocumentSymbolsUnknownDump(t *testing.T) {
+	mockDB := dbmocks.NewMockDB()
+	mockBundleManagerClient := bundlemocks.NewMockBundleManagerClient()
+	setMockDBGetDumpByID(t, mockDB, nil)
+
+	api := New(mockDB, mockBundleManagerClient)
+	if _, err := api.DocumentSymbols(context.Background(), "sub1/main.go", 42); err != ErrMissingDump {
+		t.Fatalf("unexpected error getting document symbols. want=%q have=%q", ErrMissingDump, err)
+	}
+}
@@ -85,8 +85,8 @@ func setMockDBFindClosestDumps(t *testing.T, mockDB *dbmocks.MockDB, expectedRep
 	})
 }
 
-func setMockDBSameRepoPager(t *testing.T, mockDB *dbmocks.MockDB, expectedRepositoryID int, expectedCommit, expectedScheme, expectedName, expectedVersion string, expectedLimit, totalCount int, pager db.ReferencePager) {
-	mockDB.SameRepoPagerFunc.SetDefaultHook(func(ctx context.Context, repositoryID int, commit, scheme, name, version string, limit int) (int, db.ReferencePager, error) {
+func setMockDBSameRepoPager(t *testing.T, mockDB *dbmocks.MockDB, expectedRepositoryID int, expectedCommit, expectedScheme, expectedName, expectedVersion, expectedIdentifier string, expectedLimit, totalCount int, pager db.ReferencePager) {
+	mockDB.SameRepoPagerFunc.SetDefaultHook(func(ctx context.Context, repositoryID int, commit, scheme, name, version, identifier string, limit int) (int, db.ReferencePager, error) {
 		if repositoryID != expectedRepositoryID {
 			t.Errorf("unexpected repository id for SameRepoPager. want=%v have=%v", expectedRepositoryID, repositoryID)
 		}
@@ -102,6 +102,9 @@ func setMockDBSameRepoPager(t *testing.T, mockDB *dbmocks.MockDB, expectedReposi
 		if version != expectedVersion {
 			t.Errorf("unexpected version for SameRepoPager. want=%s have=%s", expectedVersion, version)
 		}
+		if identifier != expectedIdentifier {
+			t.Errorf("unexpected identifier for SameRepoPager. want=%s have=%s", expectedIdentifier, identifier)
+		}
 		if limit != expectedLimit {
 			t.Errorf("unexpected limit for SameRepoPager. want=%d have=%d", expectedLimit, limit)
 		}
@@ -109,8 +112,8 @@ func setMockDBSameRepoPager(t *testing.T, mockDB *dbmocks.MockDB, expectedReposi
 	})
 }
 
-func setMockDBPackageReferencePager(t *testing.T, mockDB *dbmocks.MockDB, expectedScheme, expectedName, expectedVersion string, expectedRepositoryID, expectedLimit int, totalCount int, pager db.ReferencePager) {
-	mockDB.PackageReferencePagerFunc.SetDefaultHook(func(ctx context.Context, scheme, name, version string, repositoryID, limit int) (int, db.ReferencePager, error) {
+func setMockDBPackageReferencePager(t *testing.T, mockDB *dbmocks.MockDB, expectedScheme, expectedName, expectedVersion, expectedIdentifier string, expectedRepositoryID, expectedLimit int, totalCount int, pager db.ReferencePager) {
+	mockDB.PackageReferencePagerFunc.SetDefaultHook(func(ctx context.Context, scheme, name, version, identifier string, repositoryID, limit int) (int, db.ReferencePager, error) {
 		if scheme != expectedScheme {
 			t.Errorf("unexpected scheme for PackageReferencePager. want=%s have=%s", expectedScheme, scheme)
 		}
@@ -120,6 +123,9 @@ func setMockDBPackageReferencePager(t *testing.T, mockDB *dbmocks.MockDB, expect
 		if version != expectedVersion {
 			t.Errorf("unexpected version for PackageReferencePager. want=%s have=%s", expectedVersion, version)
 		}
+		if identifier != expectedIdentifier {
+			t.Errorf("unexpected identifier for PackageReferencePager. want=%s have=%s", expectedIdentifier, identifier)
+		}
 		if repositoryID != expectedRepositoryID {
 			t.Errorf("unexpected repository id for PackageReferencePager. want=%d have=%d", expectedRepositoryID, repositoryID)
 		}
@@ -204,6 +210,15 @@ func setMockBundleClientHover(t *testing.T, mockBundleClient *bundlemocks.MockBu
 	})
 }
 
+func setMockBundleClientDocumentSymbols(t *testing.T, mockBundleClient *bundlemocks.MockBundleClient, expectedPath string, symbols []bundles.Symbol) {
+	mockBundleClient.DocumentSymbolsFunc.SetDefaultHook(func(ctx context.Context, path string) ([]bundles.Symbol, error) {
+		if path != expectedPath {
+			t.Errorf("unexpected path DocumentSymbols. want=%s have=%s", expectedPath, path)
+		}
+		return symbols, nil
+	})
+}
+
 func setMockBundleClientMonikersByPosition(t *testing.T, mockBundleClient *bundlemocks.MockBundleClient, expectedPath string, expectedLine, expectedCharacter int, monikers [][]bundles.MonikerData) {
 	mockBundleClient.MonikersByPositionFunc.SetDefaultHook(func(ctx context.Context, path string, line, character int) ([][]bundles.MonikerData, error) {
 		if path != expectedPath {
@@ -0,0 +1,151 @@
+package api
+
+import (
+	"context"
+	"strings"
+
+	bundles "github.com/sourcegraph/sourcegraph/internal/codeintel/bundles/client"
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/db"
+)
+
+// ResolvedCodeIntelligenceRange is the hover text, definitions, and number of references of a
+// range of a file, with the definitions resolved to their dumps.
+type ResolvedCodeIntelligenceRange struct {
+	Range          bundles.Range
+	HoverText      string
+	Definitions    []ResolvedLocation
+	ReferenceCount int
+}
+
+// Ranges returns the hover text, definitions, and number of references of the ranges containing the
+// given positions of the given file. If no positions are given, every range of the file is returned.
+func (api *codeIntelAPI) Ranges(ctx context.Context, file string, positions []bundles.Position, uploadID int) ([]ResolvedCodeIntelligenceRange, error) {
+	dump, exists, err := api.db.GetDumpByID(ctx, uploadID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrMissingDump
+	}
+
+	pathInBundle := strings.TrimPrefix(file, dump.Root)
+	bundleClient := api.bundleManagerClient.BundleClient(dump.ID)
+
+	ranges, err := bundleClient.Ranges(ctx, pathInBundle, positions)
+	if err != nil {
+		return nil, err
+	}
+
+	// Ranges of the same symbol share monikers and definitions, so each lookup is done once per batch
+	resolver := &rangeResolver{
+		api:          api,
+		dump:         dump,
+		bundleClient: bundleClient,
+		pathInBundle: pathInBundle,
+		definitions:  map[bundles.MonikerData][]ResolvedLocation{},
+		hovers:       map[hoverKey]string{},
+	}
+
+	resolved := make([]ResolvedCodeIntelligenceRange, 0, len(ranges))
+	for _, r := range ranges {
+		resolvedRange, err := resolver.resolve(ctx, r)
+		if err != nil {
+			return nil, err
+		}
+
+		resolved = append(resolved, resolvedRange)
+	}
+
+	return resolved, nil
+}
+
+// rangeResolver resolves the definitions and hover text of ranges that are not answered by the
+// dump itself, as Definitions and Hover do for a single position.
+type rangeResolver struct {
+	api          *codeIntelAPI
+	dump         db.Dump
+	bundleClient bundles.BundleClient
+	pathInBundle string
+	definitions  map[bundles.MonikerData][]ResolvedLocation
+	hovers       map[hoverKey]string
+}
+
+// hoverKey identifies the position of a definition within a dump.
+type hoverKey struct {
+	dumpID   int
+	path     string
+	position bundles.Position
+}
+
+func (r *rangeResolver) resolve(ctx context.Context, codeIntelligenceRange bundles.CodeIntelligenceRange) (ResolvedCodeIntelligenceRange, error) {
+	definitions := resolveLocationsWithDump(r.dump, codeIntelligenceRange.Definitions)
+	if len(definitions) == 0 {
+		var err error
+		if definitions, err = r.monikerDefinitions(ctx, codeIntelligenceRange.Monikers); err != nil {
+			return ResolvedCodeIntelligenceRange{}, err
+		}
+	}
+
+	hoverText := codeIntelligenceRange.HoverText
+	if hoverText == "" && len(definitions) > 0 {
+		var err error
+		if hoverText, err = r.definitionHover(ctx, definitions[0]); err != nil {
+			return ResolvedCodeIntelligenceRange{}, err
+		}
+	}
+
+	return ResolvedCodeIntelligenceRange{
+		Range:          codeIntelligenceRange.Range,
+		HoverText:      hoverText,
+		Definitions:    definitions,
+		ReferenceCount: codeIntelligenceRange.ReferenceCount,
+	}, nil
+}
+
+// monikerDefinitions returns the definitions of the first of the given monikers that has any.
+func (r *rangeResolver) monikerDefinitions(ctx context.Context, monikers []bundles.MonikerData) ([]ResolvedLocation, error) {
+	for _, moniker := range monikers {
+		definitions, ok := r.definitions[moniker]
+		if !ok {
+			var err error
+			if moniker.Kind == "import" {
+				definitions, _, err = lookupMoniker(r.api.db, r.api.bundleManagerClient, r.dump.ID, r.pathInBundle, "definition", moniker, 0, 0)
+			} else {
+				// See definitionsRaw for why we search the definitions of our own dump
+				var locations []bundles.Location
+				locations, _, err = r.bundleClient.MonikerResults(ctx, "definition", moniker.Scheme, moniker.Identifier, 0, 0)
+				definitions = resolveLocationsWithDump(r.dump, locations)
+			}
+			if err != nil {
+				return nil, err
+			}
+
+			r.definitions[moniker] = definitions
+		}
+
+		if len(definitions) > 0 {
+			return definitions, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// definitionHover returns the hover text at the given definition.
+func (r *rangeResolver) definitionHover(ctx context.Context, definition ResolvedLocation) (string, error) {
+	key := hoverKey{dumpID: definition.Dump.ID, path: definition.Path, position: definition.Range.Start}
+	if text, ok := r.hovers[key]; ok {
+		return text, nil
+	}
+
+	pathInDefinitionBundle := strings.TrimPrefix(definition.Path, definition.Dump.Root)
+	definitionBundleClient := r.api.bundleManagerClient.BundleClient(definition.Dump.ID)
+
+	text, _, _, err := definitionBundleClient.Hover(ctx, pathInDefinitionBundle, definition.Range.Start.Line, definition.Range.Start.Character)
+	if err != nil {
+		return "", err
+	}
+
+	r.hovers[key] = text
+	return text, nil
+}
@@ -0,0 +1,79 @@
+package api
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	bundles "github.com/sourcegraph/sourcegraph/internal/codeintel/bundles/client"
+	bundlemocks "github.com/sourcegraph/sourcegraph/internal/codeintel/bundles/mocks"
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/db"
+	dbmocks "github.com/sourcegraph/sourcegraph/internal/codeintel/db/mocks"
+)
+
+func TestRanges(t *testing.T) {
+	mockDB := dbmocks.NewMockDB()
+	mockBundleManagerClient := bundlemocks.NewMockBundleManagerClient()
+	mockBundleClient1 := bundlemocks.NewMockBundleClient()
+	mockBundleClient2 := bundlemocks.NewMockBundleClient()
+
+	positions := []bundles.Position{{Line: 10, Character: 52}, {Line: 11, Character: 52}, {Line: 12, Character: 52}}
+
+	setMockDBGetDumpByID(t, mockDB, map[int]db.Dump{42: testDump1, 50: testDump2})
+	setMockBundleManagerClientBundleClient(t, mockBundleManagerClient, map[int]bundles.BundleClient{42: mockBundleClient1, 50: mockBundleClient2})
+	mockBundleClient1.RangesFunc.SetDefaultHook(func(ctx context.Context, path string, p []bundles.Position) ([]bundles.CodeIntelligenceRange, error) {
+		if path != "main.go" {
+			t.Errorf("unexpected path for Ranges. want=%s have=%s", "main.go", path)
+		}
+		if diff := cmp.Diff(positions, p); diff != "" {
+			t.Errorf("unexpected positions for Ranges (-want +got):\n%s", diff)
+		}
+
+		return []bundles.CodeIntelligenceRange{
+			{Range: testRange1, HoverText: "local", Definitions: []bundles.Location{{DumpID: 42, Path: "foo.go", Range: testRange4}}, ReferenceCount: 3},
+			{Range: testRange2, Monikers: []bundles.MonikerData{testMoniker1}, ReferenceCount: 1},
+			{Range: testRange3, Monikers: []bundles.MonikerData{testMoniker1}},
+		}, nil
+	})
+	setMockBundleClientPackageInformation(t, mockBundleClient1, "main.go", "1234", testPackageInformation)
+	setMockDBGetPackage(t, mockDB, "gomod", "leftpad", "0.1.0", testDump2, true)
+	setMockBundleClientMonikerResults(t, mockBundleClient2, "definition", "gomod", "pad", 0, 0, []bundles.Location{
+		{DumpID: 50, Path: "foo.go", Range: testRange5},
+	}, 1)
+	setMockBundleClientHover(t, mockBundleClient2, "foo.go", 14, 50, "remote", testRange5, true)
+
+	api := New(mockDB, mockBundleManagerClient)
+	ranges, err := api.Ranges(context.Background(), "sub1/main.go", positions, 42)
+	if err != nil {
+		t.Fatalf("unexpected error getting ranges: %s", err)
+	}
+
+	remoteDefinitions := []ResolvedLocation{{Dump: testDump2, Path: "sub2/foo.go", Range: testRange5}}
+	expected := []ResolvedCodeIntelligenceRange{
+		{Range: testRange1, HoverText: "local", Definitions: []ResolvedLocation{{Dump: testDump1, Path: "sub1/foo.go", Range: testRange4}}, ReferenceCount: 3},
+		{Range: testRange2, HoverText: "remote", Definitions: remoteDefinitions, ReferenceCount: 1},
+		{Range: testRange3, HoverText: "remote", Definitions: remoteDefinitions},
+	}
+	if diff := cmp.Diff(expected, ranges); diff != "" {
+		t.Errorf("unexpected ranges (-want +got):\n%s", diff)
+	}
+
+	// Remote lookups are shared by ranges with the same moniker
+	if len(mockBundleClient2.MonikerResultsFunc.History()) != 1 {
+		t.Errorf("unexpected number of MonikerResultsFunc calls. want=%d have=%d", 1, len(mockBundleClient2.MonikerResultsFunc.History()))
+	}
+	if len(mockBundleClient2.HoverFunc.History()) != 1 {
+		t.Errorf("unexpected number of HoverFunc calls. want=%d have=%d", 1, len(mockBundleClient2.HoverFunc.History()))
+	}
+}
+
+func TestRangesUnknownDump(t *testing.T) {
+	mockDB := dbmocks.NewMockDB()
+	mockBundleManagerClient := bundlemocks.NewMockBundleManagerClient()
+	setMockDBGetDumpByID(t, mockDB, nil)
+
+	api := New(mockDB, mockBundleManagerClient)
+	if _, err := api.Ranges(context.Background(), "sub1/main.go", nil, 42); err != ErrMissingDump {
+		t.Fatalf("unexpected error getting ranges. want=%q have=%q", ErrMissingDump, err)
+	}
+}
@@ -212,7 +212,7 @@ func (s *ReferencePageResolver) handleDefinitionMonikersCursor(ctx context.Conte
 
 func (s *ReferencePageResolver) handleSameRepoCursor(ctx context.Context, cursor Cursor) ([]ResolvedLocation, Cursor, bool, error) {
 	locations, newCursor, hasNewCursor, err := s.resolveLocationsViaReferencePager(ctx, cursor, func(ctx context.Context) (int, db.ReferencePager, error) {
-		return s.db.SameRepoPager(ctx, s.repositoryID, s.commit, cursor.Scheme, cursor.Name, cursor.Version, s.remoteDumpLimit)
+		return s.db.SameRepoPager(ctx, s.repositoryID, s.commit, cursor.Scheme, cursor.Name, cursor.Version, cursor.Identifier, s.remoteDumpLimit)
 	})
 	if err != nil || hasNewCursor {
 		return locations, newCursor, hasNewCursor, err
@@ -236,7 +236,7 @@ func (s *ReferencePageResolver) handleSameRepoCursor(ctx context.Context, cursor
 
 func (s *ReferencePageResolver) handleRemoteRepoCursor(ctx context.Context, cursor Cursor) ([]ResolvedLocation, Cursor, bool, error) {
 	return s.resolveLocationsViaReferencePager(ctx, cursor, func(ctx context.Context) (int, db.ReferencePager, error) {
-		return s.db.PackageReferencePager(ctx, cursor.Scheme, cursor.Name, cursor.Version, s.repositoryID, s.remoteDumpLimit)
+		return s.db.PackageReferencePager(ctx, cursor.Scheme, cursor.Name, cursor.Version, cursor.Identifier, s.repositoryID, s.remoteDumpLimit)
 	})
 }
 
@@ -270,6 +270,9 @@ func (s *ReferencePageResolver) resolveLocationsViaReferencePager(ctx context.Co
 				break
 			}
 
+			// The pager only returns dumps with indexed identifiers if they reference the
+			// identifier. Those pass the bloom filter as well, so the filter only narrows
+			// down dumps whose identifiers are not yet indexed.
 			filtered, scanned := applyBloomFilter(page, identifier, limit-len(packageReferences))
 			packageReferences = append(packageReferences, filtered...)
 			newOffset += scanned
@@ -0,0 +1,57 @@
+package api
+
+import (
+	"context"
+
+	bundles "github.com/sourcegraph/sourcegraph/internal/codeintel/bundles/client"
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/db"
+)
+
+// ResolvedSymbol is a symbol defined in a dump. Its path is relative to the repository.
+type ResolvedSymbol struct {
+	Dump          db.Dump       `json:"dump"`
+	Name          string        `json:"name"`
+	Kind          int           `json:"kind"`
+	ContainerName string        `json:"containerName"`
+	Path          string        `json:"path"`
+	Range         bundles.Range `json:"range"`
+}
+
+// SearchSymbols returns up to limit symbols matching the given query from the dumps of the given
+// repository at exactly the given commit. Paths are relative to the repository, and the query's
+// Root is ignored. Symbols of dumps at other commits are not returned, as their positions may not
+// match the requested commit.
+func (api *codeIntelAPI) SearchSymbols(ctx context.Context, repositoryID int, commit string, query bundles.SymbolQuery, limit int) ([]ResolvedSymbol, error) {
+	dumps, err := api.db.GetDumpsByRepo(ctx, repositoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	symbols := []ResolvedSymbol{}
+	for _, dump := range dumps {
+		if dump.Commit != commit || len(symbols) >= limit {
+			continue
+		}
+
+		// The bundle manager matches the path patterns against repository-relative paths
+		query.Root = dump.Root
+
+		locations, err := api.bundleManagerClient.BundleClient(dump.ID).SearchSymbols(ctx, query, limit-len(symbols))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, location := range locations {
+			symbols = append(symbols, ResolvedSymbol{
+				Dump:          dump,
+				Name:          location.Name,
+				Kind:          location.Kind,
+				ContainerName: location.ContainerName,
+				Path:          dump.Root + location.Path,
+				Range:         location.Range,
+			})
+		}
+	}
+
+	return symbols, nil
+}
@@ -0,0 +1,52 @@
+package api
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	bundles "github.com/sourcegraph/sourcegraph/internal/codeintel/bundles/client"
+	bundlemocks "github.com/sourcegraph/sourcegraph/internal/codeintel/bundles/mocks"
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/db"
+	dbmocks "github.com/sourcegraph/sourcegraph/internal/codeintel/db/mocks"
+)
+
+func TestSearchSymbols(t *testing.T) {
+	mockDB := dbmocks.NewMockDB()
+	mockBundleManagerClient := bundlemocks.NewMockBundleManagerClient()
+	mockBundleClient1 := bundlemocks.NewMockBundleClient()
+	mockBundleClient2 := bundlemocks.NewMockBundleClient()
+
+	dump1 := db.Dump{ID: 42, Commit: testCommit, Root: "sub1/"}
+	dump2 := db.Dump{ID: 50, Commit: testCommit, Root: "sub2/"}
+	dump3 := db.Dump{ID: 51, Commit: "1111111111111111111111111111111111111111", Root: "sub3/"}
+
+	mockDB.GetDumpsByRepoFunc.SetDefaultReturn([]db.Dump{dump1, dump2, dump3}, nil)
+	setMockBundleManagerClientBundleClient(t, mockBundleManagerClient, map[int]bundles.BundleClient{42: mockBundleClient1, 50: mockBundleClient2})
+	mockBundleClient1.SearchSymbolsFunc.SetDefaultHook(func(ctx context.Context, query bundles.SymbolQuery, limit int) ([]bundles.SymbolLocation, error) {
+		if query.Root != "sub1/" || limit != 2 {
+			t.Errorf("unexpected SearchSymbols args: root=%q limit=%d", query.Root, limit)
+		}
+		return []bundles.SymbolLocation{{Name: "Server", Kind: 23, Path: "main.go", Range: testRange1}}, nil
+	})
+	mockBundleClient2.SearchSymbolsFunc.SetDefaultHook(func(ctx context.Context, query bundles.SymbolQuery, limit int) ([]bundles.SymbolLocation, error) {
+		if query.Root != "sub2/" || limit != 1 {
+			t.Errorf("unexpected SearchSymbols args: root=%q limit=%d", query.Root, limit)
+		}
+		return []bundles.SymbolLocation{{Name: "ServeHTTP", Kind: 6, ContainerName: "Server", Path: "server.go", Range: testRange2}}, nil
+	})
+
+	api := New(mockDB, mockBundleManagerClient)
+	actual, err := api.SearchSymbols(context.Background(), 50, testCommit, bundles.SymbolQuery{Query: "^Serve"}, 2)
+	if err != nil {
+		t.Fatalf("unexpected error searching symbols: %s", err)
+	}
+
+	expected := []ResolvedSymbol{
+		{Dump: dump1, Name: "Server", Kind: 23, Path: "sub1/main.go", Range: testRange1},
+		{Dump: dump2, Name: "ServeHTTP", Kind: 6, ContainerName: "Server", Path: "sub2/server.go", Range: testRange2},
+	}
+	if diff := cmp.Diff(expected, actual); diff != "" {
+		t.Errorf("unexpected symbols (-want +got):\n%s", diff)
+	}
+}
@@ -28,7 +28,8 @@ func NewUploadResetter(opts UploadResetterOpts) *UploadResetter {
 // Run periodically moves all uploads that have been in the PROCESSING state for a
 // while back to QUEUED. For each updated upload record, the conversion process that
 // was responsible for handling the upload did not hold a row lock, indicating that
-// it has died.
+// it has died. Indexes which have been in the PROCESSING state for longer than
+// db.StalledIndexMaxAge are moved back to QUEUED as well.
 func (ur *UploadResetter) Run() {
 	for {
 		ids, err := ur.db.ResetStalled(context.Background(), time.Now())
@@ -39,6 +40,14 @@ func (ur *UploadResetter) Run() {
 			log15.Debug("Reset stalled upload", "uploadID", id)
 		}
 
+		indexIDs, err := ur.db.ResetStalledIndexes(context.Background(), time.Now())
+		if err != nil {
+			log15.Error("Failed to reset stalled indexes", "error", err)
+		}
+		for _, id := range indexIDs {
+			log15.Debug("Reset stalled index", "indexID", id)
+		}
+
 		time.Sleep(ur.resetInterval)
 	}
 }
@@ -0,0 +1,233 @@
+package retention
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/db"
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/gitserver"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// MaxBranchDepth is the number of commits of each branch searched for the latest uploads
+// of the branch. Uploads of older commits are not retained by the keepLatestPerBranch policy.
+const MaxBranchDepth = 1000
+
+// Policy decides which dumps of a repository are retained. A dump is retained if any of the
+// keep policies applies to it. Other dumps expire once they are older than MaxAge.
+type Policy struct {
+	KeepVisibleFromTip  bool
+	KeepTaggedCommits   bool
+	KeepLatestPerBranch int
+	Branches            []*regexp.Regexp
+	MaxAge              time.Duration // zero never expires dumps
+	DryRun              bool
+}
+
+// PolicyFromConfig converts the given site configuration into a policy. This method returns
+// a false-valued flag if no retention policy is configured.
+func PolicyFromConfig(c *schema.CodeIntelRetention) (Policy, bool, error) {
+	if c == nil {
+		return Policy{}, false, nil
+	}
+
+	var branches []*regexp.Regexp
+	for _, pattern := range c.Branches {
+		branch, err := regexp.Compile(pattern)
+		if err != nil {
+			return Policy{}, false, errors.Wrap(err, "codeIntelRetention.branches")
+		}
+
+		branches = append(branches, branch)
+	}
+
+	return Policy{
+		KeepVisibleFromTip:  c.KeepVisibleFromTip == nil || *c.KeepVisibleFromTip,
+		KeepTaggedCommits:   c.KeepTaggedCommits == nil || *c.KeepTaggedCommits,
+		KeepLatestPerBranch: c.KeepLatestPerBranch,
+		Branches:            branches,
+		MaxAge:              time.Duration(c.MaxAgeDays) * 24 * time.Hour,
+		DryRun:              c.DryRun,
+	}, true, nil
+}
+
+// Decision is the outcome of evaluating a policy against a single dump.
+type Decision struct {
+	Dump            db.Dump  `json:"upload"`
+	RetainedBecause []string `json:"retainedBecause"`
+	Expired         bool     `json:"expired"`
+}
+
+// AncestorCache remembers the ancestors of the branch tips of each repository between
+// evaluations, so that gitserver is only asked for the ancestors of branches that moved. The
+// ancestors of a commit never change. The entries of a repository are replaced by those of
+// its current branch tips on each evaluation, which bounds the cache by the number of branches.
+type AncestorCache struct {
+	mu      sync.Mutex
+	commits map[int]map[string][]string
+}
+
+// NewAncestorCache creates an empty ancestor cache.
+func NewAncestorCache() *AncestorCache {
+	return &AncestorCache{commits: map[int]map[string][]string{}}
+}
+
+// get returns the ancestors of the branch tips of the given repository, keyed by tip commit.
+func (c *AncestorCache) get(repositoryID int) map[string][]string {
+	if c == nil {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.commits[repositoryID]
+}
+
+// set replaces the ancestors of the branch tips of the given repository.
+func (c *AncestorCache) set(repositoryID int, commits map[string][]string) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.commits[repositoryID] = commits
+}
+
+// Evaluate returns the decisions of the given policy for each dump of the given repository,
+// from the most to the least recently uploaded dump. The ancestors of branch tips are read
+// from and stored in the given cache, which may be nil. Branches that point to the same
+// commit share a single request to gitserver.
+func Evaluate(ctx context.Context, db db.DB, gitserverClient gitserver.Client, cache *AncestorCache, policy Policy, repositoryID int, now time.Time) ([]Decision, error) {
+	dumps, err := db.GetDumpsByRepo(ctx, repositoryID)
+	if err != nil {
+		return nil, errors.Wrap(err, "db.GetDumpsByRepo")
+	}
+	if len(dumps) == 0 {
+		return nil, nil
+	}
+
+	var refs []gitserver.Ref
+	if policy.KeepTaggedCommits || policy.KeepLatestPerBranch > 0 {
+		if refs, err = gitserverClient.Refs(db, repositoryID); err != nil {
+			return nil, errors.Wrap(err, "gitserver.Refs")
+		}
+	}
+
+	branchCommits := map[string][]string{}
+	if policy.KeepLatestPerBranch > 0 {
+		cached := cache.get(repositoryID)
+		tips := map[string][]string{}
+
+		for _, ref := range refs {
+			if ref.IsTag || !policy.matchesBranch(ref.Name) {
+				continue
+			}
+
+			commits, ok := tips[ref.Commit]
+			if !ok {
+				if commits, ok = cached[ref.Commit]; !ok {
+					if commits, err = gitserverClient.Ancestors(db, repositoryID, ref.Commit, MaxBranchDepth); err != nil {
+						return nil, errors.Wrap(err, "gitserver.Ancestors")
+					}
+				}
+
+				tips[ref.Commit] = commits
+			}
+
+			branchCommits[ref.Name] = commits
+		}
+
+		cache.set(repositoryID, tips)
+	}
+
+	return evaluate(policy, dumps, refs, branchCommits, now), nil
+}
+
+// evaluate returns the decisions of the given policy for each of the given dumps. The branch
+// commits map the name of each branch to its commits, from the most to the least recent.
+func evaluate(policy Policy, dumps []db.Dump, refs []gitserver.Ref, branchCommits map[string][]string, now time.Time) []Decision {
+	reasons := make([][]string, len(dumps))
+
+	if policy.KeepVisibleFromTip {
+		for i, dump := range dumps {
+			if dump.VisibleAtTip {
+				reasons[i] = append(reasons[i], "visible from tip")
+			}
+		}
+	}
+
+	if policy.KeepTaggedCommits {
+		for _, ref := range refs {
+			if !ref.IsTag {
+				continue
+			}
+
+			for i, dump := range dumps {
+				if dump.Commit == ref.Commit {
+					reasons[i] = append(reasons[i], fmt.Sprintf("tagged: %s", ref.Name))
+				}
+			}
+		}
+	}
+
+	if policy.KeepLatestPerBranch > 0 {
+		dumpsByCommit := map[string][]int{}
+		for i, dump := range dumps {
+			dumpsByCommit[dump.Commit] = append(dumpsByCommit[dump.Commit], i)
+		}
+
+		for _, ref := range refs {
+			commits, ok := branchCommits[ref.Name]
+			if ref.IsTag || !ok {
+				continue
+			}
+
+			// Count the dumps of each root and indexer from the tip of the branch
+			type key struct{ root, indexer string }
+			counts := map[key]int{}
+
+			for _, commit := range commits {
+				for _, i := range dumpsByCommit[commit] {
+					k := key{dumps[i].Root, dumps[i].Indexer}
+					if counts[k] >= policy.KeepLatestPerBranch {
+						continue
+					}
+
+					counts[k]++
+					reasons[i] = append(reasons[i], fmt.Sprintf("latest on branch: %s", ref.Name))
+				}
+			}
+		}
+	}
+
+	decisions := make([]Decision, 0, len(dumps))
+	for i, dump := range dumps {
+		decisions = append(decisions, Decision{
+			Dump:            dump,
+			RetainedBecause: append([]string{}, reasons[i]...),
+			Expired:         len(reasons[i]) == 0 && policy.MaxAge > 0 && now.Sub(dump.UploadedAt) > policy.MaxAge,
+		})
+	}
+
+	return decisions
+}
+
+// matchesBranch determines if the keepLatestPerBranch policy applies to the given branch.
+func (p Policy) matchesBranch(name string) bool {
+	if len(p.Branches) == 0 {
+		return true
+	}
+
+	for _, branch := range p.Branches {
+		if branch.MatchString(name) {
+			return true
+		}
+	}
+
+	return false
+}
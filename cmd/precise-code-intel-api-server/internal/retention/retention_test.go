@@ -0,0 +1,199 @@
+package retention
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/db"
+	dbmocks "github.com/sourcegraph/sourcegraph/internal/codeintel/db/mocks"
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/gitserver"
+	gitservermocks "github.com/sourcegraph/sourcegraph/internal/codeintel/gitserver/mocks"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestPolicyFromConfig(t *testing.T) {
+	if _, ok, err := PolicyFromConfig(nil); err != nil {
+		t.Fatalf("unexpected error converting policy: %s", err)
+	} else if ok {
+		t.Errorf("unexpected policy for empty configuration")
+	}
+
+	policy, ok, err := PolicyFromConfig(&schema.CodeIntelRetention{
+		KeepLatestPerBranch: 2,
+		Branches:            []string{"^release/"},
+		MaxAgeDays:          30,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error converting policy: %s", err)
+	}
+	if !ok {
+		t.Fatalf("expected policy")
+	}
+
+	if !policy.KeepVisibleFromTip || !policy.KeepTaggedCommits {
+		t.Errorf("expected keep policies to default to true")
+	}
+	if policy.MaxAge != 30*24*time.Hour {
+		t.Errorf("unexpected max age. want=%s have=%s", 30*24*time.Hour, policy.MaxAge)
+	}
+	if !policy.matchesBranch("release/1.0") || policy.matchesBranch("feature/foo") {
+		t.Errorf("unexpected branch matches")
+	}
+
+	if _, _, err := PolicyFromConfig(&schema.CodeIntelRetention{Branches: []string{"("}}); err == nil {
+		t.Errorf("expected error for invalid branch pattern")
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	now := time.Unix(1587396557, 0).UTC()
+	old := now.Add(-time.Hour * 24 * 60)
+
+	dumps := []db.Dump{
+		{ID: 1, Commit: makeCommit(1), UploadedAt: old, VisibleAtTip: true},
+		{ID: 2, Commit: makeCommit(2), UploadedAt: old},
+		{ID: 3, Commit: makeCommit(3), UploadedAt: old},
+		{ID: 4, Commit: makeCommit(3), UploadedAt: old, Root: "sub/"},
+		{ID: 5, Commit: makeCommit(4), UploadedAt: old},
+		{ID: 6, Commit: makeCommit(5), UploadedAt: old},
+		{ID: 7, Commit: makeCommit(6), UploadedAt: now},
+	}
+
+	refs := []gitserver.Ref{
+		{Name: "master", Commit: makeCommit(1)},
+		{Name: "release/1.0", Commit: makeCommit(3)},
+		{Name: "feature", Commit: makeCommit(5)},
+		{Name: "v1.0.0", Commit: makeCommit(2), IsTag: true},
+	}
+
+	branchCommits := map[string][]string{
+		"release/1.0": {makeCommit(3), makeCommit(4), makeCommit(2)},
+	}
+
+	policy := Policy{
+		KeepVisibleFromTip:  true,
+		KeepTaggedCommits:   true,
+		KeepLatestPerBranch: 1,
+		Branches:            []*regexp.Regexp{regexp.MustCompile("^release/")},
+		MaxAge:              time.Hour * 24 * 30,
+	}
+
+	decisions := evaluate(policy, dumps, refs, branchCommits, now)
+
+	expected := []Decision{
+		{Dump: dumps[0], RetainedBecause: []string{"visible from tip"}},
+		{Dump: dumps[1], RetainedBecause: []string{"tagged: v1.0.0"}},
+		{Dump: dumps[2], RetainedBecause: []string{"latest on branch: release/1.0"}},
+		{Dump: dumps[3], RetainedBecause: []string{"latest on branch: release/1.0"}},
+		{Dump: dumps[4], RetainedBecause: []string{}, Expired: true},
+		{Dump: dumps[5], RetainedBecause: []string{}, Expired: true},
+		{Dump: dumps[6], RetainedBecause: []string{}},
+	}
+	if diff := cmp.Diff(expected, decisions); diff != "" {
+		t.Errorf("unexpected decisions (-want +got):\n%s", diff)
+	}
+}
+
+func TestEvaluateWithoutMaxAge(t *testing.T) {
+	now := time.Unix(1587396557, 0).UTC()
+	dumps := []db.Dump{{ID: 1, Commit: makeCommit(1), UploadedAt: now.Add(-time.Hour * 24 * 365)}}
+
+	decisions := evaluate(Policy{}, dumps, nil, nil, now)
+	if len(decisions) != 1 || decisions[0].Expired {
+		t.Errorf("unexpected expired dump without a max age")
+	}
+}
+
+func TestEvaluateRequestsBranchAncestors(t *testing.T) {
+	mockDB := dbmocks.NewMockDB()
+	gitserverClient := gitservermocks.NewMockClient()
+
+	now := time.Unix(1587396557, 0).UTC()
+	mockDB.GetDumpsByRepoFunc.SetDefaultReturn([]db.Dump{
+		{ID: 1, Commit: makeCommit(2), UploadedAt: now},
+		{ID: 2, Commit: makeCommit(3), UploadedAt: now},
+	}, nil)
+	gitserverClient.RefsFunc.SetDefaultReturn([]gitserver.Ref{
+		{Name: "master", Commit: makeCommit(1)},
+		{Name: "release/1.0", Commit: makeCommit(2)},
+	}, nil)
+	gitserverClient.AncestorsFunc.SetDefaultReturn([]string{makeCommit(2), makeCommit(3)}, nil)
+
+	policy := Policy{
+		KeepLatestPerBranch: 1,
+		Branches:            []*regexp.Regexp{regexp.MustCompile("^release/")},
+	}
+
+	decisions, err := Evaluate(context.Background(), mockDB, gitserverClient, nil, policy, 50, now)
+	if err != nil {
+		t.Fatalf("unexpected error evaluating policy: %s", err)
+	}
+
+	if len(gitserverClient.AncestorsFunc.History()) != 1 {
+		t.Errorf("unexpected number of AncestorsFunc calls. want=%d have=%d", 1, len(gitserverClient.AncestorsFunc.History()))
+	} else if commit := gitserverClient.AncestorsFunc.History()[0].Arg2; commit != makeCommit(2) {
+		t.Errorf("unexpected commit. want=%s have=%s", makeCommit(2), commit)
+	}
+
+	var retained []int
+	for _, decision := range decisions {
+		if len(decision.RetainedBecause) > 0 {
+			retained = append(retained, decision.Dump.ID)
+		}
+	}
+	if diff := cmp.Diff([]int{1}, retained); diff != "" {
+		t.Errorf("unexpected retained dumps (-want +got):\n%s", diff)
+	}
+}
+
+func TestEvaluateCachesBranchAncestors(t *testing.T) {
+	mockDB := dbmocks.NewMockDB()
+	gitserverClient := gitservermocks.NewMockClient()
+
+	now := time.Unix(1587396557, 0).UTC()
+	mockDB.GetDumpsByRepoFunc.SetDefaultReturn([]db.Dump{{ID: 1, Commit: makeCommit(2), UploadedAt: now}}, nil)
+	gitserverClient.RefsFunc.PushReturn([]gitserver.Ref{
+		{Name: "master", Commit: makeCommit(1)},
+		{Name: "release", Commit: makeCommit(1)},
+	}, nil)
+	gitserverClient.RefsFunc.PushReturn([]gitserver.Ref{
+		{Name: "master", Commit: makeCommit(1)},
+		{Name: "release", Commit: makeCommit(3)},
+	}, nil)
+	gitserverClient.AncestorsFunc.SetDefaultHook(func(db db.DB, repositoryID int, commit string, limit int) ([]string, error) {
+		return []string{commit, makeCommit(2)}, nil
+	})
+
+	cache := NewAncestorCache()
+	policy := Policy{KeepLatestPerBranch: 1}
+
+	for i := 0; i < 2; i++ {
+		if _, err := Evaluate(context.Background(), mockDB, gitserverClient, cache, policy, 50, now); err != nil {
+			t.Fatalf("unexpected error evaluating policy: %s", err)
+		}
+	}
+
+	// Branches sharing a tip share a request, and only the moved branch is requested again
+	var commits []string
+	for _, call := range gitserverClient.AncestorsFunc.History() {
+		commits = append(commits, call.Arg2)
+	}
+	if diff := cmp.Diff([]string{makeCommit(1), makeCommit(3)}, commits); diff != "" {
+		t.Errorf("unexpected Ancestors calls (-want +got):\n%s", diff)
+	}
+
+	if diff := cmp.Diff(map[string][]string{
+		makeCommit(1): {makeCommit(1), makeCommit(2)},
+		makeCommit(3): {makeCommit(3), makeCommit(2)},
+	}, cache.get(50)); diff != "" {
+		t.Errorf("unexpected cache entries (-want +got):\n%s", diff)
+	}
+}
+
+func makeCommit(i int) string {
+	return fmt.Sprintf("%040d", i)
+}
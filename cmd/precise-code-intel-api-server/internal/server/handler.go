@@ -1,34 +1,51 @@
 package server
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
 	"os"
+	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/inconshreveable/log15"
 	"github.com/sourcegraph/sourcegraph/cmd/precise-code-intel-api-server/internal/api"
+	"github.com/sourcegraph/sourcegraph/cmd/precise-code-intel-api-server/internal/retention"
+	bundles "github.com/sourcegraph/sourcegraph/internal/codeintel/bundles/client"
 	"github.com/sourcegraph/sourcegraph/internal/codeintel/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
 )
 
 const DefaultUploadPageSize = 50
+const DefaultIndexPageSize = 50
 const DefaultReferencesPageSize = 100
+const PruneCandidateLimit = 100
+const DefaultSymbolsLimit = 100
 
 func (s *Server) handler() http.Handler {
 	mux := mux.NewRouter()
 	mux.Path("/uploads/{id:[0-9]+}").Methods("GET").HandlerFunc(s.handleGetUploadByID)
 	mux.Path("/uploads/{id:[0-9]+}").Methods("DELETE").HandlerFunc(s.handleDeleteUploadByID)
+	mux.Path("/uploads/{id:[0-9]+}/diagnostics").Methods("GET").HandlerFunc(s.handleGetUploadDiagnostics)
 	mux.Path("/uploads/repository/{id:[0-9]+}").Methods("GET").HandlerFunc(s.handleGetUploadsByRepo)
+	mux.Path("/uploads/repository/{id:[0-9]+}/retention").Methods("GET").HandlerFunc(s.handleGetRetentionByRepo)
 	mux.Path("/upload").Methods("POST").HandlerFunc(s.handleEnqueue)
+	mux.Path("/indexes/{id:[0-9]+}").Methods("GET").HandlerFunc(s.handleGetIndexByID)
+	mux.Path("/indexes/repository/{id:[0-9]+}").Methods("GET").HandlerFunc(s.handleGetIndexesByRepo)
+	mux.Path("/indexes").Methods("POST").HandlerFunc(s.handleEnqueueIndex)
 	mux.Path("/exists").Methods("GET").HandlerFunc(s.handleExists)
 	mux.Path("/definitions").Methods("GET").HandlerFunc(s.handleDefinitions)
 	mux.Path("/references").Methods("GET").HandlerFunc(s.handleReferences)
 	mux.Path("/hover").Methods("GET").HandlerFunc(s.handleHover)
+	mux.Path("/documentSymbols").Methods("GET").HandlerFunc(s.handleDocumentSymbols)
+	mux.Path("/symbols").Methods("GET").HandlerFunc(s.handleSymbols)
+	mux.Path("/ranges").Methods("GET").HandlerFunc(s.handleRanges)
 	mux.Path("/uploads").Methods("POST").HandlerFunc(s.handleUploads)
 	mux.Path("/prune").Methods("POST").HandlerFunc(s.handlePrune)
+	mux.Path("/retention").Methods("POST").HandlerFunc(s.handleRetention)
 	mux.HandleFunc("/healthz", func(w http.ResponseWriter, _ *http.Request) {
 		w.WriteHeader(http.StatusOK)
 	})
@@ -51,6 +68,18 @@ func (s *Server) handleGetUploadByID(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, upload)
 }
 
+// GET /uploads/{id:[0-9]+}/diagnostics
+func (s *Server) handleGetUploadDiagnostics(w http.ResponseWriter, r *http.Request) {
+	diagnostics, err := s.db.GetDiagnostics(r.Context(), int(idFromRequest(r)))
+	if err != nil {
+		log15.Error("Failed to retrieve upload diagnostics", "error", err)
+		http.Error(w, fmt.Sprintf("failed to retrieve upload diagnostics: %s", err.Error()), http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, map[string]interface{}{"diagnostics": diagnostics})
+}
+
 // DELETE /uploads/{id:[0-9]+}
 func (s *Server) handleDeleteUploadByID(w http.ResponseWriter, r *http.Request) {
 	exists, err := s.db.DeleteUploadByID(r.Context(), int(idFromRequest(r)), func(repositoryID int) (string, error) {
@@ -152,6 +181,68 @@ func (s *Server) handleEnqueue(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, map[string]interface{}{"id": id})
 }
 
+// GET /indexes/{id:[0-9]+}
+func (s *Server) handleGetIndexByID(w http.ResponseWriter, r *http.Request) {
+	index, exists, err := s.db.GetIndexByID(r.Context(), int(idFromRequest(r)))
+	if err != nil {
+		log15.Error("Failed to retrieve index", "error", err)
+		http.Error(w, fmt.Sprintf("failed to retrieve index: %s", err.Error()), http.StatusInternalServerError)
+		return
+	}
+	if !exists {
+		http.Error(w, "index not found", http.StatusNotFound)
+		return
+	}
+
+	writeJSON(w, index)
+}
+
+// GET /indexes/repository/{id:[0-9]+}
+func (s *Server) handleGetIndexesByRepo(w http.ResponseWriter, r *http.Request) {
+	id := int(idFromRequest(r))
+	limit := getQueryIntDefault(r, "limit", DefaultIndexPageSize)
+	offset := getQueryInt(r, "offset")
+
+	indexes, totalCount, err := s.db.GetIndexesByRepo(r.Context(), id, getQuery(r, "state"), limit, offset)
+	if err != nil {
+		log15.Error("Failed to list indexes", "error", err)
+		http.Error(w, fmt.Sprintf("failed to list indexes: %s", err.Error()), http.StatusInternalServerError)
+		return
+	}
+
+	if offset+len(indexes) < totalCount {
+		w.Header().Set("Link", makeNextLink(r.URL, map[string]interface{}{
+			"limit":  limit,
+			"offset": offset + len(indexes),
+		}))
+	}
+
+	writeJSON(w, map[string]interface{}{"indexes": indexes, "totalCount": totalCount})
+}
+
+// POST /indexes
+func (s *Server) handleEnqueueIndex(w http.ResponseWriter, r *http.Request) {
+	id, enqueued, err := s.db.EnqueueIndex(
+		r.Context(),
+		getQuery(r, "commit"),
+		getQueryInt(r, "repositoryId"),
+		getQuery(r, "indexerName"),
+	)
+	if err != nil {
+		log15.Error("Failed to enqueue index", "error", err)
+		http.Error(w, fmt.Sprintf("failed to enqueue index: %s", err.Error()), http.StatusInternalServerError)
+		return
+	}
+	if !enqueued {
+		// The commit has already been indexed or uploaded with this indexer
+		writeJSON(w, map[string]interface{}{"id": nil})
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+	writeJSON(w, map[string]interface{}{"id": id})
+}
+
 // GET /exists
 func (s *Server) handleExists(w http.ResponseWriter, r *http.Request) {
 	dumps, err := s.api.FindClosestDumps(
@@ -283,6 +374,71 @@ func (s *Server) handleHover(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// GET /documentSymbols
+func (s *Server) handleDocumentSymbols(w http.ResponseWriter, r *http.Request) {
+	symbols, err := s.api.DocumentSymbols(r.Context(), getQuery(r, "path"), getQueryInt(r, "uploadId"))
+	if err != nil {
+		if err == api.ErrMissingDump {
+			http.Error(w, "no such dump", http.StatusNotFound)
+			return
+		}
+
+		log15.Error("Failed to handle document symbols request", "error", err)
+		http.Error(w, fmt.Sprintf("failed to handle document symbols request: %s", err.Error()), http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, symbols)
+}
+
+// GET /symbols
+func (s *Server) handleSymbols(w http.ResponseWriter, r *http.Request) {
+	query := bundles.SymbolQuery{
+		Query:           getQuery(r, "query"),
+		IncludePatterns: r.URL.Query()["include"],
+		ExcludePattern:  getQuery(r, "exclude"),
+	}
+
+	symbols, err := s.api.SearchSymbols(r.Context(), getQueryInt(r, "repositoryId"), getQuery(r, "commit"), query, getQueryIntDefault(r, "limit", DefaultSymbolsLimit))
+	if err != nil {
+		log15.Error("Failed to handle symbols request", "error", err)
+		http.Error(w, fmt.Sprintf("failed to handle symbols request: %s", err.Error()), http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, symbols)
+}
+
+// GET /ranges
+func (s *Server) handleRanges(w http.ResponseWriter, r *http.Request) {
+	positions, err := bundles.ParsePositions(getQuery(r, "positions"))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("illegal positions: %s", err.Error()), http.StatusBadRequest)
+		return
+	}
+
+	ranges, err := s.api.Ranges(r.Context(), getQuery(r, "path"), positions, getQueryInt(r, "uploadId"))
+	if err != nil {
+		if err == api.ErrMissingDump {
+			http.Error(w, "no such dump", http.StatusNotFound)
+			return
+		}
+
+		log15.Error("Failed to handle ranges request", "error", err)
+		http.Error(w, fmt.Sprintf("failed to handle ranges request: %s", err.Error()), http.StatusInternalServerError)
+		return
+	}
+
+	outers, err := serializeRanges(ranges)
+	if err != nil {
+		log15.Error("Failed to resolve locations", "error", err)
+		http.Error(w, fmt.Sprintf("failed to resolve locations: %s", err.Error()), http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, map[string]interface{}{"ranges": outers})
+}
+
 // POST /uploads
 func (s *Server) handleUploads(w http.ResponseWriter, r *http.Request) {
 	payload := struct {
@@ -309,9 +465,41 @@ func (s *Server) handleUploads(w http.ResponseWriter, r *http.Request) {
 	writeJSON(w, map[string]interface{}{"type": "map", "value": pairs})
 }
 
+// GET /uploads/repository/{id:[0-9]+}/retention
+func (s *Server) handleGetRetentionByRepo(w http.ResponseWriter, r *http.Request) {
+	policy, _, err := retention.PolicyFromConfig(conf.Get().CodeIntelRetention)
+	if err != nil {
+		log15.Error("Failed to read retention policy", "error", err)
+		http.Error(w, fmt.Sprintf("failed to read retention policy: %s", err.Error()), http.StatusInternalServerError)
+		return
+	}
+
+	decisions, err := retention.Evaluate(r.Context(), s.db, s.gitserverClient, s.ancestorCache, policy, int(idFromRequest(r)), time.Now())
+	if err != nil {
+		log15.Error("Failed to evaluate retention policy", "error", err)
+		http.Error(w, fmt.Sprintf("failed to evaluate retention policy: %s", err.Error()), http.StatusInternalServerError)
+		return
+	}
+
+	writeJSON(w, map[string]interface{}{"uploads": decisions})
+}
+
 // POST /prune
 func (s *Server) handlePrune(w http.ResponseWriter, r *http.Request) {
-	id, prunable, err := s.db.DeleteOldestDump(r.Context())
+	policy, ok, err := retention.PolicyFromConfig(conf.Get().CodeIntelRetention)
+	if err != nil {
+		log15.Error("Failed to read retention policy", "error", err)
+		http.Error(w, fmt.Sprintf("failed to read retention policy: %s", err.Error()), http.StatusInternalServerError)
+		return
+	}
+
+	var id int
+	var prunable bool
+	if ok {
+		id, prunable, err = s.pruneUnretainedDump(r.Context(), policy)
+	} else {
+		id, prunable, err = s.db.DeleteOldestDump(r.Context())
+	}
 	if err != nil {
 		log15.Error("Failed to prune upload", "error", err)
 		http.Error(w, fmt.Sprintf("failed to prune upload: %s", err.Error()), http.StatusInternalServerError)
@@ -324,3 +512,123 @@ func (s *Server) handlePrune(w http.ResponseWriter, r *http.Request) {
 		writeJSON(w, map[string]interface{}{"id": id})
 	}
 }
+
+// pruneUnretainedDump deletes the oldest dump that is not retained by the given policy. Dumps that
+// are retained by the policy are never pruned, even if that leaves the disk usage over its threshold.
+// Repositories for which the policy cannot be evaluated are skipped, as any of their dumps may be
+// retained.
+//
+// Candidates are read PruneCandidateLimit at a time, from the oldest dump onwards, until an
+// unretained dump is found, so that old retained dumps don't hide newer unretained ones. Each
+// repository is evaluated once.
+func (s *Server) pruneUnretainedDump(ctx context.Context, policy retention.Policy) (int, bool, error) {
+	retained := map[int]bool{}
+	evaluated := map[int]bool{}
+	skipped := map[int]bool{}
+
+	for offset := 0; ; offset += PruneCandidateLimit {
+		dumps, err := s.db.GetOldestDumps(ctx, PruneCandidateLimit, offset)
+		if err != nil {
+			return 0, false, err
+		}
+
+		for _, dump := range dumps {
+			if !evaluated[dump.RepositoryID] {
+				evaluated[dump.RepositoryID] = true
+
+				decisions, err := retention.Evaluate(ctx, s.db, s.gitserverClient, s.ancestorCache, policy, dump.RepositoryID, time.Now())
+				if err != nil {
+					log15.Error("Failed to evaluate retention policy", "repositoryID", dump.RepositoryID, "error", err)
+					skipped[dump.RepositoryID] = true
+					continue
+				}
+
+				for _, decision := range decisions {
+					retained[decision.Dump.ID] = len(decision.RetainedBecause) > 0
+				}
+			}
+
+			if skipped[dump.RepositoryID] || retained[dump.ID] {
+				continue
+			}
+
+			deleted, err := s.deleteUpload(ctx, dump.ID)
+			if err != nil || deleted {
+				return dump.ID, deleted, err
+			}
+		}
+
+		if len(dumps) < PruneCandidateLimit {
+			return 0, false, nil
+		}
+	}
+}
+
+// POST /retention
+func (s *Server) handleRetention(w http.ResponseWriter, r *http.Request) {
+	policy, ok, err := retention.PolicyFromConfig(conf.Get().CodeIntelRetention)
+	if err != nil {
+		log15.Error("Failed to read retention policy", "error", err)
+		http.Error(w, fmt.Sprintf("failed to read retention policy: %s", err.Error()), http.StatusInternalServerError)
+		return
+	}
+
+	ids := []int{}
+	if ok && policy.MaxAge > 0 {
+		if ids, err = s.deleteExpiredDumps(r.Context(), policy); err != nil {
+			log15.Error("Failed to apply retention policy", "error", err)
+			http.Error(w, fmt.Sprintf("failed to apply retention policy: %s", err.Error()), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	writeJSON(w, map[string]interface{}{"ids": ids})
+}
+
+// deleteExpiredDumps deletes the dumps of every repository that expired under the given policy and
+// returns their identifiers. In dry-run mode, the expired dumps are logged instead of being deleted.
+// Repositories for which the policy cannot be evaluated are logged and skipped.
+func (s *Server) deleteExpiredDumps(ctx context.Context, policy retention.Policy) ([]int, error) {
+	repositoryIDs, err := s.db.GetDumpRepositoryIDs(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := []int{}
+	for _, repositoryID := range repositoryIDs {
+		decisions, err := retention.Evaluate(ctx, s.db, s.gitserverClient, s.ancestorCache, policy, repositoryID, time.Now())
+		if err != nil {
+			log15.Error("Failed to evaluate retention policy", "repositoryID", repositoryID, "error", err)
+			continue
+		}
+
+		for _, decision := range decisions {
+			if !decision.Expired {
+				continue
+			}
+
+			if policy.DryRun {
+				log15.Info("Retention policy would delete upload", "id", decision.Dump.ID, "repositoryID", repositoryID, "uploadedAt", decision.Dump.UploadedAt)
+				continue
+			}
+
+			deleted, err := s.deleteUpload(ctx, decision.Dump.ID)
+			if err != nil {
+				return nil, err
+			}
+			if deleted {
+				log15.Debug("Retention policy deleted upload", "id", decision.Dump.ID, "repositoryID", repositoryID)
+				ids = append(ids, decision.Dump.ID)
+			}
+		}
+	}
+
+	return ids, nil
+}
+
+// deleteUpload deletes the given upload and updates the visibility of the remaining dumps of its repository.
+func (s *Server) deleteUpload(ctx context.Context, id int) (bool, error) {
+	return s.db.DeleteUploadByID(ctx, id, func(repositoryID int) (string, error) {
+		return s.gitserverClient.Head(s.db, repositoryID)
+	})
+}
@@ -25,3 +25,29 @@ func serializeLocations(resolvedLocations []api.ResolvedLocation) ([]APILocation
 
 	return apiLocations, nil
 }
+
+type APICodeIntelligenceRange struct {
+	Range          bundles.Range `json:"range"`
+	HoverText      string        `json:"hoverText"`
+	Definitions    []APILocation `json:"definitions"`
+	ReferenceCount int           `json:"referenceCount"`
+}
+
+func serializeRanges(ranges []api.ResolvedCodeIntelligenceRange) ([]APICodeIntelligenceRange, error) {
+	apiRanges := make([]APICodeIntelligenceRange, 0, len(ranges))
+	for _, r := range ranges {
+		definitions, err := serializeLocations(r.Definitions)
+		if err != nil {
+			return nil, err
+		}
+
+		apiRanges = append(apiRanges, APICodeIntelligenceRange{
+			Range:          r.Range,
+			HoverText:      r.HoverText,
+			Definitions:    definitions,
+			ReferenceCount: r.ReferenceCount,
+		})
+	}
+
+	return apiRanges, nil
+}
@@ -0,0 +1,119 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sourcegraph/sourcegraph/cmd/precise-code-intel-api-server/internal/retention"
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/db"
+	dbmocks "github.com/sourcegraph/sourcegraph/internal/codeintel/db/mocks"
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/gitserver"
+	gitservermocks "github.com/sourcegraph/sourcegraph/internal/codeintel/gitserver/mocks"
+)
+
+func TestDeleteExpiredDumpsSkipsFailedRepositories(t *testing.T) {
+	old := time.Now().Add(-time.Hour * 24 * 60)
+
+	mockDB := dbmocks.NewMockDB()
+	mockDB.GetDumpRepositoryIDsFunc.SetDefaultReturn([]int{50, 51}, nil)
+	mockDB.GetDumpsByRepoFunc.SetDefaultHook(func(ctx context.Context, repositoryID int) ([]db.Dump, error) {
+		return []db.Dump{{ID: repositoryID * 10, RepositoryID: repositoryID, UploadedAt: old}}, nil
+	})
+	mockDB.DeleteUploadByIDFunc.SetDefaultReturn(true, nil)
+
+	gitserverClient := gitservermocks.NewMockClient()
+	gitserverClient.RefsFunc.SetDefaultHook(func(db db.DB, repositoryID int) ([]gitserver.Ref, error) {
+		if repositoryID == 50 {
+			return nil, errors.New("repository not cloned")
+		}
+		return nil, nil
+	})
+
+	s := &Server{db: mockDB, gitserverClient: gitserverClient, ancestorCache: retention.NewAncestorCache()}
+	policy := retention.Policy{KeepTaggedCommits: true, MaxAge: time.Hour * 24 * 30}
+
+	ids, err := s.deleteExpiredDumps(context.Background(), policy)
+	if err != nil {
+		t.Fatalf("unexpected error deleting expired dumps: %s", err)
+	}
+	if diff := cmp.Diff([]int{510}, ids); diff != "" {
+		t.Errorf("unexpected deleted dumps (-want +got):\n%s", diff)
+	}
+}
+
+func TestPruneUnretainedDumpSkipsFailedRepositories(t *testing.T) {
+	mockDB := dbmocks.NewMockDB()
+	mockDB.GetOldestDumpsFunc.SetDefaultReturn([]db.Dump{
+		{ID: 500, RepositoryID: 50},
+		{ID: 510, RepositoryID: 51},
+	}, nil)
+	mockDB.GetDumpsByRepoFunc.SetDefaultHook(func(ctx context.Context, repositoryID int) ([]db.Dump, error) {
+		return []db.Dump{{ID: repositoryID * 10, RepositoryID: repositoryID}}, nil
+	})
+	mockDB.DeleteUploadByIDFunc.SetDefaultReturn(true, nil)
+
+	gitserverClient := gitservermocks.NewMockClient()
+	gitserverClient.RefsFunc.SetDefaultHook(func(db db.DB, repositoryID int) ([]gitserver.Ref, error) {
+		if repositoryID == 50 {
+			return nil, errors.New("repository not cloned")
+		}
+		return nil, nil
+	})
+
+	s := &Server{db: mockDB, gitserverClient: gitserverClient, ancestorCache: retention.NewAncestorCache()}
+
+	id, prunable, err := s.pruneUnretainedDump(context.Background(), retention.Policy{KeepTaggedCommits: true})
+	if err != nil {
+		t.Fatalf("unexpected error pruning dump: %s", err)
+	}
+	if !prunable || id != 510 {
+		t.Errorf("unexpected pruned dump. want=%d have=%d (prunable=%v)", 510, id, prunable)
+	}
+}
+
+func TestPruneUnretainedDumpPagesPastRetainedDumps(t *testing.T) {
+	// The oldest dumps are tagged releases, which fill more than a page of candidates
+	var dumps []db.Dump
+	var refs []gitserver.Ref
+	for i := 0; i < PruneCandidateLimit+50; i++ {
+		commit := fmt.Sprintf("%040d", i)
+		dumps = append(dumps, db.Dump{ID: i + 1, RepositoryID: 50, Commit: commit})
+		refs = append(refs, gitserver.Ref{Name: fmt.Sprintf("v%d", i), Commit: commit, IsTag: true})
+	}
+	dumps = append(dumps, db.Dump{ID: 1000, RepositoryID: 50, Commit: fmt.Sprintf("%040d", 1000)})
+
+	mockDB := dbmocks.NewMockDB()
+	mockDB.GetOldestDumpsFunc.SetDefaultHook(func(ctx context.Context, limit, offset int) ([]db.Dump, error) {
+		if offset >= len(dumps) {
+			return nil, nil
+		}
+		if offset+limit > len(dumps) {
+			return dumps[offset:], nil
+		}
+		return dumps[offset : offset+limit], nil
+	})
+	mockDB.GetDumpsByRepoFunc.SetDefaultReturn(dumps, nil)
+	mockDB.DeleteUploadByIDFunc.SetDefaultReturn(true, nil)
+
+	gitserverClient := gitservermocks.NewMockClient()
+	gitserverClient.RefsFunc.SetDefaultReturn(refs, nil)
+
+	s := &Server{db: mockDB, gitserverClient: gitserverClient, ancestorCache: retention.NewAncestorCache()}
+
+	id, prunable, err := s.pruneUnretainedDump(context.Background(), retention.Policy{KeepTaggedCommits: true})
+	if err != nil {
+		t.Fatalf("unexpected error pruning dump: %s", err)
+	}
+	if !prunable || id != 1000 {
+		t.Errorf("unexpected pruned dump. want=%d have=%d (prunable=%v)", 1000, id, prunable)
+	}
+
+	// The repository is evaluated once across pages
+	if calls := len(gitserverClient.RefsFunc.History()); calls != 1 {
+		t.Errorf("unexpected number of Refs calls. want=%d have=%d", 1, calls)
+	}
+}
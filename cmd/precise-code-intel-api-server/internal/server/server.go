@@ -8,8 +8,10 @@ import (
 
 	"github.com/inconshreveable/log15"
 	"github.com/sourcegraph/sourcegraph/cmd/precise-code-intel-api-server/internal/api"
+	"github.com/sourcegraph/sourcegraph/cmd/precise-code-intel-api-server/internal/retention"
 	bundles "github.com/sourcegraph/sourcegraph/internal/codeintel/bundles/client"
 	"github.com/sourcegraph/sourcegraph/internal/codeintel/db"
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/gitserver"
 	"github.com/sourcegraph/sourcegraph/internal/trace/ot"
 )
 
@@ -18,7 +20,9 @@ type Server struct {
 	port                int
 	db                  db.DB
 	bundleManagerClient bundles.BundleManagerClient
+	gitserverClient     gitserver.Client
 	api                 api.CodeIntelAPI
+	ancestorCache       *retention.AncestorCache
 }
 
 type ServerOpts struct {
@@ -26,6 +30,7 @@ type ServerOpts struct {
 	Port                int
 	DB                  db.DB
 	BundleManagerClient bundles.BundleManagerClient
+	GitserverClient     gitserver.Client
 }
 
 func New(opts ServerOpts) *Server {
@@ -34,7 +39,9 @@ func New(opts ServerOpts) *Server {
 		port:                opts.Port,
 		db:                  opts.DB,
 		bundleManagerClient: opts.BundleManagerClient,
+		gitserverClient:     opts.GitserverClient,
 		api:                 api.New(opts.DB, opts.BundleManagerClient),
+		ancestorCache:       retention.NewAncestorCache(),
 	}
 }
 
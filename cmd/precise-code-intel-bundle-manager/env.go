@@ -9,7 +9,13 @@ import (
 )
 
 var (
-	rawBundleDir                = env.Get("PRECISE_CODE_INTEL_BUNDLE_DIR", "/lsif-storage", "Root dir containing uploads and converted bundles.")
+	rawBundleDir                = env.Get("PRECISE_CODE_INTEL_BUNDLE_DIR", "/lsif-storage", "Root dir containing uploads and converted bundles, or bundles cached from object storage.")
+	rawStorageBackend           = env.Get("PRECISE_CODE_INTEL_STORAGE_BACKEND", "local", "Where uploads and converted bundles are stored: local (on disk in the bundle dir) or s3 (in an S3-compatible bucket).")
+	rawS3Bucket                 = env.Get("PRECISE_CODE_INTEL_S3_BUCKET", "", "The bucket holding uploads and converted bundles when the storage backend is s3.")
+	rawS3Region                 = env.Get("PRECISE_CODE_INTEL_S3_REGION", "", "The region of the S3 bucket. Defaults to the region of the AWS environment.")
+	rawS3Endpoint               = env.Get("PRECISE_CODE_INTEL_S3_ENDPOINT", "", "The URL of an S3-compatible service (such as MinIO) to use instead of AWS.")
+	rawS3AccessKeyID            = env.Get("PRECISE_CODE_INTEL_S3_ACCESS_KEY_ID", "", "The access key of the S3 bucket. Defaults to the credentials of the AWS environment.")
+	rawS3SecretAccessKey        = env.Get("PRECISE_CODE_INTEL_S3_SECRET_ACCESS_KEY", "", "The secret key of the S3 bucket.")
 	rawDatabaseCacheSize        = env.Get("PRECISE_CODE_INTEL_CONNECTION_CACHE_CAPACITY", "100", "Number of SQLite connections that can be opened at once.")
 	rawDocumentDataCacheSize    = env.Get("PRECISE_CODE_INTEL_DOCUMENT_CACHE_CAPACITY", "100", "Maximum number of decoded documents that can be held in memory at once.")
 	rawResultChunkDataCacheSize = env.Get("PRECISE_CODE_INTEL_RESULT_CHUNK_CACHE_CAPACITY", "100", "Maximum number of decoded result chunks that can be held in memory at once.")
@@ -14,10 +14,10 @@ type DatabaseCache struct {
 }
 
 type databaseCacheEntry struct {
-	filename string
-	db       Database
-	wg       sync.WaitGroup // user ref count
-	once     sync.Once      // guards db.Close()
+	key  string
+	db   Database
+	wg   sync.WaitGroup // user ref count
+	once sync.Once      // guards db.Close()
 }
 
 // close closes the cached database value after its refcount has dropped to zero.
@@ -28,7 +28,7 @@ func (entry *databaseCacheEntry) close() {
 			entry.wg.Wait()
 
 			if err := entry.db.Close(); err != nil {
-				log15.Error("Failed to close database", "filename", entry.filename, "err", err)
+				log15.Error("Failed to close database", "key", entry.key, "err", err)
 			}
 		}()
 	})
@@ -52,12 +52,12 @@ func NewDatabaseCache(size int64) (*DatabaseCache, error) {
 }
 
 // WithDatabase invokes the given handler function with a Database instance either
-// cached with the given filename, or created with the given openDatabase function.
+// cached with the given key, or created with the given openDatabase function.
 // This method is goroutine-safe and the database instance is guaranteed to remain
 // open until the handler has returned, regardless of the cache entry's eviction
 // status.
-func (c *DatabaseCache) WithDatabase(filename string, openDatabase func() (Database, error), handler func(db Database) error) error {
-	if value, ok := c.cache.Get(filename); ok {
+func (c *DatabaseCache) WithDatabase(key string, openDatabase func() (Database, error), handler func(db Database) error) error {
+	if value, ok := c.cache.Get(key); ok {
 		entry := value.(*databaseCacheEntry)
 		entry.wg.Add(1)
 		defer entry.wg.Done()
@@ -69,11 +69,11 @@ func (c *DatabaseCache) WithDatabase(filename string, openDatabase func() (Datab
 		return err
 	}
 
-	entry := &databaseCacheEntry{filename: filename, db: db}
+	entry := &databaseCacheEntry{key: key, db: db}
 	entry.wg.Add(1)
 	defer entry.wg.Done()
 
-	if !c.cache.Set(filename, entry, 1) {
+	if !c.cache.Set(key, entry, 1) {
 		defer entry.close()
 	}
 
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"regexp"
 	"sort"
 
 	"github.com/sourcegraph/sourcegraph/internal/codeintel/bundles/reader"
@@ -40,6 +41,21 @@ type Database interface {
 
 	// PackageInformation looks up package information data by identifier.
 	PackageInformation(ctx context.Context, path string, packageInformationID types.ID) (types.PackageInformationData, bool, error)
+
+	// DocumentSymbols returns the symbols defined in the given path, nested by containment.
+	DocumentSymbols(ctx context.Context, path string) ([]Symbol, error)
+
+	// SearchSymbols returns up to limit symbols of the dump which match the given query, ordered by
+	// path and position.
+	SearchSymbols(ctx context.Context, query SymbolQuery, limit int) ([]SymbolLocation, error)
+
+	// Ranges returns the hover text, definitions, and number of references of the ranges containing
+	// the given positions of the given path. If no positions are given, every range of the document is
+	// returned. The document is read once regardless of the number of positions.
+	Ranges(ctx context.Context, path string, positions []Position) ([]CodeIntelligenceRange, error)
+
+	// ReferenceIdentifiers returns the distinct moniker schemes and identifiers referenced by this dump.
+	ReferenceIdentifiers(ctx context.Context) ([]types.ReferenceIdentifier, error)
 }
 
 type databaseImpl struct {
@@ -67,6 +83,50 @@ type Position struct {
 	Character int `json:"character"`
 }
 
+// Symbol is a symbol defined in a document along with the symbols it contains.
+type Symbol struct {
+	Name     string   `json:"name"`
+	Detail   string   `json:"detail,omitempty"`
+	Kind     int      `json:"kind"`
+	Range    Range    `json:"range"`
+	Children []Symbol `json:"children,omitempty"`
+}
+
+// SymbolQuery selects the symbols returned by SearchSymbols.
+type SymbolQuery struct {
+	// Name matches the names of the symbols.
+	Name *regexp.Regexp
+
+	// Root is prefixed to the path of a symbol before it is matched against IncludePatterns and
+	// ExcludePattern, so that the patterns can refer to paths relative to the repository.
+	Root string
+
+	// IncludePatterns must all match the path of a symbol.
+	IncludePatterns []*regexp.Regexp
+
+	// ExcludePattern, if non-nil, must not match the path of a symbol.
+	ExcludePattern *regexp.Regexp
+}
+
+// SymbolLocation is a symbol along with the document that defines it.
+type SymbolLocation struct {
+	Name          string `json:"name"`
+	Kind          int    `json:"kind"`
+	ContainerName string `json:"containerName,omitempty"`
+	Path          string `json:"path"`
+	Range         Range  `json:"range"`
+}
+
+// CodeIntelligenceRange is the hover text, definitions, and number of references of a range. The
+// monikers attached to the range are included so that definitions in other dumps can be resolved.
+type CodeIntelligenceRange struct {
+	Range          Range               `json:"range"`
+	HoverText      string              `json:"hoverText"`
+	Definitions    []Location          `json:"definitions"`
+	ReferenceCount int                 `json:"referenceCount"`
+	Monikers       []types.MonikerData `json:"monikers"`
+}
+
 func newRange(startLine, startCharacter, endLine, endCharacter int) Range {
 	return Range{
 		Start: Position{
@@ -145,7 +205,7 @@ func (db *databaseImpl) Definitions(ctx context.Context, path string, line, char
 			continue
 		}
 
-		definitionResults, err := db.getResultByID(ctx, r.DefinitionResultID)
+		definitionResults, err := db.getResultByID(ctx, r.DefinitionResultID, nil)
 		if err != nil {
 			return nil, err
 		}
@@ -169,7 +229,7 @@ func (db *databaseImpl) References(ctx context.Context, path string, line, chara
 			continue
 		}
 
-		referenceResults, err := db.getResultByID(ctx, r.ReferenceResultID)
+		referenceResults, err := db.getResultByID(ctx, r.ReferenceResultID, nil)
 		if err != nil {
 			return nil, err
 		}
@@ -289,6 +349,118 @@ func (db *databaseImpl) PackageInformation(ctx context.Context, path string, pac
 	return packageInformationData, exists, nil
 }
 
+// DocumentSymbols returns the symbols defined in the given path, nested by containment.
+func (db *databaseImpl) DocumentSymbols(ctx context.Context, path string) ([]Symbol, error) {
+	documentData, exists, err := db.getDocumentData(ctx, path)
+	if err != nil || !exists {
+		return nil, err
+	}
+
+	if len(documentData.Symbols) == 0 {
+		return []Symbol{}, nil
+	}
+
+	return convertSymbols(documentData.Symbols), nil
+}
+
+// SearchSymbols returns up to limit symbols of the dump which match the given query, ordered by
+// path and position.
+func (db *databaseImpl) SearchSymbols(ctx context.Context, query SymbolQuery, limit int) ([]SymbolLocation, error) {
+	rows, err := db.reader.ReadSymbols(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	symbols := []SymbolLocation{}
+	for _, row := range rows {
+		if len(symbols) >= limit {
+			break
+		}
+		if !query.matches(row) {
+			continue
+		}
+
+		symbols = append(symbols, SymbolLocation{
+			Name:          row.Name,
+			Kind:          row.Kind,
+			ContainerName: row.ContainerName,
+			Path:          row.URI,
+			Range:         newRange(row.StartLine, row.StartCharacter, row.EndLine, row.EndCharacter),
+		})
+	}
+
+	return symbols, nil
+}
+
+func (q SymbolQuery) matches(row types.SymbolRow) bool {
+	if q.Name != nil && !q.Name.MatchString(row.Name) {
+		return false
+	}
+
+	path := q.Root + row.URI
+	for _, pattern := range q.IncludePatterns {
+		if !pattern.MatchString(path) {
+			return false
+		}
+	}
+
+	return q.ExcludePattern == nil || !q.ExcludePattern.MatchString(path)
+}
+
+// Ranges returns the hover text, definitions, and number of references of the ranges containing
+// the given positions of the given path. If no positions are given, every range of the document is
+// returned. The document is read once regardless of the number of positions, and each result chunk
+// is read at most once.
+//
+// The ranges containing a position are combined as Hover, Definitions, and References combine them
+// for a single position, and are reported by the innermost range. Positions outside of any range are
+// skipped, and positions within the same innermost range are reported once. Without positions, the
+// start of each range of the document is used as a position.
+func (db *databaseImpl) Ranges(ctx context.Context, path string, positions []Position) ([]CodeIntelligenceRange, error) {
+	documentData, exists, err := db.getDocumentData(ctx, path)
+	if err != nil || !exists {
+		return nil, err
+	}
+
+	if len(positions) == 0 {
+		for _, r := range sortRanges(documentData.Ranges) {
+			positions = append(positions, Position{Line: r.StartLine, Character: r.StartCharacter})
+		}
+	}
+
+	memo := resultChunkMemo{}
+	seen := map[Range]struct{}{}
+	codeIntelligenceRanges := make([]CodeIntelligenceRange, 0, len(positions))
+
+	for _, position := range positions {
+		ranges := findRanges(documentData.Ranges, position.Line, position.Character)
+		if len(ranges) == 0 {
+			continue
+		}
+
+		innermost := ranges[0]
+		key := newRange(innermost.StartLine, innermost.StartCharacter, innermost.EndLine, innermost.EndCharacter)
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+
+		codeIntelligenceRange, err := db.codeIntelligenceRange(ctx, documentData, ranges, memo)
+		if err != nil {
+			return nil, err
+		}
+
+		codeIntelligenceRanges = append(codeIntelligenceRanges, codeIntelligenceRange)
+	}
+
+	return codeIntelligenceRanges, nil
+}
+
+// ReferenceIdentifiers returns the distinct moniker schemes and identifiers referenced by this dump.
+func (db *databaseImpl) ReferenceIdentifiers(ctx context.Context) ([]types.ReferenceIdentifier, error) {
+	return db.reader.ReadReferenceIdentifiers(ctx)
+}
+
 // getDocumentData fetches and unmarshals the document data or the given path. This method caches
 // document data by a unique key prefixed by the database filename.
 func (db *databaseImpl) getDocumentData(ctx context.Context, path string) (types.DocumentData, bool, error) {
@@ -329,15 +501,100 @@ func (db *databaseImpl) getRangeByPosition(ctx context.Context, path string, lin
 	return documentData, findRanges(documentData.Ranges, line, character), true, nil
 }
 
+// codeIntelligenceRange combines the given ranges of a document, ordered from the innermost range
+// outwards as returned by findRanges, into the code intelligence of the innermost range. Result
+// chunks are read through the given memo.
+func (db *databaseImpl) codeIntelligenceRange(ctx context.Context, documentData types.DocumentData, ranges []types.RangeData, memo resultChunkMemo) (CodeIntelligenceRange, error) {
+	innermost := ranges[0]
+	codeIntelligenceRange := CodeIntelligenceRange{
+		Range: newRange(innermost.StartLine, innermost.StartCharacter, innermost.EndLine, innermost.EndCharacter),
+	}
+
+	for _, r := range ranges {
+		if r.HoverResultID != "" && codeIntelligenceRange.HoverText == "" {
+			text, exists := documentData.HoverResults[r.HoverResultID]
+			if !exists {
+				return CodeIntelligenceRange{}, ErrMalformedBundle{
+					Filename: db.filename,
+					Name:     "hoverResult",
+					Key:      string(r.HoverResultID),
+				}
+			}
+
+			codeIntelligenceRange.HoverText = text
+		}
+
+		if r.DefinitionResultID != "" && codeIntelligenceRange.Definitions == nil {
+			definitionResults, err := db.getResultByID(ctx, r.DefinitionResultID, memo)
+			if err != nil {
+				return CodeIntelligenceRange{}, err
+			}
+
+			locations, err := db.convertRangesToLocations(ctx, definitionResults)
+			if err != nil {
+				return CodeIntelligenceRange{}, err
+			}
+
+			codeIntelligenceRange.Definitions = locations
+		}
+
+		if r.ReferenceResultID != "" {
+			referenceResults, err := db.getResultByID(ctx, r.ReferenceResultID, memo)
+			if err != nil {
+				return CodeIntelligenceRange{}, err
+			}
+
+			codeIntelligenceRange.ReferenceCount += len(referenceResults)
+		}
+
+		for _, monikerID := range r.MonikerIDs {
+			moniker, exists := documentData.Monikers[monikerID]
+			if !exists {
+				return CodeIntelligenceRange{}, ErrMalformedBundle{
+					Filename: db.filename,
+					Name:     "moniker",
+					Key:      string(monikerID),
+				}
+			}
+
+			codeIntelligenceRange.Monikers = append(codeIntelligenceRange.Monikers, moniker)
+		}
+	}
+
+	return codeIntelligenceRange, nil
+}
+
+// resultChunkMemo holds the result chunks read during a single call by their index, so that
+// resolving the results of many ranges reads each result chunk once, even when the shared cache
+// evicts it in the meantime.
+type resultChunkMemo map[int]memoizedResultChunk
+
+type memoizedResultChunk struct {
+	data   types.ResultChunkData
+	exists bool
+}
+
 // getResultByID fetches and unmarshals a definition or reference result by identifier.
 // This method caches result chunk data by a unique key prefixed by the database filename.
-func (db *databaseImpl) getResultByID(ctx context.Context, id types.ID) ([]documentPathRangeID, error) {
-	resultChunkData, exists, err := db.getResultChunkByResultID(ctx, id)
-	if err != nil {
-		return nil, err
+// Result chunks are also read from and stored in the given memo, which may be nil.
+func (db *databaseImpl) getResultByID(ctx context.Context, id types.ID, memo resultChunkMemo) ([]documentPathRangeID, error) {
+	index := types.HashKey(id, db.numResultChunks)
+
+	chunk, ok := memo[index]
+	if !ok {
+		data, exists, err := db.getResultChunkByResultID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+
+		chunk = memoizedResultChunk{data: data, exists: exists}
+		if memo != nil {
+			memo[index] = chunk
+		}
 	}
 
-	if !exists {
+	resultChunkData := chunk.data
+	if !chunk.exists {
 		return nil, ErrMalformedBundle{
 			Filename: db.filename,
 			Name:     "result chunk",
@@ -456,3 +713,19 @@ func (db *databaseImpl) convertRangesToLocations(ctx context.Context, resultData
 
 	return locations, nil
 }
+
+// convertSymbols converts stored symbol data into symbols.
+func convertSymbols(symbols []types.SymbolData) []Symbol {
+	var converted []Symbol
+	for _, symbol := range symbols {
+		converted = append(converted, Symbol{
+			Name:     symbol.Name,
+			Detail:   symbol.Detail,
+			Kind:     symbol.Kind,
+			Range:    newRange(symbol.StartLine, symbol.StartCharacter, symbol.EndLine, symbol.EndCharacter),
+			Children: convertSymbols(symbol.Children),
+		})
+	}
+
+	return converted
+}
@@ -2,9 +2,11 @@ package database
 
 import (
 	"context"
+	"regexp"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/bundles/mocks"
 	"github.com/sourcegraph/sourcegraph/internal/codeintel/bundles/types"
 	"github.com/sourcegraph/sourcegraph/internal/sqliteutil"
 )
@@ -114,6 +116,142 @@ func TestDatabaseHover(t *testing.T) {
 	}
 }
 
+func TestDatabaseRanges(t *testing.T) {
+	// `\tcontents, err := findContents(pkgs, p, f, obj)`
+	//                     ^^^^^^^^^^^^
+
+	db := openTestDatabase(t)
+	positions := []Position{{Line: 628, Character: 20}, {Line: 628, Character: 25}, {Line: 0, Character: 0}}
+	actual, err := db.Ranges(context.Background(), "internal/index/indexer.go", positions)
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	if len(actual) != 1 {
+		t.Fatalf("unexpected number of ranges. want=%d have=%d", 1, len(actual))
+	}
+
+	// The batch must agree with the single-position queries
+	text, hoverRange, _, err := db.Hover(context.Background(), "internal/index/indexer.go", 628, 20)
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	definitions, err := db.Definitions(context.Background(), "internal/index/indexer.go", 628, 20)
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+	references, err := db.References(context.Background(), "internal/index/indexer.go", 628, 20)
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+
+	if actual[0].HoverText != text {
+		t.Errorf("unexpected hover text. want=%s have=%s", text, actual[0].HoverText)
+	}
+	if diff := cmp.Diff(hoverRange, actual[0].Range); diff != "" {
+		t.Errorf("unexpected range (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(definitions, actual[0].Definitions); diff != "" {
+		t.Errorf("unexpected definitions (-want +got):\n%s", diff)
+	}
+	if actual[0].ReferenceCount != len(references) {
+		t.Errorf("unexpected reference count. want=%d have=%d", len(references), actual[0].ReferenceCount)
+	}
+}
+
+func TestDatabaseRangesAll(t *testing.T) {
+	db := openTestDatabase(t)
+	actual, err := db.Ranges(context.Background(), "internal/index/indexer.go", nil)
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+
+	var found bool
+	for i, r := range actual {
+		if i > 0 && comparePositions(actual[i-1].Range.Start, r.Range.Start) > 0 {
+			t.Errorf("ranges are not ordered by start position: %v before %v", actual[i-1].Range, r.Range)
+		}
+		if r.Range == newRange(628, 18, 628, 30) {
+			found = r.HoverText != "" && len(r.Definitions) > 0
+		}
+	}
+	if !found {
+		t.Errorf("expected range of findContents with hover text and definitions")
+	}
+
+	if actual, err := db.Ranges(context.Background(), "missing.go", nil); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	} else if actual != nil {
+		t.Errorf("unexpected ranges for missing document: %v", actual)
+	}
+}
+
+func TestDatabaseRangesNested(t *testing.T) {
+	mockReader := mocks.NewMockReader()
+	mockReader.ReadDocumentFunc.SetDefaultReturn(types.DocumentData{
+		Ranges: map[types.ID]types.RangeData{
+			"outer": {StartLine: 1, StartCharacter: 0, EndLine: 3, EndCharacter: 1, HoverResultID: "h", ReferenceResultID: "r1"},
+			"inner": {StartLine: 2, StartCharacter: 4, EndLine: 2, EndCharacter: 8, ReferenceResultID: "r2"},
+		},
+		HoverResults: map[types.ID]string{"h": "func outer()"},
+	}, true, nil)
+	mockReader.ReadResultChunkFunc.SetDefaultReturn(types.ResultChunkData{
+		DocumentPaths: map[types.ID]string{"d": "main.go"},
+		DocumentIDRangeIDs: map[types.ID][]types.DocumentIDRangeID{
+			"r1": {{DocumentID: "d", RangeID: "outer"}},
+			"r2": {{DocumentID: "d", RangeID: "inner"}, {DocumentID: "d", RangeID: "outer"}},
+		},
+	}, true, nil)
+
+	documentDataCache, err := NewDocumentDataCache(1)
+	if err != nil {
+		t.Fatalf("unexpected error creating cache: %s", err)
+	}
+	resultChunkDataCache, err := NewResultChunkDataCache(1)
+	if err != nil {
+		t.Fatalf("unexpected error creating cache: %s", err)
+	}
+	db := &databaseImpl{
+		filename:             "test.db",
+		documentDataCache:    documentDataCache,
+		resultChunkDataCache: resultChunkDataCache,
+		reader:               mockReader,
+		numResultChunks:      1,
+	}
+
+	all, err := db.Ranges(context.Background(), "main.go", nil)
+	if err != nil {
+		t.Fatalf("unexpected error %s", err)
+	}
+
+	// The inner range is combined with the enclosing range, as for a position within it
+	expected := []CodeIntelligenceRange{
+		{Range: newRange(1, 0, 3, 1), HoverText: "func outer()", ReferenceCount: 1},
+		{Range: newRange(2, 4, 2, 8), HoverText: "func outer()", ReferenceCount: 3},
+	}
+	if diff := cmp.Diff(expected, all); diff != "" {
+		t.Errorf("unexpected ranges (-want +got):\n%s", diff)
+	}
+
+	// Both results are in the same result chunk, which is read once
+	if calls := len(mockReader.ReadResultChunkFunc.History()); calls != 1 {
+		t.Errorf("unexpected number of ReadResultChunk calls. want=%d have=%d", 1, calls)
+	}
+
+	positions := []Position{{Line: 2, Character: 5}}
+	if actual, err := db.Ranges(context.Background(), "main.go", positions); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	} else if diff := cmp.Diff(expected[1:], actual); diff != "" {
+		t.Errorf("unexpected ranges for position (-want +got):\n%s", diff)
+	}
+}
+
+func comparePositions(a, b Position) int {
+	if a.Line != b.Line {
+		return a.Line - b.Line
+	}
+	return a.Character - b.Character
+}
+
 func TestDatabaseMonikersByPosition(t *testing.T) {
 	// `func NewMetaData(id, root string, info ToolInfo) *MetaData {`
 	//       ^^^^^^^^^^^
@@ -256,3 +394,107 @@ func openTestDatabase(t *testing.T) Database {
 	t.Cleanup(func() { _ = db.Close })
 	return db
 }
+
+func TestDatabaseDocumentSymbols(t *testing.T) {
+	mockReader := mocks.NewMockReader()
+	mockReader.ReadDocumentFunc.SetDefaultHook(func(ctx context.Context, path string) (types.DocumentData, bool, error) {
+		if path != "main.go" {
+			return types.DocumentData{}, false, nil
+		}
+
+		return types.DocumentData{
+			Symbols: []types.SymbolData{
+				{
+					Name: "Server", Kind: 23, StartLine: 3, StartCharacter: 5, EndLine: 3, EndCharacter: 11,
+					Children: []types.SymbolData{
+						{Name: "addr", Detail: "string", Kind: 8, StartLine: 4, StartCharacter: 1, EndLine: 4, EndCharacter: 5},
+					},
+				},
+				{Name: "main", Kind: 12, StartLine: 7, StartCharacter: 5, EndLine: 7, EndCharacter: 9},
+			},
+		}, true, nil
+	})
+
+	documentDataCache, err := NewDocumentDataCache(1)
+	if err != nil {
+		t.Fatalf("unexpected error creating cache: %s", err)
+	}
+	db := &databaseImpl{filename: "test.db", documentDataCache: documentDataCache, reader: mockReader}
+
+	if actual, err := db.DocumentSymbols(context.Background(), "main.go"); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	} else {
+		expected := []Symbol{
+			{
+				Name: "Server", Kind: 23, Range: newRange(3, 5, 3, 11),
+				Children: []Symbol{
+					{Name: "addr", Detail: "string", Kind: 8, Range: newRange(4, 1, 4, 5)},
+				},
+			},
+			{Name: "main", Kind: 12, Range: newRange(7, 5, 7, 9)},
+		}
+
+		if diff := cmp.Diff(expected, actual); diff != "" {
+			t.Errorf("unexpected symbols (-want +got):\n%s", diff)
+		}
+	}
+
+	if actual, err := db.DocumentSymbols(context.Background(), "missing.go"); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	} else if actual != nil {
+		t.Errorf("unexpected symbols for missing document: %v", actual)
+	}
+}
+
+func TestDatabaseSearchSymbols(t *testing.T) {
+	mockReader := mocks.NewMockReader()
+	mockReader.ReadSymbolsFunc.SetDefaultReturn([]types.SymbolRow{
+		{Name: "Server", Kind: 23, URI: "cmd/main.go", StartLine: 3, StartCharacter: 5, EndLine: 3, EndCharacter: 11},
+		{Name: "ServeHTTP", Kind: 6, ContainerName: "Server", URI: "cmd/main.go", StartLine: 9, StartCharacter: 17, EndLine: 9, EndCharacter: 26},
+		{Name: "Server", Kind: 23, URI: "cmd/main_test.go", StartLine: 2, StartCharacter: 5, EndLine: 2, EndCharacter: 11},
+		{Name: "main", Kind: 12, URI: "cmd/main.go", StartLine: 12, StartCharacter: 5, EndLine: 12, EndCharacter: 9},
+	}, nil)
+
+	db := &databaseImpl{filename: "test.db", reader: mockReader}
+
+	query := SymbolQuery{
+		Name:           regexp.MustCompile("^Serve"),
+		Root:           "sub/",
+		ExcludePattern: regexp.MustCompile(`^sub/.*_test\.go$`),
+	}
+	if actual, err := db.SearchSymbols(context.Background(), query, 10); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	} else {
+		expected := []SymbolLocation{
+			{Name: "Server", Kind: 23, Path: "cmd/main.go", Range: newRange(3, 5, 3, 11)},
+			{Name: "ServeHTTP", Kind: 6, ContainerName: "Server", Path: "cmd/main.go", Range: newRange(9, 17, 9, 26)},
+		}
+		if diff := cmp.Diff(expected, actual); diff != "" {
+			t.Errorf("unexpected symbols (-want +got):\n%s", diff)
+		}
+	}
+
+	query = SymbolQuery{IncludePatterns: []*regexp.Regexp{regexp.MustCompile(`main\.go$`)}}
+	if actual, err := db.SearchSymbols(context.Background(), query, 2); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	} else if len(actual) != 2 || actual[0].Name != "Server" || actual[1].Name != "ServeHTTP" {
+		t.Errorf("unexpected limited symbols: %v", actual)
+	}
+}
+
+func TestDatabaseReferenceIdentifiers(t *testing.T) {
+	expected := []types.ReferenceIdentifier{
+		{Scheme: "gomod", Identifier: "github.com/foo/bar:Baz"},
+		{Scheme: "npm", Identifier: "lodash:merge"},
+	}
+
+	mockReader := mocks.NewMockReader()
+	mockReader.ReadReferenceIdentifiersFunc.SetDefaultReturn(expected, nil)
+	db := &databaseImpl{filename: "test.db", reader: mockReader}
+
+	if actual, err := db.ReferenceIdentifiers(context.Background()); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	} else if diff := cmp.Diff(expected, actual); diff != "" {
+		t.Errorf("unexpected identifiers (-want +got):\n%s", diff)
+	}
+}
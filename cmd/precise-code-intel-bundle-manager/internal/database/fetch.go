@@ -0,0 +1,92 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/storage"
+)
+
+// OpenStoredDatabase opens the bundle stored at the given key. Bundles in a store on the local
+// disk are opened in place. Other bundles are first streamed into a new file in cacheDir, which
+// is removed once the database is closed (e.g. when it is evicted from a DatabaseCache). Every
+// call streams a separate copy, so a copy is never removed while another database has it open.
+func OpenStoredDatabase(
+	ctx context.Context,
+	store storage.Store,
+	key string,
+	cacheDir string,
+	documentDataCache *DocumentDataCache,
+	resultChunkDataCache *ResultChunkDataCache,
+) (Database, error) {
+	if localStore, ok := store.(storage.LocalStore); ok {
+		filename := localStore.Path(key)
+		if _, err := os.Stat(filename); err != nil {
+			if os.IsNotExist(err) {
+				return nil, fmt.Errorf("%w: %s", storage.ErrNotFound, key)
+			}
+			return nil, err
+		}
+
+		return OpenDatabase(ctx, filename, documentDataCache, resultChunkDataCache)
+	}
+
+	filename, err := fetch(ctx, store, key, cacheDir)
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := OpenDatabase(ctx, filename, documentDataCache, resultChunkDataCache)
+	if err != nil {
+		os.Remove(filename)
+		return nil, err
+	}
+
+	return &fetchedDatabase{Database: db, filename: filename}, nil
+}
+
+// fetch streams the object with the given key into a new file in cacheDir and returns its path.
+func fetch(ctx context.Context, store storage.Store, key, cacheDir string) (_ string, err error) {
+	rc, err := store.Get(ctx, key)
+	if err != nil {
+		return "", err
+	}
+	defer rc.Close()
+
+	f, err := ioutil.TempFile(cacheDir, "bundle-")
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			os.Remove(f.Name())
+		}
+	}()
+
+	if _, err := io.Copy(f, rc); err != nil {
+		return "", err
+	}
+
+	return f.Name(), nil
+}
+
+// fetchedDatabase is a database opened from a local copy of a stored bundle.
+type fetchedDatabase struct {
+	Database
+	filename string
+}
+
+// Close closes the underlying database and removes the local copy of the bundle.
+func (db *fetchedDatabase) Close() error {
+	err := db.Database.Close()
+	if removeErr := os.Remove(db.filename); err == nil {
+		err = removeErr
+	}
+	return err
+}
@@ -0,0 +1,58 @@
+package database
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/storage"
+)
+
+// remoteStore hides the local paths of a local store so that bundles are fetched.
+type remoteStore struct {
+	storage.Store
+}
+
+func TestOpenStoredDatabase(t *testing.T) {
+	cacheDir, err := ioutil.TempDir("", "precise-code-intel-bundle-manager-")
+	if err != nil {
+		t.Fatalf("unexpected error creating test directory: %s", err)
+	}
+	defer os.RemoveAll(cacheDir)
+
+	documentDataCache, err := NewDocumentDataCache(1)
+	if err != nil {
+		t.Fatalf("unexpected error creating cache: %s", err)
+	}
+	resultChunkDataCache, err := NewResultChunkDataCache(1)
+	if err != nil {
+		t.Fatalf("unexpected error creating cache: %s", err)
+	}
+
+	store := remoteStore{storage.NewLocal("../../../../internal/codeintel/bundles")}
+	db, err := OpenStoredDatabase(context.Background(), store, "testdata/lsif-go@ad3507cb.lsif.db", cacheDir, documentDataCache, resultChunkDataCache)
+	if err != nil {
+		t.Fatalf("unexpected error opening database: %s", err)
+	}
+
+	if exists, err := db.Exists(context.Background(), "cmd/lsif-go/main.go"); err != nil {
+		t.Fatalf("unexpected error %s", err)
+	} else if !exists {
+		t.Errorf("expected path to exist")
+	}
+
+	if err := db.Close(); err != nil {
+		t.Fatalf("unexpected error closing database: %s", err)
+	}
+
+	if fileInfos, err := ioutil.ReadDir(cacheDir); err != nil {
+		t.Fatalf("unexpected error listing directory: %s", err)
+	} else if len(fileInfos) != 0 {
+		t.Errorf("expected fetched bundle to be removed on close, found %d files", len(fileInfos))
+	}
+
+	if _, err := OpenStoredDatabase(context.Background(), store, "testdata/missing.lsif.db", cacheDir, documentDataCache, resultChunkDataCache); !storage.IsNotFound(err) {
+		t.Errorf("expected not found error opening missing bundle, got %v", err)
+	}
+}
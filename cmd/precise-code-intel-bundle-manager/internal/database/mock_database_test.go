@@ -19,6 +19,9 @@ type MockDatabase struct {
 	// DefinitionsFunc is an instance of a mock function object controlling
 	// the behavior of the method Definitions.
 	DefinitionsFunc *DatabaseDefinitionsFunc
+	// DocumentSymbolsFunc is an instance of a mock function object
+	// controlling the behavior of the method DocumentSymbols.
+	DocumentSymbolsFunc *DatabaseDocumentSymbolsFunc
 	// ExistsFunc is an instance of a mock function object controlling the
 	// behavior of the method Exists.
 	ExistsFunc *DatabaseExistsFunc
@@ -34,9 +37,18 @@ type MockDatabase struct {
 	// PackageInformationFunc is an instance of a mock function object
 	// controlling the behavior of the method PackageInformation.
 	PackageInformationFunc *DatabasePackageInformationFunc
+	// RangesFunc is an instance of a mock function object controlling the
+	// behavior of the method Ranges.
+	RangesFunc *DatabaseRangesFunc
+	// ReferenceIdentifiersFunc is an instance of a mock function object
+	// controlling the behavior of the method ReferenceIdentifiers.
+	ReferenceIdentifiersFunc *DatabaseReferenceIdentifiersFunc
 	// ReferencesFunc is an instance of a mock function object controlling
 	// the behavior of the method References.
 	ReferencesFunc *DatabaseReferencesFunc
+	// SearchSymbolsFunc is an instance of a mock function object
+	// controlling the behavior of the method SearchSymbols.
+	SearchSymbolsFunc *DatabaseSearchSymbolsFunc
 }
 
 // NewMockDatabase creates a new mock of the Database interface. All methods
@@ -53,6 +65,11 @@ func NewMockDatabase() *MockDatabase {
 				return nil, nil
 			},
 		},
+		DocumentSymbolsFunc: &DatabaseDocumentSymbolsFunc{
+			defaultHook: func(context.Context, string) ([]Symbol, error) {
+				return nil, nil
+			},
+		},
 		ExistsFunc: &DatabaseExistsFunc{
 			defaultHook: func(context.Context, string) (bool, error) {
 				return false, nil
@@ -78,11 +95,26 @@ func NewMockDatabase() *MockDatabase {
 				return types.PackageInformationData{}, false, nil
 			},
 		},
+		RangesFunc: &DatabaseRangesFunc{
+			defaultHook: func(context.Context, string, []Position) ([]CodeIntelligenceRange, error) {
+				return nil, nil
+			},
+		},
+		ReferenceIdentifiersFunc: &DatabaseReferenceIdentifiersFunc{
+			defaultHook: func(context.Context) ([]types.ReferenceIdentifier, error) {
+				return nil, nil
+			},
+		},
 		ReferencesFunc: &DatabaseReferencesFunc{
 			defaultHook: func(context.Context, string, int, int) ([]Location, error) {
 				return nil, nil
 			},
 		},
+		SearchSymbolsFunc: &DatabaseSearchSymbolsFunc{
+			defaultHook: func(context.Context, SymbolQuery, int) ([]SymbolLocation, error) {
+				return nil, nil
+			},
+		},
 	}
 }
 
@@ -96,6 +128,9 @@ func NewMockDatabaseFrom(i Database) *MockDatabase {
 		DefinitionsFunc: &DatabaseDefinitionsFunc{
 			defaultHook: i.Definitions,
 		},
+		DocumentSymbolsFunc: &DatabaseDocumentSymbolsFunc{
+			defaultHook: i.DocumentSymbols,
+		},
 		ExistsFunc: &DatabaseExistsFunc{
 			defaultHook: i.Exists,
 		},
@@ -111,9 +146,18 @@ func NewMockDatabaseFrom(i Database) *MockDatabase {
 		PackageInformationFunc: &DatabasePackageInformationFunc{
 			defaultHook: i.PackageInformation,
 		},
+		RangesFunc: &DatabaseRangesFunc{
+			defaultHook: i.Ranges,
+		},
+		ReferenceIdentifiersFunc: &DatabaseReferenceIdentifiersFunc{
+			defaultHook: i.ReferenceIdentifiers,
+		},
 		ReferencesFunc: &DatabaseReferencesFunc{
 			defaultHook: i.References,
 		},
+		SearchSymbolsFunc: &DatabaseSearchSymbolsFunc{
+			defaultHook: i.SearchSymbols,
+		},
 	}
 }
 
@@ -331,6 +375,115 @@ func (c DatabaseDefinitionsFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
 
+// DatabaseDocumentSymbolsFunc describes the behavior when the
+// DocumentSymbols method of the parent MockDatabase instance is invoked.
+type DatabaseDocumentSymbolsFunc struct {
+	defaultHook func(context.Context, string) ([]Symbol, error)
+	hooks       []func(context.Context, string) ([]Symbol, error)
+	history     []DatabaseDocumentSymbolsFuncCall
+	mutex       sync.Mutex
+}
+
+// DocumentSymbols delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockDatabase) DocumentSymbols(v0 context.Context, v1 string) ([]Symbol, error) {
+	r0, r1 := m.DocumentSymbolsFunc.nextHook()(v0, v1)
+	m.DocumentSymbolsFunc.appendCall(DatabaseDocumentSymbolsFuncCall{v0, v1, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the DocumentSymbols
+// method of the parent MockDatabase instance is invoked and the hook queue
+// is empty.
+func (f *DatabaseDocumentSymbolsFunc) SetDefaultHook(hook func(context.Context, string) ([]Symbol, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// DocumentSymbols method of the parent MockDatabase instance inovkes the
+// hook at the front of the queue and discards it. After the queue is empty,
+// the default hook function is invoked for any future action.
+func (f *DatabaseDocumentSymbolsFunc) PushHook(hook func(context.Context, string) ([]Symbol, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *DatabaseDocumentSymbolsFunc) SetDefaultReturn(r0 []Symbol, r1 error) {
+	f.SetDefaultHook(func(context.Context, string) ([]Symbol, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *DatabaseDocumentSymbolsFunc) PushReturn(r0 []Symbol, r1 error) {
+	f.PushHook(func(context.Context, string) ([]Symbol, error) {
+		return r0, r1
+	})
+}
+
+func (f *DatabaseDocumentSymbolsFunc) nextHook() func(context.Context, string) ([]Symbol, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *DatabaseDocumentSymbolsFunc) appendCall(r0 DatabaseDocumentSymbolsFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of DatabaseDocumentSymbolsFuncCall objects
+// describing the invocations of this function.
+func (f *DatabaseDocumentSymbolsFunc) History() []DatabaseDocumentSymbolsFuncCall {
+	f.mutex.Lock()
+	history := make([]DatabaseDocumentSymbolsFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// DatabaseDocumentSymbolsFuncCall is an object that describes an invocation
+// of method DocumentSymbols on an instance of MockDatabase.
+type DatabaseDocumentSymbolsFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 string
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []Symbol
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c DatabaseDocumentSymbolsFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c DatabaseDocumentSymbolsFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
 // DatabaseExistsFunc describes the behavior when the Exists method of the
 // parent MockDatabase instance is invoked.
 type DatabaseExistsFunc struct {
@@ -913,6 +1066,224 @@ func (c DatabasePackageInformationFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1, c.Result2}
 }
 
+// DatabaseRangesFunc describes the behavior when the Ranges method of the
+// parent MockDatabase instance is invoked.
+type DatabaseRangesFunc struct {
+	defaultHook func(context.Context, string, []Position) ([]CodeIntelligenceRange, error)
+	hooks       []func(context.Context, string, []Position) ([]CodeIntelligenceRange, error)
+	history     []DatabaseRangesFuncCall
+	mutex       sync.Mutex
+}
+
+// Ranges delegates to the next hook function in the queue and stores the
+// parameter and result values of this invocation.
+func (m *MockDatabase) Ranges(v0 context.Context, v1 string, v2 []Position) ([]CodeIntelligenceRange, error) {
+	r0, r1 := m.RangesFunc.nextHook()(v0, v1, v2)
+	m.RangesFunc.appendCall(DatabaseRangesFuncCall{v0, v1, v2, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the Ranges method of the
+// parent MockDatabase instance is invoked and the hook queue is empty.
+func (f *DatabaseRangesFunc) SetDefaultHook(hook func(context.Context, string, []Position) ([]CodeIntelligenceRange, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// Ranges method of the parent MockDatabase instance inovkes the hook at the
+// front of the queue and discards it. After the queue is empty, the default
+// hook function is invoked for any future action.
+func (f *DatabaseRangesFunc) PushHook(hook func(context.Context, string, []Position) ([]CodeIntelligenceRange, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *DatabaseRangesFunc) SetDefaultReturn(r0 []CodeIntelligenceRange, r1 error) {
+	f.SetDefaultHook(func(context.Context, string, []Position) ([]CodeIntelligenceRange, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *DatabaseRangesFunc) PushReturn(r0 []CodeIntelligenceRange, r1 error) {
+	f.PushHook(func(context.Context, string, []Position) ([]CodeIntelligenceRange, error) {
+		return r0, r1
+	})
+}
+
+func (f *DatabaseRangesFunc) nextHook() func(context.Context, string, []Position) ([]CodeIntelligenceRange, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *DatabaseRangesFunc) appendCall(r0 DatabaseRangesFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of DatabaseRangesFuncCall objects describing
+// the invocations of this function.
+func (f *DatabaseRangesFunc) History() []DatabaseRangesFuncCall {
+	f.mutex.Lock()
+	history := make([]DatabaseRangesFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// DatabaseRangesFuncCall is an object that describes an invocation of
+// method Ranges on an instance of MockDatabase.
+type DatabaseRangesFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 string
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 []Position
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []CodeIntelligenceRange
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c DatabaseRangesFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c DatabaseRangesFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
+// DatabaseReferenceIdentifiersFunc describes the behavior when the
+// ReferenceIdentifiers method of the parent MockDatabase instance is
+// invoked.
+type DatabaseReferenceIdentifiersFunc struct {
+	defaultHook func(context.Context) ([]types.ReferenceIdentifier, error)
+	hooks       []func(context.Context) ([]types.ReferenceIdentifier, error)
+	history     []DatabaseReferenceIdentifiersFuncCall
+	mutex       sync.Mutex
+}
+
+// ReferenceIdentifiers delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockDatabase) ReferenceIdentifiers(v0 context.Context) ([]types.ReferenceIdentifier, error) {
+	r0, r1 := m.ReferenceIdentifiersFunc.nextHook()(v0)
+	m.ReferenceIdentifiersFunc.appendCall(DatabaseReferenceIdentifiersFuncCall{v0, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the ReferenceIdentifiers
+// method of the parent MockDatabase instance is invoked and the hook queue
+// is empty.
+func (f *DatabaseReferenceIdentifiersFunc) SetDefaultHook(hook func(context.Context) ([]types.ReferenceIdentifier, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// ReferenceIdentifiers method of the parent MockDatabase instance inovkes
+// the hook at the front of the queue and discards it. After the queue is
+// empty, the default hook function is invoked for any future action.
+func (f *DatabaseReferenceIdentifiersFunc) PushHook(hook func(context.Context) ([]types.ReferenceIdentifier, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *DatabaseReferenceIdentifiersFunc) SetDefaultReturn(r0 []types.ReferenceIdentifier, r1 error) {
+	f.SetDefaultHook(func(context.Context) ([]types.ReferenceIdentifier, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *DatabaseReferenceIdentifiersFunc) PushReturn(r0 []types.ReferenceIdentifier, r1 error) {
+	f.PushHook(func(context.Context) ([]types.ReferenceIdentifier, error) {
+		return r0, r1
+	})
+}
+
+func (f *DatabaseReferenceIdentifiersFunc) nextHook() func(context.Context) ([]types.ReferenceIdentifier, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *DatabaseReferenceIdentifiersFunc) appendCall(r0 DatabaseReferenceIdentifiersFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of DatabaseReferenceIdentifiersFuncCall
+// objects describing the invocations of this function.
+func (f *DatabaseReferenceIdentifiersFunc) History() []DatabaseReferenceIdentifiersFuncCall {
+	f.mutex.Lock()
+	history := make([]DatabaseReferenceIdentifiersFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// DatabaseReferenceIdentifiersFuncCall is an object that describes an
+// invocation of method ReferenceIdentifiers on an instance of MockDatabase.
+type DatabaseReferenceIdentifiersFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []types.ReferenceIdentifier
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c DatabaseReferenceIdentifiersFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c DatabaseReferenceIdentifiersFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
 // DatabaseReferencesFunc describes the behavior when the References method
 // of the parent MockDatabase instance is invoked.
 type DatabaseReferencesFunc struct {
@@ -1026,3 +1397,115 @@ func (c DatabaseReferencesFuncCall) Args() []interface{} {
 func (c DatabaseReferencesFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
+
+// DatabaseSearchSymbolsFunc describes the behavior when the SearchSymbols
+// method of the parent MockDatabase instance is invoked.
+type DatabaseSearchSymbolsFunc struct {
+	defaultHook func(context.Context, SymbolQuery, int) ([]SymbolLocation, error)
+	hooks       []func(context.Context, SymbolQuery, int) ([]SymbolLocation, error)
+	history     []DatabaseSearchSymbolsFuncCall
+	mutex       sync.Mutex
+}
+
+// SearchSymbols delegates to the next hook function in the queue and stores
+// the parameter and result values of this invocation.
+func (m *MockDatabase) SearchSymbols(v0 context.Context, v1 SymbolQuery, v2 int) ([]SymbolLocation, error) {
+	r0, r1 := m.SearchSymbolsFunc.nextHook()(v0, v1, v2)
+	m.SearchSymbolsFunc.appendCall(DatabaseSearchSymbolsFuncCall{v0, v1, v2, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the SearchSymbols method
+// of the parent MockDatabase instance is invoked and the hook queue is
+// empty.
+func (f *DatabaseSearchSymbolsFunc) SetDefaultHook(hook func(context.Context, SymbolQuery, int) ([]SymbolLocation, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// SearchSymbols method of the parent MockDatabase instance inovkes the hook
+// at the front of the queue and discards it. After the queue is empty, the
+// default hook function is invoked for any future action.
+func (f *DatabaseSearchSymbolsFunc) PushHook(hook func(context.Context, SymbolQuery, int) ([]SymbolLocation, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *DatabaseSearchSymbolsFunc) SetDefaultReturn(r0 []SymbolLocation, r1 error) {
+	f.SetDefaultHook(func(context.Context, SymbolQuery, int) ([]SymbolLocation, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *DatabaseSearchSymbolsFunc) PushReturn(r0 []SymbolLocation, r1 error) {
+	f.PushHook(func(context.Context, SymbolQuery, int) ([]SymbolLocation, error) {
+		return r0, r1
+	})
+}
+
+func (f *DatabaseSearchSymbolsFunc) nextHook() func(context.Context, SymbolQuery, int) ([]SymbolLocation, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *DatabaseSearchSymbolsFunc) appendCall(r0 DatabaseSearchSymbolsFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of DatabaseSearchSymbolsFuncCall objects
+// describing the invocations of this function.
+func (f *DatabaseSearchSymbolsFunc) History() []DatabaseSearchSymbolsFuncCall {
+	f.mutex.Lock()
+	history := make([]DatabaseSearchSymbolsFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// DatabaseSearchSymbolsFuncCall is an object that describes an invocation
+// of method SearchSymbols on an instance of MockDatabase.
+type DatabaseSearchSymbolsFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 SymbolQuery
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 int
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []SymbolLocation
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c DatabaseSearchSymbolsFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c DatabaseSearchSymbolsFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
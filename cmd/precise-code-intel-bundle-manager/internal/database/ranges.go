@@ -24,6 +24,23 @@ func findRanges(ranges map[types.ID]types.RangeData, line, character int) []type
 	return filtered
 }
 
+// sortRanges returns the given ranges ordered by their start position.
+func sortRanges(ranges map[types.ID]types.RangeData) []types.RangeData {
+	sorted := make([]types.RangeData, 0, len(ranges))
+	for _, r := range ranges {
+		sorted = append(sorted, r)
+	}
+
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].StartLine != sorted[j].StartLine {
+			return sorted[i].StartLine < sorted[j].StartLine
+		}
+		return sorted[i].StartCharacter < sorted[j].StartCharacter
+	})
+
+	return sorted
+}
+
 // comparePosition compres the range r with the position constructed from line and character.
 // Returns -1 if the position occurs before the range, +1 if it occurs after, and 0 if the
 // position is inside of the range.
@@ -0,0 +1,43 @@
+package janitor
+
+import (
+	"context"
+
+	"github.com/inconshreveable/log15"
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/lsifserver/client"
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/storage"
+)
+
+type RetentionFn func(ctx context.Context) ([]int, error)
+
+func defaultRetentionFn(ctx context.Context) ([]int, error) {
+	ids, err := client.DefaultClient.Retention(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "lsifserver.Retention")
+	}
+
+	return ids, nil
+}
+
+// applyRetentionPolicies calls the precise-code-intel-api-server to delete the dumps that
+// expired under the configured retention policies, then removes their databases from the
+// store. Dumps retained by the policies are also skipped when freeing disk space.
+func (j *Janitor) applyRetentionPolicies(retentionFn RetentionFn) error {
+	ctx := context.Background()
+
+	ids, err := retentionFn(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		if err := j.store.Delete(ctx, storage.DBKey(int64(id))); err != nil {
+			return err
+		}
+
+		log15.Debug("Removed expired dump", "id", id)
+	}
+
+	return nil
+}
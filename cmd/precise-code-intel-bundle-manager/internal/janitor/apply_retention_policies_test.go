@@ -0,0 +1,45 @@
+package janitor
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/storage"
+)
+
+func TestApplyRetentionPolicies(t *testing.T) {
+	withRoot(t, func(bundleDir string) {
+		for i := 1; i <= 5; i++ {
+			path := filepath.Join(bundleDir, "dbs", fmt.Sprintf("%d.lsif.db", i))
+			if err := makeFileWithSize(path, 10); err != nil {
+				t.Fatalf("unexpected error creating file %s: %s", path, err)
+			}
+		}
+
+		retentionFn := func(ctx context.Context) ([]int, error) {
+			// Dump 6 was never converted
+			return []int{2, 4, 6}, nil
+		}
+
+		j := &Janitor{
+			store: storage.NewLocal(bundleDir),
+		}
+
+		if err := j.applyRetentionPolicies(retentionFn); err != nil {
+			t.Fatalf("unexpected error applying retention policies: %s", err)
+		}
+
+		names, err := getFilenames(filepath.Join(bundleDir, "dbs"))
+		if err != nil {
+			t.Fatalf("unexpected error listing directory: %s", err)
+		}
+
+		expected := []string{"1.lsif.db", "3.lsif.db", "5.lsif.db"}
+		if diff := cmp.Diff(expected, names); diff != "" {
+			t.Errorf("unexpected directory contents (-want +got):\n%s", diff)
+		}
+	})
+}
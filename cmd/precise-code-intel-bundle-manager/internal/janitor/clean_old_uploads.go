@@ -1,35 +1,34 @@
 package janitor
 
 import (
-	"io/ioutil"
-	"os"
-	"path/filepath"
+	"context"
 	"time"
 
 	"github.com/inconshreveable/log15"
-	"github.com/sourcegraph/sourcegraph/cmd/precise-code-intel-bundle-manager/internal/paths"
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/storage"
 )
 
-// cleanOldUploads removes all upload files that are older than the configured
+// cleanOldUploads removes all uploads that are older than the configured
 // max unconverted upload age.
 func (j *Janitor) cleanOldUploads() error {
-	fileInfos, err := ioutil.ReadDir(paths.UploadsDir(j.bundleDir))
+	ctx := context.Background()
+
+	objects, err := j.store.List(ctx, storage.UploadsPrefix)
 	if err != nil {
 		return err
 	}
 
-	for _, fileInfo := range fileInfos {
-		age := time.Since(fileInfo.ModTime())
+	for _, object := range objects {
+		age := time.Since(object.LastModified)
 		if age < j.maxUploadAge {
 			continue
 		}
 
-		path := filepath.Join(paths.UploadsDir(j.bundleDir), fileInfo.Name())
-		if err := os.Remove(path); err != nil {
+		if err := j.store.Delete(ctx, object.Key); err != nil {
 			return err
 		}
 
-		log15.Debug("Removed old upload", "path", fileInfo.Name(), "age", age)
+		log15.Debug("Removed old upload", "key", object.Key, "age", age)
 	}
 
 	return nil
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/storage"
 )
 
 func TestCleanOldUploads(t *testing.T) {
@@ -25,7 +26,7 @@ func TestCleanOldUploads(t *testing.T) {
 		}
 
 		j := &Janitor{
-			bundleDir:    bundleDir,
+			store:        storage.NewLocal(bundleDir),
 			maxUploadAge: time.Minute,
 		}
 
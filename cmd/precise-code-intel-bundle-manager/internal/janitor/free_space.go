@@ -6,8 +6,8 @@ import (
 	"syscall"
 
 	"github.com/pkg/errors"
-	"github.com/sourcegraph/sourcegraph/cmd/precise-code-intel-bundle-manager/internal/paths"
 	"github.com/sourcegraph/sourcegraph/internal/codeintel/lsifserver/client"
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/storage"
 )
 
 type PruneFn func(ctx context.Context) (int64, bool, error)
@@ -23,7 +23,13 @@ func defaultPruneFn(ctx context.Context) (int64, bool, error) {
 
 // freeSpace determines the space available on the device containing the bundle directory,
 // then calls cleanOldDumps to free enough space to get back below the disk usage threshold.
+// Dumps are only pruned when they are stored on the local disk: object storage does not
+// fill up, and the bundles cached from it are bounded by the size of the database cache.
 func (j *Janitor) freeSpace(pruneFn PruneFn) error {
+	if _, ok := j.store.(storage.LocalStore); !ok {
+		return nil
+	}
+
 	var fs syscall.Statfs_t
 	if err := syscall.Statfs(j.bundleDir, &fs); err != nil {
 		return err
@@ -72,14 +78,14 @@ func (j *Janitor) cleanOldDump(pruneFn func(ctx context.Context) (int64, bool, e
 		return 0, false, err
 	}
 
-	filename := paths.DBFilename(j.bundleDir, id)
+	key := storage.DBKey(id)
 
-	fileInfo, err := os.Stat(filename)
+	fileInfo, err := os.Stat(j.store.(storage.LocalStore).Path(key))
 	if err != nil {
 		return 0, false, err
 	}
 
-	if err := os.Remove(filename); err != nil {
+	if err := j.store.Delete(context.Background(), key); err != nil {
 		return 0, false, err
 	}
 
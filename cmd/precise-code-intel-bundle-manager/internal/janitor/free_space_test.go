@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/storage"
 )
 
 func TestCleanOldDumpsStopsAfterFreeingDesiredSpace(t *testing.T) {
@@ -38,7 +39,7 @@ func TestCleanOldDumpsStopsAfterFreeingDesiredSpace(t *testing.T) {
 		}
 
 		j := &Janitor{
-			bundleDir: bundleDir,
+			store: storage.NewLocal(bundleDir),
 		}
 
 		if err := j.cleanOldDumps(pruneFn, 100); err != nil {
@@ -91,7 +92,7 @@ func TestCleanOldDumpsStopsWithNoPrunableDatabases(t *testing.T) {
 		}
 
 		j := &Janitor{
-			bundleDir: bundleDir,
+			store: storage.NewLocal(bundleDir),
 		}
 
 		if err := j.cleanOldDumps(pruneFn, 100); err != nil {
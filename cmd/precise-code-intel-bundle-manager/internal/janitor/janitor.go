@@ -5,9 +5,11 @@ import (
 
 	"github.com/inconshreveable/log15"
 	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/storage"
 )
 
 type Janitor struct {
+	store              storage.Store
 	bundleDir          string
 	desiredPercentFree int
 	janitorInterval    time.Duration
@@ -15,6 +17,7 @@ type Janitor struct {
 }
 
 type JanitorOpts struct {
+	Store              storage.Store
 	BundleDir          string
 	DesiredPercentFree int
 	JanitorInterval    time.Duration
@@ -23,6 +26,7 @@ type JanitorOpts struct {
 
 func NewJanitor(opts JanitorOpts) *Janitor {
 	return &Janitor{
+		store:              opts.Store,
 		bundleDir:          opts.BundleDir,
 		desiredPercentFree: opts.DesiredPercentFree,
 		janitorInterval:    opts.JanitorInterval,
@@ -32,7 +36,7 @@ func NewJanitor(opts JanitorOpts) *Janitor {
 
 // step performs a best-effort cleanup. See the following methods for more specifics.
 // Run periodically performs a best-effort cleanup process. See the following methods
-// for more specifics: cleanOldUploads, removeDeadDumps, and freeSpace.
+// for more specifics: cleanOldUploads, applyRetentionPolicies, removeDeadDumps, and freeSpace.
 func (j *Janitor) Run() {
 	for {
 		if err := j.run(); err != nil {
@@ -48,6 +52,10 @@ func (j *Janitor) run() error {
 		return errors.Wrap(err, "janitor.cleanOldUploads")
 	}
 
+	if err := j.applyRetentionPolicies(defaultRetentionFn); err != nil {
+		return errors.Wrap(err, "janitor.applyRetentionPolicies")
+	}
+
 	if err := j.removeDeadDumps(defaultStatesFn); err != nil {
 		return errors.Wrap(err, "janitor.removeDeadDumps")
 	}
@@ -2,16 +2,13 @@ package janitor
 
 import (
 	"context"
-	"io/ioutil"
-	"os"
-	"path/filepath"
 	"strconv"
 	"strings"
 
 	"github.com/inconshreveable/log15"
 	"github.com/pkg/errors"
-	"github.com/sourcegraph/sourcegraph/cmd/precise-code-intel-bundle-manager/internal/paths"
 	"github.com/sourcegraph/sourcegraph/internal/codeintel/lsifserver/client"
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/storage"
 )
 
 // DeadDumpBatchSize is the maximum number of dump ids to request at once from
@@ -30,16 +27,16 @@ func defaultStatesFn(ctx context.Context, ids []int) (map[int]string, error) {
 }
 
 // removeDeadDumps calls the precise-code-intel-api-server to get the current
-// state of the dumps known by this bundle manager. Any dump on disk that is
+// state of the dumps known by this bundle manager. Any dump in the store that is
 // in an errored state or is unknown by the API is removed.
 func (j *Janitor) removeDeadDumps(statesFn StatesFn) error {
-	pathsByID, err := j.databasePathsByID()
+	keysByID, err := j.databaseKeysByID()
 	if err != nil {
 		return err
 	}
 
 	var ids []int
-	for id := range pathsByID {
+	for id := range keysByID {
 		ids = append(ids, id)
 	}
 
@@ -55,9 +52,9 @@ func (j *Janitor) removeDeadDumps(statesFn StatesFn) error {
 		}
 	}
 
-	for id, path := range pathsByID {
+	for id, key := range keysByID {
 		if state, exists := allStates[id]; !exists || state == "errored" {
-			if err := os.Remove(path); err != nil {
+			if err := j.store.Delete(context.Background(), key); err != nil {
 				return err
 			}
 
@@ -68,21 +65,22 @@ func (j *Janitor) removeDeadDumps(statesFn StatesFn) error {
 	return nil
 }
 
-// databasePathsByID returns map of dump ids to their path on disk.
-func (j *Janitor) databasePathsByID() (map[int]string, error) {
-	fileInfos, err := ioutil.ReadDir(paths.DBsDir(j.bundleDir))
+// databaseKeysByID returns map of dump ids to their key in the store.
+func (j *Janitor) databaseKeysByID() (map[int]string, error) {
+	objects, err := j.store.List(context.Background(), storage.DBsPrefix)
 	if err != nil {
 		return nil, err
 	}
 
-	pathsByID := map[int]string{}
-	for _, fileInfo := range fileInfos {
-		if id, err := strconv.Atoi(strings.Split(fileInfo.Name(), ".")[0]); err == nil {
-			pathsByID[int(id)] = filepath.Join(paths.DBsDir(j.bundleDir), fileInfo.Name())
+	keysByID := map[int]string{}
+	for _, object := range objects {
+		name := strings.TrimPrefix(object.Key, storage.DBsPrefix)
+		if id, err := strconv.Atoi(strings.Split(name, ".")[0]); err == nil {
+			keysByID[int(id)] = object.Key
 		}
 	}
 
-	return pathsByID, nil
+	return keysByID, nil
 }
 
 // batchIntSlice returns slices of s (in order) at most batchSize in length.
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/storage"
 )
 
 func TestRemoveDeadDumps(t *testing.T) {
@@ -23,7 +24,7 @@ func TestRemoveDeadDumps(t *testing.T) {
 		}
 
 		j := &Janitor{
-			bundleDir: bundleDir,
+			store: storage.NewLocal(bundleDir),
 		}
 
 		var idArgs [][]int
@@ -78,7 +79,7 @@ func TestRemoveDeadDumpsMaxRequestBatchSize(t *testing.T) {
 		}
 
 		j := &Janitor{
-			bundleDir: bundleDir,
+			store: storage.NewLocal(bundleDir),
 		}
 
 		var idArgs [][]int
@@ -1,15 +1,19 @@
 package paths
 
 import (
-	"fmt"
 	"os"
 	"path/filepath"
 )
 
-// PrepDirectories
+// PrepDirectories creates the directories used by the bundle manager and removes the
+// bundles which were cached by a previous process.
 func PrepDirectories(bundleDir string) error {
-	for _, dir := range []string{UploadsDir(bundleDir), DBsDir(bundleDir)} {
-		if err := os.MkdirAll(filepath.Join(bundleDir, dir), os.ModePerm); err != nil {
+	if err := os.RemoveAll(CacheDir(bundleDir)); err != nil {
+		return err
+	}
+
+	for _, dir := range []string{UploadsDir(bundleDir), DBsDir(bundleDir), CacheDir(bundleDir)} {
+		if err := os.MkdirAll(dir, os.ModePerm); err != nil {
 			return err
 		}
 	}
@@ -27,12 +31,8 @@ func DBsDir(bundleDir string) string {
 	return filepath.Join(bundleDir, "dbs")
 }
 
-// UploadFilename returns the path fo the upload with the given identifier.
-func UploadFilename(bundleDir string, id int64) string {
-	return filepath.Join(bundleDir, "uploads", fmt.Sprintf("%d.lsif.gz", id))
-}
-
-// DBFilename returns the path fo the database with the given identifier.
-func DBFilename(bundleDir string, id int64) string {
-	return filepath.Join(bundleDir, "dbs", fmt.Sprintf("%d.lsif.db", id))
+// CacheDir returns the path of the directory holding local copies of the bundles
+// fetched from object storage.
+func CacheDir(bundleDir string) string {
+	return filepath.Join(bundleDir, "cache")
 }
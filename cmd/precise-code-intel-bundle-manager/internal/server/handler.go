@@ -4,18 +4,17 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"io"
 	"net/http"
-	"os"
 
 	"github.com/gorilla/mux"
 	"github.com/inconshreveable/log15"
 	"github.com/sourcegraph/sourcegraph/cmd/precise-code-intel-bundle-manager/internal/database"
-	"github.com/sourcegraph/sourcegraph/cmd/precise-code-intel-bundle-manager/internal/paths"
 	"github.com/sourcegraph/sourcegraph/internal/codeintel/bundles/types"
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/storage"
 )
 
 const DefaultMonikerResultPageSize = 100
+const DefaultSymbolLimit = 100
 
 func (s *Server) handler() http.Handler {
 	mux := mux.NewRouter()
@@ -29,6 +28,10 @@ func (s *Server) handler() http.Handler {
 	mux.Path("/dbs/{id:[0-9]+}/monikersByPosition").Methods("GET").HandlerFunc(s.handleMonikersByPosition)
 	mux.Path("/dbs/{id:[0-9]+}/monikerResults").Methods("GET").HandlerFunc(s.handleMonikerResults)
 	mux.Path("/dbs/{id:[0-9]+}/packageInformation").Methods("GET").HandlerFunc(s.handlePackageInformation)
+	mux.Path("/dbs/{id:[0-9]+}/documentSymbols").Methods("GET").HandlerFunc(s.handleDocumentSymbols)
+	mux.Path("/dbs/{id:[0-9]+}/symbols").Methods("GET").HandlerFunc(s.handleSymbols)
+	mux.Path("/dbs/{id:[0-9]+}/ranges").Methods("GET").HandlerFunc(s.handleRanges)
+	mux.Path("/dbs/{id:[0-9]+}/referenceIdentifiers").Methods("GET").HandlerFunc(s.handleReferenceIdentifiers)
 	mux.HandleFunc("/healthz", func(w http.ResponseWriter, _ *http.Request) {
 		w.WriteHeader(http.StatusOK)
 	})
@@ -37,24 +40,30 @@ func (s *Server) handler() http.Handler {
 
 // GET /uploads/{id:[0-9]+}
 func (s *Server) handleGetUpload(w http.ResponseWriter, r *http.Request) {
-	file, err := os.Open(paths.UploadFilename(s.bundleDir, idFromRequest(r)))
+	rc, err := s.store.Get(r.Context(), storage.UploadKey(idFromRequest(r)))
 	if err != nil {
-		http.Error(w, "Upload not found.", http.StatusNotFound)
+		if storage.IsNotFound(err) {
+			http.Error(w, "Upload not found.", http.StatusNotFound)
+			return
+		}
+
+		log15.Error("Failed to read upload", "err", err)
+		http.Error(w, fmt.Sprintf("failed to read upload: %s", err.Error()), http.StatusInternalServerError)
 		return
 	}
-	defer file.Close()
+	defer rc.Close()
 
-	copyAll(w, file)
+	copyAll(w, rc)
 }
 
 // POST /uploads/{id:[0-9]+}
 func (s *Server) handlePostUpload(w http.ResponseWriter, r *http.Request) {
-	s.doUpload(w, r, paths.UploadFilename)
+	s.doUpload(w, r, storage.UploadKey)
 }
 
 // POST /dbs/{id:[0-9]+}
 func (s *Server) handlePostDatabase(w http.ResponseWriter, r *http.Request) {
-	s.doUpload(w, r, paths.DBFilename)
+	s.doUpload(w, r, storage.DBKey)
 }
 
 // GET /dbs/{id:[0-9]+}/exists
@@ -152,19 +161,52 @@ func (s *Server) handlePackageInformation(w http.ResponseWriter, r *http.Request
 	})
 }
 
-// doUpload writes the HTTP request body to the path determined by the given
-// makeFilename function.
-func (s *Server) doUpload(w http.ResponseWriter, r *http.Request, makeFilename func(bundleDir string, id int64) string) {
-	defer r.Body.Close()
+// GET /dbs/{id:[0-9]+}/documentSymbols
+func (s *Server) handleDocumentSymbols(w http.ResponseWriter, r *http.Request) {
+	s.dbQuery(w, r, func(ctx context.Context, db database.Database) (interface{}, error) {
+		return db.DocumentSymbols(ctx, getQuery(r, "path"))
+	})
+}
+
+// GET /dbs/{id:[0-9]+}/symbols
+func (s *Server) handleSymbols(w http.ResponseWriter, r *http.Request) {
+	query, err := getSymbolQuery(r)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("illegal symbol query: %s", err.Error()), http.StatusBadRequest)
+		return
+	}
+
+	s.dbQuery(w, r, func(ctx context.Context, db database.Database) (interface{}, error) {
+		return db.SearchSymbols(ctx, query, getQueryIntDefault(r, "limit", DefaultSymbolLimit))
+	})
+}
 
-	targetFile, err := os.OpenFile(makeFilename(s.bundleDir, idFromRequest(r)), os.O_WRONLY|os.O_CREATE, 0666)
+// GET /dbs/{id:[0-9]+}/ranges
+func (s *Server) handleRanges(w http.ResponseWriter, r *http.Request) {
+	positions, err := getQueryPositions(r, "positions")
 	if err != nil {
-		log15.Error("Failed to open target file", "err", err)
-		http.Error(w, fmt.Sprintf("failed to open target file: %s", err.Error()), http.StatusInternalServerError)
+		http.Error(w, fmt.Sprintf("illegal positions: %s", err.Error()), http.StatusBadRequest)
 		return
 	}
 
-	if _, err := io.Copy(targetFile, r.Body); err != nil {
+	s.dbQuery(w, r, func(ctx context.Context, db database.Database) (interface{}, error) {
+		return db.Ranges(ctx, getQuery(r, "path"), positions)
+	})
+}
+
+// GET /dbs/{id:[0-9]+}/referenceIdentifiers
+func (s *Server) handleReferenceIdentifiers(w http.ResponseWriter, r *http.Request) {
+	s.dbQuery(w, r, func(ctx context.Context, db database.Database) (interface{}, error) {
+		return db.ReferenceIdentifiers(ctx)
+	})
+}
+
+// doUpload writes the HTTP request body to the store under the key determined by
+// the given makeKey function.
+func (s *Server) doUpload(w http.ResponseWriter, r *http.Request, makeKey func(id int64) string) {
+	defer r.Body.Close()
+
+	if err := s.store.Upload(r.Context(), makeKey(idFromRequest(r)), r.Body); err != nil {
 		log15.Error("Failed to write payload", "err", err)
 		http.Error(w, fmt.Sprintf("failed to write payload: %s", err.Error()), http.StatusInternalServerError)
 		return
@@ -175,10 +217,10 @@ func (s *Server) doUpload(w http.ResponseWriter, r *http.Request, makeFilename f
 // route's id value and serializes the resulting value to the response writer.
 func (s *Server) dbQuery(w http.ResponseWriter, r *http.Request, handler func(ctx context.Context, db database.Database) (interface{}, error)) {
 	ctx := r.Context()
-	filename := paths.DBFilename(s.bundleDir, idFromRequest(r))
+	key := storage.DBKey(idFromRequest(r))
 
 	openDatabase := func() (database.Database, error) {
-		return database.OpenDatabase(ctx, filename, s.documentDataCache, s.resultChunkDataCache)
+		return database.OpenStoredDatabase(ctx, s.store, key, s.cacheDir, s.documentDataCache, s.resultChunkDataCache)
 	}
 
 	cacheHandler := func(db database.Database) error {
@@ -191,7 +233,12 @@ func (s *Server) dbQuery(w http.ResponseWriter, r *http.Request, handler func(ct
 		return nil
 	}
 
-	if err := s.databaseCache.WithDatabase(filename, openDatabase, cacheHandler); err != nil {
+	if err := s.databaseCache.WithDatabase(key, openDatabase, cacheHandler); err != nil {
+		if storage.IsNotFound(err) {
+			http.Error(w, "Database not found.", http.StatusNotFound)
+			return
+		}
+
 		http.Error(w, fmt.Sprintf("failed to handle query: %s", err.Error()), http.StatusInternalServerError)
 		return
 	}
@@ -8,13 +8,15 @@ import (
 
 	"github.com/inconshreveable/log15"
 	"github.com/sourcegraph/sourcegraph/cmd/precise-code-intel-bundle-manager/internal/database"
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/storage"
 	"github.com/sourcegraph/sourcegraph/internal/trace/ot"
 )
 
 type Server struct {
 	host                 string
 	port                 int
-	bundleDir            string
+	store                storage.Store
+	cacheDir             string
 	databaseCache        *database.DatabaseCache
 	documentDataCache    *database.DocumentDataCache
 	resultChunkDataCache *database.ResultChunkDataCache
@@ -23,7 +25,8 @@ type Server struct {
 type ServerOpts struct {
 	Host                     string
 	Port                     int
-	BundleDir                string
+	Store                    storage.Store
+	CacheDir                 string
 	DatabaseCacheSize        int64
 	DocumentDataCacheSize    int64
 	ResultChunkDataCacheSize int64
@@ -48,7 +51,8 @@ func New(opts ServerOpts) (*Server, error) {
 	return &Server{
 		host:                 opts.Host,
 		port:                 opts.Port,
-		bundleDir:            opts.BundleDir,
+		store:                opts.Store,
+		cacheDir:             opts.CacheDir,
 		databaseCache:        databaseCache,
 		documentDataCache:    documentDataCache,
 		resultChunkDataCache: resultChunkDataCache,
@@ -6,10 +6,13 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"regexp"
 	"strconv"
+	"strings"
 
 	"github.com/gorilla/mux"
 	"github.com/inconshreveable/log15"
+	"github.com/sourcegraph/sourcegraph/cmd/precise-code-intel-bundle-manager/internal/database"
 )
 
 func getQuery(r *http.Request, name string) string {
@@ -29,6 +32,77 @@ func getQueryIntDefault(r *http.Request, name string, defaultValue int) int {
 	return value
 }
 
+// getSymbolQuery parses the symbol name pattern (query), the dump root (root), the path patterns
+// (include, which may be repeated, and exclude) of a symbol search. The patterns are regular
+// expressions.
+func getSymbolQuery(r *http.Request) (database.SymbolQuery, error) {
+	values := r.URL.Query()
+
+	compile := func(pattern string) (*regexp.Regexp, error) {
+		if pattern == "" {
+			return nil, nil
+		}
+		return regexp.Compile(pattern)
+	}
+
+	name, err := compile(values.Get("query"))
+	if err != nil {
+		return database.SymbolQuery{}, err
+	}
+	excludePattern, err := compile(values.Get("exclude"))
+	if err != nil {
+		return database.SymbolQuery{}, err
+	}
+
+	var includePatterns []*regexp.Regexp
+	for _, pattern := range values["include"] {
+		includePattern, err := compile(pattern)
+		if err != nil {
+			return database.SymbolQuery{}, err
+		}
+		if includePattern != nil {
+			includePatterns = append(includePatterns, includePattern)
+		}
+	}
+
+	return database.SymbolQuery{
+		Name:            name,
+		Root:            values.Get("root"),
+		IncludePatterns: includePatterns,
+		ExcludePattern:  excludePattern,
+	}, nil
+}
+
+// getQueryPositions parses a comma-separated list of `line:character` positions. An empty
+// value is an empty list.
+func getQueryPositions(r *http.Request, name string) ([]database.Position, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return nil, nil
+	}
+
+	var positions []database.Position
+	for _, part := range strings.Split(value, ",") {
+		i := strings.Index(part, ":")
+		if i < 0 {
+			return nil, fmt.Errorf("malformed position %q", part)
+		}
+
+		line, err := strconv.Atoi(part[:i])
+		if err != nil {
+			return nil, fmt.Errorf("malformed position %q", part)
+		}
+		character, err := strconv.Atoi(part[i+1:])
+		if err != nil {
+			return nil, fmt.Errorf("malformed position %q", part)
+		}
+
+		positions = append(positions, database.Position{Line: line, Character: character})
+	}
+
+	return positions, nil
+}
+
 // idFromRequest returns the database id from the request URL's path. This method
 // must only be called from routes containing the `id:[0-9]+` pattern, as the error
 // return from ParseInt is not checked.
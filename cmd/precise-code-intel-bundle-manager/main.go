@@ -9,6 +9,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/precise-code-intel-bundle-manager/internal/janitor"
 	"github.com/sourcegraph/sourcegraph/cmd/precise-code-intel-bundle-manager/internal/paths"
 	"github.com/sourcegraph/sourcegraph/cmd/precise-code-intel-bundle-manager/internal/server"
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/storage"
 	"github.com/sourcegraph/sourcegraph/internal/debugserver"
 	"github.com/sourcegraph/sourcegraph/internal/env"
 	"github.com/sourcegraph/sourcegraph/internal/sqliteutil"
@@ -36,6 +37,21 @@ func main() {
 		log.Fatalf("failed to prepare directories: %s", err)
 	}
 
+	store, err := storage.New(storage.Config{
+		Backend: rawStorageBackend,
+		Dir:     bundleDir,
+		S3: storage.S3Config{
+			Bucket:          rawS3Bucket,
+			Region:          rawS3Region,
+			Endpoint:        rawS3Endpoint,
+			AccessKeyID:     rawS3AccessKeyID,
+			SecretAccessKey: rawS3SecretAccessKey,
+		},
+	})
+	if err != nil {
+		log.Fatalf("failed to initialize storage: %s", err)
+	}
+
 	host := ""
 	if env.InsecureDev {
 		host = "127.0.0.1"
@@ -44,7 +60,8 @@ func main() {
 	serverInst, err := server.New(server.ServerOpts{
 		Host:                     host,
 		Port:                     3187,
-		BundleDir:                bundleDir,
+		Store:                    store,
+		CacheDir:                 paths.CacheDir(bundleDir),
 		DatabaseCacheSize:        int64(databaseCacheSize),
 		DocumentDataCacheSize:    int64(documentDataCacheSize),
 		ResultChunkDataCacheSize: int64(resultChunkDataCacheSize),
@@ -54,6 +71,7 @@ func main() {
 	}
 
 	janitorInst := janitor.NewJanitor(janitor.JanitorOpts{
+		Store:              store,
 		BundleDir:          bundleDir,
 		DesiredPercentFree: desiredPercentFree,
 		JanitorInterval:    janitorInterval,
@@ -2,14 +2,28 @@ package main
 
 import (
 	"log"
+	"strconv"
 	"time"
 
 	"github.com/sourcegraph/sourcegraph/internal/env"
 )
 
 var (
-	rawBundleManagerURL = env.Get("PRECISE_CODE_INTEL_BUNDLE_MANAGER_URL", "", "HTTP address for internal LSIF bundle manager server.")
-	rawPollInterval     = env.Get("PRECISE_CODE_INTEL_POLL_INTERVAL", "1s", "Interval between queries to the work queue.")
+	rawBundleManagerURL  = env.Get("PRECISE_CODE_INTEL_BUNDLE_MANAGER_URL", "", "HTTP address for internal LSIF bundle manager server.")
+	rawPollInterval      = env.Get("PRECISE_CODE_INTEL_POLL_INTERVAL", "1s", "Interval between queries to the work queue.")
+	rawStorageBackend    = env.Get("PRECISE_CODE_INTEL_STORAGE_BACKEND", "local", "Where the bundle manager stores uploads and converted bundles: local or s3. With s3, they are transferred to the bucket directly.")
+	rawS3Bucket          = env.Get("PRECISE_CODE_INTEL_S3_BUCKET", "", "The bucket holding uploads and converted bundles when the storage backend is s3.")
+	rawS3Region          = env.Get("PRECISE_CODE_INTEL_S3_REGION", "", "The region of the S3 bucket. Defaults to the region of the AWS environment.")
+	rawS3Endpoint        = env.Get("PRECISE_CODE_INTEL_S3_ENDPOINT", "", "The URL of an S3-compatible service (such as MinIO) to use instead of AWS.")
+	rawS3AccessKeyID     = env.Get("PRECISE_CODE_INTEL_S3_ACCESS_KEY_ID", "", "The access key of the S3 bucket. Defaults to the credentials of the AWS environment.")
+	rawS3SecretAccessKey = env.Get("PRECISE_CODE_INTEL_S3_SECRET_ACCESS_KEY", "", "The secret key of the S3 bucket.")
+	rawIndexerTimeout    = env.Get("PRECISE_CODE_INTEL_INDEXER_TIMEOUT", "30m", "Maximum duration of an auto-indexing run. Must be less than an hour.")
+	rawIndexerCPUs       = env.Get("PRECISE_CODE_INTEL_INDEXER_CPUS", "2", "Number of CPUs an auto-indexing container may use, as passed to docker run --cpus. Empty means no limit.")
+	rawIndexerMemory     = env.Get("PRECISE_CODE_INTEL_INDEXER_MEMORY", "4g", "Memory an auto-indexing container may use, as passed to docker run --memory. Empty means no limit.")
+	rawIndexerDumpMB     = env.Get("PRECISE_CODE_INTEL_INDEXER_MAX_DUMP_SIZE_MB", "10240", "Megabytes of LSIF output an auto-indexing container may write. Larger dumps fail the index. Zero means no limit.")
+	rawBackfillInterval  = env.Get("PRECISE_CODE_INTEL_BACKFILL_INTERVAL", "1m", "Interval between sweeps for package references uploaded before their identifiers were indexed.")
+	rawMemoryMB          = env.Get("PRECISE_CODE_INTEL_CORRELATION_MEMORY_LIMIT_MB", "512", "Megabytes of documents, ranges, results, and hover text held in memory while correlating an upload before they are moved to disk. Zero disables the limit.")
+	rawStrictValidation  = env.Get("PRECISE_CODE_INTEL_STRICT_VALIDATION", "false", "Reject LSIF uploads with validation warnings, such as ranges outside of any document, as well as errors.")
 )
 
 // mustGet returns the non-empty version of the given raw value fatally logs on failure.
@@ -30,3 +44,27 @@ func mustParseInterval(rawValue, name string) time.Duration {
 
 	return d
 }
+
+// mustParseBool returns the boolean version of the given raw value fatally logs on failure.
+func mustParseBool(rawValue, name string) bool {
+	b, err := strconv.ParseBool(rawValue)
+	if err != nil {
+		log.Fatalf("invalid bool %q for %s: %s", rawValue, name, err)
+	}
+
+	return b
+}
+
+// mustParseNonNegativeInt returns the non-negative integer version of the given raw value
+// fatally logs on failure.
+func mustParseNonNegativeInt(rawValue, name string) int64 {
+	i, err := strconv.ParseInt(rawValue, 10, 64)
+	if err != nil {
+		log.Fatalf("invalid int %q for %s: %s", rawValue, name, err)
+	}
+	if i < 0 {
+		log.Fatalf("invalid int %q for %s: must be non-negative", rawValue, name)
+	}
+
+	return i
+}
@@ -0,0 +1,93 @@
+package backfiller
+
+import (
+	"context"
+	"time"
+
+	"github.com/inconshreveable/log15"
+	bundles "github.com/sourcegraph/sourcegraph/internal/codeintel/bundles/client"
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/bundles/types"
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/db"
+)
+
+// Backfiller indexes the identifiers referenced by dumps that were processed before the
+// lsif_reference_identifiers table existed. The identifiers of these dumps are only known
+// to their bloom filters, so they are read back out of each dump's bundle instead.
+type Backfiller struct {
+	db                  db.DB
+	bundleManagerClient bundles.BundleManagerClient
+	interval            time.Duration
+	batchSize           int
+	lastDumpID          int // cursor into the unindexed dumps
+}
+
+type BackfillerOpts struct {
+	DB                  db.DB
+	BundleManagerClient bundles.BundleManagerClient
+	Interval            time.Duration
+	BatchSize           int
+}
+
+func New(opts BackfillerOpts) *Backfiller {
+	return &Backfiller{
+		db:                  opts.DB,
+		bundleManagerClient: opts.BundleManagerClient,
+		interval:            opts.Interval,
+		batchSize:           opts.BatchSize,
+	}
+}
+
+func (b *Backfiller) Start() error {
+	for {
+		if ok, err := b.backfill(context.Background()); err != nil {
+			return err
+		} else if !ok {
+			time.Sleep(b.interval)
+		}
+	}
+}
+
+// backfill indexes the reference identifiers of the next batch of unindexed dumps. If there
+// are no further dumps to index, this method rewinds to the first unindexed dump and returns
+// a false-valued flag. A dump that cannot be indexed is logged and skipped so that it does not
+// block the dumps after it; it remains unindexed and is retried on the next sweep. Only errors
+// reading the batch from the database are returned.
+func (b *Backfiller) backfill(ctx context.Context) (bool, error) {
+	dumpIDs, err := b.db.UnindexedReferenceDumpIDs(ctx, b.lastDumpID, b.batchSize)
+	if err != nil {
+		return false, err
+	}
+	if len(dumpIDs) == 0 {
+		b.lastDumpID = 0
+		return false, nil
+	}
+
+	for _, dumpID := range dumpIDs {
+		if err := b.index(ctx, dumpID); err != nil {
+			log15.Warn("Failed to index reference identifiers", "id", dumpID, "err", err)
+		}
+
+		b.lastDumpID = dumpID
+	}
+
+	return true, nil
+}
+
+// index reads the identifiers referenced by the given dump from its bundle and writes them
+// to the database.
+func (b *Backfiller) index(ctx context.Context, dumpID int) error {
+	referenceIdentifiers, err := b.bundleManagerClient.BundleClient(dumpID).ReferenceIdentifiers(ctx)
+	if err != nil {
+		return err
+	}
+
+	identifiers := make([]types.ReferenceIdentifier, 0, len(referenceIdentifiers))
+	for _, identifier := range referenceIdentifiers {
+		identifiers = append(identifiers, types.ReferenceIdentifier{
+			Scheme:     identifier.Scheme,
+			Identifier: identifier.Identifier,
+		})
+	}
+
+	return b.db.IndexReferenceIdentifiers(ctx, dumpID, identifiers)
+}
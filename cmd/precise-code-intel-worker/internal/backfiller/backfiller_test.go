@@ -0,0 +1,69 @@
+package backfiller
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	bundles "github.com/sourcegraph/sourcegraph/internal/codeintel/bundles/client"
+	bundlemocks "github.com/sourcegraph/sourcegraph/internal/codeintel/bundles/mocks"
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/bundles/types"
+	dbmocks "github.com/sourcegraph/sourcegraph/internal/codeintel/db/mocks"
+)
+
+func TestBackfill(t *testing.T) {
+	mockDB := dbmocks.NewMockDB()
+	mockDB.UnindexedReferenceDumpIDsFunc.PushReturn([]int{42, 43}, nil)
+	mockDB.UnindexedReferenceDumpIDsFunc.PushReturn(nil, nil)
+
+	bundleClients := map[int]*bundlemocks.MockBundleClient{
+		42: bundlemocks.NewMockBundleClient(),
+		43: bundlemocks.NewMockBundleClient(),
+	}
+	bundleClients[42].ReferenceIdentifiersFunc.SetDefaultReturn(nil, fmt.Errorf("uh-oh!"))
+	bundleClients[43].ReferenceIdentifiersFunc.SetDefaultReturn([]bundles.ReferenceIdentifier{
+		{Scheme: "gomod", Identifier: "github.com/foo/bar:Baz"},
+		{Scheme: "gomod", Identifier: "github.com/foo/bar:Quux"},
+	}, nil)
+
+	bundleManagerClient := bundlemocks.NewMockBundleManagerClient()
+	bundleManagerClient.BundleClientFunc.SetDefaultHook(func(bundleID int) bundles.BundleClient {
+		return bundleClients[bundleID]
+	})
+
+	backfiller := New(BackfillerOpts{DB: mockDB, BundleManagerClient: bundleManagerClient, BatchSize: 2})
+
+	if ok, err := backfiller.backfill(context.Background()); err != nil {
+		t.Fatalf("unexpected error backfilling: %s", err)
+	} else if !ok {
+		t.Errorf("expected dumps to be backfilled")
+	}
+
+	if ok, err := backfiller.backfill(context.Background()); err != nil {
+		t.Fatalf("unexpected error backfilling: %s", err)
+	} else if ok {
+		t.Errorf("expected no dumps to be backfilled")
+	}
+
+	if history := mockDB.UnindexedReferenceDumpIDsFunc.History(); len(history) != 2 {
+		t.Errorf("unexpected number of UnindexedReferenceDumpIDsFunc calls. want=%d have=%d", 2, len(history))
+	} else if history[0].Arg1 != 0 || history[1].Arg1 != 43 {
+		t.Errorf("unexpected cursors. want=%v have=%v", []int{0, 43}, []int{history[0].Arg1, history[1].Arg1})
+	}
+	if backfiller.lastDumpID != 0 {
+		t.Errorf("expected cursor to rewind. have=%d", backfiller.lastDumpID)
+	}
+
+	expectedIdentifiers := []types.ReferenceIdentifier{
+		{Scheme: "gomod", Identifier: "github.com/foo/bar:Baz"},
+		{Scheme: "gomod", Identifier: "github.com/foo/bar:Quux"},
+	}
+	if history := mockDB.IndexReferenceIdentifiersFunc.History(); len(history) != 1 {
+		t.Errorf("unexpected number of IndexReferenceIdentifiersFunc calls. want=%d have=%d", 1, len(history))
+	} else if history[0].Arg1 != 43 {
+		t.Errorf("unexpected dump id. want=%d have=%d", 43, history[0].Arg1)
+	} else if diff := cmp.Diff(expectedIdentifiers, history[0].Arg2); diff != "" {
+		t.Errorf("unexpected identifiers (-want +got):\n%s", diff)
+	}
+}
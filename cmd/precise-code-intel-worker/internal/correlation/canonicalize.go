@@ -8,9 +8,10 @@ import (
 )
 
 // canonicalization deduplicates data in the raw correlation state and collapses range,
-// result set, and moniker data that form chains via next edges.
-func canonicalize(state *State) {
-	fns := []func(state *State){
+// result set, and moniker data that form chains via next edges. Each step walks the maps
+// it updates once, as their values may have to be read back from disk.
+func canonicalize(state *State) error {
+	fns := []func(state *State) error{
 		canonicalizeDocuments,
 		canonicalizeReferenceResults,
 		canonicalizeResultSets,
@@ -18,59 +19,101 @@ func canonicalize(state *State) {
 	}
 
 	for _, fn := range fns {
-		fn(state)
+		if err := fn(state); err != nil {
+			return err
+		}
 	}
+
+	return nil
 }
 
 // canonicalizeDocuments determines if multiple documents are defined with the same URI. This can
 // happen in some indexers (such as lsif-tsc) that index dependent projects into the same index
 // as the target project. For each set of documents that share a path, we choose one document to
-// be the canonical representative and merge the contains, definition, and reference data into the
-// unique canonical document. This function guarantees that duplicate document IDs are removed from
-// the correlation state.
-func canonicalizeDocuments(state *State) {
+// be the canonical representative and merge the contains, definition, reference, and document
+// symbol data into the unique canonical document. This function guarantees that duplicate document
+// IDs are removed from the correlation state.
+func canonicalizeDocuments(state *State) error {
 	documentIDs := map[string][]string{}
-	for documentID, doc := range state.DocumentData {
+	if err := state.DocumentData.Each(func(documentID string, doc lsif.DocumentData) error {
 		documentIDs[doc.URI] = append(documentIDs[doc.URI], documentID)
+		return nil
+	}); err != nil {
+		return err
 	}
-	for _, v := range documentIDs {
-		sort.Strings(v)
+
+	// Choose canonical document alphabetically
+	canonicalIDs := map[string]string{}
+	for _, ids := range documentIDs {
+		sort.Strings(ids)
+
+		for _, documentID := range ids[1:] {
+			canonicalIDs[documentID] = ids[0]
+		}
+	}
+	if len(canonicalIDs) == 0 {
+		return nil
 	}
 
-	for documentID, doc := range state.DocumentData {
-		// Choose canonical document alphabetically
-		if canonicalID := documentIDs[doc.URI][0]; documentID != canonicalID {
-			for id := range state.DocumentData[documentID].Contains {
-				// Move ranges into the canonical document
-				state.DocumentData[canonicalID].Contains.Add(id)
-			}
+	for documentID, canonicalID := range canonicalIDs {
+		doc, _, err := state.DocumentData.Get(documentID)
+		if err != nil {
+			return err
+		}
+		canonicalDoc, _, err := state.DocumentData.Get(canonicalID)
+		if err != nil {
+			return err
+		}
 
-			// Move definition/reference data into the canonical document
-			canonicalizeDocumentsInDefinitionReferences(state, state.DefinitionData, documentID, canonicalID)
-			canonicalizeDocumentsInDefinitionReferences(state, state.ReferenceData, documentID, canonicalID)
+		// Move ranges into the canonical document
+		canonicalDoc.Contains.AddAll(doc.Contains)
+		if err := state.DocumentData.Set(canonicalID, canonicalDoc); err != nil {
+			return err
+		}
 
-			// Remove non-canonical document
-			delete(state.DocumentData, documentID)
+		// Move document symbols into the canonical document
+		if resultIDs, ok := state.DocumentSymbolResults[documentID]; ok {
+			state.DocumentSymbolResults.GetOrCreate(canonicalID).AddAll(resultIDs)
+			delete(state.DocumentSymbolResults, documentID)
 		}
+
+		// Remove non-canonical document
+		state.DocumentData.Delete(documentID)
 	}
+
+	// Move definition/reference data into the canonical documents
+	if err := canonicalizeDocumentsInDefinitionReferences(state.DefinitionData, canonicalIDs); err != nil {
+		return err
+	}
+	return canonicalizeDocumentsInDefinitionReferences(state.ReferenceData, canonicalIDs)
 }
 
-// canonicalizeDocumentsInDefinitionReferences moves definition or reference result data from the
-// given document to the given canonical document and removes all references to the non-canonical
-// document.
-func canonicalizeDocumentsInDefinitionReferences(state *State, definitionReferenceData map[string]datastructures.DefaultIDSetMap, documentID, canonicalID string) {
-	for _, documentRanges := range definitionReferenceData {
-		rangeIDs, ok := documentRanges[documentID]
-		if !ok {
-			continue
+// canonicalizeDocumentsInDefinitionReferences moves definition or reference result data from
+// each non-canonical document to its canonical document and removes all references to the
+// non-canonical documents.
+func canonicalizeDocumentsInDefinitionReferences(definitionReferenceData *ResultMap, canonicalIDs map[string]string) error {
+	return definitionReferenceData.Each(func(id string, documentRanges datastructures.DefaultIDSetMap) error {
+		changed := false
+		for documentID, rangeIDs := range documentRanges {
+			canonicalID, ok := canonicalIDs[documentID]
+			if !ok {
+				continue
+			}
+
+			// Move definition/reference data into the canonical document
+			documentRanges.GetOrCreate(canonicalID).AddAll(rangeIDs)
+
+			// Remove references to non-canonical document
+			delete(documentRanges, documentID)
+			changed = true
 		}
 
-		// Move definition/reference data into the canonical document
-		documentRanges.GetOrCreate(canonicalID).AddAll(rangeIDs)
+		if !changed {
+			return nil
+		}
 
-		// Remove references to non-canonical document
-		delete(documentRanges, documentID)
-	}
+		return definitionReferenceData.Set(id, documentRanges)
+	})
 }
 
 // canonicalizeReferenceResults determines which reference results are linked together. For each
@@ -78,7 +121,7 @@ func canonicalizeDocumentsInDefinitionReferences(state *State, definitionReferen
 // and merge the data into the unique canonical result set. All non-canonical results are removed from
 // the correlation state and references to non-canonical results are updated to refer to the canonical
 // choice.
-func canonicalizeReferenceResults(state *State) {
+func canonicalizeReferenceResults(state *State) error {
 	// Maintain a map from a reference result to its canonical identifier
 	canonicalIDs := map[string]string{}
 
@@ -91,60 +134,73 @@ func canonicalizeReferenceResults(state *State) {
 		// Find all reachable items in this set
 		linkedIDs := state.LinkedReferenceResults.ExtractSet(referenceResultID)
 		canonicalID, _ := linkedIDs.Choose()
-		canonicalReferenceResult := state.ReferenceData[canonicalID]
+		canonicalReferenceResult, _, err := state.ReferenceData.Get(canonicalID)
+		if err != nil {
+			return err
+		}
 
 		for linkedID := range linkedIDs {
 			// Mark canonical choice
 			canonicalIDs[linkedID] = canonicalID
 
 			if linkedID != canonicalID {
-				for documentID, rangeIDs := range state.ReferenceData[linkedID] {
+				linkedReferenceResult, _, err := state.ReferenceData.Get(linkedID)
+				if err != nil {
+					return err
+				}
+
+				for documentID, rangeIDs := range linkedReferenceResult {
 					// Move range data into the canonical document
 					canonicalReferenceResult.GetOrCreate(documentID).AddAll(rangeIDs)
 				}
+
+				// Remove non-canonical reference result
+				state.ReferenceData.Delete(linkedID)
 			}
 		}
-	}
 
-	for id, item := range state.RangeData {
-		if canonicalID, ok := canonicalIDs[item.ReferenceResultID]; ok {
-			// Update reference result identifier to canonical choice
-			state.RangeData[id] = item.SetReferenceResultID(canonicalID)
+		if err := state.ReferenceData.Set(canonicalID, canonicalReferenceResult); err != nil {
+			return err
 		}
 	}
 
-	for id, item := range state.ResultSetData {
-		if canonicalID, ok := canonicalIDs[item.ReferenceResultID]; ok {
-			// Update reference result identifier to canonical choice
-			state.ResultSetData[id] = item.SetReferenceResultID(canonicalID)
-		}
+	if len(canonicalIDs) == 0 {
+		return nil
 	}
 
-	// Invert the map to get a set of canonical identifiers
-	inverseMap := map[string]struct{}{}
-	for _, canonicalID := range canonicalIDs {
-		inverseMap[canonicalID] = struct{}{}
+	if err := state.RangeData.Each(func(id string, item lsif.RangeData) error {
+		if canonicalID, ok := canonicalIDs[item.ReferenceResultID]; ok && canonicalID != item.ReferenceResultID {
+			// Update reference result identifier to canonical choice
+			return state.RangeData.Set(id, item.SetReferenceResultID(canonicalID))
+		}
+		return nil
+	}); err != nil {
+		return err
 	}
 
-	for referenceResultID := range canonicalIDs {
-		if _, ok := inverseMap[referenceResultID]; !ok {
-			// Remove non-canonical reference result
-			delete(state.ReferenceData, referenceResultID)
+	return state.ResultSetData.Each(func(id string, item lsif.ResultSetData) error {
+		if canonicalID, ok := canonicalIDs[item.ReferenceResultID]; ok && canonicalID != item.ReferenceResultID {
+			// Update reference result identifier to canonical choice
+			return state.ResultSetData.Set(id, item.SetReferenceResultID(canonicalID))
 		}
-	}
+		return nil
+	})
 }
 
 // canonicalizeResultSets runs canonicalizeResultSet on each result set in the correlation state.
 // This will collapse result sets down recursively so that if a result set's next element also has
 // a next element, then both sets merge down into the original result set.
-func canonicalizeResultSets(state *State) {
-	for resultSetID, resultSetData := range state.ResultSetData {
-		canonicalizeResultSetData(state, resultSetID, resultSetData)
+func canonicalizeResultSets(state *State) error {
+	if err := state.ResultSetData.Each(func(resultSetID string, resultSetData lsif.ResultSetData) error {
+		_, err := canonicalizeResultSetData(state, resultSetID, resultSetData)
+		return err
+	}); err != nil {
+		return err
 	}
 
-	for resultSetID, resultSetData := range state.ResultSetData {
-		state.ResultSetData[resultSetID] = resultSetData.SetMonikerIDs(gatherMonikers(state, resultSetData.MonikerIDs))
-	}
+	return state.ResultSetData.Each(func(resultSetID string, resultSetData lsif.ResultSetData) error {
+		return state.ResultSetData.Set(resultSetID, resultSetData.SetMonikerIDs(gatherMonikers(state, resultSetData.MonikerIDs)))
+	})
 }
 
 // canonicalizeResultSets "merges down" the definition, reference, and hover result identifiers
@@ -153,34 +209,46 @@ func canonicalizeResultSets(state *State) {
 //
 // This method is assumed to be invoked only after canonicalizeResultSets, otherwise the next element
 // of a range may not have all of the necessary data to perform this canonicalization step.
-func canonicalizeRanges(state *State) {
-	for rangeID, rangeData := range state.RangeData {
-		if _, nextItem, ok := next(state, rangeID); ok {
+func canonicalizeRanges(state *State) error {
+	return state.RangeData.Each(func(rangeID string, rangeData lsif.RangeData) error {
+		_, nextItem, ok, err := next(state, rangeID)
+		if err != nil {
+			return err
+		}
+		if ok {
 			// Merge range and next element
 			rangeData = mergeNextRangeData(rangeData, nextItem)
 			// Delete next data to prevent us from re-performing this step
 			delete(state.NextData, rangeID)
 		}
 
-		state.RangeData[rangeID] = rangeData.SetMonikerIDs(gatherMonikers(state, rangeData.MonikerIDs))
-	}
+		return state.RangeData.Set(rangeID, rangeData.SetMonikerIDs(gatherMonikers(state, rangeData.MonikerIDs)))
+	})
 }
 
 // canonicalizeResultSets "merges down" the definition, reference, and hover result identifiers
 // from the element's "next" result set if such an element exists and the identifier is not
 // already defined. This also merges down the moniker ids by unioning the sets.
-func canonicalizeResultSetData(state *State, id string, item lsif.ResultSetData) lsif.ResultSetData {
-	if nextID, nextItem, ok := next(state, id); ok {
-		// Recursively canonicalize the next element
-		nextItem = canonicalizeResultSetData(state, nextID, nextItem)
-		// Merge result set and canonicalized next element
-		item = mergeNextResultSetData(item, nextItem)
-		// Delete next data to prevent us from re-performing this step
-		delete(state.NextData, id)
+func canonicalizeResultSetData(state *State, id string, item lsif.ResultSetData) (lsif.ResultSetData, error) {
+	nextID, nextItem, ok, err := next(state, id)
+	if err != nil {
+		return lsif.ResultSetData{}, err
+	}
+	if !ok {
+		// Nothing to merge down
+		return item, nil
 	}
 
-	state.ResultSetData[id] = item
-	return item
+	// Recursively canonicalize the next element
+	if nextItem, err = canonicalizeResultSetData(state, nextID, nextItem); err != nil {
+		return lsif.ResultSetData{}, err
+	}
+	// Merge result set and canonicalized next element
+	item = mergeNextResultSetData(item, nextItem)
+	// Delete next data to prevent us from re-performing this step
+	delete(state.NextData, id)
+
+	return item, state.ResultSetData.Set(id, item)
 }
 
 // mergeNextResultSetData merges the definition, reference, and hover result identifiers from
@@ -237,11 +305,12 @@ func gatherMonikers(state *State, source datastructures.IDSet) datastructures.ID
 }
 
 // next returns the "next" identifier and result set element for the given identifier, if one exists.
-func next(state *State, id string) (string, lsif.ResultSetData, bool) {
+func next(state *State, id string) (string, lsif.ResultSetData, bool, error) {
 	nextID, ok := state.NextData[id]
 	if !ok {
-		return "", lsif.ResultSetData{}, false
+		return "", lsif.ResultSetData{}, false, nil
 	}
 
-	return nextID, state.ResultSetData[nextID], true
+	nextItem, _, err := state.ResultSetData.Get(nextID)
+	return nextID, nextItem, true, err
 }
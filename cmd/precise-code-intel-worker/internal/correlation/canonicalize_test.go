@@ -10,37 +10,39 @@ import (
 
 func TestCanonicalizeDocuments(t *testing.T) {
 	state := &State{
-		DocumentData: map[string]lsif.DocumentData{
+		DocumentData: makeDocumentMap(t, map[string]lsif.DocumentData{
 			"d01": {URI: "main.go", Contains: datastructures.IDSet{"r01": {}}},
 			"d02": {URI: "foo.go", Contains: datastructures.IDSet{"r02": {}}},
 			"d03": {URI: "bar.go", Contains: datastructures.IDSet{"r03": {}}},
 			"d04": {URI: "main.go", Contains: datastructures.IDSet{"r04": {}}},
-		},
-		DefinitionData: map[string]datastructures.DefaultIDSetMap{
+		}),
+		DefinitionData: makeResultMap(t, map[string]datastructures.DefaultIDSetMap{
 			"x01": {"d01": datastructures.IDSet{"r05": {}}},
 			"x02": {"d02": datastructures.IDSet{"r06": {}}, "d04": datastructures.IDSet{"r07": {}}},
-		},
-		ReferenceData: map[string]datastructures.DefaultIDSetMap{
+		}),
+		ReferenceData: makeResultMap(t, map[string]datastructures.DefaultIDSetMap{
 			"x03": {"d01": datastructures.IDSet{"r08": {}}},
 			"x04": {"d03": datastructures.IDSet{"r09": {}}, "d04": datastructures.IDSet{"r10": {}}},
-		},
+		}),
+	}
+	if err := canonicalizeDocuments(state); err != nil {
+		t.Fatalf("unexpected error canonicalizing state: %s", err)
 	}
-	canonicalizeDocuments(state)
 
 	expectedState := &State{
-		DocumentData: map[string]lsif.DocumentData{
+		DocumentData: makeDocumentMap(t, map[string]lsif.DocumentData{
 			"d01": {URI: "main.go", Contains: datastructures.IDSet{"r01": {}, "r04": {}}},
 			"d02": {URI: "foo.go", Contains: datastructures.IDSet{"r02": {}}},
 			"d03": {URI: "bar.go", Contains: datastructures.IDSet{"r03": {}}},
-		},
-		DefinitionData: map[string]datastructures.DefaultIDSetMap{
+		}),
+		DefinitionData: makeResultMap(t, map[string]datastructures.DefaultIDSetMap{
 			"x01": {"d01": datastructures.IDSet{"r05": {}}},
 			"x02": {"d02": datastructures.IDSet{"r06": {}}, "d01": datastructures.IDSet{"r07": {}}},
-		},
-		ReferenceData: map[string]datastructures.DefaultIDSetMap{
+		}),
+		ReferenceData: makeResultMap(t, map[string]datastructures.DefaultIDSetMap{
 			"x03": {"d01": datastructures.IDSet{"r08": {}}},
 			"x04": {"d03": datastructures.IDSet{"r09": {}}, "d01": datastructures.IDSet{"r10": {}}},
-		},
+		}),
 	}
 
 	if diff := cmp.Diff(expectedState, state); diff != "" {
@@ -53,38 +55,40 @@ func TestCanonicalizeReferenceResults(t *testing.T) {
 	linkedReferenceResults.Union("x01", "x03")
 
 	state := &State{
-		RangeData: map[string]lsif.RangeData{
+		RangeData: makeRangeMap(t, map[string]lsif.RangeData{
 			"r01": {ReferenceResultID: "x02"},
 			"r02": {ReferenceResultID: "x03"},
-		},
-		ResultSetData: map[string]lsif.ResultSetData{
+		}),
+		ResultSetData: makeResultSetMap(t, map[string]lsif.ResultSetData{
 			"s03": {ReferenceResultID: "x03"},
 			"s04": {ReferenceResultID: "x04"},
-		},
-		ReferenceData: map[string]datastructures.DefaultIDSetMap{
+		}),
+		ReferenceData: makeResultMap(t, map[string]datastructures.DefaultIDSetMap{
 			"x01": {"d01": {"r05": {}}},
 			"x02": {"d02": {"r06": {}}, "d04": {"r07": {}}},
 			"x03": {"d01": {"r08": {}}, "d03": {"r09": {}}},
 			"x04": {"d04": {"r10": {}}},
-		},
+		}),
 		LinkedReferenceResults: linkedReferenceResults,
 	}
-	canonicalizeReferenceResults(state)
+	if err := canonicalizeReferenceResults(state); err != nil {
+		t.Fatalf("unexpected error canonicalizing state: %s", err)
+	}
 
 	expectedState := &State{
-		RangeData: map[string]lsif.RangeData{
+		RangeData: makeRangeMap(t, map[string]lsif.RangeData{
 			"r01": {ReferenceResultID: "x02"},
 			"r02": {ReferenceResultID: "x01"},
-		},
-		ResultSetData: map[string]lsif.ResultSetData{
+		}),
+		ResultSetData: makeResultSetMap(t, map[string]lsif.ResultSetData{
 			"s03": {ReferenceResultID: "x01"},
 			"s04": {ReferenceResultID: "x04"},
-		},
-		ReferenceData: map[string]datastructures.DefaultIDSetMap{
+		}),
+		ReferenceData: makeResultMap(t, map[string]datastructures.DefaultIDSetMap{
 			"x01": {"d01": {"r05": {}, "r08": {}}, "d03": {"r09": {}}},
 			"x02": {"d02": {"r06": {}}, "d04": {"r07": {}}},
 			"x04": {"d04": {"r10": {}}},
-		},
+		}),
 
 		LinkedReferenceResults: linkedReferenceResults,
 	}
@@ -99,7 +103,7 @@ func TestCanonicalizeResultSets(t *testing.T) {
 	linkedMonikers.Union("m02", "m05")
 
 	state := &State{
-		ResultSetData: map[string]lsif.ResultSetData{
+		ResultSetData: makeResultSetMap(t, map[string]lsif.ResultSetData{
 			"s01": {
 				DefinitionResultID: "",
 				ReferenceResultID:  "",
@@ -130,7 +134,7 @@ func TestCanonicalizeResultSets(t *testing.T) {
 				HoverResultID:      "x08",
 				MonikerIDs:         datastructures.IDSet{"m05": {}},
 			},
-		},
+		}),
 		NextData: map[string]string{
 			"s01": "s04",
 			"s03": "s05",
@@ -138,10 +142,12 @@ func TestCanonicalizeResultSets(t *testing.T) {
 		},
 		LinkedMonikers: linkedMonikers,
 	}
-	canonicalizeResultSets(state)
+	if err := canonicalizeResultSets(state); err != nil {
+		t.Fatalf("unexpected error canonicalizing state: %s", err)
+	}
 
 	expectedState := &State{
-		ResultSetData: map[string]lsif.ResultSetData{
+		ResultSetData: makeResultSetMap(t, map[string]lsif.ResultSetData{
 			"s01": {
 				DefinitionResultID: "x06",
 				ReferenceResultID:  "x07",
@@ -172,7 +178,7 @@ func TestCanonicalizeResultSets(t *testing.T) {
 				HoverResultID:      "x08",
 				MonikerIDs:         datastructures.IDSet{"m02": {}, "m05": {}},
 			},
-		},
+		}),
 		NextData:       map[string]string{},
 		LinkedMonikers: linkedMonikers,
 	}
@@ -187,7 +193,7 @@ func TestCanonicalizeRanges(t *testing.T) {
 	linkedMonikers.Union("m02", "m05")
 
 	state := &State{
-		RangeData: map[string]lsif.RangeData{
+		RangeData: makeRangeMap(t, map[string]lsif.RangeData{
 			"r01": {
 				DefinitionResultID: "",
 				ReferenceResultID:  "",
@@ -206,8 +212,8 @@ func TestCanonicalizeRanges(t *testing.T) {
 				HoverResultID:      "",
 				MonikerIDs:         datastructures.IDSet{"m03": {}},
 			},
-		},
-		ResultSetData: map[string]lsif.ResultSetData{
+		}),
+		ResultSetData: makeResultSetMap(t, map[string]lsif.ResultSetData{
 			"s01": {
 				DefinitionResultID: "x06",
 				ReferenceResultID:  "x07",
@@ -220,17 +226,19 @@ func TestCanonicalizeRanges(t *testing.T) {
 				HoverResultID:      "x08",
 				MonikerIDs:         datastructures.IDSet{"m05": {}},
 			},
-		},
+		}),
 		NextData: map[string]string{
 			"r01": "s01",
 			"r03": "s02",
 		},
 		LinkedMonikers: linkedMonikers,
 	}
-	canonicalizeRanges(state)
+	if err := canonicalizeRanges(state); err != nil {
+		t.Fatalf("unexpected error canonicalizing state: %s", err)
+	}
 
 	expectedState := &State{
-		RangeData: map[string]lsif.RangeData{
+		RangeData: makeRangeMap(t, map[string]lsif.RangeData{
 			"r01": {
 				DefinitionResultID: "x06",
 				ReferenceResultID:  "x07",
@@ -249,8 +257,8 @@ func TestCanonicalizeRanges(t *testing.T) {
 				HoverResultID:      "x08",
 				MonikerIDs:         datastructures.IDSet{"m02": {}, "m03": {}, "m05": {}},
 			},
-		},
-		ResultSetData: map[string]lsif.ResultSetData{
+		}),
+		ResultSetData: makeResultSetMap(t, map[string]lsif.ResultSetData{
 			"s01": {
 				DefinitionResultID: "x06",
 				ReferenceResultID:  "x07",
@@ -263,7 +271,7 @@ func TestCanonicalizeRanges(t *testing.T) {
 				HoverResultID:      "x08",
 				MonikerIDs:         datastructures.IDSet{"m05": {}},
 			},
-		},
+		}),
 		NextData:       map[string]string{},
 		LinkedMonikers: linkedMonikers,
 	}
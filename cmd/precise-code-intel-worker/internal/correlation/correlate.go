@@ -7,15 +7,21 @@ import (
 	"io"
 	"os"
 
+	"github.com/hashicorp/go-multierror"
 	"github.com/inconshreveable/log15"
 	"github.com/sourcegraph/sourcegraph/cmd/precise-code-intel-worker/internal/correlation/datastructures"
 	"github.com/sourcegraph/sourcegraph/cmd/precise-code-intel-worker/internal/correlation/lsif"
+	"github.com/sourcegraph/sourcegraph/cmd/precise-code-intel-worker/internal/correlation/spill"
 	"github.com/sourcegraph/sourcegraph/cmd/precise-code-intel-worker/internal/existence"
 )
 
 // Correlate reads the given gzipped upload file and returns a correlation state object with the
-// same data canonicalized and pruned for storage.
-func Correlate(filename string, dumpID int, root string, getChildren existence.GetChildrenFunc) (*GroupedBundleData, error) {
+// same data canonicalized and pruned for storage. Data that exceeds the given memory budget is
+// moved to disk. The returned value must be closed to remove any files created in this way.
+//
+// Problems found in the upload are returned as diagnostics. An upload with errors, or with warnings
+// in strict mode, is rejected with an ErrInvalidDump error.
+func Correlate(filename string, dumpID int, root string, getChildren existence.GetChildrenFunc, budget *spill.Budget, strict bool) (_ *GroupedBundleData, err error) {
 	f, err := os.Open(filename)
 	if err != nil {
 		return nil, err
@@ -28,13 +34,22 @@ func Correlate(filename string, dumpID int, root string, getChildren existence.G
 	}
 
 	// Read raw upload stream and return a correlation state
-	state, err := correlateFromReader(gzipReader, root)
+	state, err := correlateFromReader(gzipReader, root, budget, strict)
 	if err != nil {
 		return nil, err
 	}
+	defer func() {
+		if err != nil {
+			if closeErr := state.Close(); closeErr != nil {
+				err = multierror.Append(err, closeErr)
+			}
+		}
+	}()
 
 	// Remove duplicate elements, collapse linked elements
-	canonicalize(state)
+	if err := canonicalize(state); err != nil {
+		return nil, err
+	}
 
 	// Remove elements we don't need to store
 	if err := prune(state, root, getChildren); err != nil {
@@ -50,20 +65,33 @@ func Correlate(filename string, dumpID int, root string, getChildren existence.G
 }
 
 // correlateFromReader reads the given upload stream and returns a correlation state object.
-// The data in the correlation state is neither canonicalized nor pruned.
-func correlateFromReader(r io.Reader, root string) (*State, error) {
-	wrappedState := newWrappedState(root)
+// The data in the correlation state is neither canonicalized nor pruned. Elements that cannot
+// be correlated are skipped and recorded as errors so that all problems of the upload are
+// reported at once.
+func correlateFromReader(r io.Reader, root string, budget *spill.Budget, strict bool) (_ *State, err error) {
+	wrappedState := newWrappedState(root, budget, strict)
+	defer func() {
+		if err != nil {
+			if closeErr := wrappedState.Close(); closeErr != nil {
+				err = multierror.Append(err, closeErr)
+			}
+		}
+	}()
+
 	scanner := bufio.NewScanner(r)
 	scanner.Split(bufio.ScanLines)
 
 	for scanner.Scan() {
+		wrappedState.line++
+
 		element, err := lsif.UnmarshalElement(scanner.Bytes())
 		if err != nil {
-			return nil, err
+			wrappedState.validator.errorf(DiagnosticMalformedElement, "", wrappedState.line, "%s", err)
+			continue
 		}
 
 		if err := correlateElement(wrappedState, element); err != nil {
-			return nil, err
+			wrappedState.validator.elementError(element, wrappedState.line, err)
 		}
 	}
 
@@ -71,10 +99,14 @@ func correlateFromReader(r io.Reader, root string) (*State, error) {
 		return nil, err
 	}
 
-	if wrappedState.LSIFVersion == "" {
-		return nil, ErrMissingMetaData
+	if err := wrappedState.validator.validateState(wrappedState.State); err != nil {
+		return nil, err
+	}
+	if err := wrappedState.validator.err(); err != nil {
+		return nil, err
 	}
 
+	wrappedState.Diagnostics = wrappedState.validator.diagnostics
 	return wrappedState.State, nil
 }
 
@@ -82,13 +114,16 @@ type wrappedState struct {
 	*State
 	dumpRoot            string
 	unsupportedVertexes datastructures.IDSet
+	validator           *validator
+	line                int // the line of the element being correlated
 }
 
-func newWrappedState(dumpRoot string) *wrappedState {
+func newWrappedState(dumpRoot string, budget *spill.Budget, strict bool) *wrappedState {
 	return &wrappedState{
-		State:               newState(),
+		State:               newState(budget),
 		dumpRoot:            dumpRoot,
 		unsupportedVertexes: datastructures.IDSet{},
+		validator:           newValidator(strict),
 	}
 }
 
@@ -105,19 +140,24 @@ func correlateElement(state *wrappedState, element lsif.Element) error {
 }
 
 var vertexHandlers = map[string]func(state *wrappedState, element lsif.Element) error{
-	"metaData":           correlateMetaData,
-	"document":           correlateDocument,
-	"range":              correlateRange,
-	"resultSet":          correlateResultSet,
-	"definitionResult":   correlateDefinitionResult,
-	"referenceResult":    correlateReferenceResult,
-	"hoverResult":        correlateHoverResult,
-	"moniker":            correlateMoniker,
-	"packageInformation": correlatePackageInformation,
+	"metaData":             correlateMetaData,
+	"document":             correlateDocument,
+	"range":                correlateRange,
+	"resultSet":            correlateResultSet,
+	"definitionResult":     correlateDefinitionResult,
+	"referenceResult":      correlateReferenceResult,
+	"hoverResult":          correlateHoverResult,
+	"moniker":              correlateMoniker,
+	"packageInformation":   correlatePackageInformation,
+	"documentSymbolResult": correlateDocumentSymbolResult,
 }
 
 // correlateElement maps a single vertex element into the correlation state.
 func correlateVertex(state *wrappedState, element lsif.Element) error {
+	if state.hasVertex(element.ID) {
+		state.validator.warnf(DiagnosticDuplicateID, element.ID, state.line, "vertex %s reuses the identifier of a previous vertex", element.Label)
+	}
+
 	handler, ok := vertexHandlers[element.Label]
 	if !ok {
 		// Can safely skip, but need to mark this in case we have an edge
@@ -133,15 +173,16 @@ func correlateVertex(state *wrappedState, element lsif.Element) error {
 }
 
 var edgeHandlers = map[string]func(state *wrappedState, id string, edge lsif.Edge) error{
-	"contains":                correlateContainsEdge,
-	"next":                    correlateNextEdge,
-	"item":                    correlateItemEdge,
-	"textDocument/definition": correlateTextDocumentDefinitionEdge,
-	"textDocument/references": correlateTextDocumentReferencesEdge,
-	"textDocument/hover":      correlateTextDocumentHoverEdge,
-	"moniker":                 correlateMonikerEdge,
-	"nextMoniker":             correlateNextMonikerEdge,
-	"packageInformation":      correlatePackageInformationEdge,
+	"contains":                    correlateContainsEdge,
+	"next":                        correlateNextEdge,
+	"item":                        correlateItemEdge,
+	"textDocument/definition":     correlateTextDocumentDefinitionEdge,
+	"textDocument/references":     correlateTextDocumentReferencesEdge,
+	"textDocument/hover":          correlateTextDocumentHoverEdge,
+	"moniker":                     correlateMonikerEdge,
+	"nextMoniker":                 correlateNextMonikerEdge,
+	"packageInformation":          correlatePackageInformationEdge,
+	"textDocument/documentSymbol": correlateTextDocumentDocumentSymbolEdge,
 }
 
 // correlateElement maps a single edge element into the correlation state.
@@ -160,6 +201,27 @@ func correlateEdge(state *wrappedState, element lsif.Element) error {
 	return handler(state, element.ID, edge)
 }
 
+// hasVertex determines if a vertex with the given identifier has already been correlated.
+func (state *wrappedState) hasVertex(id string) bool {
+	if state.DocumentData.Has(id) || state.RangeData.Has(id) || state.ResultSetData.Has(id) {
+		return true
+	}
+	if state.DefinitionData.Has(id) || state.ReferenceData.Has(id) {
+		return true
+	}
+	if _, ok := state.MonikerData[id]; ok {
+		return true
+	}
+	if _, ok := state.PackageInformationData[id]; ok {
+		return true
+	}
+	if _, ok := state.DocumentSymbolData[id]; ok {
+		return true
+	}
+
+	return state.HoverData.Has(id) || state.unsupportedVertexes.Contains(id)
+}
+
 func correlateMetaData(state *wrappedState, element lsif.Element) error {
 	payload, err := lsif.UnmarshalMetaData(element, state.dumpRoot)
 	state.LSIFVersion = payload.Version
@@ -173,36 +235,56 @@ func correlateDocument(state *wrappedState, element lsif.Element) error {
 	}
 
 	payload, err := lsif.UnmarshalDocumentData(element, state.ProjectRoot)
-	state.DocumentData[element.ID] = payload
+	if setErr := state.DocumentData.Set(element.ID, payload); setErr != nil {
+		return setErr
+	}
 	return err
 }
 
 func correlateRange(state *wrappedState, element lsif.Element) error {
 	payload, err := lsif.UnmarshalRangeData(element)
-	state.RangeData[element.ID] = payload
-	return err
+	if err != nil {
+		return err
+	}
+	if err := state.RangeData.Set(element.ID, payload); err != nil {
+		return err
+	}
+	state.validator.validateRange(element.ID, state.line, payload)
+
+	tag, ok, err := lsif.UnmarshalRangeTag(element)
+	if err != nil {
+		return err
+	}
+	if ok {
+		state.RangeTagData[element.ID] = tag
+	}
+
+	return nil
 }
 
 func correlateResultSet(state *wrappedState, element lsif.Element) error {
 	payload, err := lsif.UnmarshalResultSetData(element)
-	state.ResultSetData[element.ID] = payload
+	if setErr := state.ResultSetData.Set(element.ID, payload); setErr != nil {
+		return setErr
+	}
 	return err
 }
 
 func correlateDefinitionResult(state *wrappedState, element lsif.Element) error {
-	state.DefinitionData[element.ID] = map[string]datastructures.IDSet{}
-	return nil
+	return state.DefinitionData.Set(element.ID, datastructures.DefaultIDSetMap{})
 }
 
 func correlateReferenceResult(state *wrappedState, element lsif.Element) error {
-	state.ReferenceData[element.ID] = map[string]datastructures.IDSet{}
-	return nil
+	return state.ReferenceData.Set(element.ID, datastructures.DefaultIDSetMap{})
 }
 
 func correlateHoverResult(state *wrappedState, element lsif.Element) error {
 	payload, err := lsif.UnmarshalHoverData(element)
-	state.HoverData[element.ID] = payload
-	return err
+	if err != nil {
+		return err
+	}
+
+	return state.HoverData.Set(element.ID, payload)
 }
 
 func correlateMoniker(state *wrappedState, element lsif.Element) error {
@@ -217,30 +299,37 @@ func correlatePackageInformation(state *wrappedState, element lsif.Element) erro
 	return err
 }
 
+func correlateDocumentSymbolResult(state *wrappedState, element lsif.Element) error {
+	payload, err := lsif.UnmarshalDocumentSymbolData(element)
+	state.DocumentSymbolData[element.ID] = payload
+	return err
+}
+
 func correlateContainsEdge(state *wrappedState, id string, edge lsif.Edge) error {
-	document, ok := state.DocumentData[edge.OutV]
+	document, ok, err := state.DocumentData.Get(edge.OutV)
+	if err != nil {
+		return err
+	}
 	if !ok {
 		// Do not track this relation for project vertices
 		return nil
 	}
 
 	for _, inV := range edge.InVs {
-		if _, ok := state.RangeData[inV]; !ok {
+		if !state.RangeData.Has(inV) {
 			return malformedDump(id, edge.InV, "range")
 		}
 		document.Contains.Add(inV)
 	}
-	return nil
+	return state.DocumentData.Set(edge.OutV, document)
 }
 
 func correlateNextEdge(state *wrappedState, id string, edge lsif.Edge) error {
-	if _, ok := state.ResultSetData[edge.InV]; !ok {
+	if !state.ResultSetData.Has(edge.InV) {
 		return malformedDump(id, edge.InV, "resultSet")
 	}
 
-	if _, ok := state.RangeData[edge.OutV]; ok {
-		state.NextData[edge.OutV] = edge.InV
-	} else if _, ok := state.ResultSetData[edge.OutV]; ok {
+	if state.RangeData.Has(edge.OutV) || state.ResultSetData.Has(edge.OutV) {
 		state.NextData[edge.OutV] = edge.InV
 	} else {
 		return malformedDump(id, edge.OutV, "range", "resultSet")
@@ -249,9 +338,13 @@ func correlateNextEdge(state *wrappedState, id string, edge lsif.Edge) error {
 }
 
 func correlateItemEdge(state *wrappedState, id string, edge lsif.Edge) error {
-	if documentMap, ok := state.DefinitionData[edge.OutV]; ok {
+	documentMap, ok, err := state.DefinitionData.Get(edge.OutV)
+	if err != nil {
+		return err
+	}
+	if ok {
 		for _, inV := range edge.InVs {
-			if _, ok := state.RangeData[inV]; !ok {
+			if !state.RangeData.Has(inV) {
 				return malformedDump(id, edge.InV, "range")
 			}
 
@@ -259,16 +352,20 @@ func correlateItemEdge(state *wrappedState, id string, edge lsif.Edge) error {
 			documentMap.GetOrCreate(edge.Document).Add(inV)
 		}
 
-		return nil
+		return state.DefinitionData.Set(edge.OutV, documentMap)
 	}
 
-	if documentMap, ok := state.ReferenceData[edge.OutV]; ok {
+	documentMap, ok, err = state.ReferenceData.Get(edge.OutV)
+	if err != nil {
+		return err
+	}
+	if ok {
 		for _, inV := range edge.InVs {
-			if _, ok := state.ReferenceData[inV]; ok {
+			if state.ReferenceData.Has(inV) {
 				// Link reference data identifiers together
 				state.LinkedReferenceResults.Union(edge.OutV, inV)
 			} else {
-				if _, ok = state.RangeData[inV]; !ok {
+				if !state.RangeData.Has(inV) {
 					return malformedDump(id, edge.InV, "range")
 				}
 
@@ -277,7 +374,7 @@ func correlateItemEdge(state *wrappedState, id string, edge lsif.Edge) error {
 			}
 		}
 
-		return nil
+		return state.ReferenceData.Set(edge.OutV, documentMap)
 	}
 
 	if !state.unsupportedVertexes.Contains(edge.OutV) {
@@ -289,48 +386,36 @@ func correlateItemEdge(state *wrappedState, id string, edge lsif.Edge) error {
 }
 
 func correlateTextDocumentDefinitionEdge(state *wrappedState, id string, edge lsif.Edge) error {
-	if _, ok := state.DefinitionData[edge.InV]; !ok {
+	if !state.DefinitionData.Has(edge.InV) {
 		return malformedDump(id, edge.InV, "definitionResult")
 	}
 
-	if source, ok := state.RangeData[edge.OutV]; ok {
-		state.RangeData[edge.OutV] = source.SetDefinitionResultID(edge.InV)
-	} else if source, ok := state.ResultSetData[edge.OutV]; ok {
-		state.ResultSetData[edge.OutV] = source.SetDefinitionResultID(edge.InV)
-	} else {
-		return malformedDump(id, edge.OutV, "range", "resultSet")
-	}
-	return nil
+	return updateRangeOrResultSet(state, id, edge.OutV,
+		func(r lsif.RangeData) lsif.RangeData { return r.SetDefinitionResultID(edge.InV) },
+		func(r lsif.ResultSetData) lsif.ResultSetData { return r.SetDefinitionResultID(edge.InV) },
+	)
 }
 
 func correlateTextDocumentReferencesEdge(state *wrappedState, id string, edge lsif.Edge) error {
-	if _, ok := state.ReferenceData[edge.InV]; !ok {
+	if !state.ReferenceData.Has(edge.InV) {
 		return malformedDump(id, edge.InV, "referenceResult")
 	}
 
-	if source, ok := state.RangeData[edge.OutV]; ok {
-		state.RangeData[edge.OutV] = source.SetReferenceResultID(edge.InV)
-	} else if source, ok := state.ResultSetData[edge.OutV]; ok {
-		state.ResultSetData[edge.OutV] = source.SetReferenceResultID(edge.InV)
-	} else {
-		return malformedDump(id, edge.OutV, "range", "resultSet")
-	}
-	return nil
+	return updateRangeOrResultSet(state, id, edge.OutV,
+		func(r lsif.RangeData) lsif.RangeData { return r.SetReferenceResultID(edge.InV) },
+		func(r lsif.ResultSetData) lsif.ResultSetData { return r.SetReferenceResultID(edge.InV) },
+	)
 }
 
 func correlateTextDocumentHoverEdge(state *wrappedState, id string, edge lsif.Edge) error {
-	if _, ok := state.HoverData[edge.InV]; !ok {
+	if !state.HoverData.Has(edge.InV) {
 		return malformedDump(id, edge.InV, "hoverResult")
 	}
 
-	if source, ok := state.RangeData[edge.OutV]; ok {
-		state.RangeData[edge.OutV] = source.SetHoverResultID(edge.InV)
-	} else if source, ok := state.ResultSetData[edge.OutV]; ok {
-		state.ResultSetData[edge.OutV] = source.SetHoverResultID(edge.InV)
-	} else {
-		return malformedDump(id, edge.OutV, "range", "resultSet")
-	}
-	return nil
+	return updateRangeOrResultSet(state, id, edge.OutV,
+		func(r lsif.RangeData) lsif.RangeData { return r.SetHoverResultID(edge.InV) },
+		func(r lsif.ResultSetData) lsif.ResultSetData { return r.SetHoverResultID(edge.InV) },
+	)
 }
 
 func correlateMonikerEdge(state *wrappedState, id string, edge lsif.Edge) error {
@@ -341,14 +426,34 @@ func correlateMonikerEdge(state *wrappedState, id string, edge lsif.Edge) error
 	ids := datastructures.IDSet{}
 	ids.Add(edge.InV)
 
-	if source, ok := state.RangeData[edge.OutV]; ok {
-		state.RangeData[edge.OutV] = source.SetMonikerIDs(ids)
-	} else if source, ok := state.ResultSetData[edge.OutV]; ok {
-		state.ResultSetData[edge.OutV] = source.SetMonikerIDs(ids)
-	} else {
-		return malformedDump(id, edge.OutV, "range", "resultSet")
+	return updateRangeOrResultSet(state, id, edge.OutV,
+		func(r lsif.RangeData) lsif.RangeData { return r.SetMonikerIDs(ids) },
+		func(r lsif.ResultSetData) lsif.ResultSetData { return r.SetMonikerIDs(ids) },
+	)
+}
+
+// updateRangeOrResultSet replaces the range or result set with the given identifier by the result of
+// the matching update function. An error is returned if the identifier refers to neither.
+func updateRangeOrResultSet(
+	state *wrappedState,
+	id string,
+	outV string,
+	updateRange func(r lsif.RangeData) lsif.RangeData,
+	updateResultSet func(r lsif.ResultSetData) lsif.ResultSetData,
+) error {
+	if source, ok, err := state.RangeData.Get(outV); err != nil {
+		return err
+	} else if ok {
+		return state.RangeData.Set(outV, updateRange(source))
 	}
-	return nil
+
+	if source, ok, err := state.ResultSetData.Get(outV); err != nil {
+		return err
+	} else if ok {
+		return state.ResultSetData.Set(outV, updateResultSet(source))
+	}
+
+	return malformedDump(id, outV, "range", "resultSet")
 }
 
 func correlateNextMonikerEdge(state *wrappedState, id string, edge lsif.Edge) error {
@@ -385,3 +490,15 @@ func correlatePackageInformationEdge(state *wrappedState, id string, edge lsif.E
 
 	return nil
 }
+
+func correlateTextDocumentDocumentSymbolEdge(state *wrappedState, id string, edge lsif.Edge) error {
+	if _, ok := state.DocumentSymbolData[edge.InV]; !ok {
+		return malformedDump(id, edge.InV, "documentSymbolResult")
+	}
+	if !state.DocumentData.Has(edge.OutV) {
+		return malformedDump(id, edge.OutV, "document")
+	}
+
+	state.DocumentSymbolResults.GetOrCreate(edge.OutV).Add(edge.InV)
+	return nil
+}
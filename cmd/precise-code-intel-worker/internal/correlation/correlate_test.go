@@ -3,11 +3,13 @@ package correlation
 import (
 	"bytes"
 	"io/ioutil"
+	"os"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/sourcegraph/sourcegraph/cmd/precise-code-intel-worker/internal/correlation/datastructures"
 	"github.com/sourcegraph/sourcegraph/cmd/precise-code-intel-worker/internal/correlation/lsif"
+	"github.com/sourcegraph/sourcegraph/cmd/precise-code-intel-worker/internal/correlation/spill"
 )
 
 func TestCorrelate(t *testing.T) {
@@ -16,7 +18,7 @@ func TestCorrelate(t *testing.T) {
 		t.Fatalf("unexpected error reading test file: %s", err)
 	}
 
-	state, err := correlateFromReader(bytes.NewReader(input), "root")
+	state, err := correlateFromReader(bytes.NewReader(input), "root", spill.NewBudget("", 0), false)
 	if err != nil {
 		t.Fatalf("unexpected error correlating input: %s", err)
 	}
@@ -24,11 +26,11 @@ func TestCorrelate(t *testing.T) {
 	expectedState := &State{
 		LSIFVersion: "0.4.3",
 		ProjectRoot: "file:///test/root",
-		DocumentData: map[string]lsif.DocumentData{
+		DocumentData: makeDocumentMap(t, map[string]lsif.DocumentData{
 			"02": {URI: "/foo.go", Contains: datastructures.IDSet{"04": {}, "05": {}, "06": {}}},
 			"03": {URI: "/bar.go", Contains: datastructures.IDSet{"07": {}, "08": {}, "09": {}}},
-		},
-		RangeData: map[string]lsif.RangeData{
+		}),
+		RangeData: makeRangeMap(t, map[string]lsif.RangeData{
 			"04": {
 				StartLine:          1,
 				StartCharacter:     2,
@@ -77,8 +79,12 @@ func TestCorrelate(t *testing.T) {
 				EndCharacter:   9,
 				MonikerIDs:     datastructures.IDSet{"19": {}},
 			},
+		}),
+		RangeTagData: map[string]lsif.RangeTag{
+			"04": {Text: "foo", Kind: 12},
+			"06": {Text: "bar", Detail: "int", Kind: 13},
 		},
-		ResultSetData: map[string]lsif.ResultSetData{
+		ResultSetData: makeResultSetMap(t, map[string]lsif.ResultSetData{
 			"10": {
 				DefinitionResultID: "12",
 				ReferenceResultID:  "14",
@@ -88,19 +94,19 @@ func TestCorrelate(t *testing.T) {
 				HoverResultID: "16",
 				MonikerIDs:    datastructures.IDSet{"21": {}},
 			},
-		},
-		DefinitionData: map[string]datastructures.DefaultIDSetMap{
+		}),
+		DefinitionData: makeResultMap(t, map[string]datastructures.DefaultIDSetMap{
 			"12": {"03": {"07": {}}},
 			"13": {"03": {"08": {}}},
-		},
-		ReferenceData: map[string]datastructures.DefaultIDSetMap{
+		}),
+		ReferenceData: makeResultMap(t, map[string]datastructures.DefaultIDSetMap{
 			"14": {"02": {"04": {}, "05": {}}},
 			"15": {},
-		},
-		HoverData: map[string]string{
+		}),
+		HoverData: makeStringMap(t, map[string]string{
 			"16": "```go\ntext A\n```",
 			"17": "```go\ntext B\n```",
-		},
+		}),
 		MonikerData: map[string]lsif.MonikerData{
 			"18": {Kind: "import", Scheme: "scheme A", Identifier: "ident A", PackageInformationID: "22"},
 			"19": {Kind: "export", Scheme: "scheme B", Identifier: "ident B", PackageInformationID: "23"},
@@ -111,6 +117,14 @@ func TestCorrelate(t *testing.T) {
 			"22": {Name: "pkg A", Version: "v0.1.0"},
 			"23": {Name: "pkg B", Version: "v1.2.3"},
 		},
+		DocumentSymbolData: map[string][]lsif.DocumentSymbolData{
+			"49": {{RangeID: "04", Children: []lsif.DocumentSymbolData{{RangeID: "06"}}}},
+			"51": {{Name: "Baz", Kind: 23, StartLine: 4, StartCharacter: 5, EndLine: 4, EndCharacter: 8}},
+		},
+		DocumentSymbolResults: datastructures.DefaultIDSetMap{
+			"02": {"49": {}},
+			"03": {"51": {}},
+		},
 		NextData: map[string]string{
 			"09": "10",
 			"10": "11",
@@ -125,3 +139,78 @@ func TestCorrelate(t *testing.T) {
 		t.Errorf("unexpected state (-want +got):\n%s", diff)
 	}
 }
+
+func TestCorrelateWithMemoryLimit(t *testing.T) {
+	input, err := ioutil.ReadFile("../../testdata/dump.lsif")
+	if err != nil {
+		t.Fatalf("unexpected error reading test file: %s", err)
+	}
+
+	tempDir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatalf("unexpected error creating temp directory: %s", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	expectedState, err := correlateFromReader(bytes.NewReader(input), "root", spill.NewBudget("", 0), false)
+	if err != nil {
+		t.Fatalf("unexpected error correlating data: %s", err)
+	}
+	defer expectedState.Close()
+
+	// A single byte budget moves every value to disk
+	state, err := correlateFromReader(bytes.NewReader(input), "root", spill.NewBudget(tempDir, 1), false)
+	if err != nil {
+		t.Fatalf("unexpected error correlating data: %s", err)
+	}
+	defer state.Close()
+
+	if diff := cmp.Diff(expectedState, state); diff != "" {
+		t.Errorf("unexpected state (-want +got):\n%s", diff)
+	}
+
+	// Updates made while canonicalizing must not be lost for values on disk
+	for _, s := range []*State{expectedState, state} {
+		if err := canonicalize(s); err != nil {
+			t.Fatalf("unexpected error canonicalizing state: %s", err)
+		}
+	}
+
+	if diff := cmp.Diff(expectedState, state); diff != "" {
+		t.Errorf("unexpected canonicalized state (-want +got):\n%s", diff)
+	}
+
+	expectedBundleData, err := groupBundleData(expectedState, 42)
+	if err != nil {
+		t.Fatalf("unexpected error converting correlation state to types: %s", err)
+	}
+	bundleData, err := groupBundleData(state, 42)
+	if err != nil {
+		t.Fatalf("unexpected error converting correlation state to types: %s", err)
+	}
+
+	expectedDocuments, expectedResultChunks := readDocumentsAndResultChunks(t, expectedBundleData)
+	documents, resultChunks := readDocumentsAndResultChunks(t, bundleData)
+	normalizeGroupedBundleData(expectedBundleData, expectedDocuments, expectedResultChunks)
+	normalizeGroupedBundleData(bundleData, documents, resultChunks)
+
+	if diff := cmp.Diff(expectedDocuments, documents); diff != "" {
+		t.Errorf("unexpected documents (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(expectedResultChunks, resultChunks); diff != "" {
+		t.Errorf("unexpected result chunks (-want +got):\n%s", diff)
+	}
+
+	// No document of the upload exists, so all document references are removed
+	getChildren := func(dirnames []string) (map[string][]string, error) { return nil, nil }
+
+	for _, s := range []*State{expectedState, state} {
+		if err := prune(s, "", getChildren); err != nil {
+			t.Fatalf("unexpected error pruning state: %s", err)
+		}
+	}
+
+	if diff := cmp.Diff(expectedState, state); diff != "" {
+		t.Errorf("unexpected pruned state (-want +got):\n%s", diff)
+	}
+}
@@ -3,6 +3,7 @@ package correlation
 import (
 	"fmt"
 	"math"
+	"sort"
 	"strings"
 
 	"github.com/sourcegraph/sourcegraph/cmd/precise-code-intel-worker/internal/correlation/datastructures"
@@ -13,24 +14,33 @@ import (
 
 // GroupedBundleData is a view of a correlation State that sorts data by it containing document
 // and shared data into shareded result chunks. The fields of this type are what is written to
-// persistent storage and what is read in the query path.
+// persistent storage and what is read in the query path. Documents and result chunks are not
+// stored in this value; they are serialized from the underlying state in batches on demand so
+// that only one batch is held in memory at a time.
 type GroupedBundleData struct {
 	LSIFVersion       string
 	NumResultChunks   int
-	Documents         map[string]types.DocumentData
-	ResultChunks      map[int]types.ResultChunkData
 	Definitions       []types.DefinitionReferenceRow
 	References        []types.DefinitionReferenceRow
 	Packages          []types.Package
 	PackageReferences []types.PackageReference
+	Diagnostics       []Diagnostic
+	state             *State
+	documentURIs      map[string]string // maps document identifiers to their paths
 }
 
 const MaxNumResultChunks = 1000
 const ResultsPerResultChunk = 500
 
+// DocumentBatchSize is the maximum number of serialized documents held in memory at once.
+const DocumentBatchSize = 100
+
+// ResultChunkBatchSize is the maximum number of serialized result chunks held in memory at once.
+const ResultChunkBatchSize = 50
+
 // groupBundleData converts a raw (but canonicalized) correlation State into a GroupedBundleData.
 func groupBundleData(state *State, dumpID int) (*GroupedBundleData, error) {
-	numResults := len(state.DefinitionData) + len(state.ReferenceData)
+	numResults := state.DefinitionData.Len() + state.ReferenceData.Len()
 	numResultChunks := int(math.Min(
 		MaxNumResultChunks,
 		math.Max(
@@ -39,22 +49,20 @@ func groupBundleData(state *State, dumpID int) (*GroupedBundleData, error) {
 		),
 	))
 
-	documents, err := serializeBundleDocuments(state)
-	if err != nil {
-		return nil, err
-	}
-
-	resultChunks, err := serializeResultChunks(state, numResultChunks)
-	if err != nil {
+	documentURIs := make(map[string]string, state.DocumentData.Len())
+	if err := state.DocumentData.Each(func(documentID string, doc lsif.DocumentData) error {
+		documentURIs[documentID] = doc.URI
+		return nil
+	}); err != nil {
 		return nil, err
 	}
 
-	definitionRows, err := gatherMonikersByResult(state, state.DefinitionData, getDefinitionResultID)
+	definitionRows, err := gatherMonikersByResult(state, documentURIs, state.DefinitionData, getDefinitionResultID)
 	if err != nil {
 		return nil, err
 	}
 
-	referenceRows, err := gatherMonikersByResult(state, state.ReferenceData, getReferenceResultID)
+	referenceRows, err := gatherMonikersByResult(state, documentURIs, state.ReferenceData, getReferenceResultID)
 	if err != nil {
 		return nil, err
 	}
@@ -72,35 +80,101 @@ func groupBundleData(state *State, dumpID int) (*GroupedBundleData, error) {
 	return &GroupedBundleData{
 		LSIFVersion:       state.LSIFVersion,
 		NumResultChunks:   numResultChunks,
-		Documents:         documents,
-		ResultChunks:      resultChunks,
 		Definitions:       definitionRows,
 		References:        referenceRows,
 		Packages:          packages,
 		PackageReferences: packageReferences,
+		Diagnostics:       state.Diagnostics,
+		state:             state,
+		documentURIs:      documentURIs,
 	}, nil
 }
 
-func serializeBundleDocuments(state *State) (map[string]types.DocumentData, error) {
-	out := map[string]types.DocumentData{}
-	for _, doc := range state.DocumentData {
+// Documents invokes fn with batches of serialized documents indexed by path. Each document is
+// included in exactly one batch, and no batch is larger than DocumentBatchSize.
+func (d *GroupedBundleData) Documents(fn func(documents map[string]types.DocumentData) error) error {
+	batch := make(map[string]types.DocumentData, DocumentBatchSize)
+	if err := d.state.DocumentData.Each(func(documentID string, doc lsif.DocumentData) error {
 		if strings.HasPrefix(doc.URI, "..") {
-			continue
+			return nil
 		}
 
-		data, err := serializeDocument(state, doc)
+		data, err := serializeDocument(d.state, documentID, doc)
 		if err != nil {
-			return nil, err
+			return err
+		}
+
+		batch[doc.URI] = data
+
+		if len(batch) >= DocumentBatchSize {
+			if err := fn(batch); err != nil {
+				return err
+			}
+
+			batch = make(map[string]types.DocumentData, DocumentBatchSize)
 		}
 
-		out[doc.URI] = data
+		return nil
+	}); err != nil {
+		return err
+	}
 
+	if len(batch) == 0 {
+		return nil
 	}
 
-	return out, nil
+	return fn(batch)
 }
 
-func serializeDocument(state *State, doc lsif.DocumentData) (types.DocumentData, error) {
+// ResultChunks invokes fn with batches of serialized result chunks indexed by chunk index. Each
+// non-empty result chunk is included in exactly one batch, and no batch is larger than
+// ResultChunkBatchSize.
+func (d *GroupedBundleData) ResultChunks(fn func(resultChunks map[int]types.ResultChunkData) error) error {
+	definitionIDs := bucketResultIDs(d.state.DefinitionData, d.NumResultChunks)
+	referenceIDs := bucketResultIDs(d.state.ReferenceData, d.NumResultChunks)
+
+	for start := 0; start < d.NumResultChunks; start += ResultChunkBatchSize {
+		end := start + ResultChunkBatchSize
+		if end > d.NumResultChunks {
+			end = d.NumResultChunks
+		}
+
+		resultChunks := map[int]types.ResultChunkData{}
+		for i := start; i < end; i++ {
+			resultChunk := types.ResultChunkData{
+				DocumentPaths:      map[types.ID]string{},
+				DocumentIDRangeIDs: map[types.ID][]types.DocumentIDRangeID{},
+			}
+			if err := addToChunk(d.documentURIs, resultChunk, d.state.DefinitionData, definitionIDs[i]); err != nil {
+				return err
+			}
+			if err := addToChunk(d.documentURIs, resultChunk, d.state.ReferenceData, referenceIDs[i]); err != nil {
+				return err
+			}
+
+			if len(resultChunk.DocumentPaths) != 0 || len(resultChunk.DocumentIDRangeIDs) != 0 {
+				resultChunks[i] = resultChunk
+			}
+		}
+
+		if len(resultChunks) == 0 {
+			continue
+		}
+
+		if err := fn(resultChunks); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Close removes any temporary files created for the underlying correlation state.
+func (d *GroupedBundleData) Close() error {
+	return d.state.Close()
+}
+
+func serializeDocument(state *State, documentID string, doc lsif.DocumentData) (types.DocumentData, error) {
 	document := types.DocumentData{
 		Ranges:             map[types.ID]types.RangeData{},
 		HoverResults:       map[types.ID]string{},
@@ -110,7 +184,10 @@ func serializeDocument(state *State, doc lsif.DocumentData) (types.DocumentData,
 
 	for rangeID := range doc.Contains {
 		k := rangeID
-		v := state.RangeData[rangeID]
+		v, _, err := state.RangeData.Get(rangeID)
+		if err != nil {
+			return types.DocumentData{}, err
+		}
 
 		var monikerIDs []types.ID
 		for m := range v.MonikerIDs {
@@ -129,7 +206,11 @@ func serializeDocument(state *State, doc lsif.DocumentData) (types.DocumentData,
 		}
 
 		if v.HoverResultID != "" {
-			hoverData := state.HoverData[v.HoverResultID]
+			hoverData, _, err := state.HoverData.Get(v.HoverResultID)
+			if err != nil {
+				return types.DocumentData{}, err
+			}
+
 			document.HoverResults[types.ID(v.HoverResultID)] = hoverData
 		}
 
@@ -152,40 +233,94 @@ func serializeDocument(state *State, doc lsif.DocumentData) (types.DocumentData,
 		}
 	}
 
+	for _, resultID := range state.DocumentSymbolResults[documentID].Keys() {
+		symbols, err := serializeSymbols(state, state.DocumentSymbolData[resultID])
+		if err != nil {
+			return types.DocumentData{}, err
+		}
+
+		document.Symbols = append(document.Symbols, symbols...)
+	}
+
 	return document, nil
 }
 
-func serializeResultChunks(state *State, numResultChunks int) (map[int]types.ResultChunkData, error) {
-	var resultChunks []types.ResultChunkData
-	for i := 0; i < numResultChunks; i++ {
-		resultChunks = append(resultChunks, types.ResultChunkData{
-			DocumentPaths:      map[types.ID]string{},
-			DocumentIDRangeIDs: map[types.ID][]types.DocumentIDRangeID{},
-		})
-	}
+// serializeSymbols converts a symbol tree into its stored form. Range-based symbols take their name
+// and kind from the tag of their range. A range-based symbol without a tagged range is dropped and
+// its children take its place.
+func serializeSymbols(state *State, symbols []lsif.DocumentSymbolData) ([]types.SymbolData, error) {
+	var out []types.SymbolData
+	for _, symbol := range symbols {
+		children, err := serializeSymbols(state, symbol.Children)
+		if err != nil {
+			return nil, err
+		}
 
-	addToChunk(state, resultChunks, state.DefinitionData)
-	addToChunk(state, resultChunks, state.ReferenceData)
+		if symbol.RangeID == "" {
+			out = append(out, types.SymbolData{
+				Name:           symbol.Name,
+				Detail:         symbol.Detail,
+				Kind:           symbol.Kind,
+				StartLine:      symbol.StartLine,
+				StartCharacter: symbol.StartCharacter,
+				EndLine:        symbol.EndLine,
+				EndCharacter:   symbol.EndCharacter,
+				Children:       children,
+			})
+			continue
+		}
 
-	out := map[int]types.ResultChunkData{}
-	for id, resultChunk := range resultChunks {
-		if len(resultChunk.DocumentPaths) == 0 && len(resultChunk.DocumentIDRangeIDs) == 0 {
+		r, ok, err := state.RangeData.Get(symbol.RangeID)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			out = append(out, children...)
+			continue
+		}
+		tag, ok := state.RangeTagData[symbol.RangeID]
+		if !ok {
+			out = append(out, children...)
 			continue
 		}
 
-		out[id] = resultChunk
+		out = append(out, types.SymbolData{
+			Name:           tag.Text,
+			Detail:         tag.Detail,
+			Kind:           tag.Kind,
+			StartLine:      r.StartLine,
+			StartCharacter: r.StartCharacter,
+			EndLine:        r.EndLine,
+			EndCharacter:   r.EndCharacter,
+			Children:       children,
+		})
 	}
 
 	return out, nil
 }
 
-func addToChunk(state *State, resultChunks []types.ResultChunkData, data map[string]datastructures.DefaultIDSetMap) {
-	for id, documentRanges := range data {
-		resultChunk := resultChunks[types.HashKey(types.ID(id), len(resultChunks))]
+// bucketResultIDs groups the identifiers of the given results by the index of the result chunk
+// they hash to.
+func bucketResultIDs(data *ResultMap, numResultChunks int) map[int][]string {
+	resultIDs := map[int][]string{}
+	for _, id := range data.Keys() {
+		index := types.HashKey(types.ID(id), numResultChunks)
+		resultIDs[index] = append(resultIDs[index], id)
+	}
+
+	return resultIDs
+}
+
+// addToChunk adds the given results to the given result chunk.
+func addToChunk(documentURIs map[string]string, resultChunk types.ResultChunkData, data *ResultMap, ids []string) error {
+	for _, id := range ids {
+		documentRanges, _, err := data.Get(id)
+		if err != nil {
+			return err
+		}
 
 		for documentID, rangeIDs := range documentRanges {
-			doc := state.DocumentData[documentID]
-			resultChunk.DocumentPaths[types.ID(documentID)] = doc.URI
+			resultChunk.DocumentPaths[types.ID(documentID)] = documentURIs[documentID]
 
 			for rangeID := range rangeIDs {
 				resultChunk.DocumentIDRangeIDs[types.ID(id)] = append(resultChunk.DocumentIDRangeIDs[types.ID(id)], types.DocumentIDRangeID{
@@ -195,6 +330,8 @@ func addToChunk(state *State, resultChunks []types.ResultChunkData, data map[str
 			}
 		}
 	}
+
+	return nil
 }
 
 var (
@@ -202,11 +339,11 @@ var (
 	getReferenceResultID  = func(r lsif.RangeData) string { return r.ReferenceResultID }
 )
 
-func gatherMonikersByResult(state *State, data map[string]datastructures.DefaultIDSetMap, xr func(r lsif.RangeData) string) ([]types.DefinitionReferenceRow, error) {
+func gatherMonikersByResult(state *State, documentURIs map[string]string, data *ResultMap, xr func(r lsif.RangeData) string) ([]types.DefinitionReferenceRow, error) {
 	var rows []types.DefinitionReferenceRow
 
 	monikers := datastructures.DefaultIDSetMap{}
-	for _, r := range state.RangeData {
+	if err := state.RangeData.Each(func(_ string, r lsif.RangeData) error {
 		resultID := xr(r)
 		if resultID != "" && len(r.MonikerIDs) > 0 {
 			s := monikers.GetOrCreate(resultID)
@@ -214,10 +351,16 @@ func gatherMonikersByResult(state *State, data map[string]datastructures.Default
 				s.Add(id)
 			}
 		}
+		return nil
+	}); err != nil {
+		return nil, err
 	}
 
-	for id, documentRanges := range data {
-		monikerIDs, ok := monikers[id]
+	for id, monikerIDs := range monikers {
+		documentRanges, ok, err := data.Get(id)
+		if err != nil {
+			return nil, err
+		}
 		if !ok {
 			continue
 		}
@@ -226,19 +369,22 @@ func gatherMonikersByResult(state *State, data map[string]datastructures.Default
 			moniker := state.MonikerData[monikerID]
 
 			for documentID, rangeIDs := range documentRanges {
-				document := state.DocumentData[documentID]
+				uri := documentURIs[documentID]
 
-				if strings.HasPrefix(document.URI, "..") {
+				if strings.HasPrefix(uri, "..") {
 					continue
 				}
 
 				for id := range rangeIDs {
-					r := state.RangeData[id]
+					r, _, err := state.RangeData.Get(id)
+					if err != nil {
+						return nil, err
+					}
 
 					rows = append(rows, types.DefinitionReferenceRow{
 						Scheme:         moniker.Scheme,
 						Identifier:     moniker.Identifier,
-						URI:            document.URI,
+						URI:            uri,
 						StartLine:      r.StartLine,
 						StartCharacter: r.StartCharacter,
 						EndLine:        r.EndLine,
@@ -306,13 +452,28 @@ func gatherPackageReferences(state *State, dumpID int) ([]types.PackageReference
 		}
 
 		packageReferences = append(packageReferences, types.PackageReference{
-			DumpID:  dumpID,
-			Scheme:  v.Scheme,
-			Name:    v.Name,
-			Version: v.Version,
-			Filter:  filter,
+			DumpID:      dumpID,
+			Scheme:      v.Scheme,
+			Name:        v.Name,
+			Version:     v.Version,
+			Filter:      filter,
+			Identifiers: sortedUniqueStrings(v.Identifiers),
 		})
 	}
 
 	return packageReferences, nil
 }
+
+// sortedUniqueStrings returns the distinct values of the given slice in sorted order.
+func sortedUniqueStrings(values []string) []string {
+	sort.Strings(values)
+
+	var unique []string
+	for i, value := range values {
+		if i == 0 || value != values[i-1] {
+			unique = append(unique, value)
+		}
+	}
+
+	return unique
+}
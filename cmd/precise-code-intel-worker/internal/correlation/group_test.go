@@ -6,8 +6,10 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/sourcegraph/sourcegraph/cmd/precise-code-intel-worker/internal/correlation/datastructures"
 	"github.com/sourcegraph/sourcegraph/cmd/precise-code-intel-worker/internal/correlation/lsif"
+	"github.com/sourcegraph/sourcegraph/cmd/precise-code-intel-worker/internal/correlation/spill"
 	"github.com/sourcegraph/sourcegraph/internal/codeintel/bloomfilter"
 	"github.com/sourcegraph/sourcegraph/internal/codeintel/bundles/types"
 )
@@ -15,12 +17,12 @@ import (
 func TestConvert(t *testing.T) {
 	state := &State{
 		LSIFVersion: "0.4.3",
-		DocumentData: map[string]lsif.DocumentData{
+		DocumentData: makeDocumentMap(t, map[string]lsif.DocumentData{
 			"d01": {URI: "foo.go", Contains: datastructures.IDSet{"r01": {}, "r02": {}, "r03": {}}},
 			"d02": {URI: "bar.go", Contains: datastructures.IDSet{"r04": {}, "r05": {}, "r06": {}}},
 			"d03": {URI: "baz.go", Contains: datastructures.IDSet{"r07": {}, "r08": {}, "r09": {}}},
-		},
-		RangeData: map[string]lsif.RangeData{
+		}),
+		RangeData: makeRangeMap(t, map[string]lsif.RangeData{
 			"r01": {StartLine: 1, StartCharacter: 2, EndLine: 3, EndCharacter: 4, DefinitionResultID: "x01", MonikerIDs: datastructures.IDSet{"m01": {}, "m02": {}}},
 			"r02": {StartLine: 2, StartCharacter: 3, EndLine: 4, EndCharacter: 5, ReferenceResultID: "x06", MonikerIDs: datastructures.IDSet{"m03": {}, "m04": {}}},
 			"r03": {StartLine: 3, StartCharacter: 4, EndLine: 5, EndCharacter: 6, DefinitionResultID: "x02"},
@@ -30,22 +32,22 @@ func TestConvert(t *testing.T) {
 			"r07": {StartLine: 7, StartCharacter: 8, EndLine: 9, EndCharacter: 0, DefinitionResultID: "x04"},
 			"r08": {StartLine: 8, StartCharacter: 9, EndLine: 0, EndCharacter: 1, HoverResultID: "x09"},
 			"r09": {StartLine: 9, StartCharacter: 0, EndLine: 1, EndCharacter: 2, DefinitionResultID: "x05"},
-		},
-		DefinitionData: map[string]datastructures.DefaultIDSetMap{
+		}),
+		DefinitionData: makeResultMap(t, map[string]datastructures.DefaultIDSetMap{
 			"x01": {"d01": {"r03": {}}, "d02": {"r04": {}}, "d03": {"r07": {}}},
 			"x02": {"d01": {"r02": {}}, "d02": {"r05": {}}, "d03": {"r08": {}}},
 			"x03": {"d01": {"r01": {}}, "d02": {"r06": {}}, "d03": {"r09": {}}},
 			"x04": {"d01": {"r03": {}}, "d02": {"r05": {}}, "d03": {"r07": {}}},
 			"x05": {"d01": {"r02": {}}, "d02": {"r06": {}}, "d03": {"r08": {}}},
-		},
-		ReferenceData: map[string]datastructures.DefaultIDSetMap{
+		}),
+		ReferenceData: makeResultMap(t, map[string]datastructures.DefaultIDSetMap{
 			"x06": {"d01": {"r03": {}}, "d03": {"r07": {}, "r09": {}}},
 			"x07": {"d01": {"r02": {}}, "d03": {"r07": {}, "r09": {}}},
-		},
-		HoverData: map[string]string{
+		}),
+		HoverData: makeStringMap(t, map[string]string{
 			"x08": "foo",
 			"x09": "bar",
-		},
+		}),
 		MonikerData: map[string]lsif.MonikerData{
 			"m01": {Kind: "import", Scheme: "scheme A", Identifier: "ident A", PackageInformationID: "p01"},
 			"m02": {Kind: "import", Scheme: "scheme B", Identifier: "ident B"},
@@ -56,6 +58,18 @@ func TestConvert(t *testing.T) {
 			"p01": {Name: "pkg A", Version: "0.1.0"},
 			"p02": {Name: "pkg B", Version: "1.2.3"},
 		},
+		RangeTagData: map[string]lsif.RangeTag{
+			"r01": {Text: "foo", Kind: 12},
+			"r03": {Text: "bar", Detail: "int", Kind: 13},
+		},
+		DocumentSymbolData: map[string][]lsif.DocumentSymbolData{
+			"s01": {{RangeID: "r01", Children: []lsif.DocumentSymbolData{{RangeID: "r02", Children: []lsif.DocumentSymbolData{{RangeID: "r03"}}}}}},
+			"s02": {{Name: "baz", Kind: 23, StartLine: 7, StartCharacter: 8, EndLine: 9, EndCharacter: 0}},
+		},
+		DocumentSymbolResults: datastructures.DefaultIDSetMap{
+			"d01": {"s01": {}},
+			"d03": {"s02": {}},
+		},
 		ImportedMonikers: datastructures.IDSet{"m01": {}},
 		ExportedMonikers: datastructures.IDSet{"m03": {}},
 	}
@@ -64,8 +78,10 @@ func TestConvert(t *testing.T) {
 	if err != nil {
 		t.Fatalf("unexpected error converting correlation state to types: %s", err)
 	}
+	actualDocuments, actualResultChunks := readDocumentsAndResultChunks(t, actualBundleData)
+
 	// Ensure arrays have deterministic order so we can compare with a canned expected object structure
-	normalizeGroupedBundleData(actualBundleData)
+	normalizeGroupedBundleData(actualBundleData, actualDocuments, actualResultChunks)
 
 	expectedFilter, err := bloomfilter.CreateFilter([]string{"ident A"})
 	if err != nil {
@@ -75,92 +91,6 @@ func TestConvert(t *testing.T) {
 	expectedBundleData := &GroupedBundleData{
 		LSIFVersion:     "0.4.3",
 		NumResultChunks: 1,
-		Documents: map[string]types.DocumentData{
-			"foo.go": {
-				Ranges: map[types.ID]types.RangeData{
-					"r01": {StartLine: 1, StartCharacter: 2, EndLine: 3, EndCharacter: 4, DefinitionResultID: "x01", MonikerIDs: []types.ID{"m01", "m02"}},
-					"r02": {StartLine: 2, StartCharacter: 3, EndLine: 4, EndCharacter: 5, ReferenceResultID: "x06", MonikerIDs: []types.ID{"m03", "m04"}},
-					"r03": {StartLine: 3, StartCharacter: 4, EndLine: 5, EndCharacter: 6, DefinitionResultID: "x02"},
-				},
-				HoverResults: map[types.ID]string{},
-				Monikers: map[types.ID]types.MonikerData{
-					"m01": {Kind: "import", Scheme: "scheme A", Identifier: "ident A", PackageInformationID: "p01"},
-					"m02": {Kind: "import", Scheme: "scheme B", Identifier: "ident B"},
-					"m03": {Kind: "export", Scheme: "scheme C", Identifier: "ident C", PackageInformationID: "p02"},
-					"m04": {Kind: "export", Scheme: "scheme D", Identifier: "ident D"},
-				},
-				PackageInformation: map[types.ID]types.PackageInformationData{
-					"p01": {Name: "pkg A", Version: "0.1.0"},
-					"p02": {Name: "pkg B", Version: "1.2.3"},
-				},
-			},
-			"bar.go": {
-				Ranges: map[types.ID]types.RangeData{
-					"r04": {StartLine: 4, StartCharacter: 5, EndLine: 6, EndCharacter: 7, ReferenceResultID: "x07"},
-					"r05": {StartLine: 5, StartCharacter: 6, EndLine: 7, EndCharacter: 8, DefinitionResultID: "x03"},
-					"r06": {StartLine: 6, StartCharacter: 7, EndLine: 8, EndCharacter: 9, HoverResultID: "x08"},
-				},
-				HoverResults:       map[types.ID]string{"x08": "foo"},
-				Monikers:           map[types.ID]types.MonikerData{},
-				PackageInformation: map[types.ID]types.PackageInformationData{},
-			},
-			"baz.go": {
-				Ranges: map[types.ID]types.RangeData{
-					"r07": {StartLine: 7, StartCharacter: 8, EndLine: 9, EndCharacter: 0, DefinitionResultID: "x04"},
-					"r08": {StartLine: 8, StartCharacter: 9, EndLine: 0, EndCharacter: 1, HoverResultID: "x09"},
-					"r09": {StartLine: 9, StartCharacter: 0, EndLine: 1, EndCharacter: 2, DefinitionResultID: "x05"},
-				},
-				HoverResults:       map[types.ID]string{"x09": "bar"},
-				Monikers:           map[types.ID]types.MonikerData{},
-				PackageInformation: map[types.ID]types.PackageInformationData{},
-			},
-		},
-		ResultChunks: map[int]types.ResultChunkData{
-			0: {
-				DocumentPaths: map[types.ID]string{
-					"d01": "foo.go",
-					"d02": "bar.go",
-					"d03": "baz.go",
-				},
-				DocumentIDRangeIDs: map[types.ID][]types.DocumentIDRangeID{
-					"x01": {
-						{DocumentID: "d01", RangeID: "r03"},
-						{DocumentID: "d02", RangeID: "r04"},
-						{DocumentID: "d03", RangeID: "r07"},
-					},
-					"x02": {
-						{DocumentID: "d01", RangeID: "r02"},
-						{DocumentID: "d02", RangeID: "r05"},
-						{DocumentID: "d03", RangeID: "r08"},
-					},
-					"x03": {
-						{DocumentID: "d01", RangeID: "r01"},
-						{DocumentID: "d02", RangeID: "r06"},
-						{DocumentID: "d03", RangeID: "r09"},
-					},
-					"x04": {
-						{DocumentID: "d01", RangeID: "r03"},
-						{DocumentID: "d02", RangeID: "r05"},
-						{DocumentID: "d03", RangeID: "r07"},
-					},
-					"x05": {
-						{DocumentID: "d01", RangeID: "r02"},
-						{DocumentID: "d02", RangeID: "r06"},
-						{DocumentID: "d03", RangeID: "r08"},
-					},
-					"x06": {
-						{DocumentID: "d01", RangeID: "r03"},
-						{DocumentID: "d03", RangeID: "r07"},
-						{DocumentID: "d03", RangeID: "r09"},
-					},
-					"x07": {
-						{DocumentID: "d01", RangeID: "r02"},
-						{DocumentID: "d03", RangeID: "r07"},
-						{DocumentID: "d03", RangeID: "r09"},
-					},
-				},
-			},
-		},
 		Definitions: []types.DefinitionReferenceRow{
 			{Scheme: "scheme A", Identifier: "ident A", URI: "bar.go", StartLine: 4, StartCharacter: 5, EndLine: 6, EndCharacter: 7},
 			{Scheme: "scheme B", Identifier: "ident B", URI: "bar.go", StartLine: 4, StartCharacter: 5, EndLine: 6, EndCharacter: 7},
@@ -181,11 +111,116 @@ func TestConvert(t *testing.T) {
 			{DumpID: 42, Scheme: "scheme C", Name: "pkg B", Version: "1.2.3"},
 		},
 		PackageReferences: []types.PackageReference{
-			{DumpID: 42, Scheme: "scheme A", Name: "pkg A", Version: "0.1.0", Filter: expectedFilter},
+			{DumpID: 42, Scheme: "scheme A", Name: "pkg A", Version: "0.1.0", Filter: expectedFilter, Identifiers: []string{"ident A"}},
+		},
+	}
+
+	expectedDocuments := map[string]types.DocumentData{
+		"foo.go": {
+			Ranges: map[types.ID]types.RangeData{
+				"r01": {StartLine: 1, StartCharacter: 2, EndLine: 3, EndCharacter: 4, DefinitionResultID: "x01", MonikerIDs: []types.ID{"m01", "m02"}},
+				"r02": {StartLine: 2, StartCharacter: 3, EndLine: 4, EndCharacter: 5, ReferenceResultID: "x06", MonikerIDs: []types.ID{"m03", "m04"}},
+				"r03": {StartLine: 3, StartCharacter: 4, EndLine: 5, EndCharacter: 6, DefinitionResultID: "x02"},
+			},
+			HoverResults: map[types.ID]string{},
+			Monikers: map[types.ID]types.MonikerData{
+				"m01": {Kind: "import", Scheme: "scheme A", Identifier: "ident A", PackageInformationID: "p01"},
+				"m02": {Kind: "import", Scheme: "scheme B", Identifier: "ident B"},
+				"m03": {Kind: "export", Scheme: "scheme C", Identifier: "ident C", PackageInformationID: "p02"},
+				"m04": {Kind: "export", Scheme: "scheme D", Identifier: "ident D"},
+			},
+			PackageInformation: map[types.ID]types.PackageInformationData{
+				"p01": {Name: "pkg A", Version: "0.1.0"},
+				"p02": {Name: "pkg B", Version: "1.2.3"},
+			},
+			Symbols: []types.SymbolData{
+				{
+					Name: "foo", Kind: 12, StartLine: 1, StartCharacter: 2, EndLine: 3, EndCharacter: 4,
+					Children: []types.SymbolData{
+						{Name: "bar", Detail: "int", Kind: 13, StartLine: 3, StartCharacter: 4, EndLine: 5, EndCharacter: 6},
+					},
+				},
+			},
+		},
+		"bar.go": {
+			Ranges: map[types.ID]types.RangeData{
+				"r04": {StartLine: 4, StartCharacter: 5, EndLine: 6, EndCharacter: 7, ReferenceResultID: "x07"},
+				"r05": {StartLine: 5, StartCharacter: 6, EndLine: 7, EndCharacter: 8, DefinitionResultID: "x03"},
+				"r06": {StartLine: 6, StartCharacter: 7, EndLine: 8, EndCharacter: 9, HoverResultID: "x08"},
+			},
+			HoverResults:       map[types.ID]string{"x08": "foo"},
+			Monikers:           map[types.ID]types.MonikerData{},
+			PackageInformation: map[types.ID]types.PackageInformationData{},
+		},
+		"baz.go": {
+			Ranges: map[types.ID]types.RangeData{
+				"r07": {StartLine: 7, StartCharacter: 8, EndLine: 9, EndCharacter: 0, DefinitionResultID: "x04"},
+				"r08": {StartLine: 8, StartCharacter: 9, EndLine: 0, EndCharacter: 1, HoverResultID: "x09"},
+				"r09": {StartLine: 9, StartCharacter: 0, EndLine: 1, EndCharacter: 2, DefinitionResultID: "x05"},
+			},
+			HoverResults:       map[types.ID]string{"x09": "bar"},
+			Monikers:           map[types.ID]types.MonikerData{},
+			PackageInformation: map[types.ID]types.PackageInformationData{},
+			Symbols: []types.SymbolData{
+				{Name: "baz", Kind: 23, StartLine: 7, StartCharacter: 8, EndLine: 9, EndCharacter: 0},
+			},
+		},
+	}
+
+	expectedResultChunks := map[int]types.ResultChunkData{
+		0: {
+			DocumentPaths: map[types.ID]string{
+				"d01": "foo.go",
+				"d02": "bar.go",
+				"d03": "baz.go",
+			},
+			DocumentIDRangeIDs: map[types.ID][]types.DocumentIDRangeID{
+				"x01": {
+					{DocumentID: "d01", RangeID: "r03"},
+					{DocumentID: "d02", RangeID: "r04"},
+					{DocumentID: "d03", RangeID: "r07"},
+				},
+				"x02": {
+					{DocumentID: "d01", RangeID: "r02"},
+					{DocumentID: "d02", RangeID: "r05"},
+					{DocumentID: "d03", RangeID: "r08"},
+				},
+				"x03": {
+					{DocumentID: "d01", RangeID: "r01"},
+					{DocumentID: "d02", RangeID: "r06"},
+					{DocumentID: "d03", RangeID: "r09"},
+				},
+				"x04": {
+					{DocumentID: "d01", RangeID: "r03"},
+					{DocumentID: "d02", RangeID: "r05"},
+					{DocumentID: "d03", RangeID: "r07"},
+				},
+				"x05": {
+					{DocumentID: "d01", RangeID: "r02"},
+					{DocumentID: "d02", RangeID: "r06"},
+					{DocumentID: "d03", RangeID: "r08"},
+				},
+				"x06": {
+					{DocumentID: "d01", RangeID: "r03"},
+					{DocumentID: "d03", RangeID: "r07"},
+					{DocumentID: "d03", RangeID: "r09"},
+				},
+				"x07": {
+					{DocumentID: "d01", RangeID: "r02"},
+					{DocumentID: "d03", RangeID: "r07"},
+					{DocumentID: "d03", RangeID: "r09"},
+				},
+			},
 		},
 	}
 
-	if diff := cmp.Diff(expectedBundleData, actualBundleData); diff != "" {
+	if diff := cmp.Diff(expectedDocuments, actualDocuments); diff != "" {
+		t.Errorf("unexpected documents (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(expectedResultChunks, actualResultChunks); diff != "" {
+		t.Errorf("unexpected result chunks (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff(expectedBundleData, actualBundleData, cmpopts.IgnoreUnexported(GroupedBundleData{})); diff != "" {
 		t.Errorf("unexpected bundle data (-want +got):\n%s", diff)
 	}
 }
@@ -193,14 +228,40 @@ func TestConvert(t *testing.T) {
 //
 //
 
-func normalizeGroupedBundleData(groupedBundleData *GroupedBundleData) {
-	for _, document := range groupedBundleData.Documents {
+// readDocumentsAndResultChunks collects the batches of documents and result chunks produced by
+// the given grouped bundle data.
+func readDocumentsAndResultChunks(t *testing.T, groupedBundleData *GroupedBundleData) (map[string]types.DocumentData, map[int]types.ResultChunkData) {
+	documents := map[string]types.DocumentData{}
+	if err := groupedBundleData.Documents(func(batch map[string]types.DocumentData) error {
+		for k, v := range batch {
+			documents[k] = v
+		}
+		return nil
+	}); err != nil {
+		t.Fatalf("unexpected error serializing documents: %s", err)
+	}
+
+	resultChunks := map[int]types.ResultChunkData{}
+	if err := groupedBundleData.ResultChunks(func(batch map[int]types.ResultChunkData) error {
+		for k, v := range batch {
+			resultChunks[k] = v
+		}
+		return nil
+	}); err != nil {
+		t.Fatalf("unexpected error serializing result chunks: %s", err)
+	}
+
+	return documents, resultChunks
+}
+
+func normalizeGroupedBundleData(groupedBundleData *GroupedBundleData, documents map[string]types.DocumentData, resultChunks map[int]types.ResultChunkData) {
+	for _, document := range documents {
 		for _, r := range document.Ranges {
 			sortMonikerIDs(r.MonikerIDs)
 		}
 	}
 
-	for _, resultChunk := range groupedBundleData.ResultChunks {
+	for _, resultChunk := range resultChunks {
 		for _, documentRanges := range resultChunk.DocumentIDRangeIDs {
 			sortDocumentIDRangeIDs(documentRanges)
 		}
@@ -237,3 +298,58 @@ func sortDefinitionReferenceRows(s []types.DefinitionReferenceRow) {
 		}
 	})
 }
+
+func makeStringMap(t *testing.T, values map[string]string) *spill.StringMap {
+	m := spill.NewStringMap(spill.NewBudget("", 0))
+	for k, v := range values {
+		if err := m.Set(k, v); err != nil {
+			t.Fatalf("unexpected error setting value: %s", err)
+		}
+	}
+
+	return m
+}
+
+func makeDocumentMap(t *testing.T, values map[string]lsif.DocumentData) *DocumentMap {
+	m := newDocumentMap(spill.NewBudget("", 0))
+	for k, v := range values {
+		if err := m.Set(k, v); err != nil {
+			t.Fatalf("unexpected error setting value: %s", err)
+		}
+	}
+
+	return m
+}
+
+func makeRangeMap(t *testing.T, values map[string]lsif.RangeData) *RangeMap {
+	m := newRangeMap(spill.NewBudget("", 0))
+	for k, v := range values {
+		if err := m.Set(k, v); err != nil {
+			t.Fatalf("unexpected error setting value: %s", err)
+		}
+	}
+
+	return m
+}
+
+func makeResultSetMap(t *testing.T, values map[string]lsif.ResultSetData) *ResultSetMap {
+	m := newResultSetMap(spill.NewBudget("", 0))
+	for k, v := range values {
+		if err := m.Set(k, v); err != nil {
+			t.Fatalf("unexpected error setting value: %s", err)
+		}
+	}
+
+	return m
+}
+
+func makeResultMap(t *testing.T, values map[string]datastructures.DefaultIDSetMap) *ResultMap {
+	m := newResultMap(spill.NewBudget("", 0))
+	for k, v := range values {
+		if err := m.Set(k, v); err != nil {
+			t.Fatalf("unexpected error setting value: %s", err)
+		}
+	}
+
+	return m
+}
@@ -0,0 +1,123 @@
+package lsif
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+)
+
+// RangeTag is the symbol information attached to a range that defines or declares a symbol.
+type RangeTag struct {
+	Text   string
+	Detail string
+	Kind   int
+}
+
+// UnmarshalRangeTag returns the tag of the given range vertex. The returned flag is false if the
+// range has no tag or if its tag does not describe a definition or declaration.
+func UnmarshalRangeTag(element Element) (RangeTag, bool, error) {
+	if !bytes.Contains(element.Raw, []byte(`"tag"`)) {
+		return RangeTag{}, false, nil
+	}
+
+	type Tag struct {
+		Type   string `json:"type"`
+		Text   string `json:"text"`
+		Detail string `json:"detail"`
+		Kind   int    `json:"kind"`
+	}
+
+	type RangeVertex struct {
+		Tag *Tag `json:"tag"`
+	}
+
+	var payload RangeVertex
+	if err := json.Unmarshal(element.Raw, &payload); err != nil {
+		return RangeTag{}, false, err
+	}
+
+	if payload.Tag == nil || (payload.Tag.Type != "definition" && payload.Tag.Type != "declaration") {
+		return RangeTag{}, false, nil
+	}
+
+	return RangeTag{Text: payload.Tag.Text, Detail: payload.Tag.Detail, Kind: payload.Tag.Kind}, true, nil
+}
+
+// DocumentSymbolData is a node in the symbol tree of a document. A range-based symbol refers to
+// a range vertex by identifier and is described by the tag of that range. Other symbols carry
+// their own name, kind, and location (the range of the symbol's name).
+type DocumentSymbolData struct {
+	RangeID        string
+	Name           string
+	Detail         string
+	Kind           int
+	StartLine      int
+	StartCharacter int
+	EndLine        int
+	EndCharacter   int
+	Children       []DocumentSymbolData
+}
+
+type documentSymbolPosition struct {
+	Line      int `json:"line"`
+	Character int `json:"character"`
+}
+
+type documentSymbolRange struct {
+	Start documentSymbolPosition `json:"start"`
+	End   documentSymbolPosition `json:"end"`
+}
+
+// documentSymbolPayload is either a RangeBasedDocumentSymbol or an LSP DocumentSymbol.
+type documentSymbolPayload struct {
+	ID             json.RawMessage         `json:"id"`
+	Name           string                  `json:"name"`
+	Detail         string                  `json:"detail"`
+	Kind           int                     `json:"kind"`
+	SelectionRange documentSymbolRange     `json:"selectionRange"`
+	Children       []documentSymbolPayload `json:"children"`
+}
+
+// UnmarshalDocumentSymbolData returns the symbol trees of the given documentSymbolResult vertex.
+func UnmarshalDocumentSymbolData(element Element) ([]DocumentSymbolData, error) {
+	type DocumentSymbolResultVertex struct {
+		Result []documentSymbolPayload `json:"result"`
+	}
+
+	var payload DocumentSymbolResultVertex
+	if err := json.Unmarshal(element.Raw, &payload); err != nil {
+		return nil, err
+	}
+
+	return convertDocumentSymbols(payload.Result), nil
+}
+
+func convertDocumentSymbols(payloads []documentSymbolPayload) []DocumentSymbolData {
+	var symbols []DocumentSymbolData
+	for _, p := range payloads {
+		symbols = append(symbols, DocumentSymbolData{
+			RangeID:        unmarshalID(p.ID),
+			Name:           p.Name,
+			Detail:         p.Detail,
+			Kind:           p.Kind,
+			StartLine:      p.SelectionRange.Start.Line,
+			StartCharacter: p.SelectionRange.Start.Character,
+			EndLine:        p.SelectionRange.End.Line,
+			EndCharacter:   p.SelectionRange.End.Character,
+			Children:       convertDocumentSymbols(p.Children),
+		})
+	}
+
+	return symbols
+}
+
+// unmarshalID converts a JSON string or number into an identifier. An empty string is returned
+// for an absent identifier.
+func unmarshalID(raw json.RawMessage) string {
+	var id string
+	if err := json.Unmarshal(raw, &id); err == nil {
+		return id
+	}
+
+	return strings.TrimSpace(string(raw))
+}
@@ -0,0 +1,76 @@
+package lsif
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestUnmarshalRangeTag(t *testing.T) {
+	tag, ok, err := UnmarshalRangeTag(Element{
+		ID:    "04",
+		Type:  "vertex",
+		Label: "range",
+		Raw:   json.RawMessage(`{"id": "04", "type": "vertex", "label": "range", "start": {"line": 1, "character": 2}, "end": {"line": 1, "character": 5}, "tag": {"type": "definition", "text": "foo", "kind": 12, "detail": "func()"}}`),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error unmarshalling range tag: %s", err)
+	}
+	if !ok {
+		t.Fatalf("expected range tag")
+	}
+
+	if diff := cmp.Diff(RangeTag{Text: "foo", Detail: "func()", Kind: 12}, tag); diff != "" {
+		t.Errorf("unexpected range tag (-want +got):\n%s", diff)
+	}
+
+	_, ok, err = UnmarshalRangeTag(Element{
+		ID:    "05",
+		Type:  "vertex",
+		Label: "range",
+		Raw:   json.RawMessage(`{"id": "05", "type": "vertex", "label": "range", "start": {"line": 1, "character": 2}, "end": {"line": 1, "character": 5}, "tag": {"type": "reference", "text": "foo"}}`),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error unmarshalling range tag: %s", err)
+	}
+	if ok {
+		t.Errorf("unexpected range tag for reference range")
+	}
+}
+
+func TestUnmarshalDocumentSymbolData(t *testing.T) {
+	symbols, err := UnmarshalDocumentSymbolData(Element{
+		ID:    "49",
+		Type:  "vertex",
+		Label: "documentSymbolResult",
+		Raw:   json.RawMessage(`{"id": "49", "type": "vertex", "label": "documentSymbolResult", "result": [{"id": "04", "children": [{"id": 6}]}]}`),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error unmarshalling document symbols: %s", err)
+	}
+
+	expectedSymbols := []DocumentSymbolData{
+		{RangeID: "04", Children: []DocumentSymbolData{{RangeID: "6"}}},
+	}
+	if diff := cmp.Diff(expectedSymbols, symbols); diff != "" {
+		t.Errorf("unexpected document symbols (-want +got):\n%s", diff)
+	}
+
+	symbols, err = UnmarshalDocumentSymbolData(Element{
+		ID:    "51",
+		Type:  "vertex",
+		Label: "documentSymbolResult",
+		Raw:   json.RawMessage(`{"id": "51", "type": "vertex", "label": "documentSymbolResult", "result": [{"name": "Baz", "detail": "struct", "kind": 23, "range": {"start": {"line": 4, "character": 0}, "end": {"line": 9, "character": 1}}, "selectionRange": {"start": {"line": 4, "character": 5}, "end": {"line": 4, "character": 8}}}]}`),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error unmarshalling document symbols: %s", err)
+	}
+
+	expectedSymbols = []DocumentSymbolData{
+		{Name: "Baz", Detail: "struct", Kind: 23, StartLine: 4, StartCharacter: 5, EndLine: 4, EndCharacter: 8},
+	}
+	if diff := cmp.Diff(expectedSymbols, symbols); diff != "" {
+		t.Errorf("unexpected document symbols (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,264 @@
+package correlation
+
+import (
+	"encoding/json"
+	"reflect"
+
+	"github.com/sourcegraph/sourcegraph/cmd/precise-code-intel-worker/internal/correlation/datastructures"
+	"github.com/sourcegraph/sourcegraph/cmd/precise-code-intel-worker/internal/correlation/lsif"
+	"github.com/sourcegraph/sourcegraph/cmd/precise-code-intel-worker/internal/correlation/spill"
+)
+
+// DocumentMap maps document identifiers to document data. Values are moved to disk once the
+// memory budget of the map is exceeded, so a modified value must be written back with Set.
+type DocumentMap struct {
+	m *spill.Map
+}
+
+func newDocumentMap(budget *spill.Budget) *DocumentMap {
+	return &DocumentMap{m: spill.NewMap(budget, documentCodec)}
+}
+
+func (m *DocumentMap) Len() int           { return m.m.Len() }
+func (m *DocumentMap) Has(id string) bool { return m.m.Has(id) }
+func (m *DocumentMap) Delete(id string)   { m.m.Delete(id) }
+func (m *DocumentMap) Keys() []string     { return m.m.Keys() }
+func (m *DocumentMap) Close() error       { return m.m.Close() }
+
+func (m *DocumentMap) Get(id string) (lsif.DocumentData, bool, error) {
+	value, ok, err := m.m.Get(id)
+	if err != nil || !ok {
+		return lsif.DocumentData{}, ok, err
+	}
+
+	return value.(lsif.DocumentData), true, nil
+}
+
+func (m *DocumentMap) Set(id string, document lsif.DocumentData) error {
+	return m.m.Set(id, document)
+}
+
+func (m *DocumentMap) Each(fn func(id string, document lsif.DocumentData) error) error {
+	return m.m.Each(func(id string, value interface{}) error { return fn(id, value.(lsif.DocumentData)) })
+}
+
+func (m *DocumentMap) Equal(other *DocumentMap) bool {
+	if m == nil || other == nil {
+		return m == other
+	}
+
+	return equalMaps(m.m, other.m)
+}
+
+// RangeMap maps range identifiers to range data. Values are moved to disk once the memory
+// budget of the map is exceeded, so a modified value must be written back with Set.
+type RangeMap struct {
+	m *spill.Map
+}
+
+func newRangeMap(budget *spill.Budget) *RangeMap {
+	return &RangeMap{m: spill.NewMap(budget, rangeCodec)}
+}
+
+func (m *RangeMap) Len() int           { return m.m.Len() }
+func (m *RangeMap) Has(id string) bool { return m.m.Has(id) }
+func (m *RangeMap) Delete(id string)   { m.m.Delete(id) }
+func (m *RangeMap) Keys() []string     { return m.m.Keys() }
+func (m *RangeMap) Close() error       { return m.m.Close() }
+
+func (m *RangeMap) Get(id string) (lsif.RangeData, bool, error) {
+	value, ok, err := m.m.Get(id)
+	if err != nil || !ok {
+		return lsif.RangeData{}, ok, err
+	}
+
+	return value.(lsif.RangeData), true, nil
+}
+
+func (m *RangeMap) Set(id string, r lsif.RangeData) error {
+	return m.m.Set(id, r)
+}
+
+func (m *RangeMap) Each(fn func(id string, r lsif.RangeData) error) error {
+	return m.m.Each(func(id string, value interface{}) error { return fn(id, value.(lsif.RangeData)) })
+}
+
+func (m *RangeMap) Equal(other *RangeMap) bool {
+	if m == nil || other == nil {
+		return m == other
+	}
+
+	return equalMaps(m.m, other.m)
+}
+
+// ResultSetMap maps result set identifiers to result set data. Values are moved to disk once
+// the memory budget of the map is exceeded, so a modified value must be written back with Set.
+type ResultSetMap struct {
+	m *spill.Map
+}
+
+func newResultSetMap(budget *spill.Budget) *ResultSetMap {
+	return &ResultSetMap{m: spill.NewMap(budget, resultSetCodec)}
+}
+
+func (m *ResultSetMap) Len() int           { return m.m.Len() }
+func (m *ResultSetMap) Has(id string) bool { return m.m.Has(id) }
+func (m *ResultSetMap) Delete(id string)   { m.m.Delete(id) }
+func (m *ResultSetMap) Keys() []string     { return m.m.Keys() }
+func (m *ResultSetMap) Close() error       { return m.m.Close() }
+
+func (m *ResultSetMap) Get(id string) (lsif.ResultSetData, bool, error) {
+	value, ok, err := m.m.Get(id)
+	if err != nil || !ok {
+		return lsif.ResultSetData{}, ok, err
+	}
+
+	return value.(lsif.ResultSetData), true, nil
+}
+
+func (m *ResultSetMap) Set(id string, resultSet lsif.ResultSetData) error {
+	return m.m.Set(id, resultSet)
+}
+
+func (m *ResultSetMap) Each(fn func(id string, resultSet lsif.ResultSetData) error) error {
+	return m.m.Each(func(id string, value interface{}) error { return fn(id, value.(lsif.ResultSetData)) })
+}
+
+func (m *ResultSetMap) Equal(other *ResultSetMap) bool {
+	if m == nil || other == nil {
+		return m == other
+	}
+
+	return equalMaps(m.m, other.m)
+}
+
+// ResultMap maps definition or reference result identifiers to the ranges of the result,
+// grouped by document identifier. Values are moved to disk once the memory budget of the
+// map is exceeded, so a modified value must be written back with Set.
+type ResultMap struct {
+	m *spill.Map
+}
+
+func newResultMap(budget *spill.Budget) *ResultMap {
+	return &ResultMap{m: spill.NewMap(budget, resultCodec)}
+}
+
+func (m *ResultMap) Len() int           { return m.m.Len() }
+func (m *ResultMap) Has(id string) bool { return m.m.Has(id) }
+func (m *ResultMap) Delete(id string)   { m.m.Delete(id) }
+func (m *ResultMap) Keys() []string     { return m.m.Keys() }
+func (m *ResultMap) Close() error       { return m.m.Close() }
+
+func (m *ResultMap) Get(id string) (datastructures.DefaultIDSetMap, bool, error) {
+	value, ok, err := m.m.Get(id)
+	if err != nil || !ok {
+		return nil, ok, err
+	}
+
+	return value.(datastructures.DefaultIDSetMap), true, nil
+}
+
+func (m *ResultMap) Set(id string, documentRanges datastructures.DefaultIDSetMap) error {
+	return m.m.Set(id, documentRanges)
+}
+
+func (m *ResultMap) Each(fn func(id string, documentRanges datastructures.DefaultIDSetMap) error) error {
+	return m.m.Each(func(id string, value interface{}) error { return fn(id, value.(datastructures.DefaultIDSetMap)) })
+}
+
+func (m *ResultMap) Equal(other *ResultMap) bool {
+	if m == nil || other == nil {
+		return m == other
+	}
+
+	return equalMaps(m.m, other.m)
+}
+
+// equalMaps returns true if both maps contain the same keys and values, regardless of which
+// of their values have been written to disk.
+func equalMaps(m1, m2 *spill.Map) bool {
+	if m1.Len() != m2.Len() {
+		return false
+	}
+
+	for _, key := range m1.Keys() {
+		v1, _, err1 := m1.Get(key)
+		v2, ok, err2 := m2.Get(key)
+		if err1 != nil || err2 != nil || !ok || !reflect.DeepEqual(v1, v2) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// jsonCodec writes values of a single type as JSON.
+type jsonCodec struct {
+	size   func(value interface{}) int64
+	decode func(data []byte) (interface{}, error)
+}
+
+func (c jsonCodec) Size(value interface{}) int64             { return c.size(value) }
+func (c jsonCodec) Encode(value interface{}) ([]byte, error) { return json.Marshal(value) }
+func (c jsonCodec) Decode(data []byte) (interface{}, error)  { return c.decode(data) }
+
+var documentCodec = jsonCodec{
+	size: func(value interface{}) int64 {
+		document := value.(lsif.DocumentData)
+		return int64(len(document.URI)) + idSetSize(document.Contains)
+	},
+	decode: func(data []byte) (interface{}, error) {
+		var document lsif.DocumentData
+		err := json.Unmarshal(data, &document)
+		return document, err
+	},
+}
+
+var rangeCodec = jsonCodec{
+	size: func(value interface{}) int64 {
+		r := value.(lsif.RangeData)
+		return 4*8 + int64(len(r.DefinitionResultID)+len(r.ReferenceResultID)+len(r.HoverResultID)) + idSetSize(r.MonikerIDs)
+	},
+	decode: func(data []byte) (interface{}, error) {
+		var r lsif.RangeData
+		err := json.Unmarshal(data, &r)
+		return r, err
+	},
+}
+
+var resultSetCodec = jsonCodec{
+	size: func(value interface{}) int64 {
+		resultSet := value.(lsif.ResultSetData)
+		return int64(len(resultSet.DefinitionResultID)+len(resultSet.ReferenceResultID)+len(resultSet.HoverResultID)) + idSetSize(resultSet.MonikerIDs)
+	},
+	decode: func(data []byte) (interface{}, error) {
+		var resultSet lsif.ResultSetData
+		err := json.Unmarshal(data, &resultSet)
+		return resultSet, err
+	},
+}
+
+var resultCodec = jsonCodec{
+	size: func(value interface{}) int64 {
+		size := int64(0)
+		for documentID, rangeIDs := range value.(datastructures.DefaultIDSetMap) {
+			size += int64(len(documentID)) + idSetSize(rangeIDs)
+		}
+		return size
+	},
+	decode: func(data []byte) (interface{}, error) {
+		var documentRanges datastructures.DefaultIDSetMap
+		err := json.Unmarshal(data, &documentRanges)
+		return documentRanges, err
+	},
+}
+
+// idSetSize returns an estimate of the number of bytes the given set occupies in memory.
+func idSetSize(set datastructures.IDSet) int64 {
+	size := int64(0)
+	for id := range set {
+		size += int64(len(id))
+	}
+
+	return size
+}
@@ -2,6 +2,7 @@ package correlation
 
 import (
 	"github.com/sourcegraph/sourcegraph/cmd/precise-code-intel-worker/internal/correlation/datastructures"
+	"github.com/sourcegraph/sourcegraph/cmd/precise-code-intel-worker/internal/correlation/lsif"
 	"github.com/sourcegraph/sourcegraph/cmd/precise-code-intel-worker/internal/existence"
 )
 
@@ -10,9 +11,17 @@ import (
 // not be the source of any queries (and take up unnecessary space in the converted index),
 // and may be the target of a definition or reference (and references a file we do not have).
 func prune(state *State, root string, getChildren existence.GetChildrenFunc) error {
-	paths := make([]string, 0, len(state.DocumentData))
-	for _, doc := range state.DocumentData {
-		paths = append(paths, doc.URI)
+	uris := make(map[string]string, state.DocumentData.Len())
+	if err := state.DocumentData.Each(func(documentID string, doc lsif.DocumentData) error {
+		uris[documentID] = doc.URI
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	paths := make([]string, 0, len(uris))
+	for _, uri := range uris {
+		paths = append(paths, uri)
 	}
 
 	checker, err := existence.NewExistenceChecker(root, paths, getChildren)
@@ -20,25 +29,35 @@ func prune(state *State, root string, getChildren existence.GetChildrenFunc) err
 		return err
 	}
 
-	for documentID, doc := range state.DocumentData {
-		if !checker.Exists(doc.URI) {
+	for documentID, uri := range uris {
+		if !checker.Exists(uri) {
 			// Document does not exist in git
-			delete(state.DocumentData, documentID)
+			state.DocumentData.Delete(documentID)
+			delete(state.DocumentSymbolResults, documentID)
 		}
 	}
 
-	pruneFromDefinitionReferences(state, state.DefinitionData)
-	pruneFromDefinitionReferences(state, state.ReferenceData)
-	return nil
+	if err := pruneFromDefinitionReferences(state, state.DefinitionData); err != nil {
+		return err
+	}
+	return pruneFromDefinitionReferences(state, state.ReferenceData)
 }
 
-func pruneFromDefinitionReferences(state *State, definitionReferenceData map[string]datastructures.DefaultIDSetMap) {
-	for _, documentRanges := range definitionReferenceData {
+func pruneFromDefinitionReferences(state *State, definitionReferenceData *ResultMap) error {
+	return definitionReferenceData.Each(func(id string, documentRanges datastructures.DefaultIDSetMap) error {
+		changed := false
 		for documentID := range documentRanges {
-			if _, ok := state.DocumentData[documentID]; !ok {
+			if !state.DocumentData.Has(documentID) {
 				// Document was pruned, remove reference
 				delete(documentRanges, documentID)
+				changed = true
 			}
 		}
-	}
+
+		if !changed {
+			return nil
+		}
+
+		return definitionReferenceData.Set(id, documentRanges)
+	})
 }
@@ -24,21 +24,21 @@ func TestPrune(t *testing.T) {
 	}
 
 	state := &State{
-		DocumentData: map[string]lsif.DocumentData{
+		DocumentData: makeDocumentMap(t, map[string]lsif.DocumentData{
 			"d01": {URI: "foo.go"},
 			"d02": {URI: "bar.go"},
 			"d03": {URI: "sub/baz.go"},
 			"d04": {URI: "foo.generated.go"},
 			"d05": {URI: "foo.generated.go"},
-		},
-		DefinitionData: map[string]datastructures.DefaultIDSetMap{
+		}),
+		DefinitionData: makeResultMap(t, map[string]datastructures.DefaultIDSetMap{
 			"x01": {"d01": {}, "d04": {}},
 			"x02": {"d02": {}},
-		},
-		ReferenceData: map[string]datastructures.DefaultIDSetMap{
+		}),
+		ReferenceData: makeResultMap(t, map[string]datastructures.DefaultIDSetMap{
 			"x03": {"d02": {}},
 			"x04": {"d02": {}, "d05": {}},
-		},
+		}),
 	}
 
 	if err := prune(state, "root", getChildren); err != nil {
@@ -46,19 +46,19 @@ func TestPrune(t *testing.T) {
 	}
 
 	expectedState := &State{
-		DocumentData: map[string]lsif.DocumentData{
+		DocumentData: makeDocumentMap(t, map[string]lsif.DocumentData{
 			"d01": {URI: "foo.go"},
 			"d02": {URI: "bar.go"},
 			"d03": {URI: "sub/baz.go"},
-		},
-		DefinitionData: map[string]datastructures.DefaultIDSetMap{
+		}),
+		DefinitionData: makeResultMap(t, map[string]datastructures.DefaultIDSetMap{
 			"x01": {"d01": {}},
 			"x02": {"d02": {}},
-		},
-		ReferenceData: map[string]datastructures.DefaultIDSetMap{
+		}),
+		ReferenceData: makeResultMap(t, map[string]datastructures.DefaultIDSetMap{
 			"x03": {"d02": {}},
 			"x04": {"d02": {}},
-		},
+		}),
 	}
 	if diff := cmp.Diff(expectedState, state); diff != "" {
 		t.Errorf("unexpected state (-want +got):\n%s", diff)
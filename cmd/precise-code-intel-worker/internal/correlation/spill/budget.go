@@ -0,0 +1,57 @@
+package spill
+
+// Budget is the amount of memory that a set of maps may use to hold values before those
+// values are moved to temporary files on disk. A single budget is shared by all maps that
+// belong to the same correlation. Once the budget is exceeded, every map of the budget
+// moves the values it holds in memory to disk.
+type Budget struct {
+	dir   string
+	limit int64
+	used  int64
+	maps  []*Map
+}
+
+// NewBudget creates a budget that allows limit bytes of values to be held in memory. A limit
+// of zero or less allows an unbounded number of bytes. Temporary files are created in dir, or
+// in the default temporary directory if dir is empty.
+func NewBudget(dir string, limit int64) *Budget {
+	return &Budget{dir: dir, limit: limit}
+}
+
+// Used returns the number of bytes currently held in memory.
+func (b *Budget) Used() int64 {
+	return b.used
+}
+
+// register adds the given map to the set of maps that are spilled when the budget is exceeded.
+func (b *Budget) register(m *Map) {
+	b.maps = append(b.maps, m)
+}
+
+// acquire records that n additional bytes are held in memory.
+func (b *Budget) acquire(n int64) {
+	b.used += n
+	memoryBytes.Add(float64(n))
+}
+
+// release records that n bytes are no longer held in memory.
+func (b *Budget) release(n int64) {
+	b.used -= n
+	memoryBytes.Sub(float64(n))
+}
+
+// exceeded returns true if more bytes are held in memory than the limit allows.
+func (b *Budget) exceeded() bool {
+	return b.limit > 0 && b.used > b.limit
+}
+
+// spill moves the values held in memory by every map of the budget to disk.
+func (b *Budget) spill() error {
+	for _, m := range b.maps {
+		if err := m.spill(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,243 @@
+// Package spill provides maps that hold their values in memory up to a shared budget and
+// move them to temporary files once the budget is exceeded.
+package spill
+
+import (
+	"bufio"
+	"io/ioutil"
+	"os"
+	"sort"
+)
+
+// Codec converts the values of a map to and from the bytes written to its temporary file.
+type Codec interface {
+	// Size returns an estimate of the number of bytes the given value occupies in memory.
+	Size(value interface{}) int64
+
+	// Encode serializes the given value.
+	Encode(value interface{}) ([]byte, error)
+
+	// Decode deserializes a value written by Encode.
+	Decode(data []byte) (interface{}, error)
+}
+
+// Map is a map from strings to values. Values are held in memory until the budget of the
+// map is exceeded, at which point the values held by all maps of the budget are written to
+// temporary files and read back on demand. Keys are always held in memory.
+//
+// A value returned by Get may be a copy of the stored value. Callers that modify a value
+// must write it back with Set.
+type Map struct {
+	budget  *Budget
+	codec   Codec
+	values  map[string]interface{}
+	sizes   map[string]int64
+	offsets map[string]span
+	file    *os.File
+	size    int64
+}
+
+// span is the location of a value within the temporary file of a map.
+type span struct {
+	offset int64
+	length int64
+}
+
+// NewMap creates an empty map that draws on the given budget and writes values to disk
+// with the given codec.
+func NewMap(budget *Budget, codec Codec) *Map {
+	m := &Map{
+		budget:  budget,
+		codec:   codec,
+		values:  map[string]interface{}{},
+		sizes:   map[string]int64{},
+		offsets: map[string]span{},
+	}
+
+	budget.register(m)
+	return m
+}
+
+// Len returns the number of keys in the map.
+func (m *Map) Len() int {
+	return len(m.values) + len(m.offsets)
+}
+
+// Has returns true if the map contains the given key.
+func (m *Map) Has(key string) bool {
+	if _, ok := m.values[key]; ok {
+		return true
+	}
+
+	_, ok := m.offsets[key]
+	return ok
+}
+
+// Get returns the value of the given key and a flag indicating whether the key exists.
+func (m *Map) Get(key string) (interface{}, bool, error) {
+	if value, ok := m.values[key]; ok {
+		return value, true, nil
+	}
+
+	s, ok := m.offsets[key]
+	if !ok {
+		return nil, false, nil
+	}
+
+	value, err := m.read(s)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return value, true, nil
+}
+
+// Set updates the value of the given key. If this exceeds the budget of the map, all values
+// held in memory by the maps of the budget are written to disk.
+func (m *Map) Set(key string, value interface{}) error {
+	m.Delete(key)
+
+	size := int64(len(key)) + m.codec.Size(value)
+	m.values[key] = value
+	m.sizes[key] = size
+	m.budget.acquire(size)
+
+	if m.budget.exceeded() {
+		return m.budget.spill()
+	}
+
+	return nil
+}
+
+// Delete removes the given key from the map. The space occupied by a value that has already
+// been written to disk is not reclaimed until the map is closed.
+func (m *Map) Delete(key string) {
+	if _, ok := m.values[key]; ok {
+		m.budget.release(m.sizes[key])
+		delete(m.values, key)
+		delete(m.sizes, key)
+	}
+
+	delete(m.offsets, key)
+}
+
+// Keys returns the keys of the map in an unspecified order.
+func (m *Map) Keys() []string {
+	keys := make([]string, 0, m.Len())
+	for key := range m.values {
+		keys = append(keys, key)
+	}
+	for key := range m.offsets {
+		keys = append(keys, key)
+	}
+
+	return keys
+}
+
+// Each invokes fn with every key and value of the map. Values written to disk are read in
+// the order they appear in the temporary file. The map may be modified by fn: keys that are
+// added are not visited, and keys that are deleted before they are visited are skipped.
+func (m *Map) Each(fn func(key string, value interface{}) error) error {
+	keys := m.Keys()
+	sort.Slice(keys, func(i, j int) bool {
+		// Values held in memory have no span and sort first
+		return m.offsets[keys[i]].offset < m.offsets[keys[j]].offset
+	})
+
+	for _, key := range keys {
+		value, ok, err := m.Get(key)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+
+		if err := fn(key, value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Close releases the memory held by the map and removes its temporary file.
+func (m *Map) Close() error {
+	for key := range m.values {
+		m.budget.release(m.sizes[key])
+	}
+	m.values = map[string]interface{}{}
+	m.sizes = map[string]int64{}
+	m.offsets = map[string]span{}
+
+	if m.file == nil {
+		return nil
+	}
+
+	file := m.file
+	m.file = nil
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	return os.Remove(file.Name())
+}
+
+// read decodes the value at the given location of the temporary file of the map.
+func (m *Map) read(s span) (interface{}, error) {
+	buf := make([]byte, s.length)
+	if _, err := m.file.ReadAt(buf, s.offset); err != nil {
+		return nil, err
+	}
+
+	return m.codec.Decode(buf)
+}
+
+// spill appends all values held in memory to the temporary file of the map, creating it if
+// necessary, and releases the memory they occupied.
+func (m *Map) spill() error {
+	if len(m.values) == 0 {
+		return nil
+	}
+
+	if m.file == nil {
+		file, err := ioutil.TempFile(m.budget.dir, "correlation-")
+		if err != nil {
+			return err
+		}
+		m.file = file
+	}
+
+	// Reads do not move the file position, which stays at the end of the file
+	w := bufio.NewWriter(m.file)
+
+	n := int64(0)
+	offsets := make(map[string]span, len(m.values))
+	for key, value := range m.values {
+		data, err := m.codec.Encode(value)
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write(data); err != nil {
+			return err
+		}
+
+		offsets[key] = span{offset: m.size + n, length: int64(len(data))}
+		n += int64(len(data))
+	}
+
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	m.size += n
+	spilledBytes.Add(float64(n))
+
+	for key := range m.values {
+		m.budget.release(m.sizes[key])
+		m.offsets[key] = offsets[key]
+	}
+	m.values = map[string]interface{}{}
+	m.sizes = map[string]int64{}
+
+	return nil
+}
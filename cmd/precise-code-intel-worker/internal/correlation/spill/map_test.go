@@ -0,0 +1,93 @@
+package spill
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func TestMapSpillsAllMapsOfBudget(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatalf("unexpected error creating temp directory: %s", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	budget := NewBudget(tempDir, 64)
+	m1 := NewMap(budget, stringCodec{})
+	m2 := NewMap(budget, stringCodec{})
+	defer m1.Close()
+	defer m2.Close()
+
+	if err := m1.Set("k1", "value 1"); err != nil {
+		t.Fatalf("unexpected error setting value: %s", err)
+	}
+	if err := m2.Set("k2", "a value that does not fit within the budget of both maps"); err != nil {
+		t.Fatalf("unexpected error setting value: %s", err)
+	}
+
+	if budget.Used() != 0 {
+		t.Errorf("unexpected memory usage. want=%d have=%d", 0, budget.Used())
+	}
+	if entries, err := ioutil.ReadDir(tempDir); err != nil {
+		t.Fatalf("unexpected error reading temp directory: %s", err)
+	} else if len(entries) != 2 {
+		t.Errorf("unexpected number of files. want=%d have=%d", 2, len(entries))
+	}
+
+	if value, ok, err := m1.Get("k1"); err != nil {
+		t.Fatalf("unexpected error getting value: %s", err)
+	} else if !ok || value != "value 1" {
+		t.Errorf("unexpected value for k1. want=%q have=%q", "value 1", value)
+	}
+}
+
+func TestMapEach(t *testing.T) {
+	for _, limit := range []int64{0, 1} {
+		t.Run(fmt.Sprintf("limit=%d", limit), func(t *testing.T) {
+			m := NewMap(NewBudget("", limit), stringCodec{})
+			defer m.Close()
+
+			for i := 0; i < 10; i++ {
+				if err := m.Set(fmt.Sprintf("k%d", i), fmt.Sprintf("value %d", i)); err != nil {
+					t.Fatalf("unexpected error setting value: %s", err)
+				}
+			}
+
+			var keys []string
+			deleted := ""
+			if err := m.Each(func(key string, value interface{}) error {
+				keys = append(keys, key)
+
+				if deleted == "" {
+					// Delete a key that has not been visited yet
+					for _, other := range m.Keys() {
+						if other != key {
+							deleted = other
+							break
+						}
+					}
+					m.Delete(deleted)
+				}
+
+				// Added keys are not visited
+				return m.Set(key+"-copy", value)
+			}); err != nil {
+				t.Fatalf("unexpected error iterating map: %s", err)
+			}
+
+			if len(keys) != 9 {
+				t.Errorf("unexpected number of visited keys. want=%d have=%d", 9, len(keys))
+			}
+			for _, key := range keys {
+				if key == deleted {
+					t.Errorf("unexpected visit of deleted key %s", key)
+				}
+			}
+			if m.Len() != 18 {
+				t.Errorf("unexpected length. want=%d have=%d", 18, m.Len())
+			}
+		})
+	}
+}
@@ -0,0 +1,18 @@
+package spill
+
+import "github.com/prometheus/client_golang/prometheus"
+
+func init() {
+	prometheus.MustRegister(memoryBytes)
+	prometheus.MustRegister(spilledBytes)
+}
+
+var memoryBytes = prometheus.NewGauge(prometheus.GaugeOpts{
+	Name: "src_precise_code_intel_worker_correlation_memory_bytes",
+	Help: "The estimated number of bytes of correlation data currently held in memory.",
+})
+
+var spilledBytes = prometheus.NewCounter(prometheus.CounterOpts{
+	Name: "src_precise_code_intel_worker_correlation_spilled_bytes_total",
+	Help: "The total number of bytes of correlation data written to temporary files.",
+})
@@ -0,0 +1,83 @@
+package spill
+
+// StringMap is a map from strings to strings whose values are moved to disk once its budget
+// is exceeded.
+type StringMap struct {
+	m *Map
+}
+
+// NewStringMap creates an empty map that draws on the given budget.
+func NewStringMap(budget *Budget) *StringMap {
+	return &StringMap{m: NewMap(budget, stringCodec{})}
+}
+
+// Len returns the number of keys in the map.
+func (m *StringMap) Len() int {
+	return m.m.Len()
+}
+
+// Has returns true if the map contains the given key.
+func (m *StringMap) Has(key string) bool {
+	return m.m.Has(key)
+}
+
+// Get returns the value of the given key and a flag indicating whether the key exists.
+func (m *StringMap) Get(key string) (string, bool, error) {
+	value, ok, err := m.m.Get(key)
+	if err != nil || !ok {
+		return "", ok, err
+	}
+
+	return value.(string), true, nil
+}
+
+// Set updates the value of the given key.
+func (m *StringMap) Set(key, value string) error {
+	return m.m.Set(key, value)
+}
+
+// Keys returns the keys of the map in an unspecified order.
+func (m *StringMap) Keys() []string {
+	return m.m.Keys()
+}
+
+// Equal returns true if both maps contain the same keys and values. Maps are considered
+// equal regardless of which of their values have been written to disk.
+func (m *StringMap) Equal(other *StringMap) bool {
+	if m == nil || other == nil {
+		return m == other
+	}
+	if m.Len() != other.Len() {
+		return false
+	}
+
+	for _, key := range m.Keys() {
+		v1, _, err1 := m.Get(key)
+		v2, ok, err2 := other.Get(key)
+		if err1 != nil || err2 != nil || !ok || v1 != v2 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Close releases the memory held by the map and removes its temporary file.
+func (m *StringMap) Close() error {
+	return m.m.Close()
+}
+
+// stringCodec writes string values as their raw bytes.
+type stringCodec struct{}
+
+func (stringCodec) Size(value interface{}) int64 {
+	return int64(len(value.(string)))
+}
+
+func (stringCodec) Encode(value interface{}) ([]byte, error) {
+	return []byte(value.(string)), nil
+}
+
+func (stringCodec) Decode(data []byte) (interface{}, error) {
+	return string(data), nil
+}
@@ -0,0 +1,89 @@
+package spill
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func TestStringMap(t *testing.T) {
+	for _, limit := range []int64{0, 1, 64} {
+		t.Run(fmt.Sprintf("limit=%d", limit), func(t *testing.T) {
+			tempDir, err := ioutil.TempDir("", "")
+			if err != nil {
+				t.Fatalf("unexpected error creating temp directory: %s", err)
+			}
+			defer os.RemoveAll(tempDir)
+
+			budget := NewBudget(tempDir, limit)
+			m := NewStringMap(budget)
+
+			for i := 0; i < 100; i++ {
+				if err := m.Set(fmt.Sprintf("k%d", i), fmt.Sprintf("value %d", i)); err != nil {
+					t.Fatalf("unexpected error setting value: %s", err)
+				}
+			}
+			if err := m.Set("k42", "updated"); err != nil {
+				t.Fatalf("unexpected error setting value: %s", err)
+			}
+
+			if limit > 0 && budget.Used() > limit {
+				t.Errorf("unexpected memory usage. want<=%d have=%d", limit, budget.Used())
+			}
+			if m.Len() != 100 {
+				t.Errorf("unexpected length. want=%d have=%d", 100, m.Len())
+			}
+
+			for i := 0; i < 100; i++ {
+				expected := fmt.Sprintf("value %d", i)
+				if i == 42 {
+					expected = "updated"
+				}
+
+				if value, ok, err := m.Get(fmt.Sprintf("k%d", i)); err != nil {
+					t.Fatalf("unexpected error getting value: %s", err)
+				} else if !ok || value != expected {
+					t.Errorf("unexpected value for k%d. want=%q have=%q", i, expected, value)
+				}
+			}
+
+			if _, ok, err := m.Get("missing"); err != nil || ok {
+				t.Errorf("unexpected value for missing key")
+			}
+
+			if err := m.Close(); err != nil {
+				t.Fatalf("unexpected error closing map: %s", err)
+			}
+			if budget.Used() != 0 {
+				t.Errorf("unexpected memory usage after close. want=%d have=%d", 0, budget.Used())
+			}
+			if entries, err := ioutil.ReadDir(tempDir); err != nil {
+				t.Fatalf("unexpected error reading temp directory: %s", err)
+			} else if len(entries) != 0 {
+				t.Errorf("unexpected files after close: %d", len(entries))
+			}
+		})
+	}
+}
+
+func TestStringMapEqual(t *testing.T) {
+	m1 := NewStringMap(NewBudget("", 0))
+	m2 := NewStringMap(NewBudget("", 1))
+	defer m1.Close()
+	defer m2.Close()
+
+	for _, m := range []*StringMap{m1, m2} {
+		_ = m.Set("foo", "bar")
+		_ = m.Set("baz", "bonk")
+	}
+
+	if !m1.Equal(m2) {
+		t.Errorf("expected maps to be equal")
+	}
+
+	_ = m2.Set("baz", "quux")
+	if m1.Equal(m2) {
+		t.Errorf("expected maps to differ")
+	}
+}
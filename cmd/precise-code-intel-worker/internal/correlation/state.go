@@ -1,40 +1,52 @@
 package correlation
 
 import (
+	"github.com/hashicorp/go-multierror"
 	"github.com/sourcegraph/sourcegraph/cmd/precise-code-intel-worker/internal/correlation/datastructures"
 	"github.com/sourcegraph/sourcegraph/cmd/precise-code-intel-worker/internal/correlation/lsif"
+	"github.com/sourcegraph/sourcegraph/cmd/precise-code-intel-worker/internal/correlation/spill"
 )
 
-// State is an in-memory representation of an uploaded LSIF index.
+// State is a representation of an uploaded LSIF index. Documents, ranges, result sets, definition
+// and reference results, and hover text make up the bulk of most indexes and are moved to disk once
+// the memory budget of the state is exceeded. The remaining data is held in memory.
 type State struct {
 	LSIFVersion            string
 	ProjectRoot            string
-	DocumentData           map[string]lsif.DocumentData
-	RangeData              map[string]lsif.RangeData
-	ResultSetData          map[string]lsif.ResultSetData
-	DefinitionData         map[string]datastructures.DefaultIDSetMap
-	ReferenceData          map[string]datastructures.DefaultIDSetMap
-	HoverData              map[string]string
+	DocumentData           *DocumentMap
+	RangeData              *RangeMap
+	RangeTagData           map[string]lsif.RangeTag // tags of ranges that define or declare a symbol
+	ResultSetData          *ResultSetMap
+	DefinitionData         *ResultMap
+	ReferenceData          *ResultMap
+	HoverData              *spill.StringMap
 	MonikerData            map[string]lsif.MonikerData
 	PackageInformationData map[string]lsif.PackageInformationData
-	NextData               map[string]string            // maps vertices related via next edges
-	ImportedMonikers       datastructures.IDSet         // moniker ids that have kind "import"
-	ExportedMonikers       datastructures.IDSet         // moniker ids that have kind "export"
-	LinkedMonikers         datastructures.DisjointIDSet // tracks which moniker ids are related via next edges
-	LinkedReferenceResults datastructures.DisjointIDSet // tracks which reference result ids are related via next edges
+	DocumentSymbolData     map[string][]lsif.DocumentSymbolData
+	DocumentSymbolResults  datastructures.DefaultIDSetMap // maps documents to their document symbol results
+	NextData               map[string]string              // maps vertices related via next edges
+	ImportedMonikers       datastructures.IDSet           // moniker ids that have kind "import"
+	ExportedMonikers       datastructures.IDSet           // moniker ids that have kind "export"
+	LinkedMonikers         datastructures.DisjointIDSet   // tracks which moniker ids are related via next edges
+	LinkedReferenceResults datastructures.DisjointIDSet   // tracks which reference result ids are related via next edges
+	Diagnostics            []Diagnostic                   // warnings found while correlating
 }
 
-// newState create a new State with zero-valued map fields.
-func newState() *State {
+// newState create a new State with zero-valued map fields. The maps that may be moved to disk
+// draw on the given budget.
+func newState(budget *spill.Budget) *State {
 	return &State{
-		DocumentData:           map[string]lsif.DocumentData{},
-		RangeData:              map[string]lsif.RangeData{},
-		ResultSetData:          map[string]lsif.ResultSetData{},
-		DefinitionData:         map[string]datastructures.DefaultIDSetMap{},
-		ReferenceData:          map[string]datastructures.DefaultIDSetMap{},
-		HoverData:              map[string]string{},
+		DocumentData:           newDocumentMap(budget),
+		RangeData:              newRangeMap(budget),
+		RangeTagData:           map[string]lsif.RangeTag{},
+		ResultSetData:          newResultSetMap(budget),
+		DefinitionData:         newResultMap(budget),
+		ReferenceData:          newResultMap(budget),
+		HoverData:              spill.NewStringMap(budget),
 		MonikerData:            map[string]lsif.MonikerData{},
 		PackageInformationData: map[string]lsif.PackageInformationData{},
+		DocumentSymbolData:     map[string][]lsif.DocumentSymbolData{},
+		DocumentSymbolResults:  datastructures.DefaultIDSetMap{},
 		NextData:               map[string]string{},
 		ImportedMonikers:       datastructures.IDSet{},
 		ExportedMonikers:       datastructures.IDSet{},
@@ -42,3 +54,34 @@ func newState() *State {
 		LinkedReferenceResults: datastructures.DisjointIDSet{},
 	}
 }
+
+// Close removes any temporary files created for the state.
+func (s *State) Close() (err error) {
+	closers := []interface{ Close() error }{}
+	if s.DocumentData != nil {
+		closers = append(closers, s.DocumentData)
+	}
+	if s.RangeData != nil {
+		closers = append(closers, s.RangeData)
+	}
+	if s.ResultSetData != nil {
+		closers = append(closers, s.ResultSetData)
+	}
+	if s.DefinitionData != nil {
+		closers = append(closers, s.DefinitionData)
+	}
+	if s.ReferenceData != nil {
+		closers = append(closers, s.ReferenceData)
+	}
+	if s.HoverData != nil {
+		closers = append(closers, s.HoverData)
+	}
+
+	for _, closer := range closers {
+		if closeErr := closer.Close(); closeErr != nil {
+			err = multierror.Append(err, closeErr)
+		}
+	}
+
+	return err
+}
@@ -0,0 +1,158 @@
+package correlation
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/sourcegraph/sourcegraph/cmd/precise-code-intel-worker/internal/correlation/lsif"
+)
+
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// Diagnostic codes
+const (
+	DiagnosticMalformedElement     = "malformed-element"      // an element is not valid JSON or has an unexpected shape
+	DiagnosticMissingMetaData      = "missing-metadata"       // no metaData vertex precedes the documents
+	DiagnosticDanglingReference    = "dangling-reference"     // an edge refers to a missing vertex or a vertex of the wrong kind
+	DiagnosticDuplicateID          = "duplicate-id"           // a vertex reuses the identifier of a previous vertex
+	DiagnosticInvalidRange         = "invalid-range"          // a range ends before it starts or has a negative position
+	DiagnosticRangeOutsideDocument = "range-outside-document" // a range is not contained by any document
+)
+
+// MaxDiagnostics is the maximum number of diagnostics recorded for a single upload. Problems
+// past this limit are counted but not described.
+const MaxDiagnostics = 100
+
+// Diagnostic describes a problem found in an upload.
+type Diagnostic struct {
+	Severity  string
+	Code      string
+	Message   string
+	ElementID string // empty if the problem does not concern a single element
+	Line      int    // the one-based line of the element in the upload; zero if unknown
+}
+
+// ErrInvalidDump occurs when an upload has errors, or has warnings and is validated in strict mode.
+type ErrInvalidDump struct {
+	Diagnostics []Diagnostic
+	NumErrors   int
+	NumWarnings int
+}
+
+func (e ErrInvalidDump) Error() string {
+	message := fmt.Sprintf("invalid LSIF upload: %d errors and %d warnings", e.NumErrors, e.NumWarnings)
+	if len(e.Diagnostics) > 0 {
+		message += fmt.Sprintf(" (first %s on line %d: %s)", e.Diagnostics[0].Severity, e.Diagnostics[0].Line, e.Diagnostics[0].Message)
+	}
+	return message
+}
+
+// validator records the diagnostics of an upload while it is correlated.
+type validator struct {
+	strict      bool
+	diagnostics []Diagnostic
+	numErrors   int
+	numWarnings int
+	rangeLines  map[string]int // the line of each range vertex
+}
+
+func newValidator(strict bool) *validator {
+	return &validator{strict: strict, rangeLines: map[string]int{}}
+}
+
+func (v *validator) errorf(code, elementID string, line int, format string, args ...interface{}) {
+	v.numErrors++
+	v.add(SeverityError, code, elementID, line, fmt.Sprintf(format, args...))
+}
+
+func (v *validator) warnf(code, elementID string, line int, format string, args ...interface{}) {
+	v.numWarnings++
+	v.add(SeverityWarning, code, elementID, line, fmt.Sprintf(format, args...))
+}
+
+func (v *validator) add(severity, code, elementID string, line int, message string) {
+	if len(v.diagnostics) < MaxDiagnostics {
+		v.diagnostics = append(v.diagnostics, Diagnostic{
+			Severity:  severity,
+			Code:      code,
+			Message:   message,
+			ElementID: elementID,
+			Line:      line,
+		})
+	}
+}
+
+// elementError records the error returned when correlating the given element.
+func (v *validator) elementError(element lsif.Element, line int, err error) {
+	code := DiagnosticMalformedElement
+	if _, ok := err.(ErrMalformedDump); ok {
+		code = DiagnosticDanglingReference
+	} else if err == ErrMissingMetaData {
+		code = DiagnosticMissingMetaData
+	}
+
+	v.errorf(code, element.ID, line, "%s", err)
+}
+
+// validateRange records problems with the positions of the given range vertex.
+func (v *validator) validateRange(id string, line int, r lsif.RangeData) {
+	v.rangeLines[id] = line
+
+	if r.StartLine < 0 || r.StartCharacter < 0 || r.EndLine < 0 || r.EndCharacter < 0 {
+		v.warnf(DiagnosticInvalidRange, id, line, "range has a negative position")
+	} else if r.EndLine < r.StartLine || (r.EndLine == r.StartLine && r.EndCharacter < r.StartCharacter) {
+		v.warnf(DiagnosticInvalidRange, id, line, "range ends before it starts")
+	}
+}
+
+// validateState records problems that can only be found once the entire upload has been read.
+func (v *validator) validateState(state *State) error {
+	if state.LSIFVersion == "" {
+		v.errorf(DiagnosticMissingMetaData, "", 0, "%s", ErrMissingMetaData)
+	}
+
+	contained := map[string]struct{}{}
+	if err := state.DocumentData.Each(func(_ string, document lsif.DocumentData) error {
+		for id := range document.Contains {
+			contained[id] = struct{}{}
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	// Report in upload order
+	for _, id := range sortedRangeIDs(v.rangeLines) {
+		if _, ok := contained[id]; !ok {
+			v.warnf(DiagnosticRangeOutsideDocument, id, v.rangeLines[id], "range is not contained by any document")
+		}
+	}
+
+	return nil
+}
+
+// err returns an ErrInvalidDump if the upload should be rejected.
+func (v *validator) err() error {
+	if v.numErrors == 0 && (!v.strict || v.numWarnings == 0) {
+		return nil
+	}
+
+	return ErrInvalidDump{
+		Diagnostics: v.diagnostics,
+		NumErrors:   v.numErrors,
+		NumWarnings: v.numWarnings,
+	}
+}
+
+// sortedRangeIDs returns the keys of the given map ordered by their value.
+func sortedRangeIDs(rangeLines map[string]int) []string {
+	ids := make([]string, 0, len(rangeLines))
+	for id := range rangeLines {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return rangeLines[ids[i]] < rangeLines[ids[j]] })
+	return ids
+}
@@ -0,0 +1,73 @@
+package correlation
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sourcegraph/sourcegraph/cmd/precise-code-intel-worker/internal/correlation/spill"
+)
+
+const validationInput = `{"id": "01", "type": "vertex", "label": "metaData", "version": "0.4.3", "projectRoot": "file:///test/"}
+{"id": "02", "type": "vertex", "label": "document", "uri": "file:///test/root/foo.go"}
+{"id": "03", "type": "vertex", "label": "range", "start": {"line": 1, "character": 2}, "end": {"line": 1, "character": 4}}
+{"id": "04", "type": "vertex", "label": "range", "start": {"line": 3, "character": 4}, "end": {"line": 2, "character": 0}}
+{"id": "05", "type": "vertex", "label": "range", "start": {"line": 5, "character": 0}, "end": {"line": 5, "character": 3}}
+{"id": "03", "type": "vertex", "label": "resultSet"}
+{"id": "06", "type": "edge", "label": "contains", "outV": "02", "inVs": ["03", "04"]}
+`
+
+func TestValidateWarnings(t *testing.T) {
+	state, err := correlateFromReader(strings.NewReader(validationInput), "root", spill.NewBudget("", 0), false)
+	if err != nil {
+		t.Fatalf("unexpected error correlating input: %s", err)
+	}
+	defer state.Close()
+
+	expected := []Diagnostic{
+		{Severity: SeverityWarning, Code: DiagnosticInvalidRange, Message: "range ends before it starts", ElementID: "04", Line: 4},
+		{Severity: SeverityWarning, Code: DiagnosticDuplicateID, Message: "vertex resultSet reuses the identifier of a previous vertex", ElementID: "03", Line: 6},
+		{Severity: SeverityWarning, Code: DiagnosticRangeOutsideDocument, Message: "range is not contained by any document", ElementID: "05", Line: 5},
+	}
+	if diff := cmp.Diff(expected, state.Diagnostics); diff != "" {
+		t.Errorf("unexpected diagnostics (-want +got):\n%s", diff)
+	}
+}
+
+func TestValidateStrict(t *testing.T) {
+	_, err := correlateFromReader(strings.NewReader(validationInput), "root", spill.NewBudget("", 0), true)
+	invalidErr, ok := err.(ErrInvalidDump)
+	if !ok {
+		t.Fatalf("unexpected error. want=%T have=%v", ErrInvalidDump{}, err)
+	}
+
+	if invalidErr.NumErrors != 0 || invalidErr.NumWarnings != 3 {
+		t.Errorf("unexpected counts. want=%d errors and %d warnings have=%d errors and %d warnings", 0, 3, invalidErr.NumErrors, invalidErr.NumWarnings)
+	}
+}
+
+func TestValidateErrors(t *testing.T) {
+	input := `{"id": "01", "type": "vertex", "label": "document", "uri": "file:///test/root/foo.go"}
+{"id": "02", "type": "vertex", "label": "metaData", "version": "0.4.3", "projectRoot": "file:///test/"}
+{"id": "03", "type": "vertex", "label": "document", "uri": "file:///test/root/bar.go"}
+not json
+{"id": "04", "type": "edge", "label": "contains", "outV": "03", "inVs": ["05"]}
+`
+
+	_, err := correlateFromReader(strings.NewReader(input), "root", spill.NewBudget("", 0), false)
+	invalidErr, ok := err.(ErrInvalidDump)
+	if !ok {
+		t.Fatalf("unexpected error. want=%T have=%v", ErrInvalidDump{}, err)
+	}
+
+	var codes []string
+	for _, diagnostic := range invalidErr.Diagnostics {
+		codes = append(codes, fmt.Sprintf("%s:%d", diagnostic.Code, diagnostic.Line))
+	}
+
+	expected := []string{"missing-metadata:1", "malformed-element:4", "dangling-reference:5"}
+	if diff := cmp.Diff(expected, codes); diff != "" {
+		t.Errorf("unexpected diagnostic codes (-want +got):\n%s", diff)
+	}
+}
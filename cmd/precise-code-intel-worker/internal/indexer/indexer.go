@@ -0,0 +1,205 @@
+package indexer
+
+import (
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"time"
+
+	"github.com/inconshreveable/log15"
+	"github.com/pkg/errors"
+	bundles "github.com/sourcegraph/sourcegraph/internal/codeintel/bundles/client"
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/db"
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/gitserver"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// DumpFilename is the name of the file, relative to the root of the repository, to
+// which indexers must write their LSIF output.
+const DumpFilename = "dump.lsif"
+
+type IndexerOpts struct {
+	DB                  db.DB
+	BundleManagerClient bundles.BundleManagerClient
+	GitserverClient     gitserver.Client
+	PollInterval        time.Duration
+	Timeout             time.Duration
+	Limits              ResourceLimits
+	// Indexers returns the currently configured indexers.
+	Indexers func() []*schema.CodeIntelIndexer
+}
+
+type Indexer struct {
+	db                  db.DB
+	bundleManagerClient bundles.BundleManagerClient
+	gitserverClient     gitserver.Client
+	pollInterval        time.Duration
+	timeout             time.Duration
+	limits              ResourceLimits
+	indexers            func() []*schema.CodeIntelIndexer
+}
+
+func New(opts IndexerOpts) *Indexer {
+	return &Indexer{
+		db:                  opts.DB,
+		bundleManagerClient: opts.BundleManagerClient,
+		gitserverClient:     opts.GitserverClient,
+		pollInterval:        opts.PollInterval,
+		timeout:             opts.Timeout,
+		limits:              opts.Limits,
+		indexers:            opts.Indexers,
+	}
+}
+
+func (i *Indexer) Start() error {
+	for {
+		if ok, err := i.dequeueAndProcess(context.Background()); err != nil {
+			return err
+		} else if !ok {
+			time.Sleep(i.pollInterval)
+		}
+	}
+}
+
+// dequeueAndProcess pulls an index job from the queue and processes it. If there was no
+// job ready to process, this method returns a false-valued flag. Only critical errors are
+// returned. Processing errors are only written to the index record and are not expected to
+// be handled by the calling function.
+func (i *Indexer) dequeueAndProcess(ctx context.Context) (bool, error) {
+	index, ok, err := i.db.DequeueIndex(ctx)
+	if err != nil || !ok {
+		return false, err
+	}
+
+	if log, err := i.process(ctx, index); err != nil {
+		log15.Warn("Failed to process index", "id", index.ID, "err", err)
+
+		if markErr := i.db.MarkIndexErrored(ctx, index.ID, err.Error(), log); markErr != nil {
+			return false, markErr
+		}
+	}
+
+	return true, nil
+}
+
+// process runs the configured indexer against the repository of the given index job and
+// enqueues the resulting dump as an upload. This method returns the (possibly truncated)
+// output of the indexer.
+func (i *Indexer) process(ctx context.Context, index db.Index) (log string, err error) {
+	config := findIndexer(i.indexers(), index.Indexer)
+	if config == nil {
+		return "", fmt.Errorf("no indexer named %q is configured", index.Indexer)
+	}
+
+	// Create scratch directory that we can clean on completion/failure
+	name, err := ioutil.TempDir("", "index-")
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		if cleanupErr := os.RemoveAll(name); cleanupErr != nil {
+			log15.Warn("Failed to remove temporary directory", "path", name, "err", cleanupErr)
+		}
+	}()
+
+	// Pull the files of the repository at the commit of the index from gitserver
+	archive, err := i.gitserverClient.Archive(i.db, index.RepositoryID, index.Commit)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to fetch repository archive")
+	}
+	err = extractTar(archive, name)
+	if closeErr := archive.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", errors.Wrap(err, "failed to extract repository archive")
+	}
+
+	// Run the indexer in the root of the repository
+	indexCtx, cancel := context.WithTimeout(ctx, i.timeout)
+	defer cancel()
+	// The dump is written outside of the repository, which the indexer controls
+	f, err := ioutil.TempFile("", "dump-")
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		f.Close()
+		if cleanupErr := os.Remove(f.Name()); cleanupErr != nil {
+			log15.Warn("Failed to remove temporary file", "path", f.Name(), "err", cleanupErr)
+		}
+	}()
+
+	out, err := runIndexer(indexCtx, config, i.limits, name, f)
+	log = truncateLog(out)
+	if err != nil {
+		if indexCtx.Err() == context.DeadlineExceeded {
+			err = fmt.Errorf("indexer timed out after %s", i.timeout)
+		}
+		return log, errors.Wrap(err, "indexer failed")
+	}
+
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return log, err
+	}
+
+	return log, i.enqueue(ctx, index, f, log)
+}
+
+// enqueue inserts an upload for the given dump and sends the dump to the bundle manager, the
+// same way uploads from CI are handled, then marks the index as complete.
+func (i *Indexer) enqueue(ctx context.Context, index db.Index, dump io.Reader, log string) (err error) {
+	tx, err := i.db.Transact(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() { err = tx.Done(err) }()
+
+	uploadID, err := tx.Enqueue(ctx, index.Commit, "", "{}", index.RepositoryID, index.Indexer)
+	if err != nil {
+		return err
+	}
+
+	// Uploads are stored gzipped by the bundle manager
+	pr, pw := io.Pipe()
+	go func() {
+		gzipWriter := gzip.NewWriter(pw)
+		_, err := io.Copy(gzipWriter, dump)
+		if closeErr := gzipWriter.Close(); err == nil {
+			err = closeErr
+		}
+		pw.CloseWithError(err)
+	}()
+	err = i.bundleManagerClient.SendUpload(ctx, uploadID, pr)
+	pr.Close()
+	if err != nil {
+		return errors.Wrap(err, "failed to send upload")
+	}
+
+	return tx.MarkIndexComplete(ctx, index.ID, uploadID, log)
+}
+
+// findIndexer returns the indexer with the given name or nil if there is no such indexer.
+func findIndexer(indexers []*schema.CodeIntelIndexer, name string) *schema.CodeIntelIndexer {
+	for _, indexer := range indexers {
+		if indexer.Name == name {
+			return indexer
+		}
+	}
+	return nil
+}
+
+// MaxLogSize is the maximum number of bytes of indexer output stored with an index.
+const MaxLogSize = 64 * 1024
+
+// truncateLog returns the tail of the given indexer output, which is the part most
+// likely to explain a failure.
+func truncateLog(out []byte) string {
+	if len(out) > MaxLogSize {
+		out = append([]byte("... (truncated)\n"), out[len(out)-MaxLogSize:]...)
+	}
+	return string(out)
+}
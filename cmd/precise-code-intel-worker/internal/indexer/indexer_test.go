@@ -0,0 +1,368 @@
+package indexer
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	bundlemocks "github.com/sourcegraph/sourcegraph/internal/codeintel/bundles/mocks"
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/db"
+	dbmocks "github.com/sourcegraph/sourcegraph/internal/codeintel/db/mocks"
+	gitservermocks "github.com/sourcegraph/sourcegraph/internal/codeintel/gitserver/mocks"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestDequeueAndProcess(t *testing.T) {
+	fixture, err := filepath.Abs("../../testdata/dump.lsif")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedDump, err := ioutil.ReadFile(fixture)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	index := db.Index{
+		ID:           7,
+		Commit:       makeCommit(1),
+		RepositoryID: 50,
+		Indexer:      "lsif-fake",
+	}
+
+	mockDB := dbmocks.NewMockDB()
+	mockDB.DequeueIndexFunc.PushReturn(index, true, nil)
+	mockDB.TransactFunc.SetDefaultReturn(mockDB, nil)
+	mockDB.DoneFunc.SetDefaultHook(func(err error) error { return err })
+	mockDB.EnqueueFunc.SetDefaultReturn(42, nil)
+
+	gitserverClient := gitservermocks.NewMockClient()
+	gitserverClient.ArchiveFunc.SetDefaultHook(func(db db.DB, repositoryID int, commit string) (io.ReadCloser, error) {
+		return makeArchive(t, map[string]string{"main.go": "package main", "sub/sub.go": "package sub"}), nil
+	})
+
+	var sent []byte
+	bundleManagerClient := bundlemocks.NewMockBundleManagerClient()
+	bundleManagerClient.SendUploadFunc.SetDefaultHook(func(ctx context.Context, bundleID int, r io.Reader) error {
+		gzipReader, err := gzip.NewReader(r)
+		if err != nil {
+			return err
+		}
+		sent, err = ioutil.ReadAll(gzipReader)
+		return err
+	})
+
+	createArgs := useFakeDocker(t)
+
+	// The fake indexer emits the fixture dump if it is run in the root of the repository
+	indexer := New(IndexerOpts{
+		DB:                  mockDB,
+		BundleManagerClient: bundleManagerClient,
+		GitserverClient:     gitserverClient,
+		Timeout:             time.Minute,
+		Limits:              ResourceLimits{CPUs: "2", Memory: "4g"},
+		Indexers: func() []*schema.CodeIntelIndexer {
+			return []*schema.CodeIntelIndexer{{
+				Language: "Go",
+				Name:     "lsif-fake",
+				Image:    "fake-image",
+				Command:  []string{"sh", "-c", fmt.Sprintf("test -f main.go && test -f sub/sub.go && cp %q dump.lsif && echo indexed", fixture)},
+			}}
+		},
+	})
+
+	if ok, err := indexer.dequeueAndProcess(context.Background()); err != nil {
+		t.Fatalf("unexpected error processing index: %s", err)
+	} else if !ok {
+		t.Fatalf("expected an index to be processed")
+	}
+
+	if history := mockDB.MarkIndexErroredFunc.History(); len(history) != 0 {
+		t.Fatalf("unexpected error processing index: %s", history[0].Arg2)
+	}
+
+	if history := mockDB.EnqueueFunc.History(); len(history) != 1 {
+		t.Errorf("unexpected number of Enqueue calls. want=%d have=%d", 1, len(history))
+	} else if history[0].Arg1 != makeCommit(1) || history[0].Arg4 != 50 || history[0].Arg5 != "lsif-fake" {
+		t.Errorf("unexpected Enqueue args: commit=%s repositoryID=%d indexer=%s", history[0].Arg1, history[0].Arg4, history[0].Arg5)
+	}
+
+	if !bytes.Equal(sent, expectedDump) {
+		t.Errorf("unexpected upload sent to bundle manager")
+	}
+
+	if history := mockDB.MarkIndexCompleteFunc.History(); len(history) != 1 {
+		t.Errorf("unexpected number of MarkIndexComplete calls. want=%d have=%d", 1, len(history))
+	} else if history[0].Arg1 != 7 || history[0].Arg2 != 42 || history[0].Arg3 != "indexed\n" {
+		t.Errorf("unexpected MarkIndexComplete args: id=%d uploadID=%d log=%q", history[0].Arg1, history[0].Arg2, history[0].Arg3)
+	}
+
+	if history := mockDB.DoneFunc.History(); len(history) != 1 || history[0].Arg0 != nil {
+		t.Errorf("expected transaction to be committed")
+	}
+
+	args, err := ioutil.ReadFile(createArgs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, flag := range []string{"--network=none", "--cap-drop=all", "--cpus=2", "--memory=4g"} {
+		if !strings.Contains(string(args), flag) {
+			t.Errorf("expected indexer container to be created with %s, got args %q", flag, args)
+		}
+	}
+}
+
+func TestDequeueAndProcessIndexerFailure(t *testing.T) {
+	mockDB := dbmocks.NewMockDB()
+	mockDB.DequeueIndexFunc.PushReturn(db.Index{ID: 7, Commit: makeCommit(1), RepositoryID: 50, Indexer: "lsif-fake"}, true, nil)
+
+	gitserverClient := gitservermocks.NewMockClient()
+	gitserverClient.ArchiveFunc.SetDefaultHook(func(db db.DB, repositoryID int, commit string) (io.ReadCloser, error) {
+		return makeArchive(t, map[string]string{"main.go": "package main"}), nil
+	})
+
+	useFakeDocker(t)
+
+	indexer := New(IndexerOpts{
+		DB:                  mockDB,
+		BundleManagerClient: bundlemocks.NewMockBundleManagerClient(),
+		GitserverClient:     gitserverClient,
+		Timeout:             time.Minute,
+		Indexers: func() []*schema.CodeIntelIndexer {
+			return []*schema.CodeIntelIndexer{{
+				Language: "Go",
+				Name:     "lsif-fake",
+				Image:    "fake-image",
+				Command:  []string{"sh", "-c", "echo compilation failed; exit 1"},
+			}}
+		},
+	})
+
+	if ok, err := indexer.dequeueAndProcess(context.Background()); err != nil {
+		t.Fatalf("unexpected error processing index: %s", err)
+	} else if !ok {
+		t.Fatalf("expected an index to be processed")
+	}
+
+	if history := mockDB.MarkIndexErroredFunc.History(); len(history) != 1 {
+		t.Errorf("unexpected number of MarkIndexErrored calls. want=%d have=%d", 1, len(history))
+	} else if history[0].Arg1 != 7 || !strings.Contains(history[0].Arg2, "indexer failed") || history[0].Arg3 != "compilation failed\n" {
+		t.Errorf("unexpected MarkIndexErrored args: id=%d summary=%q log=%q", history[0].Arg1, history[0].Arg2, history[0].Arg3)
+	}
+
+	if history := mockDB.EnqueueFunc.History(); len(history) != 0 {
+		t.Errorf("unexpected number of Enqueue calls. want=%d have=%d", 0, len(history))
+	}
+}
+
+func TestDequeueAndProcessNoImage(t *testing.T) {
+	mockDB := dbmocks.NewMockDB()
+	mockDB.DequeueIndexFunc.PushReturn(db.Index{ID: 7, Commit: makeCommit(1), RepositoryID: 50, Indexer: "lsif-fake"}, true, nil)
+
+	gitserverClient := gitservermocks.NewMockClient()
+	gitserverClient.ArchiveFunc.SetDefaultHook(func(db db.DB, repositoryID int, commit string) (io.ReadCloser, error) {
+		return makeArchive(t, map[string]string{"main.go": "package main"}), nil
+	})
+
+	marker := filepath.Join(tmpDir(t), "ran")
+
+	// Indexers without an image must not run in the worker
+	indexer := New(IndexerOpts{
+		DB:                  mockDB,
+		BundleManagerClient: bundlemocks.NewMockBundleManagerClient(),
+		GitserverClient:     gitserverClient,
+		Timeout:             time.Minute,
+		Indexers: func() []*schema.CodeIntelIndexer {
+			return []*schema.CodeIntelIndexer{{
+				Language: "Go",
+				Name:     "lsif-fake",
+				Command:  []string{"touch", marker},
+			}}
+		},
+	})
+
+	if _, err := indexer.dequeueAndProcess(context.Background()); err != nil {
+		t.Fatalf("unexpected error processing index: %s", err)
+	}
+
+	if history := mockDB.MarkIndexErroredFunc.History(); len(history) != 1 || !strings.Contains(history[0].Arg2, "no image") {
+		t.Errorf("expected index to be marked errored for the missing image")
+	}
+	if _, err := os.Stat(marker); !os.IsNotExist(err) {
+		t.Errorf("expected indexer without an image not to run")
+	}
+}
+
+func TestDequeueAndProcessDumpSymlink(t *testing.T) {
+	mockDB := dbmocks.NewMockDB()
+	mockDB.DequeueIndexFunc.PushReturn(db.Index{ID: 7, Commit: makeCommit(1), RepositoryID: 50, Indexer: "lsif-fake"}, true, nil)
+
+	gitserverClient := gitservermocks.NewMockClient()
+	gitserverClient.ArchiveFunc.SetDefaultHook(func(db db.DB, repositoryID int, commit string) (io.ReadCloser, error) {
+		return makeArchive(t, map[string]string{"main.go": "package main"}), nil
+	})
+
+	secret := filepath.Join(tmpDir(t), "secret")
+	if err := ioutil.WriteFile(secret, []byte("worker credentials"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	useFakeDocker(t)
+
+	// The dump is a symlink to a file of the worker
+	indexer := New(IndexerOpts{
+		DB:                  mockDB,
+		BundleManagerClient: bundlemocks.NewMockBundleManagerClient(),
+		GitserverClient:     gitserverClient,
+		Timeout:             time.Minute,
+		Indexers: func() []*schema.CodeIntelIndexer {
+			return []*schema.CodeIntelIndexer{{
+				Language: "Go",
+				Name:     "lsif-fake",
+				Image:    "fake-image",
+				Command:  []string{"ln", "-s", secret, "dump.lsif"},
+			}}
+		},
+	})
+
+	if _, err := indexer.dequeueAndProcess(context.Background()); err != nil {
+		t.Fatalf("unexpected error processing index: %s", err)
+	}
+
+	if history := mockDB.MarkIndexErroredFunc.History(); len(history) != 1 || !strings.Contains(history[0].Arg2, "not a regular file") {
+		t.Errorf("expected index to be marked errored for the symlinked dump")
+	}
+	if history := mockDB.EnqueueFunc.History(); len(history) != 0 {
+		t.Errorf("unexpected number of Enqueue calls. want=%d have=%d", 0, len(history))
+	}
+}
+
+func TestCopyDumpFromTar(t *testing.T) {
+	var buf bytes.Buffer
+	if err := copyDumpFromTar(makeArchive(t, map[string]string{DumpFilename: "0123456789"}), 0, &buf); err != nil {
+		t.Fatalf("unexpected error copying dump: %s", err)
+	} else if buf.String() != "0123456789" {
+		t.Errorf("unexpected dump. want=%q have=%q", "0123456789", buf.String())
+	}
+
+	if err := copyDumpFromTar(makeArchive(t, map[string]string{DumpFilename: "0123456789"}), 5, ioutil.Discard); err == nil || !strings.Contains(err.Error(), "larger than the limit") {
+		t.Errorf("expected an error for a dump over the size limit, got %v", err)
+	}
+}
+
+func TestExtractTarIllegalPath(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if err := extractTar(makeArchive(t, map[string]string{"../escape": "x"}), dir); err == nil {
+		t.Fatalf("expected an error extracting a file outside of the target directory")
+	}
+}
+
+// fakeDocker is a stand-in for the Docker CLI which implements the subcommands used by
+// runIndexer with a directory per test as the container. Its first argument is the
+// directory.
+const fakeDocker = `#!/bin/sh
+set -e
+state=$1
+shift
+command=$1
+shift
+case $command in
+create)
+	echo "$@" > "$state/create-args"
+	while [ "$1" != fake-image ]; do shift; done
+	shift
+	i=0
+	for arg in "$@"; do
+		printf '%s' "$arg" > "$state/arg$i"
+		i=$((i+1))
+	done
+	mkdir "$state/data"
+	echo fake-container
+	;;
+cp)
+	case $1 in
+	fake-container:*) tar -C "$state/data" -cf - "${1#fake-container:/data/}" ;;
+	*) cp -R "$1" "$state/data" ;;
+	esac
+	;;
+start)
+	cd "$state/data"
+	set --
+	i=0
+	while [ -f "$state/arg$i" ]; do
+		set -- "$@" "$(cat "$state/arg$i")"
+		i=$((i+1))
+	done
+	exec "$@"
+	;;
+rm)
+	rm -rf "$state/data"
+	;;
+esac
+`
+
+// useFakeDocker replaces the Docker CLI with fakeDocker for the duration of the test and
+// returns the path of the file to which it writes the arguments of docker create.
+func useFakeDocker(t *testing.T) string {
+	state := tmpDir(t)
+	script := filepath.Join(state, "docker")
+	if err := ioutil.WriteFile(script, []byte(fakeDocker), 0700); err != nil {
+		t.Fatal(err)
+	}
+	wrapper := filepath.Join(state, "docker-wrapper")
+	if err := ioutil.WriteFile(wrapper, []byte(fmt.Sprintf("#!/bin/sh\nexec %q %q \"$@\"\n", script, state)), 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	old := docker
+	docker = wrapper
+	t.Cleanup(func() { docker = old })
+	return filepath.Join(state, "create-args")
+}
+
+// tmpDir returns a temporary directory which is removed at the end of the test.
+func tmpDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	return dir
+}
+
+// makeCommit formats an integer as a 40-character git commit hash.
+func makeCommit(i int) string {
+	return fmt.Sprintf("%040d", i)
+}
+
+// makeArchive returns a tar archive with the given files.
+func makeArchive(t *testing.T, files map[string]string) io.ReadCloser {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for name, content := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return ioutil.NopCloser(&buf)
+}
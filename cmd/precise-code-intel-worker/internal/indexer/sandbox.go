@@ -0,0 +1,216 @@
+package indexer
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/inconshreveable/log15"
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// docker is the Docker CLI, which runs the indexers.
+var docker = "docker"
+
+// containerDir is the directory of the indexer container to which the repository is
+// copied, and in which the indexer is run.
+const containerDir = "/data"
+
+// ResourceLimits are the resources an indexer container may use. CPUs and Memory are in
+// the format of the --cpus and --memory flags of docker run, and DumpSize is the maximum
+// size of the dump in bytes. Zero values mean no limit.
+type ResourceLimits struct {
+	CPUs     string
+	Memory   string
+	DumpSize int64
+}
+
+// runIndexer runs the given indexer in the repository checked out at dir and writes the
+// dump it creates to dump. This method returns the output of the indexer.
+//
+// 🚨 SECURITY: Indexers run arbitrary build tooling of the repository being indexed. They
+// must not see the environment or files of the worker, which contain credentials (such
+// as the Postgres DSN), and must not reach internal services. Indexers therefore only run
+// in a container without network access and capabilities. The repository is copied into
+// the container rather than bind-mounted, as paths of the worker don't exist on the host
+// of the Docker daemon. The dump is streamed out of the container and must be a regular
+// file, so that a symlink written by the indexer can't make the worker read its own files.
+func runIndexer(ctx context.Context, config *schema.CodeIntelIndexer, limits ResourceLimits, dir string, dump io.Writer) ([]byte, error) {
+	if config.Image == "" {
+		return nil, fmt.Errorf("indexer %q has no image", config.Name)
+	}
+
+	args := []string{
+		"create",
+		"--network=none",
+		"--cap-drop=all",
+		"--security-opt=no-new-privileges",
+		"-w", containerDir,
+	}
+	if limits.CPUs != "" {
+		args = append(args, "--cpus="+limits.CPUs)
+	}
+	if limits.Memory != "" {
+		// Setting the swap limit to the memory limit disables swap.
+		args = append(args, "--memory="+limits.Memory, "--memory-swap="+limits.Memory)
+	}
+	args = append(args, config.Image)
+	out, err := dockerCommand(ctx, append(args, config.Command...)...).Output()
+	if err != nil {
+		return nil, errors.Wrap(dockerError(err), "failed to create indexer container")
+	}
+	container := strings.TrimSpace(string(out))
+	defer func() {
+		// The container keeps running if ctx is done, so it is removed with a fresh context
+		removeCtx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		defer cancel()
+		if err := dockerCommand(removeCtx, "rm", "-f", container).Run(); err != nil {
+			log15.Warn("Failed to remove indexer container", "container", container, "err", dockerError(err))
+		}
+	}()
+
+	if _, err := dockerCommand(ctx, "cp", dir+"/.", container+":"+containerDir).Output(); err != nil {
+		return nil, errors.Wrap(dockerError(err), "failed to copy repository into indexer container")
+	}
+
+	log, err := dockerCommand(ctx, "start", "--attach", container).CombinedOutput()
+	if err != nil {
+		return log, err
+	}
+
+	if err := copyDump(ctx, container, limits.DumpSize, dump); err != nil {
+		return log, err
+	}
+	return log, nil
+}
+
+// copyDump writes the dump of the given container to w. It fails if the dump is not a
+// regular file or is larger than maxSize bytes, unless maxSize is zero.
+func copyDump(ctx context.Context, container string, maxSize int64, w io.Writer) error {
+	// docker cp writes a tar archive of the path to stdout when the destination is "-"
+	cmd := dockerCommand(ctx, "cp", container+":"+path.Join(containerDir, DumpFilename), "-")
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	copyErr := copyDumpFromTar(stdout, maxSize, w)
+	// Drain the output so that docker cp doesn't block writing it
+	_, _ = io.Copy(ioutil.Discard, stdout)
+	if err := cmd.Wait(); err != nil {
+		if stderr.Len() > 0 {
+			err = fmt.Errorf("%s: %s", err, bytes.TrimSpace(stderr.Bytes()))
+		}
+		return errors.Wrapf(err, "indexer did not write %s", DumpFilename)
+	}
+	return copyErr
+}
+
+// copyDumpFromTar writes the contents of the single entry of the given tar archive to w.
+//
+// 🚨 SECURITY: The indexer controls the dump, so only a regular file is accepted. Symlinks
+// and other entries are rejected, as they would be resolved on the worker's filesystem.
+func copyDumpFromTar(r io.Reader, maxSize int64, w io.Writer) error {
+	tr := tar.NewReader(r)
+	header, err := tr.Next()
+	if err != nil {
+		return errors.Wrapf(err, "failed to read %s", DumpFilename)
+	}
+	if header.Typeflag != tar.TypeReg {
+		return fmt.Errorf("%s is not a regular file", DumpFilename)
+	}
+	if maxSize > 0 && header.Size > maxSize {
+		return fmt.Errorf("%s is larger than the limit of %d bytes", DumpFilename, maxSize)
+	}
+
+	_, err = io.Copy(w, tr)
+	return err
+}
+
+// dockerCommand returns a command which runs the Docker CLI with the given arguments. The
+// CLI only gets the variables of the worker's environment which configure it.
+func dockerCommand(ctx context.Context, args ...string) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, docker, args...)
+	cmd.Env = []string{"PATH=" + os.Getenv("PATH")}
+	for _, kv := range os.Environ() {
+		if strings.HasPrefix(kv, "DOCKER_") {
+			cmd.Env = append(cmd.Env, kv)
+		}
+	}
+	return cmd
+}
+
+// dockerError adds the stderr output of a failed Docker CLI command to err.
+func dockerError(err error) error {
+	if exitErr, ok := err.(*exec.ExitError); ok && len(exitErr.Stderr) > 0 {
+		return fmt.Errorf("%s: %s", err, bytes.TrimSpace(exitErr.Stderr))
+	}
+	return err
+}
+
+// extractTar writes the files and directories of the given tar archive into dir. Other
+// entries, such as symlinks, are skipped.
+func extractTar(r io.Reader, dir string) error {
+	tr := tar.NewReader(r)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		// 🚨 SECURITY: Archives come from arbitrary repositories, so we must not write
+		// outside of dir. This is also why symlinks are not extracted, as later entries
+		// could be written through them.
+		path := filepath.Join(dir, header.Name)
+		if path != filepath.Clean(dir) && !strings.HasPrefix(path, filepath.Clean(dir)+string(os.PathSeparator)) {
+			return fmt.Errorf("illegal file path in archive: %q", header.Name)
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(path, 0700); err != nil {
+				return err
+			}
+
+		case tar.TypeReg:
+			if err := writeFile(path, tr, os.FileMode(header.Mode)&0700|0600); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// writeFile writes the contents of r to a new file at path.
+func writeFile(path string, r io.Reader, mode os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, mode)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/hashicorp/go-multierror"
 	"github.com/inconshreveable/log15"
 	"github.com/sourcegraph/sourcegraph/cmd/precise-code-intel-worker/internal/correlation"
+	"github.com/sourcegraph/sourcegraph/cmd/precise-code-intel-worker/internal/correlation/spill"
 	"github.com/sourcegraph/sourcegraph/cmd/precise-code-intel-worker/internal/existence"
 	bundles "github.com/sourcegraph/sourcegraph/internal/codeintel/bundles/client"
 	"github.com/sourcegraph/sourcegraph/internal/codeintel/bundles/serializer"
@@ -25,6 +26,8 @@ type WorkerOpts struct {
 	BundleManagerClient bundles.BundleManagerClient
 	GitserverClient     gitserver.Client
 	PollInterval        time.Duration
+	MemoryLimit         int64 // bytes of correlation data held in memory before spilling to disk; zero is unbounded
+	StrictValidation    bool  // reject uploads with validation warnings as well as errors
 }
 
 type Worker struct {
@@ -32,6 +35,8 @@ type Worker struct {
 	bundleManagerClient bundles.BundleManagerClient
 	gitserverClient     gitserver.Client
 	pollInterval        time.Duration
+	memoryLimit         int64
+	strictValidation    bool
 }
 
 func New(opts WorkerOpts) *Worker {
@@ -40,6 +45,8 @@ func New(opts WorkerOpts) *Worker {
 		bundleManagerClient: opts.BundleManagerClient,
 		gitserverClient:     opts.GitserverClient,
 		pollInterval:        opts.PollInterval,
+		memoryLimit:         opts.MemoryLimit,
+		strictValidation:    opts.StrictValidation,
 	}
 }
 
@@ -68,7 +75,7 @@ func (w *Worker) dequeueAndProcess(ctx context.Context) (_ bool, err error) {
 		}
 	}()
 
-	if err = process(ctx, jobHandle.DB(), w.bundleManagerClient, w.gitserverClient, upload, jobHandle); err != nil {
+	if err = process(ctx, jobHandle.DB(), w.bundleManagerClient, w.gitserverClient, upload, jobHandle, w.memoryLimit, w.strictValidation); err != nil {
 		log15.Warn("Failed to process upload", "id", upload.ID, "err", err)
 
 		if markErr := jobHandle.MarkErrored(ctx, err.Error(), ""); markErr != nil {
@@ -87,6 +94,8 @@ func process(
 	gitserverClient gitserver.Client,
 	upload db.Upload,
 	jobHandle db.JobHandle,
+	memoryLimit int64,
+	strictValidation bool,
 ) (err error) {
 	// Create scratch directory that we can clean on completion/failure
 	name, err := ioutil.TempDir("", "")
@@ -114,7 +123,8 @@ func process(
 
 	// Read raw upload and write converted database to newFilename. This process also correlates
 	// and returns the  data we need to insert into Postgres to support cross-dump/repo queries.
-	packages, packageReferences, err := convert(
+	// Correlation data that does not fit within the memory limit is spilled to the scratch directory.
+	packages, packageReferences, diagnostics, err := convert(
 		ctx,
 		filename,
 		newFilename,
@@ -123,7 +133,15 @@ func process(
 		func(dirnames []string) (map[string][]string, error) {
 			return gitserverClient.DirectoryChildren(db, upload.RepositoryID, upload.Commit, dirnames)
 		},
+		spill.NewBudget(name, memoryLimit),
+		strictValidation,
 	)
+
+	// Record the problems found while correlating the upload. These are written outside of the
+	// savepoint below so that they are kept when the upload is rejected.
+	if diagnosticsErr := db.UpdateDiagnostics(ctx, upload.ID, diagnostics); diagnosticsErr != nil {
+		return multierror.Append(err, diagnosticsErr)
+	}
 	if err != nil {
 		return err
 	}
@@ -225,7 +243,8 @@ func updateCommitsAndVisibility(ctx context.Context, db db.DB, gitserverClient g
 	return nil
 }
 
-// convert correlates the raw input data and commits the correlated data to disk.
+// convert correlates the raw input data and commits the correlated data to disk. The diagnostics
+// of the upload are returned even if the upload is rejected as invalid.
 func convert(
 	ctx context.Context,
 	filename string,
@@ -233,21 +252,49 @@ func convert(
 	dumpID int,
 	root string,
 	getChildren existence.GetChildrenFunc,
-) (_ []types.Package, _ []types.PackageReference, err error) {
-	groupedBundleData, err := correlation.Correlate(filename, dumpID, root, getChildren)
+	budget *spill.Budget,
+	strictValidation bool,
+) (_ []types.Package, _ []types.PackageReference, _ []db.UploadDiagnostic, err error) {
+	groupedBundleData, err := correlation.Correlate(filename, dumpID, root, getChildren, budget, strictValidation)
 	if err != nil {
-		return nil, nil, err
+		if invalidErr, ok := err.(correlation.ErrInvalidDump); ok {
+			return nil, nil, convertDiagnostics(invalidErr.Diagnostics), err
+		}
+
+		return nil, nil, nil, err
 	}
+	defer func() {
+		if closeErr := groupedBundleData.Close(); closeErr != nil {
+			err = multierror.Append(err, closeErr)
+		}
+	}()
 
 	if err := write(ctx, newFilename, groupedBundleData); err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 
-	return groupedBundleData.Packages, groupedBundleData.PackageReferences, nil
+	return groupedBundleData.Packages, groupedBundleData.PackageReferences, convertDiagnostics(groupedBundleData.Diagnostics), nil
 }
 
-// write commits the correlated data to disk.
-func write(ctx context.Context, filename string, groupedBundleData *correlation.GroupedBundleData) error {
+// convertDiagnostics converts correlation diagnostics into their database representation.
+func convertDiagnostics(diagnostics []correlation.Diagnostic) []db.UploadDiagnostic {
+	converted := make([]db.UploadDiagnostic, 0, len(diagnostics))
+	for _, diagnostic := range diagnostics {
+		converted = append(converted, db.UploadDiagnostic{
+			Severity:  diagnostic.Severity,
+			Code:      diagnostic.Code,
+			Message:   diagnostic.Message,
+			ElementID: diagnostic.ElementID,
+			Line:      diagnostic.Line,
+		})
+	}
+
+	return converted
+}
+
+// write commits the correlated data to disk. Documents and result chunks are serialized and
+// written in batches.
+func write(ctx context.Context, filename string, groupedBundleData *correlation.GroupedBundleData) (err error) {
 	writer, err := writer.NewSQLiteWriter(filename, serializer.NewDefaultSerializer())
 	if err != nil {
 		return err
@@ -262,8 +309,16 @@ func write(ctx context.Context, filename string, groupedBundleData *correlation.
 		func() error {
 			return writer.WriteMeta(ctx, groupedBundleData.LSIFVersion, groupedBundleData.NumResultChunks)
 		},
-		func() error { return writer.WriteDocuments(ctx, groupedBundleData.Documents) },
-		func() error { return writer.WriteResultChunks(ctx, groupedBundleData.ResultChunks) },
+		func() error {
+			return groupedBundleData.Documents(func(documents map[string]types.DocumentData) error {
+				return writer.WriteDocuments(ctx, documents)
+			})
+		},
+		func() error {
+			return groupedBundleData.ResultChunks(func(resultChunks map[int]types.ResultChunkData) error {
+				return writer.WriteResultChunks(ctx, resultChunks)
+			})
+		},
 		func() error { return writer.WriteDefinitions(ctx, groupedBundleData.Definitions) },
 		func() error { return writer.WriteReferences(ctx, groupedBundleData.References) },
 		func() error { return writer.Flush(ctx) },
@@ -66,7 +66,7 @@ func TestProcess(t *testing.T) {
 		return commits, nil
 	})
 
-	err := process(context.Background(), mockDB, bundleManagerClient, gitserverClient, upload, jobHandle)
+	err := process(context.Background(), mockDB, bundleManagerClient, gitserverClient, upload, jobHandle, 0, false)
 	if err != nil {
 		t.Fatalf("unexpected error processing upload: %s", err)
 	}
@@ -90,10 +90,11 @@ func TestProcess(t *testing.T) {
 	}
 	expectedPackageReferences := []types.PackageReference{
 		{DumpID: 42,
-			Scheme:  "scheme A",
-			Name:    "pkg A",
-			Version: "v0.1.0",
-			Filter:  filter,
+			Scheme:      "scheme A",
+			Name:        "pkg A",
+			Version:     "v0.1.0",
+			Filter:      filter,
+			Identifiers: []string{"ident A"},
 		},
 	}
 	if len(mockDB.UpdatePackageReferencesFunc.History()) != 1 {
@@ -135,6 +136,12 @@ func TestProcess(t *testing.T) {
 		t.Errorf("unexpected value for tip commit. want=%s have=%s", makeCommit(30), mockDB.UpdateDumpsVisibleFromTipFunc.History()[0].Arg2)
 	}
 
+	if len(mockDB.UpdateDiagnosticsFunc.History()) != 1 {
+		t.Errorf("unexpected number of UpdateDiagnosticsFunc calls. want=%d have=%d", 1, len(mockDB.UpdateDiagnosticsFunc.History()))
+	} else if mockDB.UpdateDiagnosticsFunc.History()[0].Arg1 != 42 {
+		t.Errorf("unexpected value for upload id. want=%d have=%d", 42, mockDB.UpdateDiagnosticsFunc.History()[0].Arg1)
+	}
+
 	if len(bundleManagerClient.SendDBFunc.History()) != 1 {
 		t.Errorf("unexpected number of SendDBFunc calls. want=%d have=%d", 1, len(bundleManagerClient.SendDBFunc.History()))
 	} else if bundleManagerClient.SendDBFunc.History()[0].Arg1 != 42 {
@@ -162,7 +169,7 @@ func TestProcessError(t *testing.T) {
 	// Set a different tip commit
 	gitserverClient.HeadFunc.SetDefaultReturn("", fmt.Errorf("uh-oh!"))
 
-	err := process(context.Background(), mockDB, bundleManagerClient, gitserverClient, upload, jobHandle)
+	err := process(context.Background(), mockDB, bundleManagerClient, gitserverClient, upload, jobHandle, 0, false)
 	if err == nil {
 		t.Fatalf("unexpected nil error processing upload")
 	} else if !strings.Contains(err.Error(), "uh-oh!") {
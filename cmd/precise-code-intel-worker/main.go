@@ -6,15 +6,19 @@ import (
 	"os/signal"
 	"syscall"
 
+	"github.com/sourcegraph/sourcegraph/cmd/precise-code-intel-worker/internal/backfiller"
+	"github.com/sourcegraph/sourcegraph/cmd/precise-code-intel-worker/internal/indexer"
 	"github.com/sourcegraph/sourcegraph/cmd/precise-code-intel-worker/internal/worker"
 	bundles "github.com/sourcegraph/sourcegraph/internal/codeintel/bundles/client"
 	"github.com/sourcegraph/sourcegraph/internal/codeintel/db"
 	"github.com/sourcegraph/sourcegraph/internal/codeintel/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/storage"
 	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/debugserver"
 	"github.com/sourcegraph/sourcegraph/internal/env"
 	"github.com/sourcegraph/sourcegraph/internal/sqliteutil"
 	"github.com/sourcegraph/sourcegraph/internal/tracer"
+	"github.com/sourcegraph/sourcegraph/schema"
 )
 
 func main() {
@@ -27,18 +31,54 @@ func main() {
 	var (
 		pollInterval     = mustParseInterval(rawPollInterval, "PRECISE_CODE_INTEL_POLL_INTERVAL")
 		bundleManagerURL = mustGet(rawBundleManagerURL, "PRECISE_CODE_INTEL_BUNDLE_MANAGER_URL")
+		indexerTimeout   = mustParseInterval(rawIndexerTimeout, "PRECISE_CODE_INTEL_INDEXER_TIMEOUT")
+		memoryMB         = mustParseNonNegativeInt(rawMemoryMB, "PRECISE_CODE_INTEL_CORRELATION_MEMORY_LIMIT_MB")
+		indexerDumpMB    = mustParseNonNegativeInt(rawIndexerDumpMB, "PRECISE_CODE_INTEL_INDEXER_MAX_DUMP_SIZE_MB")
+		backfillInterval = mustParseInterval(rawBackfillInterval, "PRECISE_CODE_INTEL_BACKFILL_INTERVAL")
+		strictValidation = mustParseBool(rawStrictValidation, "PRECISE_CODE_INTEL_STRICT_VALIDATION")
 	)
 
 	db := mustInitializeDatabase()
+	bundleManagerClient := mustInitializeBundleManagerClient(bundleManagerURL)
 
 	workerImpl := worker.New(worker.WorkerOpts{
 		DB:                  db,
-		BundleManagerClient: bundles.New(bundleManagerURL),
+		BundleManagerClient: bundleManagerClient,
 		GitserverClient:     gitserver.DefaultClient,
 		PollInterval:        pollInterval,
+		MemoryLimit:         memoryMB * 1024 * 1024,
+		StrictValidation:    strictValidation,
+	})
+
+	indexerImpl := indexer.New(indexer.IndexerOpts{
+		DB:                  db,
+		BundleManagerClient: bundleManagerClient,
+		GitserverClient:     gitserver.DefaultClient,
+		PollInterval:        pollInterval,
+		Timeout:             indexerTimeout,
+		Limits: indexer.ResourceLimits{
+			CPUs:     rawIndexerCPUs,
+			Memory:   rawIndexerMemory,
+			DumpSize: indexerDumpMB * 1024 * 1024,
+		},
+		Indexers: func() []*schema.CodeIntelIndexer {
+			if c := conf.Get().CodeIntelAutoIndexing; c != nil {
+				return c.Indexers
+			}
+			return nil
+		},
+	})
+
+	backfillerImpl := backfiller.New(backfiller.BackfillerOpts{
+		DB:                  db,
+		BundleManagerClient: bundleManagerClient,
+		Interval:            backfillInterval,
+		BatchSize:           100,
 	})
 
 	go func() { _ = workerImpl.Start() }()
+	go func() { _ = indexerImpl.Start() }()
+	go func() { _ = backfillerImpl.Start() }()
 	go debugserver.Start()
 	waitForSignal()
 }
@@ -59,6 +99,29 @@ func mustInitializeDatabase() db.DB {
 	return db
 }
 
+// mustInitializeBundleManagerClient returns a client of the bundle manager. When the bundle
+// manager uses object storage, uploads and converted bundles are transferred to the bucket
+// directly rather than through the bundle manager. The bundle manager's local disk is not
+// shared with the worker, so local storage is always accessed through the bundle manager.
+func mustInitializeBundleManagerClient(bundleManagerURL string) bundles.BundleManagerClient {
+	if rawStorageBackend != "s3" {
+		return bundles.New(bundleManagerURL)
+	}
+
+	store, err := storage.NewS3(storage.S3Config{
+		Bucket:          rawS3Bucket,
+		Region:          rawS3Region,
+		Endpoint:        rawS3Endpoint,
+		AccessKeyID:     rawS3AccessKeyID,
+		SecretAccessKey: rawS3SecretAccessKey,
+	})
+	if err != nil {
+		log.Fatalf("failed to initialize storage: %s", err)
+	}
+
+	return bundles.NewWithStore(bundleManagerURL, store)
+}
+
 func waitForSignal() {
 	signals := make(chan os.Signal, 2)
 	signal.Notify(signals, syscall.SIGINT, syscall.SIGHUP)
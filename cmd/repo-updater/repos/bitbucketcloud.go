@@ -24,6 +24,8 @@ type BitbucketCloudSource struct {
 	config  *schema.BitbucketCloudConnection
 	exclude excludeFunc
 	client  *bitbucketcloud.Client
+
+	cloneStrategy cloneStrategyFunc
 }
 
 // NewBitbucketCloudSource returns a new BitbucketCloudSource from the given external service.
@@ -65,15 +67,25 @@ func newBitbucketCloudSource(svc *ExternalService, c *schema.BitbucketCloudConne
 		return nil, err
 	}
 
+	var cb cloneStrategyBuilder
+	for _, cs := range c.CloneStrategies {
+		cb.Add(cs.Pattern, cs.Mode, cs.Depth, cs.Since)
+	}
+	cloneStrategy, err := cb.Build()
+	if err != nil {
+		return nil, err
+	}
+
 	client := bitbucketcloud.NewClient(apiURL, cli)
 	client.Username = c.Username
 	client.AppPassword = c.AppPassword
 
 	return &BitbucketCloudSource{
-		svc:     svc,
-		config:  c,
-		exclude: exclude,
-		client:  client,
+		svc:           svc,
+		config:        c,
+		exclude:       exclude,
+		client:        client,
+		cloneStrategy: cloneStrategy,
 	}, nil
 }
 
@@ -97,12 +109,13 @@ func (s BitbucketCloudSource) makeRepo(r *bitbucketcloud.Repo) *Repo {
 	host = extsvc.NormalizeBaseURL(host)
 
 	urn := s.svc.URN()
+	name := string(reposource.BitbucketCloudRepoName(
+		s.config.RepositoryPathPattern,
+		host.Hostname(),
+		r.FullName,
+	))
 	return &Repo{
-		Name: string(reposource.BitbucketCloudRepoName(
-			s.config.RepositoryPathPattern,
-			host.Hostname(),
-			r.FullName,
-		)),
+		Name: name,
 		URI: string(reposource.BitbucketCloudRepoName(
 			"",
 			host.Hostname(),
@@ -120,6 +133,9 @@ func (s BitbucketCloudSource) makeRepo(r *bitbucketcloud.Repo) *Repo {
 			urn: {
 				ID:       urn,
 				CloneURL: s.authenticatedRemoteURL(r),
+				LFS:      s.config.GitLFS,
+				LFSRefs:  s.config.GitLFSRefs,
+				Clone:    s.cloneStrategy(name),
 			},
 		},
 		Metadata: r,
@@ -29,6 +29,8 @@ type BitbucketServerSource struct {
 	exclude excludeFunc
 	client  *bitbucketserver.Client
 
+	cloneStrategy cloneStrategyFunc
+
 	// rateLimiter should be used to limit requests made to the external service
 	rateLimiter *rate.Limiter
 }
@@ -72,17 +74,27 @@ func newBitbucketServerSource(svc *ExternalService, c *schema.BitbucketServerCon
 		return nil, err
 	}
 
+	var cb cloneStrategyBuilder
+	for _, cs := range c.CloneStrategies {
+		cb.Add(cs.Pattern, cs.Mode, cs.Depth, cs.Since)
+	}
+	cloneStrategy, err := cb.Build()
+	if err != nil {
+		return nil, err
+	}
+
 	client, err := bitbucketserver.NewClient(c, cli)
 	if err != nil {
 		return nil, err
 	}
 
 	return &BitbucketServerSource{
-		svc:         svc,
-		config:      c,
-		exclude:     exclude,
-		client:      client,
-		rateLimiter: rl,
+		svc:           svc,
+		config:        c,
+		exclude:       exclude,
+		client:        client,
+		rateLimiter:   rl,
+		cloneStrategy: cloneStrategy,
 	}, nil
 }
 
@@ -297,14 +309,15 @@ func (s BitbucketServerSource) makeRepo(repo *bitbucketserver.Repo, isArchived b
 	}
 
 	urn := s.svc.URN()
+	name := string(reposource.BitbucketServerRepoName(
+		s.config.RepositoryPathPattern,
+		host.Hostname(),
+		project,
+		repo.Slug,
+	))
 
 	return &Repo{
-		Name: string(reposource.BitbucketServerRepoName(
-			s.config.RepositoryPathPattern,
-			host.Hostname(),
-			project,
-			repo.Slug,
-		)),
+		Name: name,
 		URI: string(reposource.BitbucketServerRepoName(
 			"",
 			host.Hostname(),
@@ -324,6 +337,9 @@ func (s BitbucketServerSource) makeRepo(repo *bitbucketserver.Repo, isArchived b
 			urn: {
 				ID:       urn,
 				CloneURL: cloneURL,
+				LFS:      s.config.GitLFS,
+				LFSRefs:  s.config.GitLFSRefs,
+				Clone:    s.cloneStrategy(name),
 			},
 		},
 		Metadata: repo,
@@ -0,0 +1,52 @@
+package repos
+
+import (
+	"regexp"
+
+	gitserverprotocol "github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
+)
+
+// cloneStrategyFunc takes a repository name and returns how gitserver should
+// clone it, or nil to clone its whole history.
+type cloneStrategyFunc func(name string) *gitserverprotocol.CloneStrategy
+
+// cloneStrategyBuilder builds a cloneStrategyFunc from the cloneStrategies of
+// a code host connection.
+type cloneStrategyBuilder struct {
+	patterns   []*regexp.Regexp
+	strategies []*gitserverprotocol.CloneStrategy
+
+	err error
+}
+
+// Add adds the strategy of cloning repositories whose name matches pattern in
+// mode ("full", "shallow" or "blobless"). Earlier strategies take precedence.
+func (b *cloneStrategyBuilder) Add(pattern, mode string, depth int, since string) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		b.err = err
+		return
+	}
+
+	var strategy *gitserverprotocol.CloneStrategy
+	switch mode {
+	case gitserverprotocol.CloneModeShallow:
+		strategy = &gitserverprotocol.CloneStrategy{Mode: mode, Depth: depth, Since: since}
+	case gitserverprotocol.CloneModeBlobless:
+		strategy = &gitserverprotocol.CloneStrategy{Mode: mode}
+	}
+	b.patterns = append(b.patterns, re)
+	b.strategies = append(b.strategies, strategy)
+}
+
+// Build will return a cloneStrategyFunc based on the previous calls to Add.
+func (b *cloneStrategyBuilder) Build() (cloneStrategyFunc, error) {
+	return func(name string) *gitserverprotocol.CloneStrategy {
+		for i, re := range b.patterns {
+			if re.MatchString(name) {
+				return b.strategies[i]
+			}
+		}
+		return nil
+	}, b.err
+}
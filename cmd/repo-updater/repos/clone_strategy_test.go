@@ -0,0 +1,58 @@
+package repos
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	gitserverprotocol "github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestCloneStrategyBuilder(t *testing.T) {
+	var b cloneStrategyBuilder
+	for _, cs := range []*schema.GitHubCloneStrategy{
+		{Pattern: "^github.com/a/full$", Mode: "full"},
+		{Pattern: "^github.com/a/", Mode: "shallow", Depth: 100},
+		{Pattern: "^github.com/b/", Mode: "blobless", Depth: 100},
+	} {
+		b.Add(cs.Pattern, cs.Mode, cs.Depth, cs.Since)
+	}
+	cloneStrategy, err := b.Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, want := range map[string]*gitserverprotocol.CloneStrategy{
+		"github.com/a/full": nil,
+		"github.com/a/big":  {Mode: "shallow", Depth: 100},
+		"github.com/b/big":  {Mode: "blobless"},
+		"github.com/c/d":    nil,
+	} {
+		if diff := cmp.Diff(want, cloneStrategy(name)); diff != "" {
+			t.Errorf("unexpected clone strategy of %s (-want +got):\n%s", name, diff)
+		}
+	}
+
+	b.Add("(", "shallow", 0, "")
+	if _, err := b.Build(); err == nil {
+		t.Error("expected an error for an invalid pattern")
+	}
+}
+
+func TestRepo_CloneStrategy(t *testing.T) {
+	shallow := &gitserverprotocol.CloneStrategy{Mode: "shallow", Depth: 1}
+	blobless := &gitserverprotocol.CloneStrategy{Mode: "blobless"}
+
+	r := &Repo{Sources: map[string]*SourceInfo{
+		"extsvc:github:2": {ID: "extsvc:github:2", Clone: blobless},
+		"extsvc:github:1": {ID: "extsvc:github:1", Clone: shallow},
+		"extsvc:github:0": {ID: "extsvc:github:0"},
+	}}
+	if diff := cmp.Diff(shallow, r.CloneStrategy()); diff != "" {
+		t.Errorf("unexpected clone strategy (-want +got):\n%s", diff)
+	}
+
+	if got := (&Repo{}).CloneStrategy(); got != nil {
+		t.Errorf("got clone strategy %+v for a repo without sources, want nil", got)
+	}
+}
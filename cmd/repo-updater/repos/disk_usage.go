@@ -0,0 +1,72 @@
+package repos
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	multierror "github.com/hashicorp/go-multierror"
+	"github.com/inconshreveable/log15"
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
+)
+
+// A DiskUsageStore records the disk usage of repos on gitserver.
+type DiskUsageStore interface {
+	ReplaceShardDiskUsage(ctx context.Context, shard string, usage []*RepoDiskUsage) error
+	DeleteDiskUsageOfOtherShards(ctx context.Context, shards []string) error
+}
+
+// RunRepoDiskUsageSyncWorker periodically records the disk usage of the repos on every
+// gitserver shard in store, so that site admins can see which repos take up space.
+func RunRepoDiskUsageSyncWorker(ctx context.Context, store DiskUsageStore, interval time.Duration) {
+	for {
+		if err := syncRepoDiskUsage(ctx, store); err != nil {
+			log15.Error("failed to sync repo disk usage", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+	}
+}
+
+// syncRepoDiskUsage records the disk usage reported by every gitserver shard in store. The
+// recorded disk usage of shards which fail to respond is kept.
+func syncRepoDiskUsage(ctx context.Context, store DiskUsageStore) error {
+	shards := gitserverAddrs(ctx)
+	usage, err := gitserverDiskUsage(ctx)
+	if err != nil {
+		err = errors.Wrap(err, "getting disk usage from gitserver")
+	}
+
+	for shard, u := range usage {
+		records := make([]*RepoDiskUsage, 0, len(u.Repos))
+		for name, size := range u.Repos {
+			records = append(records, &RepoDiskUsage{Name: name, SizeBytes: size})
+		}
+		sort.Slice(records, func(i, j int) bool { return records[i].Name < records[j].Name })
+
+		if e := store.ReplaceShardDiskUsage(ctx, shard, records); e != nil {
+			err = multierror.Append(err, errors.Wrapf(e, "recording disk usage of %s", shard))
+		}
+	}
+
+	if e := store.DeleteDiskUsageOfOtherShards(ctx, shards); e != nil {
+		err = multierror.Append(err, errors.Wrap(e, "deleting disk usage of removed shards"))
+	}
+	return err
+}
+
+// These are mocked in tests.
+var (
+	gitserverAddrs = func(ctx context.Context) []string {
+		return gitserver.DefaultClient.Addrs(ctx)
+	}
+	gitserverDiskUsage = func(ctx context.Context) (map[string]*protocol.DiskUsageResponse, error) {
+		return gitserver.DefaultClient.DiskUsage(ctx)
+	}
+)
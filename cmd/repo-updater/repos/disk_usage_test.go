@@ -0,0 +1,56 @@
+package repos
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
+)
+
+type fakeDiskUsageStore struct {
+	usage      map[string][]*RepoDiskUsage
+	keptShards []string
+}
+
+func (s *fakeDiskUsageStore) ReplaceShardDiskUsage(ctx context.Context, shard string, usage []*RepoDiskUsage) error {
+	s.usage[shard] = usage
+	return nil
+}
+
+func (s *fakeDiskUsageStore) DeleteDiskUsageOfOtherShards(ctx context.Context, shards []string) error {
+	s.keptShards = shards
+	return nil
+}
+
+func TestSyncRepoDiskUsage(t *testing.T) {
+	origAddrs, origDiskUsage := gitserverAddrs, gitserverDiskUsage
+	defer func() { gitserverAddrs, gitserverDiskUsage = origAddrs, origDiskUsage }()
+
+	gitserverAddrs = func(context.Context) []string {
+		return []string{"gitserver-0", "gitserver-1"}
+	}
+	gitserverDiskUsage = func(context.Context) (map[string]*protocol.DiskUsageResponse, error) {
+		// gitserver-1 is down.
+		return map[string]*protocol.DiskUsageResponse{
+			"gitserver-0": {Repos: map[api.RepoName]int64{"b": 2, "a": 1}},
+		}, errors.New("gitserver-1 is down")
+	}
+
+	store := &fakeDiskUsageStore{usage: map[string][]*RepoDiskUsage{}}
+	if err := syncRepoDiskUsage(context.Background(), store); err == nil {
+		t.Error("expected an error for the shard which is down")
+	}
+
+	want := map[string][]*RepoDiskUsage{
+		"gitserver-0": {{Name: "a", SizeBytes: 1}, {Name: "b", SizeBytes: 2}},
+	}
+	if diff := cmp.Diff(want, store.usage); diff != "" {
+		t.Errorf("unexpected disk usage (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"gitserver-0", "gitserver-1"}, store.keptShards); diff != "" {
+		t.Errorf("unexpected kept shards (-want +got):\n%s", diff)
+	}
+}
@@ -30,6 +30,7 @@ type GithubSource struct {
 	config          *schema.GitHubConnection
 	exclude         excludeFunc
 	excludeArchived bool
+	cloneStrategy   cloneStrategyFunc
 	excludeForks    bool
 	githubDotCom    bool
 	baseURL         *url.URL
@@ -112,12 +113,22 @@ func newGithubSource(svc *ExternalService, c *schema.GitHubConnection, cf *httpc
 		return nil, err
 	}
 
+	var cb cloneStrategyBuilder
+	for _, cs := range c.CloneStrategies {
+		cb.Add(cs.Pattern, cs.Mode, cs.Depth, cs.Since)
+	}
+	cloneStrategy, err := cb.Build()
+	if err != nil {
+		return nil, err
+	}
+
 	return &GithubSource{
 		svc:              svc,
 		config:           c,
 		exclude:          exclude,
 		excludeArchived:  excludeArchived,
 		excludeForks:     excludeForks,
+		cloneStrategy:    cloneStrategy,
 		baseURL:          baseURL,
 		githubDotCom:     githubDotCom,
 		client:           github.NewClient(apiURL, c.Token, cli),
@@ -295,12 +306,13 @@ func (s GithubSource) GetRepo(ctx context.Context, nameWithOwner string) (*Repo,
 
 func (s GithubSource) makeRepo(r *github.Repository) *Repo {
 	urn := s.svc.URN()
+	name := string(reposource.GitHubRepoName(
+		s.config.RepositoryPathPattern,
+		s.originalHostname,
+		r.NameWithOwner,
+	))
 	return &Repo{
-		Name: string(reposource.GitHubRepoName(
-			s.config.RepositoryPathPattern,
-			s.originalHostname,
-			r.NameWithOwner,
-		)),
+		Name: name,
 		URI: string(reposource.GitHubRepoName(
 			"",
 			s.originalHostname,
@@ -315,6 +327,9 @@ func (s GithubSource) makeRepo(r *github.Repository) *Repo {
 			urn: {
 				ID:       urn,
 				CloneURL: s.authenticatedRemoteURL(r),
+				LFS:      s.config.GitLFS,
+				LFSRefs:  s.config.GitLFSRefs,
+				Clone:    s.cloneStrategy(name),
 			},
 		},
 		Metadata: r,
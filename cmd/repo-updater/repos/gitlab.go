@@ -25,6 +25,7 @@ type GitLabSource struct {
 	svc                 *ExternalService
 	config              *schema.GitLabConnection
 	exclude             excludeFunc
+	cloneStrategy       cloneStrategyFunc
 	baseURL             *url.URL // URL with path /api/v4 (no trailing slash)
 	nameTransformations reposource.NameTransformations
 	client              *gitlab.Client
@@ -70,6 +71,15 @@ func newGitLabSource(svc *ExternalService, c *schema.GitLabConnection, cf *httpc
 		return nil, err
 	}
 
+	var cb cloneStrategyBuilder
+	for _, cs := range c.CloneStrategies {
+		cb.Add(cs.Pattern, cs.Mode, cs.Depth, cs.Since)
+	}
+	cloneStrategy, err := cb.Build()
+	if err != nil {
+		return nil, err
+	}
+
 	// Validate and cache user-defined name transformations.
 	nts, err := reposource.CompileGitLabNameTransformations(c.NameTransformations)
 	if err != nil {
@@ -80,6 +90,7 @@ func newGitLabSource(svc *ExternalService, c *schema.GitLabConnection, cf *httpc
 		svc:                 svc,
 		config:              c,
 		exclude:             exclude,
+		cloneStrategy:       cloneStrategy,
 		baseURL:             baseURL,
 		nameTransformations: nts,
 		client:              gitlab.NewClientProvider(baseURL, cli).GetPATClient(c.Token, ""),
@@ -113,13 +124,14 @@ func (s GitLabSource) ExternalServices() ExternalServices {
 
 func (s GitLabSource) makeRepo(proj *gitlab.Project) *Repo {
 	urn := s.svc.URN()
+	name := string(reposource.GitLabRepoName(
+		s.config.RepositoryPathPattern,
+		s.baseURL.Hostname(),
+		proj.PathWithNamespace,
+		s.nameTransformations,
+	))
 	return &Repo{
-		Name: string(reposource.GitLabRepoName(
-			s.config.RepositoryPathPattern,
-			s.baseURL.Hostname(),
-			proj.PathWithNamespace,
-			s.nameTransformations,
-		)),
+		Name: name,
 		URI: string(reposource.GitLabRepoName(
 			"",
 			s.baseURL.Hostname(),
@@ -135,6 +147,9 @@ func (s GitLabSource) makeRepo(proj *gitlab.Project) *Repo {
 			urn: {
 				ID:       urn,
 				CloneURL: s.authenticatedRemoteURL(proj),
+				LFS:      s.config.GitLFS,
+				LFSRefs:  s.config.GitLFSRefs,
+				Clone:    s.cloneStrategy(name),
 			},
 		},
 		Metadata: proj,
@@ -48,6 +48,8 @@ func TestIntegration(t *testing.T) {
 		test func(*testing.T)
 	}{
 		{"DBStore/Transact", testDBStoreTransact(dbstore)},
+		{"DBStore/RepoUpdateSchedules", testDBStoreRepoUpdateSchedules(dbstore)},
+		{"DBStore/RepoDiskUsage", testDBStoreRepoDiskUsage(dbstore)},
 		{"DBStore/ListExternalServices", testStoreListExternalServices(store)},
 		{"DBStore/ListExternalServices/ByRepo", testStoreListExternalServicesByRepos(store)},
 		{"DBStore/UpsertExternalServices", testStoreUpsertExternalServices(store)},
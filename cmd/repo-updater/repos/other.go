@@ -19,9 +19,10 @@ import (
 // A OtherSource yields repositories from a single Other connection configured
 // in Sourcegraph via the external services configuration.
 type OtherSource struct {
-	svc    *ExternalService
-	conn   *schema.OtherExternalServiceConnection
-	client httpcli.Doer
+	svc           *ExternalService
+	conn          *schema.OtherExternalServiceConnection
+	client        httpcli.Doer
+	cloneStrategy cloneStrategyFunc
 }
 
 // NewOtherSource returns a new OtherSource from the given external service.
@@ -40,7 +41,16 @@ func NewOtherSource(svc *ExternalService, cf *httpcli.Factory) (*OtherSource, er
 		return nil, err
 	}
 
-	return &OtherSource{svc: svc, conn: &c, client: cli}, nil
+	var cb cloneStrategyBuilder
+	for _, cs := range c.CloneStrategies {
+		cb.Add(cs.Pattern, cs.Mode, cs.Depth, cs.Since)
+	}
+	cloneStrategy, err := cb.Build()
+	if err != nil {
+		return nil, err
+	}
+
+	return &OtherSource{svc: svc, conn: &c, client: cli, cloneStrategy: cloneStrategy}, nil
 }
 
 // ListRepos returns all Other repositories accessible to all connections configured
@@ -137,6 +147,9 @@ func (s OtherSource) otherRepoFromCloneURL(urn string, u *url.URL) (*Repo, error
 			urn: {
 				ID:       urn,
 				CloneURL: repoURL,
+				LFS:      s.conn.GitLFS,
+				LFSRefs:  s.conn.GitLFSRefs,
+				Clone:    s.cloneStrategy(string(repoName)),
 			},
 		},
 	}, nil
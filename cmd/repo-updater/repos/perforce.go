@@ -0,0 +1,67 @@
+package repos
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/internal/conf/reposource"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/perforce"
+	"github.com/sourcegraph/sourcegraph/internal/jsonc"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// A PerforceSource yields depots from a single Perforce connection configured
+// in Sourcegraph via the external services configuration. gitserver converts
+// each depot into a Git repository.
+type PerforceSource struct {
+	svc  *ExternalService
+	conn *schema.PerforceConnection
+}
+
+// NewPerforceSource returns a new PerforceSource from the given external service.
+func NewPerforceSource(svc *ExternalService) (*PerforceSource, error) {
+	var c schema.PerforceConnection
+	if err := jsonc.Unmarshal(svc.Config, &c); err != nil {
+		return nil, errors.Wrapf(err, "external service id=%d config error", svc.ID)
+	}
+	return &PerforceSource{svc: svc, conn: &c}, nil
+}
+
+// ListRepos returns all Perforce depots configured in the connection, as
+// repositories.
+func (s PerforceSource) ListRepos(ctx context.Context, results chan SourceResult) {
+	for _, depot := range s.conn.Depots {
+		results <- SourceResult{Source: s, Repo: s.makeRepo(depot)}
+	}
+}
+
+// ExternalServices returns a singleton slice containing the external service.
+func (s PerforceSource) ExternalServices() ExternalServices {
+	return ExternalServices{s.svc}
+}
+
+func (s PerforceSource) makeRepo(depotPath string) *Repo {
+	depot := &perforce.Depot{Path: depotPath}
+	host := perforce.Host(s.conn.P4Port)
+	name := string(reposource.PerforceRepoName(s.conn.RepositoryPathPattern, host, depotPath))
+	urn := s.svc.URN()
+	cloneURL := &perforce.CloneURL{
+		Port:       s.conn.P4Port,
+		User:       s.conn.P4User,
+		Password:   s.conn.P4Passwd,
+		Depot:      depotPath,
+		MaxChanges: s.conn.MaxChanges,
+	}
+	return &Repo{
+		Name:         name,
+		URI:          string(reposource.PerforceRepoName("", host, depotPath)),
+		ExternalRepo: perforce.ExternalRepoSpec(depot, s.conn.P4Port),
+		Sources: map[string]*SourceInfo{
+			urn: {
+				ID:       urn,
+				CloneURL: cloneURL.String(),
+			},
+		},
+		Metadata: depot,
+	}
+}
@@ -0,0 +1,471 @@
+package repos
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	multierror "github.com/hashicorp/go-multierror"
+	"github.com/inconshreveable/log15"
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/bitbucketserver"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitlab"
+	"github.com/sourcegraph/sourcegraph/internal/httpcli"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// RunPushWebhookSyncWorker periodically creates, updates and deletes the push webhooks that the
+// frontend receives at /.api/repo-update-webhooks/, so that they match the webhook secrets of the
+// external services in store. siteID names the Bitbucket Server webhook.
+//
+// It must only run in one process, so that code hosts aren't asked to sync the same webhooks
+// concurrently.
+func RunPushWebhookSyncWorker(ctx context.Context, store Store, siteID string, interval time.Duration) {
+	bbs := newBitbucketServerWebhookSyncer(store, "sourcegraph-repo-updates-"+siteID)
+	gh := newGitHubWebhookSyncer(store)
+	gl := newGitLabWebhookSyncer(store)
+
+	for {
+		externalURL := conf.Get().ExternalURL
+		if err := bbs.syncWebhooks(ctx, externalURL); err != nil {
+			log15.Error("failed to sync Bitbucket Server push webhooks", "error", err)
+		}
+		if err := gh.syncWebhooks(ctx, externalURL); err != nil {
+			log15.Error("failed to sync GitHub push webhooks", "error", err)
+		}
+		if err := gl.syncWebhooks(ctx, externalURL); err != nil {
+			log15.Error("failed to sync GitLab push webhooks", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+	}
+}
+
+// listExternalServiceConfigs returns the external services of the given kind in store, with
+// their decoded configs. External services with invalid configs are skipped.
+func listExternalServiceConfigs(ctx context.Context, store Store, kind string) ([]*ExternalService, []interface{}, error) {
+	svcs, err := store.ListExternalServices(ctx, StoreListExternalServicesArgs{Kinds: []string{kind}})
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "listing %s external services", kind)
+	}
+
+	valid := svcs[:0]
+	var cfgs []interface{}
+	for _, svc := range svcs {
+		cfg, err := svc.Configuration()
+		if err != nil {
+			log15.Error("ignoring external service with invalid config", "id", svc.ID, "error", err)
+			continue
+		}
+		valid = append(valid, svc)
+		cfgs = append(cfgs, cfg)
+	}
+	return valid, cfgs, nil
+}
+
+// bitbucketServerWebhookSyncer creates the push webhook on every Bitbucket Server instance that
+// has a webhook secret configured, using the Bitbucket Server Sourcegraph plugin.
+type bitbucketServerWebhookSyncer struct {
+	store Store
+
+	// name is the name of the webhook. It must be unique per Sourcegraph instance and differ
+	// from the name of the campaigns webhook.
+	name string
+
+	// externalServiceID -> secret
+	// It keeps track of secrets we know have been stored in the remote Bitbucket webhook config.
+	secrets map[int64]string
+
+	// Optional httpClient
+	httpClient httpcli.Doer
+}
+
+func newBitbucketServerWebhookSyncer(store Store, name string) *bitbucketServerWebhookSyncer {
+	return &bitbucketServerWebhookSyncer{store: store, name: name, secrets: make(map[int64]string)}
+}
+
+func (s *bitbucketServerWebhookSyncer) syncWebhooks(ctx context.Context, externalURL string) error {
+	svcs, cfgs, err := listExternalServiceConfigs(ctx, s.store, "BITBUCKETSERVER")
+	if err != nil {
+		return err
+	}
+
+	for i, svc := range svcs {
+		con := cfgs[i].(*schema.BitbucketServerConnection)
+		if err := s.syncWebhook(ctx, svc.ID, con, externalURL); err != nil {
+			log15.Error("failed to sync Bitbucket Server push webhook", "externalServiceID", svc.ID, "error", err)
+		}
+	}
+	return nil
+}
+
+// syncWebhook ensures that the webhook has been configured correctly on Bitbucket Server. If no
+// secret has been set, we delete the existing webhook config.
+func (s *bitbucketServerWebhookSyncer) syncWebhook(ctx context.Context, externalServiceID int64, con *schema.BitbucketServerConnection, externalURL string) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	secret := con.WebhookSecret()
+
+	oldSecret, ok := s.secrets[externalServiceID]
+	if ok && oldSecret == secret {
+		// Nothing has changed since our last check
+		return nil
+	}
+
+	client, err := bitbucketserver.NewClient(con, s.httpClient)
+	if err != nil {
+		return errors.Wrap(err, "creating client")
+	}
+
+	if secret == "" {
+		// If this is the first iteration we don't know if the server has a hook configured or
+		// not. If not, the delete will be a noop.
+		if err := client.DeleteWebhook(ctx, s.name); err != nil {
+			return errors.Wrap(err, "deleting webhook")
+		}
+	} else {
+		err := client.UpsertWebhook(ctx, bitbucketserver.Webhook{
+			Name:     s.name,
+			Scope:    "global",
+			Events:   []string{"repo"},
+			Endpoint: fmt.Sprintf("%s/.api/repo-update-webhooks/bitbucketserver?externalServiceID=%d", externalURL, externalServiceID),
+			Secret:   secret,
+		})
+		if err != nil {
+			return errors.Wrap(err, "upserting webhook")
+		}
+	}
+
+	s.secrets[externalServiceID] = secret
+	return nil
+}
+
+// githubWebhookSyncer creates the push webhook on every organization listed in the "webhooks" of
+// a GitHub external service config, using the organization webhooks API.
+type githubWebhookSyncer struct {
+	store Store
+
+	// githubHookKey -> secret
+	// It keeps track of secrets we know have been stored in the remote GitHub webhook config.
+	secrets map[githubHookKey]string
+
+	// Optional httpClient
+	httpClient httpcli.Doer
+}
+
+// githubHookKey identifies the push webhook of an organization created for an external service.
+type githubHookKey struct {
+	externalServiceID int64
+	org               string
+}
+
+func newGitHubWebhookSyncer(store Store) *githubWebhookSyncer {
+	return &githubWebhookSyncer{store: store, secrets: make(map[githubHookKey]string)}
+}
+
+func (s *githubWebhookSyncer) syncWebhooks(ctx context.Context, externalURL string) error {
+	svcs, cfgs, err := listExternalServiceConfigs(ctx, s.store, "GITHUB")
+	if err != nil {
+		return err
+	}
+
+	for i, svc := range svcs {
+		con := cfgs[i].(*schema.GitHubConnection)
+		if err := s.syncWebhook(ctx, svc.ID, con, externalURL); err != nil {
+			log15.Error("failed to sync GitHub push webhook", "externalServiceID", svc.ID, "error", err)
+		}
+	}
+	return nil
+}
+
+// syncWebhook ensures that the webhook has been configured correctly on every organization in
+// con.Webhooks. The webhooks of organizations that have been removed from con.Webhooks since our
+// last check are deleted.
+func (s *githubWebhookSyncer) syncWebhook(ctx context.Context, externalServiceID int64, con *schema.GitHubConnection, externalURL string) error {
+	secrets := make(map[string]string, len(con.Webhooks))
+	for _, hook := range con.Webhooks {
+		secrets[hook.Org] = hook.Secret
+	}
+	for key := range s.secrets {
+		if _, ok := secrets[key.org]; !ok && key.externalServiceID == externalServiceID {
+			secrets[key.org] = ""
+		}
+	}
+
+	var client *github.Client
+	endpoint := externalURL + "/.api/repo-update-webhooks/github"
+	for org, secret := range secrets {
+		key := githubHookKey{externalServiceID: externalServiceID, org: org}
+		if oldSecret, ok := s.secrets[key]; ok && oldSecret == secret {
+			// Nothing has changed since our last check
+			continue
+		}
+
+		if client == nil {
+			baseURL, err := url.Parse(con.Url)
+			if err != nil {
+				return errors.Wrap(err, "parsing GitHub URL")
+			}
+			apiURL, _ := github.APIRoot(baseURL)
+			client = github.NewClient(apiURL, con.Token, s.httpClient)
+		}
+
+		if err := upsertGitHubWebhook(ctx, client, org, endpoint, secret); err != nil {
+			return errors.Wrapf(err, "syncing webhook of organization %q", org)
+		}
+		if secret == "" {
+			delete(s.secrets, key)
+		} else {
+			s.secrets[key] = secret
+		}
+	}
+	return nil
+}
+
+// upsertGitHubWebhook creates or updates the webhook of org that sends push events to endpoint.
+// If secret is empty, the webhook is deleted.
+func upsertGitHubWebhook(ctx context.Context, client *github.Client, org, endpoint, secret string) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	hooks, err := client.ListOrgWebhooks(ctx, org)
+	if err != nil {
+		return errors.Wrap(err, "listing webhooks")
+	}
+	var existing *github.OrgWebhook
+	for _, hook := range hooks {
+		if hook.Config.URL == endpoint {
+			existing = hook
+			break
+		}
+	}
+
+	if secret == "" {
+		if existing == nil {
+			return nil
+		}
+		return errors.Wrap(client.DeleteOrgWebhook(ctx, org, existing.ID), "deleting webhook")
+	}
+
+	hook := &github.OrgWebhook{
+		Name:   "web",
+		Active: true,
+		Events: []string{"push"},
+		Config: github.OrgWebhookConfig{URL: endpoint, ContentType: "json", Secret: secret},
+	}
+	if existing == nil {
+		return errors.Wrap(client.CreateOrgWebhook(ctx, org, hook), "creating webhook")
+	}
+	hook.ID = existing.ID
+	return errors.Wrap(client.UpdateOrgWebhook(ctx, org, hook), "updating webhook")
+}
+
+// Projects whose GitLab hook couldn't be synced are retried after a delay that starts at
+// minWebhookSyncBackoff and doubles after every failure, up to maxWebhookSyncBackoff.
+const (
+	minWebhookSyncBackoff = time.Minute
+	maxWebhookSyncBackoff = time.Hour
+)
+
+// gitLabWebhookSyncer creates the push webhook on every mirrored project of every GitLab instance
+// that has a webhook secret configured, using the project hooks API.
+type gitLabWebhookSyncer struct {
+	store Store
+
+	// gitLabHookKey -> secret
+	// It keeps track of secrets we know have been stored in the remote GitLab hook config. Only
+	// non-empty secrets are kept.
+	secrets map[gitLabHookKey]string
+
+	// gitLabHookKey -> failure
+	// It keeps track of hooks that couldn't be synced, so that they are retried with backoff.
+	failures map[gitLabHookKey]*webhookSyncFailure
+
+	now func() time.Time
+
+	// Optional httpClient
+	httpClient httpcli.Doer
+}
+
+// gitLabHookKey identifies the push webhook of a project created for an external service.
+type gitLabHookKey struct {
+	externalServiceID int64
+	projectID         int
+}
+
+// webhookSyncFailure records when a hook that couldn't be synced is retried.
+type webhookSyncFailure struct {
+	retryAt time.Time
+	backoff time.Duration
+}
+
+func newGitLabWebhookSyncer(store Store) *gitLabWebhookSyncer {
+	return &gitLabWebhookSyncer{
+		store:    store,
+		secrets:  make(map[gitLabHookKey]string),
+		failures: make(map[gitLabHookKey]*webhookSyncFailure),
+		now:      time.Now,
+	}
+}
+
+func (s *gitLabWebhookSyncer) syncWebhooks(ctx context.Context, externalURL string) error {
+	svcs, cfgs, err := listExternalServiceConfigs(ctx, s.store, "GITLAB")
+	if err != nil {
+		return err
+	}
+
+	for i, svc := range svcs {
+		con := cfgs[i].(*schema.GitLabConnection)
+		if err := s.syncWebhook(ctx, svc, con, externalURL); err != nil {
+			log15.Error("failed to sync GitLab push webhooks", "externalServiceID", svc.ID, "error", err)
+		}
+	}
+	return nil
+}
+
+// syncWebhook ensures that the webhook has been configured correctly on every project of the
+// GitLab instance that is mirrored by svc. If no secret has been set, we only delete the
+// webhooks we created before, so GitLab connections without "webhooks" cost nothing.
+func (s *gitLabWebhookSyncer) syncWebhook(ctx context.Context, svc *ExternalService, con *schema.GitLabConnection, externalURL string) error {
+	var secret string
+	if len(con.Webhooks) > 0 {
+		secret = con.Webhooks[0].Secret
+	}
+
+	var projectIDs []int
+	if secret == "" {
+		for key := range s.secrets {
+			if key.externalServiceID == svc.ID {
+				projectIDs = append(projectIDs, key.projectID)
+			}
+		}
+		for key := range s.failures {
+			if key.externalServiceID == svc.ID {
+				if _, ok := s.secrets[key]; !ok {
+					// The hook was never synced, so there is nothing to delete.
+					delete(s.failures, key)
+				}
+			}
+		}
+	} else {
+		var err error
+		if projectIDs, err = s.listProjectIDs(ctx, svc); err != nil {
+			return err
+		}
+	}
+
+	var client *gitlab.Client
+	endpoint := externalURL + "/.api/repo-update-webhooks/gitlab"
+	errs := new(multierror.Error)
+	for _, projectID := range projectIDs {
+		key := gitLabHookKey{externalServiceID: svc.ID, projectID: projectID}
+		if oldSecret, ok := s.secrets[key]; ok && oldSecret == secret {
+			// Nothing has changed since our last check
+			continue
+		}
+		if f, ok := s.failures[key]; ok && s.now().Before(f.retryAt) {
+			continue
+		}
+
+		if client == nil {
+			baseURL, err := url.Parse(con.Url)
+			if err != nil {
+				return errors.Wrap(err, "parsing GitLab URL")
+			}
+			client = gitlab.NewClientProvider(baseURL, s.httpClient).GetPATClient(con.Token, "")
+		}
+
+		if err := upsertGitLabWebhook(ctx, client, projectID, endpoint, secret); err != nil {
+			s.recordFailure(key)
+			errs = multierror.Append(errs, errors.Wrapf(err, "syncing webhook of project %d", projectID))
+			continue
+		}
+		delete(s.failures, key)
+		if secret == "" {
+			delete(s.secrets, key)
+		} else {
+			s.secrets[key] = secret
+		}
+	}
+	return errs.ErrorOrNil()
+}
+
+// listProjectIDs returns the IDs of the GitLab projects that are mirrored by svc.
+func (s *gitLabWebhookSyncer) listProjectIDs(ctx context.Context, svc *ExternalService) ([]int, error) {
+	repos, err := s.store.ListRepos(ctx, StoreListReposArgs{Kinds: []string{gitlab.ServiceType}})
+	if err != nil {
+		return nil, errors.Wrap(err, "listing GitLab repositories")
+	}
+
+	urn := svc.URN()
+	var ids []int
+	for _, r := range repos {
+		if _, ok := r.Sources[urn]; !ok {
+			continue
+		}
+		if id, err := strconv.Atoi(r.ExternalRepo.ID); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+// recordFailure schedules the next attempt to sync the hook identified by key.
+func (s *gitLabWebhookSyncer) recordFailure(key gitLabHookKey) {
+	f, ok := s.failures[key]
+	if !ok {
+		f = &webhookSyncFailure{backoff: minWebhookSyncBackoff}
+		s.failures[key] = f
+	} else if f.backoff *= 2; f.backoff > maxWebhookSyncBackoff {
+		f.backoff = maxWebhookSyncBackoff
+	}
+	f.retryAt = s.now().Add(f.backoff)
+}
+
+// upsertGitLabWebhook creates or updates the hook of the project that sends push and tag push
+// events to endpoint. If secret is empty, the hook is deleted.
+func upsertGitLabWebhook(ctx context.Context, client *gitlab.Client, projectID int, endpoint, secret string) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	hooks, err := client.ListProjectHooks(ctx, projectID)
+	if err != nil {
+		return errors.Wrap(err, "listing hooks")
+	}
+	var existing *gitlab.ProjectHook
+	for _, hook := range hooks {
+		if hook.URL == endpoint {
+			existing = hook
+			break
+		}
+	}
+
+	if secret == "" {
+		if existing == nil {
+			return nil
+		}
+		return errors.Wrap(client.DeleteProjectHook(ctx, projectID, existing.ID), "deleting hook")
+	}
+
+	hook := &gitlab.ProjectHook{
+		URL:                   endpoint,
+		PushEvents:            true,
+		TagPushEvents:         true,
+		EnableSSLVerification: true,
+		Token:                 secret,
+	}
+	if existing == nil {
+		return errors.Wrap(client.AddProjectHook(ctx, projectID, hook), "adding hook")
+	}
+	hook.ID = existing.ID
+	return errors.Wrap(client.EditProjectHook(ctx, projectID, hook), "editing hook")
+}
@@ -0,0 +1,235 @@
+package repos
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestGitHubWebhookSyncer(t *testing.T) {
+	const existingHooks = `[{"id": 3, "config": {"url": "http://example.com/.api/repo-update-webhooks/github"}}]`
+	key := githubHookKey{externalServiceID: 1, org: "o"}
+
+	testCases := []struct {
+		name    string
+		con     *schema.GitHubConnection
+		secrets map[githubHookKey]string
+		hooks   string
+		expect  []string
+		want    map[githubHookKey]string
+	}{
+		{
+			name:    "No existing secret",
+			con:     &schema.GitHubConnection{Url: "https://github.com", Webhooks: []*schema.GitHubWebhook{{Org: "o", Secret: "secret"}}},
+			secrets: map[githubHookKey]string{},
+			hooks:   `[]`,
+			expect:  []string{"GET /orgs/o/hooks", "POST /orgs/o/hooks"},
+			want:    map[githubHookKey]string{key: "secret"},
+		},
+		{
+			name:    "existing secret matches",
+			con:     &schema.GitHubConnection{Url: "https://github.com", Webhooks: []*schema.GitHubWebhook{{Org: "o", Secret: "secret"}}},
+			secrets: map[githubHookKey]string{key: "secret"},
+			expect:  nil,
+			want:    map[githubHookKey]string{key: "secret"},
+		},
+		{
+			name:    "existing secret does not match",
+			con:     &schema.GitHubConnection{Url: "https://github.com", Webhooks: []*schema.GitHubWebhook{{Org: "o", Secret: "secret"}}},
+			secrets: map[githubHookKey]string{key: "old"},
+			hooks:   existingHooks,
+			expect:  []string{"GET /orgs/o/hooks", "PATCH /orgs/o/hooks/3"},
+			want:    map[githubHookKey]string{key: "secret"},
+		},
+		{
+			name:    "organization removed",
+			con:     &schema.GitHubConnection{Url: "https://github.com"},
+			secrets: map[githubHookKey]string{key: "old", {externalServiceID: 2, org: "o"}: "other"},
+			hooks:   existingHooks,
+			expect:  []string{"GET /orgs/o/hooks", "DELETE /orgs/o/hooks/3"},
+			want:    map[githubHookKey]string{{externalServiceID: 2, org: "o"}: "other"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := &requestRecorder{getBody: tc.hooks}
+			s := newGitHubWebhookSyncer(new(FakeStore))
+			s.secrets = tc.secrets
+			s.httpClient = rec
+
+			if err := s.syncWebhook(context.Background(), 1, tc.con, "http://example.com"); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.expect, rec.requests); diff != "" {
+				t.Errorf("requests mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want, s.secrets); diff != "" {
+				t.Errorf("secrets mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestGitLabWebhookSyncer(t *testing.T) {
+	ctx := context.Background()
+	svc := &ExternalService{Kind: "GITLAB", Config: `{}`}
+	other := &ExternalService{Kind: "GITLAB", Config: `{}`}
+	store := new(FakeStore)
+	if err := store.UpsertExternalServices(ctx, svc, other); err != nil {
+		t.Fatal(err)
+	}
+	gitLabRepo := func(id string, svc *ExternalService) *Repo {
+		return &Repo{
+			Name:         "gitlab.example.com/" + id,
+			ExternalRepo: api.ExternalRepoSpec{ID: id, ServiceType: "gitlab", ServiceID: "https://gitlab.example.com/"},
+			Sources:      map[string]*SourceInfo{svc.URN(): {ID: svc.URN()}},
+		}
+	}
+	if err := store.UpsertRepos(ctx, gitLabRepo("15", svc), gitLabRepo("16", other)); err != nil {
+		t.Fatal(err)
+	}
+
+	const existingHooks = `[{"id": 3, "url": "http://example.com/.api/repo-update-webhooks/gitlab"}]`
+	key := gitLabHookKey{externalServiceID: svc.ID, projectID: 15}
+
+	testCases := []struct {
+		name      string
+		con       *schema.GitLabConnection
+		secrets   map[gitLabHookKey]string
+		listError error
+		hooks     string
+		expect    []string
+		want      map[gitLabHookKey]string
+	}{
+		{
+			name:    "No existing secret",
+			con:     &schema.GitLabConnection{Url: "https://gitlab.example.com", Webhooks: []*schema.GitLabWebhook{{Secret: "secret"}}},
+			secrets: map[gitLabHookKey]string{},
+			hooks:   `[]`,
+			expect:  []string{"GET /api/v4/projects/15/hooks", "POST /api/v4/projects/15/hooks"},
+			want:    map[gitLabHookKey]string{key: "secret"},
+		},
+		{
+			name:    "existing secret matches",
+			con:     &schema.GitLabConnection{Url: "https://gitlab.example.com", Webhooks: []*schema.GitLabWebhook{{Secret: "secret"}}},
+			secrets: map[gitLabHookKey]string{key: "secret"},
+			expect:  nil,
+			want:    map[gitLabHookKey]string{key: "secret"},
+		},
+		{
+			name:    "existing secret does not match",
+			con:     &schema.GitLabConnection{Url: "https://gitlab.example.com", Webhooks: []*schema.GitLabWebhook{{Secret: "secret"}}},
+			secrets: map[gitLabHookKey]string{key: "old"},
+			hooks:   existingHooks,
+			expect:  []string{"GET /api/v4/projects/15/hooks", "PUT /api/v4/projects/15/hooks/3"},
+			want:    map[gitLabHookKey]string{key: "secret"},
+		},
+		{
+			name:      "secret removed",
+			con:       &schema.GitLabConnection{Url: "https://gitlab.example.com"},
+			secrets:   map[gitLabHookKey]string{key: "old"},
+			listError: errors.New("repos must not be listed"),
+			hooks:     existingHooks,
+			expect:    []string{"GET /api/v4/projects/15/hooks", "DELETE /api/v4/projects/15/hooks/3"},
+			want:      map[gitLabHookKey]string{},
+		},
+		{
+			name:      "no secret, never synced",
+			con:       &schema.GitLabConnection{Url: "https://gitlab.example.com"},
+			secrets:   map[gitLabHookKey]string{},
+			listError: errors.New("repos must not be listed"),
+			expect:    nil,
+			want:      map[gitLabHookKey]string{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			store.ListReposError = tc.listError
+			defer func() { store.ListReposError = nil }()
+
+			rec := &requestRecorder{getBody: tc.hooks}
+			s := newGitLabWebhookSyncer(store)
+			s.secrets = tc.secrets
+			s.httpClient = rec
+
+			if err := s.syncWebhook(ctx, svc, tc.con, "http://example.com"); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.expect, rec.requests); diff != "" {
+				t.Errorf("requests mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.want, s.secrets); diff != "" {
+				t.Errorf("secrets mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	t.Run("failures are retried with backoff", func(t *testing.T) {
+		now := time.Now()
+		rec := &requestRecorder{status: http.StatusForbidden}
+		s := newGitLabWebhookSyncer(store)
+		s.httpClient = rec
+		s.now = func() time.Time { return now }
+		con := &schema.GitLabConnection{Url: "https://gitlab.example.com", Webhooks: []*schema.GitLabWebhook{{Secret: "secret"}}}
+
+		for _, step := range []struct {
+			elapsed time.Duration
+			expect  []string
+		}{
+			{0, []string{"GET /api/v4/projects/15/hooks"}},
+			{30 * time.Second, nil},
+			{time.Minute, []string{"GET /api/v4/projects/15/hooks"}},
+			{2 * time.Minute, nil},
+			{3 * time.Minute, []string{"GET /api/v4/projects/15/hooks"}},
+		} {
+			rec.requests = nil
+			s.now = func() time.Time { return now.Add(step.elapsed) }
+
+			err := s.syncWebhook(ctx, svc, con, "http://example.com")
+			if (err != nil) != (step.expect != nil) {
+				t.Errorf("after %s: unexpected error %v", step.elapsed, err)
+			}
+			if diff := cmp.Diff(step.expect, rec.requests); diff != "" {
+				t.Errorf("after %s: requests mismatch (-want +got):\n%s", step.elapsed, diff)
+			}
+		}
+		if len(s.secrets) != 0 {
+			t.Errorf("unexpected secrets %v", s.secrets)
+		}
+	})
+}
+
+// requestRecorder records the method and path of requests. It responds with status, or 200 if
+// unset. Successful GET requests get getBody and other requests an empty JSON object.
+type requestRecorder struct {
+	status   int
+	getBody  string
+	requests []string
+}
+
+func (r *requestRecorder) Do(req *http.Request) (*http.Response, error) {
+	r.requests = append(r.requests, req.Method+" "+req.URL.Path)
+	status := r.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	body := "{}"
+	if req.Method == "GET" && status == http.StatusOK {
+		body = r.getBody
+	}
+	return &http.Response{
+		Status:     http.StatusText(status),
+		StatusCode: status,
+		Body:       ioutil.NopCloser(strings.NewReader(body)),
+	}, nil
+}
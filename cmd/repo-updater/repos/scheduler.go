@@ -3,10 +3,12 @@ package repos
 import (
 	"container/heap"
 	"context"
+	"math/rand"
 	"sync"
 	"time"
 
 	"github.com/inconshreveable/log15"
+	"github.com/pkg/errors"
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/conf"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver"
@@ -79,6 +81,19 @@ const (
 	maxDelay = 8 * time.Hour
 )
 
+// failureBackoff returns the interval until the next scheduled update of a repository whose
+// last n updates failed. It doubles with every failure, starting at minDelay, up to maxDelay.
+func failureBackoff(n int) time.Duration {
+	interval := minDelay
+	for i := 0; i < n && interval < maxDelay; i++ {
+		interval *= 2
+	}
+	if interval > maxDelay {
+		interval = maxDelay
+	}
+	return interval
+}
+
 // updateScheduler schedules repo update (or clone) requests to gitserver.
 //
 // Repository metadata is synced from configured code hosts and added to the scheduler.
@@ -93,20 +108,47 @@ const (
 //
 // A worker continuously dequeues repos and sends updates to gitserver, but its concurrency
 // is limited by the gitMaxConcurrentClones site configuration.
+//
+// Repos whose updates fail are scheduled with an exponential backoff (see failureBackoff)
+// instead, so that they don't keep taking up update slots.
+//
+// If a ScheduleStore is set with LoadSchedule, the schedule of each repo is persisted after
+// every update, and restored when repo-updater restarts.
 type updateScheduler struct {
 	mu sync.Mutex
 
 	updateQueue *updateQueue
 	schedule    *schedule
+
+	store ScheduleStore
+}
+
+// RepoUpdateSchedule is the persisted state of a repo in the update schedule.
+type RepoUpdateSchedule struct {
+	RepoID              api.RepoID
+	Interval            time.Duration
+	Due                 time.Time
+	LastFetched         time.Time
+	LastError           string
+	ConsecutiveFailures int
+}
+
+// A ScheduleStore persists the update schedule.
+type ScheduleStore interface {
+	ListRepoUpdateSchedules(context.Context) ([]*RepoUpdateSchedule, error)
+	UpsertRepoUpdateSchedules(ctx context.Context, schedules ...*RepoUpdateSchedule) error
 }
 
 // A configuredRepo2 represents the configuration data for a given repo from
 // a configuration source, such as information retrieved from GitHub for a
 // given GitHubConnection.
 type configuredRepo2 struct {
-	URL  string
-	ID   api.RepoID
-	Name api.RepoName
+	URL     string
+	ID      api.RepoID
+	Name    api.RepoName
+	LFS     bool
+	LFSRefs []string
+	Clone   *gitserverprotocol.CloneStrategy
 }
 
 // notifyChanBuffer controls the buffer size of notification channels.
@@ -122,12 +164,35 @@ func NewUpdateScheduler() *updateScheduler {
 			notifyEnqueue: make(chan struct{}, notifyChanBuffer),
 		},
 		schedule: &schedule{
-			index:  make(map[api.RepoID]*scheduledRepoUpdate),
-			wakeup: make(chan struct{}, notifyChanBuffer),
+			index:     make(map[api.RepoID]*scheduledRepoUpdate),
+			persisted: make(map[api.RepoID]*RepoUpdateSchedule),
+			wakeup:    make(chan struct{}, notifyChanBuffer),
 		},
 	}
 }
 
+// LoadSchedule restores the schedule persisted in store and persists the schedule of
+// every repo in store after its updates from now on. It must be called before the
+// scheduler is started.
+//
+// The restored schedule of a repo takes effect when the repo is added to the schedule
+// with UpdateFromDiff.
+func (s *updateScheduler) LoadSchedule(ctx context.Context, store ScheduleStore) error {
+	schedules, err := store.ListRepoUpdateSchedules(ctx)
+	if err != nil {
+		return errors.Wrap(err, "listing repo update schedules")
+	}
+
+	s.schedule.mu.Lock()
+	for _, sched := range schedules {
+		s.schedule.persisted[sched.RepoID] = sched
+	}
+	s.schedule.mu.Unlock()
+
+	s.store = store
+	return nil
+}
+
 // runScheduleLoop starts the loop that schedules updates by enqueuing them into the updateQueue.
 func (s *updateScheduler) runScheduleLoop(ctx context.Context) {
 	for {
@@ -194,12 +259,17 @@ func (s *updateScheduler) runUpdateLoop(ctx context.Context) {
 				if err != nil {
 					schedError.Inc()
 					log15.Warn("error requesting repo update", "uri", repo.Name, "err", err)
+				} else if resp != nil && resp.Error != "" {
+					schedError.Inc()
+					log15.Warn("error updating repo", "uri", repo.Name, "err", resp.Error)
+					err = errors.New(resp.Error)
 				}
-				if resp != nil && resp.LastFetched != nil && resp.LastChanged != nil {
-					// This is the heuristic that is described in the updateScheduler documentation.
-					// Update that documentation if you update this logic.
-					interval := resp.LastFetched.Sub(*resp.LastChanged) / 2
-					s.schedule.updateInterval(repo, interval)
+
+				sched := s.schedule.recordUpdate(repo, resp, err)
+				if sched != nil && s.store != nil {
+					if err := s.store.UpsertRepoUpdateSchedules(ctx, sched); err != nil {
+						log15.Error("error persisting repo update schedule", "uri", repo.Name, "err", err)
+					}
 				}
 			}(ctx, repo, cancel)
 		}
@@ -208,7 +278,11 @@ func (s *updateScheduler) runUpdateLoop(ctx context.Context) {
 
 // requestRepoUpdate sends a request to gitserver to request an update.
 var requestRepoUpdate = func(ctx context.Context, repo configuredRepo2, since time.Duration) (*gitserverprotocol.RepoUpdateResponse, error) {
-	return gitserver.DefaultClient.RequestRepoUpdate(ctx, gitserver.Repo{Name: repo.Name, URL: repo.URL}, since)
+	gitserverRepo := gitserver.Repo{Name: repo.Name, URL: repo.URL, Clone: repo.Clone}
+	if repo.LFS {
+		gitserverRepo.LFS = &gitserver.LFSOptions{Refs: repo.LFSRefs}
+	}
+	return gitserver.DefaultClient.RequestRepoUpdate(ctx, gitserverRepo, since)
 }
 
 // configuredLimiter returns a mutable limiter that is
@@ -283,8 +357,11 @@ func (s *updateScheduler) remove(r *Repo) {
 
 func configuredRepo2FromRepo(r *Repo) configuredRepo2 {
 	repo := configuredRepo2{
-		ID:   r.ID,
-		Name: api.RepoName(r.Name),
+		ID:      r.ID,
+		Name:    api.RepoName(r.Name),
+		LFS:     r.LFS(),
+		LFSRefs: r.LFSRefs(),
+		Clone:   r.CloneStrategy(),
 	}
 
 	if urls := r.CloneURLs(); len(urls) > 0 {
@@ -294,13 +371,12 @@ func configuredRepo2FromRepo(r *Repo) configuredRepo2 {
 	return repo
 }
 
-// UpdateOnce causes a single update of the given repository.
-// It neither adds nor removes the repo from the schedule.
-func (s *updateScheduler) UpdateOnce(id api.RepoID, name api.RepoName, url string) {
-	repo := configuredRepo2{
-		ID:   id,
-		Name: name,
-		URL:  url,
+// UpdateOnce causes a single update of the given repository, from url if
+// it is not empty. It neither adds nor removes the repo from the schedule.
+func (s *updateScheduler) UpdateOnce(r *Repo, url string) {
+	repo := configuredRepo2FromRepo(r)
+	if url != "" {
+		repo.URL = url
 	}
 	schedManualFetch.Inc()
 	s.updateQueue.enqueue(repo, priorityHigh)
@@ -364,10 +440,16 @@ func (s *updateScheduler) ScheduleInfo(id api.RepoID) *protocol.RepoUpdateSchedu
 	s.schedule.mu.Lock()
 	if update := s.schedule.index[id]; update != nil {
 		result.Schedule = &protocol.RepoScheduleState{
-			Index:           update.Index,
-			Total:           len(s.schedule.index),
-			IntervalSeconds: int(update.Interval / time.Second),
-			Due:             update.Due,
+			Index:               update.Index,
+			Total:               len(s.schedule.index),
+			IntervalSeconds:     int(update.Interval / time.Second),
+			Due:                 update.Due,
+			LastError:           update.LastError,
+			ConsecutiveFailures: update.ConsecutiveFailures,
+		}
+		if !update.LastFetched.IsZero() {
+			lastFetched := update.LastFetched
+			result.Schedule.LastFetched = &lastFetched
 		}
 	}
 	s.schedule.mu.Unlock()
@@ -568,6 +650,10 @@ type schedule struct {
 	heap  []*scheduledRepoUpdate // min heap of scheduledRepoUpdates based on their due time.
 	index map[api.RepoID]*scheduledRepoUpdate
 
+	// persisted is the schedule restored by LoadSchedule of repos that haven't been
+	// added to the schedule yet.
+	persisted map[api.RepoID]*RepoUpdateSchedule
+
 	// timer sends a value on the wakeup channel when it is time
 	timer  *time.Timer
 	wakeup chan struct{}
@@ -575,10 +661,25 @@ type schedule struct {
 
 // scheduledRepoUpdate is the update schedule for a single repo.
 type scheduledRepoUpdate struct {
-	Repo     configuredRepo2 // the repo to update
-	Interval time.Duration   // how regularly the repo is updated
-	Due      time.Time       // the next time that the repo will be enqueued for a update
-	Index    int             `json:"-"` // the index in the heap
+	Repo                configuredRepo2 // the repo to update
+	Interval            time.Duration   // how regularly the repo is updated
+	Due                 time.Time       // the next time that the repo will be enqueued for a update
+	LastFetched         time.Time       // the last time that the repo was fetched, as reported by gitserver
+	LastError           string          // the error of the last update, if it failed
+	ConsecutiveFailures int             // the number of updates that failed in a row
+	Index               int             `json:"-"` // the index in the heap
+}
+
+// state returns the state of the update schedule of the repo that is persisted.
+func (u *scheduledRepoUpdate) state() *RepoUpdateSchedule {
+	return &RepoUpdateSchedule{
+		RepoID:              u.Repo.ID,
+		Interval:            u.Interval,
+		Due:                 u.Due,
+		LastFetched:         u.LastFetched,
+		LastError:           u.LastError,
+		ConsecutiveFailures: u.ConsecutiveFailures,
+	}
 }
 
 // upsert inserts or updates a repo in the schedule.
@@ -595,40 +696,85 @@ func (s *schedule) upsert(repo configuredRepo2) (updated bool) {
 		return true
 	}
 
-	heap.Push(s, &scheduledRepoUpdate{
+	update := &scheduledRepoUpdate{
 		Repo:     repo,
 		Interval: minDelay,
 		Due:      timeNow().Add(minDelay),
-	})
+	}
+	if p := s.persisted[repo.ID]; p != nil {
+		delete(s.persisted, repo.ID)
+		update.Interval = p.Interval
+		update.Due = p.Due
+		update.LastFetched = p.LastFetched
+		update.LastError = p.LastError
+		update.ConsecutiveFailures = p.ConsecutiveFailures
+		if now := timeNow(); update.Due.Before(now) {
+			// The repo became due while repo-updater wasn't running. Spread such repos over
+			// their interval, so that they aren't all updated at once after a restart.
+			update.Due = now.Add(time.Duration(randInt63n(int64(update.Interval) + 1)))
+		}
+	}
+	heap.Push(s, update)
 
 	s.rescheduleTimer()
 
 	return false
 }
 
-// updateInterval updates the update interval of a repo in the schedule.
-// It does nothing if the repo is not in the schedule.
-func (s *schedule) updateInterval(repo configuredRepo2, interval time.Duration) {
+// recordUpdate records the result of an update of a repo in the schedule and returns the
+// repo's new schedule. It returns nil if the repo is not in the schedule.
+func (s *schedule) recordUpdate(repo configuredRepo2, resp *gitserverprotocol.RepoUpdateResponse, err error) *RepoUpdateSchedule {
 	if repo.ID == 0 {
 		panic("repo.id is zero")
 	}
 
 	s.mu.Lock()
-	if update := s.index[repo.ID]; update != nil {
-		switch {
-		case interval > maxDelay:
-			update.Interval = maxDelay
-		case interval < minDelay:
-			update.Interval = minDelay
-		default:
-			update.Interval = interval
-		}
-		update.Due = timeNow().Add(update.Interval)
-		log15.Debug("updated repo", "repo", repo.Name, "due", update.Due.Sub(timeNow()))
-		heap.Fix(s, update.Index)
-		s.rescheduleTimer()
+	defer s.mu.Unlock()
+
+	update := s.index[repo.ID]
+	if update == nil {
+		return nil
 	}
-	s.mu.Unlock()
+
+	if resp != nil && resp.LastFetched != nil {
+		update.LastFetched = *resp.LastFetched
+	}
+
+	switch {
+	case err != nil:
+		update.LastError = err.Error()
+		update.ConsecutiveFailures++
+		s.setInterval(update, failureBackoff(update.ConsecutiveFailures))
+	case resp != nil && resp.LastFetched != nil && resp.LastChanged != nil:
+		update.LastError = ""
+		update.ConsecutiveFailures = 0
+		// This is the heuristic that is described in the updateScheduler documentation.
+		// Update that documentation if you update this logic.
+		s.setInterval(update, resp.LastFetched.Sub(*resp.LastChanged)/2)
+	default:
+		update.LastError = ""
+		update.ConsecutiveFailures = 0
+	}
+
+	return update.state()
+}
+
+// setInterval sets the update interval of a repo in the schedule, clamped to
+// [minDelay, maxDelay], and schedules its next update accordingly.
+// The caller must hold the lock on s.mu.
+func (s *schedule) setInterval(update *scheduledRepoUpdate, interval time.Duration) {
+	switch {
+	case interval > maxDelay:
+		update.Interval = maxDelay
+	case interval < minDelay:
+		update.Interval = minDelay
+	default:
+		update.Interval = interval
+	}
+	update.Due = timeNow().Add(update.Interval)
+	log15.Debug("updated repo", "repo", update.Repo.Name, "due", update.Due.Sub(timeNow()))
+	heap.Fix(s, update.Index)
+	s.rescheduleTimer()
 }
 
 // remove removes a repo from the schedule.
@@ -673,6 +819,12 @@ func (s *schedule) reset() {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	// Keep the schedule of the repos, so that it is restored when they are added to the
+	// schedule again.
+	for _, update := range s.heap {
+		s.persisted[update.Repo.ID] = update.state()
+	}
+
 	s.heap = s.heap[:0]
 	s.index = map[api.RepoID]*scheduledRepoUpdate{}
 	s.wakeup = make(chan struct{}, notifyChanBuffer)
@@ -729,4 +881,5 @@ var notify = func(ch chan struct{}) {
 var (
 	timeNow       = time.Now
 	timeAfterFunc = time.AfterFunc
+	randInt63n    = rand.Int63n
 )
@@ -9,6 +9,8 @@ import (
 
 	"github.com/davecgh/go-spew/spew"
 	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/internal/api"
 	gitserverprotocol "github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
 	"github.com/sourcegraph/sourcegraph/internal/mutablelimiter"
 )
@@ -19,6 +21,7 @@ func init() {
 	timeNow = nil
 	notify = nil
 	timeAfterFunc = nil
+	randInt63n = nil
 }
 
 func mockTime(t time.Time) {
@@ -659,6 +662,162 @@ func Test_updateScheduler_UpdateFromDiff(t *testing.T) {
 	}
 }
 
+type fakeScheduleStore struct {
+	schedules map[api.RepoID]*RepoUpdateSchedule
+}
+
+func (s *fakeScheduleStore) ListRepoUpdateSchedules(context.Context) ([]*RepoUpdateSchedule, error) {
+	var schedules []*RepoUpdateSchedule
+	for _, sched := range s.schedules {
+		schedules = append(schedules, sched)
+	}
+	return schedules, nil
+}
+
+func (s *fakeScheduleStore) UpsertRepoUpdateSchedules(ctx context.Context, schedules ...*RepoUpdateSchedule) error {
+	for _, sched := range schedules {
+		s.schedules[sched.RepoID] = sched
+	}
+	return nil
+}
+
+func TestUpdateScheduler_LoadSchedule(t *testing.T) {
+	_, stop := startRecording()
+	defer stop()
+
+	randInt63n = func(n int64) int64 { return n / 2 }
+	defer func() { randInt63n = nil }()
+
+	a := configuredRepo2{ID: 1, Name: "a", URL: "a.com"}
+	b := configuredRepo2{ID: 2, Name: "b", URL: "b.com"}
+	c := configuredRepo2{ID: 3, Name: "c", URL: "c.com"}
+
+	store := &fakeScheduleStore{schedules: map[api.RepoID]*RepoUpdateSchedule{
+		a.ID: {RepoID: a.ID, Interval: time.Hour, Due: defaultTime.Add(time.Minute), LastFetched: defaultTime.Add(-time.Hour)},
+		// b became due while repo-updater wasn't running.
+		b.ID: {RepoID: b.ID, Interval: 4 * time.Hour, Due: defaultTime.Add(-time.Minute), LastError: "fetch failed", ConsecutiveFailures: 7},
+	}}
+
+	s := NewUpdateScheduler()
+	if err := s.LoadSchedule(context.Background(), store); err != nil {
+		t.Fatal(err)
+	}
+
+	s.UpdateFromDiff(Diff{Unmodified: []*Repo{
+		{ID: a.ID, Name: string(a.Name), Sources: map[string]*SourceInfo{"a": {CloneURL: a.URL}}},
+		{ID: b.ID, Name: string(b.Name), Sources: map[string]*SourceInfo{"b": {CloneURL: b.URL}}},
+		{ID: c.ID, Name: string(c.Name), Sources: map[string]*SourceInfo{"c": {CloneURL: c.URL}}},
+	}})
+
+	verifySchedule(t, s, []*scheduledRepoUpdate{
+		{Repo: c, Interval: minDelay, Due: defaultTime.Add(minDelay)},
+		{Repo: a, Interval: time.Hour, Due: defaultTime.Add(time.Minute), LastFetched: defaultTime.Add(-time.Hour)},
+		{Repo: b, Interval: 4 * time.Hour, Due: defaultTime.Add(2 * time.Hour), LastError: "fetch failed", ConsecutiveFailures: 7},
+	})
+	if len(s.schedule.persisted) != 0 {
+		t.Errorf("expected restored schedules to be consumed, got %d left", len(s.schedule.persisted))
+	}
+}
+
+func TestUpdateScheduler_persistSchedule(t *testing.T) {
+	_, stop := startRecording()
+	defer stop()
+
+	configuredLimiter = func() *mutablelimiter.Limiter {
+		return mutablelimiter.New(1)
+	}
+	defer func() { configuredLimiter = nil }()
+
+	a := configuredRepo2{ID: 1, Name: "a", URL: "a.com"}
+
+	done := make(chan struct{})
+	requestRepoUpdate = func(ctx context.Context, repo configuredRepo2, since time.Duration) (*gitserverprotocol.RepoUpdateResponse, error) {
+		defer close(done)
+		return nil, errors.New("gitserver unavailable")
+	}
+	defer func() { requestRepoUpdate = nil }()
+
+	store := &fakeScheduleStore{schedules: map[api.RepoID]*RepoUpdateSchedule{}}
+	s := NewUpdateScheduler()
+	if err := s.LoadSchedule(context.Background(), store); err != nil {
+		t.Fatal(err)
+	}
+	s.updateQueue.notifyEnqueue = make(chan struct{})
+	setupInitialSchedule(s, []*scheduledRepoUpdate{{Repo: a, Interval: time.Hour, Due: defaultTime.Add(time.Hour)}})
+	setupInitialQueue(s, []*repoUpdate{{Repo: a, Seq: 1}})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	loopDone := make(chan struct{})
+	go func() {
+		s.runUpdateLoop(ctx)
+		close(loopDone)
+	}()
+	s.updateQueue.notifyEnqueue <- struct{}{}
+	<-done
+
+	// Wait for the update to be removed from the queue, which happens after the schedule
+	// was persisted.
+	for {
+		s.updateQueue.mu.Lock()
+		n := len(s.updateQueue.heap)
+		s.updateQueue.mu.Unlock()
+		if n == 0 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	<-loopDone
+
+	want := map[api.RepoID]*RepoUpdateSchedule{
+		a.ID: {
+			RepoID:              a.ID,
+			Interval:            2 * minDelay,
+			Due:                 defaultTime.Add(2 * minDelay),
+			LastError:           "gitserver unavailable",
+			ConsecutiveFailures: 1,
+		},
+	}
+	if diff := cmp.Diff(want, store.schedules); diff != "" {
+		t.Errorf("persisted schedules mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestSchedule_reset(t *testing.T) {
+	_, stop := startRecording()
+	defer stop()
+
+	a := configuredRepo2{ID: 1, Name: "a", URL: "a.com"}
+
+	s := NewUpdateScheduler()
+	setupInitialSchedule(s, []*scheduledRepoUpdate{
+		{Repo: a, Interval: time.Hour, Due: defaultTime.Add(time.Minute), ConsecutiveFailures: 1, LastError: "x"},
+	})
+
+	s.schedule.reset()
+	verifySchedule(t, s, nil)
+
+	// The schedule of the repo is restored when it is added again.
+	s.schedule.upsert(a)
+	verifySchedule(t, s, []*scheduledRepoUpdate{
+		{Repo: a, Interval: time.Hour, Due: defaultTime.Add(time.Minute), ConsecutiveFailures: 1, LastError: "x"},
+	})
+}
+
+func TestFailureBackoff(t *testing.T) {
+	for n, want := range map[int]time.Duration{
+		0:    minDelay,
+		1:    2 * minDelay,
+		3:    8 * minDelay,
+		10:   maxDelay,
+		1000: maxDelay,
+	} {
+		if have := failureBackoff(n); have != want {
+			t.Errorf("failureBackoff(%d): have %s, want %s", n, have, want)
+		}
+	}
+}
+
 func TestSchedule_upsert(t *testing.T) {
 	a := configuredRepo2{ID: 1, Name: "a", URL: "a.com"}
 	a2 := configuredRepo2{ID: 1, Name: "a2", URL: "a2.com"}
@@ -807,31 +966,42 @@ func TestSchedule_upsert(t *testing.T) {
 	}
 }
 
-func TestSchedule_updateInterval(t *testing.T) {
+func TestSchedule_recordUpdate(t *testing.T) {
 	a := configuredRepo2{ID: 1, Name: "a", URL: "a.com"}
 	b := configuredRepo2{ID: 2, Name: "b", URL: "b.com"}
 	c := configuredRepo2{ID: 3, Name: "c", URL: "c.com"}
 	d := configuredRepo2{ID: 4, Name: "d", URL: "d.com"}
 	e := configuredRepo2{ID: 5, Name: "e", URL: "e.com"}
 
-	type updateCall struct {
-		time     time.Time
-		repo     configuredRepo2
-		interval time.Duration
+	// changedAgo returns a response of an update at now which last changed the repo 2*interval
+	// earlier, so that the heuristic of the updateScheduler schedules the repo every interval.
+	changedAgo := func(now time.Time, interval time.Duration) *gitserverprotocol.RepoUpdateResponse {
+		return &gitserverprotocol.RepoUpdateResponse{
+			LastFetched: timePtr(now),
+			LastChanged: timePtr(now.Add(-2 * interval)),
+		}
+	}
+	errUpdate := errors.New("update failed")
+
+	type recordCall struct {
+		time time.Time
+		repo configuredRepo2
+		resp *gitserverprotocol.RepoUpdateResponse
+		err  error
 	}
 
 	tests := []struct {
 		name                string
 		initialSchedule     []*scheduledRepoUpdate
-		updateCalls         []*updateCall
+		recordCalls         []*recordCall
 		finalSchedule       []*scheduledRepoUpdate
 		timeAfterFuncDelays []time.Duration
 		wakeupNotifications int
 	}{
 		{
-			name: "update has no effect if repo isn't in schedule",
-			updateCalls: []*updateCall{
-				{repo: a, time: defaultTime},
+			name: "record has no effect if repo isn't in schedule",
+			recordCalls: []*recordCall{
+				{repo: a, time: defaultTime, resp: changedAgo(defaultTime, time.Hour)},
 			},
 		},
 		{
@@ -843,18 +1013,19 @@ func TestSchedule_updateInterval(t *testing.T) {
 					Due:      defaultTime.Add(time.Hour),
 				},
 			},
-			updateCalls: []*updateCall{
+			recordCalls: []*recordCall{
 				{
-					repo:     a,
-					time:     defaultTime.Add(time.Second),
-					interval: 123 * time.Second,
+					repo: a,
+					time: defaultTime.Add(time.Second),
+					resp: changedAgo(defaultTime.Add(time.Second), 123*time.Second),
 				},
 			},
 			finalSchedule: []*scheduledRepoUpdate{
 				{
-					Repo:     a,
-					Interval: 123 * time.Second,
-					Due:      defaultTime.Add(124 * time.Second),
+					Repo:        a,
+					Interval:    123 * time.Second,
+					Due:         defaultTime.Add(124 * time.Second),
+					LastFetched: defaultTime.Add(time.Second),
 				},
 			},
 			timeAfterFuncDelays: []time.Duration{123 * time.Second},
@@ -869,18 +1040,19 @@ func TestSchedule_updateInterval(t *testing.T) {
 					Due:      defaultTime.Add(maxDelay),
 				},
 			},
-			updateCalls: []*updateCall{
+			recordCalls: []*recordCall{
 				{
-					repo:     a,
-					time:     defaultTime,
-					interval: time.Second,
+					repo: a,
+					time: defaultTime,
+					resp: changedAgo(defaultTime, time.Second),
 				},
 			},
 			finalSchedule: []*scheduledRepoUpdate{
 				{
-					Repo:     a,
-					Interval: minDelay,
-					Due:      defaultTime.Add(minDelay),
+					Repo:        a,
+					Interval:    minDelay,
+					Due:         defaultTime.Add(minDelay),
+					LastFetched: defaultTime,
 				},
 			},
 			timeAfterFuncDelays: []time.Duration{minDelay},
@@ -895,18 +1067,19 @@ func TestSchedule_updateInterval(t *testing.T) {
 					Due:      defaultTime.Add(minDelay),
 				},
 			},
-			updateCalls: []*updateCall{
+			recordCalls: []*recordCall{
 				{
-					repo:     a,
-					time:     defaultTime,
-					interval: 365 * 25 * time.Hour,
+					repo: a,
+					time: defaultTime,
+					resp: changedAgo(defaultTime, 365*25*time.Hour),
 				},
 			},
 			finalSchedule: []*scheduledRepoUpdate{
 				{
-					Repo:     a,
-					Interval: maxDelay,
-					Due:      defaultTime.Add(maxDelay),
+					Repo:        a,
+					Interval:    maxDelay,
+					Due:         defaultTime.Add(maxDelay),
+					LastFetched: defaultTime,
 				},
 			},
 			timeAfterFuncDelays: []time.Duration{maxDelay},
@@ -921,23 +1094,122 @@ func TestSchedule_updateInterval(t *testing.T) {
 					Due:      defaultTime.Add(time.Hour),
 				},
 			},
-			updateCalls: []*updateCall{
+			recordCalls: []*recordCall{
 				{
-					repo:     a,
-					time:     defaultTime.Add(time.Second),
-					interval: 123 * time.Minute,
+					repo: a,
+					time: defaultTime.Add(time.Second),
+					resp: changedAgo(defaultTime.Add(time.Second), 123*time.Minute),
 				},
 			},
 			finalSchedule: []*scheduledRepoUpdate{
 				{
-					Repo:     a,
-					Interval: 123 * time.Minute,
-					Due:      defaultTime.Add(time.Second + 123*time.Minute),
+					Repo:        a,
+					Interval:    123 * time.Minute,
+					Due:         defaultTime.Add(time.Second + 123*time.Minute),
+					LastFetched: defaultTime.Add(time.Second),
 				},
 			},
 			timeAfterFuncDelays: []time.Duration{123 * time.Minute},
 			wakeupNotifications: 1,
 		},
+		{
+			name: "failure backs off",
+			initialSchedule: []*scheduledRepoUpdate{
+				{
+					Repo:     a,
+					Interval: time.Hour,
+					Due:      defaultTime,
+				},
+			},
+			recordCalls: []*recordCall{
+				{repo: a, time: defaultTime, err: errUpdate},
+				{repo: a, time: defaultTime.Add(2 * minDelay), err: errUpdate},
+			},
+			finalSchedule: []*scheduledRepoUpdate{
+				{
+					Repo:                a,
+					Interval:            4 * minDelay,
+					Due:                 defaultTime.Add(6 * minDelay),
+					LastError:           errUpdate.Error(),
+					ConsecutiveFailures: 2,
+				},
+			},
+			timeAfterFuncDelays: []time.Duration{2 * minDelay, 4 * minDelay},
+			wakeupNotifications: 2,
+		},
+		{
+			name: "failure backoff is capped at the maximum interval",
+			initialSchedule: []*scheduledRepoUpdate{
+				{
+					Repo:                a,
+					Interval:            maxDelay,
+					Due:                 defaultTime,
+					LastError:           errUpdate.Error(),
+					ConsecutiveFailures: 20,
+				},
+			},
+			recordCalls: []*recordCall{
+				{repo: a, time: defaultTime, err: errUpdate},
+			},
+			finalSchedule: []*scheduledRepoUpdate{
+				{
+					Repo:                a,
+					Interval:            maxDelay,
+					Due:                 defaultTime.Add(maxDelay),
+					LastError:           errUpdate.Error(),
+					ConsecutiveFailures: 21,
+				},
+			},
+			timeAfterFuncDelays: []time.Duration{maxDelay},
+			wakeupNotifications: 1,
+		},
+		{
+			name: "success after failures resets the backoff",
+			initialSchedule: []*scheduledRepoUpdate{
+				{
+					Repo:                a,
+					Interval:            8 * minDelay,
+					Due:                 defaultTime,
+					LastError:           errUpdate.Error(),
+					ConsecutiveFailures: 3,
+				},
+			},
+			recordCalls: []*recordCall{
+				{repo: a, time: defaultTime, resp: changedAgo(defaultTime, time.Hour)},
+			},
+			finalSchedule: []*scheduledRepoUpdate{
+				{
+					Repo:        a,
+					Interval:    time.Hour,
+					Due:         defaultTime.Add(time.Hour),
+					LastFetched: defaultTime,
+				},
+			},
+			timeAfterFuncDelays: []time.Duration{time.Hour},
+			wakeupNotifications: 1,
+		},
+		{
+			name: "success without change times keeps the schedule",
+			initialSchedule: []*scheduledRepoUpdate{
+				{
+					Repo:                a,
+					Interval:            2 * minDelay,
+					Due:                 defaultTime.Add(2 * minDelay),
+					LastError:           errUpdate.Error(),
+					ConsecutiveFailures: 1,
+				},
+			},
+			recordCalls: []*recordCall{
+				{repo: a, time: defaultTime},
+			},
+			finalSchedule: []*scheduledRepoUpdate{
+				{
+					Repo:     a,
+					Interval: 2 * minDelay,
+					Due:      defaultTime.Add(2 * minDelay),
+				},
+			},
+		},
 		{
 			name: "heap reorders correctly",
 			initialSchedule: []*scheduledRepoUpdate{
@@ -947,19 +1219,19 @@ func TestSchedule_updateInterval(t *testing.T) {
 				{Repo: e, Interval: minDelay, Due: defaultTime.Add(4 * time.Minute)},
 				{Repo: b, Interval: minDelay, Due: defaultTime.Add(5 * time.Minute)},
 			},
-			updateCalls: []*updateCall{
-				{repo: a, time: defaultTime, interval: 1 * time.Minute},
-				{repo: b, time: defaultTime, interval: 2 * time.Minute},
-				{repo: c, time: defaultTime, interval: 3 * time.Minute},
-				{repo: d, time: defaultTime, interval: 4 * time.Minute},
-				{repo: e, time: defaultTime, interval: 5 * time.Minute},
+			recordCalls: []*recordCall{
+				{repo: a, time: defaultTime, resp: changedAgo(defaultTime, 1*time.Minute)},
+				{repo: b, time: defaultTime, resp: changedAgo(defaultTime, 2*time.Minute)},
+				{repo: c, time: defaultTime, resp: changedAgo(defaultTime, 3*time.Minute)},
+				{repo: d, time: defaultTime, resp: changedAgo(defaultTime, 4*time.Minute)},
+				{repo: e, time: defaultTime, resp: changedAgo(defaultTime, 5*time.Minute)},
 			},
 			finalSchedule: []*scheduledRepoUpdate{
-				{Repo: a, Interval: 1 * time.Minute, Due: defaultTime.Add(1 * time.Minute)},
-				{Repo: b, Interval: 2 * time.Minute, Due: defaultTime.Add(2 * time.Minute)},
-				{Repo: c, Interval: 3 * time.Minute, Due: defaultTime.Add(3 * time.Minute)},
-				{Repo: d, Interval: 4 * time.Minute, Due: defaultTime.Add(4 * time.Minute)},
-				{Repo: e, Interval: 5 * time.Minute, Due: defaultTime.Add(5 * time.Minute)},
+				{Repo: a, Interval: 1 * time.Minute, Due: defaultTime.Add(1 * time.Minute), LastFetched: defaultTime},
+				{Repo: b, Interval: 2 * time.Minute, Due: defaultTime.Add(2 * time.Minute), LastFetched: defaultTime},
+				{Repo: c, Interval: 3 * time.Minute, Due: defaultTime.Add(3 * time.Minute), LastFetched: defaultTime},
+				{Repo: d, Interval: 4 * time.Minute, Due: defaultTime.Add(4 * time.Minute), LastFetched: defaultTime},
+				{Repo: e, Interval: 5 * time.Minute, Due: defaultTime.Add(5 * time.Minute), LastFetched: defaultTime},
 			},
 			timeAfterFuncDelays: []time.Duration{time.Minute, time.Minute, time.Minute, time.Minute, time.Minute},
 			wakeupNotifications: 5,
@@ -974,9 +1246,16 @@ func TestSchedule_updateInterval(t *testing.T) {
 			s := NewUpdateScheduler()
 			setupInitialSchedule(s, test.initialSchedule)
 
-			for _, call := range test.updateCalls {
+			for _, call := range test.recordCalls {
 				mockTime(call.time)
-				s.schedule.updateInterval(call.repo, call.interval)
+				state := s.schedule.recordUpdate(call.repo, call.resp, call.err)
+				if update := s.schedule.index[call.repo.ID]; update == nil {
+					if state != nil {
+						t.Errorf("got schedule %+v for repo %q that isn't in the schedule", state, call.repo.Name)
+					}
+				} else if diff := cmp.Diff(update.state(), state); diff != "" {
+					t.Errorf("returned schedule of repo %q mismatch (-want +got):\n%s", call.repo.Name, diff)
+				}
 			}
 
 			verifySchedule(t, s, test.finalSchedule)
@@ -1319,13 +1598,73 @@ func TestUpdateScheduler_runUpdateLoop(t *testing.T) {
 				},
 			},
 			finalSchedule: []*scheduledRepoUpdate{
-				{Repo: a, Interval: time.Minute, Due: defaultTime.Add(time.Minute)},
+				{Repo: a, Interval: time.Minute, Due: defaultTime.Add(time.Minute), LastFetched: defaultTime.Add(2 * time.Minute)},
 			},
 			timeAfterFuncDelays: []time.Duration{time.Minute},
 			expectedNotifications: func(s *updateScheduler) []chan struct{} {
 				return []chan struct{}{s.schedule.wakeup}
 			},
 		},
+		{
+			name:                   "failed update backs off",
+			gitMaxConcurrentClones: 1,
+			initialSchedule: []*scheduledRepoUpdate{
+				{Repo: a, Interval: time.Hour, Due: defaultTime.Add(time.Hour), ConsecutiveFailures: 2},
+			},
+			initialQueue: []*repoUpdate{
+				{Repo: a, Seq: 1},
+			},
+			mockRequestRepoUpdates: []*mockRequestRepoUpdate{
+				{
+					repo: a,
+					resp: &gitserverprotocol.RepoUpdateResponse{
+						LastFetched: timePtr(defaultTime.Add(-time.Hour)),
+						LastChanged: timePtr(defaultTime.Add(-2 * time.Hour)),
+						Error:       "fetch failed",
+					},
+				},
+			},
+			finalSchedule: []*scheduledRepoUpdate{
+				{
+					Repo:                a,
+					Interval:            8 * minDelay,
+					Due:                 defaultTime.Add(8 * minDelay),
+					LastFetched:         defaultTime.Add(-time.Hour),
+					LastError:           "fetch failed",
+					ConsecutiveFailures: 3,
+				},
+			},
+			timeAfterFuncDelays: []time.Duration{8 * minDelay},
+			expectedNotifications: func(s *updateScheduler) []chan struct{} {
+				return []chan struct{}{s.schedule.wakeup}
+			},
+		},
+		{
+			name:                   "successful update resets failures",
+			gitMaxConcurrentClones: 1,
+			initialSchedule: []*scheduledRepoUpdate{
+				{Repo: a, Interval: maxDelay, Due: defaultTime.Add(time.Hour), LastError: "fetch failed", ConsecutiveFailures: 9},
+			},
+			initialQueue: []*repoUpdate{
+				{Repo: a, Seq: 1},
+			},
+			mockRequestRepoUpdates: []*mockRequestRepoUpdate{
+				{
+					repo: a,
+					resp: &gitserverprotocol.RepoUpdateResponse{
+						LastFetched: timePtr(defaultTime),
+						LastChanged: timePtr(defaultTime.Add(-2 * time.Hour)),
+					},
+				},
+			},
+			finalSchedule: []*scheduledRepoUpdate{
+				{Repo: a, Interval: time.Hour, Due: defaultTime.Add(time.Hour), LastFetched: defaultTime},
+			},
+			timeAfterFuncDelays: []time.Duration{time.Hour},
+			expectedNotifications: func(s *updateScheduler) []chan struct{} {
+				return []chan struct{}{s.schedule.wakeup}
+			},
+		},
 	}
 
 	for _, test := range tests {
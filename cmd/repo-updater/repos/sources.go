@@ -88,6 +88,8 @@ func newSource(svc *ExternalService, cf *httpcli.Factory, rl *rate.Limiter) (Sou
 		return NewAWSCodeCommitSource(svc, cf)
 	case "other":
 		return NewOtherSource(svc, cf)
+	case "perforce":
+		return NewPerforceSource(svc)
 	default:
 		panic(fmt.Sprintf("source not implemented for external service kind %q", svc.Kind))
 	}
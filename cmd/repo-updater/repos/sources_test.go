@@ -443,6 +443,16 @@ func TestSources_ListRepos(t *testing.T) {
 					RepositoryPathPattern: "a/b/c/{name}",
 				}),
 			},
+			{
+				Kind: "PERFORCE",
+				Config: marshalJSON(t, &schema.PerforceConnection{
+					P4Port:                "ssl:perforce.sgdev.org:1666",
+					P4User:                "admin",
+					P4Passwd:              "pa$$word",
+					Depots:                []string{"//Sourcegraph/", "//Engineering/Cloud/"},
+					RepositoryPathPattern: "a/b/c/{depot}",
+				}),
+			},
 			{
 				Kind: "GITOLITE",
 				Config: marshalJSON(t, &schema.GitoliteConnection{
@@ -504,6 +514,15 @@ func TestSources_ListRepos(t *testing.T) {
 							"__WARNING_DO_NOT_PUT_ANY_PRIVATE_CODE_IN_HERE",
 							"test",
 						}
+					case "PERFORCE":
+						wantNames = []string{
+							"a/b/c/Sourcegraph",
+							"a/b/c/Engineering/Cloud",
+						}
+						wantURIs = []string{
+							"perforce.sgdev.org/Sourcegraph",
+							"perforce.sgdev.org/Engineering/Cloud",
+						}
 					case "GITOLITE":
 						wantNames = []string{
 							"gitolite.mycorp.com/bar",
@@ -18,6 +18,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitlab"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitolite"
+	"github.com/sourcegraph/sourcegraph/internal/extsvc/perforce"
 )
 
 // A Store exposes methods to read and write repos and external services.
@@ -433,6 +434,193 @@ func listAllRepoNamesQuery(cursor, limit int64) *sqlf.Query {
 	return sqlf.Sprintf(listAllRepoNamesQueryFmtstr, cursor, limit)
 }
 
+// ListRepoUpdateSchedules lists the persisted update schedules of all repos.
+func (s DBStore) ListRepoUpdateSchedules(ctx context.Context) (schedules []*RepoUpdateSchedule, _ error) {
+	return schedules, s.paginate(ctx, 0, 0, listRepoUpdateSchedulesQuery,
+		func(sc scanner) (last, count int64, err error) {
+			var (
+				sched           RepoUpdateSchedule
+				intervalSeconds int64
+			)
+			err = sc.Scan(
+				&sched.RepoID,
+				&intervalSeconds,
+				&sched.Due,
+				&dbutil.NullTime{Time: &sched.LastFetched},
+				&sched.LastError,
+				&sched.ConsecutiveFailures,
+			)
+			if err != nil {
+				return 0, 0, err
+			}
+			sched.Interval = time.Duration(intervalSeconds) * time.Second
+			schedules = append(schedules, &sched)
+			return int64(sched.RepoID), 1, nil
+		},
+	)
+}
+
+const listRepoUpdateSchedulesQueryFmtstr = `
+-- source: cmd/repo-updater/repos/store.go:DBStore.ListRepoUpdateSchedules
+SELECT
+  repo_id,
+  interval_seconds,
+  due,
+  last_fetched,
+  last_error,
+  consecutive_failures
+FROM repo_update_schedule
+WHERE repo_id > %s
+ORDER BY repo_id ASC LIMIT %s
+`
+
+func listRepoUpdateSchedulesQuery(cursor, limit int64) *sqlf.Query {
+	return sqlf.Sprintf(listRepoUpdateSchedulesQueryFmtstr, cursor, limit)
+}
+
+// UpsertRepoUpdateSchedules updates or inserts the given update schedules of repos.
+func (s DBStore) UpsertRepoUpdateSchedules(ctx context.Context, schedules ...*RepoUpdateSchedule) error {
+	if len(schedules) == 0 {
+		return nil
+	}
+
+	values := make([]*sqlf.Query, 0, len(schedules))
+	for _, sched := range schedules {
+		values = append(values, sqlf.Sprintf(
+			upsertRepoUpdateSchedulesQueryValueFmtstr,
+			sched.RepoID,
+			int64(sched.Interval/time.Second),
+			sched.Due.UTC(),
+			nullTimeColumn(sched.LastFetched),
+			sched.LastError,
+			sched.ConsecutiveFailures,
+		))
+	}
+
+	q := sqlf.Sprintf(upsertRepoUpdateSchedulesQueryFmtstr, sqlf.Join(values, ",\n"))
+	rows, err := s.db.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	if err != nil {
+		return err
+	}
+	return rows.Close()
+}
+
+const upsertRepoUpdateSchedulesQueryValueFmtstr = `(%s, %s, %s, %s, %s, %s, now())`
+
+const upsertRepoUpdateSchedulesQueryFmtstr = `
+-- source: cmd/repo-updater/repos/store.go:DBStore.UpsertRepoUpdateSchedules
+INSERT INTO repo_update_schedule (
+  repo_id,
+  interval_seconds,
+  due,
+  last_fetched,
+  last_error,
+  consecutive_failures,
+  updated_at
+)
+VALUES %s
+ON CONFLICT (repo_id) DO UPDATE
+SET
+  interval_seconds     = excluded.interval_seconds,
+  due                  = excluded.due,
+  last_fetched         = excluded.last_fetched,
+  last_error           = excluded.last_error,
+  consecutive_failures = excluded.consecutive_failures,
+  updated_at           = excluded.updated_at
+`
+
+// RepoDiskUsage is the size of a repo on a gitserver shard, as reported by the gitserver.
+type RepoDiskUsage struct {
+	Name      api.RepoName `json:"name"`
+	SizeBytes int64        `json:"size_bytes"`
+}
+
+// ReplaceShardDiskUsage replaces the recorded disk usage of the repos on the given gitserver
+// shard with usage. Repos which are not in the repo table are ignored.
+func (s DBStore) ReplaceShardDiskUsage(ctx context.Context, shard string, usage []*RepoDiskUsage) error {
+	if usage == nil {
+		usage = []*RepoDiskUsage{}
+	}
+	batch, err := json.Marshal(usage)
+	if err != nil {
+		return err
+	}
+
+	q := sqlf.Sprintf(replaceShardDiskUsageQueryFmtstr, string(batch), shard, shard)
+	rows, err := s.db.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	if err != nil {
+		return err
+	}
+	return rows.Close()
+}
+
+const replaceShardDiskUsageQueryFmtstr = `
+-- source: cmd/repo-updater/repos/store.go:DBStore.ReplaceShardDiskUsage
+WITH batch AS (
+  SELECT * FROM json_to_recordset(%s) AS (name citext, size_bytes bigint)
+),
+upserted AS (
+  INSERT INTO repo_disk_usage (repo_id, shard, size_bytes, updated_at)
+  SELECT DISTINCT ON (repo.id) repo.id, %s, batch.size_bytes, now()
+  FROM batch
+  JOIN repo ON repo.name = batch.name AND repo.deleted_at IS NULL
+  ON CONFLICT (repo_id, shard) DO UPDATE
+  SET
+    size_bytes = excluded.size_bytes,
+    updated_at = excluded.updated_at
+  RETURNING repo_id
+)
+DELETE FROM repo_disk_usage
+WHERE shard = %s AND repo_id NOT IN (SELECT repo_id FROM upserted)
+`
+
+// DeleteDiskUsageOfOtherShards deletes the recorded disk usage of all gitserver shards but the
+// given ones, e.g. because they were removed.
+func (s DBStore) DeleteDiskUsageOfOtherShards(ctx context.Context, shards []string) error {
+	conds := []*sqlf.Query{sqlf.Sprintf("TRUE")}
+	for _, shard := range shards {
+		conds = append(conds, sqlf.Sprintf("shard <> %s", shard))
+	}
+	q := sqlf.Sprintf(deleteDiskUsageOfOtherShardsQueryFmtstr, sqlf.Join(conds, " AND "))
+	rows, err := s.db.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	if err != nil {
+		return err
+	}
+	return rows.Close()
+}
+
+const deleteDiskUsageOfOtherShardsQueryFmtstr = `
+-- source: cmd/repo-updater/repos/store.go:DBStore.DeleteDiskUsageOfOtherShards
+DELETE FROM repo_disk_usage WHERE %s
+`
+
+// ListShardDiskUsage lists the recorded disk usage of the repos on the given gitserver shard.
+func (s DBStore) ListShardDiskUsage(ctx context.Context, shard string) (usage []*RepoDiskUsage, _ error) {
+	q := sqlf.Sprintf(listShardDiskUsageQueryFmtstr, shard)
+	rows, err := s.db.QueryContext(ctx, q.Query(sqlf.PostgresBindVar), q.Args()...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var u RepoDiskUsage
+		if err := rows.Scan(&u.Name, &u.SizeBytes); err != nil {
+			return nil, err
+		}
+		usage = append(usage, &u)
+	}
+	return usage, rows.Err()
+}
+
+const listShardDiskUsageQueryFmtstr = `
+-- source: cmd/repo-updater/repos/store.go:DBStore.ListShardDiskUsage
+SELECT repo.name, repo_disk_usage.size_bytes
+FROM repo_disk_usage
+JOIN repo ON repo.id = repo_disk_usage.repo_id
+WHERE repo_disk_usage.shard = %s
+ORDER BY repo.name ASC
+`
+
 // a paginatedQuery returns a query with the given pagination
 // parameters
 type paginatedQuery func(cursor, limit int64) *sqlf.Query
@@ -853,6 +1041,8 @@ func scanRepo(r *Repo, s scanner) error {
 		r.Metadata = new(awscodecommit.Repository)
 	case "gitolite":
 		r.Metadata = new(gitolite.Repo)
+	case "perforce":
+		r.Metadata = new(perforce.Depot)
 	default:
 		return nil
 	}
@@ -1095,6 +1095,119 @@ func testStoreListReposPagination(store repos.Store) func(*testing.T) {
 	}
 }
 
+func testDBStoreRepoUpdateSchedules(store *repos.DBStore) func(*testing.T) {
+	return func(t *testing.T) {
+		ctx := context.Background()
+
+		txstore, err := store.Transact(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer txstore.Done(&errRollback)
+		tx := txstore.(*repos.DBStore)
+
+		repo := &repos.Repo{
+			Name: "github.com/foo/scheduled",
+			ExternalRepo: api.ExternalRepoSpec{
+				ID:          "scheduled",
+				ServiceType: "github",
+				ServiceID:   "http://github.com",
+			},
+		}
+		if err := tx.UpsertRepos(ctx, repo); err != nil {
+			t.Fatal(err)
+		}
+
+		now := time.Now().UTC().Truncate(time.Microsecond)
+		want := []*repos.RepoUpdateSchedule{{
+			RepoID:   repo.ID,
+			Interval: time.Hour,
+			Due:      now.Add(time.Hour),
+		}}
+		if err := tx.UpsertRepoUpdateSchedules(ctx, want...); err != nil {
+			t.Fatal(err)
+		}
+
+		// Upserting again updates the existing schedule.
+		want[0].LastFetched = now
+		want[0].LastError = "fetch failed"
+		want[0].ConsecutiveFailures = 2
+		if err := tx.UpsertRepoUpdateSchedules(ctx, want...); err != nil {
+			t.Fatal(err)
+		}
+
+		have, err := tx.ListRepoUpdateSchedules(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(want, have, cmpopts.EquateApproxTime(0)); diff != "" {
+			t.Errorf("schedules mismatch (-want +have):\n%s", diff)
+		}
+	}
+}
+
+func testDBStoreRepoDiskUsage(store *repos.DBStore) func(*testing.T) {
+	return func(t *testing.T) {
+		ctx := context.Background()
+
+		txstore, err := store.Transact(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer txstore.Done(&errRollback)
+		tx := txstore.(*repos.DBStore)
+
+		for _, name := range []string{"github.com/foo/a", "github.com/foo/b"} {
+			repo := &repos.Repo{
+				Name: name,
+				ExternalRepo: api.ExternalRepoSpec{
+					ID:          name,
+					ServiceType: "github",
+					ServiceID:   "http://github.com",
+				},
+			}
+			if err := tx.UpsertRepos(ctx, repo); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		usage := []*repos.RepoDiskUsage{
+			{Name: "github.com/foo/a", SizeBytes: 1},
+			{Name: "github.com/foo/b", SizeBytes: 2},
+			{Name: "github.com/foo/unknown", SizeBytes: 3},
+		}
+		if err := tx.ReplaceShardDiskUsage(ctx, "gitserver-0", usage); err != nil {
+			t.Fatal(err)
+		}
+		if err := tx.ReplaceShardDiskUsage(ctx, "gitserver-1", usage[:1]); err != nil {
+			t.Fatal(err)
+		}
+
+		// Replacing the disk usage of a shard updates and removes its repos.
+		if err := tx.ReplaceShardDiskUsage(ctx, "gitserver-0", []*repos.RepoDiskUsage{{Name: "github.com/foo/b", SizeBytes: 4}}); err != nil {
+			t.Fatal(err)
+		}
+		have, err := tx.ListShardDiskUsage(ctx, "gitserver-0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff([]*repos.RepoDiskUsage{{Name: "github.com/foo/b", SizeBytes: 4}}, have); diff != "" {
+			t.Errorf("disk usage mismatch (-want +have):\n%s", diff)
+		}
+
+		if err := tx.DeleteDiskUsageOfOtherShards(ctx, []string{"gitserver-0"}); err != nil {
+			t.Fatal(err)
+		}
+		have, err = tx.ListShardDiskUsage(ctx, "gitserver-1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(have) != 0 {
+			t.Errorf("got disk usage %+v of deleted shard, want none", have)
+		}
+	}
+}
+
 func testDBStoreTransact(store *repos.DBStore) func(*testing.T) {
 	return func(t *testing.T) {
 		ctx := context.Background()
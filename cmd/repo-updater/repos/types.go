@@ -22,6 +22,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/github"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitlab"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc/gitolite"
+	gitserverprotocol "github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
 	"github.com/sourcegraph/sourcegraph/internal/jsonc"
 	"github.com/sourcegraph/sourcegraph/schema"
 	"github.com/xeipuuv/gojsonschema"
@@ -106,6 +107,8 @@ func (e ExternalService) Configuration() (cfg interface{}, _ error) {
 		cfg = &schema.PhabricatorConnection{}
 	case "other":
 		cfg = &schema.OtherExternalServiceConnection{}
+	case "perforce":
+		cfg = &schema.PerforceConnection{}
 	default:
 		return nil, fmt.Errorf("unknown external service kind %q", e.Kind)
 	}
@@ -476,6 +479,8 @@ func (e ExternalService) schema() string {
 		return schema.PhabricatorSchemaJSON
 	case "other":
 		return schema.OtherExternalServiceSchemaJSON
+	case "perforce":
+		return schema.PerforceSchemaJSON
 	default:
 		return ""
 	}
@@ -574,6 +579,18 @@ type Repo struct {
 type SourceInfo struct {
 	ID       string
 	CloneURL string
+
+	// LFS is whether Git LFS objects should be fetched when cloning from
+	// this source.
+	LFS bool `json:",omitempty"`
+
+	// LFSRefs are the refs whose Git LFS objects are fetched. If empty, the
+	// objects of HEAD are fetched.
+	LFSRefs []string `json:",omitempty"`
+
+	// Clone is how gitserver should clone the repo when cloning from this
+	// source, or nil to clone its whole history.
+	Clone *gitserverprotocol.CloneStrategy `json:",omitempty"`
 }
 
 // ExternalServiceID returns the ID of the external service this
@@ -603,6 +620,55 @@ func (r *Repo) CloneURLs() []string {
 	return urls
 }
 
+// LFS returns whether Git LFS objects should be fetched for this repo, which
+// is the case if any of its sources enables it.
+func (r *Repo) LFS() bool {
+	for _, src := range r.Sources {
+		if src != nil && src.LFS {
+			return true
+		}
+	}
+	return false
+}
+
+// CloneStrategy returns how gitserver should clone this repo, or nil to clone
+// its whole history. If its sources disagree, the source with the smallest ID
+// wins.
+func (r *Repo) CloneStrategy() *gitserverprotocol.CloneStrategy {
+	var strategy *gitserverprotocol.CloneStrategy
+	var id string
+	for _, src := range r.Sources {
+		if src != nil && src.Clone != nil && (strategy == nil || src.ID < id) {
+			strategy, id = src.Clone, src.ID
+		}
+	}
+	return strategy
+}
+
+// LFSRefs returns the refs whose Git LFS objects should be fetched for this
+// repo, which are the refs of all sources which enable Git LFS.
+func (r *Repo) LFSRefs() []string {
+	set := map[string]bool{}
+	for _, src := range r.Sources {
+		if src == nil || !src.LFS {
+			continue
+		}
+		for _, ref := range src.LFSRefs {
+			set[ref] = true
+		}
+	}
+	if len(set) == 0 {
+		return nil
+	}
+
+	refs := make([]string, 0, len(set))
+	for ref := range set {
+		refs = append(refs, ref)
+	}
+	sort.Strings(refs)
+	return refs
+}
+
 // ExternalServiceIDs returns the IDs of the external services this
 // repo belongs to.
 func (r *Repo) ExternalServiceIDs() []int64 {
@@ -19,7 +19,9 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/repo-updater/repoupdater"
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/db/dbconn"
 	"github.com/sourcegraph/sourcegraph/internal/db/dbutil"
+	"github.com/sourcegraph/sourcegraph/internal/db/globalstatedb"
 	"github.com/sourcegraph/sourcegraph/internal/debugserver"
 	"github.com/sourcegraph/sourcegraph/internal/env"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver"
@@ -96,6 +98,9 @@ func Main(enterpriseInit EnterpriseInit) {
 	}
 
 	scheduler := repos.NewUpdateScheduler()
+	if err := scheduler.LoadSchedule(ctx, repos.NewDBStore(db, sql.TxOptions{})); err != nil {
+		log.Fatalf("failed to load repo update schedule: %v", err)
+	}
 	server := &repoupdater.Server{
 		Store:           store,
 		Scheduler:       scheduler,
@@ -192,6 +197,18 @@ func Main(enterpriseInit EnterpriseInit) {
 	go repos.RunScheduler(ctx, scheduler)
 	log15.Debug("started scheduler")
 
+	// Records the disk usage of repos on gitserver for site admins.
+	go repos.RunRepoDiskUsageSyncWorker(ctx, repos.NewDBStore(db, sql.TxOptions{}), 10*time.Minute)
+
+	// Creates the push webhooks of code hosts that have a webhook secret configured. The site ID
+	// makes the name of the Bitbucket Server webhook unique per Sourcegraph instance.
+	dbconn.Global = db
+	globalState, err := globalstatedb.Get(ctx)
+	if err != nil {
+		log.Fatalf("failed to get site ID: %v", err)
+	}
+	go repos.RunPushWebhookSyncWorker(ctx, store, globalState.SiteID, 1*time.Minute)
+
 	host := ""
 	if env.InsecureDev {
 		host = "127.0.0.1"
@@ -105,6 +105,15 @@ type PatternInfo struct {
 
 	// CombyRule is a rule that constrains matching for structural search. It only applies when IsStructuralPat is true.
 	CombyRule string
+
+	// IsSecretSearch if true will search file content for secrets (such as credentials) using
+	// the rule set in internal/search/secrets instead of matching Pattern. Previews of the
+	// returned line matches are masked.
+	IsSecretSearch bool
+
+	// SecretRules are the IDs of the secret detection rules to apply. If empty, all rules are
+	// applied. It only applies when IsSecretSearch is true.
+	SecretRules []string
 }
 
 func (p *PatternInfo) String() string {
@@ -119,6 +128,13 @@ func (p *PatternInfo) String() string {
 			args = append(args, "comby")
 		}
 	}
+	if p.IsSecretSearch {
+		if len(p.SecretRules) > 0 {
+			args = append(args, fmt.Sprintf("secrets:%s", strings.Join(p.SecretRules, "|")))
+		} else {
+			args = append(args, "secrets")
+		}
+	}
 	if p.IsWordMatch {
 		args = append(args, "word")
 	}
@@ -193,4 +209,8 @@ type LineMatch struct {
 
 	// LimitHit is true if OffsetAndLengths may not include all OffsetAndLengths.
 	LimitHit bool
+
+	// RuleIDs is set for secret detection searches. It contains the ID of the secret
+	// detection rule that produced each match, in the same order as OffsetAndLengths.
+	RuleIDs []string `json:",omitempty"`
 }
@@ -142,6 +142,7 @@ func (s *Service) search(ctx context.Context, p *protocol.Request) (matches []pr
 	span.SetTag("pattern", p.Pattern)
 	span.SetTag("isRegExp", strconv.FormatBool(p.IsRegExp))
 	span.SetTag("isStructuralPat", strconv.FormatBool(p.IsStructuralPat))
+	span.SetTag("isSecretSearch", strconv.FormatBool(p.IsSecretSearch))
 	span.SetTag("languages", p.Languages)
 	span.SetTag("isWordMatch", strconv.FormatBool(p.IsWordMatch))
 	span.SetTag("isCaseSensitive", strconv.FormatBool(p.IsCaseSensitive))
@@ -229,6 +230,9 @@ func (s *Service) search(ctx context.Context, p *protocol.Request) (matches []pr
 
 	if p.IsStructuralPat {
 		matches, limitHit, err = structuralSearch(ctx, zipPath, p.Pattern, p.CombyRule, p.Languages, p.IncludePatterns, p.Repo)
+	} else if p.IsSecretSearch {
+		// Secrets are only searched for in file content.
+		matches, limitHit, err = regexSearch(ctx, rg, zf, p.FileMatchLimit, true, false)
 	} else {
 		matches, limitHit, err = regexSearch(ctx, rg, zf, p.FileMatchLimit, p.PatternMatchesContent, p.PatternMatchesPath)
 	}
@@ -243,7 +247,7 @@ func validateParams(p *protocol.Request) error {
 	if len(p.Commit) != 40 {
 		return errors.Errorf("Commit must be resolved (Commit=%q)", p.Commit)
 	}
-	if p.Pattern == "" && p.ExcludePattern == "" && len(p.IncludePatterns) == 0 {
+	if p.Pattern == "" && p.ExcludePattern == "" && len(p.IncludePatterns) == 0 && !p.IsSecretSearch {
 		return errors.New("At least one of pattern and include/exclude pattners must be non-empty")
 	}
 	return nil
@@ -16,6 +16,7 @@ import (
 
 	"github.com/sourcegraph/sourcegraph/cmd/searcher/protocol"
 	"github.com/sourcegraph/sourcegraph/internal/pathmatch"
+	"github.com/sourcegraph/sourcegraph/internal/search/secrets"
 	"github.com/sourcegraph/sourcegraph/internal/store"
 	"github.com/sourcegraph/sourcegraph/internal/trace/ot"
 
@@ -61,6 +62,10 @@ type readerGrep struct {
 	// re. It is the output of the longestLiteral function. It is only set if
 	// the regex has an empty LiteralPrefix.
 	literalSubstring []byte
+
+	// secretRules are the secret detection rules to apply instead of re, for secret
+	// detection searches. It is nil for other searches.
+	secretRules []*secrets.Rule
 }
 
 // compile returns a readerGrep for matching p.
@@ -68,8 +73,15 @@ func compile(p *protocol.PatternInfo) (*readerGrep, error) {
 	var (
 		re               *regexp.Regexp
 		literalSubstring []byte
+		secretRules      []*secrets.Rule
 	)
-	if p.Pattern != "" {
+	if p.IsSecretSearch {
+		var err error
+		secretRules, err = secrets.Select(p.SecretRules)
+		if err != nil {
+			return nil, err
+		}
+	} else if p.Pattern != "" {
 		expr := p.Pattern
 		if !p.IsRegExp {
 			expr = regexp.QuoteMeta(expr)
@@ -123,9 +135,10 @@ func compile(p *protocol.PatternInfo) (*readerGrep, error) {
 
 	return &readerGrep{
 		re:               re,
-		ignoreCase:       !p.IsCaseSensitive,
+		ignoreCase:       !p.IsCaseSensitive && !p.IsSecretSearch,
 		matchPath:        matchPath,
 		literalSubstring: literalSubstring,
+		secretRules:      secretRules,
 	}, nil
 }
 
@@ -137,6 +150,7 @@ func (rg *readerGrep) Copy() *readerGrep {
 		ignoreCase:       rg.ignoreCase,
 		matchPath:        rg.matchPath,
 		literalSubstring: rg.literalSubstring,
+		secretRules:      rg.secretRules,
 	}
 }
 
@@ -161,6 +175,11 @@ func (rg *readerGrep) Find(zf *store.ZipFile, f *store.SrcFile) (matches []proto
 	fileBuf := zf.DataFor(f)
 	fileMatchBuf := fileBuf
 
+	if rg.secretRules != nil {
+		matches, limitHit = rg.findSecrets(fileBuf)
+		return matches, limitHit, nil
+	}
+
 	// If we are ignoring case, we transform the input instead of
 	// relying on the regular expression engine which can be
 	// slow. compile has already lowercased the pattern. We also
@@ -337,7 +356,7 @@ func regexSearch(ctx context.Context, rg *readerGrep, zf *store.ZipFile, fileMat
 		matches   = []protocol.FileMatch{}
 	)
 
-	if rg.re == nil || (patternMatchesPaths && !patternMatchesContent) {
+	if (rg.re == nil && rg.secretRules == nil) || (patternMatchesPaths && !patternMatchesContent) {
 		// Fast path for only matching file paths (or with a nil pattern, which matches all files,
 		// so is effectively matching only on file paths).
 		for _, f := range files {
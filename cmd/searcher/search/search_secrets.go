@@ -0,0 +1,76 @@
+package search
+
+import (
+	"bytes"
+	"unicode/utf8"
+
+	"github.com/sourcegraph/sourcegraph/cmd/searcher/protocol"
+	"github.com/sourcegraph/sourcegraph/internal/search/secrets"
+)
+
+// findSecrets returns a LineMatch for each line in fileBuf that contains a secret
+// detected by rg.secretRules. The secrets in the previews are masked, so that the
+// search results don't spread the secrets further.
+func (rg *readerGrep) findSecrets(fileBuf []byte) (matches []protocol.LineMatch, limitHit bool) {
+	found := secrets.Find(rg.secretRules, fileBuf, maxLineMatches+1)
+	if len(found) > maxLineMatches {
+		found = found[:maxLineMatches]
+		limitHit = true
+	}
+
+	var (
+		lineNumber int
+		lineStart  int
+		lineEnd    int
+		ranges     [][2]int // byte ranges of the secrets on the current line, relative to lineStart
+	)
+	// flush masks the secrets in the preview of the current line.
+	flush := func() {
+		if len(matches) == 0 {
+			return
+		}
+		lm := &matches[len(matches)-1]
+		// Mask from right to left, because masking may change the byte offsets of
+		// everything after the masked secret.
+		for i := len(ranges) - 1; i >= 0; i-- {
+			lm.Preview = secrets.MaskRange(lm.Preview, ranges[i][0], ranges[i][1])
+		}
+		ranges = ranges[:0]
+	}
+
+	for _, m := range found {
+		if idx := bytes.LastIndexByte(fileBuf[lineStart:m.Start], '\n'); idx >= 0 || len(matches) == 0 {
+			if idx >= 0 {
+				lineNumber += bytes.Count(fileBuf[lineStart:lineStart+idx+1], []byte{'\n'})
+				lineStart += idx + 1
+			}
+			flush()
+			if idx := bytes.IndexByte(fileBuf[m.Start:], '\n'); idx >= 0 {
+				lineEnd = m.Start + idx
+			} else {
+				lineEnd = len(fileBuf)
+			}
+			matches = append(matches, protocol.LineMatch{
+				Preview:    string(fileBuf[lineStart:lineEnd]),
+				LineNumber: lineNumber,
+			})
+		}
+
+		// A secret that spans multiple lines is only reported on its first line.
+		end := m.End
+		if end > lineEnd {
+			end = lineEnd
+		}
+
+		lm := &matches[len(matches)-1]
+		lm.OffsetAndLengths = append(lm.OffsetAndLengths, [2]int{
+			utf8.RuneCount(fileBuf[lineStart:m.Start]),
+			utf8.RuneCount(fileBuf[m.Start:end]),
+		})
+		lm.RuleIDs = append(lm.RuleIDs, m.RuleID)
+		ranges = append(ranges, [2]int{m.Start - lineStart, end - lineStart})
+	}
+	flush()
+
+	return matches, limitHit
+}
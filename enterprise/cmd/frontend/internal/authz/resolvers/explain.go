@@ -0,0 +1,96 @@
+package resolvers
+
+import (
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+)
+
+var _ graphqlbackend.AuthorizationExplanationResolver = &authorizationExplanationResolver{}
+
+// authorizationExplanationResolver resolves the permissions data that is used to
+// decide whether a user can access a repository.
+type authorizationExplanationResolver struct {
+	user           *types.User
+	repo           *types.Repo
+	public         bool
+	unrestricted   bool
+	providers      []*authorizationProviderExplanationResolver
+	userPerms      *authorizationPermissionsExplanationResolver
+	repoPerms      *authorizationPermissionsExplanationResolver
+	pendingBindIDs []string
+}
+
+func (r *authorizationExplanationResolver) User() *graphqlbackend.UserResolver {
+	return graphqlbackend.NewUserResolver(r.user)
+}
+
+func (r *authorizationExplanationResolver) Repository() *graphqlbackend.RepositoryResolver {
+	return graphqlbackend.NewRepositoryResolver(r.repo)
+}
+
+func (r *authorizationExplanationResolver) Public() bool {
+	return r.public
+}
+
+func (r *authorizationExplanationResolver) Unrestricted() bool {
+	return r.unrestricted
+}
+
+func (r *authorizationExplanationResolver) Providers() []graphqlbackend.AuthorizationProviderExplanationResolver {
+	providers := make([]graphqlbackend.AuthorizationProviderExplanationResolver, len(r.providers))
+	for i := range r.providers {
+		providers[i] = r.providers[i]
+	}
+	return providers
+}
+
+func (r *authorizationExplanationResolver) UserPermissions() graphqlbackend.AuthorizationPermissionsExplanationResolver {
+	if r.userPerms == nil {
+		return nil
+	}
+	return r.userPerms
+}
+
+func (r *authorizationExplanationResolver) RepositoryPermissions() graphqlbackend.AuthorizationPermissionsExplanationResolver {
+	if r.repoPerms == nil {
+		return nil
+	}
+	return r.repoPerms
+}
+
+func (r *authorizationExplanationResolver) PendingBindIDs() []string {
+	if r.pendingBindIDs == nil {
+		return []string{}
+	}
+	return r.pendingBindIDs
+}
+
+type authorizationProviderExplanationResolver struct {
+	serviceType string
+	serviceID   string
+	accountID   string
+}
+
+func (r *authorizationProviderExplanationResolver) ServiceType() string {
+	return r.serviceType
+}
+
+func (r *authorizationProviderExplanationResolver) ServiceID() string {
+	return r.serviceID
+}
+
+func (r *authorizationProviderExplanationResolver) AccountID() *string {
+	if r.accountID == "" {
+		return nil
+	}
+	return &r.accountID
+}
+
+type authorizationPermissionsExplanationResolver struct {
+	permissionsInfoResolver
+	grantsAccess bool
+}
+
+func (r *authorizationPermissionsExplanationResolver) GrantsAccess() bool {
+	return r.grantsAccess
+}
@@ -16,9 +16,12 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
 	edb "github.com/sourcegraph/sourcegraph/enterprise/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/db/dbutil"
 	"github.com/sourcegraph/sourcegraph/internal/errcode"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/repoupdater"
+	"github.com/sourcegraph/sourcegraph/internal/repoupdater/protocol"
 )
 
 type Resolver struct {
@@ -279,3 +282,226 @@ func (r *Resolver) RepositoryPermissionsInfo(ctx context.Context, id graphql.ID)
 		updatedAt: p.UpdatedAt,
 	}, nil
 }
+
+func (r *Resolver) ScheduleUserPermissionsSync(ctx context.Context, args *graphqlbackend.UserPermissionsSyncArgs) (*graphqlbackend.EmptyResponse, error) {
+	// 🚨 SECURITY: Only site admins can trigger user permissions syncs.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	userID, err := graphqlbackend.UnmarshalUserID(args.User)
+	if err != nil {
+		return nil, err
+	}
+	// Make sure the user ID is valid.
+	if _, err = db.Users.GetByID(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	if err := repoupdater.DefaultClient.SchedulePermsSync(ctx, protocol.PermsSyncRequest{
+		UserIDs: []int32{userID},
+	}); err != nil {
+		return nil, err
+	}
+	return &graphqlbackend.EmptyResponse{}, nil
+}
+
+func (r *Resolver) ScheduleRepositoryPermissionsSync(ctx context.Context, args *graphqlbackend.RepositoryPermissionsSyncArgs) (*graphqlbackend.EmptyResponse, error) {
+	// 🚨 SECURITY: Only site admins can trigger repository permissions syncs.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	repoID, err := graphqlbackend.UnmarshalRepositoryID(args.Repository)
+	if err != nil {
+		return nil, err
+	}
+	// Make sure the repo ID is valid.
+	if _, err = db.Repos.Get(ctx, repoID); err != nil {
+		return nil, err
+	}
+
+	if err := repoupdater.DefaultClient.SchedulePermsSync(ctx, protocol.PermsSyncRequest{
+		RepoIDs: []api.RepoID{repoID},
+	}); err != nil {
+		return nil, err
+	}
+	return &graphqlbackend.EmptyResponse{}, nil
+}
+
+func (r *Resolver) AuthorizationExplain(ctx context.Context, args *graphqlbackend.AuthorizationExplainArgs) (graphqlbackend.AuthorizationExplanationResolver, error) {
+	// 🚨 SECURITY: Only site admins can query repository permissions.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	userID, err := graphqlbackend.UnmarshalUserID(args.User)
+	if err != nil {
+		return nil, err
+	}
+	user, err := db.Users.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	repoID, err := graphqlbackend.UnmarshalRepositoryID(args.Repository)
+	if err != nil {
+		return nil, err
+	}
+	repo, err := db.Repos.Get(ctx, repoID)
+	if err != nil {
+		return nil, err
+	}
+
+	accts, err := r.store.ListExternalAccounts(ctx, user.ID)
+	if err != nil {
+		return nil, errors.Wrap(err, "list external accounts")
+	}
+
+	e := &authorizationExplanationResolver{
+		user:   user,
+		repo:   repo,
+		public: !repo.Private,
+	}
+
+	// The pending permissions are looked up by the account IDs of the user on each
+	// applicable provider.
+	var pendingAccounts []*extsvc.Accounts
+
+	allowByDefault, providers := authz.GetProviders()
+	for _, p := range providers {
+		if p.ServiceType() != repo.ExternalRepo.ServiceType || p.ServiceID() != repo.ExternalRepo.ServiceID {
+			continue
+		}
+
+		pe := &authorizationProviderExplanationResolver{
+			serviceType: p.ServiceType(),
+			serviceID:   p.ServiceID(),
+		}
+		for _, acct := range accts {
+			if acct.ServiceType == p.ServiceType() && acct.ServiceID == p.ServiceID() {
+				pe.accountID = acct.AccountID
+				pendingAccounts = append(pendingAccounts, &extsvc.Accounts{
+					ServiceType: acct.ServiceType,
+					ServiceID:   acct.ServiceID,
+					AccountIDs:  []string{acct.AccountID},
+				})
+				break
+			}
+		}
+		e.providers = append(e.providers, pe)
+	}
+
+	cfg := globals.PermissionsUserMapping()
+	if cfg.Enabled {
+		bindIDs, err := userBindIDs(ctx, user, cfg.BindID)
+		if err != nil {
+			return nil, err
+		}
+
+		pe := &authorizationProviderExplanationResolver{
+			serviceType: authz.SourcegraphServiceType,
+			serviceID:   authz.SourcegraphServiceID,
+		}
+		if len(bindIDs) > 0 {
+			pe.accountID = bindIDs[0]
+		}
+		e.providers = append(e.providers, pe)
+		pendingAccounts = append(pendingAccounts, &extsvc.Accounts{
+			ServiceType: authz.SourcegraphServiceType,
+			ServiceID:   authz.SourcegraphServiceID,
+			AccountIDs:  bindIDs,
+		})
+	}
+
+	e.unrestricted = !cfg.Enabled && len(e.providers) == 0 && allowByDefault
+
+	up := &authz.UserPermissions{
+		UserID: user.ID,
+		Perm:   authz.Read, // Note: We currently only support read for repository permissions.
+		Type:   authz.PermRepos,
+	}
+	err = r.store.LoadUserPermissions(ctx, up)
+	if err != nil && err != authz.ErrPermsNotFound {
+		return nil, errors.Wrap(err, "load user permissions")
+	} else if err == nil {
+		e.userPerms = &authorizationPermissionsExplanationResolver{
+			permissionsInfoResolver: permissionsInfoResolver{
+				perms:     up.Perm,
+				syncedAt:  up.SyncedAt,
+				updatedAt: up.UpdatedAt,
+			},
+			grantsAccess: up.IDs.Contains(uint32(repo.ID)),
+		}
+	}
+
+	rp := &authz.RepoPermissions{
+		RepoID: int32(repo.ID),
+		Perm:   authz.Read, // Note: We currently only support read for repository permissions.
+	}
+	err = r.store.LoadRepoPermissions(ctx, rp)
+	if err != nil && err != authz.ErrPermsNotFound {
+		return nil, errors.Wrap(err, "load repository permissions")
+	} else if err == nil {
+		e.repoPerms = &authorizationPermissionsExplanationResolver{
+			permissionsInfoResolver: permissionsInfoResolver{
+				perms:     rp.Perm,
+				syncedAt:  rp.SyncedAt,
+				updatedAt: rp.UpdatedAt,
+			},
+			grantsAccess: rp.UserIDs.Contains(uint32(user.ID)),
+		}
+	}
+
+	for _, accounts := range pendingAccounts {
+		for _, bindID := range accounts.AccountIDs {
+			p := &authz.UserPendingPermissions{
+				ServiceType: accounts.ServiceType,
+				ServiceID:   accounts.ServiceID,
+				BindID:      bindID,
+				Perm:        authz.Read, // Note: We currently only support read for repository permissions.
+				Type:        authz.PermRepos,
+			}
+			err = r.store.LoadUserPendingPermissions(ctx, p)
+			if err == authz.ErrPermsNotFound {
+				continue
+			} else if err != nil {
+				return nil, errors.Wrap(err, "load user pending permissions")
+			}
+
+			if p.IDs.Contains(uint32(repo.ID)) {
+				e.pendingBindIDs = append(e.pendingBindIDs, bindID)
+			}
+		}
+	}
+
+	return e, nil
+}
+
+// userBindIDs returns the bind IDs that identify the user according to the given
+// bind ID type of the permissions user mapping.
+func userBindIDs(ctx context.Context, user *types.User, bindIDType string) ([]string, error) {
+	switch bindIDType {
+	case "email":
+		// 🚨 SECURITY: It is critical to only use verified emails.
+		emails, err := db.UserEmails.ListByUser(ctx, db.UserEmailsListOptions{
+			UserID:       user.ID,
+			OnlyVerified: true,
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "list verified emails")
+		}
+
+		bindIDs := make([]string, 0, len(emails))
+		for i := range emails {
+			bindIDs = append(bindIDs, emails[i].Email)
+		}
+		return bindIDs, nil
+
+	case "username":
+		return []string{user.Username}, nil
+
+	default:
+		return nil, fmt.Errorf("unrecognized user mapping bind ID type %q", bindIDType)
+	}
+}
@@ -23,6 +23,8 @@ import (
 	"github.com/sourcegraph/sourcegraph/internal/actor"
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/extsvc"
+	"github.com/sourcegraph/sourcegraph/internal/repoupdater"
+	"github.com/sourcegraph/sourcegraph/internal/repoupdater/protocol"
 	"github.com/sourcegraph/sourcegraph/schema"
 )
 
@@ -612,3 +614,170 @@ func TestResolver_RepositoryPermissionsInfo(t *testing.T) {
 		})
 	}
 }
+
+func TestResolver_AuthorizationExplain(t *testing.T) {
+	t.Run("authenticated as non-admin", func(t *testing.T) {
+		db.Mocks.Users.GetByCurrentAuthUser = func(context.Context) (*types.User, error) {
+			return &types.User{}, nil
+		}
+		t.Cleanup(func() {
+			db.Mocks.Users.GetByCurrentAuthUser = nil
+		})
+
+		ctx := actor.WithActor(context.Background(), &actor.Actor{UID: 1})
+		result, err := (&Resolver{}).AuthorizationExplain(ctx, &graphqlbackend.AuthorizationExplainArgs{})
+		if want := backend.ErrMustBeSiteAdmin; err != want {
+			t.Errorf("err: want %q but got %v", want, err)
+		}
+		if result != nil {
+			t.Errorf("result: want nil but got %v", result)
+		}
+	})
+
+	authz.SetProviders(true, nil)
+	db.Mocks.Users.GetByCurrentAuthUser = func(context.Context) (*types.User, error) {
+		return &types.User{SiteAdmin: true}, nil
+	}
+	db.Mocks.Users.GetByID = func(_ context.Context, id int32) (*types.User, error) {
+		return &types.User{ID: id, Username: "alice"}, nil
+	}
+	db.Mocks.Repos.Get = func(_ context.Context, id api.RepoID) (*types.Repo, error) {
+		return &types.Repo{ID: id, Name: "github.com/owner/repo", Private: true}, nil
+	}
+	edb.Mocks.Perms.ListExternalAccounts = func(context.Context, int32) ([]*extsvc.Account, error) {
+		return nil, nil
+	}
+	edb.Mocks.Perms.LoadUserPermissions = func(_ context.Context, p *authz.UserPermissions) error {
+		p.IDs = roaring.BitmapOf(1)
+		p.UpdatedAt = clock()
+		p.SyncedAt = clock()
+		return nil
+	}
+	edb.Mocks.Perms.LoadRepoPermissions = func(_ context.Context, p *authz.RepoPermissions) error {
+		return authz.ErrPermsNotFound
+	}
+	defer func() {
+		db.Mocks.Users = db.MockUsers{}
+		db.Mocks.Repos = db.MockRepos{}
+		edb.Mocks.Perms = edb.MockPerms{}
+	}()
+
+	tests := []struct {
+		name     string
+		gqlTests []*gqltesting.Test
+	}{
+		{
+			name: "explain permissions without authz providers",
+			gqlTests: []*gqltesting.Test{
+				{
+					Schema: mustParseGraphQLSchema(t, nil),
+					Query: `
+				{
+					authorizationExplain(user: "VXNlcjox", repository: "UmVwb3NpdG9yeTox") {
+						user {
+							username
+						}
+						repository {
+							name
+						}
+						public
+						unrestricted
+						providers {
+							serviceType
+						}
+						userPermissions {
+							permissions
+							grantsAccess
+							syncedAt
+							updatedAt
+						}
+						repositoryPermissions {
+							grantsAccess
+						}
+						pendingBindIDs
+					}
+				}
+			`,
+					ExpectedResult: fmt.Sprintf(`
+				{
+					"authorizationExplain": {
+						"user": {
+							"username": "alice"
+						},
+						"repository": {
+							"name": "github.com/owner/repo"
+						},
+						"public": false,
+						"unrestricted": true,
+						"providers": [],
+						"userPermissions": {
+							"permissions": ["READ"],
+							"grantsAccess": true,
+							"syncedAt": "%[1]s",
+							"updatedAt": "%[1]s"
+						},
+						"repositoryPermissions": null,
+						"pendingBindIDs": []
+					}
+				}
+			`, clock().Format(time.RFC3339)),
+				},
+			},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			gqltesting.RunTests(t, test.gqlTests)
+		})
+	}
+}
+
+func TestResolver_ScheduleRepositoryPermissionsSync(t *testing.T) {
+	t.Run("authenticated as non-admin", func(t *testing.T) {
+		db.Mocks.Users.GetByCurrentAuthUser = func(context.Context) (*types.User, error) {
+			return &types.User{}, nil
+		}
+		t.Cleanup(func() {
+			db.Mocks.Users.GetByCurrentAuthUser = nil
+		})
+
+		ctx := actor.WithActor(context.Background(), &actor.Actor{UID: 1})
+		result, err := (&Resolver{}).ScheduleRepositoryPermissionsSync(ctx, &graphqlbackend.RepositoryPermissionsSyncArgs{})
+		if want := backend.ErrMustBeSiteAdmin; err != want {
+			t.Errorf("err: want %q but got %v", want, err)
+		}
+		if result != nil {
+			t.Errorf("result: want nil but got %v", result)
+		}
+	})
+
+	db.Mocks.Users.GetByCurrentAuthUser = func(context.Context) (*types.User, error) {
+		return &types.User{SiteAdmin: true}, nil
+	}
+	db.Mocks.Repos.Get = func(_ context.Context, id api.RepoID) (*types.Repo, error) {
+		return &types.Repo{ID: id}, nil
+	}
+	var calledWith protocol.PermsSyncRequest
+	repoupdater.MockSchedulePermsSync = func(_ context.Context, args protocol.PermsSyncRequest) error {
+		calledWith = args
+		return nil
+	}
+	defer func() {
+		db.Mocks.Users = db.MockUsers{}
+		db.Mocks.Repos = db.MockRepos{}
+		repoupdater.MockSchedulePermsSync = nil
+	}()
+
+	ctx := actor.WithActor(context.Background(), &actor.Actor{UID: 1})
+	_, err := (&Resolver{}).ScheduleRepositoryPermissionsSync(ctx, &graphqlbackend.RepositoryPermissionsSyncArgs{
+		Repository: graphqlbackend.MarshalRepositoryID(1),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantArgs := protocol.PermsSyncRequest{RepoIDs: []api.RepoID{1}}
+	if diff := cmp.Diff(wantArgs, calledWith); diff != "" {
+		t.Fatalf("calledWith: %v", diff)
+	}
+}
@@ -1375,13 +1375,10 @@ func TestCreateCampaignWithPatchSet(t *testing.T) {
 	}
 	defer func() { git.Mocks.GetCommit = nil }()
 
-	git.Mocks.ExecReader = func(args []string) (io.ReadCloser, error) {
-		if len(args) < 1 && args[0] != "diff" {
-			t.Fatalf("gitserver.ExecReader received wrong args: %v", args)
-		}
+	git.Mocks.ReadDiff = func(opt git.DiffOptions) (io.ReadCloser, error) {
 		return ioutil.NopCloser(strings.NewReader(testDiff)), nil
 	}
-	defer func() { git.Mocks.ExecReader = nil }()
+	defer func() { git.Mocks.ReadDiff = nil }()
 
 	var queryCampaignResponse struct{ Node apitest.Campaign }
 
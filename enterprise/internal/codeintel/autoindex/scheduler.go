@@ -0,0 +1,161 @@
+// Package autoindex schedules LSIF index jobs for repositories which are configured to be
+// indexed by Sourcegraph instead of uploading LSIF data from their CI.
+package autoindex
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/inconshreveable/log15"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/lsifserver/client"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+// DefaultInterval is the interval at which repositories are checked for commits to index
+// when the configuration does not set one.
+const DefaultInterval = time.Hour
+
+// Scheduler enqueues index jobs for the tip of the default branch of the repositories matching
+// the codeIntelAutoIndexing site configuration, for each configured indexer whose language is
+// used in the repository. The precise-code-intel-api-server skips commits which were already
+// indexed or uploaded with the same indexer.
+type Scheduler struct {
+	// ListRepos returns the repositories whose name matches the given pattern.
+	ListRepos func(ctx context.Context, pattern string) ([]*types.Repo, error)
+	// ResolveHead returns the tip commit of the default branch of the given repository.
+	ResolveHead func(ctx context.Context, repo *types.Repo) (api.CommitID, error)
+	// Languages returns the names of the languages used in the given repository at the given commit.
+	Languages func(ctx context.Context, repo *types.Repo, commit api.CommitID) ([]string, error)
+	// EnqueueIndex enqueues an index job and returns whether a job was enqueued.
+	EnqueueIndex func(ctx context.Context, repo *types.Repo, commit api.CommitID, indexerName string) (bool, error)
+}
+
+// NewScheduler returns a Scheduler backed by the database, gitserver, and the
+// precise-code-intel-api-server.
+func NewScheduler() *Scheduler {
+	return &Scheduler{
+		ListRepos: func(ctx context.Context, pattern string) ([]*types.Repo, error) {
+			return backend.Repos.List(ctx, db.ReposListOptions{IncludePatterns: []string{pattern}})
+		},
+		ResolveHead: func(ctx context.Context, repo *types.Repo) (api.CommitID, error) {
+			return backend.Repos.ResolveRev(ctx, repo, "")
+		},
+		Languages: func(ctx context.Context, repo *types.Repo, commit api.CommitID) ([]string, error) {
+			inv, err := backend.Repos.GetInventory(ctx, repo, commit, false)
+			if err != nil {
+				return nil, err
+			}
+			names := make([]string, 0, len(inv.Languages))
+			for _, lang := range inv.Languages {
+				names = append(names, lang.Name)
+			}
+			return names, nil
+		},
+		EnqueueIndex: func(ctx context.Context, repo *types.Repo, commit api.CommitID, indexerName string) (bool, error) {
+			_, enqueued, err := client.DefaultClient.EnqueueIndex(ctx, &struct {
+				RepoID      api.RepoID
+				Commit      api.CommitID
+				IndexerName string
+			}{
+				RepoID:      repo.ID,
+				Commit:      commit,
+				IndexerName: indexerName,
+			})
+			return enqueued, err
+		},
+	}
+}
+
+// Start schedules index jobs at the configured interval. It never returns.
+func (s *Scheduler) Start() {
+	for {
+		interval := DefaultInterval
+		if config := conf.Get().CodeIntelAutoIndexing; config != nil {
+			if config.IntervalMinutes > 0 {
+				interval = time.Duration(config.IntervalMinutes) * time.Minute
+			}
+			// The scheduler acts on behalf of the site, so it must see private repositories.
+			ctx := actor.WithActor(context.Background(), &actor.Actor{Internal: true})
+			if err := s.Schedule(ctx, config); err != nil {
+				log15.Error("Failed to schedule LSIF index jobs", "error", err)
+			}
+		}
+
+		time.Sleep(interval)
+	}
+}
+
+// Schedule enqueues the index jobs for the given configuration. Failures for individual
+// repositories are logged and do not prevent jobs for other repositories from being enqueued.
+func (s *Scheduler) Schedule(ctx context.Context, config *schema.CodeIntelAutoIndexing) error {
+	if len(config.Indexers) == 0 {
+		return nil
+	}
+
+	seen := map[api.RepoID]bool{}
+	for _, pattern := range config.Repositories {
+		repos, err := s.ListRepos(ctx, pattern)
+		if err != nil {
+			return err
+		}
+
+		for _, repo := range repos {
+			if seen[repo.ID] {
+				continue
+			}
+			seen[repo.ID] = true
+
+			if err := s.scheduleRepo(ctx, repo, config.Indexers); err != nil {
+				log15.Warn("Failed to schedule LSIF index jobs for repository", "repo", repo.Name, "error", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// scheduleRepo enqueues index jobs for the tip of the default branch of the given repository.
+func (s *Scheduler) scheduleRepo(ctx context.Context, repo *types.Repo, indexers []*schema.CodeIntelIndexer) error {
+	commit, err := s.ResolveHead(ctx, repo)
+	if err != nil {
+		return err
+	}
+
+	languages, err := s.Languages(ctx, repo, commit)
+	if err != nil {
+		return err
+	}
+
+	for _, indexer := range indexers {
+		if !containsFold(languages, indexer.Language) {
+			continue
+		}
+
+		enqueued, err := s.EnqueueIndex(ctx, repo, commit, indexer.Name)
+		if err != nil {
+			return err
+		}
+		if enqueued {
+			log15.Debug("Enqueued LSIF index job", "repo", repo.Name, "commit", commit, "indexer", indexer.Name)
+		}
+	}
+
+	return nil
+}
+
+// containsFold returns whether values contains value, ignoring case.
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
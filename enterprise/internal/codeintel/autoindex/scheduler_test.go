@@ -0,0 +1,69 @@
+package autoindex
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/schema"
+)
+
+func TestSchedule(t *testing.T) {
+	repos := map[string][]*types.Repo{
+		"^github\\.com/go/":    {{ID: 1, Name: "github.com/go/a"}, {ID: 2, Name: "github.com/go/b"}},
+		"^github\\.com/go/a$":  {{ID: 1, Name: "github.com/go/a"}},
+		"^github\\.com/mixed/": {{ID: 3, Name: "github.com/mixed/c"}, {ID: 4, Name: "github.com/mixed/broken"}},
+	}
+	languages := map[api.RepoID][]string{
+		1: {"Go"},
+		2: {"Go", "Shell"},
+		3: {"TypeScript", "Go"},
+	}
+
+	var enqueued []string
+	s := &Scheduler{
+		ListRepos: func(ctx context.Context, pattern string) ([]*types.Repo, error) {
+			return repos[pattern], nil
+		},
+		ResolveHead: func(ctx context.Context, repo *types.Repo) (api.CommitID, error) {
+			if repo.ID == 4 {
+				return "", errors.New("revision not found")
+			}
+			return api.CommitID("deadbeef"), nil
+		},
+		Languages: func(ctx context.Context, repo *types.Repo, commit api.CommitID) ([]string, error) {
+			return languages[repo.ID], nil
+		},
+		EnqueueIndex: func(ctx context.Context, repo *types.Repo, commit api.CommitID, indexerName string) (bool, error) {
+			enqueued = append(enqueued, string(repo.Name)+"@"+string(commit)+":"+indexerName)
+			return true, nil
+		},
+	}
+
+	config := &schema.CodeIntelAutoIndexing{
+		Repositories: []string{"^github\\.com/go/", "^github\\.com/go/a$", "^github\\.com/mixed/"},
+		Indexers: []*schema.CodeIntelIndexer{
+			{Language: "go", Name: "lsif-go", Command: []string{"lsif-go"}},
+			{Language: "TypeScript", Name: "lsif-tsc", Command: []string{"lsif-tsc", "-p", "."}},
+			{Language: "Java", Name: "lsif-java", Command: []string{"lsif-java"}},
+		},
+	}
+	if err := s.Schedule(context.Background(), config); err != nil {
+		t.Fatal(err)
+	}
+
+	sort.Strings(enqueued)
+	want := []string{
+		"github.com/go/a@deadbeef:lsif-go",
+		"github.com/go/b@deadbeef:lsif-go",
+		"github.com/mixed/c@deadbeef:lsif-go",
+		"github.com/mixed/c@deadbeef:lsif-tsc",
+	}
+	if diff := cmp.Diff(want, enqueued); diff != "" {
+		t.Errorf("unexpected index jobs (-want +got):\n%s", diff)
+	}
+}
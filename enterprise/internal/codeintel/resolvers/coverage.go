@@ -0,0 +1,441 @@
+package resolvers
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/inconshreveable/log15"
+	"github.com/pkg/errors"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/external/inventory"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/graphqlutil"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/conf"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
+)
+
+// lsifIndexerLanguages maps the names of well-known LSIF indexers to the languages they index.
+// The indexers configured for auto-indexing extend this list with their language.
+var lsifIndexerLanguages = map[string][]string{
+	"lsif-go":         {"Go"},
+	"lsif-tsc":        {"TypeScript", "JavaScript"},
+	"lsif-node":       {"TypeScript", "JavaScript"},
+	"lsif-java":       {"Java"},
+	"lsif-semanticdb": {"Java", "Scala"},
+	"lsif-clang":      {"C", "C++", "Objective-C"},
+	"lsif-cpp":        {"C", "C++"},
+	"lsif-py":         {"Python"},
+	"lsif-dart":       {"Dart"},
+	"lsif-hie":        {"Haskell"},
+	"rust-analyzer":   {"Rust"},
+}
+
+// lsifUploadPageSize is the number of uploads requested per page when listing the uploads
+// visible at the tip of a repository's default branch.
+const lsifUploadPageSize = 1000
+
+// LSIFCoverage reports where precise code intelligence is available at the tip of the repository's
+// default branch. The languages of the given directory and of each of its subdirectories are matched
+// with the LSIF uploads visible at the tip whose indexer handles the language. Uploads whose commit
+// can't be compared with the tip are left out. This method returns nil if the repository is empty.
+func (r *Resolver) LSIFCoverage(ctx context.Context, args *graphqlbackend.LSIFRepositoryCoverageQueryArgs) (graphqlbackend.LSIFCoverageResolver, error) {
+	commit, err := args.Repository.Commit(ctx, &graphqlbackend.RepositoryCommitArgs{Rev: "HEAD"})
+	if err != nil || commit == nil {
+		return nil, err
+	}
+
+	uploads, err := r.lsifUploadsAtTip(ctx, args.Repository.ID())
+	if err != nil {
+		return nil, err
+	}
+
+	repo := args.Repository.Type()
+	cachedRepo, err := backend.CachedGitRepo(ctx, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	tipCommit := string(commit.OID())
+	coverageUploads := make([]*lsifUploadCoverageResolver, 0, len(uploads))
+	for _, upload := range uploads {
+		behindAhead, err := git.GetBehindAhead(ctx, *cachedRepo, upload.InputCommit(), tipCommit)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, err
+			}
+			// The commit may no longer exist, for example after a force push.
+			log15.Warn("Skipping LSIF upload in coverage report.", "repo", repo.Name, "upload", upload.ID(), "commit", upload.InputCommit(), "error", err)
+			continue
+		}
+
+		coverageUploads = append(coverageUploads, &lsifUploadCoverageResolver{
+			upload:           upload,
+			commitsBehindTip: int32(behindAhead.Ahead),
+		})
+	}
+
+	directories, err := directoryInventories(ctx, *cachedRepo, api.CommitID(tipCommit), strings.Trim(args.Path, "/"))
+	if err != nil {
+		return nil, err
+	}
+
+	indexerLanguages := configuredIndexerLanguages()
+	directoryResolvers := make([]*lsifDirectoryCoverageResolver, 0, len(directories))
+	for _, directory := range directories {
+		directoryResolvers = append(directoryResolvers, newLSIFDirectoryCoverage(directory.path, directory.inventory, coverageUploads, indexerLanguages))
+	}
+
+	return &lsifCoverageResolver{
+		commit:      commit,
+		uploads:     coverageUploads,
+		directories: directoryResolvers,
+	}, nil
+}
+
+// LSIFRepositoryCoverage lists repositories by name along with their precise code intelligence
+// coverage, for the site admin coverage report.
+func (r *Resolver) LSIFRepositoryCoverage(ctx context.Context, args *graphqlbackend.LSIFRepositoryCoverageArgs) (graphqlbackend.LSIFRepositoryCoverageConnectionResolver, error) {
+	// 🚨 SECURITY: Only site admins may list the coverage of all repositories.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	opt := db.ReposListOptions{
+		OrderBy: db.RepoListOrderBy{{Field: db.RepoListName}},
+	}
+	if args.Query != nil {
+		opt.Query = *args.Query
+	}
+	args.ConnectionArgs.Set(&opt.LimitOffset)
+	if args.After != nil {
+		if opt.LimitOffset == nil {
+			return nil, errors.New("after requires first")
+		}
+		offset, err := strconv.Atoi(*args.After)
+		if err != nil || offset < 0 {
+			return nil, errors.Errorf("invalid cursor %q", *args.After)
+		}
+		opt.Offset = offset
+	}
+	return &lsifRepositoryCoverageConnectionResolver{resolver: r, opt: opt}, nil
+}
+
+type lsifRepositoryCoverageConnectionResolver struct {
+	resolver *Resolver
+	opt      db.ReposListOptions
+
+	// cache results because they are used by multiple fields
+	once  sync.Once
+	repos []*types.Repo
+	err   error
+}
+
+var _ graphqlbackend.LSIFRepositoryCoverageConnectionResolver = &lsifRepositoryCoverageConnectionResolver{}
+
+func (r *lsifRepositoryCoverageConnectionResolver) compute(ctx context.Context) ([]*types.Repo, error) {
+	r.once.Do(func() {
+		opt := r.opt
+		if opt.LimitOffset != nil {
+			// Ask for one more repository to know whether there is a next page
+			limitOffset := *opt.LimitOffset
+			limitOffset.Limit++
+			opt.LimitOffset = &limitOffset
+		}
+
+		r.repos, r.err = db.Repos.List(ctx, opt)
+	})
+	return r.repos, r.err
+}
+
+func (r *lsifRepositoryCoverageConnectionResolver) Nodes(ctx context.Context) ([]graphqlbackend.LSIFRepositoryCoverageResolver, error) {
+	repos, err := r.compute(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if r.opt.LimitOffset != nil && len(repos) > r.opt.Limit {
+		repos = repos[:r.opt.Limit]
+	}
+
+	resolvers := make([]graphqlbackend.LSIFRepositoryCoverageResolver, 0, len(repos))
+	for _, repo := range repos {
+		resolvers = append(resolvers, &lsifRepositoryCoverageResolver{
+			resolver:   r.resolver,
+			repository: graphqlbackend.NewRepositoryResolver(repo),
+		})
+	}
+	return resolvers, nil
+}
+
+func (r *lsifRepositoryCoverageConnectionResolver) TotalCount(ctx context.Context) (int32, error) {
+	opt := r.opt
+	opt.LimitOffset = nil
+	count, err := db.Repos.Count(ctx, opt)
+	return int32(count), err
+}
+
+func (r *lsifRepositoryCoverageConnectionResolver) PageInfo(ctx context.Context) (*graphqlutil.PageInfo, error) {
+	repos, err := r.compute(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if r.opt.LimitOffset != nil && len(repos) > r.opt.Limit {
+		// The cursor is the offset of the next page.
+		return graphqlutil.NextPageCursor(strconv.Itoa(r.opt.Offset + r.opt.Limit)), nil
+	}
+	return graphqlutil.HasNextPage(false), nil
+}
+
+type lsifRepositoryCoverageResolver struct {
+	resolver   *Resolver
+	repository *graphqlbackend.RepositoryResolver
+}
+
+var _ graphqlbackend.LSIFRepositoryCoverageResolver = &lsifRepositoryCoverageResolver{}
+
+func (r *lsifRepositoryCoverageResolver) Repository() *graphqlbackend.RepositoryResolver {
+	return r.repository
+}
+
+func (r *lsifRepositoryCoverageResolver) Coverage(ctx context.Context, args *graphqlbackend.LSIFCoverageArgs) (graphqlbackend.LSIFCoverageResolver, error) {
+	return r.resolver.LSIFCoverage(ctx, &graphqlbackend.LSIFRepositoryCoverageQueryArgs{
+		LSIFCoverageArgs: args,
+		Repository:       r.repository,
+	})
+}
+
+// lsifUploadsAtTip returns all completed uploads of the repository that are visible at the tip
+// of its default branch.
+func (r *Resolver) lsifUploadsAtTip(ctx context.Context, repositoryID graphql.ID) ([]graphqlbackend.LSIFUploadResolver, error) {
+	var (
+		state           = "COMPLETED"
+		isLatestForRepo = true
+		first           = int32(lsifUploadPageSize)
+		after           *string
+		uploads         []graphqlbackend.LSIFUploadResolver
+	)
+
+	for {
+		connection, err := r.LSIFUploads(ctx, &graphqlbackend.LSIFRepositoryUploadsQueryArgs{
+			LSIFUploadsQueryArgs: &graphqlbackend.LSIFUploadsQueryArgs{
+				ConnectionArgs:  graphqlutil.ConnectionArgs{First: &first},
+				State:           &state,
+				IsLatestForRepo: &isLatestForRepo,
+				After:           after,
+			},
+			RepositoryID: repositoryID,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		nodes, err := connection.Nodes(ctx)
+		if err != nil {
+			return nil, err
+		}
+		uploads = append(uploads, nodes...)
+
+		pageInfo, err := connection.PageInfo(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if !pageInfo.HasNextPage() {
+			return uploads, nil
+		}
+		after = pageInfo.EndCursor()
+	}
+}
+
+type directoryInventory struct {
+	path      string
+	inventory inventory.Inventory
+}
+
+// directoryInventories returns the inventory of the given directory followed by the inventory
+// of each of its subdirectories.
+func directoryInventories(ctx context.Context, repo gitserver.Repo, commitID api.CommitID, dir string) ([]directoryInventory, error) {
+	invCtx, err := backend.InventoryContext(repo, commitID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	root, err := git.Stat(ctx, repo, commitID, dir)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := git.ReadDir(ctx, repo, commitID, dir, false)
+	if err != nil {
+		return nil, err
+	}
+
+	var directories []directoryInventory
+	for _, entry := range append([]os.FileInfo{root}, entries...) {
+		if !entry.IsDir() {
+			continue
+		}
+
+		inv, err := invCtx.Entries(ctx, entry)
+		if err != nil {
+			return nil, err
+		}
+
+		directoryPath := dir
+		if entry != root {
+			directoryPath = entry.Name()
+		}
+		directories = append(directories, directoryInventory{path: directoryPath, inventory: inv})
+	}
+
+	return directories, nil
+}
+
+// configuredIndexerLanguages returns the languages handled by each known indexer, including
+// the indexers configured for auto-indexing.
+func configuredIndexerLanguages() map[string][]string {
+	indexerLanguages := make(map[string][]string, len(lsifIndexerLanguages))
+	for name, languages := range lsifIndexerLanguages {
+		indexerLanguages[name] = languages
+	}
+
+	if c := conf.Get().CodeIntelAutoIndexing; c != nil {
+		for _, indexer := range c.Indexers {
+			if !containsString(indexerLanguages[indexer.Name], indexer.Language) {
+				indexerLanguages[indexer.Name] = append(append([]string(nil), indexerLanguages[indexer.Name]...), indexer.Language)
+			}
+		}
+	}
+
+	return indexerLanguages
+}
+
+// newLSIFDirectoryCoverage matches each language of the given directory with the uploads whose
+// indexer handles the language. An upload covers all of the directory if its root encloses the
+// directory, and part of it if its root lies within the directory.
+func newLSIFDirectoryCoverage(dir string, inv inventory.Inventory, uploads []*lsifUploadCoverageResolver, indexerLanguages map[string][]string) *lsifDirectoryCoverageResolver {
+	dirPrefix := ""
+	if dir != "" {
+		dirPrefix = dir + "/"
+	}
+
+	languages := make([]*lsifLanguageCoverageResolver, 0, len(inv.Languages))
+	for _, lang := range inv.Languages {
+		language := &lsifLanguageCoverageResolver{lang: lang}
+
+		for _, upload := range uploads {
+			if !containsString(indexerLanguages[upload.upload.InputIndexer()], lang.Name) {
+				continue
+			}
+
+			root := upload.upload.InputRoot()
+			if root != "" && !strings.HasSuffix(root, "/") {
+				root += "/"
+			}
+
+			if strings.HasPrefix(dirPrefix, root) {
+				language.uploads = append(language.uploads, upload)
+			} else if strings.HasPrefix(root, dirPrefix) {
+				language.partialUploads = append(language.partialUploads, upload)
+			}
+		}
+
+		languages = append(languages, language)
+	}
+
+	return &lsifDirectoryCoverageResolver{path: dir, languages: languages}
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+type lsifCoverageResolver struct {
+	commit      *graphqlbackend.GitCommitResolver
+	uploads     []*lsifUploadCoverageResolver
+	directories []*lsifDirectoryCoverageResolver
+}
+
+var _ graphqlbackend.LSIFCoverageResolver = &lsifCoverageResolver{}
+
+func (r *lsifCoverageResolver) Commit() *graphqlbackend.GitCommitResolver { return r.commit }
+
+func (r *lsifCoverageResolver) Uploads() []graphqlbackend.LSIFUploadCoverageResolver {
+	return toUploadCoverageResolvers(r.uploads)
+}
+
+func (r *lsifCoverageResolver) Directories() []graphqlbackend.LSIFDirectoryCoverageResolver {
+	resolvers := make([]graphqlbackend.LSIFDirectoryCoverageResolver, 0, len(r.directories))
+	for _, directory := range r.directories {
+		resolvers = append(resolvers, directory)
+	}
+	return resolvers
+}
+
+type lsifUploadCoverageResolver struct {
+	upload           graphqlbackend.LSIFUploadResolver
+	commitsBehindTip int32
+}
+
+var _ graphqlbackend.LSIFUploadCoverageResolver = &lsifUploadCoverageResolver{}
+
+func (r *lsifUploadCoverageResolver) Upload() graphqlbackend.LSIFUploadResolver { return r.upload }
+
+func (r *lsifUploadCoverageResolver) CommitsBehindTip() int32 { return r.commitsBehindTip }
+
+func toUploadCoverageResolvers(uploads []*lsifUploadCoverageResolver) []graphqlbackend.LSIFUploadCoverageResolver {
+	resolvers := make([]graphqlbackend.LSIFUploadCoverageResolver, 0, len(uploads))
+	for _, upload := range uploads {
+		resolvers = append(resolvers, upload)
+	}
+	return resolvers
+}
+
+type lsifDirectoryCoverageResolver struct {
+	path      string
+	languages []*lsifLanguageCoverageResolver
+}
+
+var _ graphqlbackend.LSIFDirectoryCoverageResolver = &lsifDirectoryCoverageResolver{}
+
+func (r *lsifDirectoryCoverageResolver) Path() string { return r.path }
+
+func (r *lsifDirectoryCoverageResolver) Languages() []graphqlbackend.LSIFLanguageCoverageResolver {
+	resolvers := make([]graphqlbackend.LSIFLanguageCoverageResolver, 0, len(r.languages))
+	for _, language := range r.languages {
+		resolvers = append(resolvers, language)
+	}
+	return resolvers
+}
+
+type lsifLanguageCoverageResolver struct {
+	lang           inventory.Lang
+	uploads        []*lsifUploadCoverageResolver
+	partialUploads []*lsifUploadCoverageResolver
+}
+
+var _ graphqlbackend.LSIFLanguageCoverageResolver = &lsifLanguageCoverageResolver{}
+
+func (r *lsifLanguageCoverageResolver) Name() string { return r.lang.Name }
+
+func (r *lsifLanguageCoverageResolver) TotalBytes() float64 { return float64(r.lang.TotalBytes) }
+
+func (r *lsifLanguageCoverageResolver) TotalLines() int32 { return int32(r.lang.TotalLines) }
+
+func (r *lsifLanguageCoverageResolver) Uploads() []graphqlbackend.LSIFUploadCoverageResolver {
+	return toUploadCoverageResolvers(r.uploads)
+}
+
+func (r *lsifLanguageCoverageResolver) PartialUploads() []graphqlbackend.LSIFUploadCoverageResolver {
+	return toUploadCoverageResolvers(r.partialUploads)
+}
@@ -0,0 +1,176 @@
+package resolvers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/db"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/external/inventory"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/graphqlutil"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/actor"
+)
+
+type fakeLSIFUpload struct {
+	graphqlbackend.LSIFUploadResolver
+	root    string
+	indexer string
+}
+
+func (u *fakeLSIFUpload) InputRoot() string    { return u.root }
+func (u *fakeLSIFUpload) InputIndexer() string { return u.indexer }
+
+func TestNewLSIFDirectoryCoverage(t *testing.T) {
+	uploads := []*lsifUploadCoverageResolver{
+		{upload: &fakeLSIFUpload{root: "", indexer: "lsif-go"}},
+		{upload: &fakeLSIFUpload{root: "cmd/", indexer: "lsif-go"}},
+		{upload: &fakeLSIFUpload{root: "cmd/frontend/web/", indexer: "lsif-tsc"}},
+		{upload: &fakeLSIFUpload{root: "cmdline/", indexer: "lsif-go"}},
+		{upload: &fakeLSIFUpload{root: "cmd", indexer: "lsif-custom"}},
+	}
+	indexerLanguages := map[string][]string{
+		"lsif-go":     {"Go"},
+		"lsif-tsc":    {"TypeScript", "JavaScript"},
+		"lsif-custom": {"Python"},
+	}
+	inv := inventory.Inventory{
+		Languages: []inventory.Lang{
+			{Name: "Go", TotalBytes: 100, TotalLines: 10},
+			{Name: "TypeScript", TotalBytes: 50, TotalLines: 5},
+			{Name: "Python", TotalBytes: 20, TotalLines: 2},
+			{Name: "Shell", TotalBytes: 10, TotalLines: 1},
+		},
+	}
+
+	coverage := newLSIFDirectoryCoverage("cmd", inv, uploads, indexerLanguages)
+	if coverage.Path() != "cmd" {
+		t.Errorf("unexpected path. want=%q have=%q", "cmd", coverage.Path())
+	}
+
+	type languageCoverage struct {
+		Name           string
+		Uploads        []string
+		PartialUploads []string
+	}
+	roots := func(uploads []graphqlbackend.LSIFUploadCoverageResolver) []string {
+		var roots []string
+		for _, upload := range uploads {
+			roots = append(roots, upload.Upload().InputRoot())
+		}
+		return roots
+	}
+
+	var languages []languageCoverage
+	for _, language := range coverage.Languages() {
+		languages = append(languages, languageCoverage{
+			Name:           language.Name(),
+			Uploads:        roots(language.Uploads()),
+			PartialUploads: roots(language.PartialUploads()),
+		})
+	}
+
+	expected := []languageCoverage{
+		{Name: "Go", Uploads: []string{"", "cmd/"}},
+		{Name: "TypeScript", PartialUploads: []string{"cmd/frontend/web/"}},
+		{Name: "Python", Uploads: []string{"cmd"}},
+		{Name: "Shell"},
+	}
+	if diff := cmp.Diff(expected, languages); diff != "" {
+		t.Errorf("unexpected coverage (-want +got):\n%s", diff)
+	}
+}
+
+func TestLSIFRepositoryCoverage(t *testing.T) {
+	db.Mocks.Repos.List = func(ctx context.Context, opt db.ReposListOptions) ([]*types.Repo, error) {
+		repos := []*types.Repo{{Name: "repo1"}, {Name: "repo2"}, {Name: "repo3"}}
+		if opt.LimitOffset == nil {
+			return repos, nil
+		}
+		if opt.Offset > len(repos) {
+			return nil, nil
+		}
+		repos = repos[opt.Offset:]
+		if opt.Limit < len(repos) {
+			repos = repos[:opt.Limit]
+		}
+		return repos, nil
+	}
+	db.Mocks.Repos.Count = func(context.Context, db.ReposListOptions) (int, error) { return 3, nil }
+	db.Mocks.Users.GetByCurrentAuthUser = func(ctx context.Context) (*types.User, error) {
+		return &types.User{ID: 1, SiteAdmin: true}, nil
+	}
+	defer func() { db.Mocks = db.MockStores{} }()
+
+	ctx := actor.WithActor(context.Background(), &actor.Actor{UID: 1})
+	first := int32(2)
+	after := "2"
+
+	testCases := []struct {
+		name            string
+		after           *string
+		expectedNames   []string
+		expectedCursor  *string
+		expectedHasNext bool
+	}{
+		{name: "first page", expectedNames: []string{"repo1", "repo2"}, expectedCursor: &after, expectedHasNext: true},
+		{name: "last page", after: &after, expectedNames: []string{"repo3"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			connection, err := (&Resolver{}).LSIFRepositoryCoverage(ctx, &graphqlbackend.LSIFRepositoryCoverageArgs{
+				ConnectionArgs: graphqlutil.ConnectionArgs{First: &first},
+				After:          tc.after,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			nodes, err := connection.Nodes(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var names []string
+			for _, node := range nodes {
+				names = append(names, node.Repository().Name())
+			}
+			if diff := cmp.Diff(tc.expectedNames, names); diff != "" {
+				t.Errorf("unexpected repositories (-want +got):\n%s", diff)
+			}
+
+			totalCount, err := connection.TotalCount(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if totalCount != 3 {
+				t.Errorf("unexpected total count. want=%d have=%d", 3, totalCount)
+			}
+
+			pageInfo, err := connection.PageInfo(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if pageInfo.HasNextPage() != tc.expectedHasNext {
+				t.Errorf("unexpected hasNextPage. want=%v have=%v", tc.expectedHasNext, pageInfo.HasNextPage())
+			}
+			if diff := cmp.Diff(tc.expectedCursor, pageInfo.EndCursor()); diff != "" {
+				t.Errorf("unexpected end cursor (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestLSIFRepositoryCoverageNotSiteAdmin(t *testing.T) {
+	db.Mocks.Users.GetByCurrentAuthUser = func(ctx context.Context) (*types.User, error) {
+		return &types.User{ID: 1, SiteAdmin: false}, nil
+	}
+	defer func() { db.Mocks = db.MockStores{} }()
+
+	ctx := actor.WithActor(context.Background(), &actor.Actor{UID: 1})
+	if _, err := (&Resolver{}).LSIFRepositoryCoverage(ctx, &graphqlbackend.LSIFRepositoryCoverageArgs{}); err != backend.ErrMustBeSiteAdmin {
+		t.Errorf("unexpected error. want=%q have=%q", backend.ErrMustBeSiteAdmin, err)
+	}
+}
@@ -0,0 +1,175 @@
+package resolvers
+
+import (
+	"context"
+	"encoding/base64"
+	"strings"
+	"sync"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/relay"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend/graphqlutil"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/lsifserver/client"
+	"github.com/sourcegraph/sourcegraph/internal/lsif"
+)
+
+type lsifIndexResolver struct {
+	lsifIndex *lsif.LSIFIndex
+}
+
+var _ graphqlbackend.LSIFIndexResolver = &lsifIndexResolver{}
+
+func (r *lsifIndexResolver) ID() graphql.ID {
+	return marshalLSIFIndexGQLID(r.lsifIndex.ID)
+}
+
+func (r *lsifIndexResolver) InputCommit() string {
+	return r.lsifIndex.Commit
+}
+
+func (r *lsifIndexResolver) Indexer() string {
+	return r.lsifIndex.Indexer
+}
+
+func (r *lsifIndexResolver) State() string {
+	return strings.ToUpper(r.lsifIndex.State)
+}
+
+func (r *lsifIndexResolver) QueuedAt() graphqlbackend.DateTime {
+	return graphqlbackend.DateTime{Time: r.lsifIndex.QueuedAt}
+}
+
+func (r *lsifIndexResolver) StartedAt() *graphqlbackend.DateTime {
+	return graphqlbackend.DateTimeOrNil(r.lsifIndex.StartedAt)
+}
+
+func (r *lsifIndexResolver) FinishedAt() *graphqlbackend.DateTime {
+	return graphqlbackend.DateTimeOrNil(r.lsifIndex.FinishedAt)
+}
+
+func (r *lsifIndexResolver) Failure() *string {
+	return r.lsifIndex.FailureSummary
+}
+
+func (r *lsifIndexResolver) Log(ctx context.Context) (*string, error) {
+	// 🚨 SECURITY: Only site admins may view the output of indexers, as it may contain
+	// details of the environment the indexers run in.
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	return r.lsifIndex.Log, nil
+}
+
+func (r *lsifIndexResolver) Upload(ctx context.Context) (graphqlbackend.LSIFUploadResolver, error) {
+	if r.lsifIndex.UploadID == nil {
+		return nil, nil
+	}
+
+	lsifUpload, err := client.DefaultClient.GetUpload(ctx, &struct {
+		UploadID int64
+	}{
+		UploadID: *r.lsifIndex.UploadID,
+	})
+	if err != nil {
+		// The upload may have been deleted since
+		if client.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &lsifUploadResolver{lsifUpload: lsifUpload}, nil
+}
+
+type LSIFIndexesListOptions struct {
+	RepositoryID graphql.ID
+	State        *string
+	Limit        *int32
+	NextURL      *string
+}
+
+type lsifIndexConnectionResolver struct {
+	opt LSIFIndexesListOptions
+
+	// cache results because they are used by multiple fields
+	once       sync.Once
+	indexes    []*lsif.LSIFIndex
+	totalCount *int
+	nextURL    string
+	err        error
+}
+
+var _ graphqlbackend.LSIFIndexConnectionResolver = &lsifIndexConnectionResolver{}
+
+func (r *lsifIndexConnectionResolver) Nodes(ctx context.Context) ([]graphqlbackend.LSIFIndexResolver, error) {
+	indexes, _, _, err := r.compute(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var l []graphqlbackend.LSIFIndexResolver
+	for _, lsifIndex := range indexes {
+		l = append(l, &lsifIndexResolver{lsifIndex: lsifIndex})
+	}
+	return l, nil
+}
+
+func (r *lsifIndexConnectionResolver) TotalCount(ctx context.Context) (*int32, error) {
+	_, count, _, err := r.compute(ctx)
+	if count == nil || err != nil {
+		return nil, err
+	}
+
+	c := int32(*count)
+	return &c, nil
+}
+
+func (r *lsifIndexConnectionResolver) PageInfo(ctx context.Context) (*graphqlutil.PageInfo, error) {
+	_, _, nextURL, err := r.compute(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if nextURL != "" {
+		return graphqlutil.NextPageCursor(base64.StdEncoding.EncodeToString([]byte(nextURL))), nil
+	}
+
+	return graphqlutil.HasNextPage(false), nil
+}
+
+func (r *lsifIndexConnectionResolver) compute(ctx context.Context) ([]*lsif.LSIFIndex, *int, string, error) {
+	r.once.Do(func() {
+		repositoryResolver, err := graphqlbackend.RepositoryByID(ctx, r.opt.RepositoryID)
+		if err != nil {
+			r.err = err
+			return
+		}
+
+		r.indexes, r.nextURL, r.totalCount, r.err = client.DefaultClient.GetIndexes(ctx, &struct {
+			RepoID api.RepoID
+			State  *string
+			Limit  *int32
+			Cursor *string
+		}{
+			RepoID: repositoryResolver.Type().ID,
+			State:  r.opt.State,
+			Limit:  r.opt.Limit,
+			Cursor: r.opt.NextURL,
+		})
+	})
+
+	return r.indexes, r.totalCount, r.nextURL, r.err
+}
+
+func marshalLSIFIndexGQLID(lsifIndexID int64) graphql.ID {
+	return relay.MarshalID("LSIFIndex", lsifIndexID)
+}
+
+func unmarshalLSIFIndexGQLID(id graphql.ID) (lsifIndexID int64, err error) {
+	err = relay.UnmarshalSpec(id, &lsifIndexID)
+	return
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/sourcegraph/go-lsp"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/lsif"
 	"github.com/sourcegraph/sourcegraph/internal/vcs/git"
 )
 
@@ -41,7 +42,11 @@ func readDiff(ctx context.Context, repo *types.Repo, sourceCommit, targetCommit,
 		return nil, err
 	}
 
-	reader, err := git.ExecReader(ctx, *cachedRepo, []string{"diff", sourceCommit, targetCommit, "--", path})
+	reader, err := git.ReadDiff(ctx, *cachedRepo, git.DiffOptions{
+		Base:  sourceCommit,
+		Head:  targetCommit,
+		Paths: []string{path},
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -80,6 +85,27 @@ func (pa *positionAdjuster) adjustRange(r lsp.Range) (lsp.Range, bool) {
 	return lsp.Range{Start: start, End: end}, true
 }
 
+// adjustSymbols transforms the ranges of the given symbols in the source commit to ranges in the
+// target commit. A symbol whose range cannot be adjusted is dropped and its children take its place.
+func (pa *positionAdjuster) adjustSymbols(symbols []lsif.LSIFSymbol) []lsif.LSIFSymbol {
+	var adjusted []lsif.LSIFSymbol
+	for _, symbol := range symbols {
+		children := pa.adjustSymbols(symbol.Children)
+
+		r, ok := pa.adjustRange(symbol.Range)
+		if !ok {
+			adjusted = append(adjusted, children...)
+			continue
+		}
+
+		symbol.Range = r
+		symbol.Children = children
+		adjusted = append(adjusted, symbol)
+	}
+
+	return adjusted
+}
+
 // adjustPosition transforms the given position in the source commit to a position in the target
 // commit. This method returns second boolean value indicating that the adjustment succeeded. If
 // that particular line does not exist or has been edited in between the source and target commit,
@@ -3,7 +3,9 @@ package resolvers
 import (
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/sourcegraph/go-lsp"
+	"github.com/sourcegraph/sourcegraph/internal/lsif"
 )
 
 // hugoDiff is a diff from github.com/gohugoio/hugo generated via the following command.
@@ -155,3 +157,47 @@ func TestAdjustPositionEmptyDiff(t *testing.T) {
 		t.Errorf("Unexpected result: got %d:%d expected %d:%d", adjusted.Line, adjusted.Character, 25, 10)
 	}
 }
+
+func TestAdjustSymbols(t *testing.T) {
+	adjuster, err := newPositionAdjusterFromDiffOutput([]byte(prometheusDiff))
+	if err != nil {
+		t.Fatalf("unexpected error reading diff: %s", err)
+	}
+
+	symbols := []lsif.LSIFSymbol{
+		{
+			Name:  "Manager",
+			Range: lsp.Range{Start: lsp.Position{Line: 10, Character: 5}, End: lsp.Position{Line: 10, Character: 12}},
+			Children: []lsif.LSIFSymbol{
+				{
+					// Removed in the target commit
+					Name:  "ok",
+					Range: lsp.Range{Start: lsp.Position{Line: 295, Character: 8}, End: lsp.Position{Line: 295, Character: 10}},
+					Children: []lsif.LSIFSymbol{
+						{
+							Name:  "tg",
+							Range: lsp.Range{Start: lsp.Position{Line: 300, Character: 3}, End: lsp.Position{Line: 300, Character: 5}},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	expected := []lsif.LSIFSymbol{
+		{
+			Name:  "Manager",
+			Range: lsp.Range{Start: lsp.Position{Line: 10, Character: 5}, End: lsp.Position{Line: 10, Character: 12}},
+			Children: []lsif.LSIFSymbol{
+				{
+					Name:  "tg",
+					Range: lsp.Range{Start: lsp.Position{Line: 300, Character: 3}, End: lsp.Position{Line: 300, Character: 5}},
+				},
+			},
+		},
+	}
+
+	if diff := cmp.Diff(expected, adjuster.adjustSymbols(symbols)); diff != "" {
+		t.Errorf("unexpected symbols (-want +got):\n%s", diff)
+	}
+}
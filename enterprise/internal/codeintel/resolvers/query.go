@@ -23,6 +23,8 @@ type lsifQueryResolver struct {
 
 var _ graphqlbackend.LSIFQueryResolver = &lsifQueryResolver{}
 
+func (r *lsifQueryResolver) Precise() bool { return true }
+
 func (r *lsifQueryResolver) Definitions(ctx context.Context, args *graphqlbackend.LSIFQueryPositionArgs) (graphqlbackend.LocationConnectionResolver, error) {
 	for _, upload := range r.uploads {
 		// TODO(efritz) - we should also detect renames/copies on position adjustment
@@ -195,6 +197,110 @@ func (r *lsifQueryResolver) Hover(ctx context.Context, args *graphqlbackend.LSIF
 	return nil, nil
 }
 
+func (r *lsifQueryResolver) Ranges(ctx context.Context, args *graphqlbackend.LSIFRangesArgs) ([]graphqlbackend.CodeIntelligenceRangeResolver, error) {
+	for _, upload := range r.uploads {
+		// A nil slice of positions requests every range of the document
+		var positions []lsp.Position
+		if args.Positions != nil {
+			adjuster, err := newPositionAdjuster(ctx, r.repositoryResolver.Type(), string(r.commit), upload.Commit, r.path)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, position := range *args.Positions {
+				if adjusted, ok := adjuster.adjustPosition(lsp.Position{Line: int(position.Line), Character: int(position.Character)}); ok {
+					positions = append(positions, adjusted)
+				}
+			}
+			if len(positions) == 0 {
+				continue
+			}
+		}
+
+		ranges, err := client.DefaultClient.Ranges(ctx, &struct {
+			RepoID    api.RepoID
+			Commit    api.CommitID
+			Path      string
+			Positions []lsp.Position
+			UploadID  int64
+		}{
+			RepoID:    r.repositoryResolver.Type().ID,
+			Commit:    r.commit,
+			Path:      r.path,
+			Positions: positions,
+			UploadID:  upload.ID,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if len(ranges) == 0 {
+			continue
+		}
+
+		adjuster, err := newPositionAdjuster(ctx, r.repositoryResolver.Type(), upload.Commit, string(r.commit), r.path)
+		if err != nil {
+			return nil, err
+		}
+
+		var resolvers []graphqlbackend.CodeIntelligenceRangeResolver
+		for _, codeIntelligenceRange := range ranges {
+			adjustedRange, ok := adjuster.adjustRange(codeIntelligenceRange.Range)
+			if !ok {
+				// Skip ranges on lines edited since the upload's commit, as in Hover
+				continue
+			}
+
+			resolvers = append(resolvers, &codeIntelligenceRangeResolver{
+				repo:                  r.repositoryResolver.Type(),
+				commit:                r.commit,
+				lspRange:              adjustedRange,
+				codeIntelligenceRange: codeIntelligenceRange,
+			})
+		}
+
+		if len(resolvers) > 0 {
+			return resolvers, nil
+		}
+	}
+
+	return []graphqlbackend.CodeIntelligenceRangeResolver{}, nil
+}
+
+func (r *lsifQueryResolver) DocumentSymbols(ctx context.Context) ([]lsif.LSIFSymbol, error) {
+	for _, upload := range r.uploads {
+		symbols, err := client.DefaultClient.DocumentSymbols(ctx, &struct {
+			RepoID   api.RepoID
+			Commit   api.CommitID
+			Path     string
+			UploadID int64
+		}{
+			RepoID:   r.repositoryResolver.Type().ID,
+			Commit:   r.commit,
+			Path:     r.path,
+			UploadID: upload.ID,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if len(symbols) == 0 {
+			continue
+		}
+
+		adjuster, err := newPositionAdjuster(ctx, r.repositoryResolver.Type(), upload.Commit, string(r.commit), r.path)
+		if err != nil {
+			return nil, err
+		}
+
+		// Symbols on lines edited since the upload's commit are dropped, as we have low
+		// confidence that their ranges still point at the symbol's name.
+		if adjusted := adjuster.adjustSymbols(symbols); len(adjusted) > 0 {
+			return adjusted, nil
+		}
+	}
+
+	return nil, nil
+}
+
 // adjustPosition adjusts the position denoted by `line` and `character` in the requested commit into an
 // LSP position in the upload commit. This method returns nil if no equivalent position is found.
 func (r *lsifQueryResolver) adjustPosition(ctx context.Context, uploadCommit string, line, character int32) (lsp.Position, bool, error) {
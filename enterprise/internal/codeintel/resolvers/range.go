@@ -0,0 +1,43 @@
+package resolvers
+
+import (
+	"github.com/sourcegraph/go-lsp"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/types"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/lsif"
+)
+
+type codeIntelligenceRangeResolver struct {
+	repo   *types.Repo
+	commit api.CommitID
+	// lspRange is the range adjusted to the requested commit
+	lspRange              lsp.Range
+	codeIntelligenceRange *lsif.LSIFCodeIntelligenceRange
+}
+
+var _ graphqlbackend.CodeIntelligenceRangeResolver = &codeIntelligenceRangeResolver{}
+
+func (r *codeIntelligenceRangeResolver) Range() graphqlbackend.RangeResolver {
+	return graphqlbackend.NewRangeResolver(r.lspRange)
+}
+
+func (r *codeIntelligenceRangeResolver) Hover() graphqlbackend.HoverResolver {
+	if r.codeIntelligenceRange.HoverText == "" {
+		return nil
+	}
+
+	return &hoverResolver{text: r.codeIntelligenceRange.HoverText, lspRange: r.lspRange}
+}
+
+func (r *codeIntelligenceRangeResolver) Definitions() graphqlbackend.LocationConnectionResolver {
+	return &locationConnectionResolver{
+		repo:      r.repo,
+		commit:    r.commit,
+		locations: r.codeIntelligenceRange.Definitions,
+	}
+}
+
+func (r *codeIntelligenceRangeResolver) ReferenceCount() int32 {
+	return r.codeIntelligenceRange.ReferenceCount
+}
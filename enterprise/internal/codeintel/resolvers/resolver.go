@@ -9,6 +9,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/codeintel/lsifserver/client"
+	"github.com/sourcegraph/sourcegraph/internal/lsif"
 )
 
 type Resolver struct{}
@@ -90,6 +91,51 @@ func (r *Resolver) LSIFUploads(ctx context.Context, args *graphqlbackend.LSIFRep
 	return &lsifUploadConnectionResolver{opt: opt}, nil
 }
 
+func (r *Resolver) LSIFIndexByID(ctx context.Context, id graphql.ID) (graphqlbackend.LSIFIndexResolver, error) {
+	indexID, err := unmarshalLSIFIndexGQLID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	lsifIndex, err := client.DefaultClient.GetIndex(ctx, &struct {
+		IndexID int64
+	}{
+		IndexID: indexID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// 🚨 SECURITY: Index jobs are only visible to users who can access their repository
+	if _, err := backend.Repos.Get(ctx, lsifIndex.RepositoryID); err != nil {
+		return nil, err
+	}
+
+	return &lsifIndexResolver{lsifIndex: lsifIndex}, nil
+}
+
+// LSIFIndexes resolves the LSIF index jobs of a repository in a given state. This
+// method implements cursor-based forward pagination like LSIFUploads.
+func (r *Resolver) LSIFIndexes(ctx context.Context, args *graphqlbackend.LSIFRepositoryIndexesQueryArgs) (graphqlbackend.LSIFIndexConnectionResolver, error) {
+	opt := LSIFIndexesListOptions{
+		RepositoryID: args.RepositoryID,
+		State:        args.State,
+	}
+	if args.First != nil {
+		opt.Limit = args.First
+	}
+	if args.After != nil {
+		decoded, err := base64.StdEncoding.DecodeString(*args.After)
+		if err != nil {
+			return nil, err
+		}
+		nextURL := string(decoded)
+		opt.NextURL = &nextURL
+	}
+
+	return &lsifIndexConnectionResolver{opt: opt}, nil
+}
+
 func (r *Resolver) LSIF(ctx context.Context, args *graphqlbackend.LSIFQueryArgs) (graphqlbackend.LSIFQueryResolver, error) {
 	uploads, err := client.DefaultClient.Exists(ctx, &struct {
 		RepoID api.RepoID
@@ -116,3 +162,21 @@ func (r *Resolver) LSIF(ctx context.Context, args *graphqlbackend.LSIFQueryArgs)
 		uploads:            uploads,
 	}, nil
 }
+
+func (r *Resolver) LSIFSymbols(ctx context.Context, args *graphqlbackend.LSIFSymbolsArgs) ([]lsif.LSIFSymbolLocation, error) {
+	return client.DefaultClient.SearchSymbols(ctx, &struct {
+		RepoID          api.RepoID
+		Commit          api.CommitID
+		Query           string
+		IncludePatterns []string
+		ExcludePattern  string
+		Limit           int
+	}{
+		RepoID:          args.RepoID,
+		Commit:          args.Commit,
+		Query:           args.Query,
+		IncludePatterns: args.IncludePatterns,
+		ExcludePattern:  args.ExcludePattern,
+		Limit:           args.Limit,
+	})
+}
@@ -0,0 +1,60 @@
+package resolvers
+
+import (
+	"context"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/backend"
+	"github.com/sourcegraph/sourcegraph/cmd/frontend/graphqlbackend"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/lsifserver/client"
+	"github.com/sourcegraph/sourcegraph/internal/lsif"
+)
+
+// LSIFRetention reports how the configured retention policies apply to each completed upload
+// of the given repository. This does not delete any upload.
+func (r *Resolver) LSIFRetention(ctx context.Context, repositoryID graphql.ID) ([]graphqlbackend.LSIFUploadRetentionResolver, error) {
+	// 🚨 SECURITY: Only site admins may inspect the retention of LSIF data
+	if err := backend.CheckCurrentUserIsSiteAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	repoID, err := graphqlbackend.UnmarshalRepositoryID(repositoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	retentions, err := client.DefaultClient.GetRetention(ctx, &struct {
+		RepoID api.RepoID
+	}{
+		RepoID: repoID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resolvers := make([]graphqlbackend.LSIFUploadRetentionResolver, 0, len(retentions))
+	for _, retention := range retentions {
+		resolvers = append(resolvers, &lsifUploadRetentionResolver{retention: retention})
+	}
+
+	return resolvers, nil
+}
+
+type lsifUploadRetentionResolver struct {
+	retention *lsif.LSIFUploadRetention
+}
+
+var _ graphqlbackend.LSIFUploadRetentionResolver = &lsifUploadRetentionResolver{}
+
+func (r *lsifUploadRetentionResolver) Upload() graphqlbackend.LSIFUploadResolver {
+	return &lsifUploadResolver{lsifUpload: &r.retention.Upload}
+}
+
+func (r *lsifUploadRetentionResolver) RetainedBecause() []string {
+	return r.retention.RetainedBecause
+}
+
+func (r *lsifUploadRetentionResolver) Expired() bool {
+	return r.retention.Expired
+}
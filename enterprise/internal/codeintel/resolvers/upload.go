@@ -75,6 +75,24 @@ func (r *lsifUploadResolver) PlaceInQueue() *int32 {
 	return r.lsifUpload.PlaceInQueue
 }
 
+func (r *lsifUploadResolver) Diagnostics(ctx context.Context) ([]graphqlbackend.LSIFUploadDiagnosticResolver, error) {
+	diagnostics, err := client.DefaultClient.GetUploadDiagnostics(ctx, &struct {
+		UploadID int64
+	}{
+		UploadID: r.lsifUpload.ID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resolvers := make([]graphqlbackend.LSIFUploadDiagnosticResolver, 0, len(diagnostics))
+	for _, diagnostic := range diagnostics {
+		resolvers = append(resolvers, &lsifUploadDiagnosticResolver{diagnostic: diagnostic})
+	}
+
+	return resolvers, nil
+}
+
 type lsifUploadFailureReasonResolver struct {
 	lsifUpload *lsif.LSIFUpload
 }
@@ -97,6 +115,32 @@ func (r *lsifUploadFailureReasonResolver) Stacktrace() string {
 	return *r.lsifUpload.FailureStacktrace
 }
 
+type lsifUploadDiagnosticResolver struct {
+	diagnostic *lsif.LSIFUploadDiagnostic
+}
+
+var _ graphqlbackend.LSIFUploadDiagnosticResolver = &lsifUploadDiagnosticResolver{}
+
+func (r *lsifUploadDiagnosticResolver) Severity() string {
+	return strings.ToUpper(r.diagnostic.Severity)
+}
+
+func (r *lsifUploadDiagnosticResolver) Code() string {
+	return r.diagnostic.Code
+}
+
+func (r *lsifUploadDiagnosticResolver) Message() string {
+	return r.diagnostic.Message
+}
+
+func (r *lsifUploadDiagnosticResolver) ElementID() string {
+	return r.diagnostic.ElementID
+}
+
+func (r *lsifUploadDiagnosticResolver) Line() int32 {
+	return r.diagnostic.Line
+}
+
 type LSIFUploadsListOptions struct {
 	RepositoryID    graphql.ID
 	Query           *string
@@ -3,6 +3,9 @@ package client
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
 )
 
 // BundleClient is the interface to the precise-code-intel-bundle-manager service scoped to a particular dump.
@@ -29,6 +32,20 @@ type BundleClient interface {
 
 	// PackageInformation retrieves package information data by its identifier.
 	PackageInformation(ctx context.Context, path, packageInformationID string) (PackageInformationData, error)
+
+	// DocumentSymbols retrieves the symbols defined in the given path, nested by containment.
+	DocumentSymbols(ctx context.Context, path string) ([]Symbol, error)
+
+	// SearchSymbols retrieves up to limit symbols of the dump which match the given query, ordered by
+	// path and position.
+	SearchSymbols(ctx context.Context, query SymbolQuery, limit int) ([]SymbolLocation, error)
+
+	// ReferenceIdentifiers retrieves the distinct moniker schemes and identifiers referenced by the dump.
+	ReferenceIdentifiers(ctx context.Context) ([]ReferenceIdentifier, error)
+
+	// Ranges retrieves the hover text, definitions, and number of references of the ranges containing the
+	// given positions of the given path. If no positions are given, every range of the path is retrieved.
+	Ranges(ctx context.Context, path string, positions []Position) ([]CodeIntelligenceRange, error)
 }
 
 type bundleClientImpl struct {
@@ -150,6 +167,89 @@ func (c *bundleClientImpl) PackageInformation(ctx context.Context, path, package
 	return target, err
 }
 
+// DocumentSymbols retrieves the symbols defined in the given path, nested by containment.
+func (c *bundleClientImpl) DocumentSymbols(ctx context.Context, path string) (symbols []Symbol, err error) {
+	err = c.request(ctx, "documentSymbols", map[string]interface{}{"path": path}, &symbols)
+	return symbols, err
+}
+
+// SearchSymbols retrieves up to limit symbols of the dump which match the given query, ordered by
+// path and position.
+func (c *bundleClientImpl) SearchSymbols(ctx context.Context, query SymbolQuery, limit int) (symbols []SymbolLocation, err error) {
+	args := map[string]interface{}{
+		"query":   query.Query,
+		"root":    query.Root,
+		"include": query.IncludePatterns,
+		"exclude": query.ExcludePattern,
+		"limit":   limit,
+	}
+
+	err = c.request(ctx, "symbols", args, &symbols)
+	return symbols, err
+}
+
+// ReferenceIdentifiers retrieves the distinct moniker schemes and identifiers referenced by the dump.
+func (c *bundleClientImpl) ReferenceIdentifiers(ctx context.Context) (identifiers []ReferenceIdentifier, err error) {
+	err = c.request(ctx, "referenceIdentifiers", nil, &identifiers)
+	return identifiers, err
+}
+
+// Ranges retrieves the hover text, definitions, and number of references of the ranges containing the
+// given positions of the given path. If no positions are given, every range of the path is retrieved.
+func (c *bundleClientImpl) Ranges(ctx context.Context, path string, positions []Position) (ranges []CodeIntelligenceRange, err error) {
+	args := map[string]interface{}{
+		"path": path,
+	}
+	if len(positions) > 0 {
+		args["positions"] = FormatPositions(positions)
+	}
+
+	err = c.request(ctx, "ranges", args, &ranges)
+	for i := range ranges {
+		c.addBundleIDToLocations(ranges[i].Definitions)
+	}
+	return ranges, err
+}
+
+// FormatPositions encodes the given positions as a comma-separated list of `line:character` pairs.
+func FormatPositions(positions []Position) string {
+	parts := make([]string, 0, len(positions))
+	for _, position := range positions {
+		parts = append(parts, fmt.Sprintf("%d:%d", position.Line, position.Character))
+	}
+
+	return strings.Join(parts, ",")
+}
+
+// ParsePositions decodes a comma-separated list of `line:character` pairs. An empty value is an
+// empty list.
+func ParsePositions(value string) ([]Position, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	var positions []Position
+	for _, part := range strings.Split(value, ",") {
+		i := strings.Index(part, ":")
+		if i < 0 {
+			return nil, fmt.Errorf("malformed position %q", part)
+		}
+
+		line, err := strconv.Atoi(part[:i])
+		if err != nil {
+			return nil, fmt.Errorf("malformed position %q", part)
+		}
+		character, err := strconv.Atoi(part[i+1:])
+		if err != nil {
+			return nil, fmt.Errorf("malformed position %q", part)
+		}
+
+		positions = append(positions, Position{Line: line, Character: character})
+	}
+
+	return positions, nil
+}
+
 func (c *bundleClientImpl) request(ctx context.Context, path string, qs map[string]interface{}, target interface{}) error {
 	return c.base.QueryBundle(ctx, c.bundleID, path, qs, &target)
 }
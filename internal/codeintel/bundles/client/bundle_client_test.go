@@ -270,6 +270,133 @@ func TestPackageInformation(t *testing.T) {
 	}
 }
 
+func TestDocumentSymbols(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assertRequest(t, r, "GET", "/dbs/42/documentSymbols", map[string]string{
+			"path": "main.go",
+		})
+
+		_, _ = w.Write([]byte(`[
+			{"name": "Server", "kind": 23, "range": {"start": {"line": 3, "character": 5}, "end": {"line": 3, "character": 11}}, "children": [
+				{"name": "addr", "detail": "string", "kind": 8, "range": {"start": {"line": 4, "character": 1}, "end": {"line": 4, "character": 5}}}
+			]}
+		]`))
+	}))
+	defer ts.Close()
+
+	expected := []Symbol{
+		{
+			Name:  "Server",
+			Kind:  23,
+			Range: Range{Start: Position{3, 5}, End: Position{3, 11}},
+			Children: []Symbol{
+				{Name: "addr", Detail: "string", Kind: 8, Range: Range{Start: Position{4, 1}, End: Position{4, 5}}},
+			},
+		},
+	}
+
+	client := &bundleClientImpl{base: &bundleManagerClientImpl{bundleManagerURL: ts.URL}, bundleID: 42}
+	symbols, err := client.DocumentSymbols(context.Background(), "main.go")
+	if err != nil {
+		t.Fatalf("unexpected error querying document symbols: %s", err)
+	} else if diff := cmp.Diff(expected, symbols); diff != "" {
+		t.Errorf("unexpected symbols (-want +got):\n%s", diff)
+	}
+}
+
+func TestSearchSymbols(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assertRequest(t, r, "GET", "/dbs/42/symbols", map[string]string{
+			"query":   "^Serve",
+			"root":    "sub/",
+			"include": `\.go$`,
+			"exclude": "_test",
+			"limit":   "25",
+		})
+
+		_, _ = w.Write([]byte(`[
+			{"name": "ServeHTTP", "kind": 6, "containerName": "Server", "path": "main.go", "range": {"start": {"line": 9, "character": 17}, "end": {"line": 9, "character": 26}}}
+		]`))
+	}))
+	defer ts.Close()
+
+	expected := []SymbolLocation{
+		{Name: "ServeHTTP", Kind: 6, ContainerName: "Server", Path: "main.go", Range: Range{Start: Position{9, 17}, End: Position{9, 26}}},
+	}
+
+	query := SymbolQuery{Query: "^Serve", Root: "sub/", IncludePatterns: []string{`\.go$`}, ExcludePattern: "_test"}
+	client := &bundleClientImpl{base: &bundleManagerClientImpl{bundleManagerURL: ts.URL}, bundleID: 42}
+	symbols, err := client.SearchSymbols(context.Background(), query, 25)
+	if err != nil {
+		t.Fatalf("unexpected error querying symbols: %s", err)
+	} else if diff := cmp.Diff(expected, symbols); diff != "" {
+		t.Errorf("unexpected symbols (-want +got):\n%s", diff)
+	}
+}
+
+func TestRanges(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assertRequest(t, r, "GET", "/dbs/42/ranges", map[string]string{
+			"path":      "main.go",
+			"positions": "10:20,30:40",
+		})
+
+		_, _ = w.Write([]byte(`[
+			{
+				"range": {"start": {"line": 10, "character": 18}, "end": {"line": 10, "character": 22}},
+				"hoverText": "func main()",
+				"definitions": [{"path": "foo.go", "range": {"start": {"line": 1, "character": 2}, "end": {"line": 3, "character": 4}}}],
+				"referenceCount": 3,
+				"monikers": [{"Kind": "export", "Scheme": "gomod", "Identifier": "main:main", "PackageInformationID": "1"}]
+			}
+		]`))
+	}))
+	defer ts.Close()
+
+	expected := []CodeIntelligenceRange{
+		{
+			Range:          Range{Start: Position{10, 18}, End: Position{10, 22}},
+			HoverText:      "func main()",
+			Definitions:    []Location{{DumpID: 42, Path: "foo.go", Range: Range{Start: Position{1, 2}, End: Position{3, 4}}}},
+			ReferenceCount: 3,
+			Monikers:       []MonikerData{{Kind: "export", Scheme: "gomod", Identifier: "main:main", PackageInformationID: "1"}},
+		},
+	}
+
+	client := &bundleClientImpl{base: &bundleManagerClientImpl{bundleManagerURL: ts.URL}, bundleID: 42}
+	ranges, err := client.Ranges(context.Background(), "main.go", []Position{{10, 20}, {30, 40}})
+	if err != nil {
+		t.Fatalf("unexpected error querying ranges: %s", err)
+	} else if diff := cmp.Diff(expected, ranges); diff != "" {
+		t.Errorf("unexpected ranges (-want +got):\n%s", diff)
+	}
+}
+
+func TestReferenceIdentifiers(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assertRequest(t, r, "GET", "/dbs/42/referenceIdentifiers", map[string]string{})
+
+		_, _ = w.Write([]byte(`[
+			{"Scheme": "gomod", "Identifier": "github.com/foo/bar:Baz"},
+			{"Scheme": "npm", "Identifier": "lodash:merge"}
+		]`))
+	}))
+	defer ts.Close()
+
+	expected := []ReferenceIdentifier{
+		{Scheme: "gomod", Identifier: "github.com/foo/bar:Baz"},
+		{Scheme: "npm", Identifier: "lodash:merge"},
+	}
+
+	client := &bundleClientImpl{base: &bundleManagerClientImpl{bundleManagerURL: ts.URL}, bundleID: 42}
+	identifiers, err := client.ReferenceIdentifiers(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error querying reference identifiers: %s", err)
+	} else if diff := cmp.Diff(expected, identifiers); diff != "" {
+		t.Errorf("unexpected identifiers (-want +got):\n%s", diff)
+	}
+}
+
 func assertRequest(t *testing.T, r *http.Request, expectedMethod, expectedPath string, expectedQuery map[string]string) {
 	if r.Method != expectedMethod {
 		t.Errorf("unexpected method. want=%s have=%s", expectedMethod, r.Method)
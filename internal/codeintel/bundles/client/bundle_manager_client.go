@@ -11,6 +11,7 @@ import (
 	"path/filepath"
 
 	"github.com/google/uuid"
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/storage"
 )
 
 // BundleManagerClient is the interface to the precise-code-intel-bundle-manager service.
@@ -18,14 +19,14 @@ type BundleManagerClient interface {
 	// BundleClient creates a client that can answer intelligence queries for a single dump.
 	BundleClient(bundleID int) BundleClient
 
-	// SendUpload transfers a raw LSIF upload to the bundle manager to be stored on disk.
+	// SendUpload transfers a raw LSIF upload to the bundle manager to be stored.
 	SendUpload(ctx context.Context, bundleID int, r io.Reader) error
 
-	// GetUploads retrieves a raw LSIF upload from disk. The file is written to a file in the
+	// GetUploads retrieves a raw LSIF upload from storage. The file is written to a file in the
 	// given directory with a random filename. The generated filename is returned on success.
 	GetUpload(ctx context.Context, bundleID int, dir string) (string, error)
 
-	// SendDB transfers a converted databse to the bundle manager to be stored on disk.
+	// SendDB transfers a converted databse to the bundle manager to be stored.
 	SendDB(ctx context.Context, bundleID int, r io.Reader) error
 }
 
@@ -35,6 +36,7 @@ type baseClient interface {
 
 type bundleManagerClientImpl struct {
 	bundleManagerURL string
+	store            storage.Store
 }
 
 var _ BundleManagerClient = &bundleManagerClientImpl{}
@@ -44,6 +46,14 @@ func New(bundleManagerURL string) BundleManagerClient {
 	return &bundleManagerClientImpl{bundleManagerURL: bundleManagerURL}
 }
 
+// NewWithStore creates a client which transfers uploads and converted databases directly
+// to and from the given store, which must be the store used by the bundle manager. Queries
+// are still answered by the bundle manager. This avoids proxying large files through the
+// bundle manager when it uses object storage.
+func NewWithStore(bundleManagerURL string, store storage.Store) BundleManagerClient {
+	return &bundleManagerClientImpl{bundleManagerURL: bundleManagerURL, store: store}
+}
+
 // BundleClient creates a client that can answer intelligence queries for a single dump.
 func (c *bundleManagerClientImpl) BundleClient(bundleID int) BundleClient {
 	return &bundleClientImpl{
@@ -52,8 +62,12 @@ func (c *bundleManagerClientImpl) BundleClient(bundleID int) BundleClient {
 	}
 }
 
-// SendUpload transfers a raw LSIF upload to the bundle manager to be stored on disk.
+// SendUpload transfers a raw LSIF upload to the bundle manager to be stored.
 func (c *bundleManagerClientImpl) SendUpload(ctx context.Context, bundleID int, r io.Reader) error {
+	if c.store != nil {
+		return c.store.Upload(ctx, storage.UploadKey(int64(bundleID)), r)
+	}
+
 	url, err := makeURL(c.bundleManagerURL, fmt.Sprintf("uploads/%d", bundleID), nil)
 	if err != nil {
 		return err
@@ -67,15 +81,10 @@ func (c *bundleManagerClientImpl) SendUpload(ctx context.Context, bundleID int,
 	return nil
 }
 
-// GetUploads retrieves a raw LSIF upload from disk. The file is written to a file in the
+// GetUploads retrieves a raw LSIF upload from storage. The file is written to a file in the
 // given directory with a random filename. The generated filename is returned on success.
 func (c *bundleManagerClientImpl) GetUpload(ctx context.Context, bundleID int, dir string) (_ string, err error) {
-	url, err := makeURL(c.bundleManagerURL, fmt.Sprintf("uploads/%d", bundleID), nil)
-	if err != nil {
-		return "", err
-	}
-
-	body, err := c.do(ctx, "GET", url, nil)
+	body, err := c.getUpload(ctx, bundleID)
 	if err != nil {
 		return "", err
 	}
@@ -98,8 +107,26 @@ func (c *bundleManagerClientImpl) GetUpload(ctx context.Context, bundleID int, d
 	return f.Name(), nil
 }
 
-// SendDB transfers a converted databse to the bundle manager to be stored on disk.
+// getUpload returns a reader of the raw LSIF upload with the given identifier.
+func (c *bundleManagerClientImpl) getUpload(ctx context.Context, bundleID int) (io.ReadCloser, error) {
+	if c.store != nil {
+		return c.store.Get(ctx, storage.UploadKey(int64(bundleID)))
+	}
+
+	url, err := makeURL(c.bundleManagerURL, fmt.Sprintf("uploads/%d", bundleID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.do(ctx, "GET", url, nil)
+}
+
+// SendDB transfers a converted databse to the bundle manager to be stored.
 func (c *bundleManagerClientImpl) SendDB(ctx context.Context, bundleID int, r io.Reader) error {
+	if c.store != nil {
+		return c.store.Upload(ctx, storage.DBKey(int64(bundleID)), r)
+	}
+
 	url, err := makeURL(c.bundleManagerURL, fmt.Sprintf("dbs/%d", bundleID), nil)
 	if err != nil {
 		return err
@@ -163,7 +190,11 @@ func openRandomFile(dir string) (*os.File, error) {
 func makeURL(baseURL, path string, qs map[string]interface{}) (*url.URL, error) {
 	values := url.Values{}
 	for k, v := range qs {
-		values[k] = []string{fmt.Sprintf("%v", v)}
+		if vs, ok := v.([]string); ok {
+			values[k] = vs
+		} else {
+			values[k] = []string{fmt.Sprintf("%v", v)}
+		}
 	}
 
 	url, err := url.Parse(fmt.Sprintf("%s/%s", baseURL, path))
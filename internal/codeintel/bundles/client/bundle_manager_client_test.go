@@ -12,6 +12,7 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/storage"
 )
 
 func TestSendUpload(t *testing.T) {
@@ -143,3 +144,47 @@ func TestSendDBBadResponse(t *testing.T) {
 		t.Fatalf("unexpected nil error sending db")
 	}
 }
+
+func TestTransfersWithStore(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request to bundle manager: %s %s", r.Method, r.URL.Path)
+	}))
+	defer ts.Close()
+
+	storeDir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatalf("unexpected error creating temp directory: %s", err)
+	}
+	defer os.RemoveAll(storeDir)
+	store := storage.NewLocal(storeDir)
+
+	client := NewWithStore(ts.URL, store)
+	if err := client.SendUpload(context.Background(), 42, bytes.NewReader([]byte("payload\n"))); err != nil {
+		t.Fatalf("unexpected error sending upload: %s", err)
+	}
+	if err := client.SendDB(context.Background(), 42, bytes.NewReader([]byte("db\n"))); err != nil {
+		t.Fatalf("unexpected error sending db: %s", err)
+	}
+
+	if content, err := ioutil.ReadFile(store.Path(storage.DBKey(42))); err != nil {
+		t.Fatalf("unexpected error reading db: %s", err)
+	} else if diff := cmp.Diff([]byte("db\n"), content); diff != "" {
+		t.Errorf("unexpected db contents (-want +got):\n%s", diff)
+	}
+
+	tempDir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatalf("unexpected error creating temp directory: %s", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	filename, err := client.GetUpload(context.Background(), 42, tempDir)
+	if err != nil {
+		t.Fatalf("unexpected error getting upload: %s", err)
+	}
+	if content, err := ioutil.ReadFile(filename); err != nil {
+		t.Fatalf("unexpected error reading upload: %s", err)
+	} else if diff := cmp.Diff([]byte("payload\n"), content); diff != "" {
+		t.Errorf("unexpected upload contents (-want +got):\n%s", diff)
+	}
+}
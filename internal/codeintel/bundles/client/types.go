@@ -32,3 +32,47 @@ type PackageInformationData struct {
 	Name    string `json:"name"`
 	Version string `json:"version"`
 }
+
+// Symbol is a symbol defined in a document along with the symbols it contains.
+type Symbol struct {
+	Name     string   `json:"name"`
+	Detail   string   `json:"detail"`
+	Kind     int      `json:"kind"`
+	Range    Range    `json:"range"`
+	Children []Symbol `json:"children"`
+}
+
+// SymbolQuery selects the symbols of a symbol search. Query matches symbol names, and the include
+// and exclude patterns match the path of the symbol prefixed with Root. All patterns are regular
+// expressions, and empty patterns match everything.
+type SymbolQuery struct {
+	Query           string
+	Root            string
+	IncludePatterns []string
+	ExcludePattern  string
+}
+
+// SymbolLocation is a symbol along with the document that defines it.
+type SymbolLocation struct {
+	Name          string `json:"name"`
+	Kind          int    `json:"kind"`
+	ContainerName string `json:"containerName"`
+	Path          string `json:"path"`
+	Range         Range  `json:"range"`
+}
+
+// CodeIntelligenceRange is the hover text, definitions, and number of references of a range
+// within a dump, along with the monikers attached to the range.
+type CodeIntelligenceRange struct {
+	Range          Range         `json:"range"`
+	HoverText      string        `json:"hoverText"`
+	Definitions    []Location    `json:"definitions"`
+	ReferenceCount int           `json:"referenceCount"`
+	Monikers       []MonikerData `json:"monikers"`
+}
+
+// ReferenceIdentifier is a moniker scheme and identifier referenced by a dump.
+type ReferenceIdentifier struct {
+	Scheme     string `json:"scheme"`
+	Identifier string `json:"identifier"`
+}
@@ -16,6 +16,9 @@ type MockBundleClient struct {
 	// DefinitionsFunc is an instance of a mock function object controlling
 	// the behavior of the method Definitions.
 	DefinitionsFunc *BundleClientDefinitionsFunc
+	// DocumentSymbolsFunc is an instance of a mock function object
+	// controlling the behavior of the method DocumentSymbols.
+	DocumentSymbolsFunc *BundleClientDocumentSymbolsFunc
 	// ExistsFunc is an instance of a mock function object controlling the
 	// behavior of the method Exists.
 	ExistsFunc *BundleClientExistsFunc
@@ -31,9 +34,18 @@ type MockBundleClient struct {
 	// PackageInformationFunc is an instance of a mock function object
 	// controlling the behavior of the method PackageInformation.
 	PackageInformationFunc *BundleClientPackageInformationFunc
+	// RangesFunc is an instance of a mock function object controlling the
+	// behavior of the method Ranges.
+	RangesFunc *BundleClientRangesFunc
+	// ReferenceIdentifiersFunc is an instance of a mock function object
+	// controlling the behavior of the method ReferenceIdentifiers.
+	ReferenceIdentifiersFunc *BundleClientReferenceIdentifiersFunc
 	// ReferencesFunc is an instance of a mock function object controlling
 	// the behavior of the method References.
 	ReferencesFunc *BundleClientReferencesFunc
+	// SearchSymbolsFunc is an instance of a mock function object
+	// controlling the behavior of the method SearchSymbols.
+	SearchSymbolsFunc *BundleClientSearchSymbolsFunc
 }
 
 // NewMockBundleClient creates a new mock of the BundleClient interface. All
@@ -45,6 +57,11 @@ func NewMockBundleClient() *MockBundleClient {
 				return nil, nil
 			},
 		},
+		DocumentSymbolsFunc: &BundleClientDocumentSymbolsFunc{
+			defaultHook: func(context.Context, string) ([]client.Symbol, error) {
+				return nil, nil
+			},
+		},
 		ExistsFunc: &BundleClientExistsFunc{
 			defaultHook: func(context.Context, string) (bool, error) {
 				return false, nil
@@ -70,11 +87,26 @@ func NewMockBundleClient() *MockBundleClient {
 				return client.PackageInformationData{}, nil
 			},
 		},
+		RangesFunc: &BundleClientRangesFunc{
+			defaultHook: func(context.Context, string, []client.Position) ([]client.CodeIntelligenceRange, error) {
+				return nil, nil
+			},
+		},
+		ReferenceIdentifiersFunc: &BundleClientReferenceIdentifiersFunc{
+			defaultHook: func(context.Context) ([]client.ReferenceIdentifier, error) {
+				return nil, nil
+			},
+		},
 		ReferencesFunc: &BundleClientReferencesFunc{
 			defaultHook: func(context.Context, string, int, int) ([]client.Location, error) {
 				return nil, nil
 			},
 		},
+		SearchSymbolsFunc: &BundleClientSearchSymbolsFunc{
+			defaultHook: func(context.Context, client.SymbolQuery, int) ([]client.SymbolLocation, error) {
+				return nil, nil
+			},
+		},
 	}
 }
 
@@ -86,6 +118,9 @@ func NewMockBundleClientFrom(i client.BundleClient) *MockBundleClient {
 		DefinitionsFunc: &BundleClientDefinitionsFunc{
 			defaultHook: i.Definitions,
 		},
+		DocumentSymbolsFunc: &BundleClientDocumentSymbolsFunc{
+			defaultHook: i.DocumentSymbols,
+		},
 		ExistsFunc: &BundleClientExistsFunc{
 			defaultHook: i.Exists,
 		},
@@ -101,9 +136,18 @@ func NewMockBundleClientFrom(i client.BundleClient) *MockBundleClient {
 		PackageInformationFunc: &BundleClientPackageInformationFunc{
 			defaultHook: i.PackageInformation,
 		},
+		RangesFunc: &BundleClientRangesFunc{
+			defaultHook: i.Ranges,
+		},
+		ReferenceIdentifiersFunc: &BundleClientReferenceIdentifiersFunc{
+			defaultHook: i.ReferenceIdentifiers,
+		},
 		ReferencesFunc: &BundleClientReferencesFunc{
 			defaultHook: i.References,
 		},
+		SearchSymbolsFunc: &BundleClientSearchSymbolsFunc{
+			defaultHook: i.SearchSymbols,
+		},
 	}
 }
 
@@ -222,6 +266,116 @@ func (c BundleClientDefinitionsFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
 
+// BundleClientDocumentSymbolsFunc describes the behavior when the
+// DocumentSymbols method of the parent MockBundleClient instance is
+// invoked.
+type BundleClientDocumentSymbolsFunc struct {
+	defaultHook func(context.Context, string) ([]client.Symbol, error)
+	hooks       []func(context.Context, string) ([]client.Symbol, error)
+	history     []BundleClientDocumentSymbolsFuncCall
+	mutex       sync.Mutex
+}
+
+// DocumentSymbols delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockBundleClient) DocumentSymbols(v0 context.Context, v1 string) ([]client.Symbol, error) {
+	r0, r1 := m.DocumentSymbolsFunc.nextHook()(v0, v1)
+	m.DocumentSymbolsFunc.appendCall(BundleClientDocumentSymbolsFuncCall{v0, v1, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the DocumentSymbols
+// method of the parent MockBundleClient instance is invoked and the hook
+// queue is empty.
+func (f *BundleClientDocumentSymbolsFunc) SetDefaultHook(hook func(context.Context, string) ([]client.Symbol, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// DocumentSymbols method of the parent MockBundleClient instance inovkes
+// the hook at the front of the queue and discards it. After the queue is
+// empty, the default hook function is invoked for any future action.
+func (f *BundleClientDocumentSymbolsFunc) PushHook(hook func(context.Context, string) ([]client.Symbol, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *BundleClientDocumentSymbolsFunc) SetDefaultReturn(r0 []client.Symbol, r1 error) {
+	f.SetDefaultHook(func(context.Context, string) ([]client.Symbol, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *BundleClientDocumentSymbolsFunc) PushReturn(r0 []client.Symbol, r1 error) {
+	f.PushHook(func(context.Context, string) ([]client.Symbol, error) {
+		return r0, r1
+	})
+}
+
+func (f *BundleClientDocumentSymbolsFunc) nextHook() func(context.Context, string) ([]client.Symbol, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *BundleClientDocumentSymbolsFunc) appendCall(r0 BundleClientDocumentSymbolsFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of BundleClientDocumentSymbolsFuncCall objects
+// describing the invocations of this function.
+func (f *BundleClientDocumentSymbolsFunc) History() []BundleClientDocumentSymbolsFuncCall {
+	f.mutex.Lock()
+	history := make([]BundleClientDocumentSymbolsFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// BundleClientDocumentSymbolsFuncCall is an object that describes an
+// invocation of method DocumentSymbols on an instance of MockBundleClient.
+type BundleClientDocumentSymbolsFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 string
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []client.Symbol
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c BundleClientDocumentSymbolsFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c BundleClientDocumentSymbolsFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
 // BundleClientExistsFunc describes the behavior when the Exists method of
 // the parent MockBundleClient instance is invoked.
 type BundleClientExistsFunc struct {
@@ -805,6 +959,226 @@ func (c BundleClientPackageInformationFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
 
+// BundleClientRangesFunc describes the behavior when the Ranges method of
+// the parent MockBundleClient instance is invoked.
+type BundleClientRangesFunc struct {
+	defaultHook func(context.Context, string, []client.Position) ([]client.CodeIntelligenceRange, error)
+	hooks       []func(context.Context, string, []client.Position) ([]client.CodeIntelligenceRange, error)
+	history     []BundleClientRangesFuncCall
+	mutex       sync.Mutex
+}
+
+// Ranges delegates to the next hook function in the queue and stores the
+// parameter and result values of this invocation.
+func (m *MockBundleClient) Ranges(v0 context.Context, v1 string, v2 []client.Position) ([]client.CodeIntelligenceRange, error) {
+	r0, r1 := m.RangesFunc.nextHook()(v0, v1, v2)
+	m.RangesFunc.appendCall(BundleClientRangesFuncCall{v0, v1, v2, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the Ranges method of the
+// parent MockBundleClient instance is invoked and the hook queue is empty.
+func (f *BundleClientRangesFunc) SetDefaultHook(hook func(context.Context, string, []client.Position) ([]client.CodeIntelligenceRange, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// Ranges method of the parent MockBundleClient instance inovkes the hook at
+// the front of the queue and discards it. After the queue is empty, the
+// default hook function is invoked for any future action.
+func (f *BundleClientRangesFunc) PushHook(hook func(context.Context, string, []client.Position) ([]client.CodeIntelligenceRange, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *BundleClientRangesFunc) SetDefaultReturn(r0 []client.CodeIntelligenceRange, r1 error) {
+	f.SetDefaultHook(func(context.Context, string, []client.Position) ([]client.CodeIntelligenceRange, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *BundleClientRangesFunc) PushReturn(r0 []client.CodeIntelligenceRange, r1 error) {
+	f.PushHook(func(context.Context, string, []client.Position) ([]client.CodeIntelligenceRange, error) {
+		return r0, r1
+	})
+}
+
+func (f *BundleClientRangesFunc) nextHook() func(context.Context, string, []client.Position) ([]client.CodeIntelligenceRange, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *BundleClientRangesFunc) appendCall(r0 BundleClientRangesFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of BundleClientRangesFuncCall objects
+// describing the invocations of this function.
+func (f *BundleClientRangesFunc) History() []BundleClientRangesFuncCall {
+	f.mutex.Lock()
+	history := make([]BundleClientRangesFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// BundleClientRangesFuncCall is an object that describes an invocation of
+// method Ranges on an instance of MockBundleClient.
+type BundleClientRangesFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 string
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 []client.Position
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []client.CodeIntelligenceRange
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c BundleClientRangesFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c BundleClientRangesFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
+// BundleClientReferenceIdentifiersFunc describes the behavior when the
+// ReferenceIdentifiers method of the parent MockBundleClient instance is
+// invoked.
+type BundleClientReferenceIdentifiersFunc struct {
+	defaultHook func(context.Context) ([]client.ReferenceIdentifier, error)
+	hooks       []func(context.Context) ([]client.ReferenceIdentifier, error)
+	history     []BundleClientReferenceIdentifiersFuncCall
+	mutex       sync.Mutex
+}
+
+// ReferenceIdentifiers delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockBundleClient) ReferenceIdentifiers(v0 context.Context) ([]client.ReferenceIdentifier, error) {
+	r0, r1 := m.ReferenceIdentifiersFunc.nextHook()(v0)
+	m.ReferenceIdentifiersFunc.appendCall(BundleClientReferenceIdentifiersFuncCall{v0, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the ReferenceIdentifiers
+// method of the parent MockBundleClient instance is invoked and the hook
+// queue is empty.
+func (f *BundleClientReferenceIdentifiersFunc) SetDefaultHook(hook func(context.Context) ([]client.ReferenceIdentifier, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// ReferenceIdentifiers method of the parent MockBundleClient instance
+// inovkes the hook at the front of the queue and discards it. After the
+// queue is empty, the default hook function is invoked for any future
+// action.
+func (f *BundleClientReferenceIdentifiersFunc) PushHook(hook func(context.Context) ([]client.ReferenceIdentifier, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *BundleClientReferenceIdentifiersFunc) SetDefaultReturn(r0 []client.ReferenceIdentifier, r1 error) {
+	f.SetDefaultHook(func(context.Context) ([]client.ReferenceIdentifier, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *BundleClientReferenceIdentifiersFunc) PushReturn(r0 []client.ReferenceIdentifier, r1 error) {
+	f.PushHook(func(context.Context) ([]client.ReferenceIdentifier, error) {
+		return r0, r1
+	})
+}
+
+func (f *BundleClientReferenceIdentifiersFunc) nextHook() func(context.Context) ([]client.ReferenceIdentifier, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *BundleClientReferenceIdentifiersFunc) appendCall(r0 BundleClientReferenceIdentifiersFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of BundleClientReferenceIdentifiersFuncCall
+// objects describing the invocations of this function.
+func (f *BundleClientReferenceIdentifiersFunc) History() []BundleClientReferenceIdentifiersFuncCall {
+	f.mutex.Lock()
+	history := make([]BundleClientReferenceIdentifiersFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// BundleClientReferenceIdentifiersFuncCall is an object that describes an
+// invocation of method ReferenceIdentifiers on an instance of
+// MockBundleClient.
+type BundleClientReferenceIdentifiersFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []client.ReferenceIdentifier
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c BundleClientReferenceIdentifiersFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c BundleClientReferenceIdentifiersFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
 // BundleClientReferencesFunc describes the behavior when the References
 // method of the parent MockBundleClient instance is invoked.
 type BundleClientReferencesFunc struct {
@@ -919,3 +1293,115 @@ func (c BundleClientReferencesFuncCall) Args() []interface{} {
 func (c BundleClientReferencesFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
+
+// BundleClientSearchSymbolsFunc describes the behavior when the
+// SearchSymbols method of the parent MockBundleClient instance is invoked.
+type BundleClientSearchSymbolsFunc struct {
+	defaultHook func(context.Context, client.SymbolQuery, int) ([]client.SymbolLocation, error)
+	hooks       []func(context.Context, client.SymbolQuery, int) ([]client.SymbolLocation, error)
+	history     []BundleClientSearchSymbolsFuncCall
+	mutex       sync.Mutex
+}
+
+// SearchSymbols delegates to the next hook function in the queue and stores
+// the parameter and result values of this invocation.
+func (m *MockBundleClient) SearchSymbols(v0 context.Context, v1 client.SymbolQuery, v2 int) ([]client.SymbolLocation, error) {
+	r0, r1 := m.SearchSymbolsFunc.nextHook()(v0, v1, v2)
+	m.SearchSymbolsFunc.appendCall(BundleClientSearchSymbolsFuncCall{v0, v1, v2, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the SearchSymbols method
+// of the parent MockBundleClient instance is invoked and the hook queue is
+// empty.
+func (f *BundleClientSearchSymbolsFunc) SetDefaultHook(hook func(context.Context, client.SymbolQuery, int) ([]client.SymbolLocation, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// SearchSymbols method of the parent MockBundleClient instance inovkes the
+// hook at the front of the queue and discards it. After the queue is empty,
+// the default hook function is invoked for any future action.
+func (f *BundleClientSearchSymbolsFunc) PushHook(hook func(context.Context, client.SymbolQuery, int) ([]client.SymbolLocation, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *BundleClientSearchSymbolsFunc) SetDefaultReturn(r0 []client.SymbolLocation, r1 error) {
+	f.SetDefaultHook(func(context.Context, client.SymbolQuery, int) ([]client.SymbolLocation, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *BundleClientSearchSymbolsFunc) PushReturn(r0 []client.SymbolLocation, r1 error) {
+	f.PushHook(func(context.Context, client.SymbolQuery, int) ([]client.SymbolLocation, error) {
+		return r0, r1
+	})
+}
+
+func (f *BundleClientSearchSymbolsFunc) nextHook() func(context.Context, client.SymbolQuery, int) ([]client.SymbolLocation, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *BundleClientSearchSymbolsFunc) appendCall(r0 BundleClientSearchSymbolsFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of BundleClientSearchSymbolsFuncCall objects
+// describing the invocations of this function.
+func (f *BundleClientSearchSymbolsFunc) History() []BundleClientSearchSymbolsFuncCall {
+	f.mutex.Lock()
+	history := make([]BundleClientSearchSymbolsFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// BundleClientSearchSymbolsFuncCall is an object that describes an
+// invocation of method SearchSymbols on an instance of MockBundleClient.
+type BundleClientSearchSymbolsFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 client.SymbolQuery
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 int
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []client.SymbolLocation
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c BundleClientSearchSymbolsFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c BundleClientSearchSymbolsFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
@@ -26,12 +26,18 @@ type MockReader struct {
 	// ReadMetaFunc is an instance of a mock function object controlling the
 	// behavior of the method ReadMeta.
 	ReadMetaFunc *ReaderReadMetaFunc
+	// ReadReferenceIdentifiersFunc is an instance of a mock function object
+	// controlling the behavior of the method ReadReferenceIdentifiers.
+	ReadReferenceIdentifiersFunc *ReaderReadReferenceIdentifiersFunc
 	// ReadReferencesFunc is an instance of a mock function object
 	// controlling the behavior of the method ReadReferences.
 	ReadReferencesFunc *ReaderReadReferencesFunc
 	// ReadResultChunkFunc is an instance of a mock function object
 	// controlling the behavior of the method ReadResultChunk.
 	ReadResultChunkFunc *ReaderReadResultChunkFunc
+	// ReadSymbolsFunc is an instance of a mock function object controlling
+	// the behavior of the method ReadSymbols.
+	ReadSymbolsFunc *ReaderReadSymbolsFunc
 }
 
 // NewMockReader creates a new mock of the Reader interface. All methods
@@ -58,6 +64,11 @@ func NewMockReader() *MockReader {
 				return "", "", 0, nil
 			},
 		},
+		ReadReferenceIdentifiersFunc: &ReaderReadReferenceIdentifiersFunc{
+			defaultHook: func(context.Context) ([]types.ReferenceIdentifier, error) {
+				return nil, nil
+			},
+		},
 		ReadReferencesFunc: &ReaderReadReferencesFunc{
 			defaultHook: func(context.Context, string, string, int, int) ([]types.DefinitionReferenceRow, int, error) {
 				return nil, 0, nil
@@ -68,6 +79,11 @@ func NewMockReader() *MockReader {
 				return types.ResultChunkData{}, false, nil
 			},
 		},
+		ReadSymbolsFunc: &ReaderReadSymbolsFunc{
+			defaultHook: func(context.Context) ([]types.SymbolRow, error) {
+				return nil, nil
+			},
+		},
 	}
 }
 
@@ -87,12 +103,18 @@ func NewMockReaderFrom(i reader.Reader) *MockReader {
 		ReadMetaFunc: &ReaderReadMetaFunc{
 			defaultHook: i.ReadMeta,
 		},
+		ReadReferenceIdentifiersFunc: &ReaderReadReferenceIdentifiersFunc{
+			defaultHook: i.ReadReferenceIdentifiers,
+		},
 		ReadReferencesFunc: &ReaderReadReferencesFunc{
 			defaultHook: i.ReadReferences,
 		},
 		ReadResultChunkFunc: &ReaderReadResultChunkFunc{
 			defaultHook: i.ReadResultChunk,
 		},
+		ReadSymbolsFunc: &ReaderReadSymbolsFunc{
+			defaultHook: i.ReadSymbols,
+		},
 	}
 }
 
@@ -538,6 +560,114 @@ func (c ReaderReadMetaFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1, c.Result2, c.Result3}
 }
 
+// ReaderReadReferenceIdentifiersFunc describes the behavior when the
+// ReadReferenceIdentifiers method of the parent MockReader instance is
+// invoked.
+type ReaderReadReferenceIdentifiersFunc struct {
+	defaultHook func(context.Context) ([]types.ReferenceIdentifier, error)
+	hooks       []func(context.Context) ([]types.ReferenceIdentifier, error)
+	history     []ReaderReadReferenceIdentifiersFuncCall
+	mutex       sync.Mutex
+}
+
+// ReadReferenceIdentifiers delegates to the next hook function in the queue
+// and stores the parameter and result values of this invocation.
+func (m *MockReader) ReadReferenceIdentifiers(v0 context.Context) ([]types.ReferenceIdentifier, error) {
+	r0, r1 := m.ReadReferenceIdentifiersFunc.nextHook()(v0)
+	m.ReadReferenceIdentifiersFunc.appendCall(ReaderReadReferenceIdentifiersFuncCall{v0, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the
+// ReadReferenceIdentifiers method of the parent MockReader instance is
+// invoked and the hook queue is empty.
+func (f *ReaderReadReferenceIdentifiersFunc) SetDefaultHook(hook func(context.Context) ([]types.ReferenceIdentifier, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// ReadReferenceIdentifiers method of the parent MockReader instance inovkes
+// the hook at the front of the queue and discards it. After the queue is
+// empty, the default hook function is invoked for any future action.
+func (f *ReaderReadReferenceIdentifiersFunc) PushHook(hook func(context.Context) ([]types.ReferenceIdentifier, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *ReaderReadReferenceIdentifiersFunc) SetDefaultReturn(r0 []types.ReferenceIdentifier, r1 error) {
+	f.SetDefaultHook(func(context.Context) ([]types.ReferenceIdentifier, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *ReaderReadReferenceIdentifiersFunc) PushReturn(r0 []types.ReferenceIdentifier, r1 error) {
+	f.PushHook(func(context.Context) ([]types.ReferenceIdentifier, error) {
+		return r0, r1
+	})
+}
+
+func (f *ReaderReadReferenceIdentifiersFunc) nextHook() func(context.Context) ([]types.ReferenceIdentifier, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *ReaderReadReferenceIdentifiersFunc) appendCall(r0 ReaderReadReferenceIdentifiersFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of ReaderReadReferenceIdentifiersFuncCall
+// objects describing the invocations of this function.
+func (f *ReaderReadReferenceIdentifiersFunc) History() []ReaderReadReferenceIdentifiersFuncCall {
+	f.mutex.Lock()
+	history := make([]ReaderReadReferenceIdentifiersFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// ReaderReadReferenceIdentifiersFuncCall is an object that describes an
+// invocation of method ReadReferenceIdentifiers on an instance of
+// MockReader.
+type ReaderReadReferenceIdentifiersFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []types.ReferenceIdentifier
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c ReaderReadReferenceIdentifiersFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c ReaderReadReferenceIdentifiersFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
 // ReaderReadReferencesFunc describes the behavior when the ReadReferences
 // method of the parent MockReader instance is invoked.
 type ReaderReadReferencesFunc struct {
@@ -770,3 +900,108 @@ func (c ReaderReadResultChunkFuncCall) Args() []interface{} {
 func (c ReaderReadResultChunkFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1, c.Result2}
 }
+
+// ReaderReadSymbolsFunc describes the behavior when the ReadSymbols method
+// of the parent MockReader instance is invoked.
+type ReaderReadSymbolsFunc struct {
+	defaultHook func(context.Context) ([]types.SymbolRow, error)
+	hooks       []func(context.Context) ([]types.SymbolRow, error)
+	history     []ReaderReadSymbolsFuncCall
+	mutex       sync.Mutex
+}
+
+// ReadSymbols delegates to the next hook function in the queue and stores
+// the parameter and result values of this invocation.
+func (m *MockReader) ReadSymbols(v0 context.Context) ([]types.SymbolRow, error) {
+	r0, r1 := m.ReadSymbolsFunc.nextHook()(v0)
+	m.ReadSymbolsFunc.appendCall(ReaderReadSymbolsFuncCall{v0, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the ReadSymbols method
+// of the parent MockReader instance is invoked and the hook queue is empty.
+func (f *ReaderReadSymbolsFunc) SetDefaultHook(hook func(context.Context) ([]types.SymbolRow, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// ReadSymbols method of the parent MockReader instance inovkes the hook at
+// the front of the queue and discards it. After the queue is empty, the
+// default hook function is invoked for any future action.
+func (f *ReaderReadSymbolsFunc) PushHook(hook func(context.Context) ([]types.SymbolRow, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *ReaderReadSymbolsFunc) SetDefaultReturn(r0 []types.SymbolRow, r1 error) {
+	f.SetDefaultHook(func(context.Context) ([]types.SymbolRow, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *ReaderReadSymbolsFunc) PushReturn(r0 []types.SymbolRow, r1 error) {
+	f.PushHook(func(context.Context) ([]types.SymbolRow, error) {
+		return r0, r1
+	})
+}
+
+func (f *ReaderReadSymbolsFunc) nextHook() func(context.Context) ([]types.SymbolRow, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *ReaderReadSymbolsFunc) appendCall(r0 ReaderReadSymbolsFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of ReaderReadSymbolsFuncCall objects
+// describing the invocations of this function.
+func (f *ReaderReadSymbolsFunc) History() []ReaderReadSymbolsFuncCall {
+	f.mutex.Lock()
+	history := make([]ReaderReadSymbolsFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// ReaderReadSymbolsFuncCall is an object that describes an invocation of
+// method ReadSymbols on an instance of MockReader.
+type ReaderReadSymbolsFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []types.SymbolRow
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c ReaderReadSymbolsFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c ReaderReadSymbolsFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
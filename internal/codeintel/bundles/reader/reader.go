@@ -12,5 +12,7 @@ type Reader interface {
 	ReadResultChunk(ctx context.Context, id int) (types.ResultChunkData, bool, error)
 	ReadDefinitions(ctx context.Context, scheme, identifier string, skip, take int) ([]types.DefinitionReferenceRow, int, error)
 	ReadReferences(ctx context.Context, scheme, identifier string, skip, take int) ([]types.DefinitionReferenceRow, int, error)
+	ReadReferenceIdentifiers(ctx context.Context) ([]types.ReferenceIdentifier, error)
+	ReadSymbols(ctx context.Context) ([]types.SymbolRow, error)
 	Close() error
 }
@@ -145,6 +145,68 @@ func (r *sqliteReader) ReadReferences(ctx context.Context, scheme, identifier st
 	return rows, count, err
 }
 
+func (r *sqliteReader) ReadReferenceIdentifiers(ctx context.Context) ([]types.ReferenceIdentifier, error) {
+	query := `SELECT DISTINCT scheme, identifier FROM "references" ORDER BY scheme, identifier`
+
+	rows, err := r.query(ctx, sqlf.Sprintf(query))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var identifiers []types.ReferenceIdentifier
+	for rows.Next() {
+		var identifier types.ReferenceIdentifier
+		if err := rows.Scan(&identifier.Scheme, &identifier.Identifier); err != nil {
+			return nil, err
+		}
+
+		identifiers = append(identifiers, identifier)
+	}
+
+	return identifiers, rows.Err()
+}
+
+func (r *sqliteReader) ReadSymbols(ctx context.Context) ([]types.SymbolRow, error) {
+	query := `
+		SELECT name, kind, containerName, documentPath, startLine, startCharacter, endLine, endCharacter
+		FROM symbols
+		ORDER BY documentPath, startLine, startCharacter
+	`
+
+	rows, err := r.query(ctx, sqlf.Sprintf(query))
+	if err != nil {
+		// Bundles written before symbols were searchable have no symbols table
+		if strings.Contains(err.Error(), "no such table") {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+	defer rows.Close()
+
+	var symbols []types.SymbolRow
+	for rows.Next() {
+		var symbol types.SymbolRow
+		if err := rows.Scan(
+			&symbol.Name,
+			&symbol.Kind,
+			&symbol.ContainerName,
+			&symbol.URI,
+			&symbol.StartLine,
+			&symbol.StartCharacter,
+			&symbol.EndLine,
+			&symbol.EndCharacter,
+		); err != nil {
+			return nil, err
+		}
+
+		symbols = append(symbols, symbol)
+	}
+
+	return symbols, rows.Err()
+}
+
 func (r *sqliteReader) Close() error {
 	return r.db.Close()
 }
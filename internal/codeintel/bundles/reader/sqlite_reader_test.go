@@ -142,6 +142,36 @@ func TestReadReferences(t *testing.T) {
 	}
 }
 
+func TestReadReferenceIdentifiers(t *testing.T) {
+	identifiers, err := testReader(t).ReadReferenceIdentifiers(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error getting reference identifiers: %s", err)
+	}
+
+	expectedIdentifier := types.ReferenceIdentifier{Scheme: "gomod", Identifier: "golang.org/x/tools/go/packages:Package"}
+
+	found := false
+	for _, identifier := range identifiers {
+		if identifier == expectedIdentifier {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected identifier %v in %v", expectedIdentifier, identifiers)
+	}
+}
+
+func TestReadSymbolsWithoutSymbolsTable(t *testing.T) {
+	// The test bundle predates the symbols table
+	symbols, err := testReader(t).ReadSymbols(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error getting symbols: %s", err)
+	}
+	if len(symbols) != 0 {
+		t.Errorf("unexpected symbols: %v", symbols)
+	}
+}
+
 func testReader(t *testing.T) Reader {
 	reader, err := NewSQLiteReader("../testdata/lsif-go@ad3507cb.lsif.db", serializer.NewDefaultSerializer())
 	if err != nil {
@@ -59,6 +59,7 @@ func (*defaultSerializer) MarshalDocumentData(d types.DocumentData) ([]byte, err
 		"hoverResults":       map[string]interface{}{"type": "map", "value": hoverResultPairs},
 		"monikers":           map[string]interface{}{"type": "map", "value": monikerPairs},
 		"packageInformation": map[string]interface{}{"type": "map", "value": packageInformationPairs},
+		"symbols":            marshalSymbols(d.Symbols),
 	})
 	if err != nil {
 		return nil, err
@@ -95,6 +96,7 @@ func (defaultSerializer) UnmarshalDocumentData(data []byte) (types.DocumentData,
 		HoverResults       wrappedMapValue `json:"hoverResults"`
 		Monikers           wrappedMapValue `json:"monikers"`
 		PackageInformation wrappedMapValue `json:"packageInformation"`
+		Symbols            []symbolValue   `json:"symbols"`
 	}{}
 
 	if err := unmarshalGzippedJSON(data, &payload); err != nil {
@@ -126,6 +128,7 @@ func (defaultSerializer) UnmarshalDocumentData(data []byte) (types.DocumentData,
 		HoverResults:       hoverResults,
 		Monikers:           monikers,
 		PackageInformation: packageInformation,
+		Symbols:            unmarshalSymbols(payload.Symbols),
 	}, nil
 }
 
@@ -221,6 +224,59 @@ func unmarshalWrappedRanges(pairs []json.RawMessage) (map[types.ID]types.RangeDa
 	return m, nil
 }
 
+// symbolValue is the encoded form of a document symbol. Documents written before symbols
+// were stored have no symbols field.
+type symbolValue struct {
+	Name           string        `json:"name"`
+	Detail         string        `json:"detail,omitempty"`
+	Kind           int           `json:"kind"`
+	StartLine      int           `json:"startLine"`
+	StartCharacter int           `json:"startCharacter"`
+	EndLine        int           `json:"endLine"`
+	EndCharacter   int           `json:"endCharacter"`
+	Children       []symbolValue `json:"children,omitempty"`
+}
+
+func marshalSymbols(symbols []types.SymbolData) []symbolValue {
+	values := make([]symbolValue, 0, len(symbols))
+	for _, s := range symbols {
+		values = append(values, symbolValue{
+			Name:           s.Name,
+			Detail:         s.Detail,
+			Kind:           s.Kind,
+			StartLine:      s.StartLine,
+			StartCharacter: s.StartCharacter,
+			EndLine:        s.EndLine,
+			EndCharacter:   s.EndCharacter,
+			Children:       marshalSymbols(s.Children),
+		})
+	}
+
+	return values
+}
+
+func unmarshalSymbols(values []symbolValue) []types.SymbolData {
+	if len(values) == 0 {
+		return nil
+	}
+
+	symbols := make([]types.SymbolData, 0, len(values))
+	for _, v := range values {
+		symbols = append(symbols, types.SymbolData{
+			Name:           v.Name,
+			Detail:         v.Detail,
+			Kind:           v.Kind,
+			StartLine:      v.StartLine,
+			StartCharacter: v.StartCharacter,
+			EndLine:        v.EndLine,
+			EndCharacter:   v.EndCharacter,
+			Children:       unmarshalSymbols(v.Children),
+		})
+	}
+
+	return symbols
+}
+
 func unmarshalWrappedHoverResults(pairs []json.RawMessage) (map[types.ID]string, error) {
 	m := map[types.ID]string{}
 	for _, pair := range pairs {
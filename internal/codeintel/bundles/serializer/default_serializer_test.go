@@ -94,6 +94,45 @@ func TestDefaultSerializerDocumentData(t *testing.T) {
 	}
 }
 
+func TestDefaultSerializerDocumentDataSymbols(t *testing.T) {
+	serializer := &defaultSerializer{}
+
+	expected := types.DocumentData{
+		Ranges:             map[types.ID]types.RangeData{},
+		HoverResults:       map[types.ID]string{},
+		Monikers:           map[types.ID]types.MonikerData{},
+		PackageInformation: map[types.ID]types.PackageInformationData{},
+		Symbols: []types.SymbolData{
+			{
+				Name:           "Writer",
+				Kind:           23,
+				StartLine:      10,
+				StartCharacter: 5,
+				EndLine:        10,
+				EndCharacter:   11,
+				Children: []types.SymbolData{
+					{Name: "Write", Detail: "func(v Vertex) error", Kind: 6, StartLine: 14, StartCharacter: 17, EndLine: 14, EndCharacter: 22},
+				},
+			},
+			{Name: "NewWriter", Kind: 12, StartLine: 20, StartCharacter: 5, EndLine: 20, EndCharacter: 14},
+		},
+	}
+
+	compressed, err := serializer.MarshalDocumentData(expected)
+	if err != nil {
+		t.Fatalf("unexpected error marshalling document data: %s", err)
+	}
+
+	actual, err := serializer.UnmarshalDocumentData(compressed)
+	if err != nil {
+		t.Fatalf("unexpected error unmarshalling document data: %s", err)
+	}
+
+	if diff := cmp.Diff(expected, actual); diff != "" {
+		t.Errorf("unexpected document data (-want +got):\n%s", diff)
+	}
+}
+
 func TestDefaultSerializerResultChunkData(t *testing.T) {
 	serializer := &defaultSerializer{}
 
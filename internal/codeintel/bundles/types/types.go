@@ -10,6 +10,7 @@ type DocumentData struct {
 	HoverResults       map[ID]string // hover text normalized to markdown string
 	Monikers           map[ID]MonikerData
 	PackageInformation map[ID]PackageInformationData
+	Symbols            []SymbolData // possibly empty
 }
 
 // RangeData represents a range vertex within an index. It contains the same relevant
@@ -27,6 +28,32 @@ type RangeData struct {
 	MonikerIDs         []ID // possibly empty
 }
 
+// SymbolData represents a document symbol within an index. Symbols form a tree that mirrors
+// the structure of the document (e.g. the methods of a class are children of the class).
+type SymbolData struct {
+	Name           string
+	Detail         string // possibly empty
+	Kind           int    // an LSP SymbolKind
+	StartLine      int    // 0-indexed, inclusive
+	StartCharacter int    // 0-indexed, inclusive
+	EndLine        int    // 0-indexed, inclusive
+	EndCharacter   int    // 0-indexed, inclusive
+	Children       []SymbolData
+}
+
+// SymbolRow is a document symbol flattened out of the symbol tree of its document, so that
+// symbols can be searched by name without reading every document of a bundle.
+type SymbolRow struct {
+	Name           string
+	Kind           int    // an LSP SymbolKind
+	ContainerName  string // name of the enclosing symbol, possibly empty
+	URI            string
+	StartLine      int
+	StartCharacter int
+	EndLine        int
+	EndCharacter   int
+}
+
 // MonikerData represent a unique name (eventually) attached to a range.
 type MonikerData struct {
 	Kind                 string // local, import, export
@@ -85,6 +112,16 @@ type PackageReference struct {
 	Name    string
 	Version string
 	Filter  []byte // a bloom filter of identifiers imported by this dependent
+
+	// Identifiers are the identifiers imported by this dependent, in sorted order. They are
+	// indexed so that remote references can be found without testing every bloom filter.
+	Identifiers []string
+}
+
+// ReferenceIdentifier is a moniker scheme and identifier referenced by a dump.
+type ReferenceIdentifier struct {
+	Scheme     string
+	Identifier string
 }
 
 // DefinitionReferenceRow represents a linking between a definition of a symbol or
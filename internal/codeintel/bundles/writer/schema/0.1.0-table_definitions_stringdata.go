@@ -41,4 +41,16 @@ CREATE TABLE "references" (
     "startCharacter" integer NOT NULL,
     "endCharacter" integer NOT NULL
 );
+
+CREATE TABLE "symbols" (
+    "id" integer PRIMARY KEY NOT NULL,
+    "name" text NOT NULL,
+    "kind" integer NOT NULL,
+    "containerName" text NOT NULL,
+    "documentPath" text NOT NULL,
+    "startLine" integer NOT NULL,
+    "endLine" integer NOT NULL,
+    "startCharacter" integer NOT NULL,
+    "endCharacter" integer NOT NULL
+);
 `
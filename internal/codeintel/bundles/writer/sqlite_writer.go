@@ -21,6 +21,7 @@ type sqliteWriter struct {
 	resultChunkInserter *sqliteutil.BatchInserter
 	definitionInserter  *sqliteutil.BatchInserter
 	referenceInserter   *sqliteutil.BatchInserter
+	symbolInserter      *sqliteutil.BatchInserter
 }
 
 var _ Writer = &sqliteWriter{}
@@ -53,6 +54,7 @@ func NewSQLiteWriter(filename string, serializer serializer.Serializer) (_ Write
 	documentsColumns := []string{"path", "data"}
 	resultChunksColumns := []string{"id", "data"}
 	definitionsReferencesColumns := []string{"scheme", "identifier", "documentPath", "startLine", "startCharacter", "endLine", "endCharacter"}
+	symbolsColumns := []string{"name", "kind", "containerName", "documentPath", "startLine", "startCharacter", "endLine", "endCharacter"}
 
 	return &sqliteWriter{
 		serializer:          serializer,
@@ -63,6 +65,7 @@ func NewSQLiteWriter(filename string, serializer serializer.Serializer) (_ Write
 		resultChunkInserter: sqliteutil.NewBatchInserter(tx, "resultChunks", resultChunksColumns...),
 		definitionInserter:  sqliteutil.NewBatchInserter(tx, "definitions", definitionsReferencesColumns...),
 		referenceInserter:   sqliteutil.NewBatchInserter(tx, `references`, definitionsReferencesColumns...),
+		symbolInserter:      sqliteutil.NewBatchInserter(tx, "symbols", symbolsColumns...),
 	}, nil
 }
 
@@ -80,6 +83,25 @@ func (w *sqliteWriter) WriteDocuments(ctx context.Context, documents map[string]
 		if err := w.documentInserter.Insert(ctx, k, ser); err != nil {
 			return err
 		}
+
+		if err := w.writeSymbols(ctx, k, "", v.Symbols); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeSymbols inserts the given symbol trees of the document with the given path into the
+// symbols table, which makes them searchable by name.
+func (w *sqliteWriter) writeSymbols(ctx context.Context, path, containerName string, symbols []types.SymbolData) error {
+	for _, s := range symbols {
+		if err := w.symbolInserter.Insert(ctx, s.Name, s.Kind, containerName, path, s.StartLine, s.StartCharacter, s.EndLine, s.EndCharacter); err != nil {
+			return err
+		}
+
+		if err := w.writeSymbols(ctx, path, s.Name, s.Children); err != nil {
+			return err
+		}
 	}
 	return nil
 }
@@ -123,6 +145,7 @@ func (w *sqliteWriter) Flush(ctx context.Context) error {
 		w.resultChunkInserter,
 		w.definitionInserter,
 		w.referenceInserter,
+		w.symbolInserter,
 	}
 
 	for _, inserter := range inserters {
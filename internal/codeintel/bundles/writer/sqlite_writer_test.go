@@ -56,6 +56,11 @@ func TestWrite(t *testing.T) {
 			"p01": {Name: "pkg A", Version: "0.1.0"},
 			"p02": {Name: "pkg B", Version: "1.2.3"},
 		},
+		Symbols: []types.SymbolData{
+			{Name: "Foo", Kind: 23, StartLine: 1, StartCharacter: 0, EndLine: 5, EndCharacter: 1, Children: []types.SymbolData{
+				{Name: "bar", Kind: 8, StartLine: 2, StartCharacter: 1, EndLine: 2, EndCharacter: 9},
+			}},
+		},
 	}
 	if err := writer.WriteDocuments(ctx, map[string]types.DocumentData{"foo.go": expectedDocumentData}); err != nil {
 		t.Fatalf("unexpected error while writing documents: %s", err)
@@ -165,4 +170,16 @@ func TestWrite(t *testing.T) {
 	if diff := cmp.Diff(expectedReferences, references); diff != "" {
 		t.Errorf("unexpected references (-want +got):\n%s", diff)
 	}
+
+	expectedSymbols := []types.SymbolRow{
+		{Name: "Foo", Kind: 23, URI: "foo.go", StartLine: 1, StartCharacter: 0, EndLine: 5, EndCharacter: 1},
+		{Name: "bar", Kind: 8, ContainerName: "Foo", URI: "foo.go", StartLine: 2, StartCharacter: 1, EndLine: 2, EndCharacter: 9},
+	}
+	symbols, err := reader.ReadSymbols(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error reading from database: %s", err)
+	}
+	if diff := cmp.Diff(expectedSymbols, symbols); diff != "" {
+		t.Errorf("unexpected symbols (-want +got):\n%s", diff)
+	}
 }
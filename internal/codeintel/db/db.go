@@ -13,8 +13,11 @@ import (
 // DB is the interface to Postgres that deals with LSIF-specific tables.
 //
 //   - lsif_commits
+//   - lsif_indexes
 //   - lsif_packages
+//   - lsif_reference_identifiers
 //   - lsif_references
+//   - lsif_upload_diagnostics
 //   - lsif_uploads
 //
 // These tables are kept separate from the remainder of Sourcegraph tablespace.
@@ -45,6 +48,12 @@ type DB interface {
 	// false-valued flag.  This method must not be called from within a transaction.
 	Dequeue(ctx context.Context) (Upload, JobHandle, bool, error)
 
+	// GetDiagnostics returns the diagnostics of the given upload in the order in which they were found.
+	GetDiagnostics(ctx context.Context, uploadID int) ([]UploadDiagnostic, error)
+
+	// UpdateDiagnostics replaces the diagnostics of the given upload.
+	UpdateDiagnostics(ctx context.Context, uploadID int, diagnostics []UploadDiagnostic) error
+
 	// GetStates returns the states for the uploads with the given identifiers.
 	GetStates(ctx context.Context, ids []int) (map[int]string, error)
 
@@ -57,12 +66,49 @@ type DB interface {
 	// This method returns a list of updated upload identifiers.
 	ResetStalled(ctx context.Context, now time.Time) ([]int, error)
 
+	// GetIndexByID returns an index by its identifier and boolean flag indicating its existence.
+	GetIndexByID(ctx context.Context, id int) (Index, bool, error)
+
+	// GetIndexesByRepo returns a list of indexes for a particular repo and the total count of records matching the given conditions.
+	GetIndexesByRepo(ctx context.Context, repositoryID int, state string, limit, offset int) ([]Index, int, error)
+
+	// EnqueueIndex inserts a new index with a "queued" state and returns its identifier and a flag
+	// indicating its insertion. An index is not inserted if the repository already has an index or a
+	// non-errored upload for the same commit and indexer.
+	EnqueueIndex(ctx context.Context, commit string, repositoryID int, indexerName string) (int, bool, error)
+
+	// DequeueIndex selects the oldest queued index and marks it as processing. If there is no such
+	// index, a zero-value index is returned along with a false-valued flag.
+	DequeueIndex(ctx context.Context) (Index, bool, error)
+
+	// MarkIndexComplete updates the state of the index to complete and records the upload produced
+	// by the indexer and its output.
+	MarkIndexComplete(ctx context.Context, id, uploadID int, log string) error
+
+	// MarkIndexErrored updates the state of the index to errored and records the failure summary
+	// and the output of the indexer.
+	MarkIndexErrored(ctx context.Context, id int, failureSummary, log string) error
+
+	// ResetStalledIndexes moves all indexes processing for more than `StalledIndexMaxAge` back to the queued
+	// state. This method returns a list of updated index identifiers.
+	ResetStalledIndexes(ctx context.Context, now time.Time) ([]int, error)
+
 	// GetDumpByID returns a dump by its identifier and boolean flag indicating its existence.
 	GetDumpByID(ctx context.Context, id int) (Dump, bool, error)
 
 	// FindClosestDumps returns the set of dumps that can most accurately answer queries for the given repository, commit, and file.
 	FindClosestDumps(ctx context.Context, repositoryID int, commit, file string) ([]Dump, error)
 
+	// GetDumpsByRepo returns all dumps of the given repository, from the most to the least recently uploaded.
+	GetDumpsByRepo(ctx context.Context, repositoryID int) ([]Dump, error)
+
+	// GetDumpRepositoryIDs returns the identifiers of the repositories with at least one dump.
+	GetDumpRepositoryIDs(ctx context.Context) ([]int, error)
+
+	// GetOldestDumps returns at most limit dumps that are not currently visible at the tip of their repository's
+	// default branch, from the least to the most recently uploaded, skipping the first offset such dumps.
+	GetOldestDumps(ctx context.Context, limit, offset int) ([]Dump, error)
+
 	// DeleteOldestDump deletes the oldest dump that is not currently visible at the tip of its repository's default branch.
 	// This method returns the deleted dump's identifier and a flag indicating its (previous) existence.
 	DeleteOldestDump(ctx context.Context) (int, bool, error)
@@ -82,16 +128,27 @@ type DB interface {
 	UpdatePackages(ctx context.Context, packages []types.Package) error
 
 	// SameRepoPager returns a ReferencePager for dumps that belong to the given repository and commit and reference the package with the
-	// given scheme, name, and version.
-	SameRepoPager(ctx context.Context, repositoryID int, commit, scheme, name, version string, limit int) (int, ReferencePager, error)
+	// given scheme, name, and version. Dumps whose identifiers are indexed are only returned if they reference the given identifier. The
+	// references of other dumps must be tested against their bloom filter.
+	SameRepoPager(ctx context.Context, repositoryID int, commit, scheme, name, version, identifier string, limit int) (int, ReferencePager, error)
 
-	// UpdatePackageReferences bulk inserts package reference data.
+	// UpdatePackageReferences bulk inserts package reference data and indexes the identifiers of each reference.
 	UpdatePackageReferences(ctx context.Context, packageReferences []types.PackageReference) error
 
 	// PackageReferencePager returns a ReferencePager for dumps that belong to a remote repository (distinct from the given repository id)
 	// and reference the package with the given scheme, name, and version. All resulting dumps are visible at the tip of their repository's
-	// default branch.
-	PackageReferencePager(ctx context.Context, scheme, name, version string, repositoryID, limit int) (int, ReferencePager, error)
+	// default branch. Dumps whose identifiers are indexed are only returned if they reference the given identifier. The references of other
+	// dumps must be tested against their bloom filter.
+	PackageReferencePager(ctx context.Context, scheme, name, version, identifier string, repositoryID, limit int) (int, ReferencePager, error)
+
+	// UnindexedReferenceDumpIDs returns the identifiers, in ascending order, of at most limit dumps with an identifier
+	// greater than afterID and with package references whose identifiers have not been indexed. These references were
+	// inserted before identifiers were indexed on upload.
+	UnindexedReferenceDumpIDs(ctx context.Context, afterID, limit int) ([]int, error)
+
+	// IndexReferenceIdentifiers indexes the given identifiers referenced by the given dump and marks its package
+	// references as indexed.
+	IndexReferenceIdentifiers(ctx context.Context, dumpID int, identifiers []types.ReferenceIdentifier) error
 
 	// UpdateCommits upserts commits/parent-commit relations for the given repository ID.
 	UpdateCommits(ctx context.Context, repositoryID int, commits map[string][]string) error
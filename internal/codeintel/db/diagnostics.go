@@ -0,0 +1,54 @@
+package db
+
+import (
+	"context"
+
+	"github.com/keegancsmith/sqlf"
+)
+
+// UploadDiagnostic is a problem found in an upload while it was processed.
+type UploadDiagnostic struct {
+	Severity  string `json:"severity"`
+	Code      string `json:"code"`
+	Message   string `json:"message"`
+	ElementID string `json:"elementId"`
+	Line      int    `json:"line"`
+}
+
+// GetDiagnostics returns the diagnostics of the given upload in the order in which they were found.
+func (db *dbImpl) GetDiagnostics(ctx context.Context, uploadID int) ([]UploadDiagnostic, error) {
+	return scanDiagnostics(db.query(ctx, sqlf.Sprintf(`
+		SELECT severity, code, message, element_id, line
+		FROM lsif_upload_diagnostics
+		WHERE upload_id = %s
+		ORDER BY id
+	`, uploadID)))
+}
+
+// UpdateDiagnostics replaces the diagnostics of the given upload.
+func (db *dbImpl) UpdateDiagnostics(ctx context.Context, uploadID int, diagnostics []UploadDiagnostic) (err error) {
+	tx, started, err := db.transact(ctx)
+	if err != nil {
+		return err
+	}
+	if started {
+		defer func() { err = tx.Done(err) }()
+	}
+
+	if err := tx.exec(ctx, sqlf.Sprintf(`DELETE FROM lsif_upload_diagnostics WHERE upload_id = %s`, uploadID)); err != nil {
+		return err
+	}
+	if len(diagnostics) == 0 {
+		return nil
+	}
+
+	var values []*sqlf.Query
+	for _, d := range diagnostics {
+		values = append(values, sqlf.Sprintf("(%s, %s, %s, %s, %s, %s)", uploadID, d.Severity, d.Code, d.Message, d.ElementID, d.Line))
+	}
+
+	return tx.exec(ctx, sqlf.Sprintf(`
+		INSERT INTO lsif_upload_diagnostics (upload_id, severity, code, message, element_id, line)
+		VALUES %s
+	`, sqlf.Join(values, ",")))
+}
@@ -0,0 +1,57 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/sourcegraph/sourcegraph/internal/db/dbconn"
+	"github.com/sourcegraph/sourcegraph/internal/db/dbtesting"
+)
+
+func TestUpdateDiagnostics(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	dbtesting.SetupGlobalTestDB(t)
+	db := &dbImpl{db: dbconn.Global}
+
+	insertUploads(t, dbconn.Global,
+		Upload{ID: 1},
+		Upload{ID: 2},
+	)
+
+	diagnostics := []UploadDiagnostic{
+		{Severity: "error", Code: "dangling-reference", Message: "unknown reference to 5", ElementID: "4", Line: 4},
+		{Severity: "warning", Code: "invalid-range", Message: "range ends before it starts", ElementID: "2", Line: 2},
+	}
+	if err := db.UpdateDiagnostics(context.Background(), 1, diagnostics); err != nil {
+		t.Fatalf("unexpected error updating diagnostics: %s", err)
+	}
+	if err := db.UpdateDiagnostics(context.Background(), 2, diagnostics[:1]); err != nil {
+		t.Fatalf("unexpected error updating diagnostics: %s", err)
+	}
+
+	if have, err := db.GetDiagnostics(context.Background(), 1); err != nil {
+		t.Fatalf("unexpected error getting diagnostics: %s", err)
+	} else if diff := cmp.Diff(diagnostics, have); diff != "" {
+		t.Errorf("unexpected diagnostics (-want +got):\n%s", diff)
+	}
+
+	// Replace existing diagnostics
+	if err := db.UpdateDiagnostics(context.Background(), 1, nil); err != nil {
+		t.Fatalf("unexpected error updating diagnostics: %s", err)
+	}
+
+	if have, err := db.GetDiagnostics(context.Background(), 1); err != nil {
+		t.Fatalf("unexpected error getting diagnostics: %s", err)
+	} else if len(have) != 0 {
+		t.Errorf("unexpected diagnostics. want=%d have=%d", 0, len(have))
+	}
+
+	if have, err := db.GetDiagnostics(context.Background(), 2); err != nil {
+		t.Fatalf("unexpected error getting diagnostics: %s", err)
+	} else if diff := cmp.Diff(diagnostics[:1], have); diff != "" {
+		t.Errorf("unexpected diagnostics (-want +got):\n%s", diff)
+	}
+}
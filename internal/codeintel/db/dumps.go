@@ -111,6 +111,56 @@ func deduplicateDumps(allDumps []Dump) (dumps []Dump) {
 	return dumps
 }
 
+// GetDumpsByRepo returns all dumps of the given repository, from the most to the least recently uploaded.
+func (db *dbImpl) GetDumpsByRepo(ctx context.Context, repositoryID int) ([]Dump, error) {
+	return scanDumps(db.query(ctx, sqlf.Sprintf(`
+		SELECT
+			d.id,
+			d.commit,
+			d.root,
+			d.visible_at_tip,
+			d.uploaded_at,
+			d.state,
+			d.failure_summary,
+			d.failure_stacktrace,
+			d.started_at,
+			d.finished_at,
+			d.tracing_context,
+			d.repository_id,
+			d.indexer
+		FROM lsif_dumps d WHERE repository_id = %s
+		ORDER BY uploaded_at DESC
+	`, repositoryID)))
+}
+
+// GetDumpRepositoryIDs returns the identifiers of the repositories with at least one dump.
+func (db *dbImpl) GetDumpRepositoryIDs(ctx context.Context) ([]int, error) {
+	return scanInts(db.query(ctx, sqlf.Sprintf(`SELECT DISTINCT repository_id FROM lsif_dumps ORDER BY repository_id`)))
+}
+
+// GetOldestDumps returns at most limit dumps that are not currently visible at the tip of their repository's
+// default branch, from the least to the most recently uploaded, skipping the first offset such dumps.
+func (db *dbImpl) GetOldestDumps(ctx context.Context, limit, offset int) ([]Dump, error) {
+	return scanDumps(db.query(ctx, sqlf.Sprintf(`
+		SELECT
+			d.id,
+			d.commit,
+			d.root,
+			d.visible_at_tip,
+			d.uploaded_at,
+			d.state,
+			d.failure_summary,
+			d.failure_stacktrace,
+			d.started_at,
+			d.finished_at,
+			d.tracing_context,
+			d.repository_id,
+			d.indexer
+		FROM lsif_dumps d WHERE visible_at_tip = false
+		ORDER BY uploaded_at, id LIMIT %d OFFSET %d
+	`, limit, offset)))
+}
+
 // DeleteOldestDump deletes the oldest dump that is not currently visible at the tip of its repository's default branch.
 // This method returns the deleted dump's identifier and a flag indicating its (previous) existence.
 func (db *dbImpl) DeleteOldestDump(ctx context.Context) (int, bool, error) {
@@ -338,6 +338,97 @@ func TestDeleteOldestDump(t *testing.T) {
 	}
 }
 
+func TestGetDumpsByRepo(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	dbtesting.SetupGlobalTestDB(t)
+	db := &dbImpl{db: dbconn.Global}
+
+	t1 := time.Unix(1587396557, 0).UTC()
+	t2 := t1.Add(time.Minute)
+	t3 := t1.Add(time.Minute * 2)
+
+	insertUploads(t, dbconn.Global,
+		Upload{ID: 1, UploadedAt: t1},
+		Upload{ID: 2, UploadedAt: t3},
+		Upload{ID: 3, UploadedAt: t2, State: "queued"},
+		Upload{ID: 4, UploadedAt: t2},
+		Upload{ID: 5, UploadedAt: t2, RepositoryID: 51},
+	)
+
+	dumps, err := db.GetDumpsByRepo(context.Background(), 50)
+	if err != nil {
+		t.Fatalf("unexpected error getting dumps: %s", err)
+	}
+
+	var ids []int
+	for _, dump := range dumps {
+		ids = append(ids, dump.ID)
+	}
+	if diff := cmp.Diff([]int{2, 4, 1}, ids); diff != "" {
+		t.Errorf("unexpected dump ids (-want +got):\n%s", diff)
+	}
+}
+
+func TestGetDumpRepositoryIDs(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	dbtesting.SetupGlobalTestDB(t)
+	db := &dbImpl{db: dbconn.Global}
+
+	insertUploads(t, dbconn.Global,
+		Upload{ID: 1, RepositoryID: 52},
+		Upload{ID: 2, RepositoryID: 50},
+		Upload{ID: 3, RepositoryID: 52},
+		Upload{ID: 4, RepositoryID: 51, State: "errored"},
+	)
+
+	repositoryIDs, err := db.GetDumpRepositoryIDs(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error getting repository ids: %s", err)
+	}
+	if diff := cmp.Diff([]int{50, 52}, repositoryIDs); diff != "" {
+		t.Errorf("unexpected repository ids (-want +got):\n%s", diff)
+	}
+}
+
+func TestGetOldestDumps(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	dbtesting.SetupGlobalTestDB(t)
+	db := &dbImpl{db: dbconn.Global}
+
+	t1 := time.Unix(1587396557, 0).UTC()
+	t2 := t1.Add(time.Minute)
+	t3 := t1.Add(time.Minute * 2)
+	t4 := t1.Add(time.Minute * 3)
+
+	insertUploads(t, dbconn.Global,
+		Upload{ID: 1, UploadedAt: t4},
+		Upload{ID: 2, UploadedAt: t1, VisibleAtTip: true},
+		Upload{ID: 3, UploadedAt: t2},
+		Upload{ID: 4, UploadedAt: t3},
+	)
+
+	for offset, expected := range [][]int{{3, 4}, {4, 1}} {
+		dumps, err := db.GetOldestDumps(context.Background(), 2, offset)
+		if err != nil {
+			t.Fatalf("unexpected error getting dumps: %s", err)
+		}
+
+		var ids []int
+		for _, dump := range dumps {
+			ids = append(ids, dump.ID)
+		}
+		if diff := cmp.Diff(expected, ids); diff != "" {
+			t.Errorf("unexpected dump ids at offset %d (-want +got):\n%s", offset, diff)
+		}
+	}
+}
+
 type FindClosestDumpsTestCase struct {
 	commit   string
 	file     string
@@ -0,0 +1,182 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"github.com/keegancsmith/sqlf"
+)
+
+// Index is a subset of the lsif_indexes table and stores the jobs which run an indexer
+// against a repository at a particular commit to produce an upload.
+type Index struct {
+	ID             int        `json:"id"`
+	Commit         string     `json:"commit"`
+	RepositoryID   int        `json:"repositoryId"`
+	Indexer        string     `json:"indexer"`
+	State          string     `json:"state"`
+	QueuedAt       time.Time  `json:"queuedAt"`
+	StartedAt      *time.Time `json:"startedAt"`
+	FinishedAt     *time.Time `json:"finishedAt"`
+	FailureSummary *string    `json:"failureSummary"`
+	Log            *string    `json:"log"`
+	UploadID       *int       `json:"uploadId"`
+}
+
+// GetIndexByID returns an index by its identifier and boolean flag indicating its existence.
+func (db *dbImpl) GetIndexByID(ctx context.Context, id int) (Index, bool, error) {
+	return scanFirstIndex(db.query(ctx, sqlf.Sprintf(`
+		SELECT
+			i.id,
+			i.commit,
+			i.repository_id,
+			i.indexer,
+			i.state,
+			i.queued_at,
+			i.started_at,
+			i.finished_at,
+			i.failure_summary,
+			i.log,
+			i.upload_id
+		FROM lsif_indexes i
+		WHERE i.id = %s
+	`, id)))
+}
+
+// GetIndexesByRepo returns a list of indexes for a particular repo and the total count of records matching the given conditions.
+func (db *dbImpl) GetIndexesByRepo(ctx context.Context, repositoryID int, state string, limit, offset int) (_ []Index, _ int, err error) {
+	tx, started, err := db.transact(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	if started {
+		defer func() { err = tx.Done(err) }()
+	}
+
+	conds := []*sqlf.Query{
+		sqlf.Sprintf("i.repository_id = %s", repositoryID),
+	}
+	if state != "" {
+		conds = append(conds, sqlf.Sprintf("i.state = %s", state))
+	}
+
+	count, _, err := scanFirstInt(tx.query(
+		ctx,
+		sqlf.Sprintf(`SELECT COUNT(1) FROM lsif_indexes i WHERE %s`, sqlf.Join(conds, " AND ")),
+	))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	indexes, err := scanIndexes(tx.query(
+		ctx,
+		sqlf.Sprintf(`
+			SELECT
+				i.id,
+				i.commit,
+				i.repository_id,
+				i.indexer,
+				i.state,
+				i.queued_at,
+				i.started_at,
+				i.finished_at,
+				i.failure_summary,
+				i.log,
+				i.upload_id
+			FROM lsif_indexes i
+			WHERE %s ORDER BY queued_at DESC LIMIT %d OFFSET %d
+		`, sqlf.Join(conds, " AND "), limit, offset),
+	))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return indexes, count, nil
+}
+
+// EnqueueIndex inserts a new index with a "queued" state and returns its identifier and a flag
+// indicating its insertion. An index is not inserted if the repository already has an index or a
+// non-errored upload for the same commit and indexer.
+func (db *dbImpl) EnqueueIndex(ctx context.Context, commit string, repositoryID int, indexerName string) (int, bool, error) {
+	return scanFirstInt(db.query(
+		ctx,
+		sqlf.Sprintf(`
+			INSERT INTO lsif_indexes (commit, repository_id, indexer)
+			SELECT %s, %s, %s
+			WHERE NOT EXISTS (
+				SELECT 1 FROM lsif_uploads
+				WHERE repository_id = %s AND commit = %s AND indexer = %s AND state != 'errored'
+			)
+			ON CONFLICT DO NOTHING
+			RETURNING id
+		`, commit, repositoryID, indexerName, repositoryID, commit, indexerName),
+	))
+}
+
+// DequeueIndex selects the oldest queued index and marks it as processing. If there is no such
+// index, a zero-value index is returned along with a false-valued flag. Unlike uploads, the index
+// row is not locked while the indexer runs, as indexers can run for a long time. Instead, the
+// worker must invoke either MarkIndexComplete or MarkIndexErrored before StalledIndexMaxAge elapses.
+func (db *dbImpl) DequeueIndex(ctx context.Context) (Index, bool, error) {
+	return scanFirstIndex(db.query(ctx, sqlf.Sprintf(`
+		UPDATE lsif_indexes i SET state = 'processing', started_at = now() WHERE id = (
+			SELECT id FROM lsif_indexes
+			WHERE state = 'queued'
+			ORDER BY queued_at
+			FOR UPDATE SKIP LOCKED LIMIT 1
+		)
+		RETURNING
+			i.id,
+			i.commit,
+			i.repository_id,
+			i.indexer,
+			i.state,
+			i.queued_at,
+			i.started_at,
+			i.finished_at,
+			i.failure_summary,
+			i.log,
+			i.upload_id
+	`)))
+}
+
+// MarkIndexComplete updates the state of the index to complete and records the upload produced
+// by the indexer and its output.
+func (db *dbImpl) MarkIndexComplete(ctx context.Context, id, uploadID int, log string) error {
+	return db.exec(ctx, sqlf.Sprintf(`
+		UPDATE lsif_indexes
+		SET state = 'completed', finished_at = now(), upload_id = %s, log = %s
+		WHERE id = %s
+	`, uploadID, log, id))
+}
+
+// MarkIndexErrored updates the state of the index to errored and records the failure summary
+// and the output of the indexer.
+func (db *dbImpl) MarkIndexErrored(ctx context.Context, id int, failureSummary, log string) error {
+	return db.exec(ctx, sqlf.Sprintf(`
+		UPDATE lsif_indexes
+		SET state = 'errored', finished_at = now(), failure_summary = %s, log = %s
+		WHERE id = %s
+	`, failureSummary, log, id))
+}
+
+// StalledIndexMaxAge is the maximum allowable duration an index can be in the processing state.
+// Workers abort indexers well before this duration, so an index that is still processing after
+// this duration likely indicates that the worker that dequeued the index has died.
+const StalledIndexMaxAge = time.Hour
+
+// ResetStalledIndexes moves all indexes processing for more than `StalledIndexMaxAge` back to the queued
+// state. This method returns a list of updated index identifiers.
+func (db *dbImpl) ResetStalledIndexes(ctx context.Context, now time.Time) ([]int, error) {
+	return scanInts(db.query(
+		ctx,
+		sqlf.Sprintf(`
+			UPDATE lsif_indexes i SET state = 'queued', started_at = null WHERE id = ANY(
+				SELECT id FROM lsif_indexes
+				WHERE state = 'processing' AND %s - started_at > (%s * interval '1 second')
+				FOR UPDATE SKIP LOCKED
+			)
+			RETURNING i.id
+		`, now.UTC(), StalledIndexMaxAge/time.Second),
+	))
+}
@@ -26,18 +26,42 @@ type MockDB struct {
 	// DequeueFunc is an instance of a mock function object controlling the
 	// behavior of the method Dequeue.
 	DequeueFunc *DBDequeueFunc
+	// DequeueIndexFunc is an instance of a mock function object controlling
+	// the behavior of the method DequeueIndex.
+	DequeueIndexFunc *DBDequeueIndexFunc
 	// DoneFunc is an instance of a mock function object controlling the
 	// behavior of the method Done.
 	DoneFunc *DBDoneFunc
 	// EnqueueFunc is an instance of a mock function object controlling the
 	// behavior of the method Enqueue.
 	EnqueueFunc *DBEnqueueFunc
+	// EnqueueIndexFunc is an instance of a mock function object controlling
+	// the behavior of the method EnqueueIndex.
+	EnqueueIndexFunc *DBEnqueueIndexFunc
 	// FindClosestDumpsFunc is an instance of a mock function object
 	// controlling the behavior of the method FindClosestDumps.
 	FindClosestDumpsFunc *DBFindClosestDumpsFunc
+	// GetDiagnosticsFunc is an instance of a mock function object
+	// controlling the behavior of the method GetDiagnostics.
+	GetDiagnosticsFunc *DBGetDiagnosticsFunc
 	// GetDumpByIDFunc is an instance of a mock function object controlling
 	// the behavior of the method GetDumpByID.
 	GetDumpByIDFunc *DBGetDumpByIDFunc
+	// GetDumpRepositoryIDsFunc is an instance of a mock function object
+	// controlling the behavior of the method GetDumpRepositoryIDs.
+	GetDumpRepositoryIDsFunc *DBGetDumpRepositoryIDsFunc
+	// GetDumpsByRepoFunc is an instance of a mock function object
+	// controlling the behavior of the method GetDumpsByRepo.
+	GetDumpsByRepoFunc *DBGetDumpsByRepoFunc
+	// GetIndexByIDFunc is an instance of a mock function object controlling
+	// the behavior of the method GetIndexByID.
+	GetIndexByIDFunc *DBGetIndexByIDFunc
+	// GetIndexesByRepoFunc is an instance of a mock function object
+	// controlling the behavior of the method GetIndexesByRepo.
+	GetIndexesByRepoFunc *DBGetIndexesByRepoFunc
+	// GetOldestDumpsFunc is an instance of a mock function object
+	// controlling the behavior of the method GetOldestDumps.
+	GetOldestDumpsFunc *DBGetOldestDumpsFunc
 	// GetPackageFunc is an instance of a mock function object controlling
 	// the behavior of the method GetPackage.
 	GetPackageFunc *DBGetPackageFunc
@@ -50,6 +74,16 @@ type MockDB struct {
 	// GetUploadsByRepoFunc is an instance of a mock function object
 	// controlling the behavior of the method GetUploadsByRepo.
 	GetUploadsByRepoFunc *DBGetUploadsByRepoFunc
+	// IndexReferenceIdentifiersFunc is an instance of a mock function
+	// object controlling the behavior of the method
+	// IndexReferenceIdentifiers.
+	IndexReferenceIdentifiersFunc *DBIndexReferenceIdentifiersFunc
+	// MarkIndexCompleteFunc is an instance of a mock function object
+	// controlling the behavior of the method MarkIndexComplete.
+	MarkIndexCompleteFunc *DBMarkIndexCompleteFunc
+	// MarkIndexErroredFunc is an instance of a mock function object
+	// controlling the behavior of the method MarkIndexErrored.
+	MarkIndexErroredFunc *DBMarkIndexErroredFunc
 	// PackageReferencePagerFunc is an instance of a mock function object
 	// controlling the behavior of the method PackageReferencePager.
 	PackageReferencePagerFunc *DBPackageReferencePagerFunc
@@ -59,15 +93,25 @@ type MockDB struct {
 	// ResetStalledFunc is an instance of a mock function object controlling
 	// the behavior of the method ResetStalled.
 	ResetStalledFunc *DBResetStalledFunc
+	// ResetStalledIndexesFunc is an instance of a mock function object
+	// controlling the behavior of the method ResetStalledIndexes.
+	ResetStalledIndexesFunc *DBResetStalledIndexesFunc
 	// SameRepoPagerFunc is an instance of a mock function object
 	// controlling the behavior of the method SameRepoPager.
 	SameRepoPagerFunc *DBSameRepoPagerFunc
 	// TransactFunc is an instance of a mock function object controlling the
 	// behavior of the method Transact.
 	TransactFunc *DBTransactFunc
+	// UnindexedReferenceDumpIDsFunc is an instance of a mock function
+	// object controlling the behavior of the method
+	// UnindexedReferenceDumpIDs.
+	UnindexedReferenceDumpIDsFunc *DBUnindexedReferenceDumpIDsFunc
 	// UpdateCommitsFunc is an instance of a mock function object
 	// controlling the behavior of the method UpdateCommits.
 	UpdateCommitsFunc *DBUpdateCommitsFunc
+	// UpdateDiagnosticsFunc is an instance of a mock function object
+	// controlling the behavior of the method UpdateDiagnostics.
+	UpdateDiagnosticsFunc *DBUpdateDiagnosticsFunc
 	// UpdateDumpsVisibleFromTipFunc is an instance of a mock function
 	// object controlling the behavior of the method
 	// UpdateDumpsVisibleFromTip.
@@ -104,6 +148,11 @@ func NewMockDB() *MockDB {
 				return db.Upload{}, nil, false, nil
 			},
 		},
+		DequeueIndexFunc: &DBDequeueIndexFunc{
+			defaultHook: func(context.Context) (db.Index, bool, error) {
+				return db.Index{}, false, nil
+			},
+		},
 		DoneFunc: &DBDoneFunc{
 			defaultHook: func(error) error {
 				return nil
@@ -114,16 +163,51 @@ func NewMockDB() *MockDB {
 				return 0, nil
 			},
 		},
+		EnqueueIndexFunc: &DBEnqueueIndexFunc{
+			defaultHook: func(context.Context, string, int, string) (int, bool, error) {
+				return 0, false, nil
+			},
+		},
 		FindClosestDumpsFunc: &DBFindClosestDumpsFunc{
 			defaultHook: func(context.Context, int, string, string) ([]db.Dump, error) {
 				return nil, nil
 			},
 		},
+		GetDiagnosticsFunc: &DBGetDiagnosticsFunc{
+			defaultHook: func(context.Context, int) ([]db.UploadDiagnostic, error) {
+				return nil, nil
+			},
+		},
 		GetDumpByIDFunc: &DBGetDumpByIDFunc{
 			defaultHook: func(context.Context, int) (db.Dump, bool, error) {
 				return db.Dump{}, false, nil
 			},
 		},
+		GetDumpRepositoryIDsFunc: &DBGetDumpRepositoryIDsFunc{
+			defaultHook: func(context.Context) ([]int, error) {
+				return nil, nil
+			},
+		},
+		GetDumpsByRepoFunc: &DBGetDumpsByRepoFunc{
+			defaultHook: func(context.Context, int) ([]db.Dump, error) {
+				return nil, nil
+			},
+		},
+		GetIndexByIDFunc: &DBGetIndexByIDFunc{
+			defaultHook: func(context.Context, int) (db.Index, bool, error) {
+				return db.Index{}, false, nil
+			},
+		},
+		GetIndexesByRepoFunc: &DBGetIndexesByRepoFunc{
+			defaultHook: func(context.Context, int, string, int, int) ([]db.Index, int, error) {
+				return nil, 0, nil
+			},
+		},
+		GetOldestDumpsFunc: &DBGetOldestDumpsFunc{
+			defaultHook: func(context.Context, int, int) ([]db.Dump, error) {
+				return nil, nil
+			},
+		},
 		GetPackageFunc: &DBGetPackageFunc{
 			defaultHook: func(context.Context, string, string, string) (db.Dump, bool, error) {
 				return db.Dump{}, false, nil
@@ -144,8 +228,23 @@ func NewMockDB() *MockDB {
 				return nil, 0, nil
 			},
 		},
+		IndexReferenceIdentifiersFunc: &DBIndexReferenceIdentifiersFunc{
+			defaultHook: func(context.Context, int, []types.ReferenceIdentifier) error {
+				return nil
+			},
+		},
+		MarkIndexCompleteFunc: &DBMarkIndexCompleteFunc{
+			defaultHook: func(context.Context, int, int, string) error {
+				return nil
+			},
+		},
+		MarkIndexErroredFunc: &DBMarkIndexErroredFunc{
+			defaultHook: func(context.Context, int, string, string) error {
+				return nil
+			},
+		},
 		PackageReferencePagerFunc: &DBPackageReferencePagerFunc{
-			defaultHook: func(context.Context, string, string, string, int, int) (int, db.ReferencePager, error) {
+			defaultHook: func(context.Context, string, string, string, string, int, int) (int, db.ReferencePager, error) {
 				return 0, nil, nil
 			},
 		},
@@ -159,8 +258,13 @@ func NewMockDB() *MockDB {
 				return nil, nil
 			},
 		},
+		ResetStalledIndexesFunc: &DBResetStalledIndexesFunc{
+			defaultHook: func(context.Context, time.Time) ([]int, error) {
+				return nil, nil
+			},
+		},
 		SameRepoPagerFunc: &DBSameRepoPagerFunc{
-			defaultHook: func(context.Context, int, string, string, string, string, int) (int, db.ReferencePager, error) {
+			defaultHook: func(context.Context, int, string, string, string, string, string, int) (int, db.ReferencePager, error) {
 				return 0, nil, nil
 			},
 		},
@@ -169,11 +273,21 @@ func NewMockDB() *MockDB {
 				return nil, nil
 			},
 		},
+		UnindexedReferenceDumpIDsFunc: &DBUnindexedReferenceDumpIDsFunc{
+			defaultHook: func(context.Context, int, int) ([]int, error) {
+				return nil, nil
+			},
+		},
 		UpdateCommitsFunc: &DBUpdateCommitsFunc{
 			defaultHook: func(context.Context, int, map[string][]string) error {
 				return nil
 			},
 		},
+		UpdateDiagnosticsFunc: &DBUpdateDiagnosticsFunc{
+			defaultHook: func(context.Context, int, []db.UploadDiagnostic) error {
+				return nil
+			},
+		},
 		UpdateDumpsVisibleFromTipFunc: &DBUpdateDumpsVisibleFromTipFunc{
 			defaultHook: func(context.Context, int, string) error {
 				return nil
@@ -208,18 +322,42 @@ func NewMockDBFrom(i db.DB) *MockDB {
 		DequeueFunc: &DBDequeueFunc{
 			defaultHook: i.Dequeue,
 		},
+		DequeueIndexFunc: &DBDequeueIndexFunc{
+			defaultHook: i.DequeueIndex,
+		},
 		DoneFunc: &DBDoneFunc{
 			defaultHook: i.Done,
 		},
 		EnqueueFunc: &DBEnqueueFunc{
 			defaultHook: i.Enqueue,
 		},
+		EnqueueIndexFunc: &DBEnqueueIndexFunc{
+			defaultHook: i.EnqueueIndex,
+		},
 		FindClosestDumpsFunc: &DBFindClosestDumpsFunc{
 			defaultHook: i.FindClosestDumps,
 		},
+		GetDiagnosticsFunc: &DBGetDiagnosticsFunc{
+			defaultHook: i.GetDiagnostics,
+		},
 		GetDumpByIDFunc: &DBGetDumpByIDFunc{
 			defaultHook: i.GetDumpByID,
 		},
+		GetDumpRepositoryIDsFunc: &DBGetDumpRepositoryIDsFunc{
+			defaultHook: i.GetDumpRepositoryIDs,
+		},
+		GetDumpsByRepoFunc: &DBGetDumpsByRepoFunc{
+			defaultHook: i.GetDumpsByRepo,
+		},
+		GetIndexByIDFunc: &DBGetIndexByIDFunc{
+			defaultHook: i.GetIndexByID,
+		},
+		GetIndexesByRepoFunc: &DBGetIndexesByRepoFunc{
+			defaultHook: i.GetIndexesByRepo,
+		},
+		GetOldestDumpsFunc: &DBGetOldestDumpsFunc{
+			defaultHook: i.GetOldestDumps,
+		},
 		GetPackageFunc: &DBGetPackageFunc{
 			defaultHook: i.GetPackage,
 		},
@@ -232,6 +370,15 @@ func NewMockDBFrom(i db.DB) *MockDB {
 		GetUploadsByRepoFunc: &DBGetUploadsByRepoFunc{
 			defaultHook: i.GetUploadsByRepo,
 		},
+		IndexReferenceIdentifiersFunc: &DBIndexReferenceIdentifiersFunc{
+			defaultHook: i.IndexReferenceIdentifiers,
+		},
+		MarkIndexCompleteFunc: &DBMarkIndexCompleteFunc{
+			defaultHook: i.MarkIndexComplete,
+		},
+		MarkIndexErroredFunc: &DBMarkIndexErroredFunc{
+			defaultHook: i.MarkIndexErrored,
+		},
 		PackageReferencePagerFunc: &DBPackageReferencePagerFunc{
 			defaultHook: i.PackageReferencePager,
 		},
@@ -241,15 +388,24 @@ func NewMockDBFrom(i db.DB) *MockDB {
 		ResetStalledFunc: &DBResetStalledFunc{
 			defaultHook: i.ResetStalled,
 		},
+		ResetStalledIndexesFunc: &DBResetStalledIndexesFunc{
+			defaultHook: i.ResetStalledIndexes,
+		},
 		SameRepoPagerFunc: &DBSameRepoPagerFunc{
 			defaultHook: i.SameRepoPager,
 		},
 		TransactFunc: &DBTransactFunc{
 			defaultHook: i.Transact,
 		},
+		UnindexedReferenceDumpIDsFunc: &DBUnindexedReferenceDumpIDsFunc{
+			defaultHook: i.UnindexedReferenceDumpIDs,
+		},
 		UpdateCommitsFunc: &DBUpdateCommitsFunc{
 			defaultHook: i.UpdateCommits,
 		},
+		UpdateDiagnosticsFunc: &DBUpdateDiagnosticsFunc{
+			defaultHook: i.UpdateDiagnostics,
+		},
 		UpdateDumpsVisibleFromTipFunc: &DBUpdateDumpsVisibleFromTipFunc{
 			defaultHook: i.UpdateDumpsVisibleFromTip,
 		},
@@ -709,6 +865,114 @@ func (c DBDequeueFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1, c.Result2, c.Result3}
 }
 
+// DBDequeueIndexFunc describes the behavior when the DequeueIndex method of
+// the parent MockDB instance is invoked.
+type DBDequeueIndexFunc struct {
+	defaultHook func(context.Context) (db.Index, bool, error)
+	hooks       []func(context.Context) (db.Index, bool, error)
+	history     []DBDequeueIndexFuncCall
+	mutex       sync.Mutex
+}
+
+// DequeueIndex delegates to the next hook function in the queue and stores
+// the parameter and result values of this invocation.
+func (m *MockDB) DequeueIndex(v0 context.Context) (db.Index, bool, error) {
+	r0, r1, r2 := m.DequeueIndexFunc.nextHook()(v0)
+	m.DequeueIndexFunc.appendCall(DBDequeueIndexFuncCall{v0, r0, r1, r2})
+	return r0, r1, r2
+}
+
+// SetDefaultHook sets function that is called when the DequeueIndex method
+// of the parent MockDB instance is invoked and the hook queue is empty.
+func (f *DBDequeueIndexFunc) SetDefaultHook(hook func(context.Context) (db.Index, bool, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// DequeueIndex method of the parent MockDB instance inovkes the hook at the
+// front of the queue and discards it. After the queue is empty, the default
+// hook function is invoked for any future action.
+func (f *DBDequeueIndexFunc) PushHook(hook func(context.Context) (db.Index, bool, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *DBDequeueIndexFunc) SetDefaultReturn(r0 db.Index, r1 bool, r2 error) {
+	f.SetDefaultHook(func(context.Context) (db.Index, bool, error) {
+		return r0, r1, r2
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *DBDequeueIndexFunc) PushReturn(r0 db.Index, r1 bool, r2 error) {
+	f.PushHook(func(context.Context) (db.Index, bool, error) {
+		return r0, r1, r2
+	})
+}
+
+func (f *DBDequeueIndexFunc) nextHook() func(context.Context) (db.Index, bool, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *DBDequeueIndexFunc) appendCall(r0 DBDequeueIndexFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of DBDequeueIndexFuncCall objects describing
+// the invocations of this function.
+func (f *DBDequeueIndexFunc) History() []DBDequeueIndexFuncCall {
+	f.mutex.Lock()
+	history := make([]DBDequeueIndexFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// DBDequeueIndexFuncCall is an object that describes an invocation of
+// method DequeueIndex on an instance of MockDB.
+type DBDequeueIndexFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 db.Index
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 bool
+	// Result2 is the value of the 3rd result returned from this method
+	// invocation.
+	Result2 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c DBDequeueIndexFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c DBDequeueIndexFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1, c.Result2}
+}
+
 // DBDoneFunc describes the behavior when the Done method of the parent
 // MockDB instance is invoked.
 type DBDoneFunc struct {
@@ -931,6 +1195,123 @@ func (c DBEnqueueFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
 
+// DBEnqueueIndexFunc describes the behavior when the EnqueueIndex method of
+// the parent MockDB instance is invoked.
+type DBEnqueueIndexFunc struct {
+	defaultHook func(context.Context, string, int, string) (int, bool, error)
+	hooks       []func(context.Context, string, int, string) (int, bool, error)
+	history     []DBEnqueueIndexFuncCall
+	mutex       sync.Mutex
+}
+
+// EnqueueIndex delegates to the next hook function in the queue and stores
+// the parameter and result values of this invocation.
+func (m *MockDB) EnqueueIndex(v0 context.Context, v1 string, v2 int, v3 string) (int, bool, error) {
+	r0, r1, r2 := m.EnqueueIndexFunc.nextHook()(v0, v1, v2, v3)
+	m.EnqueueIndexFunc.appendCall(DBEnqueueIndexFuncCall{v0, v1, v2, v3, r0, r1, r2})
+	return r0, r1, r2
+}
+
+// SetDefaultHook sets function that is called when the EnqueueIndex method
+// of the parent MockDB instance is invoked and the hook queue is empty.
+func (f *DBEnqueueIndexFunc) SetDefaultHook(hook func(context.Context, string, int, string) (int, bool, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// EnqueueIndex method of the parent MockDB instance inovkes the hook at the
+// front of the queue and discards it. After the queue is empty, the default
+// hook function is invoked for any future action.
+func (f *DBEnqueueIndexFunc) PushHook(hook func(context.Context, string, int, string) (int, bool, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *DBEnqueueIndexFunc) SetDefaultReturn(r0 int, r1 bool, r2 error) {
+	f.SetDefaultHook(func(context.Context, string, int, string) (int, bool, error) {
+		return r0, r1, r2
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *DBEnqueueIndexFunc) PushReturn(r0 int, r1 bool, r2 error) {
+	f.PushHook(func(context.Context, string, int, string) (int, bool, error) {
+		return r0, r1, r2
+	})
+}
+
+func (f *DBEnqueueIndexFunc) nextHook() func(context.Context, string, int, string) (int, bool, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *DBEnqueueIndexFunc) appendCall(r0 DBEnqueueIndexFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of DBEnqueueIndexFuncCall objects describing
+// the invocations of this function.
+func (f *DBEnqueueIndexFunc) History() []DBEnqueueIndexFuncCall {
+	f.mutex.Lock()
+	history := make([]DBEnqueueIndexFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// DBEnqueueIndexFuncCall is an object that describes an invocation of
+// method EnqueueIndex on an instance of MockDB.
+type DBEnqueueIndexFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 string
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 int
+	// Arg3 is the value of the 4th argument passed to this method
+	// invocation.
+	Arg3 string
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 int
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 bool
+	// Result2 is the value of the 3rd result returned from this method
+	// invocation.
+	Result2 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c DBEnqueueIndexFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c DBEnqueueIndexFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1, c.Result2}
+}
+
 // DBFindClosestDumpsFunc describes the behavior when the FindClosestDumps
 // method of the parent MockDB instance is invoked.
 type DBFindClosestDumpsFunc struct {
@@ -1046,34 +1427,35 @@ func (c DBFindClosestDumpsFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
 
-// DBGetDumpByIDFunc describes the behavior when the GetDumpByID method of
-// the parent MockDB instance is invoked.
-type DBGetDumpByIDFunc struct {
-	defaultHook func(context.Context, int) (db.Dump, bool, error)
-	hooks       []func(context.Context, int) (db.Dump, bool, error)
-	history     []DBGetDumpByIDFuncCall
+// DBGetDiagnosticsFunc describes the behavior when the GetDiagnostics
+// method of the parent MockDB instance is invoked.
+type DBGetDiagnosticsFunc struct {
+	defaultHook func(context.Context, int) ([]db.UploadDiagnostic, error)
+	hooks       []func(context.Context, int) ([]db.UploadDiagnostic, error)
+	history     []DBGetDiagnosticsFuncCall
 	mutex       sync.Mutex
 }
 
-// GetDumpByID delegates to the next hook function in the queue and stores
-// the parameter and result values of this invocation.
-func (m *MockDB) GetDumpByID(v0 context.Context, v1 int) (db.Dump, bool, error) {
-	r0, r1, r2 := m.GetDumpByIDFunc.nextHook()(v0, v1)
-	m.GetDumpByIDFunc.appendCall(DBGetDumpByIDFuncCall{v0, v1, r0, r1, r2})
-	return r0, r1, r2
+// GetDiagnostics delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockDB) GetDiagnostics(v0 context.Context, v1 int) ([]db.UploadDiagnostic, error) {
+	r0, r1 := m.GetDiagnosticsFunc.nextHook()(v0, v1)
+	m.GetDiagnosticsFunc.appendCall(DBGetDiagnosticsFuncCall{v0, v1, r0, r1})
+	return r0, r1
 }
 
-// SetDefaultHook sets function that is called when the GetDumpByID method
-// of the parent MockDB instance is invoked and the hook queue is empty.
-func (f *DBGetDumpByIDFunc) SetDefaultHook(hook func(context.Context, int) (db.Dump, bool, error)) {
+// SetDefaultHook sets function that is called when the GetDiagnostics
+// method of the parent MockDB instance is invoked and the hook queue is
+// empty.
+func (f *DBGetDiagnosticsFunc) SetDefaultHook(hook func(context.Context, int) ([]db.UploadDiagnostic, error)) {
 	f.defaultHook = hook
 }
 
 // PushHook adds a function to the end of hook queue. Each invocation of the
-// GetDumpByID method of the parent MockDB instance inovkes the hook at the
-// front of the queue and discards it. After the queue is empty, the default
-// hook function is invoked for any future action.
-func (f *DBGetDumpByIDFunc) PushHook(hook func(context.Context, int) (db.Dump, bool, error)) {
+// GetDiagnostics method of the parent MockDB instance inovkes the hook at
+// the front of the queue and discards it. After the queue is empty, the
+// default hook function is invoked for any future action.
+func (f *DBGetDiagnosticsFunc) PushHook(hook func(context.Context, int) ([]db.UploadDiagnostic, error)) {
 	f.mutex.Lock()
 	f.hooks = append(f.hooks, hook)
 	f.mutex.Unlock()
@@ -1081,9 +1463,117 @@ func (f *DBGetDumpByIDFunc) PushHook(hook func(context.Context, int) (db.Dump, b
 
 // SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
 // the given values.
-func (f *DBGetDumpByIDFunc) SetDefaultReturn(r0 db.Dump, r1 bool, r2 error) {
-	f.SetDefaultHook(func(context.Context, int) (db.Dump, bool, error) {
-		return r0, r1, r2
+func (f *DBGetDiagnosticsFunc) SetDefaultReturn(r0 []db.UploadDiagnostic, r1 error) {
+	f.SetDefaultHook(func(context.Context, int) ([]db.UploadDiagnostic, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *DBGetDiagnosticsFunc) PushReturn(r0 []db.UploadDiagnostic, r1 error) {
+	f.PushHook(func(context.Context, int) ([]db.UploadDiagnostic, error) {
+		return r0, r1
+	})
+}
+
+func (f *DBGetDiagnosticsFunc) nextHook() func(context.Context, int) ([]db.UploadDiagnostic, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *DBGetDiagnosticsFunc) appendCall(r0 DBGetDiagnosticsFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of DBGetDiagnosticsFuncCall objects describing
+// the invocations of this function.
+func (f *DBGetDiagnosticsFunc) History() []DBGetDiagnosticsFuncCall {
+	f.mutex.Lock()
+	history := make([]DBGetDiagnosticsFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// DBGetDiagnosticsFuncCall is an object that describes an invocation of
+// method GetDiagnostics on an instance of MockDB.
+type DBGetDiagnosticsFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []db.UploadDiagnostic
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c DBGetDiagnosticsFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c DBGetDiagnosticsFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
+// DBGetDumpByIDFunc describes the behavior when the GetDumpByID method of
+// the parent MockDB instance is invoked.
+type DBGetDumpByIDFunc struct {
+	defaultHook func(context.Context, int) (db.Dump, bool, error)
+	hooks       []func(context.Context, int) (db.Dump, bool, error)
+	history     []DBGetDumpByIDFuncCall
+	mutex       sync.Mutex
+}
+
+// GetDumpByID delegates to the next hook function in the queue and stores
+// the parameter and result values of this invocation.
+func (m *MockDB) GetDumpByID(v0 context.Context, v1 int) (db.Dump, bool, error) {
+	r0, r1, r2 := m.GetDumpByIDFunc.nextHook()(v0, v1)
+	m.GetDumpByIDFunc.appendCall(DBGetDumpByIDFuncCall{v0, v1, r0, r1, r2})
+	return r0, r1, r2
+}
+
+// SetDefaultHook sets function that is called when the GetDumpByID method
+// of the parent MockDB instance is invoked and the hook queue is empty.
+func (f *DBGetDumpByIDFunc) SetDefaultHook(hook func(context.Context, int) (db.Dump, bool, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// GetDumpByID method of the parent MockDB instance inovkes the hook at the
+// front of the queue and discards it. After the queue is empty, the default
+// hook function is invoked for any future action.
+func (f *DBGetDumpByIDFunc) PushHook(hook func(context.Context, int) (db.Dump, bool, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *DBGetDumpByIDFunc) SetDefaultReturn(r0 db.Dump, r1 bool, r2 error) {
+	f.SetDefaultHook(func(context.Context, int) (db.Dump, bool, error) {
+		return r0, r1, r2
 	})
 }
 
@@ -1153,8 +1643,567 @@ func (c DBGetDumpByIDFuncCall) Args() []interface{} {
 
 // Results returns an interface slice containing the results of this
 // invocation.
-func (c DBGetDumpByIDFuncCall) Results() []interface{} {
-	return []interface{}{c.Result0, c.Result1, c.Result2}
+func (c DBGetDumpByIDFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1, c.Result2}
+}
+
+// DBGetDumpRepositoryIDsFunc describes the behavior when the
+// GetDumpRepositoryIDs method of the parent MockDB instance is invoked.
+type DBGetDumpRepositoryIDsFunc struct {
+	defaultHook func(context.Context) ([]int, error)
+	hooks       []func(context.Context) ([]int, error)
+	history     []DBGetDumpRepositoryIDsFuncCall
+	mutex       sync.Mutex
+}
+
+// GetDumpRepositoryIDs delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockDB) GetDumpRepositoryIDs(v0 context.Context) ([]int, error) {
+	r0, r1 := m.GetDumpRepositoryIDsFunc.nextHook()(v0)
+	m.GetDumpRepositoryIDsFunc.appendCall(DBGetDumpRepositoryIDsFuncCall{v0, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the GetDumpRepositoryIDs
+// method of the parent MockDB instance is invoked and the hook queue is
+// empty.
+func (f *DBGetDumpRepositoryIDsFunc) SetDefaultHook(hook func(context.Context) ([]int, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// GetDumpRepositoryIDs method of the parent MockDB instance inovkes the
+// hook at the front of the queue and discards it. After the queue is empty,
+// the default hook function is invoked for any future action.
+func (f *DBGetDumpRepositoryIDsFunc) PushHook(hook func(context.Context) ([]int, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *DBGetDumpRepositoryIDsFunc) SetDefaultReturn(r0 []int, r1 error) {
+	f.SetDefaultHook(func(context.Context) ([]int, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *DBGetDumpRepositoryIDsFunc) PushReturn(r0 []int, r1 error) {
+	f.PushHook(func(context.Context) ([]int, error) {
+		return r0, r1
+	})
+}
+
+func (f *DBGetDumpRepositoryIDsFunc) nextHook() func(context.Context) ([]int, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *DBGetDumpRepositoryIDsFunc) appendCall(r0 DBGetDumpRepositoryIDsFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of DBGetDumpRepositoryIDsFuncCall objects
+// describing the invocations of this function.
+func (f *DBGetDumpRepositoryIDsFunc) History() []DBGetDumpRepositoryIDsFuncCall {
+	f.mutex.Lock()
+	history := make([]DBGetDumpRepositoryIDsFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// DBGetDumpRepositoryIDsFuncCall is an object that describes an invocation
+// of method GetDumpRepositoryIDs on an instance of MockDB.
+type DBGetDumpRepositoryIDsFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []int
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c DBGetDumpRepositoryIDsFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c DBGetDumpRepositoryIDsFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
+// DBGetDumpsByRepoFunc describes the behavior when the GetDumpsByRepo
+// method of the parent MockDB instance is invoked.
+type DBGetDumpsByRepoFunc struct {
+	defaultHook func(context.Context, int) ([]db.Dump, error)
+	hooks       []func(context.Context, int) ([]db.Dump, error)
+	history     []DBGetDumpsByRepoFuncCall
+	mutex       sync.Mutex
+}
+
+// GetDumpsByRepo delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockDB) GetDumpsByRepo(v0 context.Context, v1 int) ([]db.Dump, error) {
+	r0, r1 := m.GetDumpsByRepoFunc.nextHook()(v0, v1)
+	m.GetDumpsByRepoFunc.appendCall(DBGetDumpsByRepoFuncCall{v0, v1, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the GetDumpsByRepo
+// method of the parent MockDB instance is invoked and the hook queue is
+// empty.
+func (f *DBGetDumpsByRepoFunc) SetDefaultHook(hook func(context.Context, int) ([]db.Dump, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// GetDumpsByRepo method of the parent MockDB instance inovkes the hook at
+// the front of the queue and discards it. After the queue is empty, the
+// default hook function is invoked for any future action.
+func (f *DBGetDumpsByRepoFunc) PushHook(hook func(context.Context, int) ([]db.Dump, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *DBGetDumpsByRepoFunc) SetDefaultReturn(r0 []db.Dump, r1 error) {
+	f.SetDefaultHook(func(context.Context, int) ([]db.Dump, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *DBGetDumpsByRepoFunc) PushReturn(r0 []db.Dump, r1 error) {
+	f.PushHook(func(context.Context, int) ([]db.Dump, error) {
+		return r0, r1
+	})
+}
+
+func (f *DBGetDumpsByRepoFunc) nextHook() func(context.Context, int) ([]db.Dump, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *DBGetDumpsByRepoFunc) appendCall(r0 DBGetDumpsByRepoFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of DBGetDumpsByRepoFuncCall objects describing
+// the invocations of this function.
+func (f *DBGetDumpsByRepoFunc) History() []DBGetDumpsByRepoFuncCall {
+	f.mutex.Lock()
+	history := make([]DBGetDumpsByRepoFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// DBGetDumpsByRepoFuncCall is an object that describes an invocation of
+// method GetDumpsByRepo on an instance of MockDB.
+type DBGetDumpsByRepoFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []db.Dump
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c DBGetDumpsByRepoFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c DBGetDumpsByRepoFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
+// DBGetIndexByIDFunc describes the behavior when the GetIndexByID method of
+// the parent MockDB instance is invoked.
+type DBGetIndexByIDFunc struct {
+	defaultHook func(context.Context, int) (db.Index, bool, error)
+	hooks       []func(context.Context, int) (db.Index, bool, error)
+	history     []DBGetIndexByIDFuncCall
+	mutex       sync.Mutex
+}
+
+// GetIndexByID delegates to the next hook function in the queue and stores
+// the parameter and result values of this invocation.
+func (m *MockDB) GetIndexByID(v0 context.Context, v1 int) (db.Index, bool, error) {
+	r0, r1, r2 := m.GetIndexByIDFunc.nextHook()(v0, v1)
+	m.GetIndexByIDFunc.appendCall(DBGetIndexByIDFuncCall{v0, v1, r0, r1, r2})
+	return r0, r1, r2
+}
+
+// SetDefaultHook sets function that is called when the GetIndexByID method
+// of the parent MockDB instance is invoked and the hook queue is empty.
+func (f *DBGetIndexByIDFunc) SetDefaultHook(hook func(context.Context, int) (db.Index, bool, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// GetIndexByID method of the parent MockDB instance inovkes the hook at the
+// front of the queue and discards it. After the queue is empty, the default
+// hook function is invoked for any future action.
+func (f *DBGetIndexByIDFunc) PushHook(hook func(context.Context, int) (db.Index, bool, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *DBGetIndexByIDFunc) SetDefaultReturn(r0 db.Index, r1 bool, r2 error) {
+	f.SetDefaultHook(func(context.Context, int) (db.Index, bool, error) {
+		return r0, r1, r2
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *DBGetIndexByIDFunc) PushReturn(r0 db.Index, r1 bool, r2 error) {
+	f.PushHook(func(context.Context, int) (db.Index, bool, error) {
+		return r0, r1, r2
+	})
+}
+
+func (f *DBGetIndexByIDFunc) nextHook() func(context.Context, int) (db.Index, bool, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *DBGetIndexByIDFunc) appendCall(r0 DBGetIndexByIDFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of DBGetIndexByIDFuncCall objects describing
+// the invocations of this function.
+func (f *DBGetIndexByIDFunc) History() []DBGetIndexByIDFuncCall {
+	f.mutex.Lock()
+	history := make([]DBGetIndexByIDFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// DBGetIndexByIDFuncCall is an object that describes an invocation of
+// method GetIndexByID on an instance of MockDB.
+type DBGetIndexByIDFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 db.Index
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 bool
+	// Result2 is the value of the 3rd result returned from this method
+	// invocation.
+	Result2 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c DBGetIndexByIDFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c DBGetIndexByIDFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1, c.Result2}
+}
+
+// DBGetIndexesByRepoFunc describes the behavior when the GetIndexesByRepo
+// method of the parent MockDB instance is invoked.
+type DBGetIndexesByRepoFunc struct {
+	defaultHook func(context.Context, int, string, int, int) ([]db.Index, int, error)
+	hooks       []func(context.Context, int, string, int, int) ([]db.Index, int, error)
+	history     []DBGetIndexesByRepoFuncCall
+	mutex       sync.Mutex
+}
+
+// GetIndexesByRepo delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockDB) GetIndexesByRepo(v0 context.Context, v1 int, v2 string, v3 int, v4 int) ([]db.Index, int, error) {
+	r0, r1, r2 := m.GetIndexesByRepoFunc.nextHook()(v0, v1, v2, v3, v4)
+	m.GetIndexesByRepoFunc.appendCall(DBGetIndexesByRepoFuncCall{v0, v1, v2, v3, v4, r0, r1, r2})
+	return r0, r1, r2
+}
+
+// SetDefaultHook sets function that is called when the GetIndexesByRepo
+// method of the parent MockDB instance is invoked and the hook queue is
+// empty.
+func (f *DBGetIndexesByRepoFunc) SetDefaultHook(hook func(context.Context, int, string, int, int) ([]db.Index, int, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// GetIndexesByRepo method of the parent MockDB instance inovkes the hook at
+// the front of the queue and discards it. After the queue is empty, the
+// default hook function is invoked for any future action.
+func (f *DBGetIndexesByRepoFunc) PushHook(hook func(context.Context, int, string, int, int) ([]db.Index, int, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *DBGetIndexesByRepoFunc) SetDefaultReturn(r0 []db.Index, r1 int, r2 error) {
+	f.SetDefaultHook(func(context.Context, int, string, int, int) ([]db.Index, int, error) {
+		return r0, r1, r2
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *DBGetIndexesByRepoFunc) PushReturn(r0 []db.Index, r1 int, r2 error) {
+	f.PushHook(func(context.Context, int, string, int, int) ([]db.Index, int, error) {
+		return r0, r1, r2
+	})
+}
+
+func (f *DBGetIndexesByRepoFunc) nextHook() func(context.Context, int, string, int, int) ([]db.Index, int, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *DBGetIndexesByRepoFunc) appendCall(r0 DBGetIndexesByRepoFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of DBGetIndexesByRepoFuncCall objects
+// describing the invocations of this function.
+func (f *DBGetIndexesByRepoFunc) History() []DBGetIndexesByRepoFuncCall {
+	f.mutex.Lock()
+	history := make([]DBGetIndexesByRepoFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// DBGetIndexesByRepoFuncCall is an object that describes an invocation of
+// method GetIndexesByRepo on an instance of MockDB.
+type DBGetIndexesByRepoFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 string
+	// Arg3 is the value of the 4th argument passed to this method
+	// invocation.
+	Arg3 int
+	// Arg4 is the value of the 5th argument passed to this method
+	// invocation.
+	Arg4 int
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []db.Index
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 int
+	// Result2 is the value of the 3rd result returned from this method
+	// invocation.
+	Result2 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c DBGetIndexesByRepoFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3, c.Arg4}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c DBGetIndexesByRepoFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1, c.Result2}
+}
+
+// DBGetOldestDumpsFunc describes the behavior when the GetOldestDumps
+// method of the parent MockDB instance is invoked.
+type DBGetOldestDumpsFunc struct {
+	defaultHook func(context.Context, int, int) ([]db.Dump, error)
+	hooks       []func(context.Context, int, int) ([]db.Dump, error)
+	history     []DBGetOldestDumpsFuncCall
+	mutex       sync.Mutex
+}
+
+// GetOldestDumps delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockDB) GetOldestDumps(v0 context.Context, v1 int, v2 int) ([]db.Dump, error) {
+	r0, r1 := m.GetOldestDumpsFunc.nextHook()(v0, v1, v2)
+	m.GetOldestDumpsFunc.appendCall(DBGetOldestDumpsFuncCall{v0, v1, v2, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the GetOldestDumps
+// method of the parent MockDB instance is invoked and the hook queue is
+// empty.
+func (f *DBGetOldestDumpsFunc) SetDefaultHook(hook func(context.Context, int, int) ([]db.Dump, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// GetOldestDumps method of the parent MockDB instance inovkes the hook at
+// the front of the queue and discards it. After the queue is empty, the
+// default hook function is invoked for any future action.
+func (f *DBGetOldestDumpsFunc) PushHook(hook func(context.Context, int, int) ([]db.Dump, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *DBGetOldestDumpsFunc) SetDefaultReturn(r0 []db.Dump, r1 error) {
+	f.SetDefaultHook(func(context.Context, int, int) ([]db.Dump, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *DBGetOldestDumpsFunc) PushReturn(r0 []db.Dump, r1 error) {
+	f.PushHook(func(context.Context, int, int) ([]db.Dump, error) {
+		return r0, r1
+	})
+}
+
+func (f *DBGetOldestDumpsFunc) nextHook() func(context.Context, int, int) ([]db.Dump, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *DBGetOldestDumpsFunc) appendCall(r0 DBGetOldestDumpsFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of DBGetOldestDumpsFuncCall objects describing
+// the invocations of this function.
+func (f *DBGetOldestDumpsFunc) History() []DBGetOldestDumpsFuncCall {
+	f.mutex.Lock()
+	history := make([]DBGetOldestDumpsFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// DBGetOldestDumpsFuncCall is an object that describes an invocation of
+// method GetOldestDumps on an instance of MockDB.
+type DBGetOldestDumpsFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 int
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []db.Dump
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c DBGetOldestDumpsFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c DBGetOldestDumpsFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
 }
 
 // DBGetPackageFunc describes the behavior when the GetPackage method of the
@@ -1620,27 +2669,361 @@ func (c DBGetUploadsByRepoFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1, c.Result2}
 }
 
+// DBIndexReferenceIdentifiersFunc describes the behavior when the
+// IndexReferenceIdentifiers method of the parent MockDB instance is
+// invoked.
+type DBIndexReferenceIdentifiersFunc struct {
+	defaultHook func(context.Context, int, []types.ReferenceIdentifier) error
+	hooks       []func(context.Context, int, []types.ReferenceIdentifier) error
+	history     []DBIndexReferenceIdentifiersFuncCall
+	mutex       sync.Mutex
+}
+
+// IndexReferenceIdentifiers delegates to the next hook function in the
+// queue and stores the parameter and result values of this invocation.
+func (m *MockDB) IndexReferenceIdentifiers(v0 context.Context, v1 int, v2 []types.ReferenceIdentifier) error {
+	r0 := m.IndexReferenceIdentifiersFunc.nextHook()(v0, v1, v2)
+	m.IndexReferenceIdentifiersFunc.appendCall(DBIndexReferenceIdentifiersFuncCall{v0, v1, v2, r0})
+	return r0
+}
+
+// SetDefaultHook sets function that is called when the
+// IndexReferenceIdentifiers method of the parent MockDB instance is invoked
+// and the hook queue is empty.
+func (f *DBIndexReferenceIdentifiersFunc) SetDefaultHook(hook func(context.Context, int, []types.ReferenceIdentifier) error) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// IndexReferenceIdentifiers method of the parent MockDB instance inovkes
+// the hook at the front of the queue and discards it. After the queue is
+// empty, the default hook function is invoked for any future action.
+func (f *DBIndexReferenceIdentifiersFunc) PushHook(hook func(context.Context, int, []types.ReferenceIdentifier) error) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *DBIndexReferenceIdentifiersFunc) SetDefaultReturn(r0 error) {
+	f.SetDefaultHook(func(context.Context, int, []types.ReferenceIdentifier) error {
+		return r0
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *DBIndexReferenceIdentifiersFunc) PushReturn(r0 error) {
+	f.PushHook(func(context.Context, int, []types.ReferenceIdentifier) error {
+		return r0
+	})
+}
+
+func (f *DBIndexReferenceIdentifiersFunc) nextHook() func(context.Context, int, []types.ReferenceIdentifier) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *DBIndexReferenceIdentifiersFunc) appendCall(r0 DBIndexReferenceIdentifiersFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of DBIndexReferenceIdentifiersFuncCall objects
+// describing the invocations of this function.
+func (f *DBIndexReferenceIdentifiersFunc) History() []DBIndexReferenceIdentifiersFuncCall {
+	f.mutex.Lock()
+	history := make([]DBIndexReferenceIdentifiersFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// DBIndexReferenceIdentifiersFuncCall is an object that describes an
+// invocation of method IndexReferenceIdentifiers on an instance of MockDB.
+type DBIndexReferenceIdentifiersFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 []types.ReferenceIdentifier
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c DBIndexReferenceIdentifiersFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c DBIndexReferenceIdentifiersFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0}
+}
+
+// DBMarkIndexCompleteFunc describes the behavior when the MarkIndexComplete
+// method of the parent MockDB instance is invoked.
+type DBMarkIndexCompleteFunc struct {
+	defaultHook func(context.Context, int, int, string) error
+	hooks       []func(context.Context, int, int, string) error
+	history     []DBMarkIndexCompleteFuncCall
+	mutex       sync.Mutex
+}
+
+// MarkIndexComplete delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockDB) MarkIndexComplete(v0 context.Context, v1 int, v2 int, v3 string) error {
+	r0 := m.MarkIndexCompleteFunc.nextHook()(v0, v1, v2, v3)
+	m.MarkIndexCompleteFunc.appendCall(DBMarkIndexCompleteFuncCall{v0, v1, v2, v3, r0})
+	return r0
+}
+
+// SetDefaultHook sets function that is called when the MarkIndexComplete
+// method of the parent MockDB instance is invoked and the hook queue is
+// empty.
+func (f *DBMarkIndexCompleteFunc) SetDefaultHook(hook func(context.Context, int, int, string) error) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// MarkIndexComplete method of the parent MockDB instance inovkes the hook
+// at the front of the queue and discards it. After the queue is empty, the
+// default hook function is invoked for any future action.
+func (f *DBMarkIndexCompleteFunc) PushHook(hook func(context.Context, int, int, string) error) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *DBMarkIndexCompleteFunc) SetDefaultReturn(r0 error) {
+	f.SetDefaultHook(func(context.Context, int, int, string) error {
+		return r0
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *DBMarkIndexCompleteFunc) PushReturn(r0 error) {
+	f.PushHook(func(context.Context, int, int, string) error {
+		return r0
+	})
+}
+
+func (f *DBMarkIndexCompleteFunc) nextHook() func(context.Context, int, int, string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *DBMarkIndexCompleteFunc) appendCall(r0 DBMarkIndexCompleteFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of DBMarkIndexCompleteFuncCall objects
+// describing the invocations of this function.
+func (f *DBMarkIndexCompleteFunc) History() []DBMarkIndexCompleteFuncCall {
+	f.mutex.Lock()
+	history := make([]DBMarkIndexCompleteFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// DBMarkIndexCompleteFuncCall is an object that describes an invocation of
+// method MarkIndexComplete on an instance of MockDB.
+type DBMarkIndexCompleteFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 int
+	// Arg3 is the value of the 4th argument passed to this method
+	// invocation.
+	Arg3 string
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c DBMarkIndexCompleteFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c DBMarkIndexCompleteFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0}
+}
+
+// DBMarkIndexErroredFunc describes the behavior when the MarkIndexErrored
+// method of the parent MockDB instance is invoked.
+type DBMarkIndexErroredFunc struct {
+	defaultHook func(context.Context, int, string, string) error
+	hooks       []func(context.Context, int, string, string) error
+	history     []DBMarkIndexErroredFuncCall
+	mutex       sync.Mutex
+}
+
+// MarkIndexErrored delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockDB) MarkIndexErrored(v0 context.Context, v1 int, v2 string, v3 string) error {
+	r0 := m.MarkIndexErroredFunc.nextHook()(v0, v1, v2, v3)
+	m.MarkIndexErroredFunc.appendCall(DBMarkIndexErroredFuncCall{v0, v1, v2, v3, r0})
+	return r0
+}
+
+// SetDefaultHook sets function that is called when the MarkIndexErrored
+// method of the parent MockDB instance is invoked and the hook queue is
+// empty.
+func (f *DBMarkIndexErroredFunc) SetDefaultHook(hook func(context.Context, int, string, string) error) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// MarkIndexErrored method of the parent MockDB instance inovkes the hook at
+// the front of the queue and discards it. After the queue is empty, the
+// default hook function is invoked for any future action.
+func (f *DBMarkIndexErroredFunc) PushHook(hook func(context.Context, int, string, string) error) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *DBMarkIndexErroredFunc) SetDefaultReturn(r0 error) {
+	f.SetDefaultHook(func(context.Context, int, string, string) error {
+		return r0
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *DBMarkIndexErroredFunc) PushReturn(r0 error) {
+	f.PushHook(func(context.Context, int, string, string) error {
+		return r0
+	})
+}
+
+func (f *DBMarkIndexErroredFunc) nextHook() func(context.Context, int, string, string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *DBMarkIndexErroredFunc) appendCall(r0 DBMarkIndexErroredFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of DBMarkIndexErroredFuncCall objects
+// describing the invocations of this function.
+func (f *DBMarkIndexErroredFunc) History() []DBMarkIndexErroredFuncCall {
+	f.mutex.Lock()
+	history := make([]DBMarkIndexErroredFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// DBMarkIndexErroredFuncCall is an object that describes an invocation of
+// method MarkIndexErrored on an instance of MockDB.
+type DBMarkIndexErroredFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 string
+	// Arg3 is the value of the 4th argument passed to this method
+	// invocation.
+	Arg3 string
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c DBMarkIndexErroredFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c DBMarkIndexErroredFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0}
+}
+
 // DBPackageReferencePagerFunc describes the behavior when the
 // PackageReferencePager method of the parent MockDB instance is invoked.
 type DBPackageReferencePagerFunc struct {
-	defaultHook func(context.Context, string, string, string, int, int) (int, db.ReferencePager, error)
-	hooks       []func(context.Context, string, string, string, int, int) (int, db.ReferencePager, error)
+	defaultHook func(context.Context, string, string, string, string, int, int) (int, db.ReferencePager, error)
+	hooks       []func(context.Context, string, string, string, string, int, int) (int, db.ReferencePager, error)
 	history     []DBPackageReferencePagerFuncCall
 	mutex       sync.Mutex
 }
 
 // PackageReferencePager delegates to the next hook function in the queue
 // and stores the parameter and result values of this invocation.
-func (m *MockDB) PackageReferencePager(v0 context.Context, v1 string, v2 string, v3 string, v4 int, v5 int) (int, db.ReferencePager, error) {
-	r0, r1, r2 := m.PackageReferencePagerFunc.nextHook()(v0, v1, v2, v3, v4, v5)
-	m.PackageReferencePagerFunc.appendCall(DBPackageReferencePagerFuncCall{v0, v1, v2, v3, v4, v5, r0, r1, r2})
+func (m *MockDB) PackageReferencePager(v0 context.Context, v1 string, v2 string, v3 string, v4 string, v5 int, v6 int) (int, db.ReferencePager, error) {
+	r0, r1, r2 := m.PackageReferencePagerFunc.nextHook()(v0, v1, v2, v3, v4, v5, v6)
+	m.PackageReferencePagerFunc.appendCall(DBPackageReferencePagerFuncCall{v0, v1, v2, v3, v4, v5, v6, r0, r1, r2})
 	return r0, r1, r2
 }
 
 // SetDefaultHook sets function that is called when the
 // PackageReferencePager method of the parent MockDB instance is invoked and
 // the hook queue is empty.
-func (f *DBPackageReferencePagerFunc) SetDefaultHook(hook func(context.Context, string, string, string, int, int) (int, db.ReferencePager, error)) {
+func (f *DBPackageReferencePagerFunc) SetDefaultHook(hook func(context.Context, string, string, string, string, int, int) (int, db.ReferencePager, error)) {
 	f.defaultHook = hook
 }
 
@@ -1648,7 +3031,7 @@ func (f *DBPackageReferencePagerFunc) SetDefaultHook(hook func(context.Context,
 // PackageReferencePager method of the parent MockDB instance inovkes the
 // hook at the front of the queue and discards it. After the queue is empty,
 // the default hook function is invoked for any future action.
-func (f *DBPackageReferencePagerFunc) PushHook(hook func(context.Context, string, string, string, int, int) (int, db.ReferencePager, error)) {
+func (f *DBPackageReferencePagerFunc) PushHook(hook func(context.Context, string, string, string, string, int, int) (int, db.ReferencePager, error)) {
 	f.mutex.Lock()
 	f.hooks = append(f.hooks, hook)
 	f.mutex.Unlock()
@@ -1657,7 +3040,7 @@ func (f *DBPackageReferencePagerFunc) PushHook(hook func(context.Context, string
 // SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
 // the given values.
 func (f *DBPackageReferencePagerFunc) SetDefaultReturn(r0 int, r1 db.ReferencePager, r2 error) {
-	f.SetDefaultHook(func(context.Context, string, string, string, int, int) (int, db.ReferencePager, error) {
+	f.SetDefaultHook(func(context.Context, string, string, string, string, int, int) (int, db.ReferencePager, error) {
 		return r0, r1, r2
 	})
 }
@@ -1665,12 +3048,12 @@ func (f *DBPackageReferencePagerFunc) SetDefaultReturn(r0 int, r1 db.ReferencePa
 // PushReturn calls PushDefaultHook with a function that returns the given
 // values.
 func (f *DBPackageReferencePagerFunc) PushReturn(r0 int, r1 db.ReferencePager, r2 error) {
-	f.PushHook(func(context.Context, string, string, string, int, int) (int, db.ReferencePager, error) {
+	f.PushHook(func(context.Context, string, string, string, string, int, int) (int, db.ReferencePager, error) {
 		return r0, r1, r2
 	})
 }
 
-func (f *DBPackageReferencePagerFunc) nextHook() func(context.Context, string, string, string, int, int) (int, db.ReferencePager, error) {
+func (f *DBPackageReferencePagerFunc) nextHook() func(context.Context, string, string, string, string, int, int) (int, db.ReferencePager, error) {
 	f.mutex.Lock()
 	defer f.mutex.Unlock()
 
@@ -1717,10 +3100,13 @@ type DBPackageReferencePagerFuncCall struct {
 	Arg3 string
 	// Arg4 is the value of the 5th argument passed to this method
 	// invocation.
-	Arg4 int
+	Arg4 string
 	// Arg5 is the value of the 6th argument passed to this method
 	// invocation.
 	Arg5 int
+	// Arg6 is the value of the 7th argument passed to this method
+	// invocation.
+	Arg6 int
 	// Result0 is the value of the 1st result returned from this method
 	// invocation.
 	Result0 int
@@ -1735,7 +3121,7 @@ type DBPackageReferencePagerFuncCall struct {
 // Args returns an interface slice containing the arguments of this
 // invocation.
 func (c DBPackageReferencePagerFuncCall) Args() []interface{} {
-	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3, c.Arg4, c.Arg5}
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3, c.Arg4, c.Arg5, c.Arg6}
 }
 
 // Results returns an interface slice containing the results of this
@@ -1960,26 +3346,135 @@ func (c DBResetStalledFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
 
+// DBResetStalledIndexesFunc describes the behavior when the
+// ResetStalledIndexes method of the parent MockDB instance is invoked.
+type DBResetStalledIndexesFunc struct {
+	defaultHook func(context.Context, time.Time) ([]int, error)
+	hooks       []func(context.Context, time.Time) ([]int, error)
+	history     []DBResetStalledIndexesFuncCall
+	mutex       sync.Mutex
+}
+
+// ResetStalledIndexes delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockDB) ResetStalledIndexes(v0 context.Context, v1 time.Time) ([]int, error) {
+	r0, r1 := m.ResetStalledIndexesFunc.nextHook()(v0, v1)
+	m.ResetStalledIndexesFunc.appendCall(DBResetStalledIndexesFuncCall{v0, v1, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the ResetStalledIndexes
+// method of the parent MockDB instance is invoked and the hook queue is
+// empty.
+func (f *DBResetStalledIndexesFunc) SetDefaultHook(hook func(context.Context, time.Time) ([]int, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// ResetStalledIndexes method of the parent MockDB instance inovkes the hook
+// at the front of the queue and discards it. After the queue is empty, the
+// default hook function is invoked for any future action.
+func (f *DBResetStalledIndexesFunc) PushHook(hook func(context.Context, time.Time) ([]int, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *DBResetStalledIndexesFunc) SetDefaultReturn(r0 []int, r1 error) {
+	f.SetDefaultHook(func(context.Context, time.Time) ([]int, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *DBResetStalledIndexesFunc) PushReturn(r0 []int, r1 error) {
+	f.PushHook(func(context.Context, time.Time) ([]int, error) {
+		return r0, r1
+	})
+}
+
+func (f *DBResetStalledIndexesFunc) nextHook() func(context.Context, time.Time) ([]int, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *DBResetStalledIndexesFunc) appendCall(r0 DBResetStalledIndexesFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of DBResetStalledIndexesFuncCall objects
+// describing the invocations of this function.
+func (f *DBResetStalledIndexesFunc) History() []DBResetStalledIndexesFuncCall {
+	f.mutex.Lock()
+	history := make([]DBResetStalledIndexesFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// DBResetStalledIndexesFuncCall is an object that describes an invocation
+// of method ResetStalledIndexes on an instance of MockDB.
+type DBResetStalledIndexesFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 time.Time
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []int
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c DBResetStalledIndexesFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c DBResetStalledIndexesFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
 // DBSameRepoPagerFunc describes the behavior when the SameRepoPager method
 // of the parent MockDB instance is invoked.
 type DBSameRepoPagerFunc struct {
-	defaultHook func(context.Context, int, string, string, string, string, int) (int, db.ReferencePager, error)
-	hooks       []func(context.Context, int, string, string, string, string, int) (int, db.ReferencePager, error)
+	defaultHook func(context.Context, int, string, string, string, string, string, int) (int, db.ReferencePager, error)
+	hooks       []func(context.Context, int, string, string, string, string, string, int) (int, db.ReferencePager, error)
 	history     []DBSameRepoPagerFuncCall
 	mutex       sync.Mutex
 }
 
 // SameRepoPager delegates to the next hook function in the queue and stores
 // the parameter and result values of this invocation.
-func (m *MockDB) SameRepoPager(v0 context.Context, v1 int, v2 string, v3 string, v4 string, v5 string, v6 int) (int, db.ReferencePager, error) {
-	r0, r1, r2 := m.SameRepoPagerFunc.nextHook()(v0, v1, v2, v3, v4, v5, v6)
-	m.SameRepoPagerFunc.appendCall(DBSameRepoPagerFuncCall{v0, v1, v2, v3, v4, v5, v6, r0, r1, r2})
+func (m *MockDB) SameRepoPager(v0 context.Context, v1 int, v2 string, v3 string, v4 string, v5 string, v6 string, v7 int) (int, db.ReferencePager, error) {
+	r0, r1, r2 := m.SameRepoPagerFunc.nextHook()(v0, v1, v2, v3, v4, v5, v6, v7)
+	m.SameRepoPagerFunc.appendCall(DBSameRepoPagerFuncCall{v0, v1, v2, v3, v4, v5, v6, v7, r0, r1, r2})
 	return r0, r1, r2
 }
 
 // SetDefaultHook sets function that is called when the SameRepoPager method
 // of the parent MockDB instance is invoked and the hook queue is empty.
-func (f *DBSameRepoPagerFunc) SetDefaultHook(hook func(context.Context, int, string, string, string, string, int) (int, db.ReferencePager, error)) {
+func (f *DBSameRepoPagerFunc) SetDefaultHook(hook func(context.Context, int, string, string, string, string, string, int) (int, db.ReferencePager, error)) {
 	f.defaultHook = hook
 }
 
@@ -1987,7 +3482,7 @@ func (f *DBSameRepoPagerFunc) SetDefaultHook(hook func(context.Context, int, str
 // SameRepoPager method of the parent MockDB instance inovkes the hook at
 // the front of the queue and discards it. After the queue is empty, the
 // default hook function is invoked for any future action.
-func (f *DBSameRepoPagerFunc) PushHook(hook func(context.Context, int, string, string, string, string, int) (int, db.ReferencePager, error)) {
+func (f *DBSameRepoPagerFunc) PushHook(hook func(context.Context, int, string, string, string, string, string, int) (int, db.ReferencePager, error)) {
 	f.mutex.Lock()
 	f.hooks = append(f.hooks, hook)
 	f.mutex.Unlock()
@@ -1996,7 +3491,7 @@ func (f *DBSameRepoPagerFunc) PushHook(hook func(context.Context, int, string, s
 // SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
 // the given values.
 func (f *DBSameRepoPagerFunc) SetDefaultReturn(r0 int, r1 db.ReferencePager, r2 error) {
-	f.SetDefaultHook(func(context.Context, int, string, string, string, string, int) (int, db.ReferencePager, error) {
+	f.SetDefaultHook(func(context.Context, int, string, string, string, string, string, int) (int, db.ReferencePager, error) {
 		return r0, r1, r2
 	})
 }
@@ -2004,12 +3499,12 @@ func (f *DBSameRepoPagerFunc) SetDefaultReturn(r0 int, r1 db.ReferencePager, r2
 // PushReturn calls PushDefaultHook with a function that returns the given
 // values.
 func (f *DBSameRepoPagerFunc) PushReturn(r0 int, r1 db.ReferencePager, r2 error) {
-	f.PushHook(func(context.Context, int, string, string, string, string, int) (int, db.ReferencePager, error) {
+	f.PushHook(func(context.Context, int, string, string, string, string, string, int) (int, db.ReferencePager, error) {
 		return r0, r1, r2
 	})
 }
 
-func (f *DBSameRepoPagerFunc) nextHook() func(context.Context, int, string, string, string, string, int) (int, db.ReferencePager, error) {
+func (f *DBSameRepoPagerFunc) nextHook() func(context.Context, int, string, string, string, string, string, int) (int, db.ReferencePager, error) {
 	f.mutex.Lock()
 	defer f.mutex.Unlock()
 
@@ -2062,7 +3557,10 @@ type DBSameRepoPagerFuncCall struct {
 	Arg5 string
 	// Arg6 is the value of the 7th argument passed to this method
 	// invocation.
-	Arg6 int
+	Arg6 string
+	// Arg7 is the value of the 8th argument passed to this method
+	// invocation.
+	Arg7 int
 	// Result0 is the value of the 1st result returned from this method
 	// invocation.
 	Result0 int
@@ -2077,7 +3575,7 @@ type DBSameRepoPagerFuncCall struct {
 // Args returns an interface slice containing the arguments of this
 // invocation.
 func (c DBSameRepoPagerFuncCall) Args() []interface{} {
-	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3, c.Arg4, c.Arg5, c.Arg6}
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3, c.Arg4, c.Arg5, c.Arg6, c.Arg7}
 }
 
 // Results returns an interface slice containing the results of this
@@ -2191,6 +3689,119 @@ func (c DBTransactFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
 
+// DBUnindexedReferenceDumpIDsFunc describes the behavior when the
+// UnindexedReferenceDumpIDs method of the parent MockDB instance is
+// invoked.
+type DBUnindexedReferenceDumpIDsFunc struct {
+	defaultHook func(context.Context, int, int) ([]int, error)
+	hooks       []func(context.Context, int, int) ([]int, error)
+	history     []DBUnindexedReferenceDumpIDsFuncCall
+	mutex       sync.Mutex
+}
+
+// UnindexedReferenceDumpIDs delegates to the next hook function in the
+// queue and stores the parameter and result values of this invocation.
+func (m *MockDB) UnindexedReferenceDumpIDs(v0 context.Context, v1 int, v2 int) ([]int, error) {
+	r0, r1 := m.UnindexedReferenceDumpIDsFunc.nextHook()(v0, v1, v2)
+	m.UnindexedReferenceDumpIDsFunc.appendCall(DBUnindexedReferenceDumpIDsFuncCall{v0, v1, v2, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the
+// UnindexedReferenceDumpIDs method of the parent MockDB instance is invoked
+// and the hook queue is empty.
+func (f *DBUnindexedReferenceDumpIDsFunc) SetDefaultHook(hook func(context.Context, int, int) ([]int, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// UnindexedReferenceDumpIDs method of the parent MockDB instance inovkes
+// the hook at the front of the queue and discards it. After the queue is
+// empty, the default hook function is invoked for any future action.
+func (f *DBUnindexedReferenceDumpIDsFunc) PushHook(hook func(context.Context, int, int) ([]int, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *DBUnindexedReferenceDumpIDsFunc) SetDefaultReturn(r0 []int, r1 error) {
+	f.SetDefaultHook(func(context.Context, int, int) ([]int, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *DBUnindexedReferenceDumpIDsFunc) PushReturn(r0 []int, r1 error) {
+	f.PushHook(func(context.Context, int, int) ([]int, error) {
+		return r0, r1
+	})
+}
+
+func (f *DBUnindexedReferenceDumpIDsFunc) nextHook() func(context.Context, int, int) ([]int, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *DBUnindexedReferenceDumpIDsFunc) appendCall(r0 DBUnindexedReferenceDumpIDsFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of DBUnindexedReferenceDumpIDsFuncCall objects
+// describing the invocations of this function.
+func (f *DBUnindexedReferenceDumpIDsFunc) History() []DBUnindexedReferenceDumpIDsFuncCall {
+	f.mutex.Lock()
+	history := make([]DBUnindexedReferenceDumpIDsFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// DBUnindexedReferenceDumpIDsFuncCall is an object that describes an
+// invocation of method UnindexedReferenceDumpIDs on an instance of MockDB.
+type DBUnindexedReferenceDumpIDsFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 int
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []int
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c DBUnindexedReferenceDumpIDsFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c DBUnindexedReferenceDumpIDsFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
 // DBUpdateCommitsFunc describes the behavior when the UpdateCommits method
 // of the parent MockDB instance is invoked.
 type DBUpdateCommitsFunc struct {
@@ -2299,6 +3910,115 @@ func (c DBUpdateCommitsFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0}
 }
 
+// DBUpdateDiagnosticsFunc describes the behavior when the UpdateDiagnostics
+// method of the parent MockDB instance is invoked.
+type DBUpdateDiagnosticsFunc struct {
+	defaultHook func(context.Context, int, []db.UploadDiagnostic) error
+	hooks       []func(context.Context, int, []db.UploadDiagnostic) error
+	history     []DBUpdateDiagnosticsFuncCall
+	mutex       sync.Mutex
+}
+
+// UpdateDiagnostics delegates to the next hook function in the queue and
+// stores the parameter and result values of this invocation.
+func (m *MockDB) UpdateDiagnostics(v0 context.Context, v1 int, v2 []db.UploadDiagnostic) error {
+	r0 := m.UpdateDiagnosticsFunc.nextHook()(v0, v1, v2)
+	m.UpdateDiagnosticsFunc.appendCall(DBUpdateDiagnosticsFuncCall{v0, v1, v2, r0})
+	return r0
+}
+
+// SetDefaultHook sets function that is called when the UpdateDiagnostics
+// method of the parent MockDB instance is invoked and the hook queue is
+// empty.
+func (f *DBUpdateDiagnosticsFunc) SetDefaultHook(hook func(context.Context, int, []db.UploadDiagnostic) error) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// UpdateDiagnostics method of the parent MockDB instance inovkes the hook
+// at the front of the queue and discards it. After the queue is empty, the
+// default hook function is invoked for any future action.
+func (f *DBUpdateDiagnosticsFunc) PushHook(hook func(context.Context, int, []db.UploadDiagnostic) error) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *DBUpdateDiagnosticsFunc) SetDefaultReturn(r0 error) {
+	f.SetDefaultHook(func(context.Context, int, []db.UploadDiagnostic) error {
+		return r0
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *DBUpdateDiagnosticsFunc) PushReturn(r0 error) {
+	f.PushHook(func(context.Context, int, []db.UploadDiagnostic) error {
+		return r0
+	})
+}
+
+func (f *DBUpdateDiagnosticsFunc) nextHook() func(context.Context, int, []db.UploadDiagnostic) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *DBUpdateDiagnosticsFunc) appendCall(r0 DBUpdateDiagnosticsFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of DBUpdateDiagnosticsFuncCall objects
+// describing the invocations of this function.
+func (f *DBUpdateDiagnosticsFunc) History() []DBUpdateDiagnosticsFuncCall {
+	f.mutex.Lock()
+	history := make([]DBUpdateDiagnosticsFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// DBUpdateDiagnosticsFuncCall is an object that describes an invocation of
+// method UpdateDiagnostics on an instance of MockDB.
+type DBUpdateDiagnosticsFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 context.Context
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 []db.UploadDiagnostic
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c DBUpdateDiagnosticsFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c DBUpdateDiagnosticsFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0}
+}
+
 // DBUpdateDumpsVisibleFromTipFunc describes the behavior when the
 // UpdateDumpsVisibleFromTip method of the parent MockDB instance is
 // invoked.
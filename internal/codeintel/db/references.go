@@ -8,8 +8,9 @@ import (
 )
 
 // SameRepoPager returns a ReferencePager for dumps that belong to the given repository and commit and reference the package with the
-// given scheme, name, and version.
-func (db *dbImpl) SameRepoPager(ctx context.Context, repositoryID int, commit, scheme, name, version string, limit int) (_ int, _ ReferencePager, err error) {
+// given scheme, name, and version. Dumps whose identifiers are indexed are only returned if they reference the given identifier. The
+// references of other dumps must be tested against their bloom filter.
+func (db *dbImpl) SameRepoPager(ctx context.Context, repositoryID int, commit, scheme, name, version, identifier string, limit int) (_ int, _ ReferencePager, err error) {
 	tx, started, err := db.transact(ctx)
 	if err != nil {
 		return 0, nil, err
@@ -36,6 +37,7 @@ func (db *dbImpl) SameRepoPager(ctx context.Context, repositoryID int, commit, s
 		sqlf.Sprintf("r.name = %s", name),
 		sqlf.Sprintf("r.version = %s", version),
 		sqlf.Sprintf("r.dump_id IN (%s)", sqlf.Join(intsToQueries(visibleIDs), ", ")),
+		referencesIdentifierCond(identifier),
 	}
 
 	totalCount, _, err := scanFirstInt(tx.query(
@@ -62,8 +64,9 @@ func (db *dbImpl) SameRepoPager(ctx context.Context, repositoryID int, commit, s
 
 // PackageReferencePager returns a ReferencePager for dumps that belong to a remote repository (distinct from the given repository id)
 // and reference the package with the given scheme, name, and version. All resulting dumps are visible at the tip of their repository's
-// default branch.
-func (db *dbImpl) PackageReferencePager(ctx context.Context, scheme, name, version string, repositoryID, limit int) (_ int, _ ReferencePager, err error) {
+// default branch. Dumps whose identifiers are indexed are only returned if they reference the given identifier. The references of other
+// dumps must be tested against their bloom filter.
+func (db *dbImpl) PackageReferencePager(ctx context.Context, scheme, name, version, identifier string, repositoryID, limit int) (_ int, _ ReferencePager, err error) {
 	tx, started, err := db.transact(ctx)
 	if err != nil {
 		return 0, nil, err
@@ -80,6 +83,7 @@ func (db *dbImpl) PackageReferencePager(ctx context.Context, scheme, name, versi
 		sqlf.Sprintf("r.version = %s", version),
 		sqlf.Sprintf("d.repository_id != %s", repositoryID),
 		sqlf.Sprintf("d.visible_at_tip = true"),
+		referencesIdentifierCond(identifier),
 	}
 
 	totalCount, _, err := scanFirstInt(tx.query(
@@ -105,19 +109,109 @@ func (db *dbImpl) PackageReferencePager(ctx context.Context, scheme, name, versi
 	return totalCount, newReferencePager(pageFromOffset, done), nil
 }
 
-// UpdatePackageReferences inserts reference data tied to the given upload.
+// UpdatePackageReferences inserts reference data tied to the given upload and indexes the identifiers of
+// each reference. References without identifiers are left unindexed and are matched by their bloom filter.
 func (db *dbImpl) UpdatePackageReferences(ctx context.Context, references []types.PackageReference) (err error) {
 	if len(references) == 0 {
 		return nil
 	}
 
+	tx, started, err := db.transact(ctx)
+	if err != nil {
+		return err
+	}
+	if started {
+		defer func() { err = tx.Done(err) }()
+	}
+
 	var values []*sqlf.Query
+	var identifiers []*sqlf.Query
 	for _, r := range references {
-		values = append(values, sqlf.Sprintf("(%s, %s, %s, %s, %s)", r.DumpID, r.Scheme, r.Name, r.Version, r.Filter))
+		values = append(values, sqlf.Sprintf("(%s, %s, %s, %s, %s, %s)", r.DumpID, r.Scheme, r.Name, r.Version, r.Filter, len(r.Identifiers) > 0))
+
+		for _, identifier := range r.Identifiers {
+			identifiers = append(identifiers, sqlf.Sprintf("(%s, %s, %s)", r.DumpID, r.Scheme, identifier))
+		}
 	}
 
-	return db.exec(ctx, sqlf.Sprintf(`
-		INSERT INTO lsif_references (dump_id, scheme, name, version, filter)
+	if err := tx.exec(ctx, sqlf.Sprintf(`
+		INSERT INTO lsif_references (dump_id, scheme, name, version, filter, identifiers_indexed)
 		VALUES %s
-	`, sqlf.Join(values, ",")))
+	`, sqlf.Join(values, ","))); err != nil {
+		return err
+	}
+
+	return tx.insertReferenceIdentifiers(ctx, identifiers)
+}
+
+// UnindexedReferenceDumpIDs returns the identifiers, in ascending order, of at most limit dumps with an identifier
+// greater than afterID and with package references whose identifiers have not been indexed. These references were
+// inserted before identifiers were indexed on upload.
+func (db *dbImpl) UnindexedReferenceDumpIDs(ctx context.Context, afterID, limit int) ([]int, error) {
+	return scanInts(db.query(ctx, sqlf.Sprintf(`
+		SELECT DISTINCT r.dump_id FROM lsif_references r
+		WHERE NOT r.identifiers_indexed AND r.dump_id > %s
+		ORDER BY r.dump_id LIMIT %d
+	`, afterID, limit)))
+}
+
+// IndexReferenceIdentifiers indexes the given identifiers referenced by the given dump and marks its package
+// references as indexed.
+func (db *dbImpl) IndexReferenceIdentifiers(ctx context.Context, dumpID int, identifiers []types.ReferenceIdentifier) (err error) {
+	tx, started, err := db.transact(ctx)
+	if err != nil {
+		return err
+	}
+	if started {
+		defer func() { err = tx.Done(err) }()
+	}
+
+	// Clear identifiers indexed by a previous attempt for this dump
+	if err := tx.exec(ctx, sqlf.Sprintf(`DELETE FROM lsif_reference_identifiers WHERE dump_id = %s`, dumpID)); err != nil {
+		return err
+	}
+
+	var values []*sqlf.Query
+	for _, identifier := range identifiers {
+		values = append(values, sqlf.Sprintf("(%s, %s, %s)", dumpID, identifier.Scheme, identifier.Identifier))
+	}
+	if err := tx.insertReferenceIdentifiers(ctx, values); err != nil {
+		return err
+	}
+
+	return tx.exec(ctx, sqlf.Sprintf(`UPDATE lsif_references SET identifiers_indexed = true WHERE dump_id = %s`, dumpID))
+}
+
+// insertReferenceIdentifiers inserts the given (dump_id, scheme, identifier) tuples in batches.
+func (db *dbImpl) insertReferenceIdentifiers(ctx context.Context, values []*sqlf.Query) error {
+	for len(values) > 0 {
+		batch := values
+		if len(batch) > ReferenceIdentifierBatchSize {
+			batch = batch[:ReferenceIdentifierBatchSize]
+		}
+		values = values[len(batch):]
+
+		if err := db.exec(ctx, sqlf.Sprintf(`
+			INSERT INTO lsif_reference_identifiers (dump_id, scheme, identifier)
+			VALUES %s
+		`, sqlf.Join(batch, ","))); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ReferenceIdentifierBatchSize is the maximum number of identifiers inserted by a single statement,
+// which keeps the number of bind variables under the Postgres limit.
+const ReferenceIdentifierBatchSize = 5000
+
+// referencesIdentifierCond returns a condition on the package references table aliased as r that
+// excludes references from dumps whose indexed identifiers do not include the given identifier.
+// References that have not been indexed always match.
+func referencesIdentifierCond(identifier string) *sqlf.Query {
+	return sqlf.Sprintf(`(
+		NOT r.identifiers_indexed OR
+		r.dump_id IN (SELECT i.dump_id FROM lsif_reference_identifiers i WHERE i.scheme = r.scheme AND i.identifier = %s)
+	)`, identifier)
 }
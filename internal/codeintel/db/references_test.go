@@ -43,7 +43,7 @@ func TestSameRepoPager(t *testing.T) {
 		t.Fatalf("unexpected error updating commits: %s", err)
 	}
 
-	totalCount, pager, err := db.SameRepoPager(context.Background(), 50, makeCommit(1), "gomod", "leftpad", "0.1.0", 5)
+	totalCount, pager, err := db.SameRepoPager(context.Background(), 50, makeCommit(1), "gomod", "leftpad", "0.1.0", "ident", 5)
 	if err != nil {
 		t.Fatalf("unexpected error getting pager: %s", err)
 	}
@@ -67,7 +67,7 @@ func TestSameRepoPagerEmpty(t *testing.T) {
 	dbtesting.SetupGlobalTestDB(t)
 	db := &dbImpl{db: dbconn.Global}
 
-	totalCount, pager, err := db.SameRepoPager(context.Background(), 50, makeCommit(1), "gomod", "leftpad", "0.1.0", 5)
+	totalCount, pager, err := db.SameRepoPager(context.Background(), 50, makeCommit(1), "gomod", "leftpad", "0.1.0", "ident", 5)
 	if err != nil {
 		t.Fatalf("unexpected error getting pager: %s", err)
 	}
@@ -116,7 +116,7 @@ func TestSameRepoPagerMultiplePages(t *testing.T) {
 		t.Fatalf("unexpected error updating commits: %s", err)
 	}
 
-	totalCount, pager, err := db.SameRepoPager(context.Background(), 50, makeCommit(1), "gomod", "leftpad", "0.1.0", 3)
+	totalCount, pager, err := db.SameRepoPager(context.Background(), 50, makeCommit(1), "gomod", "leftpad", "0.1.0", "ident", 3)
 	if err != nil {
 		t.Fatalf("unexpected error getting pager: %s", err)
 	}
@@ -176,7 +176,7 @@ func TestSameRepoPagerVisibility(t *testing.T) {
 		t.Fatalf("unexpected error updating commits: %s", err)
 	}
 
-	totalCount, pager, err := db.SameRepoPager(context.Background(), 50, makeCommit(6), "gomod", "leftpad", "0.1.0", 5)
+	totalCount, pager, err := db.SameRepoPager(context.Background(), 50, makeCommit(6), "gomod", "leftpad", "0.1.0", "ident", 5)
 	if err != nil {
 		t.Fatalf("unexpected error getting pager: %s", err)
 	}
@@ -222,7 +222,7 @@ func TestPackageReferencePager(t *testing.T) {
 		{DumpID: 6, Scheme: "gomod", Name: "leftpad", Version: "0.1.0", Filter: []byte("f6")},
 	}, expected...))
 
-	totalCount, pager, err := db.PackageReferencePager(context.Background(), "gomod", "leftpad", "0.1.0", 50, 5)
+	totalCount, pager, err := db.PackageReferencePager(context.Background(), "gomod", "leftpad", "0.1.0", "ident", 50, 5)
 	if err != nil {
 		t.Fatalf("unexpected error getting pager: %s", err)
 	}
@@ -239,6 +239,47 @@ func TestPackageReferencePager(t *testing.T) {
 	}
 }
 
+func TestPackageReferencePagerIndexedIdentifiers(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	dbtesting.SetupGlobalTestDB(t)
+	db := &dbImpl{db: dbconn.Global}
+
+	insertUploads(t, dbconn.Global,
+		Upload{ID: 2, Commit: makeCommit(2), VisibleAtTip: true, RepositoryID: 51},
+		Upload{ID: 3, Commit: makeCommit(3), VisibleAtTip: true, RepositoryID: 52},
+		Upload{ID: 4, Commit: makeCommit(4), VisibleAtTip: true, RepositoryID: 53},
+	)
+
+	insertPackageReferences(t, db, []types.PackageReference{
+		{DumpID: 2, Scheme: "gomod", Name: "leftpad", Version: "0.1.0", Filter: []byte("f2"), Identifiers: []string{"ident", "other"}},
+		{DumpID: 3, Scheme: "gomod", Name: "leftpad", Version: "0.1.0", Filter: []byte("f3"), Identifiers: []string{"other"}},
+		{DumpID: 4, Scheme: "gomod", Name: "leftpad", Version: "0.1.0", Filter: []byte("f4")},
+	})
+
+	totalCount, pager, err := db.PackageReferencePager(context.Background(), "gomod", "leftpad", "0.1.0", "ident", 50, 5)
+	if err != nil {
+		t.Fatalf("unexpected error getting pager: %s", err)
+	}
+	defer func() { _ = pager.Done(nil) }()
+
+	if totalCount != 2 {
+		t.Errorf("unexpected dump. want=%d have=%d", 2, totalCount)
+	}
+
+	// Dump 3 is indexed and does not reference the identifier; dump 4 is not indexed
+	expected := []types.PackageReference{
+		{DumpID: 2, Scheme: "gomod", Name: "leftpad", Version: "0.1.0", Filter: []byte("f2")},
+		{DumpID: 4, Scheme: "gomod", Name: "leftpad", Version: "0.1.0", Filter: []byte("f4")},
+	}
+	if references, err := pager.PageFromOffset(context.Background(), 0); err != nil {
+		t.Fatalf("unexpected error getting next page: %s", err)
+	} else if diff := cmp.Diff(expected, references); diff != "" {
+		t.Errorf("unexpected references (-want +got):\n%s", diff)
+	}
+}
+
 func TestPackageReferencePagerEmpty(t *testing.T) {
 	if testing.Short() {
 		t.Skip()
@@ -246,7 +287,7 @@ func TestPackageReferencePagerEmpty(t *testing.T) {
 	dbtesting.SetupGlobalTestDB(t)
 	db := &dbImpl{db: dbconn.Global}
 
-	totalCount, pager, err := db.PackageReferencePager(context.Background(), "gomod", "leftpad", "0.1.0", 50, 5)
+	totalCount, pager, err := db.PackageReferencePager(context.Background(), "gomod", "leftpad", "0.1.0", "ident", 50, 5)
 	if err != nil {
 		t.Fatalf("unexpected error getting pager: %s", err)
 	}
@@ -289,7 +330,7 @@ func TestPackageReferencePagerPages(t *testing.T) {
 	}
 	insertPackageReferences(t, db, expected)
 
-	totalCount, pager, err := db.PackageReferencePager(context.Background(), "gomod", "leftpad", "0.1.0", 50, 3)
+	totalCount, pager, err := db.PackageReferencePager(context.Background(), "gomod", "leftpad", "0.1.0", "ident", 50, 3)
 	if err != nil {
 		t.Fatalf("unexpected error getting pager: %s", err)
 	}
@@ -418,3 +459,52 @@ func TestUpdatePackageReferencesWithDuplicates(t *testing.T) {
 		t.Errorf("unexpected reference count. want=%d have=%d", 12, count)
 	}
 }
+
+func TestIndexReferenceIdentifiers(t *testing.T) {
+	if testing.Short() {
+		t.Skip()
+	}
+	dbtesting.SetupGlobalTestDB(t)
+	db := &dbImpl{db: dbconn.Global}
+
+	insertUploads(t, dbconn.Global, Upload{ID: 42}, Upload{ID: 43}, Upload{ID: 44})
+	insertPackageReferences(t, db, []types.PackageReference{
+		{DumpID: 42, Scheme: "s0", Name: "n0", Version: "v0"},
+		{DumpID: 42, Scheme: "s1", Name: "n1", Version: "v1"},
+		{DumpID: 43, Scheme: "s0", Name: "n0", Version: "v0"},
+		{DumpID: 44, Scheme: "s0", Name: "n0", Version: "v0", Identifiers: []string{"i0"}},
+	})
+
+	if dumpIDs, err := db.UnindexedReferenceDumpIDs(context.Background(), 0, 10); err != nil {
+		t.Fatalf("unexpected error getting unindexed dumps: %s", err)
+	} else if diff := cmp.Diff([]int{42, 43}, dumpIDs); diff != "" {
+		t.Errorf("unexpected dump ids (-want +got):\n%s", diff)
+	}
+
+	if err := db.IndexReferenceIdentifiers(context.Background(), 42, []types.ReferenceIdentifier{
+		{Scheme: "s0", Identifier: "i0"},
+		{Scheme: "s1", Identifier: "i1"},
+	}); err != nil {
+		t.Fatalf("unexpected error indexing identifiers: %s", err)
+	}
+
+	if dumpIDs, err := db.UnindexedReferenceDumpIDs(context.Background(), 0, 10); err != nil {
+		t.Fatalf("unexpected error getting unindexed dumps: %s", err)
+	} else if diff := cmp.Diff([]int{43}, dumpIDs); diff != "" {
+		t.Errorf("unexpected dump ids (-want +got):\n%s", diff)
+	}
+
+	if dumpIDs, err := db.UnindexedReferenceDumpIDs(context.Background(), 43, 10); err != nil {
+		t.Fatalf("unexpected error getting unindexed dumps: %s", err)
+	} else if len(dumpIDs) != 0 {
+		t.Errorf("unexpected dump ids after cursor: %v", dumpIDs)
+	}
+
+	count, err := scanInt(dbconn.Global.QueryRow("SELECT COUNT(*) FROM lsif_reference_identifiers WHERE dump_id = 42"))
+	if err != nil {
+		t.Fatalf("unexpected error checking identifier count: %s", err)
+	}
+	if count != 2 {
+		t.Errorf("unexpected identifier count. want=%d have=%d", 2, count)
+	}
+}
@@ -117,6 +117,56 @@ func scanFirstUpload(rows *sql.Rows, err error) (Upload, bool, error) {
 	return uploads[0], true, nil
 }
 
+// scanIndex populates an Index value from the given scanner.
+func scanIndex(scanner scanner) (index Index, err error) {
+	err = scanner.Scan(
+		&index.ID,
+		&index.Commit,
+		&index.RepositoryID,
+		&index.Indexer,
+		&index.State,
+		&index.QueuedAt,
+		&index.StartedAt,
+		&index.FinishedAt,
+		&index.FailureSummary,
+		&index.Log,
+		&index.UploadID,
+	)
+	return index, err
+}
+
+// scanIndexes reads the given set of index rows and returns a slice of resulting
+// values. This method should be called directly with the return value of `*db.query`.
+func scanIndexes(rows *sql.Rows, err error) ([]Index, error) {
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var indexes []Index
+	for rows.Next() {
+		index, err := scanIndex(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		indexes = append(indexes, index)
+	}
+
+	return indexes, nil
+}
+
+// scanFirstIndex reads the given set of index rows and returns the first value and
+// a boolean flag indicating its presence. This method should be called directly with
+// the return value of `*db.query`.
+func scanFirstIndex(rows *sql.Rows, err error) (Index, bool, error) {
+	indexes, err := scanIndexes(rows, err)
+	if err != nil || len(indexes) == 0 {
+		return Index{}, false, err
+	}
+	return indexes[0], true, nil
+}
+
 // scanPackageReference populates a package reference value from the given scanner.
 func scanPackageReference(scanner scanner) (reference types.PackageReference, err error) {
 	err = scanner.Scan(&reference.DumpID, &reference.Scheme, &reference.Name, &reference.Version, &reference.Filter)
@@ -144,6 +194,39 @@ func scanPackageReferences(rows *sql.Rows, err error) ([]types.PackageReference,
 	return references, nil
 }
 
+// scanDiagnostic populates an UploadDiagnostic value from the given scanner.
+func scanDiagnostic(scanner scanner) (diagnostic UploadDiagnostic, err error) {
+	err = scanner.Scan(
+		&diagnostic.Severity,
+		&diagnostic.Code,
+		&diagnostic.Message,
+		&diagnostic.ElementID,
+		&diagnostic.Line,
+	)
+	return diagnostic, err
+}
+
+// scanDiagnostics reads the given set of diagnostic rows and returns a slice of resulting values.
+// This method should be called directly with the return value of `*db.query`.
+func scanDiagnostics(rows *sql.Rows, err error) ([]UploadDiagnostic, error) {
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var diagnostics []UploadDiagnostic
+	for rows.Next() {
+		diagnostic, err := scanDiagnostic(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		diagnostics = append(diagnostics, diagnostic)
+	}
+
+	return diagnostics, nil
+}
+
 // scanString populates a string value from the given scanner.
 func scanString(scanner scanner) (value string, err error) {
 	err = scanner.Scan(&value)
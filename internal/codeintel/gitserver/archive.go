@@ -0,0 +1,24 @@
+package gitserver
+
+import (
+	"context"
+	"io"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/db"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+)
+
+// Archive returns a tar archive of the files of the given repository at the given commit.
+func Archive(db db.DB, repositoryID int, commit string) (io.ReadCloser, error) {
+	// TODO(efritz) - remove dependency on codeintel/db package
+	repoName, err := db.RepoName(context.Background(), repositoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	return gitserver.DefaultClient.Archive(context.Background(), gitserver.Repo{Name: api.RepoName(repoName)}, gitserver.ArchiveOptions{
+		Treeish: commit,
+		Format:  "tar",
+	})
+}
@@ -1,6 +1,10 @@
 package gitserver
 
-import "github.com/sourcegraph/sourcegraph/internal/codeintel/db"
+import (
+	"io"
+
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/db"
+)
 
 // Client is an interface that wraps all of the queries to gitserver needed by the
 // precise-code-intel services.
@@ -16,6 +20,16 @@ type Client interface {
 	// of git ls-tree. The keys of the resulting map are the input (unsanitized) dirnames, and the value of
 	// that key are the files nested under that directory.
 	DirectoryChildren(db db.DB, repositoryID int, commit string, dirnames []string) (map[string][]string, error)
+
+	// Archive returns a tar archive of the files of the given repository at the given commit.
+	Archive(db db.DB, repositoryID int, commit string) (io.ReadCloser, error)
+
+	// Refs returns the branches and tags of the given repository.
+	Refs(db db.DB, repositoryID int) ([]Ref, error)
+
+	// Ancestors returns the given commit followed by at most limit-1 of its ancestors, from
+	// the most to the least recent.
+	Ancestors(db db.DB, repositoryID int, commit string, limit int) ([]string, error)
 }
 
 type defaultClient struct{}
@@ -33,3 +47,15 @@ func (c *defaultClient) CommitsNear(db db.DB, repositoryID int, commit string) (
 func (c *defaultClient) DirectoryChildren(db db.DB, repositoryID int, commit string, dirnames []string) (map[string][]string, error) {
 	return DirectoryChildren(db, repositoryID, commit, dirnames)
 }
+
+func (c *defaultClient) Archive(db db.DB, repositoryID int, commit string) (io.ReadCloser, error) {
+	return Archive(db, repositoryID, commit)
+}
+
+func (c *defaultClient) Refs(db db.DB, repositoryID int) ([]Ref, error) {
+	return Refs(db, repositoryID)
+}
+
+func (c *defaultClient) Ancestors(db db.DB, repositoryID int, commit string, limit int) ([]string, error) {
+	return Ancestors(db, repositoryID, commit, limit)
+}
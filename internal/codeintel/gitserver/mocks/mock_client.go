@@ -5,6 +5,7 @@ package mocks
 import (
 	db "github.com/sourcegraph/sourcegraph/internal/codeintel/db"
 	gitserver "github.com/sourcegraph/sourcegraph/internal/codeintel/gitserver"
+	"io"
 	"sync"
 )
 
@@ -12,6 +13,12 @@ import (
 // package github.com/sourcegraph/sourcegraph/internal/codeintel/gitserver)
 // used for unit testing.
 type MockClient struct {
+	// AncestorsFunc is an instance of a mock function object controlling
+	// the behavior of the method Ancestors.
+	AncestorsFunc *ClientAncestorsFunc
+	// ArchiveFunc is an instance of a mock function object controlling the
+	// behavior of the method Archive.
+	ArchiveFunc *ClientArchiveFunc
 	// CommitsNearFunc is an instance of a mock function object controlling
 	// the behavior of the method CommitsNear.
 	CommitsNearFunc *ClientCommitsNearFunc
@@ -21,12 +28,25 @@ type MockClient struct {
 	// HeadFunc is an instance of a mock function object controlling the
 	// behavior of the method Head.
 	HeadFunc *ClientHeadFunc
+	// RefsFunc is an instance of a mock function object controlling the
+	// behavior of the method Refs.
+	RefsFunc *ClientRefsFunc
 }
 
 // NewMockClient creates a new mock of the Client interface. All methods
 // return zero values for all results, unless overwritten.
 func NewMockClient() *MockClient {
 	return &MockClient{
+		AncestorsFunc: &ClientAncestorsFunc{
+			defaultHook: func(db.DB, int, string, int) ([]string, error) {
+				return nil, nil
+			},
+		},
+		ArchiveFunc: &ClientArchiveFunc{
+			defaultHook: func(db.DB, int, string) (io.ReadCloser, error) {
+				return nil, nil
+			},
+		},
 		CommitsNearFunc: &ClientCommitsNearFunc{
 			defaultHook: func(db.DB, int, string) (map[string][]string, error) {
 				return nil, nil
@@ -42,6 +62,11 @@ func NewMockClient() *MockClient {
 				return "", nil
 			},
 		},
+		RefsFunc: &ClientRefsFunc{
+			defaultHook: func(db.DB, int) ([]gitserver.Ref, error) {
+				return nil, nil
+			},
+		},
 	}
 }
 
@@ -49,6 +74,12 @@ func NewMockClient() *MockClient {
 // methods delegate to the given implementation, unless overwritten.
 func NewMockClientFrom(i gitserver.Client) *MockClient {
 	return &MockClient{
+		AncestorsFunc: &ClientAncestorsFunc{
+			defaultHook: i.Ancestors,
+		},
+		ArchiveFunc: &ClientArchiveFunc{
+			defaultHook: i.Archive,
+		},
 		CommitsNearFunc: &ClientCommitsNearFunc{
 			defaultHook: i.CommitsNear,
 		},
@@ -58,7 +89,235 @@ func NewMockClientFrom(i gitserver.Client) *MockClient {
 		HeadFunc: &ClientHeadFunc{
 			defaultHook: i.Head,
 		},
+		RefsFunc: &ClientRefsFunc{
+			defaultHook: i.Refs,
+		},
+	}
+}
+
+// ClientAncestorsFunc describes the behavior when the Ancestors method of
+// the parent MockClient instance is invoked.
+type ClientAncestorsFunc struct {
+	defaultHook func(db.DB, int, string, int) ([]string, error)
+	hooks       []func(db.DB, int, string, int) ([]string, error)
+	history     []ClientAncestorsFuncCall
+	mutex       sync.Mutex
+}
+
+// Ancestors delegates to the next hook function in the queue and stores the
+// parameter and result values of this invocation.
+func (m *MockClient) Ancestors(v0 db.DB, v1 int, v2 string, v3 int) ([]string, error) {
+	r0, r1 := m.AncestorsFunc.nextHook()(v0, v1, v2, v3)
+	m.AncestorsFunc.appendCall(ClientAncestorsFuncCall{v0, v1, v2, v3, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the Ancestors method of
+// the parent MockClient instance is invoked and the hook queue is empty.
+func (f *ClientAncestorsFunc) SetDefaultHook(hook func(db.DB, int, string, int) ([]string, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// Ancestors method of the parent MockClient instance inovkes the hook at
+// the front of the queue and discards it. After the queue is empty, the
+// default hook function is invoked for any future action.
+func (f *ClientAncestorsFunc) PushHook(hook func(db.DB, int, string, int) ([]string, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *ClientAncestorsFunc) SetDefaultReturn(r0 []string, r1 error) {
+	f.SetDefaultHook(func(db.DB, int, string, int) ([]string, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *ClientAncestorsFunc) PushReturn(r0 []string, r1 error) {
+	f.PushHook(func(db.DB, int, string, int) ([]string, error) {
+		return r0, r1
+	})
+}
+
+func (f *ClientAncestorsFunc) nextHook() func(db.DB, int, string, int) ([]string, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *ClientAncestorsFunc) appendCall(r0 ClientAncestorsFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of ClientAncestorsFuncCall objects describing
+// the invocations of this function.
+func (f *ClientAncestorsFunc) History() []ClientAncestorsFuncCall {
+	f.mutex.Lock()
+	history := make([]ClientAncestorsFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// ClientAncestorsFuncCall is an object that describes an invocation of
+// method Ancestors on an instance of MockClient.
+type ClientAncestorsFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 db.DB
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 string
+	// Arg3 is the value of the 4th argument passed to this method
+	// invocation.
+	Arg3 int
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []string
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c ClientAncestorsFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2, c.Arg3}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c ClientAncestorsFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
+
+// ClientArchiveFunc describes the behavior when the Archive method of the
+// parent MockClient instance is invoked.
+type ClientArchiveFunc struct {
+	defaultHook func(db.DB, int, string) (io.ReadCloser, error)
+	hooks       []func(db.DB, int, string) (io.ReadCloser, error)
+	history     []ClientArchiveFuncCall
+	mutex       sync.Mutex
+}
+
+// Archive delegates to the next hook function in the queue and stores the
+// parameter and result values of this invocation.
+func (m *MockClient) Archive(v0 db.DB, v1 int, v2 string) (io.ReadCloser, error) {
+	r0, r1 := m.ArchiveFunc.nextHook()(v0, v1, v2)
+	m.ArchiveFunc.appendCall(ClientArchiveFuncCall{v0, v1, v2, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the Archive method of
+// the parent MockClient instance is invoked and the hook queue is empty.
+func (f *ClientArchiveFunc) SetDefaultHook(hook func(db.DB, int, string) (io.ReadCloser, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// Archive method of the parent MockClient instance inovkes the hook at the
+// front of the queue and discards it. After the queue is empty, the default
+// hook function is invoked for any future action.
+func (f *ClientArchiveFunc) PushHook(hook func(db.DB, int, string) (io.ReadCloser, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *ClientArchiveFunc) SetDefaultReturn(r0 io.ReadCloser, r1 error) {
+	f.SetDefaultHook(func(db.DB, int, string) (io.ReadCloser, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *ClientArchiveFunc) PushReturn(r0 io.ReadCloser, r1 error) {
+	f.PushHook(func(db.DB, int, string) (io.ReadCloser, error) {
+		return r0, r1
+	})
+}
+
+func (f *ClientArchiveFunc) nextHook() func(db.DB, int, string) (io.ReadCloser, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
 	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *ClientArchiveFunc) appendCall(r0 ClientArchiveFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of ClientArchiveFuncCall objects describing
+// the invocations of this function.
+func (f *ClientArchiveFunc) History() []ClientArchiveFuncCall {
+	f.mutex.Lock()
+	history := make([]ClientArchiveFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// ClientArchiveFuncCall is an object that describes an invocation of method
+// Archive on an instance of MockClient.
+type ClientArchiveFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 db.DB
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int
+	// Arg2 is the value of the 3rd argument passed to this method
+	// invocation.
+	Arg2 string
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 io.ReadCloser
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c ClientArchiveFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1, c.Arg2}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c ClientArchiveFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
 }
 
 // ClientCommitsNearFunc describes the behavior when the CommitsNear method
@@ -394,3 +653,111 @@ func (c ClientHeadFuncCall) Args() []interface{} {
 func (c ClientHeadFuncCall) Results() []interface{} {
 	return []interface{}{c.Result0, c.Result1}
 }
+
+// ClientRefsFunc describes the behavior when the Refs method of the parent
+// MockClient instance is invoked.
+type ClientRefsFunc struct {
+	defaultHook func(db.DB, int) ([]gitserver.Ref, error)
+	hooks       []func(db.DB, int) ([]gitserver.Ref, error)
+	history     []ClientRefsFuncCall
+	mutex       sync.Mutex
+}
+
+// Refs delegates to the next hook function in the queue and stores the
+// parameter and result values of this invocation.
+func (m *MockClient) Refs(v0 db.DB, v1 int) ([]gitserver.Ref, error) {
+	r0, r1 := m.RefsFunc.nextHook()(v0, v1)
+	m.RefsFunc.appendCall(ClientRefsFuncCall{v0, v1, r0, r1})
+	return r0, r1
+}
+
+// SetDefaultHook sets function that is called when the Refs method of the
+// parent MockClient instance is invoked and the hook queue is empty.
+func (f *ClientRefsFunc) SetDefaultHook(hook func(db.DB, int) ([]gitserver.Ref, error)) {
+	f.defaultHook = hook
+}
+
+// PushHook adds a function to the end of hook queue. Each invocation of the
+// Refs method of the parent MockClient instance inovkes the hook at the
+// front of the queue and discards it. After the queue is empty, the default
+// hook function is invoked for any future action.
+func (f *ClientRefsFunc) PushHook(hook func(db.DB, int) ([]gitserver.Ref, error)) {
+	f.mutex.Lock()
+	f.hooks = append(f.hooks, hook)
+	f.mutex.Unlock()
+}
+
+// SetDefaultReturn calls SetDefaultDefaultHook with a function that returns
+// the given values.
+func (f *ClientRefsFunc) SetDefaultReturn(r0 []gitserver.Ref, r1 error) {
+	f.SetDefaultHook(func(db.DB, int) ([]gitserver.Ref, error) {
+		return r0, r1
+	})
+}
+
+// PushReturn calls PushDefaultHook with a function that returns the given
+// values.
+func (f *ClientRefsFunc) PushReturn(r0 []gitserver.Ref, r1 error) {
+	f.PushHook(func(db.DB, int) ([]gitserver.Ref, error) {
+		return r0, r1
+	})
+}
+
+func (f *ClientRefsFunc) nextHook() func(db.DB, int) ([]gitserver.Ref, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if len(f.hooks) == 0 {
+		return f.defaultHook
+	}
+
+	hook := f.hooks[0]
+	f.hooks = f.hooks[1:]
+	return hook
+}
+
+func (f *ClientRefsFunc) appendCall(r0 ClientRefsFuncCall) {
+	f.mutex.Lock()
+	f.history = append(f.history, r0)
+	f.mutex.Unlock()
+}
+
+// History returns a sequence of ClientRefsFuncCall objects describing the
+// invocations of this function.
+func (f *ClientRefsFunc) History() []ClientRefsFuncCall {
+	f.mutex.Lock()
+	history := make([]ClientRefsFuncCall, len(f.history))
+	copy(history, f.history)
+	f.mutex.Unlock()
+
+	return history
+}
+
+// ClientRefsFuncCall is an object that describes an invocation of method
+// Refs on an instance of MockClient.
+type ClientRefsFuncCall struct {
+	// Arg0 is the value of the 1st argument passed to this method
+	// invocation.
+	Arg0 db.DB
+	// Arg1 is the value of the 2nd argument passed to this method
+	// invocation.
+	Arg1 int
+	// Result0 is the value of the 1st result returned from this method
+	// invocation.
+	Result0 []gitserver.Ref
+	// Result1 is the value of the 2nd result returned from this method
+	// invocation.
+	Result1 error
+}
+
+// Args returns an interface slice containing the arguments of this
+// invocation.
+func (c ClientRefsFuncCall) Args() []interface{} {
+	return []interface{}{c.Arg0, c.Arg1}
+}
+
+// Results returns an interface slice containing the results of this
+// invocation.
+func (c ClientRefsFuncCall) Results() []interface{} {
+	return []interface{}{c.Result0, c.Result1}
+}
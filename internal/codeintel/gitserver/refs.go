@@ -0,0 +1,81 @@
+package gitserver
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/codeintel/db"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+)
+
+// Ref is a branch or tag of a repository.
+type Ref struct {
+	Name   string // the short name of the branch or tag
+	Commit string // the commit the ref points to; tags are peeled to their commit
+	IsTag  bool
+}
+
+// Refs returns the branches and tags of the given repository.
+func Refs(db db.DB, repositoryID int) ([]Ref, error) {
+	// TODO(efritz) - remove dependency on codeintel/db package
+	repoName, err := db.RepoName(context.Background(), repositoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	cmd := gitserver.DefaultClient.Command("git", "for-each-ref", "--format=%(refname) %(objectname) %(*objectname)", "refs/heads", "refs/tags")
+	cmd.Repo = gitserver.Repo{Name: api.RepoName(repoName)}
+	out, err := cmd.CombinedOutput(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	return parseRefs(strings.Split(string(bytes.TrimSpace(out)), "\n")), nil
+}
+
+// parseRefs converts the output of git for-each-ref into a list of refs. The commit of an
+// annotated tag is the peeled object name in the last column.
+func parseRefs(lines []string) []Ref {
+	var refs []Ref
+	for _, line := range lines {
+		parts := strings.Fields(line)
+		if len(parts) < 2 {
+			continue
+		}
+
+		commit := parts[1]
+		if len(parts) > 2 {
+			commit = parts[2]
+		}
+
+		if name := strings.TrimPrefix(parts[0], "refs/heads/"); name != parts[0] {
+			refs = append(refs, Ref{Name: name, Commit: commit})
+		} else if name := strings.TrimPrefix(parts[0], "refs/tags/"); name != parts[0] {
+			refs = append(refs, Ref{Name: name, Commit: commit, IsTag: true})
+		}
+	}
+
+	return refs
+}
+
+// Ancestors returns the given commit followed by at most limit-1 of its ancestors, from
+// the most to the least recent.
+func Ancestors(db db.DB, repositoryID int, commit string, limit int) ([]string, error) {
+	// TODO(efritz) - remove dependency on codeintel/db package
+	repoName, err := db.RepoName(context.Background(), repositoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	cmd := gitserver.DefaultClient.Command("git", "rev-list", fmt.Sprintf("--max-count=%d", limit), commit)
+	cmd.Repo = gitserver.Repo{Name: api.RepoName(repoName)}
+	out, err := cmd.CombinedOutput(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	return strings.Fields(string(out)), nil
+}
@@ -0,0 +1,28 @@
+package gitserver
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseRefs(t *testing.T) {
+	lines := []string{
+		"refs/heads/master 9ad62c7ec68e377b41a8b8dd846e573b76634172 ",
+		"refs/heads/release/1.0 683cafd122632142bda6e36563f5719e5b0fa37d ",
+		"refs/tags/v1.0.0 1afa9c06d8bb8b2c5746e539ed4eb80c23b21db3 02f41985f46b400b7a673c3dfb6bab8fd1ac6a6d",
+		"refs/tags/lightweight a94fb112d1f2e70f55851c6c569916a9e31caee1 ",
+		"",
+	}
+
+	expected := []Ref{
+		{Name: "master", Commit: "9ad62c7ec68e377b41a8b8dd846e573b76634172"},
+		{Name: "release/1.0", Commit: "683cafd122632142bda6e36563f5719e5b0fa37d"},
+		{Name: "v1.0.0", Commit: "02f41985f46b400b7a673c3dfb6bab8fd1ac6a6d", IsTag: true},
+		{Name: "lightweight", Commit: "a94fb112d1f2e70f55851c6c569916a9e31caee1", IsTag: true},
+	}
+
+	if diff := cmp.Diff(expected, parseRefs(lines)); diff != "" {
+		t.Errorf("unexpected refs (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,88 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/lsif"
+)
+
+func (c *Client) GetIndexes(ctx context.Context, args *struct {
+	RepoID api.RepoID
+	State  *string
+	Limit  *int32
+	Cursor *string
+}) ([]*lsif.LSIFIndex, string, *int, error) {
+	query := queryValues{}
+	query.SetOptionalInt32("limit", args.Limit)
+
+	if args.State != nil {
+		query.Set("state", strings.ToLower(*args.State))
+	}
+
+	req := &lsifRequest{
+		path:   fmt.Sprintf("/indexes/repository/%d", args.RepoID),
+		cursor: args.Cursor,
+		query:  query,
+	}
+
+	payload := struct {
+		Indexes    []*lsif.LSIFIndex `json:"indexes"`
+		TotalCount *int              `json:"totalCount"`
+	}{
+		Indexes: []*lsif.LSIFIndex{},
+	}
+
+	meta, err := c.do(ctx, req, &payload)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	return payload.Indexes, meta.nextURL, payload.TotalCount, nil
+}
+
+func (c *Client) GetIndex(ctx context.Context, args *struct {
+	IndexID int64
+}) (*lsif.LSIFIndex, error) {
+	req := &lsifRequest{
+		path: fmt.Sprintf("/indexes/%d", args.IndexID),
+	}
+
+	payload := &lsif.LSIFIndex{}
+	_, err := c.do(ctx, req, &payload)
+	return payload, err
+}
+
+func (c *Client) EnqueueIndex(ctx context.Context, args *struct {
+	RepoID      api.RepoID
+	Commit      api.CommitID
+	IndexerName string
+}) (int64, bool, error) {
+	query := queryValues{}
+	query.SetInt("repositoryId", int64(args.RepoID))
+	query.Set("commit", string(args.Commit))
+	query.Set("indexerName", args.IndexerName)
+
+	req := &lsifRequest{
+		path:   "/indexes",
+		method: "POST",
+		query:  query,
+	}
+
+	payload := struct {
+		ID *int64 `json:"id"`
+	}{}
+
+	meta, err := c.do(ctx, req, &payload)
+	if err != nil {
+		return 0, false, err
+	}
+	if payload.ID == nil {
+		return 0, false, nil
+	}
+
+	return *payload.ID, meta.statusCode == http.StatusAccepted, nil
+}
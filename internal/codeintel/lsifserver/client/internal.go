@@ -24,6 +24,22 @@ func (c *Client) Prune(ctx context.Context) (int64, bool, error) {
 	return *id, true, nil
 }
 
+func (c *Client) Retention(ctx context.Context) ([]int, error) {
+	req := &lsifRequest{
+		method: "POST",
+		path:   "/retention",
+	}
+
+	var payload struct {
+		IDs []int `json:"ids"`
+	}
+	if _, err := c.do(ctx, req, &payload); err != nil {
+		return nil, err
+	}
+
+	return payload.IDs, nil
+}
+
 func (c *Client) States(ctx context.Context, ids []int) (map[int]string, error) {
 	serialized, err := json.Marshal(map[string]interface{}{"ids": ids})
 	if err != nil {
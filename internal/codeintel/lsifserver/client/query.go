@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
 
 	"github.com/sourcegraph/go-lsp"
 	"github.com/sourcegraph/sourcegraph/internal/api"
@@ -208,3 +209,97 @@ func (c *Client) Hover(ctx context.Context, args *struct {
 
 	return payload.Text, payload.Range, nil
 }
+
+func (c *Client) Ranges(ctx context.Context, args *struct {
+	RepoID    api.RepoID
+	Commit    api.CommitID
+	Path      string
+	Positions []lsp.Position
+	UploadID  int64
+}) ([]*lsif.LSIFCodeIntelligenceRange, error) {
+	query := queryValues{}
+	query.SetInt("repositoryId", int64(args.RepoID))
+	query.Set("commit", string(args.Commit))
+	query.Set("path", args.Path)
+	query.SetInt("uploadId", int64(args.UploadID))
+
+	if len(args.Positions) > 0 {
+		positions := make([]string, 0, len(args.Positions))
+		for _, position := range args.Positions {
+			positions = append(positions, fmt.Sprintf("%d:%d", position.Line, position.Character))
+		}
+		query.Set("positions", strings.Join(positions, ","))
+	}
+
+	req := &lsifRequest{
+		path:       "/ranges",
+		query:      query,
+		routingKey: fmt.Sprintf("%d:%s", args.RepoID, args.Commit),
+	}
+
+	payload := struct {
+		Ranges []*lsif.LSIFCodeIntelligenceRange `json:"ranges"`
+	}{}
+
+	if _, err := c.do(ctx, req, &payload); err != nil {
+		return nil, err
+	}
+
+	return payload.Ranges, nil
+}
+
+func (c *Client) DocumentSymbols(ctx context.Context, args *struct {
+	RepoID   api.RepoID
+	Commit   api.CommitID
+	Path     string
+	UploadID int64
+}) ([]lsif.LSIFSymbol, error) {
+	query := queryValues{}
+	query.SetInt("repositoryId", int64(args.RepoID))
+	query.Set("commit", string(args.Commit))
+	query.Set("path", args.Path)
+	query.SetInt("uploadId", int64(args.UploadID))
+
+	req := &lsifRequest{
+		path:       "/documentSymbols",
+		query:      query,
+		routingKey: fmt.Sprintf("%d:%s", args.RepoID, args.Commit),
+	}
+
+	var payload []lsif.LSIFSymbol
+	if _, err := c.do(ctx, req, &payload); err != nil {
+		return nil, err
+	}
+
+	return payload, nil
+}
+
+func (c *Client) SearchSymbols(ctx context.Context, args *struct {
+	RepoID          api.RepoID
+	Commit          api.CommitID
+	Query           string
+	IncludePatterns []string
+	ExcludePattern  string
+	Limit           int
+}) ([]lsif.LSIFSymbolLocation, error) {
+	query := queryValues{}
+	query.SetInt("repositoryId", int64(args.RepoID))
+	query.Set("commit", string(args.Commit))
+	query.Set("query", args.Query)
+	query["include"] = args.IncludePatterns
+	query.Set("exclude", args.ExcludePattern)
+	query.SetInt("limit", int64(args.Limit))
+
+	req := &lsifRequest{
+		path:       "/symbols",
+		query:      query,
+		routingKey: fmt.Sprintf("%d:%s", args.RepoID, args.Commit),
+	}
+
+	var payload []lsif.LSIFSymbolLocation
+	if _, err := c.do(ctx, req, &payload); err != nil {
+		return nil, err
+	}
+
+	return payload, nil
+}
@@ -59,6 +59,46 @@ func (c *Client) GetUpload(ctx context.Context, args *struct {
 	return payload, err
 }
 
+func (c *Client) GetUploadDiagnostics(ctx context.Context, args *struct {
+	UploadID int64
+}) ([]*lsif.LSIFUploadDiagnostic, error) {
+	req := &lsifRequest{
+		path: fmt.Sprintf("/uploads/%d/diagnostics", args.UploadID),
+	}
+
+	payload := struct {
+		Diagnostics []*lsif.LSIFUploadDiagnostic `json:"diagnostics"`
+	}{
+		Diagnostics: []*lsif.LSIFUploadDiagnostic{},
+	}
+
+	if _, err := c.do(ctx, req, &payload); err != nil {
+		return nil, err
+	}
+
+	return payload.Diagnostics, nil
+}
+
+func (c *Client) GetRetention(ctx context.Context, args *struct {
+	RepoID api.RepoID
+}) ([]*lsif.LSIFUploadRetention, error) {
+	req := &lsifRequest{
+		path: fmt.Sprintf("/uploads/repository/%d/retention", args.RepoID),
+	}
+
+	payload := struct {
+		Uploads []*lsif.LSIFUploadRetention `json:"uploads"`
+	}{
+		Uploads: []*lsif.LSIFUploadRetention{},
+	}
+
+	if _, err := c.do(ctx, req, &payload); err != nil {
+		return nil, err
+	}
+
+	return payload.Uploads, nil
+}
+
 func (c *Client) DeleteUpload(ctx context.Context, args *struct {
 	UploadID int64
 }) error {
@@ -0,0 +1,101 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+type localStore struct {
+	dir string
+}
+
+var _ LocalStore = &localStore{}
+
+// NewLocal returns a store which keeps objects as files in the given directory. The key
+// of an object is its path relative to dir.
+func NewLocal(dir string) LocalStore {
+	return &localStore{dir: dir}
+}
+
+func (s *localStore) Path(key string) string {
+	return filepath.Join(s.dir, filepath.FromSlash(key))
+}
+
+func (s *localStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	f, err := os.Open(s.Path(key))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%w: %s", ErrNotFound, key)
+		}
+		return nil, err
+	}
+
+	return f, nil
+}
+
+func (s *localStore) Upload(ctx context.Context, key string, r io.Reader) (err error) {
+	path := s.Path(key)
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return err
+	}
+
+	// Write into a temporary file in the same directory and rename it over the target so
+	// that concurrent readers never see a partial object
+	tmp, err := ioutil.TempFile(filepath.Dir(path), ".upload-")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			os.Remove(tmp.Name())
+		}
+	}()
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+func (s *localStore) Delete(ctx context.Context, key string) error {
+	if err := os.Remove(s.Path(key)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return nil
+}
+
+func (s *localStore) List(ctx context.Context, prefix string) ([]Object, error) {
+	fileInfos, err := ioutil.ReadDir(s.Path(prefix))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var objects []Object
+	for _, fileInfo := range fileInfos {
+		if !fileInfo.Mode().IsRegular() || fileInfo.Name()[0] == '.' {
+			// Skip directories and in-progress uploads
+			continue
+		}
+
+		objects = append(objects, Object{
+			Key:          prefix + fileInfo.Name(),
+			Size:         fileInfo.Size(),
+			LastModified: fileInfo.ModTime(),
+		})
+	}
+
+	return objects, nil
+}
@@ -0,0 +1,17 @@
+package storage
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func TestLocalStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "precise-code-intel-storage-")
+	if err != nil {
+		t.Fatalf("unexpected error creating test directory: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	testStore(t, NewLocal(dir))
+}
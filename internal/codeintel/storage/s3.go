@@ -0,0 +1,137 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/awserr"
+	"github.com/aws/aws-sdk-go-v2/aws/external"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/s3manager"
+)
+
+// S3Config configures a store backed by an S3 bucket.
+type S3Config struct {
+	Bucket string
+	// Region defaults to the region of the ambient AWS configuration.
+	Region string
+	// Endpoint is the URL of an S3-compatible service (such as MinIO) to use instead of AWS.
+	// Buckets of such services are addressed by path rather than by hostname.
+	Endpoint string
+	// AccessKeyID and SecretAccessKey default to the credentials of the ambient AWS
+	// configuration (environment variables, shared credentials file, or instance role).
+	AccessKeyID     string
+	SecretAccessKey string
+}
+
+type s3Store struct {
+	bucket   string
+	client   *s3.Client
+	uploader *s3manager.Uploader
+}
+
+var _ Store = &s3Store{}
+
+// NewS3 returns a store which keeps objects in the configured S3 bucket.
+func NewS3(config S3Config) (Store, error) {
+	if config.Bucket == "" {
+		return nil, errors.New("no bucket supplied for S3 storage")
+	}
+
+	awsConfig, err := external.LoadDefaultAWSConfig()
+	if err != nil {
+		return nil, err
+	}
+	if config.Region != "" {
+		awsConfig.Region = config.Region
+	}
+	if config.AccessKeyID != "" {
+		awsConfig.Credentials = aws.StaticCredentialsProvider{
+			Value: aws.Credentials{
+				AccessKeyID:     config.AccessKeyID,
+				SecretAccessKey: config.SecretAccessKey,
+				Source:          "precise-code-intel-storage",
+			},
+		}
+	}
+	if config.Endpoint != "" {
+		awsConfig.EndpointResolver = aws.ResolveWithEndpointURL(config.Endpoint)
+	}
+
+	client := s3.New(awsConfig)
+	client.ForcePathStyle = config.Endpoint != ""
+
+	return &s3Store{
+		bucket:   config.Bucket,
+		client:   client,
+		uploader: s3manager.NewUploaderWithClient(client),
+	}, nil
+}
+
+func (s *s3Store) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	resp, err := s.client.GetObjectRequest(&s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	}).Send(ctx)
+	if err != nil {
+		if isNoSuchKey(err) {
+			return nil, fmt.Errorf("%w: %s", ErrNotFound, key)
+		}
+		return nil, err
+	}
+
+	return resp.Body, nil
+}
+
+// Upload streams r to the bucket, using a multipart upload for large objects.
+func (s *s3Store) Upload(ctx context.Context, key string, r io.Reader) error {
+	_, err := s.uploader.UploadWithContext(ctx, &s3manager.UploadInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+		Body:   r,
+	})
+	return err
+}
+
+func (s *s3Store) Delete(ctx context.Context, key string) error {
+	_, err := s.client.DeleteObjectRequest(&s3.DeleteObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	}).Send(ctx)
+	if err != nil && !isNoSuchKey(err) {
+		return err
+	}
+
+	return nil
+}
+
+func (s *s3Store) List(ctx context.Context, prefix string) ([]Object, error) {
+	paginator := s3.NewListObjectsV2Paginator(s.client.ListObjectsV2Request(&s3.ListObjectsV2Input{
+		Bucket: aws.String(s.bucket),
+		Prefix: aws.String(prefix),
+	}))
+
+	var objects []Object
+	for paginator.Next(ctx) {
+		for _, object := range paginator.CurrentPage().Contents {
+			objects = append(objects, Object{
+				Key:          aws.StringValue(object.Key),
+				Size:         aws.Int64Value(object.Size),
+				LastModified: aws.TimeValue(object.LastModified),
+			})
+		}
+	}
+	if err := paginator.Err(); err != nil {
+		return nil, err
+	}
+
+	return objects, nil
+}
+
+func isNoSuchKey(err error) bool {
+	var awsErr awserr.Error
+	return errors.As(err, &awsErr) && awsErr.Code() == s3.ErrCodeNoSuchKey
+}
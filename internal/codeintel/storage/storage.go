@@ -0,0 +1,96 @@
+// Package storage provides the stores in which raw LSIF uploads and converted bundles
+// are kept, either on the local disk of the bundle manager or in an S3-compatible
+// object storage service.
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Store is a flat collection of objects addressed by slash-separated keys.
+type Store interface {
+	// Get returns a reader of the object with the given key. If the object does not
+	// exist, the returned error satisfies IsNotFound.
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+
+	// Upload writes the contents of r to the object with the given key, replacing any
+	// existing object. The object is not visible to readers until the upload completes.
+	Upload(ctx context.Context, key string, r io.Reader) error
+
+	// Delete removes the object with the given key. Deleting an object that does not
+	// exist is not an error.
+	Delete(ctx context.Context, key string) error
+
+	// List returns the objects whose keys begin with the given prefix, which must end
+	// with a slash.
+	List(ctx context.Context, prefix string) ([]Object, error)
+}
+
+// LocalStore is a store whose objects are files on the local disk. Readers which need
+// a file on disk can use them in place instead of copying them.
+type LocalStore interface {
+	Store
+
+	// Path returns the path of the file that holds the object with the given key.
+	Path(key string) string
+}
+
+// Object describes an object in a store.
+type Object struct {
+	Key          string
+	Size         int64
+	LastModified time.Time
+}
+
+// ErrNotFound occurs when the requested object does not exist.
+var ErrNotFound = errors.New("object not found")
+
+// IsNotFound reports whether err is or wraps ErrNotFound.
+func IsNotFound(err error) bool {
+	return errors.Is(err, ErrNotFound)
+}
+
+// UploadsPrefix is the prefix of the keys of raw LSIF uploads.
+const UploadsPrefix = "uploads/"
+
+// DBsPrefix is the prefix of the keys of converted bundles.
+const DBsPrefix = "dbs/"
+
+// UploadKey returns the key of the raw (gzipped) LSIF upload with the given identifier.
+func UploadKey(id int64) string {
+	return fmt.Sprintf("%s%d.lsif.gz", UploadsPrefix, id)
+}
+
+// DBKey returns the key of the converted bundle with the given identifier.
+func DBKey(id int64) string {
+	return fmt.Sprintf("%s%d.lsif.db", DBsPrefix, id)
+}
+
+// Config selects and configures a store.
+type Config struct {
+	// Backend is either "local" or "s3".
+	Backend string
+	// Dir is the root directory of the local store.
+	Dir string
+	// S3 configures the S3 store.
+	S3 S3Config
+}
+
+// New returns the store selected by the given configuration.
+func New(config Config) (Store, error) {
+	switch config.Backend {
+	case "", "local":
+		if config.Dir == "" {
+			return nil, errors.New("no directory supplied for local storage")
+		}
+		return NewLocal(config.Dir), nil
+	case "s3":
+		return NewS3(config.S3)
+	default:
+		return nil, fmt.Errorf("unknown storage backend %q", config.Backend)
+	}
+}
@@ -0,0 +1,74 @@
+package storage
+
+import (
+	"context"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// testStore exercises the behavior shared by all store implementations.
+func testStore(t *testing.T, store Store) {
+	ctx := context.Background()
+
+	for key, content := range map[string]string{
+		UploadKey(1): "upload 1",
+		UploadKey(2): "upload 2",
+		DBKey(1):     "db 1",
+	} {
+		if err := store.Upload(ctx, key, strings.NewReader(content)); err != nil {
+			t.Fatalf("unexpected error uploading %s: %s", key, err)
+		}
+	}
+
+	// Replace an existing object
+	if err := store.Upload(ctx, UploadKey(2), strings.NewReader("upload 2 (replaced)")); err != nil {
+		t.Fatalf("unexpected error uploading %s: %s", UploadKey(2), err)
+	}
+
+	rc, err := store.Get(ctx, UploadKey(2))
+	if err != nil {
+		t.Fatalf("unexpected error getting %s: %s", UploadKey(2), err)
+	}
+	content, err := ioutil.ReadAll(rc)
+	rc.Close()
+	if err != nil {
+		t.Fatalf("unexpected error reading %s: %s", UploadKey(2), err)
+	}
+	if string(content) != "upload 2 (replaced)" {
+		t.Errorf("unexpected content. want=%q have=%q", "upload 2 (replaced)", content)
+	}
+
+	if _, err := store.Get(ctx, UploadKey(3)); !IsNotFound(err) {
+		t.Errorf("expected not found error getting missing object, got %v", err)
+	}
+
+	if err := store.Delete(ctx, UploadKey(1)); err != nil {
+		t.Fatalf("unexpected error deleting %s: %s", UploadKey(1), err)
+	}
+	if err := store.Delete(ctx, UploadKey(1)); err != nil {
+		t.Fatalf("unexpected error deleting missing object: %s", err)
+	}
+
+	objects, err := store.List(ctx, UploadsPrefix)
+	if err != nil {
+		t.Fatalf("unexpected error listing objects: %s", err)
+	}
+	var keys []string
+	for _, object := range objects {
+		keys = append(keys, object.Key)
+		if object.Size != int64(len("upload 2 (replaced)")) {
+			t.Errorf("unexpected size of %s: %d", object.Key, object.Size)
+		}
+		if object.LastModified.IsZero() {
+			t.Errorf("expected last modified time of %s", object.Key)
+		}
+	}
+	sort.Strings(keys)
+	if diff := cmp.Diff([]string{UploadKey(2)}, keys); diff != "" {
+		t.Errorf("unexpected keys (-want +got):\n%s", diff)
+	}
+}
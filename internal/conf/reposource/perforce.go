@@ -0,0 +1,19 @@
+package reposource
+
+import (
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+)
+
+// PerforceRepoName returns the name of the repository which mirrors the depot
+// at depotPath (e.g., "//Sourcegraph/") of the Perforce Server at host.
+func PerforceRepoName(repositoryPathPattern, host, depotPath string) api.RepoName {
+	if repositoryPathPattern == "" {
+		repositoryPathPattern = "{host}/{depot}"
+	}
+	return api.RepoName(strings.NewReplacer(
+		"{host}", host,
+		"{depot}", strings.Trim(depotPath, "/"),
+	).Replace(repositoryPathPattern))
+}
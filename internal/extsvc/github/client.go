@@ -193,6 +193,9 @@ func (c *Client) do(ctx context.Context, req *http.Request, result interface{})
 		err.Code = resp.StatusCode
 		return &err
 	}
+	if result == nil {
+		return nil
+	}
 	return json.NewDecoder(resp.Body).Decode(result)
 }
 
@@ -0,0 +1,69 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// OrgWebhook is a webhook of a GitHub organization.
+// See https://developer.github.com/v3/orgs/hooks/.
+type OrgWebhook struct {
+	ID     int64            `json:"id,omitempty"`
+	Name   string           `json:"name"`
+	Active bool             `json:"active"`
+	Events []string         `json:"events"`
+	Config OrgWebhookConfig `json:"config"`
+}
+
+// OrgWebhookConfig is the delivery configuration of an OrgWebhook. GitHub never returns the
+// secret; it is only sent when creating or updating a webhook.
+type OrgWebhookConfig struct {
+	URL         string `json:"url"`
+	ContentType string `json:"content_type"`
+	Secret      string `json:"secret,omitempty"`
+}
+
+// ListOrgWebhooks lists the first 100 webhooks of the given organization. The authenticated user
+// must be an owner of the organization.
+func (c *Client) ListOrgWebhooks(ctx context.Context, org string) ([]*OrgWebhook, error) {
+	var hooks []*OrgWebhook
+	if err := c.requestGet(ctx, fmt.Sprintf("orgs/%s/hooks?per_page=100", url.PathEscape(org)), &hooks); err != nil {
+		return nil, err
+	}
+	return hooks, nil
+}
+
+// CreateOrgWebhook creates a webhook on the given organization.
+func (c *Client) CreateOrgWebhook(ctx context.Context, org string, hook *OrgWebhook) error {
+	return c.requestWebhook(ctx, "POST", fmt.Sprintf("orgs/%s/hooks", url.PathEscape(org)), hook)
+}
+
+// UpdateOrgWebhook updates the webhook of the given organization that has the ID of hook.
+func (c *Client) UpdateOrgWebhook(ctx context.Context, org string, hook *OrgWebhook) error {
+	return c.requestWebhook(ctx, "PATCH", fmt.Sprintf("orgs/%s/hooks/%d", url.PathEscape(org), hook.ID), hook)
+}
+
+// DeleteOrgWebhook deletes the webhook with the given ID from the given organization.
+func (c *Client) DeleteOrgWebhook(ctx context.Context, org string, id int64) error {
+	req, err := http.NewRequest("DELETE", fmt.Sprintf("orgs/%s/hooks/%d", url.PathEscape(org), id), nil)
+	if err != nil {
+		return err
+	}
+	return c.do(ctx, req, nil)
+}
+
+func (c *Client) requestWebhook(ctx context.Context, method, requestURI string, hook *OrgWebhook) error {
+	body, err := json.Marshal(hook)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(method, requestURI, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	return c.do(ctx, req, hook)
+}
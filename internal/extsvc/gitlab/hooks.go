@@ -0,0 +1,68 @@
+package gitlab
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// ProjectHook is a webhook of a GitLab project.
+// See https://docs.gitlab.com/ee/api/projects.html#hooks.
+type ProjectHook struct {
+	ID                    int    `json:"id,omitempty"`
+	URL                   string `json:"url"`
+	PushEvents            bool   `json:"push_events"`
+	TagPushEvents         bool   `json:"tag_push_events"`
+	EnableSSLVerification bool   `json:"enable_ssl_verification"`
+
+	// Token is the secret token that GitLab sends in the X-Gitlab-Token header. GitLab never
+	// returns it; it is only sent when adding or editing a hook.
+	Token string `json:"token,omitempty"`
+}
+
+// ListProjectHooks lists the first 100 hooks of the project with the given ID. The authenticated
+// user must be a maintainer of the project.
+func (c *Client) ListProjectHooks(ctx context.Context, projectID int) ([]*ProjectHook, error) {
+	req, err := http.NewRequest("GET", fmt.Sprintf("projects/%d/hooks?per_page=100", projectID), nil)
+	if err != nil {
+		return nil, err
+	}
+	var hooks []*ProjectHook
+	_, err = c.do(ctx, req, &hooks)
+	return hooks, err
+}
+
+// AddProjectHook adds a hook to the project with the given ID.
+func (c *Client) AddProjectHook(ctx context.Context, projectID int, hook *ProjectHook) error {
+	return c.requestProjectHook(ctx, "POST", fmt.Sprintf("projects/%d/hooks", projectID), hook)
+}
+
+// EditProjectHook edits the hook of the project with the given ID that has the ID of hook.
+func (c *Client) EditProjectHook(ctx context.Context, projectID int, hook *ProjectHook) error {
+	return c.requestProjectHook(ctx, "PUT", fmt.Sprintf("projects/%d/hooks/%d", projectID, hook.ID), hook)
+}
+
+// DeleteProjectHook deletes the hook with the given ID from the project with the given ID.
+func (c *Client) DeleteProjectHook(ctx context.Context, projectID, hookID int) error {
+	req, err := http.NewRequest("DELETE", fmt.Sprintf("projects/%d/hooks/%d", projectID, hookID), nil)
+	if err != nil {
+		return err
+	}
+	_, err = c.do(ctx, req, nil)
+	return err
+}
+
+func (c *Client) requestProjectHook(ctx context.Context, method, urlStr string, hook *ProjectHook) error {
+	body, err := json.Marshal(hook)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(method, urlStr, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	_, err = c.do(ctx, req, hook)
+	return err
+}
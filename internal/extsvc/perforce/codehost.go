@@ -0,0 +1,53 @@
+// Package perforce contains the types and helpers shared by the services which
+// mirror Perforce depots as Git repositories.
+package perforce
+
+import (
+	"strings"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+)
+
+// ServiceType is the (api.ExternalRepoSpec).ServiceType value for Perforce
+// depots. The ServiceID value is the P4PORT of the Perforce Server (e.g.,
+// "ssl:perforce.example.com:1666").
+const ServiceType = "perforce"
+
+// Depot is the metadata of a Perforce depot mirrored as a repository.
+type Depot struct {
+	// Path is the depot path, such as "//Sourcegraph/".
+	Path string
+}
+
+// ExternalRepoSpec returns an api.ExternalRepoSpec that refers to the
+// specified depot on the Perforce Server at port.
+func ExternalRepoSpec(depot *Depot, port string) api.ExternalRepoSpec {
+	return api.ExternalRepoSpec{
+		ID:          depot.Path,
+		ServiceType: ServiceType,
+		ServiceID:   port,
+	}
+}
+
+// Host returns the host of the Perforce Server at port (e.g.,
+// "perforce.example.com" for "ssl:perforce.example.com:1666").
+func Host(port string) string {
+	_, addr := splitTransport(port)
+	i := strings.LastIndex(addr, ":")
+	if i < 0 {
+		// port is only a port number, which p4 resolves on localhost.
+		return "localhost"
+	}
+	return addr[:i]
+}
+
+// splitTransport splits port into its transport prefix (such as "ssl"), which
+// is empty if it has none, and its address.
+func splitTransport(port string) (transport, addr string) {
+	if i := strings.Index(port, ":"); i >= 0 {
+		if t := port[:i]; strings.HasPrefix(t, "tcp") || strings.HasPrefix(t, "ssl") {
+			return t, port[i+1:]
+		}
+	}
+	return "", port
+}
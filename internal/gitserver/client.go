@@ -60,6 +60,9 @@ func NewClient(cli httpcli.Doer) *Client {
 		Addrs: func(ctx context.Context) []string {
 			return conf.Get().ServiceConnections.GitServers
 		},
+		ReplicationFactor: func() int {
+			return conf.Get().ExperimentalFeatures.GitServerReplicationFactor
+		},
 		HTTPClient:  cli,
 		HTTPLimiter: parallel.NewRun(500),
 		// Use the binary name for UserAgent. This should effectively identify
@@ -82,31 +85,122 @@ type Client struct {
 	// concurrent use. It may return different results at different times.
 	Addrs func(ctx context.Context) []string
 
+	// ReplicationFactor is an optional function which returns the number of
+	// gitservers each repository is cloned on. It defaults to 1.
+	ReplicationFactor func() int
+
 	// UserAgent is a string identifing who the client is. It will be logged in
 	// the telemetry in gitserver.
 	UserAgent string
+
+	// unhealthy tracks gitservers which recently failed to respond, mapped to
+	// the time they failed. They are only used after the healthy replicas of
+	// a repository.
+	unhealthyMu sync.Mutex
+	unhealthy   map[string]time.Time
 }
 
+// unhealthyTTL is how long a gitserver which failed to respond is treated as
+// unhealthy.
+const unhealthyTTL = 30 * time.Second
+
 // AddrForRepo returns the gitserver address to use for the given repo name.
+// This is the first healthy replica of the repo.
 func (c *Client) AddrForRepo(ctx context.Context, repo api.RepoName) string {
-	repo = protocol.NormalizeRepo(repo) // in case the caller didn't already normalize it
-	return c.addrForKey(ctx, string(repo))
+	return c.AddrsForRepo(ctx, repo)[0]
+}
+
+// AddrsForRepo returns the addresses of the gitservers the given repo is
+// cloned on, in the order they should be tried. Replicas which recently
+// failed to respond are moved to the end.
+func (c *Client) AddrsForRepo(ctx context.Context, repo api.RepoName) []string {
+	replicas := c.replicaAddrs(ctx, repo)
+	if len(replicas) == 1 {
+		return replicas
+	}
+
+	c.unhealthyMu.Lock()
+	defer c.unhealthyMu.Unlock()
+	healthy := make([]string, 0, len(replicas))
+	var unhealthy []string
+	for _, addr := range replicas {
+		if failed, ok := c.unhealthy[addr]; ok && time.Since(failed) < unhealthyTTL {
+			unhealthy = append(unhealthy, addr)
+		} else {
+			healthy = append(healthy, addr)
+		}
+	}
+	return append(healthy, unhealthy...)
 }
 
-// addrForKey returns the gitserver address to use for the given string key,
-// which is hashed for sharding purposes.
-func (c *Client) addrForKey(ctx context.Context, key string) string {
+// replicaAddrs returns the addresses of the gitservers the given repo is
+// cloned on, with the primary first.
+func (c *Client) replicaAddrs(ctx context.Context, repo api.RepoName) []string {
+	repo = protocol.NormalizeRepo(repo) // in case the caller didn't already normalize it
 	addrs := c.Addrs(ctx)
 	if len(addrs) == 0 {
 		panic("unexpected state: no gitserver addresses")
 	}
-	return addrForKey(addrs, key)
+	return AddrsForKey(addrs, string(repo), c.replicationFactor())
 }
 
-func addrForKey(addrs []string, key string) string {
+func (c *Client) replicationFactor() int {
+	if c.ReplicationFactor == nil {
+		return 1
+	}
+	return c.ReplicationFactor()
+}
+
+// markUnhealthy records that the gitserver at addr failed to respond.
+func (c *Client) markUnhealthy(addr string) {
+	c.unhealthyMu.Lock()
+	if c.unhealthy == nil {
+		c.unhealthy = make(map[string]time.Time)
+	}
+	c.unhealthy[addr] = time.Now()
+	c.unhealthyMu.Unlock()
+}
+
+// respondingAddrForRepo returns the first replica of the given repo which
+// responds to a ping, for callers that hand the address to someone else and
+// so cannot fail over themselves. Replicas which fail to respond are marked
+// unhealthy. If none respond, the first replica is returned.
+func (c *Client) respondingAddrForRepo(ctx context.Context, repo api.RepoName) string {
+	addrs := c.AddrsForRepo(ctx, repo)
+	if len(addrs) == 1 {
+		return addrs[0]
+	}
+
+	for _, addr := range addrs {
+		err := c.ping(ctx, addr)
+		if err == nil {
+			return addr
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		c.markUnhealthy(addr)
+	}
+	return addrs[0]
+}
+
+// AddrsForKey returns the addresses of the replicationFactor gitservers that
+// replicate the given key. The first address is the same as without
+// replication, and the other replicas are the addresses following it.
+func AddrsForKey(addrs []string, key string, replicationFactor int) []string {
+	if replicationFactor < 1 {
+		replicationFactor = 1
+	}
+	if replicationFactor > len(addrs) {
+		replicationFactor = len(addrs)
+	}
 	sum := md5.Sum([]byte(key))
 	serverIndex := binary.BigEndian.Uint64(sum[:]) % uint64(len(addrs))
-	return addrs[serverIndex]
+	replicas := make([]string, 0, replicationFactor)
+	for i := 0; i < replicationFactor; i++ {
+		replicas = append(replicas, addrs[(serverIndex+uint64(i))%uint64(len(addrs))])
+	}
+	return replicas
 }
 
 // ArchiveOptions contains options for the Archive func.
@@ -114,6 +208,10 @@ type ArchiveOptions struct {
 	Treeish string   // the tree or commit to produce an archive for
 	Format  string   // format of the resulting archive (usually "tar" or "zip")
 	Paths   []string // if nonempty, only include these paths
+
+	// LFS is whether to replace Git LFS pointer files with the contents of
+	// the files, if the gitserver has fetched them.
+	LFS bool
 }
 
 // archiveReader wraps the StdoutReader yielded by gitserver's
@@ -144,6 +242,15 @@ func (a *archiveReader) Close() error {
 // ArchiveURL returns a URL from which an archive of the given Git repository can
 // be downloaded from.
 func (c *Client) ArchiveURL(ctx context.Context, repo Repo, opt ArchiveOptions) *url.URL {
+	return &url.URL{
+		Scheme:   "http",
+		Host:     c.respondingAddrForRepo(ctx, repo.Name),
+		Path:     "/archive",
+		RawQuery: archiveQuery(repo, opt).Encode(),
+	}
+}
+
+func archiveQuery(repo Repo, opt ArchiveOptions) url.Values {
 	q := url.Values{
 		"repo":    {string(repo.Name)},
 		"treeish": {opt.Treeish},
@@ -153,13 +260,10 @@ func (c *Client) ArchiveURL(ctx context.Context, repo Repo, opt ArchiveOptions)
 	for _, path := range opt.Paths {
 		q.Add("path", path)
 	}
-
-	return &url.URL{
-		Scheme:   "http",
-		Host:     c.AddrForRepo(ctx, repo.Name),
-		Path:     "/archive",
-		RawQuery: q.Encode(),
+	if opt.LFS {
+		q.Set("lfs", "true")
 	}
+	return q
 }
 
 // Archive produces an archive from a Git repository.
@@ -181,8 +285,7 @@ func (c *Client) Archive(ctx context.Context, repo Repo, opt ArchiveOptions) (_
 		return nil, err
 	}
 
-	u := c.ArchiveURL(ctx, repo, opt)
-	resp, err := c.do(ctx, repo.Name, "GET", u.String(), nil)
+	resp, err := c.do(ctx, repo.Name, "GET", "archive?"+archiveQuery(repo, opt).Encode(), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -267,6 +370,15 @@ func (c *Cmd) sendExec(ctx context.Context) (_ io.ReadCloser, _ http.Header, err
 		resp.Body.Close()
 		return nil, nil, &vcs.RepoNotExistError{Repo: repoName, CloneInProgress: payload.CloneInProgress, CloneProgress: payload.CloneProgress}
 
+	case http.StatusUnprocessableEntity:
+		var payload protocol.HistoryUnavailablePayload
+		if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+			resp.Body.Close()
+			return nil, nil, err
+		}
+		resp.Body.Close()
+		return nil, nil, &HistoryUnavailableError{Repo: repoName, Reason: payload.Reason}
+
 	default:
 		resp.Body.Close()
 		return nil, nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
@@ -301,6 +413,21 @@ type Repo struct {
 	// this field is optional (it will use the last-used Git remote URL). If the repository is not
 	// cloned on the gitserver, the request will fail.
 	URL string
+
+	// LFS is which Git LFS objects the gitserver should fetch, or nil to not
+	// fetch any. It is only used by RequestRepoUpdate. It is a pointer so that
+	// Repo stays comparable.
+	LFS *LFSOptions
+
+	// Clone is how the gitserver should clone and fetch the repository, or
+	// nil for the whole history. It is only used by RequestRepoUpdate.
+	Clone *protocol.CloneStrategy
+}
+
+// LFSOptions configures which Git LFS objects the gitserver fetches.
+type LFSOptions struct {
+	// Refs are the refs whose Git LFS objects are fetched, or nil for HEAD.
+	Refs []string
 }
 
 // Command creates a new Cmd. Command name must be 'git',
@@ -460,7 +587,7 @@ func (c *Client) ping(ctx context.Context, addr string) error {
 func (c *Client) ListGitolite(ctx context.Context, gitoliteHost string) (list []*gitolite.Repo, err error) {
 	// The gitserver calls the shared Gitolite server in response to this request, so
 	// we need to only call a single gitserver (or else we'd get duplicate results).
-	addr := c.addrForKey(ctx, gitoliteHost)
+	addr := AddrsForKey(c.Addrs(ctx), gitoliteHost, 1)[0]
 	req, err := http.NewRequest("GET", "http://"+addr+"/list-gitolite?gitolite="+url.QueryEscape(gitoliteHost), nil)
 	if err != nil {
 		return nil, err
@@ -483,40 +610,95 @@ func (c *Client) ListCloned(ctx context.Context) ([]string, error) {
 		mu    sync.Mutex
 		err   error
 		repos []string
+		seen  = map[string]bool{}
 	)
 	addrs := c.Addrs(ctx)
+	n := c.replicationFactor()
 	for _, addr := range addrs {
 		wg.Add(1)
 		go func(addr string) {
 			defer wg.Done()
 			r, e := c.doListOne(ctx, "?cloned", addr)
 
-			// Only include repos that belong on addr.
-			if len(r) > 0 {
-				filtered := r[:0]
-				for _, repo := range r {
-					if addrForKey(addrs, repo) == addr {
-						filtered = append(filtered, repo)
+			mu.Lock()
+			defer mu.Unlock()
+			if e != nil {
+				err = e
+			}
+			// Only include repos that belong on addr, and only once if they
+			// are replicated.
+			for _, repo := range r {
+				if seen[repo] {
+					continue
+				}
+				for _, replica := range AddrsForKey(addrs, repo, n) {
+					if replica == addr {
+						seen[repo] = true
+						repos = append(repos, repo)
+						break
 					}
 				}
-				r = filtered
 			}
+		}(addr)
+	}
+	wg.Wait()
+	return repos, err
+}
+
+// DiskUsage returns the disk usage of each gitserver, by address. If some
+// gitservers fail to respond, the disk usage of the others is returned with
+// an error.
+func (c *Client) DiskUsage(ctx context.Context) (map[string]*protocol.DiskUsageResponse, error) {
+	var (
+		wg    sync.WaitGroup
+		mu    sync.Mutex
+		err   error
+		usage = map[string]*protocol.DiskUsageResponse{}
+	)
+	for _, addr := range c.Addrs(ctx) {
+		wg.Add(1)
+		go func(addr string) {
+			defer wg.Done()
+			u, e := c.doDiskUsageOne(ctx, addr)
+
 			mu.Lock()
+			defer mu.Unlock()
 			if e != nil {
 				err = e
+				return
 			}
-			repos = append(repos, r...)
-			mu.Unlock()
+			usage[addr] = u
 		}(addr)
 	}
 	wg.Wait()
-	return repos, err
+	return usage, err
+}
+
+func (c *Client) doDiskUsageOne(ctx context.Context, addr string) (*protocol.DiskUsageResponse, error) {
+	req, err := http.NewRequest("GET", "http://"+addr+"/disk-usage", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.HTTPClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 200))
+		return nil, fmt.Errorf("gitserver %s: disk usage: http status %d: %s", addr, resp.StatusCode, body)
+	}
+
+	var usage protocol.DiskUsageResponse
+	err = json.NewDecoder(resp.Body).Decode(&usage)
+	return &usage, err
 }
 
 // GetGitolitePhabricatorMetadata returns Phabricator metadata for a Gitolite repository fetched via
 // a user-provided command.
 func (c *Client) GetGitolitePhabricatorMetadata(ctx context.Context, gitoliteHost string, repoName api.RepoName) (*protocol.GitolitePhabricatorMetadataResponse, error) {
-	u := "http://" + c.addrForKey(ctx, gitoliteHost) +
+	u := "http://" + AddrsForKey(c.Addrs(ctx), gitoliteHost, 1)[0] +
 		"/getGitolitePhabricatorMetadata?gitolite=" + url.QueryEscape(gitoliteHost) +
 		"&repo=" + url.QueryEscape(string(repoName))
 
@@ -564,11 +746,33 @@ func (c *Client) RequestRepoUpdate(ctx context.Context, repo Repo, since time.Du
 		Repo:  repo.Name,
 		URL:   repo.URL,
 		Since: since,
+		Clone: repo.Clone,
 	}
-	resp, err := c.httpPost(ctx, repo.Name, "repo-update", req)
-	if err != nil {
-		return nil, err
+	if repo.LFS != nil {
+		req.LFS, req.LFSRefs = true, repo.LFS.Refs
 	}
+
+	// The update is sent to all replicas, which also clones the repo on
+	// replicas that don't have it yet. We return the response of the first
+	// replica that was updated, so that an unavailable replica doesn't fail
+	// the update.
+	resps, errs := c.doAll(ctx, repo.Name, "POST", "repo-update", req)
+	infos := make([]*protocol.RepoUpdateResponse, len(errs))
+	for i, resp := range resps {
+		if errs[i] != nil {
+			continue
+		}
+		infos[i], errs[i] = decodeRepoUpdateResponse(resp)
+	}
+	for i, info := range infos {
+		if errs[i] == nil {
+			return info, nil
+		}
+	}
+	return nil, errs[0]
+}
+
+func decodeRepoUpdateResponse(resp *http.Response) (*protocol.RepoUpdateResponse, error) {
 	defer resp.Body.Close()
 	if resp.StatusCode != http.StatusOK {
 		body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 200))
@@ -576,7 +780,7 @@ func (c *Client) RequestRepoUpdate(ctx context.Context, repo Repo, since time.Du
 	}
 
 	var info *protocol.RepoUpdateResponse
-	err = json.NewDecoder(resp.Body).Decode(&info)
+	err := json.NewDecoder(resp.Body).Decode(&info)
 	return info, err
 }
 
@@ -661,29 +865,32 @@ func (c *Client) RepoCloneProgress(ctx context.Context, repos ...api.RepoName) (
 	numPossibleShards := len(c.Addrs(ctx))
 	shards := make(map[string]*protocol.RepoCloneProgressRequest, (len(repos)/numPossibleShards)*2) // 2x because it may not be a perfect division
 
+	// Repos are grouped by all of their replicas, so that every repo of a
+	// shard is on the gitserver the shard's request fails over to.
 	for _, r := range repos {
-		addr := c.AddrForRepo(ctx, r)
-		shard := shards[addr]
+		key := strings.Join(c.AddrsForRepo(ctx, r), ",")
+		shard := shards[key]
 
 		if shard == nil {
 			shard = new(protocol.RepoCloneProgressRequest)
-			shards[addr] = shard
+			shards[key] = shard
 		}
 
 		shard.Repos = append(shard.Repos, r)
 	}
 
 	type op struct {
-		req *protocol.RepoCloneProgressRequest
-		res *protocol.RepoCloneProgressResponse
-		err error
+		addrs []string
+		req   *protocol.RepoCloneProgressRequest
+		res   *protocol.RepoCloneProgressResponse
+		err   error
 	}
 
 	ch := make(chan op, len(shards))
-	for _, req := range shards {
+	for key, req := range shards {
 		go func(o op) {
 			var resp *http.Response
-			resp, o.err = c.httpPost(ctx, o.req.Repos[0], "repo-clone-progress", o.req)
+			resp, o.err = c.doAddrs(ctx, o.addrs, o.req.Repos[0], "POST", "repo-clone-progress", o.req)
 			if o.err != nil {
 				ch <- o
 				return
@@ -703,7 +910,7 @@ func (c *Client) RepoCloneProgress(ctx context.Context, repos ...api.RepoName) (
 			o.res = new(protocol.RepoCloneProgressResponse)
 			o.err = json.NewDecoder(resp.Body).Decode(o.res)
 			ch <- o
-		}(op{req: req})
+		}(op{addrs: strings.Split(key, ","), req: req})
 	}
 
 	err := new(multierror.Error)
@@ -738,29 +945,32 @@ func (c *Client) RepoInfo(ctx context.Context, repos ...api.RepoName) (*protocol
 	numPossibleShards := len(c.Addrs(ctx))
 	shards := make(map[string]*protocol.RepoInfoRequest, (len(repos)/numPossibleShards)*2) // 2x because it may not be a perfect division
 
+	// Repos are grouped by all of their replicas, so that every repo of a
+	// shard is on the gitserver the shard's request fails over to.
 	for _, r := range repos {
-		addr := c.AddrForRepo(ctx, r)
-		shard := shards[addr]
+		key := strings.Join(c.AddrsForRepo(ctx, r), ",")
+		shard := shards[key]
 
 		if shard == nil {
 			shard = new(protocol.RepoInfoRequest)
-			shards[addr] = shard
+			shards[key] = shard
 		}
 
 		shard.Repos = append(shard.Repos, r)
 	}
 
 	type op struct {
-		req *protocol.RepoInfoRequest
-		res *protocol.RepoInfoResponse
-		err error
+		addrs []string
+		req   *protocol.RepoInfoRequest
+		res   *protocol.RepoInfoResponse
+		err   error
 	}
 
 	ch := make(chan op, len(shards))
-	for _, req := range shards {
+	for key, req := range shards {
 		go func(o op) {
 			var resp *http.Response
-			resp, o.err = c.httpPost(ctx, o.req.Repos[0], "repos", o.req)
+			resp, o.err = c.doAddrs(ctx, o.addrs, o.req.Repos[0], "POST", "repos", o.req)
 			if o.err != nil {
 				ch <- o
 				return
@@ -780,7 +990,7 @@ func (c *Client) RepoInfo(ctx context.Context, repos ...api.RepoName) (*protocol
 			o.res = new(protocol.RepoInfoResponse)
 			o.err = json.NewDecoder(resp.Body).Decode(o.res)
 			ch <- o
-		}(op{req: req})
+		}(op{addrs: strings.Split(key, ","), req: req})
 	}
 
 	err := new(multierror.Error)
@@ -804,15 +1014,27 @@ func (c *Client) RepoInfo(ctx context.Context, repos ...api.RepoName) (*protocol
 	return &res, err.ErrorOrNil()
 }
 
-// Remove removes the repository clone from gitserver.
+// Remove removes the repository clone from all gitservers it is replicated
+// on.
 func (c *Client) Remove(ctx context.Context, repo api.RepoName) error {
 	req := &protocol.RepoDeleteRequest{
 		Repo: repo,
 	}
-	resp, err := c.httpPost(ctx, repo, "delete", req)
-	if err != nil {
-		return err
+	var errs *multierror.Error
+	resps, respErrs := c.doAll(ctx, repo, "POST", "delete", req)
+	for i, resp := range resps {
+		if respErrs[i] != nil {
+			errs = multierror.Append(errs, respErrs[i])
+			continue
+		}
+		if err := checkRemoveResponse(resp); err != nil {
+			errs = multierror.Append(errs, err)
+		}
 	}
+	return errs.ErrorOrNil()
+}
+
+func checkRemoveResponse(resp *http.Response) error {
 	defer resp.Body.Close()
 	if resp.StatusCode != http.StatusOK {
 		// best-effort inclusion of body in error message
@@ -828,28 +1050,78 @@ func (c *Client) httpPost(ctx context.Context, repo api.RepoName, op string, pay
 
 // do performs a request to a gitserver, sharding based on the given
 // repo name (the repo name is otherwise not used).
+//
+// If the repo is replicated, the request fails over to the next replica when
+// a gitserver doesn't respond or doesn't have the repo cloned (yet).
 func (c *Client) do(ctx context.Context, repo api.RepoName, method, op string, payload interface{}) (resp *http.Response, err error) {
-	span, ctx := ot.StartSpanFromContext(ctx, "Client.do")
-	defer func() {
-		span.LogKV("repo", string(repo), "method", method, "op", op)
+	return c.doAddrs(ctx, c.AddrsForRepo(ctx, repo), repo, method, op, payload)
+}
+
+// doAddrs performs a request to the first of the gitservers at addrs that
+// responds, failing over like do. The repo name is only used for tracing.
+func (c *Client) doAddrs(ctx context.Context, addrs []string, repo api.RepoName, method, op string, payload interface{}) (resp *http.Response, err error) {
+	reqBody, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, addr := range addrs {
+		resp, err = c.doAddr(ctx, addr, repo, method, op, reqBody)
 		if err != nil {
-			ext.Error.Set(span, true)
-			span.SetTag("err", err.Error())
+			if ctx.Err() != nil {
+				return nil, err
+			}
+			c.markUnhealthy(addr)
+			continue
 		}
-		span.Finish()
-	}()
+		if resp.StatusCode == http.StatusNotFound && i < len(addrs)-1 {
+			resp.Body.Close()
+			continue
+		}
+		return resp, nil
+	}
+	return nil, err
+}
 
+// doAll performs a request on every replica of the given repo concurrently.
+// It returns the responses and errors in the order of the replicas.
+func (c *Client) doAll(ctx context.Context, repo api.RepoName, method, op string, payload interface{}) ([]*http.Response, []error) {
 	reqBody, err := json.Marshal(payload)
 	if err != nil {
-		return nil, err
+		return nil, []error{err}
 	}
 
-	uri := op
-	if !strings.HasPrefix(op, "http") {
-		uri = "http://" + c.AddrForRepo(ctx, repo) + "/" + op
+	addrs := c.replicaAddrs(ctx, repo)
+	resps := make([]*http.Response, len(addrs))
+	errs := make([]error, len(addrs))
+	var wg sync.WaitGroup
+	for i, addr := range addrs {
+		wg.Add(1)
+		go func(i int, addr string) {
+			defer wg.Done()
+			resps[i], errs[i] = c.doAddr(ctx, addr, repo, method, op, reqBody)
+			if errs[i] != nil && ctx.Err() == nil {
+				c.markUnhealthy(addr)
+			}
+		}(i, addr)
 	}
+	wg.Wait()
+	return resps, errs
+}
+
+// doAddr performs a request to the gitserver at addr.
+func (c *Client) doAddr(ctx context.Context, addr string, repo api.RepoName, method, op string, reqBody []byte) (resp *http.Response, err error) {
+	span, ctx := ot.StartSpanFromContext(ctx, "Client.do")
+	defer func() {
+		span.LogKV("repo", string(repo), "addr", addr, "method", method, "op", op)
+		if err != nil {
+			ext.Error.Set(span, true)
+			span.SetTag("err", err.Error())
+		}
+		span.Finish()
+	}()
 
-	req, err := http.NewRequest(method, uri, bytes.NewReader(reqBody))
+	req, err := http.NewRequest(method, "http://"+addr+"/"+op, bytes.NewReader(reqBody))
 	if err != nil {
 		return nil, err
 	}
@@ -5,6 +5,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -14,6 +15,7 @@ import (
 	"os/exec"
 	"path/filepath"
 	"sort"
+	"sync"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -22,6 +24,7 @@ import (
 	"github.com/sourcegraph/sourcegraph/cmd/gitserver/server"
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
 	"github.com/sourcegraph/sourcegraph/internal/httpcli"
 )
 
@@ -57,6 +60,30 @@ func TestClient_ListCloned(t *testing.T) {
 	}
 }
 
+func TestClient_ListCloned_Replication(t *testing.T) {
+	addrs := []string{"gitserver-0", "gitserver-1", "gitserver-2"}
+	repos := []string{"a", "b", "c", "d"}
+	cli := &gitserver.Client{
+		Addrs:             func(ctx context.Context) []string { return addrs },
+		ReplicationFactor: func() int { return 2 },
+		HTTPClient: httpcli.DoerFunc(func(r *http.Request) (*http.Response, error) {
+			// Every gitserver has every repo cloned, but only the replicas
+			// of a repo should report it.
+			b, _ := json.Marshal(repos)
+			return &http.Response{Body: ioutil.NopCloser(bytes.NewReader(b))}, nil
+		}),
+	}
+
+	got, err := cli.ListCloned(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(got)
+	if !cmp.Equal(repos, got) {
+		t.Errorf("mismatch for (-want +got):\n%s", cmp.Diff(repos, got))
+	}
+}
+
 func TestClient_Archive(t *testing.T) {
 	root, err := ioutil.TempDir("", t.Name())
 	if err != nil {
@@ -148,6 +175,169 @@ func TestClient_Archive(t *testing.T) {
 	}
 }
 
+func TestClient_Replication(t *testing.T) {
+	root, err := ioutil.TempDir("", t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+
+	var addrs []string
+	srvs := map[string]*httptest.Server{}
+	reposDirs := map[string]string{}
+	for i := 0; i < 3; i++ {
+		reposDir := filepath.Join(root, fmt.Sprintf("repos-%d", i))
+		srv := httptest.NewServer((&server.Server{ReposDir: reposDir}).Handler())
+		defer srv.Close()
+
+		u, _ := url.Parse(srv.URL)
+		addrs = append(addrs, u.Host)
+		srvs[u.Host] = srv
+		reposDirs[u.Host] = reposDir
+	}
+
+	cli := gitserver.NewClient(&http.Client{})
+	cli.Addrs = func(context.Context) []string { return addrs }
+	cli.ReplicationFactor = func() int { return 2 }
+
+	ctx := context.Background()
+	repo := gitserver.Repo{Name: "simple", URL: createSimpleGitRepo(t, root)}
+	replicas := gitserver.AddrsForKey(addrs, string(repo.Name), 2)
+
+	cloned := func(addr string) bool {
+		_, err := os.Stat(filepath.Join(reposDirs[addr], string(repo.Name), ".git", "HEAD"))
+		return err == nil
+	}
+
+	// Updates are sent to all replicas, which clones the repo on them.
+	if _, err := cli.RequestRepoUpdate(ctx, repo, 0); err != nil {
+		t.Fatal(err)
+	}
+	for _, addr := range addrs {
+		want := addr == replicas[0] || addr == replicas[1]
+		if have := cloned(addr); have != want {
+			t.Errorf("repo cloned on %s: have %v, want %v", addr, have, want)
+		}
+	}
+
+	// Reads fail over to the other replica when the primary is down.
+	srvs[replicas[0]].Close()
+
+	if have, want := cli.ArchiveURL(ctx, repo, gitserver.ArchiveOptions{Treeish: "HEAD", Format: "tar"}).Host, replicas[1]; have != want {
+		t.Errorf("expected archive URL to point at the available replica: have %s, want %s", have, want)
+	}
+
+	cmd := cli.Command("git", "log", "--format=%s")
+	cmd.Repo = repo
+	out, err := cmd.Output(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if have, want := string(out), "commit2\ncommit1\n"; have != want {
+		t.Errorf("git log: have %q, want %q", have, want)
+	}
+	if have, want := cli.AddrForRepo(ctx, repo.Name), replicas[1]; have != want {
+		t.Errorf("expected unavailable primary to be tried last: have %s, want %s", have, want)
+	}
+	if ok, err := cli.IsRepoCloned(ctx, repo.Name); err != nil || !ok {
+		t.Errorf("IsRepoCloned: have %v, %v, want true", ok, err)
+	}
+
+	// Removing the repo removes it from all available replicas.
+	if err := cli.Remove(ctx, repo.Name); err == nil {
+		t.Error("expected Remove to report the unavailable replica")
+	}
+	if cloned(replicas[1]) {
+		t.Error("expected repo to be removed from replica")
+	}
+}
+
+func TestClient_RepoInfo_Replication(t *testing.T) {
+	addrs := []string{"gitserver-0", "gitserver-1", "gitserver-2"}
+	var repos []api.RepoName
+	for i := 0; i < 20; i++ {
+		repos = append(repos, api.RepoName(fmt.Sprintf("github.com/foo/repo-%d", i)))
+	}
+
+	var (
+		mu   sync.Mutex
+		down string
+	)
+	cli := &gitserver.Client{
+		Addrs:             func(ctx context.Context) []string { return addrs },
+		ReplicationFactor: func() int { return 2 },
+		HTTPClient: httpcli.DoerFunc(func(r *http.Request) (*http.Response, error) {
+			mu.Lock()
+			isDown := r.URL.Host == down
+			mu.Unlock()
+			if isDown {
+				return nil, errors.New("connection refused")
+			}
+
+			var req protocol.RepoInfoRequest
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				return nil, err
+			}
+			res := protocol.RepoInfoResponse{Results: map[api.RepoName]*protocol.RepoInfo{}}
+			for _, repo := range req.Repos {
+				replicas := gitserver.AddrsForKey(addrs, string(repo), 2)
+				cloned := r.URL.Host == replicas[0] || r.URL.Host == replicas[1]
+				if !cloned {
+					mu.Lock()
+					t.Errorf("%s was sent to %s, which is not one of its replicas %v", repo, r.URL.Host, replicas)
+					mu.Unlock()
+				}
+				res.Results[repo] = &protocol.RepoInfo{Cloned: cloned}
+			}
+			body, _ := json.Marshal(res)
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ioutil.NopCloser(bytes.NewReader(body)),
+			}, nil
+		}),
+	}
+
+	// Once a gitserver failed to respond, it is tried last, which changes
+	// the order in which the replicas of its repos are tried. Requests then
+	// fail over to a gitserver that has all of the requested repos, even if
+	// the first replica of some of them is down.
+	for _, addr := range addrs[1:] {
+		mu.Lock()
+		down = addr
+		mu.Unlock()
+
+		res, err := cli.RepoInfo(context.Background(), repos...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, repo := range repos {
+			if info := res.Results[repo]; info == nil || !info.Cloned {
+				t.Errorf("%s is down: %s expected to be reported as cloned by an available replica, got %+v", addr, repo, info)
+			}
+		}
+	}
+}
+
+func TestAddrsForKey(t *testing.T) {
+	addrs := []string{"gitserver-0", "gitserver-1", "gitserver-2"}
+	for _, key := range []string{"a", "b", "c", "github.com/foo/bar"} {
+		primary := gitserver.AddrsForKey(addrs, key, 1)
+		replicas := gitserver.AddrsForKey(addrs, key, 2)
+		if len(primary) != 1 || len(replicas) != 2 {
+			t.Fatalf("unexpected number of addresses: %v, %v", primary, replicas)
+		}
+		if primary[0] != replicas[0] {
+			t.Errorf("%s: expected replication to keep the primary %s, got %v", key, primary[0], replicas)
+		}
+		if replicas[0] == replicas[1] {
+			t.Errorf("%s: expected distinct replicas, got %v", key, replicas)
+		}
+		if all := gitserver.AddrsForKey(addrs, key, 5); len(all) != len(addrs) {
+			t.Errorf("%s: expected replication factor to be capped at %d, got %v", key, len(addrs), all)
+		}
+	}
+}
+
 func createRepoWithDotGitDir(t *testing.T, root string) string {
 	t.Helper()
 	b64 := func(s string) string {
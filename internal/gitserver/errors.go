@@ -3,6 +3,7 @@ package gitserver
 import (
 	"fmt"
 
+	"github.com/pkg/errors"
 	"github.com/sourcegraph/sourcegraph/internal/api"
 )
 
@@ -25,3 +26,25 @@ func IsRevisionNotFound(err error) bool {
 	_, ok := err.(*RevisionNotFoundError)
 	return ok
 }
+
+// HistoryUnavailableError is an error that reports a command needs history of
+// a repository that gitserver did not clone, because the repository is
+// configured to be cloned shallowly.
+type HistoryUnavailableError struct {
+	Repo   api.RepoName
+	Reason string
+}
+
+func (e *HistoryUnavailableError) Error() string {
+	return fmt.Sprintf("history unavailable: %s: %s", e.Repo, e.Reason)
+}
+
+func (e *HistoryUnavailableError) HTTPStatusCode() int {
+	return 422
+}
+
+// IsHistoryUnavailable reports if err is (or wraps) a HistoryUnavailableError.
+func IsHistoryUnavailable(err error) bool {
+	_, ok := errors.Cause(err).(*HistoryUnavailableError)
+	return ok
+}
@@ -0,0 +1,200 @@
+package gitserver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+
+	"github.com/opentracing/opentracing-go/ext"
+	"github.com/sourcegraph/sourcegraph/internal/api"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
+	"github.com/sourcegraph/sourcegraph/internal/trace/ot"
+	"github.com/sourcegraph/sourcegraph/internal/vcs"
+)
+
+// This file contains the client methods of the typed git endpoints (see
+// protocol.GitRequest). Their errors are converted to the error types used by
+// the rest of the client:
+//
+// * Repository not cloned: vcs.RepoNotExistError
+// * Revision not found: RevisionNotFoundError
+// * Path not found: an *os.PathError wrapping os.ErrNotExist
+// * History of a shallow clone needed: HistoryUnavailableError
+// * Invalid request: an error with a BadRequest method
+
+// ResolveRevision returns the commit ID req.Spec resolves to.
+func (c *Client) ResolveRevision(ctx context.Context, req *protocol.ResolveRevisionRequest) (api.CommitID, error) {
+	var resp protocol.ResolveRevisionResponse
+	if err := c.gitOpJSON(ctx, "resolve-revision", req, &resp); err != nil {
+		return "", gitOpError(req, err, req.Spec, "")
+	}
+	return resp.CommitID, nil
+}
+
+// ReadBlob returns a reader of the contents of the file req.Path at
+// req.Commit. The caller must close it.
+func (c *Client) ReadBlob(ctx context.Context, req *protocol.ReadBlobRequest) (io.ReadCloser, error) {
+	rc, err := c.gitOpReader(ctx, "blob", req)
+	if err != nil {
+		return nil, gitOpError(req, err, string(req.Commit), req.Path)
+	}
+	return rc, nil
+}
+
+// ListTree returns the entries of the tree at req.Path at req.Commit.
+func (c *Client) ListTree(ctx context.Context, req *protocol.ListTreeRequest) ([]*protocol.TreeEntry, error) {
+	var resp protocol.ListTreeResponse
+	if err := c.gitOpJSON(ctx, "tree", req, &resp); err != nil {
+		return nil, gitOpError(req, err, string(req.Commit), req.Path)
+	}
+	return resp.Entries, nil
+}
+
+// Log returns the commits matching req.
+func (c *Client) Log(ctx context.Context, req *protocol.LogRequest) ([]*protocol.Commit, error) {
+	var resp protocol.LogResponse
+	if err := c.gitOpJSON(ctx, "log", req, &resp); err != nil {
+		return nil, gitOpError(req, err, req.Range, req.Path)
+	}
+	return resp.Commits, nil
+}
+
+// Diff returns a reader of the diff between req.Base and req.Head in unified
+// format. The caller must close it.
+func (c *Client) Diff(ctx context.Context, req *protocol.DiffRequest) (io.ReadCloser, error) {
+	rc, err := c.gitOpReader(ctx, "diff", req)
+	if err != nil {
+		return nil, gitOpError(req, err, req.Base+"..."+req.Head, "")
+	}
+	return rc, nil
+}
+
+// Blame returns the blame hunks of the file req.Path.
+func (c *Client) Blame(ctx context.Context, req *protocol.BlameRequest) ([]*protocol.Hunk, error) {
+	var resp protocol.BlameResponse
+	if err := c.gitOpJSON(ctx, "blame", req, &resp); err != nil {
+		return nil, gitOpError(req, err, string(req.NewestCommit), req.Path)
+	}
+	return resp.Hunks, nil
+}
+
+// MergeBase returns the merge base of req.A and req.B.
+func (c *Client) MergeBase(ctx context.Context, req *protocol.MergeBaseRequest) (api.CommitID, error) {
+	var resp protocol.MergeBaseResponse
+	if err := c.gitOpJSON(ctx, "merge-base", req, &resp); err != nil {
+		return "", gitOpError(req, err, string(req.A)+"..."+string(req.B), "")
+	}
+	return resp.CommitID, nil
+}
+
+// gitOpJSON performs the typed git request op and decodes the response into
+// v.
+func (c *Client) gitOpJSON(ctx context.Context, op string, req protocol.GitRequest, v interface{}) error {
+	resp, err := c.gitOp(ctx, op, req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// gitOpReader performs the typed git request op and returns a reader of the
+// streamed response body. Errors which happen while streaming are returned by
+// Read.
+func (c *Client) gitOpReader(ctx context.Context, op string, req protocol.GitRequest) (io.ReadCloser, error) {
+	resp, err := c.gitOp(ctx, op, req)
+	if err != nil {
+		return nil, err
+	}
+	return &gitOpReader{resp: resp}, nil
+}
+
+// gitOp performs the typed git request op and returns the successful
+// response. If it fails, the error is a *protocol.GitError, or a transport
+// error.
+func (c *Client) gitOp(ctx context.Context, op string, req protocol.GitRequest) (_ *http.Response, err error) {
+	repo := protocol.NormalizeRepo(req.Repository().Repo)
+
+	span, ctx := ot.StartSpanFromContext(ctx, "Client.gitOp")
+	defer func() {
+		if err != nil {
+			ext.Error.Set(span, true)
+			span.SetTag("err", err.Error())
+		}
+		span.Finish()
+	}()
+	span.SetTag("op", op)
+	span.SetTag("repo", repo)
+
+	// Check that ctx is not expired.
+	if err := ctx.Err(); err != nil {
+		deadlineExceededCounter.Inc()
+		return nil, err
+	}
+
+	resp, err := c.httpPost(ctx, repo, op, req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusOK {
+		return resp, nil
+	}
+	defer resp.Body.Close()
+
+	var gitErr protocol.GitError
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(body, &gitErr); err != nil || gitErr.Code == "" {
+		return nil, fmt.Errorf("unexpected status code: %d (body: %q)", resp.StatusCode, body)
+	}
+	return nil, &gitErr
+}
+
+// gitOpReader reads a streamed typed git response, and returns the error of
+// the X-Git-Error trailer at the end of the body.
+type gitOpReader struct {
+	resp *http.Response
+}
+
+func (r *gitOpReader) Read(p []byte) (int, error) {
+	n, err := r.resp.Body.Read(p)
+	if err == io.EOF {
+		if msg := r.resp.Trailer.Get("X-Git-Error"); msg != "" {
+			return n, fmt.Errorf("gitserver %s: %s", r.resp.Request.URL.Path, msg)
+		}
+	}
+	return n, err
+}
+
+func (r *gitOpReader) Close() error { return r.resp.Body.Close() }
+
+// gitOpError converts err of the request req to the errors used by the
+// client. spec and path are the revision and the path of the request, for
+// error messages.
+func gitOpError(req protocol.GitRequest, err error, spec, path string) error {
+	e, ok := err.(*protocol.GitError)
+	if !ok {
+		return err
+	}
+	repo := protocol.NormalizeRepo(req.Repository().Repo)
+	switch e.Code {
+	case protocol.GitErrorRepoNotFound:
+		return &vcs.RepoNotExistError{Repo: repo, CloneInProgress: e.CloneInProgress, CloneProgress: e.CloneProgress}
+	case protocol.GitErrorRevisionNotFound:
+		return &RevisionNotFoundError{Repo: repo, Spec: spec}
+	case protocol.GitErrorPathNotFound:
+		return &os.PathError{Op: "open", Path: path, Err: os.ErrNotExist}
+	case protocol.GitErrorHistoryUnavailable:
+		return &HistoryUnavailableError{Repo: repo, Reason: e.Message}
+	case protocol.GitErrorBadRequest:
+		return &badRequestError{error: e}
+	default:
+		return e
+	}
+}
@@ -0,0 +1,281 @@
+package protocol
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/sourcegraph/sourcegraph/internal/api"
+)
+
+// This file contains the requests and responses of the typed git endpoints of
+// gitserver. Unlike ExecRequest, which passes git arguments through, they
+// describe the operation, and gitserver builds the git command itself.
+
+// GitRequest is implemented by the requests of the typed git endpoints.
+type GitRequest interface {
+	// Repository returns the repository the request is for.
+	Repository() GitRepo
+
+	// EnsureRevision returns the revision the operation needs. If the
+	// operation fails because the revision is missing and the request has a
+	// remote URL, gitserver fetches the repository and retries.
+	EnsureRevision() string
+}
+
+// GitRepo identifies the repository of a typed git request.
+type GitRepo struct {
+	Repo api.RepoName `json:"repo"`
+
+	// URL is the repository's Git remote URL. If the gitserver already has cloned the repository,
+	// this field is optional (it will use the last-used Git remote URL). If it is set, gitserver
+	// clones the repository if needed, and fetches it if the revision the request needs is missing.
+	URL string `json:"url,omitempty"`
+}
+
+// Repository implements GitRequest.
+func (r GitRepo) Repository() GitRepo { return r }
+
+// ResolveRevisionRequest is a request to resolve a revision to a commit ID.
+type ResolveRevisionRequest struct {
+	GitRepo
+
+	// Spec is the revision to resolve. If empty, HEAD is resolved.
+	Spec string `json:"spec"`
+}
+
+// EnsureRevision implements GitRequest.
+func (r *ResolveRevisionRequest) EnsureRevision() string { return r.Spec }
+
+// ResolveRevisionResponse is the response to a ResolveRevisionRequest.
+type ResolveRevisionResponse struct {
+	CommitID api.CommitID `json:"commitID"`
+}
+
+// ReadBlobRequest is a request to read the contents of a file at a commit.
+// The response body is the file contents.
+type ReadBlobRequest struct {
+	GitRepo
+
+	Commit api.CommitID `json:"commit"` // an absolute commit ID
+	Path   string       `json:"path"`   // the path of the file, relative to the repository root
+
+	// MaxBytes limits the response to the first MaxBytes bytes of the file.
+	// If zero or negative, the whole file is returned.
+	MaxBytes int64 `json:"maxBytes,omitempty"`
+}
+
+// EnsureRevision implements GitRequest.
+func (r *ReadBlobRequest) EnsureRevision() string { return string(r.Commit) }
+
+// ListTreeRequest is a request to list the entries of a tree at a commit.
+type ListTreeRequest struct {
+	GitRepo
+
+	Commit api.CommitID `json:"commit"` // an absolute commit ID
+
+	// Path is the path to list, relative to the repository root. With a
+	// trailing slash, the entries of the tree at Path are listed instead of
+	// the entry of Path itself. If empty, the root tree is listed.
+	Path string `json:"path,omitempty"`
+
+	// Recursive is whether to list the entries of subtrees too.
+	Recursive bool `json:"recursive,omitempty"`
+}
+
+// EnsureRevision implements GitRequest.
+func (r *ListTreeRequest) EnsureRevision() string { return string(r.Commit) }
+
+// ListTreeResponse is the response to a ListTreeRequest.
+type ListTreeResponse struct {
+	Entries []*TreeEntry `json:"entries"`
+}
+
+// TreeEntry is an entry of a git tree.
+type TreeEntry struct {
+	Path string `json:"path"` // full path relative to the repository root
+	Mode uint32 `json:"mode"` // the git file mode, e.g. 0100644
+	Type string `json:"type"` // "blob", "tree" or "commit" (for submodules)
+	OID  string `json:"oid"`  // the object ID
+
+	// Size is the size of blobs in bytes. It is zero for other entries.
+	Size int64 `json:"size,omitempty"`
+
+	// Submodule is set for submodule entries. Its fields are empty if the
+	// submodule is not configured in the .gitmodules file at the commit.
+	Submodule *Submodule `json:"submodule,omitempty"`
+}
+
+// Submodule is the configuration of a git submodule.
+type Submodule struct {
+	Path string `json:"path"` // the path to which the submodule is checked out
+	URL  string `json:"url"`  // the remote repository URL of the submodule
+}
+
+// LogRequest is a request for the commits of a repository, in the order of
+// git log.
+type LogRequest struct {
+	GitRepo
+
+	Range string `json:"range,omitempty"` // commit range (revspec, "A..B", "A...B", etc.)
+
+	N    uint `json:"n,omitempty"`    // limit the number of returned commits to this many (0 means no limit)
+	Skip uint `json:"skip,omitempty"` // skip this many commits at the beginning
+
+	MessageQuery string `json:"messageQuery,omitempty"` // include only commits whose commit message contains this substring
+	Author       string `json:"author,omitempty"`       // include only commits whose author matches this
+	After        string `json:"after,omitempty"`        // include only commits after this date
+	Path         string `json:"path,omitempty"`         // include only commits modifying this path
+}
+
+// EnsureRevision implements GitRequest.
+func (r *LogRequest) EnsureRevision() string { return r.Range }
+
+// LogResponse is the response to a LogRequest.
+type LogResponse struct {
+	Commits []*Commit `json:"commits"`
+}
+
+// Commit is a git commit.
+type Commit struct {
+	ID        api.CommitID `json:"ID,omitempty"`
+	Author    Signature    `json:"Author"`
+	Committer *Signature   `json:"Committer,omitempty"`
+	Message   string       `json:"Message,omitempty"`
+	// Parents are the commit IDs of this commit's parent commits.
+	Parents []api.CommitID `json:"Parents,omitempty"`
+}
+
+// Signature is the author or committer of a commit.
+type Signature struct {
+	Name  string    `json:"Name,omitempty"`
+	Email string    `json:"Email,omitempty"`
+	Date  time.Time `json:"Date"`
+}
+
+// DiffRequest is a request for the diff between two revisions. The response
+// body is the diff in unified format.
+type DiffRequest struct {
+	GitRepo
+
+	Base string `json:"base"` // the base revision
+	Head string `json:"head"` // the head revision
+
+	// MergeBase is whether to diff Head against the merge base of Base and
+	// Head (like "git diff Base...Head") instead of against Base.
+	MergeBase bool `json:"mergeBase,omitempty"`
+
+	// Paths limits the diff to these paths, if nonempty.
+	Paths []string `json:"paths,omitempty"`
+
+	FindRenames      bool `json:"findRenames,omitempty"`      // detect renames and copies
+	FullIndex        bool `json:"fullIndex,omitempty"`        // show full object IDs in index lines
+	InterHunkContext int  `json:"interHunkContext,omitempty"` // merge hunks at most this many lines apart
+	NoPrefix         bool `json:"noPrefix,omitempty"`         // omit the a/ and b/ path prefixes
+}
+
+// EnsureRevision implements GitRequest.
+func (r *DiffRequest) EnsureRevision() string { return r.Head }
+
+// BlameRequest is a request to blame a file.
+type BlameRequest struct {
+	GitRepo
+
+	Path         string       `json:"path"`
+	NewestCommit api.CommitID `json:"newestCommit,omitempty"` // or "" for HEAD
+
+	StartLine int `json:"startLine,omitempty"` // 1-indexed start line (or 0 for beginning of file)
+	EndLine   int `json:"endLine,omitempty"`   // 1-indexed end line (or 0 for end of file)
+}
+
+// EnsureRevision implements GitRequest.
+func (r *BlameRequest) EnsureRevision() string { return string(r.NewestCommit) }
+
+// BlameResponse is the response to a BlameRequest.
+type BlameResponse struct {
+	Hunks []*Hunk `json:"hunks"`
+}
+
+// A Hunk is a contiguous portion of a file associated with a commit.
+type Hunk struct {
+	StartLine int // 1-indexed start line number
+	EndLine   int // 1-indexed end line number
+	StartByte int // 0-indexed start byte position (inclusive)
+	EndByte   int // 0-indexed end byte position (exclusive)
+	api.CommitID
+	Author  Signature
+	Message string
+}
+
+// MergeBaseRequest is a request for the merge base of two commits.
+type MergeBaseRequest struct {
+	GitRepo
+
+	A api.CommitID `json:"a"`
+	B api.CommitID `json:"b"`
+}
+
+// EnsureRevision implements GitRequest.
+func (r *MergeBaseRequest) EnsureRevision() string { return string(r.B) }
+
+// MergeBaseResponse is the response to a MergeBaseRequest.
+type MergeBaseResponse struct {
+	CommitID api.CommitID `json:"commitID"`
+}
+
+// GitErrorCode identifies the kind of a GitError.
+type GitErrorCode string
+
+const (
+	// GitErrorRepoNotFound means the repository is not cloned (yet).
+	GitErrorRepoNotFound GitErrorCode = "RepoNotFound"
+
+	// GitErrorRevisionNotFound means a revision of the request doesn't
+	// exist in the repository.
+	GitErrorRevisionNotFound GitErrorCode = "RevisionNotFound"
+
+	// GitErrorPathNotFound means a path of the request doesn't exist at the
+	// commit.
+	GitErrorPathNotFound GitErrorCode = "PathNotFound"
+
+	// GitErrorHistoryUnavailable means the operation needs history which
+	// the repository lacks, because it is cloned shallowly.
+	GitErrorHistoryUnavailable GitErrorCode = "HistoryUnavailable"
+
+	// GitErrorBadRequest means the request is invalid.
+	GitErrorBadRequest GitErrorCode = "BadRequest"
+
+	// GitErrorInternal is any other error, such as an unexpected git
+	// failure.
+	GitErrorInternal GitErrorCode = "Internal"
+)
+
+// HTTPStatus returns the HTTP status code of responses with errors of code
+// c. Missing repositories, revisions and paths are reported with 404, so
+// that clients try the other replicas of the repository.
+func (c GitErrorCode) HTTPStatus() int {
+	switch c {
+	case GitErrorRepoNotFound, GitErrorRevisionNotFound, GitErrorPathNotFound:
+		return http.StatusNotFound
+	case GitErrorBadRequest:
+		return http.StatusBadRequest
+	case GitErrorHistoryUnavailable:
+		return http.StatusUnprocessableEntity
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// GitError is the response body of a failed typed git request.
+type GitError struct {
+	Code    GitErrorCode `json:"code"`
+	Message string       `json:"message"`
+
+	// CloneInProgress and CloneProgress are set for GitErrorRepoNotFound
+	// errors if the repository is being cloned.
+	CloneInProgress bool   `json:"cloneInProgress,omitempty"`
+	CloneProgress   string `json:"cloneProgress,omitempty"`
+}
+
+func (e *GitError) Error() string {
+	return string(e.Code) + ": " + e.Message
+}
@@ -48,6 +48,31 @@ type RepoUpdateRequest struct {
 	Repo  api.RepoName  `json:"repo"`  // identifying URL for repo
 	URL   string        `json:"url"`   // repo's remote URL
 	Since time.Duration `json:"since"` // debounce interval for queries, used only with request-repo-update
+	LFS   bool          `json:"lfs"`   // whether to fetch Git LFS objects
+
+	// LFSRefs are the refs whose Git LFS objects are fetched, or nil to
+	// fetch the objects of HEAD.
+	LFSRefs []string `json:"lfsRefs,omitempty"`
+
+	// Clone is how to clone and fetch the repo, or nil to clone the whole
+	// history. If the repo was cloned with another mode, it is recloned.
+	Clone *CloneStrategy `json:"clone,omitempty"`
+}
+
+const (
+	// CloneModeShallow clones and fetches only the most recent history.
+	CloneModeShallow = "shallow"
+	// CloneModeBlobless clones and fetches all commits and trees, and fetches
+	// blobs only when they are needed.
+	CloneModeBlobless = "blobless"
+)
+
+// CloneStrategy configures how gitserver clones and fetches a very large
+// repository.
+type CloneStrategy struct {
+	Mode  string `json:"mode"`            // CloneModeShallow or CloneModeBlobless
+	Depth int    `json:"depth,omitempty"` // for shallow clones, the number of commits to fetch
+	Since string `json:"since,omitempty"` // for shallow clones, only fetch history after this date (YYYY-MM-DD)
 }
 
 // RepoUpdateResponse returns meta information of the repo enqueued for
@@ -68,6 +93,12 @@ type RepoUpdateResponse struct {
 	Finished *time.Time // time request completed
 }
 
+// HistoryUnavailablePayload is the response body of an exec request for a
+// command which needs history that the repository was not cloned with.
+type HistoryUnavailablePayload struct {
+	Reason string `json:"reason"`
+}
+
 type NotFoundPayload struct {
 	CloneInProgress bool `json:"cloneInProgress"` // If true, exec returned with noop because clone is in progress.
 
@@ -131,6 +162,16 @@ type RepoInfoResponse struct {
 	Results map[api.RepoName]*RepoInfo
 }
 
+// DiskUsageResponse is the response of a gitserver to a disk usage request.
+type DiskUsageResponse struct {
+	DiskSizeBytes uint64 // size of the disk the repositories are stored on
+	FreeBytes     uint64 // free space on the disk
+
+	// Repos maps the repositories on the gitserver to their size in bytes.
+	// Repositories whose size wasn't computed yet are missing.
+	Repos map[api.RepoName]int64
+}
+
 // RepoCloneProgressRequest is a request for information about the clone progress of multiple
 // repositories on gitserver.
 type RepoCloneProgressRequest struct {
@@ -0,0 +1,73 @@
+// Package lfs contains helpers for Git LFS (Large File Storage) pointer files.
+//
+// See https://github.com/git-lfs/git-lfs/blob/master/docs/spec.md.
+package lfs
+
+import (
+	"bytes"
+	"regexp"
+	"strconv"
+)
+
+// MaxPointerSize is the maximum size of a pointer file. Blobs which are
+// larger can't be pointers.
+const MaxPointerSize = 1024
+
+// Pointer is a Git LFS pointer file, which is stored in git in place of the
+// contents of a file tracked by Git LFS.
+type Pointer struct {
+	// OID is the SHA-256 hash of the file contents, as a hex string.
+	OID string
+
+	// Size is the size of the file contents in bytes.
+	Size int64
+}
+
+const pointerVersion = "version https://git-lfs.github.com/spec/v1"
+
+var (
+	oidRe  = regexp.MustCompile(`^oid sha256:([0-9a-f]{64})$`)
+	sizeRe = regexp.MustCompile(`^size ([0-9]+)$`)
+
+	oidHexRe = regexp.MustCompile(`^[0-9a-f]{64}$`)
+)
+
+// ParsePointer parses data as a pointer file. It returns false if data is not
+// a valid pointer file.
+func ParsePointer(data []byte) (*Pointer, bool) {
+	if len(data) > MaxPointerSize || !bytes.HasPrefix(data, []byte(pointerVersion+"\n")) {
+		return nil, false
+	}
+
+	var (
+		p       Pointer
+		hasSize bool
+	)
+	for _, line := range bytes.Split(bytes.TrimSuffix(data, []byte("\n")), []byte("\n"))[1:] {
+		if m := oidRe.FindSubmatch(line); m != nil {
+			p.OID = string(m[1])
+		} else if m := sizeRe.FindSubmatch(line); m != nil {
+			size, err := strconv.ParseInt(string(m[1]), 10, 64)
+			if err != nil {
+				return nil, false
+			}
+			p.Size, hasSize = size, true
+		}
+		// Other keys are extensions, which we ignore.
+	}
+	if p.OID == "" || !hasSize {
+		return nil, false
+	}
+	return &p, true
+}
+
+// ValidOID reports whether oid is a valid Git LFS object ID, which is a
+// lowercase hex encoded SHA-256 hash.
+func ValidOID(oid string) bool {
+	return oidHexRe.MatchString(oid)
+}
+
+// String returns the pointer file contents for p.
+func (p *Pointer) String() string {
+	return pointerVersion + "\noid sha256:" + p.OID + "\nsize " + strconv.FormatInt(p.Size, 10) + "\n"
+}
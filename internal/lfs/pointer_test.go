@@ -0,0 +1,61 @@
+package lfs
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParsePointer(t *testing.T) {
+	const oid = "4d7a214614ab2935c943f9e0ff69d22eadbb8f32b1258daaa5e2ca24d17e2393"
+	tests := []struct {
+		name string
+		data string
+		want *Pointer
+	}{
+		{
+			name: "pointer",
+			data: "version https://git-lfs.github.com/spec/v1\noid sha256:" + oid + "\nsize 12345\n",
+			want: &Pointer{OID: oid, Size: 12345},
+		},
+		{
+			name: "pointer with extension",
+			data: "version https://git-lfs.github.com/spec/v1\next-0-foo sha256:" + oid + "\noid sha256:" + oid + "\nsize 1\n",
+			want: &Pointer{OID: oid, Size: 1},
+		},
+		{
+			name: "missing size",
+			data: "version https://git-lfs.github.com/spec/v1\noid sha256:" + oid + "\n",
+		},
+		{
+			name: "bad oid",
+			data: "version https://git-lfs.github.com/spec/v1\noid sha256:abc\nsize 1\n",
+		},
+		{
+			name: "not a pointer",
+			data: "package main\n",
+		},
+		{
+			name: "too large",
+			data: "version https://git-lfs.github.com/spec/v1\noid sha256:" + oid + "\nsize 1\n" + strings.Repeat("x", MaxPointerSize),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, ok := ParsePointer([]byte(test.data))
+			if ok != (test.want != nil) {
+				t.Fatalf("got ok %v, want %v", ok, test.want != nil)
+			}
+			if ok && *got != *test.want {
+				t.Errorf("got %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestPointer_String(t *testing.T) {
+	p := &Pointer{OID: strings.Repeat("a", 64), Size: 42}
+	got, ok := ParsePointer([]byte(p.String()))
+	if !ok || *got != *p {
+		t.Errorf("round trip of %+v failed: got %+v", p, got)
+	}
+}
@@ -24,9 +24,61 @@ type LSIFUpload struct {
 	PlaceInQueue      *int32     `json:"placeInQueue"`
 }
 
+type LSIFUploadRetention struct {
+	Upload          LSIFUpload `json:"upload"`
+	RetainedBecause []string   `json:"retainedBecause"`
+	Expired         bool       `json:"expired"`
+}
+
+type LSIFUploadDiagnostic struct {
+	Severity  string `json:"severity"`
+	Code      string `json:"code"`
+	Message   string `json:"message"`
+	ElementID string `json:"elementId"`
+	Line      int32  `json:"line"`
+}
+
+type LSIFIndex struct {
+	ID             int64      `json:"id"`
+	RepositoryID   api.RepoID `json:"repositoryId"`
+	Commit         string     `json:"commit"`
+	Indexer        string     `json:"indexer"`
+	State          string     `json:"state"`
+	QueuedAt       time.Time  `json:"queuedAt"`
+	StartedAt      *time.Time `json:"startedAt"`
+	FinishedAt     *time.Time `json:"finishedAt"`
+	FailureSummary *string    `json:"failureSummary"`
+	Log            *string    `json:"log"`
+	UploadID       *int64     `json:"uploadId"`
+}
+
 type LSIFLocation struct {
 	RepositoryID api.RepoID `json:"repositoryId"`
 	Commit       string     `json:"commit"`
 	Path         string     `json:"path"`
 	Range        lsp.Range  `json:"range"`
 }
+
+type LSIFCodeIntelligenceRange struct {
+	Range          lsp.Range       `json:"range"`
+	HoverText      string          `json:"hoverText"`
+	Definitions    []*LSIFLocation `json:"definitions"`
+	ReferenceCount int32           `json:"referenceCount"`
+}
+
+type LSIFSymbol struct {
+	Name     string         `json:"name"`
+	Detail   string         `json:"detail"`
+	Kind     lsp.SymbolKind `json:"kind"`
+	Range    lsp.Range      `json:"range"`
+	Children []LSIFSymbol   `json:"children"`
+}
+
+// LSIFSymbolLocation is a symbol found by a search of the symbols of the uploads of a commit.
+type LSIFSymbolLocation struct {
+	Name          string         `json:"name"`
+	Kind          lsp.SymbolKind `json:"kind"`
+	ContainerName string         `json:"containerName"`
+	Path          string         `json:"path"`
+	Range         lsp.Range      `json:"range"`
+}
@@ -26,6 +26,10 @@ type RepoScheduleState struct {
 	Total           int
 	IntervalSeconds int
 	Due             time.Time
+
+	LastFetched         *time.Time `json:",omitempty"`
+	LastError           string     `json:",omitempty"`
+	ConsecutiveFailures int        `json:",omitempty"`
 }
 
 type RepoQueueState struct {
@@ -170,6 +174,18 @@ type ChangesetSyncResponse struct {
 	Error string
 }
 
+// PermsSyncRequest is a request to sync permissions of a number of users
+// and repositories as soon as possible.
+type PermsSyncRequest struct {
+	UserIDs []int32      `json:"user_ids"`
+	RepoIDs []api.RepoID `json:"repo_ids"`
+}
+
+// PermsSyncResponse is a response to sync permissions.
+type PermsSyncResponse struct {
+	Error string
+}
+
 // ExternalServiceSyncRequest is a request to sync a specific external service eagerly.
 //
 // The FrontendAPI is one of the issuers of this request. It does so when creating or
@@ -8,6 +8,7 @@ import (
 
 	"github.com/sourcegraph/sourcegraph/internal/search/query/syntax"
 	"github.com/sourcegraph/sourcegraph/internal/search/query/types"
+	"github.com/sourcegraph/sourcegraph/internal/search/secrets"
 )
 
 // All field names.
@@ -174,9 +175,30 @@ func Validate(q QueryInfo, searchType SearchType) error {
 			return errors.New(`the parameter "type:" is not valid for structural search, search is always performed on file content`)
 		}
 	}
+	if searchType == SearchTypeSecrets {
+		if q.Fields()[FieldCase] != nil {
+			return errors.New(`the parameter "case:" is not valid for secret detection search`)
+		}
+		if q.Fields()[FieldType] != nil {
+			return errors.New(`the parameter "type:" is not valid for secret detection search, search is always performed on file content`)
+		}
+		if q.Fields()[FieldContent] != nil {
+			return errors.New(`the parameter "content:" is not valid for secret detection search, the search pattern selects secret detection rules`)
+		}
+		if _, err := secrets.Select(SecretRules(q)); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
+// SecretRules returns the IDs of the secret detection rules selected by the
+// search pattern of a secret detection search. Each term of the pattern is a rule
+// ID. If there are none, all rules are applied.
+func SecretRules(q QueryInfo) []string {
+	return strings.Fields(processSearchPattern(q))
+}
+
 // Process is a top level convenience function for processing a raw string into
 // a validated and type checked query, and the parse tree of the raw string.
 func Process(queryString string, searchType SearchType) (QueryInfo, error) {
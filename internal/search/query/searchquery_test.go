@@ -163,6 +163,24 @@ func TestQuery_Validate(t *testing.T) {
 			SearchType: SearchTypeStructural,
 			Want:       "",
 		},
+		{
+			Name:       `Secret detection search validates with rule IDs`,
+			Query:      `patterntype:secrets aws-access-key-id github-token`,
+			SearchType: SearchTypeSecrets,
+			Want:       "",
+		},
+		{
+			Name:       `Secret detection search incompatible with "type:"`,
+			Query:      `patterntype:secrets type:commit`,
+			SearchType: SearchTypeSecrets,
+			Want:       `the parameter "type:" is not valid for secret detection search, search is always performed on file content`,
+		},
+		{
+			Name:       `Secret detection search rejects unknown rule IDs`,
+			Query:      `patterntype:secrets foo`,
+			SearchType: SearchTypeSecrets,
+			Want:       `unknown secret detection rule "foo" (valid rules are: aws-access-key-id, aws-secret-access-key, github-token, google-api-key, high-entropy-assignment, private-key, slack-token, stripe-secret-key)`,
+		},
 	}
 	for _, tt := range cases {
 		t.Run(tt.Name, func(t *testing.T) {
@@ -14,6 +14,7 @@ const (
 	SearchTypeRegex SearchType = iota
 	SearchTypeLiteral
 	SearchTypeStructural
+	SearchTypeSecrets
 )
 
 // QueryInfo is an intermediate type for an interface of both ordinary queries
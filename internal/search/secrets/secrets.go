@@ -0,0 +1,247 @@
+// Package secrets contains the curated rule set used by secret detection search
+// (patterntype:secrets) to find credentials that were committed to repositories.
+//
+// The rule set is versioned (see Version). Change the version whenever a rule is
+// added, removed or changed, so that clients can tell which rules produced a
+// result.
+package secrets
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Version is the version of the rule set.
+const Version = "1"
+
+// A Rule detects one kind of secret.
+type Rule struct {
+	// ID identifies the rule. It is used to select rules in queries and is
+	// returned with each match.
+	ID string
+
+	// Description is a human-readable description of what the rule detects.
+	Description string
+
+	// Pattern matches the secret. If it has a capturing group, the first group
+	// is the secret and the rest of the match is context (such as the name of
+	// the variable the secret is assigned to).
+	Pattern *regexp.Regexp
+
+	// Keywords, if set, are literal strings of which at least one must appear in
+	// a file for Pattern to be evaluated against it. They are case sensitive.
+	Keywords []string
+
+	// MinEntropy, if set, is the minimum Shannon entropy (in bits per
+	// character) that the secret must have. It filters out placeholders such as
+	// "changeme" or "xxxxxxxxxxxxxxxx".
+	MinEntropy float64
+}
+
+var rules = []*Rule{
+	{
+		ID:          "aws-access-key-id",
+		Description: "AWS access key ID",
+		Pattern:     regexp.MustCompile(`\b((?:AKIA|ASIA)[0-9A-Z]{16})\b`),
+		Keywords:    []string{"AKIA", "ASIA"},
+	},
+	{
+		ID:          "aws-secret-access-key",
+		Description: "AWS secret access key",
+		Pattern:     regexp.MustCompile(`(?i)aws_?secret_?(?:access_?)?key["']?\s*[:=]+\s*["']?([A-Za-z0-9/+]{40})(?:[^A-Za-z0-9/+]|$)`),
+		MinEntropy:  4,
+	},
+	{
+		ID:          "github-token",
+		Description: "GitHub personal access, OAuth, app or refresh token",
+		Pattern:     regexp.MustCompile(`\b((?:ghp|gho|ghu|ghs|ghr)_[A-Za-z0-9]{36})\b`),
+		Keywords:    []string{"ghp_", "gho_", "ghu_", "ghs_", "ghr_"},
+	},
+	{
+		ID:          "slack-token",
+		Description: "Slack API token",
+		Pattern:     regexp.MustCompile(`\b(xox[abposr]-[0-9A-Za-z-]{10,72})\b`),
+		Keywords:    []string{"xox"},
+	},
+	{
+		ID:          "google-api-key",
+		Description: "Google API key",
+		Pattern:     regexp.MustCompile(`\b(AIza[0-9A-Za-z_-]{35})`),
+		Keywords:    []string{"AIza"},
+	},
+	{
+		ID:          "stripe-secret-key",
+		Description: "Stripe live secret or restricted key",
+		Pattern:     regexp.MustCompile(`\b((?:sk|rk)_live_[0-9A-Za-z]{24,99})\b`),
+		Keywords:    []string{"_live_"},
+	},
+	{
+		ID:          "private-key",
+		Description: "PEM-encoded private key",
+		Pattern:     regexp.MustCompile(`-----BEGIN (?:[A-Z]+ )?PRIVATE KEY(?: BLOCK)?-----`),
+		Keywords:    []string{"PRIVATE KEY"},
+	},
+	{
+		ID:          "high-entropy-assignment",
+		Description: "High-entropy string assigned to a variable or key whose name suggests a secret",
+		Pattern:     regexp.MustCompile(`(?i)(?:secret|token|passw(?:or)?d|api_?key|access_?key|credentials?|auth)[A-Za-z0-9_.-]*["']?\s*[:=]+\s*["']([A-Za-z0-9+/_=.-]{16,128})["']`),
+		MinEntropy:  3.5,
+	},
+}
+
+func init() {
+	seen := map[string]bool{}
+	for _, r := range rules {
+		if seen[r.ID] {
+			panic("duplicate secret detection rule ID: " + r.ID)
+		}
+		seen[r.ID] = true
+	}
+}
+
+// Rules returns all rules, sorted by ID.
+func Rules() []*Rule {
+	l := make([]*Rule, len(rules))
+	copy(l, rules)
+	sort.Slice(l, func(i, j int) bool { return l[i].ID < l[j].ID })
+	return l
+}
+
+// Select returns the rules with the given IDs, or all rules if ids is empty.
+// It returns an error if an ID doesn't identify a rule.
+func Select(ids []string) ([]*Rule, error) {
+	if len(ids) == 0 {
+		return Rules(), nil
+	}
+	byID := make(map[string]*Rule, len(rules))
+	for _, r := range rules {
+		byID[r.ID] = r
+	}
+	selected := make([]*Rule, 0, len(ids))
+	for _, id := range ids {
+		r, ok := byID[id]
+		if !ok {
+			return nil, fmt.Errorf("unknown secret detection rule %q (valid rules are: %s)", id, strings.Join(ruleIDs(), ", "))
+		}
+		selected = append(selected, r)
+	}
+	return selected, nil
+}
+
+func ruleIDs() []string {
+	ids := make([]string, len(rules))
+	for i, r := range Rules() {
+		ids[i] = r.ID
+	}
+	return ids
+}
+
+// A Match is a secret found in some data.
+type Match struct {
+	RuleID string
+
+	// Start and End are the byte offsets of the secret in the data.
+	Start, End int
+}
+
+// Find returns the secrets in data that are detected by the rules, ordered by
+// position. Overlapping matches are reported once, for the rule that matched
+// first. At most limit matches are returned if limit is positive.
+func Find(rules []*Rule, data []byte, limit int) []Match {
+	var matches []Match
+	for _, r := range rules {
+		if !containsAny(data, r.Keywords) {
+			continue
+		}
+		for _, loc := range r.Pattern.FindAllSubmatchIndex(data, -1) {
+			start, end := loc[0], loc[1]
+			if len(loc) >= 4 && loc[2] >= 0 {
+				start, end = loc[2], loc[3]
+			}
+			if r.MinEntropy > 0 && Entropy(data[start:end]) < r.MinEntropy {
+				continue
+			}
+			matches = append(matches, Match{RuleID: r.ID, Start: start, End: end})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].Start < matches[j].Start })
+	deduped := matches[:0]
+	for _, m := range matches {
+		if n := len(deduped); n > 0 && m.Start < deduped[n-1].End {
+			continue
+		}
+		deduped = append(deduped, m)
+		if limit > 0 && len(deduped) == limit {
+			break
+		}
+	}
+	return deduped
+}
+
+func containsAny(data []byte, keywords []string) bool {
+	if len(keywords) == 0 {
+		return true
+	}
+	for _, k := range keywords {
+		if bytes.Contains(data, []byte(k)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Entropy returns the Shannon entropy of s in bits per byte.
+func Entropy(s []byte) float64 {
+	if len(s) == 0 {
+		return 0
+	}
+	var counts [256]int
+	for _, b := range s {
+		counts[b]++
+	}
+	var entropy float64
+	for _, c := range counts {
+		if c == 0 {
+			continue
+		}
+		p := float64(c) / float64(len(s))
+		entropy -= p * math.Log2(p)
+	}
+	return entropy
+}
+
+// visiblePrefix is the number of leading characters of a secret that Mask leaves
+// visible, which is enough to recognize the kind of secret (e.g. "AKIA").
+const visiblePrefix = 4
+
+// Mask returns s with all but the first few characters replaced by '*'. The
+// result has the same number of characters as s, so that character offsets into
+// a line stay valid after the secrets in it are masked.
+func Mask(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+	i := 0
+	for _, r := range s {
+		if i < visiblePrefix {
+			b.WriteRune(r)
+		} else {
+			b.WriteByte('*')
+		}
+		i++
+	}
+	return b.String()
+}
+
+// MaskRange returns line with the secret at the byte offsets [start, end) masked
+// (see Mask).
+func MaskRange(line string, start, end int) string {
+	if start < 0 || end > len(line) || start >= end {
+		return line
+	}
+	return line[:start] + Mask(line[start:end]) + line[end:]
+}
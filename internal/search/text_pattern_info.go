@@ -3,13 +3,21 @@ package search
 
 import (
 	"regexp/syntax"
+
+	"github.com/sourcegraph/sourcegraph/internal/search/secrets"
 )
 
 func (p *TextPatternInfo) IsEmpty() bool {
-	return p.Pattern == "" && p.ExcludePattern == "" && len(p.IncludePatterns) == 0
+	return p.Pattern == "" && p.ExcludePattern == "" && len(p.IncludePatterns) == 0 && !p.IsSecretSearch
 }
 
 func (p *TextPatternInfo) Validate() error {
+	if p.IsSecretSearch {
+		if _, err := secrets.Select(p.SecretRules); err != nil {
+			return err
+		}
+	}
+
 	if p.IsRegExp {
 		if _, err := syntax.Parse(p.Pattern, syntax.Perl); err != nil {
 			return err
@@ -110,6 +110,8 @@ type TextPatternInfo struct {
 	IsRegExp        bool
 	IsStructuralPat bool
 	CombyRule       string
+	IsSecretSearch  bool
+	SecretRules     []string
 	IsWordMatch     bool
 	IsCaseSensitive bool
 	FileMatchLimit  int32
@@ -143,6 +145,13 @@ func (p *TextPatternInfo) String() string {
 			args = append(args, "comby")
 		}
 	}
+	if p.IsSecretSearch {
+		if len(p.SecretRules) > 0 {
+			args = append(args, fmt.Sprintf("secrets:%s", strings.Join(p.SecretRules, "|")))
+		} else {
+			args = append(args, "secrets")
+		}
+	}
 	if p.IsWordMatch {
 		args = append(args, "word")
 	}
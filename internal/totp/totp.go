@@ -0,0 +1,134 @@
+// Package totp implements time-based one-time passwords (TOTP) as specified in RFC 6238, using the
+// parameters that common authenticator apps support (HMAC-SHA1, 6 digits, 30 second steps).
+package totp
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// Digits is the number of digits in a code.
+	Digits = 6
+
+	// Period is the duration for which a code is valid.
+	Period = 30 * time.Second
+
+	// skew is the number of steps before and after the current one for which codes are also
+	// accepted, to allow for clock drift between the server and the authenticator.
+	skew = 1
+
+	secretSize = 20
+)
+
+var encoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// GenerateSecret returns a new random secret, encoded in base32 (the format used by
+// authenticator apps).
+func GenerateSecret() (string, error) {
+	b := make([]byte, secretSize)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return encoding.EncodeToString(b), nil
+}
+
+// Code returns the code for the base32-encoded secret at time t.
+func Code(secret string, t time.Time) (string, error) {
+	key, err := decodeSecret(secret)
+	if err != nil {
+		return "", err
+	}
+	return code(key, step(t)), nil
+}
+
+// Validate reports whether code is valid for the base32-encoded secret at time t. Codes of the
+// neighboring time steps are also accepted.
+//
+// 🚨 SECURITY: Validate does not prevent a code from being used more than once. Use ValidateStep
+// and reject steps that are not after the last accepted one to enforce that.
+func Validate(secret, code string, t time.Time) bool {
+	_, ok := ValidateStep(secret, code, t)
+	return ok
+}
+
+// ValidateStep is like Validate, but it also returns the time step of the code if it is valid.
+func ValidateStep(secret, code string, t time.Time) (uint64, bool) {
+	code = strings.Replace(code, " ", "", -1)
+	if len(code) != Digits {
+		return 0, false
+	}
+	key, err := decodeSecret(secret)
+	if err != nil {
+		return 0, false
+	}
+
+	var (
+		valid   int
+		matched uint64
+		s       = step(t)
+	)
+	for i := -skew; i <= skew; i++ {
+		// 🚨 SECURITY: Use a constant-time comparison to avoid leaking the code through timing.
+		eq := subtle.ConstantTimeCompare([]byte(codeAt(key, s, i)), []byte(code))
+		valid |= eq
+		matched = uint64(subtle.ConstantTimeSelect(eq, int(int64(s)+int64(i)), int(matched)))
+	}
+	return matched, valid == 1
+}
+
+// KeyURI returns the otpauth:// URI for the secret, which authenticator apps can import
+// (usually by scanning a QR code of it).
+func KeyURI(issuer, account, secret string) string {
+	v := url.Values{}
+	v.Set("secret", secret)
+	v.Set("issuer", issuer)
+	v.Set("algorithm", "SHA1")
+	v.Set("digits", fmt.Sprint(Digits))
+	v.Set("period", fmt.Sprint(int(Period/time.Second)))
+	u := url.URL{
+		Scheme:   "otpauth",
+		Host:     "totp",
+		Path:     "/" + issuer + ":" + account,
+		RawQuery: v.Encode(),
+	}
+	return u.String()
+}
+
+func decodeSecret(secret string) ([]byte, error) {
+	return encoding.DecodeString(strings.ToUpper(strings.TrimRight(secret, "=")))
+}
+
+func step(t time.Time) uint64 {
+	return uint64(t.Unix() / int64(Period/time.Second))
+}
+
+func codeAt(key []byte, s uint64, offset int) string {
+	return code(key, uint64(int64(s)+int64(offset)))
+}
+
+// code implements HOTP (RFC 4226) for the given counter.
+func code(key []byte, counter uint64) string {
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], counter)
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0xf
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	mod := uint32(1)
+	for i := 0; i < Digits; i++ {
+		mod *= 10
+	}
+	return fmt.Sprintf("%0*d", Digits, value%mod)
+}
@@ -0,0 +1,101 @@
+package totp
+
+import (
+	"encoding/base32"
+	"strings"
+	"testing"
+	"time"
+)
+
+// The test vectors are from RFC 6238 Appendix B (SHA1), truncated to 6 digits.
+var rfcSecret = base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+
+func TestCode(t *testing.T) {
+	tests := map[int64]string{
+		59:          "287082",
+		1111111109:  "081804",
+		1111111111:  "050471",
+		1234567890:  "005924",
+		2000000000:  "279037",
+		20000000000: "353130",
+	}
+	for unix, want := range tests {
+		got, err := Code(rfcSecret, time.Unix(unix, 0))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("%d: got %q, want %q", unix, got, want)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	now := time.Unix(1111111109, 0)
+	code, err := Code(rfcSecret, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		code string
+		at   time.Time
+		want bool
+	}{
+		{name: "current step", code: code, at: now, want: true},
+		{name: "with spaces", code: code[:3] + " " + code[3:], at: now, want: true},
+		{name: "previous step", code: code, at: now.Add(Period), want: true},
+		{name: "next step", code: code, at: now.Add(-Period), want: true},
+		{name: "expired", code: code, at: now.Add(3 * Period), want: false},
+		{name: "wrong code", code: "000000", at: now, want: false},
+		{name: "too short", code: code[:5], at: now, want: false},
+		{name: "empty", code: "", at: now, want: false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := Validate(rfcSecret, test.code, test.at); got != test.want {
+				t.Errorf("got %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestValidateStep(t *testing.T) {
+	now := time.Unix(1111111109, 0)
+	code, err := Code(rfcSecret, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := step(now)
+	for _, at := range []time.Time{now.Add(-Period), now, now.Add(Period)} {
+		if got, ok := ValidateStep(rfcSecret, code, at); !ok || got != want {
+			t.Errorf("at %s: got step %d (valid %v), want %d", at, got, ok, want)
+		}
+	}
+	if _, ok := ValidateStep(rfcSecret, "000000", now); ok {
+		t.Error("wrong code was accepted")
+	}
+}
+
+func TestGenerateSecret(t *testing.T) {
+	secret, err := GenerateSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Code(secret, time.Now()); err != nil {
+		t.Fatalf("generated secret %q is invalid: %s", secret, err)
+	}
+	if strings.Contains(secret, "=") {
+		t.Errorf("secret %q should not be padded", secret)
+	}
+}
+
+func TestKeyURI(t *testing.T) {
+	got := KeyURI("Sourcegraph", "alice", "JBSWY3DPEHPK3PXP")
+	want := "otpauth://totp/Sourcegraph:alice?algorithm=SHA1&digits=6&issuer=Sourcegraph&period=30&secret=JBSWY3DPEHPK3PXP"
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
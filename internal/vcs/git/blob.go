@@ -2,15 +2,13 @@ package git
 
 import (
 	"context"
-	"fmt"
 	"io"
 	"io/ioutil"
-	"os"
-	"strings"
 
 	"github.com/pkg/errors"
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
 	"github.com/sourcegraph/sourcegraph/internal/trace/ot"
 	"github.com/sourcegraph/sourcegraph/internal/vcs/util"
 )
@@ -50,7 +48,7 @@ func NewFileReader(ctx context.Context, repo gitserver.Repo, commit api.CommitID
 	defer span.Finish()
 
 	name = util.Rel(name)
-	br, err := newBlobReader(ctx, repo, commit, name)
+	br, err := newBlobReader(ctx, repo, commit, name, 0)
 	if err != nil {
 		return nil, errors.Wrapf(err, "getting blobReader for %q", name)
 	}
@@ -58,89 +56,62 @@ func NewFileReader(ctx context.Context, repo gitserver.Repo, commit api.CommitID
 }
 
 func readFileBytes(ctx context.Context, repo gitserver.Repo, commit api.CommitID, name string, maxBytes int64) ([]byte, error) {
-	br, err := newBlobReader(ctx, repo, commit, name)
+	br, err := newBlobReader(ctx, repo, commit, name, maxBytes)
 	if err != nil {
 		return nil, err
 	}
 	defer br.Close()
-
-	r := io.Reader(br)
-	if maxBytes > 0 {
-		r = io.LimitReader(r, maxBytes)
-	}
-	data, err := ioutil.ReadAll(r)
-	if err != nil {
-		return nil, err
-	}
-	return data, nil
+	return ioutil.ReadAll(br)
 }
 
-// blobReader, which should be created using newBlobReader, is a struct that allows
-// us to get a ReadCloser to a specific named file at a specific commit
+// blobReader, which should be created using newBlobReader, reads the first
+// maxBytes of a named file at a specific commit. It requests the file from
+// gitserver on the first Read, so errors (e.g. for nonexistent files) are
+// returned by Read.
 type blobReader struct {
-	ctx    context.Context
-	repo   gitserver.Repo
-	commit api.CommitID
-	name   string
-	cmd    *gitserver.Cmd
-	rc     io.ReadCloser
+	ctx      context.Context
+	repo     gitserver.Repo
+	commit   api.CommitID
+	name     string
+	maxBytes int64
+	rc       io.ReadCloser
 }
 
-func newBlobReader(ctx context.Context, repo gitserver.Repo, commit api.CommitID, name string) (*blobReader, error) {
+// newBlobReader returns a reader of the first maxBytes of the named file at
+// commit. If maxBytes <= 0, the entire file is read. Submodules are read as
+// empty files.
+func newBlobReader(ctx context.Context, repo gitserver.Repo, commit api.CommitID, name string, maxBytes int64) (*blobReader, error) {
 	if err := ensureAbsoluteCommit(commit); err != nil {
 		return nil, err
 	}
-
-	cmd := gitserver.DefaultClient.Command("git", "show", string(commit)+":"+name)
-	cmd.Repo = repo
-	stdout, err := gitserver.StdoutReader(ctx, cmd)
-	if err != nil {
-		return nil, err
-	}
-
 	return &blobReader{
-		ctx:    ctx,
-		repo:   repo,
-		commit: commit,
-		name:   name,
-		cmd:    cmd,
-		rc:     stdout,
+		ctx:      ctx,
+		repo:     repo,
+		commit:   commit,
+		name:     name,
+		maxBytes: maxBytes,
 	}, nil
 }
 
 func (br *blobReader) Read(p []byte) (int, error) {
-	n, err := br.rc.Read(p)
-	if err != nil {
-		return n, br.convertError(err)
+	if br.rc == nil {
+		rc, err := gitserver.DefaultClient.ReadBlob(br.ctx, &protocol.ReadBlobRequest{
+			GitRepo:  protocol.GitRepo{Repo: br.repo.Name, URL: br.repo.URL},
+			Commit:   br.commit,
+			Path:     br.name,
+			MaxBytes: br.maxBytes,
+		})
+		if err != nil {
+			return 0, err
+		}
+		br.rc = rc
 	}
-	return n, nil
+	return br.rc.Read(p)
 }
 
 func (br *blobReader) Close() error {
-	return br.rc.Close()
-}
-
-// convertError converts an error returned from 'git show' into a more appropriate error type
-func (br *blobReader) convertError(err error) error {
-	if err == nil {
+	if br.rc == nil {
 		return nil
 	}
-	if err == io.EOF {
-		return err
-	}
-	if strings.Contains(err.Error(), "exists on disk, but not in") || strings.Contains(err.Error(), "does not exist") {
-		return &os.PathError{Op: "open", Path: br.name, Err: os.ErrNotExist}
-	}
-	if strings.Contains(err.Error(), "fatal: bad object ") {
-		// Could be a git submodule.
-		fi, err := Stat(br.ctx, br.repo, br.commit, br.name)
-		if err != nil {
-			return err
-		}
-		// Return EOF for a submodule for now which indicates zero content
-		if fi.Mode()&ModeSubmodule != 0 {
-			return io.EOF
-		}
-	}
-	return errors.WithMessage(err, fmt.Sprintf("git command %v failed (output: %q)", br.cmd.Args, err))
+	return br.rc.Close()
 }
@@ -11,24 +11,14 @@ import (
 	"github.com/pkg/errors"
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
 	"github.com/sourcegraph/sourcegraph/internal/lazyregexp"
 	"github.com/sourcegraph/sourcegraph/internal/trace/ot"
 )
 
-type Commit struct {
-	ID        api.CommitID `json:"ID,omitempty"`
-	Author    Signature    `json:"Author"`
-	Committer *Signature   `json:"Committer,omitempty"`
-	Message   string       `json:"Message,omitempty"`
-	// Parents are the commit IDs of this commit's parent commits.
-	Parents []api.CommitID `json:"Parents,omitempty"`
-}
+type Commit = protocol.Commit
 
-type Signature struct {
-	Name  string    `json:"Name,omitempty"`
-	Email string    `json:"Email,omitempty"`
-	Date  time.Time `json:"Date"`
-}
+type Signature = protocol.Signature
 
 // CommitsOptions specifies options for (Repository).Commits (Repository).CommitCount.
 type CommitsOptions struct {
@@ -141,51 +131,25 @@ func isInvalidRevisionRangeError(output, obj string) bool {
 //
 // The caller is responsible for doing checkSpecArgSafety on opt.Head and opt.Base.
 func commitLog(ctx context.Context, repo gitserver.Repo, opt CommitsOptions) (commits []*Commit, err error) {
-	args, err := commitLogArgs([]string{"log", logFormatWithoutRefs}, opt)
-	if err != nil {
+	if err := checkSpecArgSafety(string(opt.Range)); err != nil {
 		return nil, err
 	}
 
-	cmd := gitserver.DefaultClient.Command("git", args...)
-	cmd.Repo = repo
-	cmd.EnsureRevision = opt.Range
-	retryer := &commandRetryer{
-		cmd:           cmd,
-		remoteURLFunc: opt.RemoteURLFunc,
-		exec: func() error {
-			commits, err = runCommitLog(ctx, cmd, opt)
-			return err
-		},
-	}
-	err = retryer.run()
-	return
-}
-
-// runCommitLog sends the git command to gitserver. It interprets missing
-// revision responses and converts them into RevisionNotFoundError.
-func runCommitLog(ctx context.Context, cmd *gitserver.Cmd, opt CommitsOptions) ([]*Commit, error) {
-	data, stderr, err := cmd.DividedOutput(ctx)
-	if err != nil {
-		data = bytes.TrimSpace(data)
-		if isBadObjectErr(string(stderr), string(opt.Range)) {
-			return nil, &gitserver.RevisionNotFoundError{Repo: cmd.Repo.Name, Spec: string(opt.Range)}
-		}
-		return nil, errors.WithMessage(err, fmt.Sprintf("git command %v failed (output: %q)", cmd.Args, data))
-	}
-
-	allParts := bytes.Split(data, []byte{'\x00'})
-	numCommits := len(allParts) / partsPerCommit
-	commits := make([]*Commit, 0, numCommits)
-	for len(data) > 0 {
-		var commit *Commit
-		var err error
-		commit, _, data, err = parseCommitFromLog(data)
-		if err != nil {
-			return nil, err
-		}
-		commits = append(commits, commit)
+	req := &protocol.LogRequest{
+		Range:        opt.Range,
+		N:            opt.N,
+		Skip:         opt.Skip,
+		MessageQuery: opt.MessageQuery,
+		Author:       opt.Author,
+		After:        opt.After,
+		Path:         opt.Path,
 	}
-	return commits, nil
+	err = retryGitOp(repo, opt.RemoteURLFunc, opt.Range, func(gitRepo protocol.GitRepo) (err error) {
+		req.GitRepo = gitRepo
+		commits, err = gitserver.DefaultClient.Log(ctx, req)
+		return err
+	})
+	return commits, err
 }
 
 func commitLogArgs(initialArgs []string, opt CommitsOptions) (args []string, err error) {
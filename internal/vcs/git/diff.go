@@ -0,0 +1,59 @@
+package git
+
+import (
+	"context"
+	"io"
+
+	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
+	"github.com/sourcegraph/sourcegraph/internal/trace/ot"
+)
+
+// DiffOptions configures a diff.
+type DiffOptions struct {
+	Base string // the base revision
+	Head string // the head revision
+
+	// MergeBase is whether to diff Head against the merge base of Base and
+	// Head (like "git diff Base...Head") instead of against Base.
+	MergeBase bool
+
+	Paths []string // limit the diff to these paths (optional)
+
+	FindRenames      bool // detect renames and copies
+	FullIndex        bool // show full object IDs in index lines
+	InterHunkContext int  // merge hunks at most this many lines apart
+	NoPrefix         bool // omit the a/ and b/ path prefixes
+}
+
+// ReadDiff returns a reader of the diff between two revisions in unified format.
+// The caller must close it.
+func ReadDiff(ctx context.Context, repo gitserver.Repo, opt DiffOptions) (io.ReadCloser, error) {
+	if Mocks.ReadDiff != nil {
+		return Mocks.ReadDiff(opt)
+	}
+
+	span, ctx := ot.StartSpanFromContext(ctx, "Git: ReadDiff")
+	span.SetTag("Base", opt.Base)
+	span.SetTag("Head", opt.Head)
+	defer span.Finish()
+
+	if err := checkSpecArgSafety(opt.Base); err != nil {
+		return nil, err
+	}
+	if err := checkSpecArgSafety(opt.Head); err != nil {
+		return nil, err
+	}
+
+	return gitserver.DefaultClient.Diff(ctx, &protocol.DiffRequest{
+		GitRepo:          protocol.GitRepo{Repo: repo.Name, URL: repo.URL},
+		Base:             opt.Base,
+		Head:             opt.Head,
+		MergeBase:        opt.MergeBase,
+		Paths:            opt.Paths,
+		FindRenames:      opt.FindRenames,
+		FullIndex:        opt.FullIndex,
+		InterHunkContext: opt.InterHunkContext,
+		NoPrefix:         opt.NoPrefix,
+	})
+}
@@ -11,6 +11,7 @@ import (
 
 	"github.com/pkg/errors"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
 	"github.com/sourcegraph/sourcegraph/internal/trace/ot"
 	"github.com/sourcegraph/sourcegraph/internal/vcs"
 )
@@ -179,23 +180,6 @@ func isWhitelistedGitCmd(args []string) bool {
 	return true
 }
 
-func gitserverCmdFunc(repo gitserver.Repo) cmdFunc {
-	return func(args []string) cmd {
-		cmd := gitserver.DefaultClient.Command("git", args...)
-		cmd.Repo = gitserver.Repo(repo)
-		return cmd
-	}
-}
-
-// cmdFunc is a func that creates a new executable Git command.
-type cmdFunc func(args []string) cmd
-
-// cmd is an executable Git command.
-type cmd interface {
-	Output(context.Context) ([]byte, error)
-	String() string
-}
-
 // commandRetryer executes a gitserver command first without a remote URL and
 // ensured revision, then secondarily retries with a remote URL and ensured
 // revision.
@@ -290,3 +274,34 @@ func (c *commandRetryer) run() error {
 	cpy.Repo.URL = c.cmd.Repo.URL
 	return c.exec()
 }
+
+// retryGitOp is the equivalent of commandRetryer for the typed gitserver
+// requests. If op fails because the repository or the revision rev is
+// missing, it calls op again with the remote URL so that gitserver clones or
+// fetches the repository.
+//
+// The remoteURLFunc is called to get the Git remote URL if it's not set in
+// repo and if it is needed.
+func retryGitOp(repo gitserver.Repo, remoteURLFunc func() (string, error), rev string, op func(protocol.GitRepo) error) error {
+	err := op(protocol.GitRepo{Repo: repo.Name, URL: repo.URL})
+	if err == nil {
+		return nil
+	}
+
+	if repo.URL != "" || remoteURLFunc == nil {
+		// Gitserver already had the remote URL, or we can't determine it.
+		return err
+	}
+	if !vcs.IsRepoNotExist(err) && !(gitserver.IsRevisionNotFound(err) && rev != "HEAD") {
+		// If we didn't find HEAD, the repo is empty and there is no reason
+		// to retry. All other error types (e.g. network failure) are not
+		// retried either.
+		return err
+	}
+
+	url, err := remoteURLFunc()
+	if err != nil {
+		return err
+	}
+	return op(protocol.GitRepo{Repo: repo.Name, URL: url})
+}
@@ -1,13 +1,11 @@
 package git
 
 import (
-	"bytes"
 	"context"
-	"fmt"
 
-	"github.com/pkg/errors"
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
 	"github.com/sourcegraph/sourcegraph/internal/trace/ot"
 )
 
@@ -18,11 +16,9 @@ func MergeBase(ctx context.Context, repo gitserver.Repo, a, b api.CommitID) (api
 	span.SetTag("B", b)
 	defer span.Finish()
 
-	cmd := gitserver.DefaultClient.Command("git", "merge-base", "--", string(a), string(b))
-	cmd.Repo = repo
-	out, err := cmd.CombinedOutput(ctx)
-	if err != nil {
-		return "", errors.WithMessage(err, fmt.Sprintf("git command %v failed (output: %q)", cmd.Args, out))
-	}
-	return api.CommitID(bytes.TrimSpace(out)), nil
+	return gitserver.DefaultClient.MergeBase(ctx, &protocol.MergeBaseRequest{
+		GitRepo: protocol.GitRepo{Repo: repo.Name, URL: repo.URL},
+		A:       a,
+		B:       b,
+	})
 }
@@ -8,6 +8,7 @@ import (
 	"github.com/pkg/errors"
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
 	"github.com/sourcegraph/sourcegraph/internal/trace/ot"
 	"github.com/sourcegraph/sourcegraph/internal/vcs"
 )
@@ -73,36 +74,24 @@ func ResolveRevision(ctx context.Context, repo gitserver.Repo, remoteURLFunc fun
 	if spec == "" {
 		spec = "HEAD"
 	}
-	if spec != "HEAD" {
-		// "git rev-parse HEAD^0" is slower than "git rev-parse HEAD"
-		// since it checks that the resolved git object exists. We can
-		// assume it exists for HEAD, but for other commits we should
-		// check.
-		spec = spec + "^0"
-	}
 
 	var (
 		commit api.CommitID
 		err    error
 	)
-	cmd := gitserver.DefaultClient.Command("git", "rev-parse", spec)
-	cmd.Repo = repo
-	cmd.EnsureRevision = spec
-	retryer := &commandRetryer{
-		cmd:           cmd,
-		remoteURLFunc: remoteURLFunc,
-		exec: func() error {
-			commit, err = runRevParse(ctx, cmd, spec)
-			return err
-		},
+	req := &protocol.ResolveRevisionRequest{Spec: spec}
+	resolve := func(gitRepo protocol.GitRepo) error {
+		req.GitRepo = gitRepo
+		commit, err = gitserver.DefaultClient.ResolveRevision(ctx, req)
+		return err
 	}
 	if opt != nil && opt.NoEnsureRevision {
-		// Make the commandRetryer no-op so that gitserver does not try to
+		// Do not pass the remote URL so that gitserver does not try to
 		// update the repository.
-		cmd.EnsureRevision = ""
-		retryer.remoteURLFunc = nil
+		err = resolve(protocol.GitRepo{Repo: repo.Name})
+	} else {
+		err = retryGitOp(repo, remoteURLFunc, spec, resolve)
 	}
-	err = retryer.run()
 	return commit, err
 }
 
@@ -1,23 +1,19 @@
 package git
 
 import (
-	"bytes"
 	"context"
 	"fmt"
 	"os"
 	stdlibpath "path"
 	"path/filepath"
-	"strconv"
 	"strings"
 	"sync"
 	"time"
 
-	"gopkg.in/src-d/go-git.v4/plumbing/format/config"
-
 	"github.com/golang/groupcache/lru"
-	"github.com/pkg/errors"
 	"github.com/sourcegraph/sourcegraph/internal/api"
 	"github.com/sourcegraph/sourcegraph/internal/gitserver"
+	"github.com/sourcegraph/sourcegraph/internal/gitserver/protocol"
 	"github.com/sourcegraph/sourcegraph/internal/trace/ot"
 	"github.com/sourcegraph/sourcegraph/internal/vcs/util"
 )
@@ -178,30 +174,20 @@ func lsTreeUncached(ctx context.Context, repo gitserver.Repo, commit api.CommitI
 		return nil, err
 	}
 
-	args := []string{
-		"ls-tree",
-		"--long", // show size
-		"--full-name",
-		"-z",
-		string(commit),
-	}
-	if recurse {
-		args = append(args, "-r", "-t")
-	}
-	if path != "" {
-		args = append(args, "--", filepath.ToSlash(path))
-	}
-	cmd := gitserver.DefaultClient.Command("git", args...)
-	cmd.Repo = repo
-	out, err := cmd.CombinedOutput(ctx)
+	entries, err := gitserver.DefaultClient.ListTree(ctx, &protocol.ListTreeRequest{
+		GitRepo:   protocol.GitRepo{Repo: repo.Name, URL: repo.URL},
+		Commit:    commit,
+		Path:      filepath.ToSlash(path),
+		Recursive: recurse,
+	})
 	if err != nil {
-		if bytes.Contains(out, []byte("exists on disk, but not in")) {
+		if os.IsNotExist(err) {
 			return nil, &os.PathError{Op: "ls-tree", Path: filepath.ToSlash(path), Err: os.ErrNotExist}
 		}
-		return nil, errors.WithMessage(err, fmt.Sprintf("git command %v failed (output: %q)", cmd.Args, out))
+		return nil, err
 	}
 
-	if len(out) == 0 {
+	if len(entries) == 0 {
 		// If we are listing the empty root tree, we will have no output.
 		if stdlibpath.Clean(path) == "." {
 			return []os.FileInfo{}, nil
@@ -210,56 +196,26 @@ func lsTreeUncached(ctx context.Context, repo gitserver.Repo, commit api.CommitI
 	}
 
 	trimPath := strings.TrimPrefix(path, "./")
-	lines := strings.Split(string(out), "\x00")
-	fis := make([]os.FileInfo, len(lines)-1)
-	for i, line := range lines {
-		if i == len(lines)-1 {
-			// last entry is empty
-			continue
-		}
-
-		tabPos := strings.IndexByte(line, '\t')
-		if tabPos == -1 {
-			return nil, fmt.Errorf("invalid `git ls-tree` output: %q", out)
-		}
-		info := strings.SplitN(line[:tabPos], " ", 4)
-		name := line[tabPos+1:]
+	fis := make([]os.FileInfo, len(entries))
+	for i, entry := range entries {
+		name := entry.Path
 		if len(name) < len(trimPath) {
 			// This is in a submodule; return the original path to avoid a slice out of bounds panic
 			// when setting the FileInfo._Name below.
 			name = trimPath
 		}
 
-		if len(info) != 4 {
-			return nil, fmt.Errorf("invalid `git ls-tree` output: %q", out)
+		if !IsAbsoluteRevision(entry.OID) {
+			return nil, fmt.Errorf("invalid `git ls-tree` SHA output: %q", entry.OID)
 		}
-		typ := info[1]
-		sha := info[2]
-		if !IsAbsoluteRevision(sha) {
-			return nil, fmt.Errorf("invalid `git ls-tree` SHA output: %q", sha)
-		}
-		oid, err := decodeOID(sha)
+		oid, err := decodeOID(entry.OID)
 		if err != nil {
 			return nil, err
 		}
 
-		sizeStr := strings.TrimSpace(info[3])
-		var size int64
-		if sizeStr != "-" {
-			// Size of "-" indicates a dir or submodule.
-			size, err = strconv.ParseInt(sizeStr, 10, 64)
-			if err != nil || size < 0 {
-				return nil, fmt.Errorf("invalid `git ls-tree` size output: %q (error: %s)", sizeStr, err)
-			}
-		}
-
 		var sys interface{}
-		modeVal, err := strconv.ParseInt(info[0], 8, 32)
-		if err != nil {
-			return nil, err
-		}
-		mode := os.FileMode(modeVal)
-		switch typ {
+		mode := os.FileMode(entry.Mode)
+		switch entry.Type {
 		case "blob":
 			const gitModeSymlink = 020000
 			if mode&gitModeSymlink != 0 {
@@ -270,19 +226,10 @@ func lsTreeUncached(ctx context.Context, repo gitserver.Repo, commit api.CommitI
 			}
 		case "commit":
 			mode = mode | ModeSubmodule
-			cmd := gitserver.DefaultClient.Command("git", "show", fmt.Sprintf("%s:.gitmodules", commit))
-			cmd.Repo = repo
 			var submodule Submodule
-			if out, err := cmd.Output(ctx); err == nil {
-
-				var cfg config.Config
-				err := config.NewDecoder(bytes.NewBuffer(out)).Decode(&cfg)
-				if err != nil {
-					return nil, fmt.Errorf("error parsing .gitmodules: %s", err)
-				}
-
-				submodule.Path = cfg.Section("submodule").Subsection(name).Option("path")
-				submodule.URL = cfg.Section("submodule").Subsection(name).Option("url")
+			if entry.Submodule != nil {
+				submodule.Path = entry.Submodule.Path
+				submodule.URL = entry.Submodule.URL
 			}
 			submodule.CommitID = api.CommitID(oid.String())
 			sys = submodule
@@ -298,7 +245,7 @@ func lsTreeUncached(ctx context.Context, repo gitserver.Repo, commit api.CommitI
 		fis[i] = &util.FileInfo{
 			Name_: name, // full path relative to root (not just basename)
 			Mode_: os.FileMode(mode),
-			Size_: size,
+			Size_: entry.Size,
 			Sys_:  sys,
 		}
 	}
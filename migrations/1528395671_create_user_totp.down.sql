@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP TABLE IF EXISTS user_totp_recovery_codes;
+DROP TABLE IF EXISTS user_totp;
+
+COMMIT;
@@ -0,0 +1,22 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS user_totp (
+    user_id integer PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    secret text NOT NULL,
+    enabled_at timestamp with time zone,
+    failed_attempts integer NOT NULL DEFAULT 0,
+    last_failed_attempt_at timestamp with time zone,
+    created_at timestamp with time zone NOT NULL DEFAULT now(),
+    last_used_step bigint
+);
+
+CREATE TABLE IF NOT EXISTS user_totp_recovery_codes (
+    id bigserial PRIMARY KEY,
+    user_id integer NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    code_hash text NOT NULL,
+    used_at timestamp with time zone
+);
+
+CREATE INDEX IF NOT EXISTS user_totp_recovery_codes_user_id ON user_totp_recovery_codes(user_id) WHERE used_at IS NULL;
+
+COMMIT;
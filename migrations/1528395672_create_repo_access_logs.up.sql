@@ -0,0 +1,22 @@
+BEGIN;
+
+-- Repositories and users are not referenced with foreign keys, so that the log is kept intact
+-- (for audits) when they are deleted.
+CREATE TABLE IF NOT EXISTS repo_access_logs (
+    id bigserial PRIMARY KEY,
+    repo_id integer NOT NULL,
+    repo_name text NOT NULL,
+    user_id integer NOT NULL DEFAULT 0,
+    kind text NOT NULL,
+    path text NOT NULL DEFAULT '',
+    commit_id text NOT NULL DEFAULT '',
+    query text NOT NULL DEFAULT '',
+    ip text NOT NULL DEFAULT '',
+    created_at timestamp with time zone NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS repo_access_logs_repo_id ON repo_access_logs(repo_id, created_at);
+CREATE INDEX IF NOT EXISTS repo_access_logs_user_id ON repo_access_logs(user_id, created_at);
+CREATE INDEX IF NOT EXISTS repo_access_logs_created_at ON repo_access_logs(created_at);
+
+COMMIT;
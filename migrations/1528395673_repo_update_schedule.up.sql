@@ -0,0 +1,13 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS repo_update_schedule (
+    repo_id integer PRIMARY KEY REFERENCES repo(id) ON DELETE CASCADE,
+    interval_seconds integer NOT NULL,
+    due timestamp with time zone NOT NULL,
+    last_fetched timestamp with time zone,
+    last_error text NOT NULL DEFAULT '',
+    consecutive_failures integer NOT NULL DEFAULT 0,
+    updated_at timestamp with time zone NOT NULL DEFAULT now()
+);
+
+COMMIT;
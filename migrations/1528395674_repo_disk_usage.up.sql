@@ -0,0 +1,13 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS repo_disk_usage (
+    repo_id integer NOT NULL REFERENCES repo(id) ON DELETE CASCADE,
+    shard text NOT NULL,
+    size_bytes bigint NOT NULL,
+    updated_at timestamp with time zone NOT NULL DEFAULT now(),
+    PRIMARY KEY (repo_id, shard)
+);
+
+CREATE INDEX IF NOT EXISTS repo_disk_usage_size_bytes ON repo_disk_usage (size_bytes DESC);
+
+COMMIT;
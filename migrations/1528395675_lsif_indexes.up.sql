@@ -0,0 +1,22 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS lsif_indexes (
+    id serial PRIMARY KEY,
+    commit text NOT NULL,
+    repository_id integer NOT NULL,
+    indexer text NOT NULL,
+    state text NOT NULL DEFAULT 'queued',
+    queued_at timestamp with time zone NOT NULL DEFAULT now(),
+    started_at timestamp with time zone,
+    finished_at timestamp with time zone,
+    failure_summary text,
+    log text,
+    upload_id integer REFERENCES lsif_uploads(id) ON DELETE SET NULL,
+    CONSTRAINT lsif_indexes_commit_valid_chars CHECK (commit ~ '^[a-z0-9]{40}$'),
+    CONSTRAINT lsif_indexes_state_check CHECK (state IN ('queued', 'processing', 'completed', 'errored'))
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS lsif_indexes_repository_commit_indexer ON lsif_indexes (repository_id, commit, indexer);
+CREATE INDEX IF NOT EXISTS lsif_indexes_state_queued_at ON lsif_indexes (state, queued_at);
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+DROP INDEX IF EXISTS lsif_references_identifiers_unindexed;
+ALTER TABLE lsif_references DROP COLUMN IF EXISTS identifiers_indexed;
+DROP TABLE IF EXISTS lsif_reference_identifiers;
+
+COMMIT;
@@ -0,0 +1,17 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS lsif_reference_identifiers (
+    dump_id integer NOT NULL REFERENCES lsif_uploads(id) ON DELETE CASCADE,
+    scheme text NOT NULL,
+    identifier text NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS lsif_reference_identifiers_scheme_identifier ON lsif_reference_identifiers (scheme, identifier);
+CREATE INDEX IF NOT EXISTS lsif_reference_identifiers_dump_id ON lsif_reference_identifiers (dump_id);
+
+-- Existing references only have a bloom filter of their identifiers. These rows are indexed
+-- from their bundles by the precise-code-intel-worker after this migration runs.
+ALTER TABLE lsif_references ADD COLUMN IF NOT EXISTS identifiers_indexed boolean NOT NULL DEFAULT false;
+CREATE INDEX IF NOT EXISTS lsif_references_identifiers_unindexed ON lsif_references (dump_id) WHERE NOT identifiers_indexed;
+
+COMMIT;
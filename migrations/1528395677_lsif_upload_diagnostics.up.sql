@@ -0,0 +1,15 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS lsif_upload_diagnostics (
+    id serial PRIMARY KEY,
+    upload_id integer NOT NULL REFERENCES lsif_uploads(id) ON DELETE CASCADE,
+    severity text NOT NULL,
+    code text NOT NULL,
+    message text NOT NULL,
+    element_id text NOT NULL,
+    line integer NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS lsif_upload_diagnostics_upload_id ON lsif_upload_diagnostics (upload_id);
+
+COMMIT;
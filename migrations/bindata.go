@@ -106,6 +106,22 @@
 // 1528395668_campaign_description_nullable.up.sql (143B)
 // 1528395669_add_synced_at_to_perms_tables.down.sql (121B)
 // 1528395669_add_synced_at_to_perms_tables.up.sql (143B)
+// 1528395670_create_user_sessions.down.sql (53B)
+// 1528395670_create_user_sessions.up.sql (547B)
+// 1528395671_create_user_totp.down.sql (96B)
+// 1528395671_create_user_totp.up.sql (733B)
+// 1528395672_create_repo_access_logs.down.sql (56B)
+// 1528395672_create_repo_access_logs.up.sql (837B)
+// 1528395673_repo_update_schedule.down.sql (60B)
+// 1528395673_repo_update_schedule.up.sql (423B)
+// 1528395674_repo_disk_usage.down.sql (55B)
+// 1528395674_repo_disk_usage.up.sql (380B)
+// 1528395675_lsif_indexes.down.sql (52B)
+// 1528395675_lsif_indexes.up.sql (880B)
+// 1528395676_lsif_reference_identifiers.down.sql (197B)
+// 1528395676_lsif_reference_identifiers.up.sql (841B)
+// 1528395677_lsif_upload_diagnostics.down.sql (63B)
+// 1528395677_lsif_upload_diagnostics.up.sql (415B)
 
 package migrations
 
@@ -2294,6 +2310,326 @@ func _1528395669_add_synced_at_to_perms_tablesUpSql() (*asset, error) {
 	return a, nil
 }
 
+var __1528395670_create_user_sessionsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x35\x00\xca\xff\x42\x45\x47\x49\x4e\x3b\x0a\x0a\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x75\x73\x65\x72\x5f\x73\x65\x73\x73\x69\x6f\x6e\x73\x3b\x0a\x0a\x43\x4f\x4d\x4d\x49\x54\x3b\x0a\x03\x00\xf0\xf5\x9e\x39\x35\x00\x00\x00")
+
+func _1528395670_create_user_sessionsDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395670_create_user_sessionsDownSql,
+		"1528395670_create_user_sessions.down.sql",
+	)
+}
+
+func _1528395670_create_user_sessionsDownSql() (*asset, error) {
+	bytes, err := _1528395670_create_user_sessionsDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395670_create_user_sessions.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x71, 0x84, 0xe, 0x16, 0xc6, 0xbe, 0xc7, 0x8c, 0xbf, 0xa8, 0xf6, 0x76, 0x6c, 0xe2, 0x7, 0x68, 0x1b, 0x50, 0x5f, 0xeb, 0x7, 0x2d, 0xbc, 0x48, 0xbc, 0x4c, 0x13, 0xbf, 0x79, 0x6c, 0x5f, 0x5c}}
+	return a, nil
+}
+
+var __1528395670_create_user_sessionsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x9c\x91\xc1\x4e\xc3\x30\x10\x44\xef\xfe\x8a\xbd\x35\x91\xf8\x83\x9e\xdc\x64\x0b\x16\xa9\x83\x1c\x57\xb4\xa7\xc8\x34\xab\xb2\xa2\x4d\x22\xdb\xb4\x85\xaf\x47\x4a\x80\x16\x21\x15\x89\xa3\x35\x9e\x37\xa3\x9d\x19\xde\x2a\x3d\x15\x22\x33\x28\x2d\x82\x95\xb3\x02\x41\xcd\x41\x97\x16\x70\xa5\x2a\x5b\xc1\x6b\x20\x5f\x07\x0a\x81\xbb\x36\x40\x22\x00\x00\xb8\x81\x27\xde\x06\xf2\xec\x76\xf0\x60\xd4\x42\x9a\x35\xdc\xe3\xfa\x66\x50\x07\x07\x37\xc0\x6d\xa4\x2d\xf9\x01\xa6\x97\x45\x01\x06\xe7\x68\x50\x67\x38\x52\x43\xc2\x4d\x0a\xa5\x86\x1c\x0b\xb4\x08\x99\xac\x32\x99\xe3\x08\xe1\x1e\x22\x9d\xe2\xd9\x9c\xe3\x5c\x2e\x0b\x0b\x93\xc9\x45\x8a\xdb\x52\x1b\xff\xf8\xb8\xf1\xe4\x22\x35\xb5\x8b\x10\x79\x4f\x21\xba\x7d\x0f\x47\x8e\xcf\xc3\x13\xde\xbb\x96\x7e\x9b\xdb\xee\x98\xa4\x63\xd0\xce\x85\x58\xbb\x4d\xe4\x03\xfd\x9b\xe1\xe9\xd0\xbd\x5c\xef\x30\x86\xd1\xa9\x67\xff\x56\xf7\xe4\xb9\x1b\x2f\xe8\x0f\x6e\xf7\xcd\x16\xe9\x79\x2c\xa5\x73\x5c\x5d\x1b\xab\xfe\x1a\xa2\xd4\x3f\x85\xe4\x53\x48\xe1\xf1\x0e\x0d\x5e\xb6\x53\xd5\x90\x33\x15\x22\x2b\x17\x0b\x65\xa7\xe2\x63\x00\x72\x3e\x14\x6b\x23\x02\x00\x00")
+
+func _1528395670_create_user_sessionsUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395670_create_user_sessionsUpSql,
+		"1528395670_create_user_sessions.up.sql",
+	)
+}
+
+func _1528395670_create_user_sessionsUpSql() (*asset, error) {
+	bytes, err := _1528395670_create_user_sessionsUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395670_create_user_sessions.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xac, 0x21, 0xab, 0x33, 0x8, 0x63, 0x72, 0xde, 0x38, 0xfb, 0x1c, 0x12, 0x69, 0xb, 0xea, 0xe, 0xec, 0xa6, 0x51, 0xf7, 0x2, 0x15, 0xf9, 0x32, 0x1c, 0xfd, 0x1, 0x8c, 0xe6, 0xc2, 0xb6, 0x4b}}
+	return a, nil
+}
+
+var __1528395671_create_user_totpDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x60\x00\x9f\xff\x42\x45\x47\x49\x4e\x3b\x0a\x0a\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x75\x73\x65\x72\x5f\x74\x6f\x74\x70\x5f\x72\x65\x63\x6f\x76\x65\x72\x79\x5f\x63\x6f\x64\x65\x73\x3b\x0a\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x75\x73\x65\x72\x5f\x74\x6f\x74\x70\x3b\x0a\x0a\x43\x4f\x4d\x4d\x49\x54\x3b\x0a\x03\x00\x7e\xf3\xc6\xef\x60\x00\x00\x00")
+
+func _1528395671_create_user_totpDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395671_create_user_totpDownSql,
+		"1528395671_create_user_totp.down.sql",
+	)
+}
+
+func _1528395671_create_user_totpDownSql() (*asset, error) {
+	bytes, err := _1528395671_create_user_totpDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395671_create_user_totp.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x76, 0xa7, 0xce, 0xbf, 0x64, 0x58, 0x63, 0x5c, 0xd1, 0x1a, 0xda, 0xbc, 0xd1, 0x74, 0x47, 0x66, 0xa5, 0x87, 0x86, 0xe8, 0x2c, 0x47, 0x6, 0x2a, 0xbb, 0x3a, 0x86, 0x3, 0xb8, 0xd2, 0x96, 0x88}}
+	return a, nil
+}
+
+var __1528395671_create_user_totpUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x94\x91\x41\x6f\xe2\x30\x10\x85\xef\xf9\x15\x73\x4c\x24\x0e\x7b\xe7\x14\x92\x61\xd7\xda\xe0\xac\x12\xa3\x85\x93\x65\x92\x29\x58\x82\x24\xb2\x87\xd2\xf6\xd7\x57\x24\x80\xa8\x4a\x29\x3d\x5a\x7e\xf3\xde\x9b\xf9\x26\xf8\x5b\xc8\x71\x10\x24\x05\xc6\x0a\x41\xc5\x93\x0c\x41\x4c\x41\xe6\x0a\x70\x21\x4a\x55\xc2\xde\x93\xd3\xdc\x72\x07\x61\x00\x00\xc3\xdb\xd6\x60\x1b\xa6\x35\x39\xf8\x57\x88\x59\x5c\x2c\xe1\x2f\x2e\xa1\xc0\x29\x16\x28\x13\x1c\xc6\x7c\x68\xeb\x08\x72\x09\x29\x66\xa8\x10\x92\xb8\x4c\xe2\x14\x47\xbd\x8f\xa7\xca\x11\x03\xd3\x0b\xf7\x71\x72\x9e\x65\xc3\x0f\x35\x66\xb5\xa5\x5a\x1b\x06\xb6\x3b\xf2\x6c\x76\x1d\x1c\x2c\x6f\xfa\x27\xbc\xb5\x0d\x0d\xc2\x27\x63\x07\x1d\xd3\xae\x63\x7f\xa9\x74\xb6\x83\x14\xa7\xf1\x3c\x53\xf0\x6b\xd0\x6f\x8d\x67\xfd\x71\xe8\xfb\x90\xca\x91\xe1\xfb\x6d\x3e\x07\x36\xed\x21\x8c\xae\x42\xf7\x9e\x6a\xed\x99\x3a\x58\xd9\xb5\x6d\x38\x88\x1e\x3c\xba\x76\x54\xb5\xcf\xe4\x5e\x75\xd5\xd6\xe4\x4f\x0c\x6c\x7d\xf4\xf1\xe4\xac\xd9\x5e\x03\x18\xdd\x24\x74\x69\xf7\x13\x3c\xc7\x38\xbd\x31\x7e\x73\x8b\xd0\xde\xdf\x3f\xc8\xf5\x7a\x42\xa6\xb8\x78\x70\x3d\x7d\xae\x9e\xcb\x2f\x35\xe1\x49\x13\xc1\xff\x3f\x58\xe0\xa5\x8b\x28\x7b\x04\xe3\x20\x48\xf2\xd9\x4c\xa8\x71\xf0\x3e\x00\x4b\x19\x5c\x08\xdd\x02\x00\x00")
+
+func _1528395671_create_user_totpUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395671_create_user_totpUpSql,
+		"1528395671_create_user_totp.up.sql",
+	)
+}
+
+func _1528395671_create_user_totpUpSql() (*asset, error) {
+	bytes, err := _1528395671_create_user_totpUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395671_create_user_totp.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x13, 0xe4, 0xa2, 0x47, 0x8c, 0x8d, 0xbd, 0x63, 0xd1, 0xaf, 0xf, 0x26, 0x34, 0x14, 0xc3, 0x9a, 0xd7, 0xdb, 0x74, 0x4, 0xd7, 0xe0, 0xd, 0xe5, 0x6, 0xfc, 0xc0, 0x4c, 0xce, 0xd7, 0x6e, 0x29}}
+	return a, nil
+}
+
+var __1528395672_create_repo_access_logsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x38\x00\xc7\xff\x42\x45\x47\x49\x4e\x3b\x0a\x0a\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x72\x65\x70\x6f\x5f\x61\x63\x63\x65\x73\x73\x5f\x6c\x6f\x67\x73\x3b\x0a\x0a\x43\x4f\x4d\x4d\x49\x54\x3b\x0a\x03\x00\xbd\x3b\x8b\x8c\x38\x00\x00\x00")
+
+func _1528395672_create_repo_access_logsDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395672_create_repo_access_logsDownSql,
+		"1528395672_create_repo_access_logs.down.sql",
+	)
+}
+
+func _1528395672_create_repo_access_logsDownSql() (*asset, error) {
+	bytes, err := _1528395672_create_repo_access_logsDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395672_create_repo_access_logs.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x46, 0x7d, 0xea, 0xda, 0xe9, 0xc1, 0x9a, 0x4, 0x92, 0x34, 0x72, 0xc9, 0x1c, 0x17, 0x29, 0x4b, 0xb7, 0x8, 0x91, 0x1d, 0x2e, 0x54, 0x13, 0x19, 0xd2, 0xb3, 0x74, 0xc3, 0xcf, 0x23, 0x2e, 0x81}}
+	return a, nil
+}
+
+var __1528395672_create_repo_access_logsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\xa4\x91\x41\x6f\xda\x40\x10\x85\xef\xfb\x2b\xde\x2d\x20\x91\xaa\x77\x4e\x24\xd9\x54\x56\xc1\x54\x8e\x23\x25\x27\x6b\xeb\x1d\xec\x11\xf6\xae\xbb\x3b\x88\xd2\x5f\x5f\xd9\x84\x36\x01\x4b\xa8\xea\xd5\xef\x7d\x9f\xc7\x7e\x77\xfa\x4b\x92\xce\x95\xba\xbd\x45\x46\x9d\x8f\x2c\x3e\x30\x45\x18\x67\xb1\x8b\x14\x22\x4c\x20\x38\x2f\x08\xb4\xa1\x40\xae\x24\x8b\x3d\x4b\x8d\x8d\x0f\xc4\x95\xc3\x96\x0e\x71\x86\xe8\x21\xb5\x11\x48\x4d\x68\x7c\x05\x8e\xd8\x52\x27\x60\x27\xa6\x94\x5e\x3f\xd9\xf8\x00\xb3\xb3\x2c\x71\x8a\x7d\x4d\xae\xef\x1e\x06\xbd\xa5\x86\x84\xec\x27\x75\x9f\xe9\x45\xae\x91\x2f\xee\x96\x1a\xc9\x23\xd2\x75\x0e\xfd\x92\x3c\xe5\x4f\x08\xd4\xf9\xc2\x94\x25\xc5\x58\x34\xbe\x8a\x98\x28\x00\x60\x8b\xef\x5c\x45\x0a\x6c\x1a\x7c\xcb\x92\xd5\x22\x7b\xc5\x57\xfd\x3a\x1b\xd2\x01\x62\xdb\x5f\x41\x15\x85\xc1\x97\x3e\x2f\x97\xef\x52\x67\x5a\x82\xd0\x4f\x39\x0b\xfb\x8f\x1f\x43\xf1\xa0\x1f\x17\xcf\xcb\x1c\x9f\x8f\x92\x2d\x3b\x3b\xc6\x77\x46\xea\x8f\xcf\xff\x90\x37\x37\xc7\x4a\xe9\xdb\x96\xa5\xe0\x33\xfe\xa2\xf7\x63\x47\xe1\x70\xa5\xc3\xdd\x95\x42\x19\xc8\x08\xd9\xa2\xdf\x88\x5b\x8a\x62\xda\xee\x38\xa4\x70\x4b\xf8\xe5\x1d\x5d\xc2\xce\xef\x27\x53\x35\x9d\xab\xd3\x32\x49\xfa\xa0\x5f\xae\x2c\x53\x9c\xfe\xfa\x3a\xbd\xc8\x26\x6f\xd9\xec\xdd\x3d\xd3\xf9\x3f\xd9\x4f\xc3\x8c\xd9\xdf\xb2\xff\xb0\xff\x05\x47\x5f\xf0\xc1\xab\xee\xd7\xab\x55\x92\xcf\xd5\xef\x01\x00\xd4\xd8\xed\x47\x45\x03\x00\x00")
+
+func _1528395672_create_repo_access_logsUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395672_create_repo_access_logsUpSql,
+		"1528395672_create_repo_access_logs.up.sql",
+	)
+}
+
+func _1528395672_create_repo_access_logsUpSql() (*asset, error) {
+	bytes, err := _1528395672_create_repo_access_logsUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395672_create_repo_access_logs.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x1e, 0x61, 0x8b, 0xa0, 0xbd, 0x66, 0x9, 0x7d, 0x2a, 0xdc, 0x84, 0x9c, 0x8a, 0x5c, 0xf3, 0x73, 0x57, 0x2b, 0x4f, 0x51, 0x1d, 0x1f, 0xcc, 0x95, 0x2e, 0x6e, 0x2f, 0x35, 0x80, 0xd1, 0x10, 0x91}}
+	return a, nil
+}
+
+var __1528395673_repo_update_scheduleDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x3c\x00\xc3\xff\x42\x45\x47\x49\x4e\x3b\x0a\x0a\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x72\x65\x70\x6f\x5f\x75\x70\x64\x61\x74\x65\x5f\x73\x63\x68\x65\x64\x75\x6c\x65\x3b\x0a\x0a\x43\x4f\x4d\x4d\x49\x54\x3b\x0a\x03\x00\xb0\xbf\x92\xc4\x3c\x00\x00\x00")
+
+func _1528395673_repo_update_scheduleDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395673_repo_update_scheduleDownSql,
+		"1528395673_repo_update_schedule.down.sql",
+	)
+}
+
+func _1528395673_repo_update_scheduleDownSql() (*asset, error) {
+	bytes, err := _1528395673_repo_update_scheduleDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395673_repo_update_schedule.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x2c, 0xaa, 0x7f, 0x73, 0x93, 0x72, 0x71, 0xe2, 0x24, 0x16, 0x69, 0xa3, 0x6b, 0x30, 0xe0, 0x65, 0x9c, 0x45, 0x81, 0x40, 0x5e, 0x47, 0xc6, 0x54, 0x97, 0x9a, 0x20, 0x4f, 0xfc, 0x94, 0x65, 0xa8}}
+	return a, nil
+}
+
+var __1528395673_repo_update_scheduleUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x84\x90\xcd\x6a\xeb\x30\x10\x46\xf7\x7a\x8a\xd9\x25\x86\xbb\xb8\x7b\xaf\x14\x7b\x5c\x4c\xfd\x53\x6c\x05\x9a\x95\x10\xd6\xa4\x11\x38\x96\x91\xe4\xa4\xf4\xe9\x8b\x23\x30\xa5\xa5\x74\x29\xf4\x9d\xc3\x61\x0e\xf8\x54\x36\x29\x63\x59\x87\x5c\x20\x08\x7e\xa8\x10\xca\x02\x9a\x56\x00\xbe\x96\xbd\xe8\xc1\xd1\x6c\xe5\x32\x6b\x15\x48\xfa\xe1\x42\x7a\x19\x09\xf6\x0c\x00\xe2\x97\xd1\x60\xa6\x40\x6f\xe4\xe0\xa5\x2b\x6b\xde\x9d\xe0\x19\x4f\xd0\x61\x81\x1d\x36\x19\x46\xc3\xde\xe8\x04\xda\x06\x72\xac\x50\x20\x64\xbc\xcf\x78\x8e\xff\x1e\x9a\x15\x77\x37\x35\x4a\x4f\x83\x9d\xb4\xdf\x7c\x6b\x45\x73\xac\xaa\x38\xd3\x0b\x41\x30\x57\xf2\x41\x5d\x67\xb8\x9b\x70\x79\x3c\xe1\xc3\x4e\xf4\x6d\x3a\x2a\x1f\xe4\x99\xc2\x9a\xfb\x2b\xf3\x65\x4a\xce\x59\x07\x81\xde\xc3\x26\x82\x1c\x0b\x7e\xac\x04\xec\x76\x71\x38\xd8\xc9\xd3\xb0\x04\x73\x23\x79\x56\x66\x5c\x1c\xfd\x2c\xdd\xa8\xff\x11\x8a\x77\xd3\x52\x85\xbf\xd3\x37\x76\xb2\xf7\x7d\xc2\x92\x94\xb1\xac\xad\xeb\x52\xa4\xec\x73\x00\xe2\xda\x7d\xc3\xa7\x01\x00\x00")
+
+func _1528395673_repo_update_scheduleUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395673_repo_update_scheduleUpSql,
+		"1528395673_repo_update_schedule.up.sql",
+	)
+}
+
+func _1528395673_repo_update_scheduleUpSql() (*asset, error) {
+	bytes, err := _1528395673_repo_update_scheduleUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395673_repo_update_schedule.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x3d, 0x57, 0xb4, 0x2f, 0x7f, 0x90, 0xd0, 0x86, 0x4a, 0xc5, 0x3e, 0x67, 0x16, 0xe4, 0x1a, 0x95, 0x24, 0xa3, 0xac, 0x1d, 0xf9, 0xf7, 0x8, 0x56, 0x72, 0x1, 0x6b, 0x3d, 0xb0, 0xa6, 0xcf, 0xb7}}
+	return a, nil
+}
+
+var __1528395674_repo_disk_usageDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x37\x00\xc8\xff\x42\x45\x47\x49\x4e\x3b\x0a\x0a\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x72\x65\x70\x6f\x5f\x64\x69\x73\x6b\x5f\x75\x73\x61\x67\x65\x3b\x0a\x0a\x43\x4f\x4d\x4d\x49\x54\x3b\x0a\x03\x00\x45\x7a\x8b\xdc\x37\x00\x00\x00")
+
+func _1528395674_repo_disk_usageDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395674_repo_disk_usageDownSql,
+		"1528395674_repo_disk_usage.down.sql",
+	)
+}
+
+func _1528395674_repo_disk_usageDownSql() (*asset, error) {
+	bytes, err := _1528395674_repo_disk_usageDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395674_repo_disk_usage.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xc8, 0x20, 0x46, 0xea, 0x6, 0x19, 0x6c, 0x2, 0xaa, 0x9c, 0x76, 0xd3, 0x8c, 0xa0, 0x1c, 0xcb, 0x4, 0xc4, 0x2b, 0x40, 0x86, 0xe6, 0x92, 0x9c, 0xd6, 0xa, 0x42, 0x18, 0xba, 0xc4, 0xbc, 0xba}}
+	return a, nil
+}
+
+var __1528395674_repo_disk_usageUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x7c\x90\xc1\x8a\xb3\x30\x14\x85\xf7\x3e\xc5\x59\x2a\xf4\x0d\xba\x4a\xf5\xfa\x13\x7e\x8d\x83\xa6\xd0\xae\x42\x4a\x2e\x36\x0c\x55\x31\x29\x9d\xe9\xd3\x0f\x68\xe9\x94\x19\x98\x65\x72\xf8\x0e\xe7\x7e\x3b\xfa\x27\xd5\x36\x49\xf2\x96\x84\x26\x68\xb1\xab\x08\xb2\x84\x6a\x34\xe8\x20\x3b\xdd\x61\xe6\x69\x34\xce\x87\x77\x73\x0d\xb6\x67\xa4\x09\x80\xf5\xd7\x3b\xf8\x21\x72\xcf\xf3\x02\xa8\x7d\x55\xa1\xa5\x92\x5a\x52\x39\xad\x64\xea\x5d\x86\x46\xa1\xa0\x8a\x34\x21\x17\x5d\x2e\x0a\xda\x2c\x1d\xe1\x6c\x67\x87\xc8\x1f\xf1\x89\x3f\x02\x7f\x67\x73\xfa\x8c\x1c\x70\xf2\xbd\x1f\x7e\xe6\xd7\xc9\xd9\xc8\xce\xd8\x88\xe8\x2f\x1c\xa2\xbd\x4c\xb8\xf9\x78\x5e\x9e\xb8\x8f\x03\x3f\x09\x14\x54\x8a\x7d\xa5\x31\x8c\xb7\x34\x5b\xf9\xb7\x56\xd6\xa2\x3d\xe2\x3f\x1d\x91\x3e\x2e\xd9\xac\x73\xb2\x24\xfb\xd6\x21\x55\x41\x87\xbf\x75\x98\x97\xad\x8d\xfa\x2d\xeb\x25\x2e\xa8\xcb\x97\xf2\xa6\xae\xa5\xde\x26\x5f\x03\x00\xdb\x7b\x4a\xb0\x7c\x01\x00\x00")
+
+func _1528395674_repo_disk_usageUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395674_repo_disk_usageUpSql,
+		"1528395674_repo_disk_usage.up.sql",
+	)
+}
+
+func _1528395674_repo_disk_usageUpSql() (*asset, error) {
+	bytes, err := _1528395674_repo_disk_usageUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395674_repo_disk_usage.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x71, 0xce, 0xed, 0x58, 0x5b, 0xde, 0xb2, 0xdb, 0x76, 0x4, 0x8, 0x16, 0x8, 0x8f, 0x8a, 0x3b, 0xa7, 0x5f, 0x52, 0x8b, 0xd3, 0x2f, 0x24, 0xb6, 0x73, 0xb8, 0xca, 0xae, 0x37, 0xcc, 0x56, 0x52}}
+	return a, nil
+}
+
+var __1528395675_lsif_indexesDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x34\x00\xcb\xff\x42\x45\x47\x49\x4e\x3b\x0a\x0a\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x6c\x73\x69\x66\x5f\x69\x6e\x64\x65\x78\x65\x73\x3b\x0a\x0a\x43\x4f\x4d\x4d\x49\x54\x3b\x0a\x03\x00\x06\xa4\x2c\x49\x34\x00\x00\x00")
+
+func _1528395675_lsif_indexesDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395675_lsif_indexesDownSql,
+		"1528395675_lsif_indexes.down.sql",
+	)
+}
+
+func _1528395675_lsif_indexesDownSql() (*asset, error) {
+	bytes, err := _1528395675_lsif_indexesDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395675_lsif_indexes.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xb0, 0x30, 0xd1, 0xbb, 0xbf, 0xac, 0x94, 0xfb, 0x27, 0x32, 0x62, 0x57, 0xa3, 0xaf, 0x5d, 0xbe, 0x90, 0x7f, 0xd3, 0xbb, 0x3e, 0x79, 0x45, 0x9a, 0x76, 0xba, 0xe9, 0x9f, 0xca, 0x42, 0x41, 0x67}}
+	return a, nil
+}
+
+var __1528395675_lsif_indexesUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x8c\x92\x4f\x8f\xd3\x30\x10\xc5\xef\xf9\x14\x73\x40\x4a\x22\x65\xa5\x3d\x70\x41\x3d\x65\xd3\x29\x44\x9b\x3a\x90\xa6\xd2\xae\x10\x58\x56\x32\xdb\x5a\x24\x71\xb0\x1d\xf6\x0f\x82\xcf\x8e\xb0\xdb\x6d\xcb\xa2\xc2\x71\x3c\x3f\xcf\xd3\xcc\x7b\x57\xf8\x36\x67\xb3\x20\xc8\x2a\x4c\x6b\x84\x3a\xbd\x2a\x10\xf2\x05\xb0\xb2\x06\xbc\xc9\x57\xf5\x0a\x3a\x23\xef\xb8\x1c\x5a\x7a\x20\x03\x51\x00\x00\x20\x5b\x30\xa4\xa5\xe8\xe0\x7d\x95\x2f\xd3\xea\x16\xae\xf1\x36\x71\xad\x46\xf5\xbd\xb4\x60\xe9\xc1\xba\x21\x6c\x5d\x14\xbe\xa3\x69\x54\x46\x5a\xa5\x1f\xb9\x6c\x41\x0e\x96\x36\xa4\xff\x60\xbc\x8c\xfe\xdb\x77\x63\x85\xa5\xd3\x06\xcc\x71\x91\xae\x8b\x1a\xc2\xaf\x13\x4d\xd4\x86\x9e\xf4\x05\x17\x16\xac\xec\xc9\x58\xd1\x8f\x70\x2f\xed\xd6\x95\xf0\xa4\x06\x7a\x39\x61\x50\xf7\x51\xfc\x2c\xa4\xed\xf9\xff\x1e\xbc\x93\x83\x34\xdb\xff\x22\x85\xec\x26\x4d\xdc\x4c\x7d\x2f\xf4\xa3\xdb\xc2\x77\x3a\xb5\x39\xaa\xa6\xb1\x53\xa2\x3d\x3e\x4f\x85\x0b\xac\x90\x65\xb8\xf3\xc1\x13\x26\x92\x6d\x0c\x25\x83\x39\x16\x58\x23\xac\xf0\xf8\x52\x59\xc9\x56\x75\x95\xe6\xac\x3e\xf1\x8e\x7b\x6b\xf8\x37\xd1\xc9\x96\x37\x5b\xa1\x0d\x64\xef\x30\xbb\x86\x68\x67\xda\x4f\x08\x3f\x7f\x14\x17\x4f\x97\x17\x6f\x3e\x7d\x7f\x7d\xf9\xe3\x55\x18\x9f\x9f\xe8\x3c\xe1\xcd\x96\x9a\x2f\xfb\x51\xee\x09\x72\x06\xd1\xb3\x29\x10\x8e\x5a\x35\x64\x8c\x1c\x36\x61\x02\x61\xa3\xfa\xb1\x23\xfb\xdb\x2f\x08\x49\x6b\xa5\xa9\x0d\xe3\x38\x88\x0f\x39\x5c\xb3\xfc\xc3\x1a\x21\x67\x73\xbc\x39\x13\x47\x7e\x94\xa9\xdd\x76\xfb\x04\x95\xec\x84\x84\xe8\x24\x7e\x09\x78\x3c\x81\x1d\x1f\xcf\xf6\xd2\xff\xd4\x74\x1b\xf2\x43\xc8\x5e\x28\x39\x20\x39\xc4\xd0\xed\x55\x2e\x97\x79\x3d\x0b\x7e\x0d\x00\x94\xd7\x6e\xc3\x70\x03\x00\x00")
+
+func _1528395675_lsif_indexesUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395675_lsif_indexesUpSql,
+		"1528395675_lsif_indexes.up.sql",
+	)
+}
+
+func _1528395675_lsif_indexesUpSql() (*asset, error) {
+	bytes, err := _1528395675_lsif_indexesUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395675_lsif_indexes.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xab, 0x4f, 0xce, 0x24, 0x77, 0x53, 0x6a, 0xa6, 0x8b, 0x21, 0x7d, 0x6b, 0xe3, 0x42, 0x2c, 0xdc, 0x42, 0xfc, 0x6e, 0x4a, 0xba, 0x8e, 0x2, 0xec, 0xad, 0xe9, 0x52, 0x5, 0xb5, 0xa2, 0x75, 0x9c}}
+	return a, nil
+}
+
+var __1528395676_lsif_reference_identifiersDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x74\xce\x3d\x0a\x02\x31\x10\xc5\xf1\x7e\x4e\x31\xf7\x48\xb5\x1f\xa3\x0c\x6c\x12\xd9\x8d\xb0\x5d\x0a\x33\x81\x01\x49\x91\x28\x78\x7c\x61\x05\x09\x82\xfd\x7b\x3f\xfe\x23\x9d\xd9\x19\x80\x79\xf5\x17\x64\x37\xd3\x8e\x7c\x42\xda\x79\x0b\x1b\xde\x9b\xe6\x58\x25\x4b\x95\x72\x93\x16\x35\x49\x79\x68\x56\xa9\x2d\x3e\x8b\x96\x24\x2f\x49\x06\x86\x25\xd0\x8a\x61\x18\x17\xfa\xbd\xe0\xe1\x4e\x7e\xb9\x5a\xd7\xc1\x3d\xf4\x65\x8e\xe9\x47\xf9\x97\xd0\x17\x18\x80\xc9\x5b\xcb\xc1\xc0\x7b\x00\x61\xa9\x92\xf8\xc5\x00\x00\x00")
+
+func _1528395676_lsif_reference_identifiersDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395676_lsif_reference_identifiersDownSql,
+		"1528395676_lsif_reference_identifiers.down.sql",
+	)
+}
+
+func _1528395676_lsif_reference_identifiersDownSql() (*asset, error) {
+	bytes, err := _1528395676_lsif_reference_identifiersDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395676_lsif_reference_identifiers.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x4a, 0xc8, 0xdb, 0xa2, 0x2d, 0xc4, 0xb7, 0x7d, 0x5, 0x74, 0xf3, 0x69, 0x2f, 0xcc, 0xad, 0x99, 0x8, 0x44, 0x97, 0x1b, 0xda, 0xf8, 0x1, 0xa7, 0xf6, 0xe3, 0xbb, 0xe9, 0x93, 0xdd, 0xe4, 0x12}}
+	return a, nil
+}
+
+var __1528395676_lsif_reference_identifiersUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x9c\x92\xcd\x8e\x9b\x30\x14\x85\xf7\x3c\xc5\x59\x4e\xa4\x61\x5e\x20\x2b\x06\x6e\x5a\x24\x42\x24\xe2\xa8\xb3\x43\x24\xbe\x04\xab\xc6\x8e\x6c\xd3\xc9\xbc\x7d\x45\xc2\x64\x48\x5b\xf5\x6f\xeb\xfb\x73\xbe\x7b\x8e\x9f\xe9\x53\x5e\x2e\xa3\x28\xad\x28\x11\x04\x91\x3c\x17\x84\x7c\x85\x72\x23\x40\x2f\xf9\x56\x6c\xa1\xbd\x6a\x6b\xc7\x2d\x3b\x36\x07\xae\x95\x64\x13\x54\xab\xd8\x79\x3c\x44\x00\x20\x87\xfe\x54\x2b\x09\x65\x02\x1f\xd9\x5d\x66\xcb\x5d\x51\xa0\xa2\x15\x55\x54\xa6\x34\x2d\x19\x4e\xda\x36\xd2\x3f\x28\xb9\xc0\xa6\x44\x46\x05\x09\x42\x9a\x6c\xd3\x24\xa3\xc7\xcb\x2e\x7f\xe8\xb8\x67\x04\x3e\x87\xdb\x9e\x6b\xe5\x43\xf7\xbe\x1a\x2d\x3e\xf0\xf3\x32\xa3\x97\xbf\xc6\xaf\xaf\x62\xb3\xa7\x91\xea\x77\xe7\x5e\x07\x1e\x67\x2c\x8b\xe5\x7f\x6a\xbf\x9b\xf6\x07\xc5\xa9\x6d\xbc\x31\x8e\x41\x67\xe5\x83\x32\x47\xdc\xba\x3d\xac\xd1\x6f\xe8\x9a\x6f\x8c\x06\x7b\x6d\x6d\x8f\x56\xe9\xc0\x0e\xb6\x45\xe8\x58\xb9\x19\xad\x7f\x82\xe8\xd8\x33\x9c\x7d\xf5\x68\x1c\x43\x19\xc9\x67\x96\x51\x1c\xa3\x75\xb6\x9f\x26\xf6\x83\x91\x9a\x3d\xf6\x6f\xe3\x03\x4e\x8e\x0f\xca\x73\x7c\xb0\x92\xe3\x31\x65\x1d\xbf\x5a\xf7\x95\x1d\x9a\x76\x54\x0a\x9d\xf2\xe8\xd5\xd1\x35\x41\x59\x03\x37\x18\xff\x14\x25\x85\xa0\x6a\xfa\x50\xf7\x17\x7a\x24\x59\x86\x74\x53\xec\xd6\xe5\x0f\x86\xcd\x50\xeb\x09\x0d\x7b\x6b\x35\x37\xe6\x16\x38\x32\x5a\x25\xbb\x42\xa0\x6d\xb4\xe7\x7f\xf0\xdf\xdf\x05\x30\x98\x77\x81\x9f\x22\x98\xf9\x8e\x2f\x9f\xa9\xa2\x8b\xf6\x2f\xd8\x96\x51\x94\x6e\xd6\xeb\x5c\x2c\xa3\xef\x03\x00\x99\x5f\x3d\x1f\x49\x03\x00\x00")
+
+func _1528395676_lsif_reference_identifiersUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395676_lsif_reference_identifiersUpSql,
+		"1528395676_lsif_reference_identifiers.up.sql",
+	)
+}
+
+func _1528395676_lsif_reference_identifiersUpSql() (*asset, error) {
+	bytes, err := _1528395676_lsif_reference_identifiersUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395676_lsif_reference_identifiers.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x7, 0xb0, 0xc, 0x38, 0x32, 0x5, 0x32, 0xb, 0xba, 0x84, 0x27, 0xd7, 0x9d, 0xc6, 0xc9, 0xba, 0x65, 0x4, 0x4b, 0x25, 0xbc, 0x3d, 0xc5, 0x19, 0x15, 0xa8, 0x26, 0x29, 0x7c, 0x66, 0xa3, 0x4d}}
+	return a, nil
+}
+
+var __1528395677_lsif_upload_diagnosticsDownSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x00\x3f\x00\xc0\xff\x42\x45\x47\x49\x4e\x3b\x0a\x0a\x44\x52\x4f\x50\x20\x54\x41\x42\x4c\x45\x20\x49\x46\x20\x45\x58\x49\x53\x54\x53\x20\x6c\x73\x69\x66\x5f\x75\x70\x6c\x6f\x61\x64\x5f\x64\x69\x61\x67\x6e\x6f\x73\x74\x69\x63\x73\x3b\x0a\x0a\x43\x4f\x4d\x4d\x49\x54\x3b\x0a\x03\x00\xa7\x53\xfe\x39\x3f\x00\x00\x00")
+
+func _1528395677_lsif_upload_diagnosticsDownSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395677_lsif_upload_diagnosticsDownSql,
+		"1528395677_lsif_upload_diagnostics.down.sql",
+	)
+}
+
+func _1528395677_lsif_upload_diagnosticsDownSql() (*asset, error) {
+	bytes, err := _1528395677_lsif_upload_diagnosticsDownSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395677_lsif_upload_diagnostics.down.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x12, 0x9f, 0xc, 0x8, 0x1d, 0xae, 0x1c, 0x9, 0xcb, 0x4f, 0x60, 0xf6, 0xc8, 0x86, 0x35, 0x54, 0xf8, 0x34, 0x5c, 0x3, 0xe6, 0x2f, 0x69, 0xd, 0x3b, 0x5f, 0xee, 0xcd, 0x3d, 0xc9, 0x52, 0x60}}
+	return a, nil
+}
+
+var __1528395677_lsif_upload_diagnosticsUpSql = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x8c\x90\xd1\x4a\xc3\x30\x18\x85\xef\xfb\x14\xe7\xb2\x03\xdf\x60\x57\x59\xfb\x4f\x82\x6d\x2a\x69\x84\xed\xaa\x94\xe5\xb7\xfc\x90\xb5\xd2\x44\xd1\xb7\x17\x3b\x9d\x88\x15\xbc\x3d\xdf\xc9\x97\xe4\xec\xe8\x56\x9b\x6d\x96\x15\x96\x94\x23\x38\xb5\xab\x08\x7a\x0f\xd3\x38\xd0\x41\xb7\xae\x45\x88\xf2\xd8\x3d\x3f\x85\xa9\xf7\x9d\x97\x7e\x18\xa7\x98\xe4\x14\x91\x67\x00\x20\x1e\x91\x67\xe9\x03\xee\xad\xae\x95\x3d\xe2\x8e\x8e\x37\x0b\xfa\x3c\x23\x1e\x32\x26\x1e\x78\x5e\xac\xe6\xa1\xaa\x60\x69\x4f\x96\x4c\x41\x3f\xf4\x31\x17\xbf\x41\x63\x50\x52\x45\x8e\x50\xa8\xb6\x50\x25\x5d\x6c\x91\x5f\x78\x96\xf4\x86\xc4\xaf\xe9\x6a\xba\xb0\xd3\xe4\x79\x2d\x3f\x73\x8c\xfd\xb0\x8a\x38\xf0\x99\xc7\xd4\x89\x5f\xa3\x41\x46\xfe\xf5\xea\x6c\xf3\x3d\x94\x36\x25\x1d\xfe\x37\xd4\x57\x24\xfe\xe3\x6b\x7f\x94\x90\x5f\x5b\xcb\x2d\x4d\x5d\x6b\xb7\xcd\xde\x07\x00\xfe\x65\x23\xb9\x9f\x01\x00\x00")
+
+func _1528395677_lsif_upload_diagnosticsUpSqlBytes() ([]byte, error) {
+	return bindataRead(
+		__1528395677_lsif_upload_diagnosticsUpSql,
+		"1528395677_lsif_upload_diagnostics.up.sql",
+	)
+}
+
+func _1528395677_lsif_upload_diagnosticsUpSql() (*asset, error) {
+	bytes, err := _1528395677_lsif_upload_diagnosticsUpSqlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "1528395677_lsif_upload_diagnostics.up.sql", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x23, 0x80, 0x65, 0x79, 0xda, 0xff, 0x6b, 0x47, 0xa, 0x79, 0x64, 0x28, 0x69, 0xf7, 0x64, 0xc0, 0x2c, 0xdf, 0x3c, 0x3a, 0xf3, 0x84, 0xd2, 0xe5, 0x35, 0xfc, 0x6c, 0x8f, 0xbd, 0x8, 0xdf, 0x55}}
+	return a, nil
+}
+
 // Asset loads and returns the asset for the given name.
 // It returns an error if the asset could not be found or
 // could not be loaded.
@@ -2491,17 +2827,35 @@ var _bindata = map[string]func() (*asset, error){
 	"1528395668_campaign_description_nullable.up.sql":                         _1528395668_campaign_description_nullableUpSql,
 	"1528395669_add_synced_at_to_perms_tables.down.sql":                       _1528395669_add_synced_at_to_perms_tablesDownSql,
 	"1528395669_add_synced_at_to_perms_tables.up.sql":                         _1528395669_add_synced_at_to_perms_tablesUpSql,
+	"1528395670_create_user_sessions.down.sql":                                _1528395670_create_user_sessionsDownSql,
+	"1528395670_create_user_sessions.up.sql":                                  _1528395670_create_user_sessionsUpSql,
+	"1528395671_create_user_totp.down.sql":                                    _1528395671_create_user_totpDownSql,
+	"1528395671_create_user_totp.up.sql":                                      _1528395671_create_user_totpUpSql,
+	"1528395672_create_repo_access_logs.down.sql":                             _1528395672_create_repo_access_logsDownSql,
+	"1528395672_create_repo_access_logs.up.sql":                               _1528395672_create_repo_access_logsUpSql,
+	"1528395673_repo_update_schedule.down.sql":                                _1528395673_repo_update_scheduleDownSql,
+	"1528395673_repo_update_schedule.up.sql":                                  _1528395673_repo_update_scheduleUpSql,
+	"1528395674_repo_disk_usage.down.sql":                                     _1528395674_repo_disk_usageDownSql,
+	"1528395674_repo_disk_usage.up.sql":                                       _1528395674_repo_disk_usageUpSql,
+	"1528395675_lsif_indexes.down.sql":                                        _1528395675_lsif_indexesDownSql,
+	"1528395675_lsif_indexes.up.sql":                                          _1528395675_lsif_indexesUpSql,
+	"1528395676_lsif_reference_identifiers.down.sql":                          _1528395676_lsif_reference_identifiersDownSql,
+	"1528395676_lsif_reference_identifiers.up.sql":                            _1528395676_lsif_reference_identifiersUpSql,
+	"1528395677_lsif_upload_diagnostics.down.sql":                             _1528395677_lsif_upload_diagnosticsDownSql,
+	"1528395677_lsif_upload_diagnostics.up.sql":                               _1528395677_lsif_upload_diagnosticsUpSql,
 }
 
 // AssetDir returns the file names below a certain
 // directory embedded in the file by go-bindata.
 // For example if you run go-bindata on data/... and data contains the
 // following hierarchy:
-//     data/
-//       foo.txt
-//       img/
-//         a.png
-//         b.png
+//
+//	data/
+//	  foo.txt
+//	  img/
+//	    a.png
+//	    b.png
+//
 // then AssetDir("data") would return []string{"foo.txt", "img"},
 // AssetDir("data/img") would return []string{"a.png", "b.png"},
 // AssetDir("foo.txt") and AssetDir("notexist") would return an error, and
@@ -2640,6 +2994,22 @@ var _bintree = &bintree{nil, map[string]*bintree{
 	"1528395668_campaign_description_nullable.up.sql":                         {_1528395668_campaign_description_nullableUpSql, map[string]*bintree{}},
 	"1528395669_add_synced_at_to_perms_tables.down.sql":                       {_1528395669_add_synced_at_to_perms_tablesDownSql, map[string]*bintree{}},
 	"1528395669_add_synced_at_to_perms_tables.up.sql":                         {_1528395669_add_synced_at_to_perms_tablesUpSql, map[string]*bintree{}},
+	"1528395670_create_user_sessions.down.sql":                                {_1528395670_create_user_sessionsDownSql, map[string]*bintree{}},
+	"1528395670_create_user_sessions.up.sql":                                  {_1528395670_create_user_sessionsUpSql, map[string]*bintree{}},
+	"1528395671_create_user_totp.down.sql":                                    {_1528395671_create_user_totpDownSql, map[string]*bintree{}},
+	"1528395671_create_user_totp.up.sql":                                      {_1528395671_create_user_totpUpSql, map[string]*bintree{}},
+	"1528395672_create_repo_access_logs.down.sql":                             {_1528395672_create_repo_access_logsDownSql, map[string]*bintree{}},
+	"1528395672_create_repo_access_logs.up.sql":                               {_1528395672_create_repo_access_logsUpSql, map[string]*bintree{}},
+	"1528395673_repo_update_schedule.down.sql":                                {_1528395673_repo_update_scheduleDownSql, map[string]*bintree{}},
+	"1528395673_repo_update_schedule.up.sql":                                  {_1528395673_repo_update_scheduleUpSql, map[string]*bintree{}},
+	"1528395674_repo_disk_usage.down.sql":                                     {_1528395674_repo_disk_usageDownSql, map[string]*bintree{}},
+	"1528395674_repo_disk_usage.up.sql":                                       {_1528395674_repo_disk_usageUpSql, map[string]*bintree{}},
+	"1528395675_lsif_indexes.down.sql":                                        {_1528395675_lsif_indexesDownSql, map[string]*bintree{}},
+	"1528395675_lsif_indexes.up.sql":                                          {_1528395675_lsif_indexesUpSql, map[string]*bintree{}},
+	"1528395676_lsif_reference_identifiers.down.sql":                          {_1528395676_lsif_reference_identifiersDownSql, map[string]*bintree{}},
+	"1528395676_lsif_reference_identifiers.up.sql":                            {_1528395676_lsif_reference_identifiersUpSql, map[string]*bintree{}},
+	"1528395677_lsif_upload_diagnostics.down.sql":                             {_1528395677_lsif_upload_diagnosticsDownSql, map[string]*bintree{}},
+	"1528395677_lsif_upload_diagnostics.up.sql":                               {_1528395677_lsif_upload_diagnosticsUpSql, map[string]*bintree{}},
 }}
 
 // RestoreAsset restores an asset under the given directory.
@@ -35,6 +35,11 @@ const BitbucketCloudSchemaJSON = `{
       "format": "uri",
       "examples": ["https://api.bitbucket.org"]
     },
+    "webhookSecret": {
+      "description": "A secret that authenticates Bitbucket Cloud webhooks that notify Sourcegraph of pushes, so that repositories are updated immediately instead of on the next poll. Bitbucket Cloud doesn't sign webhook payloads, so the secret must be part of the webhook URL: https://sourcegraph.example.com/.api/repo-update-webhooks/bitbucketcloud?secret=<webhookSecret>. The webhook must be triggered by \"Repository push\".",
+      "type": "string",
+      "minLength": 1
+    },
     "rateLimit": {
       "description": "Rate limit applied when making background API requests to Bitbucket Cloud.",
       "title": "BitbucketCloudRateLimit",
@@ -73,6 +78,51 @@ const BitbucketCloudSchemaJSON = `{
       "default": "http",
       "examples": ["ssh"]
     },
+    "gitLFS": {
+      "description": "Whether to fetch the contents of files tracked by Git LFS from Bitbucket Cloud. When enabled, search and symbols use the file contents instead of the LFS pointer files, subject to the size limits configured on gitserver. Requires Git URLs of type \"http\".",
+      "type": "boolean",
+      "default": false
+    },
+    "gitLFSRefs": {
+      "description": "The refs (such as branches or tags) whose Git LFS objects are fetched when gitLFS is enabled. Search for other revisions uses the LFS pointer files.",
+      "type": "array",
+      "items": { "type": "string" },
+      "default": ["HEAD"],
+      "examples": [["HEAD", "refs/heads/release"]]
+    },
+    "cloneStrategies": {
+      "description": "How to clone very large repositories. The first strategy whose pattern matches the name of a repository applies to it; other repositories are fully cloned. Shallow and blobless clones save disk space and clone time, but search over commit messages and diffs, and blame, are unavailable for shallowly cloned repositories. Changing the mode of a repository reclones it.",
+      "type": "array",
+      "items": {
+        "title": "BitbucketCloudCloneStrategy",
+        "type": "object",
+        "additionalProperties": false,
+        "required": ["pattern", "mode"],
+        "properties": {
+          "pattern": {
+            "description": "Regular expression matched against the repository name, e.g. \"^github\\.com/myorg/monorepo$\".",
+            "type": "string",
+            "format": "regex"
+          },
+          "mode": {
+            "description": "\"full\" clones the whole history. \"shallow\" only clones the most recent history (see depth and since). \"blobless\" clones all commits and trees, but fetches the contents of files (blobs) only when they are needed.",
+            "type": "string",
+            "enum": ["full", "shallow", "blobless"]
+          },
+          "depth": {
+            "description": "For shallow clones, the number of commits of history to fetch from the tip of each branch and tag. Defaults to 1 if since is not set.",
+            "type": "integer",
+            "minimum": 1
+          },
+          "since": {
+            "description": "For shallow clones, only fetch the history after this date (YYYY-MM-DD).",
+            "type": "string",
+            "pattern": "^[0-9]{4}-[0-9]{2}-[0-9]{2}$"
+          }
+        }
+      },
+      "examples": [[{ "pattern": "^github\\.com/myorg/monorepo$", "mode": "shallow", "depth": 1000 }]]
+    },
     "repositoryPathPattern": {
       "description": "The pattern used to generate the corresponding Sourcegraph repository name for a Bitbucket Cloud repository.\n\n - \"{host}\" is replaced with the Bitbucket Cloud URL's host (such as bitbucket.org),  and \"{nameWithOwner}\" is replaced with the Bitbucket Cloud repository's \"owner/path\" (such as \"myorg/myrepo\").\n\nFor example, if your Bitbucket Cloud is https://bitbucket.org and your Sourcegraph is https://src.example.com, then a repositoryPathPattern of \"{host}/{nameWithOwner}\" would mean that a Bitbucket Cloud repository at https://bitbucket.org/alice/my-repo is available on Sourcegraph at https://src.example.com/bitbucket.org/alice/my-repo.\n\nIt is important that the Sourcegraph repository name generated with this pattern be unique to this code host. If different code hosts generate repository names that collide, Sourcegraph's behavior is undefined.",
       "type": "string",
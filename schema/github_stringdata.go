@@ -58,6 +58,51 @@ const GitHubSchemaJSON = `{
         "requestsPeHour": 5000
       }
     },
+    "gitLFS": {
+      "description": "Whether to fetch the contents of files tracked by Git LFS from GitHub. When enabled, search and symbols use the file contents instead of the LFS pointer files, subject to the size limits configured on gitserver. Requires Git URLs of type \"http\".",
+      "type": "boolean",
+      "default": false
+    },
+    "gitLFSRefs": {
+      "description": "The refs (such as branches or tags) whose Git LFS objects are fetched when gitLFS is enabled. Search for other revisions uses the LFS pointer files.",
+      "type": "array",
+      "items": { "type": "string" },
+      "default": ["HEAD"],
+      "examples": [["HEAD", "refs/heads/release"]]
+    },
+    "cloneStrategies": {
+      "description": "How to clone very large repositories. The first strategy whose pattern matches the name of a repository applies to it; other repositories are fully cloned. Shallow and blobless clones save disk space and clone time, but search over commit messages and diffs, and blame, are unavailable for shallowly cloned repositories. Changing the mode of a repository reclones it.",
+      "type": "array",
+      "items": {
+        "title": "GitHubCloneStrategy",
+        "type": "object",
+        "additionalProperties": false,
+        "required": ["pattern", "mode"],
+        "properties": {
+          "pattern": {
+            "description": "Regular expression matched against the repository name, e.g. \"^github\\.com/myorg/monorepo$\".",
+            "type": "string",
+            "format": "regex"
+          },
+          "mode": {
+            "description": "\"full\" clones the whole history. \"shallow\" only clones the most recent history (see depth and since). \"blobless\" clones all commits and trees, but fetches the contents of files (blobs) only when they are needed.",
+            "type": "string",
+            "enum": ["full", "shallow", "blobless"]
+          },
+          "depth": {
+            "description": "For shallow clones, the number of commits of history to fetch from the tip of each branch and tag. Defaults to 1 if since is not set.",
+            "type": "integer",
+            "minimum": 1
+          },
+          "since": {
+            "description": "For shallow clones, only fetch the history after this date (YYYY-MM-DD).",
+            "type": "string",
+            "pattern": "^[0-9]{4}-[0-9]{2}-[0-9]{2}$"
+          }
+        }
+      },
+      "examples": [[{ "pattern": "^github\\.com/myorg/monorepo$", "mode": "shallow", "depth": 1000 }]]
+    },
     "certificate": {
       "description": "TLS certificate of the GitHub Enterprise instance. This is only necessary if the certificate is self-signed or signed by an internal CA. To get the certificate run ` + "`" + `openssl s_client -connect HOST:443 -showcerts < /dev/null 2> /dev/null | openssl x509 -outform PEM` + "`" + `. To escape the value into a JSON string, you may want to use a tool like https://json-escape-text.now.sh.",
       "type": "string",
@@ -77,7 +122,7 @@ const GitHubSchemaJSON = `{
       "examples": [["name"], ["kubernetes", "golang", "facebook"]]
     },
     "webhooks": {
-      "description": "An array of configurations defining existing GitHub webhooks that send updates back to Sourcegraph.",
+      "description": "An array of configurations defining GitHub organization webhooks that send updates back to Sourcegraph. Sourcegraph creates a webhook that notifies it of pushes on each organization, using the token, which must have the admin:org_hook scope.",
       "type": "array",
       "items": {
         "type": "object",
@@ -0,0 +1,53 @@
+// Code generated by stringdata. DO NOT EDIT.
+
+package schema
+
+// PerforceSchemaJSON is the content of the file "perforce.schema.json".
+const PerforceSchemaJSON = `{
+  "$schema": "http://json-schema.org/draft-07/schema#",
+  "$id": "perforce.schema.json#",
+  "title": "PerforceConnection",
+  "description": "Configuration for a connection to Perforce Server.",
+  "allowComments": true,
+  "type": "object",
+  "additionalProperties": false,
+  "required": ["p4.port", "p4.user", "p4.passwd", "depots"],
+  "properties": {
+    "p4.port": {
+      "description": "The Perforce Server address to be used for p4 CLI (P4PORT), including its transport prefix if it has one (e.g., \"ssl:perforce.example.com:1666\").",
+      "type": "string",
+      "pattern": "^(?:(?:tcp|ssl)[0-9]*:)?(?:\\[[^\\]]+\\]:|[^:/\\[\\]]+:)?[0-9]+$",
+      "examples": ["ssl:perforce.example.com:1666", "perforce.example.com:1666"]
+    },
+    "p4.user": {
+      "description": "The user to be authenticated for p4 CLI (P4USER).",
+      "type": "string",
+      "examples": ["admin"]
+    },
+    "p4.passwd": {
+      "description": "The ticket or password of the user to be authenticated for p4 CLI (P4PASSWD).",
+      "type": "string"
+    },
+    "depots": {
+      "description": "Depot paths that are mirrored as repositories. Each depot path is converted to a Git repository, with one commit per changelist that touches it.",
+      "type": "array",
+      "items": {
+        "type": "string",
+        "pattern": "^//[^/]+(/[^/]+)*/?$"
+      },
+      "examples": [["//Sourcegraph/", "//Engineering/Cloud/"]]
+    },
+    "repositoryPathPattern": {
+      "description": "The pattern used to generate the corresponding Sourcegraph repository name for a Perforce depot. In the pattern, the variable \"{host}\" is replaced with the host of the Perforce Server, and \"{depot}\" is replaced with the depot path without its leading and trailing slashes.\n\nFor example, if your Perforce Server is at perforce.example.com:1666 and your depot path is //Sourcegraph/, then a repositoryPathPattern of \"{host}/{depot}\" would mean that the depot is available on Sourcegraph at https://sourcegraph.example.com/perforce.example.com/Sourcegraph.\n\nIt is important that the Sourcegraph repository name generated with this pattern be unique to this Perforce Server. If different code hosts generate repository names that collide, Sourcegraph's behavior is undefined.",
+      "type": "string",
+      "default": "{host}/{depot}",
+      "examples": ["perforce/{depot}"]
+    },
+    "maxChanges": {
+      "description": "The number of most recent changelists of each depot to import when it is cloned. Older changelists are not imported. By default, the whole history of each depot is imported.",
+      "type": "integer",
+      "minimum": 1
+    }
+  }
+}
+`
@@ -111,16 +111,33 @@ func (v *AuthProviders) UnmarshalJSON(data []byte) error {
 	return fmt.Errorf("tagged union type must have a %q property whose value is one of %s", "type", []string{"builtin", "saml", "openidconnect", "http-header", "github", "gitlab"})
 }
 
+type BitbucketCloudCloneStrategy struct {
+	// Depth description: For shallow clones, the number of commits of history to fetch from the tip of each branch and tag. Defaults to 1 if since is not set.
+	Depth int `json:"depth,omitempty"`
+	// Mode description: "full" clones the whole history. "shallow" only clones the most recent history (see depth and since). "blobless" clones all commits and trees, but fetches the contents of files (blobs) only when they are needed.
+	Mode string `json:"mode"`
+	// Pattern description: Regular expression matched against the repository name, e.g. "^github\.com/myorg/monorepo$".
+	Pattern string `json:"pattern"`
+	// Since description: For shallow clones, only fetch the history after this date (YYYY-MM-DD).
+	Since string `json:"since,omitempty"`
+}
+
 // BitbucketCloudConnection description: Configuration for a connection to Bitbucket Cloud.
 type BitbucketCloudConnection struct {
 	// ApiURL description: The API URL of Bitbucket Cloud, such as https://api.bitbucket.org. Generally, admin should not modify the value of this option because Bitbucket Cloud is a public hosting platform.
 	ApiURL string `json:"apiURL,omitempty"`
 	// AppPassword description: The app password to use when authenticating to the Bitbucket Cloud. Also set the corresponding "username" field.
 	AppPassword string `json:"appPassword"`
+	// CloneStrategies description: How to clone very large repositories. The first strategy whose pattern matches the name of a repository applies to it; other repositories are fully cloned. Shallow and blobless clones save disk space and clone time, but search over commit messages and diffs, and blame, are unavailable for shallowly cloned repositories. Changing the mode of a repository reclones it.
+	CloneStrategies []*BitbucketCloudCloneStrategy `json:"cloneStrategies,omitempty"`
 	// Exclude description: A list of repositories to never mirror from Bitbucket Cloud. Takes precedence over "teams" configuration.
 	//
 	// Supports excluding by name ({"name": "myorg/myrepo"}) or by UUID ({"uuid": "{fceb73c7-cef6-4abe-956d-e471281126bd}"}).
 	Exclude []*ExcludedBitbucketCloudRepo `json:"exclude,omitempty"`
+	// GitLFS description: Whether to fetch the contents of files tracked by Git LFS from Bitbucket Cloud. When enabled, search and symbols use the file contents instead of the LFS pointer files, subject to the size limits configured on gitserver. Requires Git URLs of type "http".
+	GitLFS bool `json:"gitLFS,omitempty"`
+	// GitLFSRefs description: The refs (such as branches or tags) whose Git LFS objects are fetched when gitLFS is enabled. Search for other revisions uses the LFS pointer files.
+	GitLFSRefs []string `json:"gitLFSRefs,omitempty"`
 	// GitURLType description: The type of Git URLs to use for cloning and fetching Git repositories on this Bitbucket Cloud.
 	//
 	// If "http", Sourcegraph will access Bitbucket Cloud repositories using Git URLs of the form https://bitbucket.org/myteam/myproject.git.
@@ -143,6 +160,8 @@ type BitbucketCloudConnection struct {
 	Url string `json:"url"`
 	// Username description: The username to use when authenticating to the Bitbucket Cloud. Also set the corresponding "appPassword" field.
 	Username string `json:"username"`
+	// WebhookSecret description: A secret that authenticates Bitbucket Cloud webhooks that notify Sourcegraph of pushes, so that repositories are updated immediately instead of on the next poll. Bitbucket Cloud doesn't sign webhook payloads, so the secret must be part of the webhook URL: https://sourcegraph.example.com/.api/repo-update-webhooks/bitbucketcloud?secret=<webhookSecret>. The webhook must be triggered by "Repository push".
+	WebhookSecret string `json:"webhookSecret,omitempty"`
 }
 
 // BitbucketCloudRateLimit description: Rate limit applied when making background API requests to Bitbucket Cloud.
@@ -168,6 +187,16 @@ type BitbucketServerAuthorization struct {
 	// If set to zero, Sourcegraph will sync a user's entire accessible repository list on every request (NOT recommended).
 	Ttl string `json:"ttl,omitempty"`
 }
+type BitbucketServerCloneStrategy struct {
+	// Depth description: For shallow clones, the number of commits of history to fetch from the tip of each branch and tag. Defaults to 1 if since is not set.
+	Depth int `json:"depth,omitempty"`
+	// Mode description: "full" clones the whole history. "shallow" only clones the most recent history (see depth and since). "blobless" clones all commits and trees, but fetches the contents of files (blobs) only when they are needed.
+	Mode string `json:"mode"`
+	// Pattern description: Regular expression matched against the repository name, e.g. "^github\.com/myorg/monorepo$".
+	Pattern string `json:"pattern"`
+	// Since description: For shallow clones, only fetch the history after this date (YYYY-MM-DD).
+	Since string `json:"since,omitempty"`
+}
 
 // BitbucketServerConnection description: Configuration for a connection to Bitbucket Server.
 type BitbucketServerConnection struct {
@@ -175,12 +204,18 @@ type BitbucketServerConnection struct {
 	Authorization *BitbucketServerAuthorization `json:"authorization,omitempty"`
 	// Certificate description: TLS certificate of the Bitbucket Server instance. This is only necessary if the certificate is self-signed or signed by an internal CA. To get the certificate run `openssl s_client -connect HOST:443 -showcerts < /dev/null 2> /dev/null | openssl x509 -outform PEM`. To escape the value into a JSON string, you may want to use a tool like https://json-escape-text.now.sh.
 	Certificate string `json:"certificate,omitempty"`
+	// CloneStrategies description: How to clone very large repositories. The first strategy whose pattern matches the name of a repository applies to it; other repositories are fully cloned. Shallow and blobless clones save disk space and clone time, but search over commit messages and diffs, and blame, are unavailable for shallowly cloned repositories. Changing the mode of a repository reclones it.
+	CloneStrategies []*BitbucketServerCloneStrategy `json:"cloneStrategies,omitempty"`
 	// Exclude description: A list of repositories to never mirror from this Bitbucket Server instance. Takes precedence over "repos" and "repositoryQuery".
 	//
 	// Supports excluding by name ({"name": "projectKey/repositorySlug"}) or by ID ({"id": 42}).
 	Exclude []*ExcludedBitbucketServerRepo `json:"exclude,omitempty"`
 	// ExcludePersonalRepositories description: Whether or not personal repositories should be excluded or not. When true, Sourcegraph will ignore personal repositories it may have access to. See https://docs.sourcegraph.com/integration/bitbucket_server#excluding-personal-repositories for more information.
 	ExcludePersonalRepositories bool `json:"excludePersonalRepositories,omitempty"`
+	// GitLFS description: Whether to fetch the contents of files tracked by Git LFS from Bitbucket Server. When enabled, search and symbols use the file contents instead of the LFS pointer files, subject to the size limits configured on gitserver. Requires Git URLs of type "http".
+	GitLFS bool `json:"gitLFS,omitempty"`
+	// GitLFSRefs description: The refs (such as branches or tags) whose Git LFS objects are fetched when gitLFS is enabled. Search for other revisions uses the LFS pointer files.
+	GitLFSRefs []string `json:"gitLFSRefs,omitempty"`
 	// GitURLType description: The type of Git URLs to use for cloning and fetching Git repositories on this Bitbucket Server instance.
 	//
 	// If "http", Sourcegraph will access Bitbucket Server repositories using Git URLs of the form http(s)://bitbucket.example.com/scm/myproject/myrepo.git (using https: if the Bitbucket Server instance uses HTTPS).
@@ -305,8 +340,10 @@ type BuiltinAuthProvider struct {
 	// AllowSignup description: Allows new visitors to sign up for accounts. The sign-up page will be enabled and accessible to all visitors.
 	//
 	// SECURITY: If the site has no users (i.e., during initial setup), it will always allow the first user to sign up and become site admin **without any approval** (first user to sign up becomes the admin).
-	AllowSignup bool   `json:"allowSignup,omitempty"`
-	Type        string `json:"type"`
+	AllowSignup bool `json:"allowSignup,omitempty"`
+	// RequireTwoFactor description: Requires all users who sign in with a username and password to use two-factor authentication (a time-based one-time password from an authenticator app). Users who have not set up two-factor authentication yet are asked to do so when they next sign in.
+	RequireTwoFactor bool   `json:"requireTwoFactor,omitempty"`
+	Type             string `json:"type"`
 }
 
 // CloneURLToRepositoryName description: Describes a mapping from clone URL to repository name. The `from` field contains a regular expression with named capturing groups. The `to` field contains a template string that references capturing group names. For instance, if `from` is "^../(?P<name>\w+)$" and `to` is "github.com/user/{name}", the clone URL "../myRepository" would be mapped to the repository name "github.com/user/myRepository".
@@ -317,6 +354,42 @@ type CloneURLToRepositoryName struct {
 	To string `json:"to"`
 }
 
+// CodeIntelAutoIndexing description: Runs LSIF indexers for repositories which don't upload LSIF data from their CI. The tip of the default branch of matching repositories is indexed with the indexers of the languages detected in the repository, and the resulting LSIF data is uploaded as if it came from CI.
+type CodeIntelAutoIndexing struct {
+	// Indexers description: The indexers to run, by language.
+	Indexers []*CodeIntelIndexer `json:"indexers"`
+	// IntervalMinutes description: The interval (in minutes) at which matching repositories are checked for commits which need to be indexed.
+	IntervalMinutes int `json:"intervalMinutes,omitempty"`
+	// Repositories description: Regular expressions matched against repository names, such as "^github\.com/myorg/". Repositories whose name matches any of the patterns are indexed.
+	Repositories []string `json:"repositories"`
+}
+type CodeIntelIndexer struct {
+	// Command description: The command which runs the indexer, as a list of arguments. It is run in the root directory of the repository, and must write the LSIF data to the file dump.lsif in that directory.
+	Command []string `json:"command"`
+	// Image description: The Docker image in which the command is run, with the repository copied to /data. The container has no network access and limited CPU and memory (see the PRECISE_CODE_INTEL_INDEXER_CPUS and PRECISE_CODE_INTEL_INDEXER_MEMORY environment variables of the worker).
+	Image string `json:"image"`
+	// Language description: The language the indexer is run for, as detected by Sourcegraph (such as "Go" or "TypeScript"). The indexer is run for a repository if the language makes up part of the repository's code.
+	Language string `json:"language"`
+	// Name description: The name of the indexer, which is recorded on the uploads it produces (such as "lsif-go").
+	Name string `json:"name"`
+}
+
+// CodeIntelRetention description: Policies deciding which completed LSIF uploads are kept. An upload is kept if any of the keep policies applies to it. Other uploads are deleted once they are older than maxAgeDays. When this property is unset, uploads are only deleted to free disk space.
+type CodeIntelRetention struct {
+	// Branches description: Regular expressions matched against branch names, such as "^release/". The keepLatestPerBranch policy only applies to branches whose name matches any of the patterns. If unset, it applies to all branches.
+	Branches []string `json:"branches,omitempty"`
+	// DryRun description: Report the uploads that would be deleted in the logs of precise-code-intel-api-server instead of deleting them.
+	DryRun bool `json:"dryRun,omitempty"`
+	// KeepLatestPerBranch description: Keep the given number of most recent uploads on each branch, for each root and indexer. Zero keeps none.
+	KeepLatestPerBranch int `json:"keepLatestPerBranch,omitempty"`
+	// KeepTaggedCommits description: Keep the uploads of tagged commits.
+	KeepTaggedCommits *bool `json:"keepTaggedCommits,omitempty"`
+	// KeepVisibleFromTip description: Keep the uploads that answer queries at the tip of the default branch.
+	KeepVisibleFromTip *bool `json:"keepVisibleFromTip,omitempty"`
+	// MaxAgeDays description: The age in days after which uploads that are not kept by any policy are deleted. Zero never deletes them.
+	MaxAgeDays int `json:"maxAgeDays,omitempty"`
+}
+
 // CustomGitFetchMapping description: Mapping from Git clone URl domain/path to git fetch command. The `domainPath` field contains the Git clone URL domain/path part. The `fetch` field contains the custom git fetch command.
 type CustomGitFetchMapping struct {
 	// DomainPath description: Git clone URL domain/path
@@ -399,6 +472,8 @@ type ExperimentalFeatures struct {
 	Discussions string `json:"discussions,omitempty"`
 	// EventLogging description: Enables user event logging inside of the Sourcegraph instance. This will allow admins to have greater visibility of user activity, such as frequently viewed pages, frequent searches, and more. These event logs (and any specific user actions) are only stored locally, and never leave this Sourcegraph instance.
 	EventLogging string `json:"eventLogging,omitempty"`
+	// GitServerReplicationFactor description: The number of gitservers each repository is cloned on. Reads are served by a healthy replica and fail over to the other replicas, and updates are sent to all replicas. Must not exceed the number of gitservers.
+	GitServerReplicationFactor int `json:"gitServerReplicationFactor,omitempty"`
 	// SearchMultipleRevisionsPerRepository description: Enables searching multiple revisions of the same repository (using `repo:myrepo@branch1:branch2`).
 	SearchMultipleRevisionsPerRepository *bool `json:"searchMultipleRevisionsPerRepository,omitempty"`
 	// StructuralSearch description: Enables structural search.
@@ -455,6 +530,16 @@ type GitHubAuthorization struct {
 	// Public repositories are cached once for all users per cache TTL period.
 	Ttl string `json:"ttl,omitempty"`
 }
+type GitHubCloneStrategy struct {
+	// Depth description: For shallow clones, the number of commits of history to fetch from the tip of each branch and tag. Defaults to 1 if since is not set.
+	Depth int `json:"depth,omitempty"`
+	// Mode description: "full" clones the whole history. "shallow" only clones the most recent history (see depth and since). "blobless" clones all commits and trees, but fetches the contents of files (blobs) only when they are needed.
+	Mode string `json:"mode"`
+	// Pattern description: Regular expression matched against the repository name, e.g. "^github\.com/myorg/monorepo$".
+	Pattern string `json:"pattern"`
+	// Since description: For shallow clones, only fetch the history after this date (YYYY-MM-DD).
+	Since string `json:"since,omitempty"`
+}
 
 // GitHubConnection description: Configuration for a connection to GitHub or GitHub Enterprise.
 type GitHubConnection struct {
@@ -462,12 +547,18 @@ type GitHubConnection struct {
 	Authorization *GitHubAuthorization `json:"authorization,omitempty"`
 	// Certificate description: TLS certificate of the GitHub Enterprise instance. This is only necessary if the certificate is self-signed or signed by an internal CA. To get the certificate run `openssl s_client -connect HOST:443 -showcerts < /dev/null 2> /dev/null | openssl x509 -outform PEM`. To escape the value into a JSON string, you may want to use a tool like https://json-escape-text.now.sh.
 	Certificate string `json:"certificate,omitempty"`
+	// CloneStrategies description: How to clone very large repositories. The first strategy whose pattern matches the name of a repository applies to it; other repositories are fully cloned. Shallow and blobless clones save disk space and clone time, but search over commit messages and diffs, and blame, are unavailable for shallowly cloned repositories. Changing the mode of a repository reclones it.
+	CloneStrategies []*GitHubCloneStrategy `json:"cloneStrategies,omitempty"`
 	// Exclude description: A list of repositories to never mirror from this GitHub instance. Takes precedence over "orgs", "repos", and "repositoryQuery" configuration.
 	//
 	// Supports excluding by name ({"name": "owner/name"}) or by ID ({"id": "MDEwOlJlcG9zaXRvcnkxMTczMDM0Mg=="}).
 	//
 	// Note: ID is the GitHub GraphQL ID, not the GitHub database ID. eg: "curl https://api.github.com/repos/vuejs/vue | jq .node_id"
 	Exclude []*ExcludedGitHubRepo `json:"exclude,omitempty"`
+	// GitLFS description: Whether to fetch the contents of files tracked by Git LFS from GitHub. When enabled, search and symbols use the file contents instead of the LFS pointer files, subject to the size limits configured on gitserver. Requires Git URLs of type "http".
+	GitLFS bool `json:"gitLFS,omitempty"`
+	// GitLFSRefs description: The refs (such as branches or tags) whose Git LFS objects are fetched when gitLFS is enabled. Search for other revisions uses the LFS pointer files.
+	GitLFSRefs []string `json:"gitLFSRefs,omitempty"`
 	// GitURLType description: The type of Git URLs to use for cloning and fetching Git repositories on this GitHub instance.
 	//
 	// If "http", Sourcegraph will access GitHub repositories using Git URLs of the form http(s)://github.com/myteam/myproject.git (using https: if the GitHub instance uses HTTPS).
@@ -509,7 +600,7 @@ type GitHubConnection struct {
 	Token string `json:"token"`
 	// Url description: URL of a GitHub instance, such as https://github.com or https://github-enterprise.example.com.
 	Url string `json:"url"`
-	// Webhooks description: An array of configurations defining existing GitHub webhooks that send updates back to Sourcegraph.
+	// Webhooks description: An array of configurations defining GitHub organization webhooks that send updates back to Sourcegraph. Sourcegraph creates a webhook that notifies it of pushes on each organization, using the token, which must have the admin:org_hook scope.
 	Webhooks []*GitHubWebhook `json:"webhooks,omitempty"`
 }
 
@@ -552,6 +643,16 @@ type GitLabAuthorization struct {
 	// Public and internal repositories are cached once for all users per cache TTL period.
 	Ttl string `json:"ttl,omitempty"`
 }
+type GitLabCloneStrategy struct {
+	// Depth description: For shallow clones, the number of commits of history to fetch from the tip of each branch and tag. Defaults to 1 if since is not set.
+	Depth int `json:"depth,omitempty"`
+	// Mode description: "full" clones the whole history. "shallow" only clones the most recent history (see depth and since). "blobless" clones all commits and trees, but fetches the contents of files (blobs) only when they are needed.
+	Mode string `json:"mode"`
+	// Pattern description: Regular expression matched against the repository name, e.g. "^github\.com/myorg/monorepo$".
+	Pattern string `json:"pattern"`
+	// Since description: For shallow clones, only fetch the history after this date (YYYY-MM-DD).
+	Since string `json:"since,omitempty"`
+}
 
 // GitLabConnection description: Configuration for a connection to GitLab (GitLab.com or GitLab self-managed).
 type GitLabConnection struct {
@@ -559,8 +660,14 @@ type GitLabConnection struct {
 	Authorization *GitLabAuthorization `json:"authorization,omitempty"`
 	// Certificate description: TLS certificate of the GitLab instance. This is only necessary if the certificate is self-signed or signed by an internal CA. To get the certificate run `openssl s_client -connect HOST:443 -showcerts < /dev/null 2> /dev/null | openssl x509 -outform PEM`. To escape the value into a JSON string, you may want to use a tool like https://json-escape-text.now.sh.
 	Certificate string `json:"certificate,omitempty"`
+	// CloneStrategies description: How to clone very large repositories. The first strategy whose pattern matches the name of a repository applies to it; other repositories are fully cloned. Shallow and blobless clones save disk space and clone time, but search over commit messages and diffs, and blame, are unavailable for shallowly cloned repositories. Changing the mode of a repository reclones it.
+	CloneStrategies []*GitLabCloneStrategy `json:"cloneStrategies,omitempty"`
 	// Exclude description: A list of projects to never mirror from this GitLab instance. Takes precedence over "projects" and "projectQuery" configuration. Supports excluding by name ({"name": "group/name"}) or by ID ({"id": 42}).
 	Exclude []*ExcludedGitLabProject `json:"exclude,omitempty"`
+	// GitLFS description: Whether to fetch the contents of files tracked by Git LFS from GitLab. When enabled, search and symbols use the file contents instead of the LFS pointer files, subject to the size limits configured on gitserver. Requires Git URLs of type "http".
+	GitLFS bool `json:"gitLFS,omitempty"`
+	// GitLFSRefs description: The refs (such as branches or tags) whose Git LFS objects are fetched when gitLFS is enabled. Search for other revisions uses the LFS pointer files.
+	GitLFSRefs []string `json:"gitLFSRefs,omitempty"`
 	// GitURLType description: The type of Git URLs to use for cloning and fetching Git repositories on this GitLab instance.
 	//
 	// If "http", Sourcegraph will access GitLab repositories using Git URLs of the form http(s)://gitlab.example.com/myteam/myproject.git (using https: if the GitLab instance uses HTTPS).
@@ -589,6 +696,8 @@ type GitLabConnection struct {
 	Token string `json:"token"`
 	// Url description: URL of a GitLab instance, such as https://gitlab.example.com or (for GitLab.com) https://gitlab.com.
 	Url string `json:"url"`
+	// Webhooks description: An array of configurations defining GitLab webhooks that notify Sourcegraph of pushes, so that repositories are updated immediately instead of on the next poll. Sourcegraph adds a hook with the secret of the first entry to every mirrored project, using the token, which must belong to a maintainer of the projects. Group and system hooks can be created manually with the URL https://sourcegraph.example.com/.api/repo-update-webhooks/gitlab, "Push events" and "Tag push events".
+	Webhooks []*GitLabWebhook `json:"webhooks,omitempty"`
 }
 type GitLabNameTransformation struct {
 	// Regex description: The regex to match for the occurrences of its replacement.
@@ -610,6 +719,16 @@ type GitLabRateLimit struct {
 	// RequestsPerHour description: Requests per hour permitted. This is an average, calculated per second.
 	RequestsPerHour float64 `json:"requestsPerHour"`
 }
+type GitLabWebhook struct {
+	// Secret description: The secret token used when creating the webhook
+	Secret string `json:"secret"`
+}
+type GitRepoSizeLimit struct {
+	// MaxSizeMB description: The maximum size in MB of the repositories on disk. 0 means no limit.
+	MaxSizeMB int `json:"maxSizeMB"`
+	// Pattern description: Regular expression matched against repository names, such as "^github\.com/myorg/". Use ".*" for a limit of all repositories.
+	Pattern string `json:"pattern"`
+}
 
 // GitoliteConnection description: Configuration for a connection to Gitolite.
 type GitoliteConnection struct {
@@ -737,10 +856,26 @@ type OpenIDConnectAuthProvider struct {
 	RequireEmailDomain string `json:"requireEmailDomain,omitempty"`
 	Type               string `json:"type"`
 }
+type OtherCloneStrategy struct {
+	// Depth description: For shallow clones, the number of commits of history to fetch from the tip of each branch and tag. Defaults to 1 if since is not set.
+	Depth int `json:"depth,omitempty"`
+	// Mode description: "full" clones the whole history. "shallow" only clones the most recent history (see depth and since). "blobless" clones all commits and trees, but fetches the contents of files (blobs) only when they are needed.
+	Mode string `json:"mode"`
+	// Pattern description: Regular expression matched against the repository name, e.g. "^github\.com/myorg/monorepo$".
+	Pattern string `json:"pattern"`
+	// Since description: For shallow clones, only fetch the history after this date (YYYY-MM-DD).
+	Since string `json:"since,omitempty"`
+}
 
 // OtherExternalServiceConnection description: Configuration for a Connection to Git repositories for which an external service integration isn't yet available.
 type OtherExternalServiceConnection struct {
-	Repos []string `json:"repos"`
+	// CloneStrategies description: How to clone very large repositories. The first strategy whose pattern matches the name of a repository applies to it; other repositories are fully cloned. Shallow and blobless clones save disk space and clone time, but search over commit messages and diffs, and blame, are unavailable for shallowly cloned repositories. Changing the mode of a repository reclones it.
+	CloneStrategies []*OtherCloneStrategy `json:"cloneStrategies,omitempty"`
+	// GitLFS description: Whether to fetch the contents of files tracked by Git LFS from the Git host. When enabled, search and symbols use the file contents instead of the LFS pointer files, subject to the size limits configured on gitserver. Requires Git URLs of type "http".
+	GitLFS bool `json:"gitLFS,omitempty"`
+	// GitLFSRefs description: The refs (such as branches or tags) whose Git LFS objects are fetched when gitLFS is enabled. Search for other revisions uses the LFS pointer files.
+	GitLFSRefs []string `json:"gitLFSRefs,omitempty"`
+	Repos      []string `json:"repos"`
 	// RepositoryPathPattern description: The pattern used to generate the corresponding Sourcegraph repository name for the repositories. In the pattern, the variable "{base}" is replaced with the Git clone base URL host and path, and "{repo}" is replaced with the repository path taken from the `repos` field.
 	//
 	// For example, if your Git clone base URL is https://git.example.com/repos and `repos` contains the value "my/repo", then a repositoryPathPattern of "{base}/{repo}" would mean that a repository at https://git.example.com/repos/my/repo is available on Sourcegraph at https://sourcegraph.example.com/git.example.com/repos/my/repo.
@@ -755,6 +890,26 @@ type ParentSourcegraph struct {
 	Url string `json:"url,omitempty"`
 }
 
+// PerforceConnection description: Configuration for a connection to Perforce Server.
+type PerforceConnection struct {
+	// Depots description: Depot paths that are mirrored as repositories. Each depot path is converted to a Git repository, with one commit per changelist that touches it.
+	Depots []string `json:"depots"`
+	// MaxChanges description: The number of most recent changelists of each depot to import when it is cloned. Older changelists are not imported. By default, the whole history of each depot is imported.
+	MaxChanges int `json:"maxChanges,omitempty"`
+	// P4Passwd description: The ticket or password of the user to be authenticated for p4 CLI (P4PASSWD).
+	P4Passwd string `json:"p4.passwd"`
+	// P4Port description: The Perforce Server address to be used for p4 CLI (P4PORT), including its transport prefix if it has one (e.g., "ssl:perforce.example.com:1666").
+	P4Port string `json:"p4.port"`
+	// P4User description: The user to be authenticated for p4 CLI (P4USER).
+	P4User string `json:"p4.user"`
+	// RepositoryPathPattern description: The pattern used to generate the corresponding Sourcegraph repository name for a Perforce depot. In the pattern, the variable "{host}" is replaced with the host of the Perforce Server, and "{depot}" is replaced with the depot path without its leading and trailing slashes.
+	//
+	// For example, if your Perforce Server is at perforce.example.com:1666 and your depot path is //Sourcegraph/, then a repositoryPathPattern of "{host}/{depot}" would mean that the depot is available on Sourcegraph at https://sourcegraph.example.com/perforce.example.com/Sourcegraph.
+	//
+	// It is important that the Sourcegraph repository name generated with this pattern be unique to this Perforce Server. If different code hosts generate repository names that collide, Sourcegraph's behavior is undefined.
+	RepositoryPathPattern string `json:"repositoryPathPattern,omitempty"`
+}
+
 // PermissionsBackgroundSync description: Sync code host repository and user permissions in the background.
 type PermissionsBackgroundSync struct {
 	// Enabled description: Whether syncing permissions in the background is enabled.
@@ -794,6 +949,14 @@ type QuickLink struct {
 	// Url description: The URL of this quick link (absolute or relative)
 	Url string `json:"url"`
 }
+
+// RepoAccessLog description: Records who accessed the code of sensitive repositories (file views, raw file and archive downloads, search results, and git clones) in an access log that site admins can query. Entries are stored separately from other event logs.
+type RepoAccessLog struct {
+	// Repositories description: Regular expressions matching the names of the repositories whose accesses are logged (e.g. "^github\.com/myorg/secret-").
+	Repositories []string `json:"repositories,omitempty"`
+	// RetentionDays description: The number of days to keep access log entries. Older entries are deleted.
+	RetentionDays int `json:"retentionDays,omitempty"`
+}
 type Repos struct {
 	// Callsign description: The unique Phabricator identifier for the repository, like 'MUX'.
 	Callsign string `json:"callsign"`
@@ -969,6 +1132,10 @@ type SiteConfiguration struct {
 	Branding *Branding `json:"branding,omitempty"`
 	// CampaignsReadAccessEnabled description: Enables read-only access to campaigns for non-site-admin users. This is a setting for the experimental campaigns feature. These will only have an effect when campaigns is enabled with `{"experimentalFeatures": {"automation": "enabled"}}`.
 	CampaignsReadAccessEnabled *bool `json:"campaigns.readAccess.enabled,omitempty"`
+	// CodeIntelAutoIndexing description: Runs LSIF indexers for repositories which don't upload LSIF data from their CI. The tip of the default branch of matching repositories is indexed with the indexers of the languages detected in the repository, and the resulting LSIF data is uploaded as if it came from CI.
+	CodeIntelAutoIndexing *CodeIntelAutoIndexing `json:"codeIntelAutoIndexing,omitempty"`
+	// CodeIntelRetention description: Policies deciding which completed LSIF uploads are kept. An upload is kept if any of the keep policies applies to it. Other uploads are deleted once they are older than maxAgeDays. When this property is unset, uploads are only deleted to free disk space.
+	CodeIntelRetention *CodeIntelRetention `json:"codeIntelRetention,omitempty"`
 	// CorsOrigin description: Required when using any of the native code host integrations for Phabricator, GitLab, or Bitbucket Server. It is a space-separated list of allowed origins for cross-origin HTTP requests which should be the base URL for your Phabricator, GitLab, or Bitbucket Server instance.
 	CorsOrigin string `json:"corsOrigin,omitempty"`
 	// DebugSearchSymbolsParallelism description: (debug) controls the amount of symbol search parallelism. Defaults to 20. It is not recommended to change this outside of debugging scenarios. This option will be removed in a future version.
@@ -1001,6 +1168,8 @@ type SiteConfiguration struct {
 	GitCloneURLToRepositoryName []*CloneURLToRepositoryName `json:"git.cloneURLToRepositoryName,omitempty"`
 	// GitMaxConcurrentClones description: Maximum number of git clone processes that will be run concurrently to update repositories.
 	GitMaxConcurrentClones int `json:"gitMaxConcurrentClones,omitempty"`
+	// GitRepoSizeLimits description: Maximum sizes of repositories on gitserver, by repository name pattern. The first limit whose pattern matches the name of a repository applies. Clones of repositories which exceed their limit are aborted, and updates which make a repository exceed its limit are reported as failed (the repository stays available).
+	GitRepoSizeLimits []*GitRepoSizeLimit `json:"gitRepoSizeLimits,omitempty"`
 	// GithubClientID description: Client ID for GitHub. (DEPRECATED)
 	GithubClientID string `json:"githubClientID,omitempty"`
 	// GithubClientSecret description: Client secret for GitHub. (DEPRECATED)
@@ -1037,6 +1206,8 @@ type SiteConfiguration struct {
 	PermissionsBackgroundSync *PermissionsBackgroundSync `json:"permissions.backgroundSync,omitempty"`
 	// PermissionsUserMapping description: Settings for Sourcegraph permissions, which allow the site admin to explicitly manage repository permissions via the GraphQL API. This setting cannot be enabled if repository permissions for any specific external service are enabled (i.e., when the external service's `authorization` field is set).
 	PermissionsUserMapping *PermissionsUserMapping `json:"permissions.userMapping,omitempty"`
+	// RepoAccessLog description: Records who accessed the code of sensitive repositories (file views, raw file and archive downloads, search results, and git clones) in an access log that site admins can query. Entries are stored separately from other event logs.
+	RepoAccessLog *RepoAccessLog `json:"repoAccessLog,omitempty"`
 	// RepoListUpdateInterval description: Interval (in minutes) for checking code hosts (such as GitHub, Gitolite, etc.) for new repositories.
 	RepoListUpdateInterval int `json:"repoListUpdateInterval,omitempty"`
 	// SearchIndexEnabled description: Whether indexed search is enabled. If unset Sourcegraph detects the environment to decide if indexed search is enabled. Indexed search is RAM heavy, and is disabled by default in the single docker image. All other environments will have it enabled by default. The size of all your repository working copies is the amount of additional RAM required.
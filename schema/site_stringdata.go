@@ -162,6 +162,12 @@ const SiteSchemaJSON = `{
               }
             ]
           ]
+        },
+        "gitServerReplicationFactor": {
+          "description": "The number of gitservers each repository is cloned on. Reads are served by a healthy replica and fail over to the other replicas, and updates are sent to all replicas. Must not exceed the number of gitservers.",
+          "type": "integer",
+          "minimum": 1,
+          "default": 1
         }
       },
       "examples": [
@@ -205,6 +211,125 @@ const SiteSchemaJSON = `{
       "default": false,
       "group": "Security"
     },
+    "codeIntelAutoIndexing": {
+      "description": "Runs LSIF indexers for repositories which don't upload LSIF data from their CI. The tip of the default branch of matching repositories is indexed with the indexers of the languages detected in the repository, and the resulting LSIF data is uploaded as if it came from CI.",
+      "type": "object",
+      "additionalProperties": false,
+      "required": ["repositories", "indexers"],
+      "properties": {
+        "repositories": {
+          "description": "Regular expressions matched against repository names, such as \"^github\\.com/myorg/\". Repositories whose name matches any of the patterns are indexed.",
+          "type": "array",
+          "items": {
+            "type": "string",
+            "format": "regex"
+          }
+        },
+        "indexers": {
+          "description": "The indexers to run, by language.",
+          "type": "array",
+          "items": {
+            "title": "CodeIntelIndexer",
+            "type": "object",
+            "additionalProperties": false,
+            "required": ["language", "name", "command", "image"],
+            "properties": {
+              "language": {
+                "description": "The language the indexer is run for, as detected by Sourcegraph (such as \"Go\" or \"TypeScript\"). The indexer is run for a repository if the language makes up part of the repository's code.",
+                "type": "string",
+                "minLength": 1
+              },
+              "name": {
+                "description": "The name of the indexer, which is recorded on the uploads it produces (such as \"lsif-go\").",
+                "type": "string",
+                "minLength": 1
+              },
+              "command": {
+                "description": "The command which runs the indexer, as a list of arguments. It is run in the root directory of the repository, and must write the LSIF data to the file dump.lsif in that directory.",
+                "type": "array",
+                "items": {
+                  "type": "string"
+                },
+                "minItems": 1
+              },
+              "image": {
+                "description": "The Docker image in which the command is run, with the repository copied to /data. The container has no network access and limited CPU and memory (see the PRECISE_CODE_INTEL_INDEXER_CPUS and PRECISE_CODE_INTEL_INDEXER_MEMORY environment variables of the worker).",
+                "type": "string",
+                "minLength": 1
+              }
+            }
+          }
+        },
+        "intervalMinutes": {
+          "description": "The interval (in minutes) at which matching repositories are checked for commits which need to be indexed.",
+          "type": "integer",
+          "minimum": 1,
+          "default": 60
+        }
+      },
+      "examples": [
+        {
+          "repositories": ["^github\\.com/myorg/"],
+          "indexers": [
+            { "language": "Go", "name": "lsif-go", "command": ["lsif-go", "--noProgress"], "image": "sourcegraph/lsif-go" },
+            { "language": "TypeScript", "name": "lsif-tsc", "command": ["lsif-tsc", "-p", "."], "image": "sourcegraph/lsif-node" }
+          ]
+        }
+      ],
+      "group": "Experimental"
+    },
+    "codeIntelRetention": {
+      "description": "Policies deciding which completed LSIF uploads are kept. An upload is kept if any of the keep policies applies to it. Other uploads are deleted once they are older than maxAgeDays. When this property is unset, uploads are only deleted to free disk space.",
+      "type": "object",
+      "additionalProperties": false,
+      "properties": {
+        "keepVisibleFromTip": {
+          "description": "Keep the uploads that answer queries at the tip of the default branch.",
+          "type": "boolean",
+          "default": true,
+          "!go": { "pointer": true }
+        },
+        "keepTaggedCommits": {
+          "description": "Keep the uploads of tagged commits.",
+          "type": "boolean",
+          "default": true,
+          "!go": { "pointer": true }
+        },
+        "keepLatestPerBranch": {
+          "description": "Keep the given number of most recent uploads on each branch, for each root and indexer. Zero keeps none.",
+          "type": "integer",
+          "minimum": 0,
+          "default": 0
+        },
+        "branches": {
+          "description": "Regular expressions matched against branch names, such as \"^release/\". The keepLatestPerBranch policy only applies to branches whose name matches any of the patterns. If unset, it applies to all branches.",
+          "type": "array",
+          "items": {
+            "type": "string",
+            "format": "regex"
+          }
+        },
+        "maxAgeDays": {
+          "description": "The age in days after which uploads that are not kept by any policy are deleted. Zero never deletes them.",
+          "type": "integer",
+          "minimum": 0,
+          "default": 0
+        },
+        "dryRun": {
+          "description": "Report the uploads that would be deleted in the logs of precise-code-intel-api-server instead of deleting them.",
+          "type": "boolean",
+          "default": false
+        }
+      },
+      "examples": [
+        {
+          "keepLatestPerBranch": 1,
+          "branches": ["^main$", "^release/"],
+          "maxAgeDays": 30
+        }
+      ],
+      "group": "Experimental"
+    },
     "disableNonCriticalTelemetry": {
       "description": "Disable aggregated event counts from being sent to Sourcegraph.com via pings.",
       "type": "boolean",
@@ -262,6 +387,35 @@ const SiteSchemaJSON = `{
       "default": 5,
       "group": "External services"
     },
+    "gitRepoSizeLimits": {
+      "description": "Maximum sizes of repositories on gitserver, by repository name pattern. The first limit whose pattern matches the name of a repository applies. Clones of repositories which exceed their limit are aborted, and updates which make a repository exceed its limit are reported as failed (the repository stays available).",
+      "type": "array",
+      "items": {
+        "title": "GitRepoSizeLimit",
+        "type": "object",
+        "additionalProperties": false,
+        "required": ["pattern", "maxSizeMB"],
+        "properties": {
+          "pattern": {
+            "description": "Regular expression matched against repository names, such as \"^github\\.com/myorg/\". Use \".*\" for a limit of all repositories.",
+            "type": "string",
+            "format": "regex"
+          },
+          "maxSizeMB": {
+            "description": "The maximum size in MB of the repositories on disk. 0 means no limit.",
+            "type": "integer",
+            "minimum": 0
+          }
+        }
+      },
+      "examples": [
+        [
+          { "pattern": "^github\\.com/myorg/monorepo$", "maxSizeMB": 20480 },
+          { "pattern": ".*", "maxSizeMB": 5120 }
+        ]
+      ],
+      "group": "External services"
+    },
     "repoListUpdateInterval": {
       "description": "Interval (in minutes) for checking code hosts (such as GitHub, Gitolite, etc.) for new repositories.",
       "type": "integer",
@@ -643,6 +797,34 @@ const SiteSchemaJSON = `{
       "type": "string",
       "group": "Sourcegraph Enterprise license"
     },
+    "repoAccessLog": {
+      "description": "Records who accessed the code of sensitive repositories (file views, raw file and archive downloads, search results, and git clones) in an access log that site admins can query. Entries are stored separately from other event logs.",
+      "type": "object",
+      "additionalProperties": false,
+      "properties": {
+        "repositories": {
+          "description": "Regular expressions matching the names of the repositories whose accesses are logged (e.g. \"^github\\.com/myorg/secret-\").",
+          "type": "array",
+          "items": {
+            "type": "string",
+            "format": "regex"
+          }
+        },
+        "retentionDays": {
+          "description": "The number of days to keep access log entries. Older entries are deleted.",
+          "type": "integer",
+          "minimum": 1,
+          "default": 365
+        }
+      },
+      "examples": [
+        {
+          "repositories": ["^github\\.com/myorg/secret-", "^gitlab\\.example\\.com/finance/"],
+          "retentionDays": 730
+        }
+      ],
+      "group": "Security"
+    },
     "auth.providers": {
       "description": "The authentication providers to use for identifying and signing in users. See instructions below for configuring SAML, OpenID Connect (including G Suite), and HTTP authentication proxies. Multiple authentication providers are supported (by specifying multiple elements in this array).",
       "type": "array",
@@ -733,6 +915,11 @@ const SiteSchemaJSON = `{
           "description": "Allows new visitors to sign up for accounts. The sign-up page will be enabled and accessible to all visitors.\n\nSECURITY: If the site has no users (i.e., during initial setup), it will always allow the first user to sign up and become site admin **without any approval** (first user to sign up becomes the admin).",
           "type": "boolean",
           "default": false
+        },
+        "requireTwoFactor": {
+          "description": "Requires all users who sign in with a username and password to use two-factor authentication (a time-based one-time password from an authenticator app). Users who have not set up two-factor authentication yet are asked to do so when they next sign in.",
+          "type": "boolean",
+          "default": false
         }
       }
     },
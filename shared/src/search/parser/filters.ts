@@ -107,7 +107,7 @@ export const FILTERS: Record<NegatableFilter, NegatableFilterDefinition> &
         description: 'Commits with messages matching a certain string',
     },
     [FilterType.patterntype]: {
-        discreteValues: ['regexp', 'literal', 'structural'],
+        discreteValues: ['regexp', 'literal', 'structural', 'secrets'],
         description: 'The pattern type (regexp, literal, structural) in use',
         singular: true,
     },
@@ -18,6 +18,28 @@ interface State {
     password: string
     error?: Error
     loading: boolean
+
+    /** Whether the password was correct and a two-factor authentication code is required. */
+    twoFactorRequired: boolean
+    twoFactorCode: string
+
+    /** Set if the user must set up two-factor authentication before signing in. */
+    twoFactorEnrollment?: TwoFactorEnrollment
+
+    /** The user's new recovery codes, if the user set up two-factor authentication while signing in. */
+    recoveryCodes?: string[]
+}
+
+interface TwoFactorEnrollment {
+    secret: string
+    keyURI: string
+}
+
+/** The JSON body of a sign-in response that asks for a two-factor authentication code. */
+interface TwoFactorChallenge {
+    twoFactorRequired: boolean
+    error?: string
+    enrollment?: TwoFactorEnrollment
 }
 
 /**
@@ -30,10 +52,26 @@ export class UsernamePasswordSignInForm extends React.Component<Props, State> {
             email: '',
             password: '',
             loading: false,
+            twoFactorRequired: false,
+            twoFactorCode: '',
         }
     }
 
     public render(): JSX.Element | null {
+        if (this.state.recoveryCodes) {
+            return (
+                <div className="signin-signup-form signin-form">
+                    <p>
+                        Two-factor authentication is now set up. Save these recovery codes in a safe place. Each code
+                        can be used once to sign in if you lose access to your authenticator app.
+                    </p>
+                    <pre className="e2e-recovery-codes">{this.state.recoveryCodes.join('\n')}</pre>
+                    <button className="btn btn-primary btn-block" type="button" onClick={this.onSignedIn}>
+                        Continue
+                    </button>
+                </div>
+            )
+        }
         return (
             <Form className="signin-signup-form signin-form e2e-signin-form" onSubmit={this.handleSubmit}>
                 {window.context.allowSignup ? (
@@ -70,6 +108,34 @@ export class UsernamePasswordSignInForm extends React.Component<Props, State> {
                         autoComplete="current-password"
                     />
                 </div>
+                {this.state.twoFactorEnrollment && (
+                    <div className="form-group">
+                        <p>
+                            This site requires two-factor authentication. Add this key to your authenticator app, then
+                            enter the code it shows:
+                        </p>
+                        <code className="d-block mb-2">{this.state.twoFactorEnrollment.secret}</code>
+                        <small className="form-text text-muted">
+                            <a href={this.state.twoFactorEnrollment.keyURI}>Open in authenticator app</a>
+                        </small>
+                    </div>
+                )}
+                {this.state.twoFactorRequired && (
+                    <div className="form-group">
+                        <input
+                            className="form-control signin-signup-form__input"
+                            type="text"
+                            placeholder="Two-factor authentication code or recovery code"
+                            onChange={this.onTwoFactorCodeFieldChange}
+                            required={true}
+                            value={this.state.twoFactorCode}
+                            disabled={this.state.loading}
+                            autoCapitalize="off"
+                            autoFocus={true}
+                            autoComplete="one-time-code"
+                        />
+                    </div>
+                )}
                 <div className="form-group">
                     <button className="btn btn-primary btn-block" type="submit" disabled={this.state.loading}>
                         Sign in
@@ -97,6 +163,19 @@ export class UsernamePasswordSignInForm extends React.Component<Props, State> {
         this.setState({ password: e.target.value })
     }
 
+    private onTwoFactorCodeFieldChange = (e: React.ChangeEvent<HTMLInputElement>): void => {
+        this.setState({ twoFactorCode: e.target.value })
+    }
+
+    private onSignedIn = (): void => {
+        if (new URLSearchParams(this.props.location.search).get('close') === 'true') {
+            window.close()
+        } else {
+            const returnTo = getReturnTo(this.props.location)
+            window.location.replace(returnTo)
+        }
+    }
+
     private handleSubmit = (event: React.FormEvent<HTMLFormElement>): void => {
         event.preventDefault()
         if (this.state.loading) {
@@ -116,18 +195,31 @@ export class UsernamePasswordSignInForm extends React.Component<Props, State> {
             body: JSON.stringify({
                 email: this.state.email,
                 password: this.state.password,
+                twoFactorCode: this.state.twoFactorRequired ? this.state.twoFactorCode : undefined,
             }),
         })
-            .then(resp => {
+            .then(async resp => {
+                const isJSON = (resp.headers.get('Content-Type') || '').startsWith('application/json')
                 if (resp.status === 200) {
-                    if (new URLSearchParams(this.props.location.search).get('close') === 'true') {
-                        window.close()
-                    } else {
-                        const returnTo = getReturnTo(this.props.location)
-                        window.location.replace(returnTo)
+                    const result: { recoveryCodes?: string[] } = isJSON ? await resp.json() : {}
+                    if (result.recoveryCodes && result.recoveryCodes.length > 0) {
+                        this.setState({ loading: false, recoveryCodes: result.recoveryCodes })
+                        return
                     }
+                    this.onSignedIn()
+                } else if (resp.status === 401 && isJSON) {
+                    const challenge: TwoFactorChallenge = await resp.json()
+                    this.setState({
+                        loading: false,
+                        error: challenge.error ? new Error(challenge.error) : undefined,
+                        twoFactorRequired: challenge.twoFactorRequired,
+                        twoFactorCode: '',
+                        twoFactorEnrollment: challenge.enrollment,
+                    })
                 } else if (resp.status === 401) {
                     throw new Error('User or password was incorrect')
+                } else if (resp.status === 429) {
+                    throw new Error(await resp.text())
                 } else {
                     throw new Error('Unknown Error')
                 }
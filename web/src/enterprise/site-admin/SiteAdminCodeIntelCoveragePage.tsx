@@ -0,0 +1,210 @@
+import React, { FunctionComponent, useEffect, useMemo, useState } from 'react'
+import * as H from 'history'
+import { RouteComponentProps } from 'react-router'
+import { Link } from 'react-router-dom'
+import { of } from 'rxjs'
+import { catchError } from 'rxjs/operators'
+import { LoadingSpinner } from '@sourcegraph/react-loading-spinner'
+import * as GQL from '../../../../shared/src/graphql/schema'
+import { asError, ErrorLike, isErrorLike } from '../../../../shared/src/util/errors'
+import { useObservable } from '../../../../shared/src/util/useObservable'
+import { pluralize } from '../../../../shared/src/util/strings'
+import { ErrorAlert } from '../../components/alerts'
+import { FilteredConnection } from '../../components/FilteredConnection'
+import { PageTitle } from '../../components/PageTitle'
+import { eventLogger } from '../../tracking/eventLogger'
+import { fetchLsifCoverage, fetchLsifRepositoryCoverage } from './backend'
+
+interface Props extends RouteComponentProps<{}> {}
+
+/**
+ * A page displaying where precise code intelligence is available at the tip of the
+ * default branch of each repository.
+ */
+export const SiteAdminCodeIntelCoveragePage: FunctionComponent<Props> = ({ history, location }) => {
+    useEffect(() => eventLogger.logViewEvent('SiteAdminCodeIntelCoverage'), [])
+
+    return (
+        <div className="site-admin-code-intel-coverage-page">
+            <PageTitle title="Code intelligence coverage - Admin" />
+            <h2>Code intelligence coverage</h2>
+            <p>
+                The languages of each repository directory at the tip of the default branch, and the LSIF uploads
+                that provide precise code intelligence for them. Select a directory to see its subdirectories.
+            </p>
+            <FilteredCoverageConnection
+                className="list-group list-group-flush mt-3"
+                noun="repository"
+                pluralNoun="repositories"
+                queryConnection={fetchLsifRepositoryCoverage}
+                nodeComponent={RepositoryCoverageNode}
+                nodeComponentProps={{ history }}
+                history={history}
+                location={location}
+            />
+        </div>
+    )
+}
+
+interface RepositoryCoverageNodeProps {
+    node: GQL.ILSIFRepositoryCoverage
+    history: H.History
+}
+
+class FilteredCoverageConnection extends FilteredConnection<
+    GQL.ILSIFRepositoryCoverage,
+    Pick<RepositoryCoverageNodeProps, 'history'>
+> {}
+
+/**
+ * Displays the coverage of a repository, starting at its root. Selecting a subdirectory
+ * fetches the coverage of that directory.
+ */
+const RepositoryCoverageNode: FunctionComponent<RepositoryCoverageNodeProps> = ({ node, history }) => {
+    const [path, setPath] = useState('')
+
+    const coverageOrError = useObservable(
+        useMemo(
+            () =>
+                path === ''
+                    ? of<GQL.ILSIFCoverage | null | ErrorLike>(node.coverage)
+                    : fetchLsifCoverage({ repository: node.repository.id, path }).pipe(
+                          catchError((error): [ErrorLike] => [asError(error)])
+                      ),
+            [node, path]
+        )
+    )
+
+    return (
+        <li className="list-group-item py-2">
+            <div className="d-flex align-items-center justify-content-between">
+                <h3 className="mb-0">
+                    <Link to={node.repository.url}>{node.repository.name}</Link>
+                    {path !== '' && <span className="text-muted">/{path}</span>}
+                </h3>
+                {path !== '' && (
+                    <button type="button" className="btn btn-link btn-sm" onClick={() => setPath(parentPath(path))}>
+                        Up
+                    </button>
+                )}
+            </div>
+            {coverageOrError === undefined ? (
+                <LoadingSpinner className="icon-inline" />
+            ) : isErrorLike(coverageOrError) ? (
+                <ErrorAlert prefix="Error loading code intelligence coverage" error={coverageOrError} history={history} />
+            ) : coverageOrError === null ? (
+                <p className="text-muted mb-0">The repository is empty.</p>
+            ) : (
+                <CoverageTable coverage={coverageOrError} onSelectDirectory={setPath} />
+            )}
+        </li>
+    )
+}
+
+const CoverageTable: FunctionComponent<{
+    coverage: GQL.ILSIFCoverage
+    onSelectDirectory: (path: string) => void
+}> = ({ coverage, onSelectDirectory }) => (
+    <table className="table table-sm mt-2 mb-0">
+        <thead>
+            <tr>
+                <th>Directory</th>
+                <th>Language</th>
+                <th>Lines</th>
+                <th>
+                    Covered at <code>{coverage.commit.abbreviatedOID}</code>
+                </th>
+            </tr>
+        </thead>
+        <tbody>
+            {coverage.directories.map((directory, index) =>
+                directory.languages.length === 0 ? (
+                    <tr key={directory.path}>
+                        <td>
+                            <DirectoryName path={directory.path} isRoot={index === 0} onSelect={onSelectDirectory} />
+                        </td>
+                        <td colSpan={3} className="text-muted">
+                            No languages
+                        </td>
+                    </tr>
+                ) : (
+                    directory.languages.map((language, languageIndex) => (
+                        <tr key={`${directory.path}:${language.name}`}>
+                            <td>
+                                {languageIndex === 0 && (
+                                    <DirectoryName
+                                        path={directory.path}
+                                        isRoot={index === 0}
+                                        onSelect={onSelectDirectory}
+                                    />
+                                )}
+                            </td>
+                            <td>{language.name}</td>
+                            <td>{language.totalLines}</td>
+                            <td>
+                                <UploadsCoverage uploads={language.uploads} partialUploads={language.partialUploads} />
+                            </td>
+                        </tr>
+                    ))
+                )
+            )}
+        </tbody>
+    </table>
+)
+
+const DirectoryName: FunctionComponent<{ path: string; isRoot: boolean; onSelect: (path: string) => void }> = ({
+    path,
+    isRoot,
+    onSelect,
+}) =>
+    isRoot ? (
+        <span>{path === '' ? '/' : path}</span>
+    ) : (
+        <button type="button" className="btn btn-link btn-sm p-0" onClick={() => onSelect(path)}>
+            {path}
+        </button>
+    )
+
+const UploadsCoverage: FunctionComponent<{
+    uploads: GQL.ILSIFUploadCoverage[]
+    partialUploads: GQL.ILSIFUploadCoverage[]
+}> = ({ uploads, partialUploads }) =>
+    uploads.length === 0 && partialUploads.length === 0 ? (
+        <span className="text-muted">Not covered</span>
+    ) : (
+        <>
+            {uploads.map(coverage => (
+                <UploadCoverage key={coverage.upload.id} coverage={coverage} />
+            ))}
+            {partialUploads.length > 0 && (
+                <div className="text-muted">
+                    Partially covered by {partialUploads.length} {pluralize('upload', partialUploads.length)} in
+                    subdirectories
+                </div>
+            )}
+        </>
+    )
+
+const UploadCoverage: FunctionComponent<{ coverage: GQL.ILSIFUploadCoverage }> = ({
+    coverage: { upload, commitsBehindTip },
+}) => (
+    <div>
+        <Link to={`/site-admin/lsif-uploads/${upload.id}`}>
+            {upload.inputIndexer} at {upload.inputRoot || '/'}
+        </Link>{' '}
+        <span className={commitsBehindTip > 0 ? 'text-warning' : 'text-muted'}>
+            {commitsBehindTip === 0
+                ? '(at tip)'
+                : `(${commitsBehindTip} ${pluralize('commit', commitsBehindTip)} behind tip)`}
+        </span>
+    </div>
+)
+
+/**
+ * Returns the parent of the given directory path, or the empty string for top-level
+ * directories.
+ */
+function parentPath(path: string): string {
+    const index = path.lastIndexOf('/')
+    return index === -1 ? '' : path.slice(0, index)
+}
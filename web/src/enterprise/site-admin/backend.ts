@@ -41,3 +41,110 @@ export function fetchLsifUpload({ id }: { id: string }): Observable<GQL.ILSIFUpl
         })
     )
 }
+
+const lsifCoverageFragment = gql`
+    fragment LsifCoverageFields on LSIFCoverage {
+        commit {
+            abbreviatedOID
+        }
+        directories {
+            path
+            languages {
+                name
+                totalLines
+                uploads {
+                    ...LsifUploadCoverageFields
+                }
+                partialUploads {
+                    ...LsifUploadCoverageFields
+                }
+            }
+        }
+    }
+
+    fragment LsifUploadCoverageFields on LSIFUploadCoverage {
+        upload {
+            id
+            inputRoot
+            inputIndexer
+        }
+        commitsBehindTip
+    }
+`
+
+/**
+ * Fetch the precise code intelligence coverage of repositories. Only site admins may
+ * perform this query.
+ */
+export function fetchLsifRepositoryCoverage({
+    first,
+    query,
+}: GQL.ILsifRepositoryCoverageOnQueryArguments): Observable<GQL.ILSIFRepositoryCoverageConnection> {
+    return queryGraphQL(
+        gql`
+            query LsifRepositoryCoverage($first: Int, $query: String) {
+                lsifRepositoryCoverage(first: $first, query: $query) {
+                    nodes {
+                        repository {
+                            id
+                            name
+                            url
+                        }
+                        coverage {
+                            ...LsifCoverageFields
+                        }
+                    }
+                    totalCount
+                    pageInfo {
+                        hasNextPage
+                    }
+                }
+            }
+            ${lsifCoverageFragment}
+        `,
+        { first, query }
+    ).pipe(
+        map(dataOrThrowErrors),
+        map(({ lsifRepositoryCoverage }) => lsifRepositoryCoverage)
+    )
+}
+
+/**
+ * Fetch the precise code intelligence coverage of a directory of a repository.
+ */
+export function fetchLsifCoverage({
+    repository,
+    path,
+}: {
+    repository: string
+    path: string
+}): Observable<GQL.ILSIFCoverage | null> {
+    return queryGraphQL(
+        gql`
+            query LsifCoverage($repository: ID!, $path: String!) {
+                node(id: $repository) {
+                    __typename
+                    ... on Repository {
+                        lsifCoverage(path: $path) {
+                            ...LsifCoverageFields
+                        }
+                    }
+                }
+            }
+            ${lsifCoverageFragment}
+        `,
+        { repository, path }
+    ).pipe(
+        map(dataOrThrowErrors),
+        map(({ node }) => {
+            if (!node) {
+                throw new Error(`Repository ${repository} not found`)
+            }
+            if (node.__typename !== 'Repository') {
+                throw new Error(`The given ID is a ${node.__typename}, not a Repository`)
+            }
+
+            return node.lsifCoverage
+        })
+    )
+}
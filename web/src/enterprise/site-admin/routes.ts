@@ -75,4 +75,9 @@ export const enterpriseSiteAdminAreaRoutes: readonly SiteAdminAreaRoute[] = [
         exact: true,
         render: lazyComponent(() => import('./SiteAdminLsifUploadPage'), 'SiteAdminLsifUploadPage'),
     },
+    {
+        path: '/code-intelligence/coverage',
+        exact: true,
+        render: lazyComponent(() => import('./SiteAdminCodeIntelCoveragePage'), 'SiteAdminCodeIntelCoveragePage'),
+    },
 ]
@@ -79,6 +79,10 @@ export const enterpriseSiteAdminSidebarGroups: SiteAdminSideBarGroups = siteAdmi
                         label: 'Extensions',
                         to: '/site-admin/registry/extensions',
                     },
+                    {
+                        label: 'Code intelligence coverage',
+                        to: '/site-admin/code-intelligence/coverage',
+                    },
                 ],
             },
             // Insert dotcom group after other group (on Sourcegraph.com)
@@ -83,6 +83,12 @@ class UpdateMirrorRepositoryActionContainer extends React.PureComponent<UpdateMi
                             {updateSchedule.index + 1} out of {updateSchedule.total} in the schedule)
                         </div>
                     )}
+                    {updateSchedule?.lastError && (
+                        <div className="text-danger">
+                            Last update failed ({updateSchedule.consecutiveFailures} in a row):{' '}
+                            {updateSchedule.lastError}
+                        </div>
+                    )}
                     {this.props.repo.mirrorInfo.updateQueue && !this.props.repo.mirrorInfo.updateQueue.updating && (
                         <div>
                             Queued for update (position {this.props.repo.mirrorInfo.updateQueue.index + 1} out of{' '}
@@ -27,6 +27,8 @@ export function fetchRepository(name: string): Observable<GQL.IRepository> {
                             due
                             index
                             total
+                            lastError
+                            consecutiveFailures
                         }
                         updateQueue {
                             updating
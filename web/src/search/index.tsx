@@ -31,7 +31,8 @@ export function parseSearchURLPatternType(query: string): SearchPatternType | un
     if (
         patternType !== SearchPatternType.literal &&
         patternType !== SearchPatternType.regexp &&
-        patternType !== SearchPatternType.structural
+        patternType !== SearchPatternType.structural &&
+        patternType !== SearchPatternType.secrets
     ) {
         return undefined
     }
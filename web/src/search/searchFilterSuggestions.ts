@@ -207,7 +207,7 @@ export const searchFilterSuggestions: SearchFilterSuggestions = {
         values: [],
     },
     patterntype: {
-        values: [{ value: 'literal' }, { value: 'structural' }, { value: 'regexp' }].map(
+        values: [{ value: 'literal' }, { value: 'structural' }, { value: 'regexp' }, { value: 'secrets' }].map(
             assign({ type: FilterType.patterntype })
         ),
     },
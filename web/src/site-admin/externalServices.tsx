@@ -5,6 +5,7 @@ import BitbucketIcon from 'mdi-react/BitbucketIcon'
 import GithubCircleIcon from 'mdi-react/GithubCircleIcon'
 import GitIcon from 'mdi-react/GitIcon'
 import GitLabIcon from 'mdi-react/GitlabIcon'
+import SourceRepositoryIcon from 'mdi-react/SourceRepositoryIcon'
 import React from 'react'
 import awsCodeCommitSchemaJSON from '../../../schema/aws_codecommit.schema.json'
 import bitbucketCloudSchemaJSON from '../../../schema/bitbucket_cloud.schema.json'
@@ -13,6 +14,7 @@ import githubSchemaJSON from '../../../schema/github.schema.json'
 import gitlabSchemaJSON from '../../../schema/gitlab.schema.json'
 import gitoliteSchemaJSON from '../../../schema/gitolite.schema.json'
 import otherExternalServiceSchemaJSON from '../../../schema/other_external_service.schema.json'
+import perforceSchemaJSON from '../../../schema/perforce.schema.json'
 import phabricatorSchemaJSON from '../../../schema/phabricator.schema.json'
 import { PhabricatorIcon } from '../../../shared/src/components/icons'
 import * as GQL from '../../../shared/src/graphql/schema'
@@ -941,6 +943,56 @@ const GITOLITE: AddExternalServiceOptions = {
         },
     ],
 }
+const PERFORCE: AddExternalServiceOptions = {
+    kind: GQL.ExternalServiceKind.PERFORCE,
+    title: 'Perforce',
+    icon: SourceRepositoryIcon,
+    jsonSchema: perforceSchemaJSON,
+    defaultDisplayName: 'Perforce',
+    defaultConfig: `{
+  "p4.port": "ssl:perforce.example.com:1666",
+  "p4.user": "<user>",
+  "p4.passwd": "<ticket or password>",
+  "depots": []
+}`,
+    instructions: (
+        <div>
+            <ol>
+                <li>
+                    In the configuration below, set <Field>p4.port</Field> to the address of your Perforce Server, and{' '}
+                    <Field>p4.user</Field> and <Field>p4.passwd</Field> to the credentials of a user that can read the
+                    depots.
+                </li>
+                <li>
+                    Add the paths of the depots you wish to index to the <Field>depots</Field> field. Each depot is
+                    converted to a Git repository.
+                </li>
+            </ol>
+            <p>
+                See{' '}
+                <a
+                    rel="noopener noreferrer"
+                    target="_blank"
+                    href="https://docs.sourcegraph.com/admin/repo/perforce#configuration"
+                >
+                    the docs for more options
+                </a>
+                , or try one of the buttons below.
+            </p>
+        </div>
+    ),
+    editorActions: [
+        {
+            id: 'addDepot',
+            label: 'Add a depot',
+            run: config => {
+                const value = '//depot/path/'
+                const edits = setProperty(config, ['depots', -1], value, defaultFormattingOptions)
+                return { edits, selectText: value }
+            },
+        },
+    ],
+}
 const PHABRICATOR_SERVICE: AddExternalServiceOptions = {
     kind: GQL.ExternalServiceKind.PHABRICATOR,
     title: 'Phabricator connection',
@@ -1056,6 +1108,7 @@ export const codeHostExternalServices: Record<string, AddExternalServiceOptions>
     bitbucketserver: BITBUCKET_SERVER,
     aws_codecommit: AWS_CODE_COMMIT,
     gitolite: GITOLITE,
+    perforce: PERFORCE,
     git: GENERIC_GIT,
 }
 
@@ -1075,6 +1128,7 @@ export const defaultExternalServices: Record<GQL.ExternalServiceKind, AddExterna
     [GQL.ExternalServiceKind.GITLAB]: GITLAB_DOTCOM,
     [GQL.ExternalServiceKind.GITOLITE]: GITOLITE,
     [GQL.ExternalServiceKind.PHABRICATOR]: PHABRICATOR_SERVICE,
+    [GQL.ExternalServiceKind.PERFORCE]: PERFORCE,
     [GQL.ExternalServiceKind.OTHER]: GENERIC_GIT,
     [GQL.ExternalServiceKind.AWSCODECOMMIT]: AWS_CODE_COMMIT,
 }